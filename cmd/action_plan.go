@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/actionplan"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+)
+
+var actionPlanOutputFormats = []string{constants.OutputFormatText, constants.OutputFormatJSON}
+
+// validateActionPlanOutputFormat shows an error and sets the exit code if the output format of a destructive command is invalid
+func validateActionPlanOutputFormat(ctx context.Context) bool {
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if !helpers.StringSliceContains(actionPlanOutputFormats, outputFormat) {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s]", outputFormat, strings.Join(actionPlanOutputFormats, ", ")))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return false
+	}
+	return true
+}
+
+// confirmActionPlan shows the plan of a destructive command and returns whether the command should continue
+// (i.e. --force is set, or the user confirmed the plan)
+func confirmActionPlan(ctx context.Context, plan *actionplan.Plan) bool {
+	outputFormat := viper.GetString(constants.ArgOutput)
+	confirmed, err := plan.Confirm(ctx, outputFormat, viper.GetBool(constants.ArgForce))
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return false
+	}
+	if !confirmed && !plan.Empty() && outputFormat == constants.OutputFormatText {
+		fmt.Println("Cancelled.")
+	}
+	return confirmed
+}
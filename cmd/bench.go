@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/bench"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/contexthelpers"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/initialisation"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+func benchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Args:  cobra.NoArgs,
+		Run:   runBenchCmd,
+		Short: "Benchmark Steampipe performance",
+		Long: `Benchmark Steampipe performance.
+
+Run a standard suite of synthetic queries (schema load, large result streaming,
+parallel queries and, if a table is specified, cache hit/miss and parallel scans)
+and report the timings as JSON.
+
+The report includes the Steampipe version and details of the machine, so results
+can be compared across versions, machines and configurations, e.g. when filing
+performance issues.
+
+Examples:
+
+  # Run the standard benchmark suite
+  steampipe bench
+
+  # Include the cache and parallel scan benchmarks, using a plugin table
+  steampipe bench --table aws_s3_bucket
+
+  # Run 10 iterations of each benchmark, streaming 1 million rows
+  steampipe bench --iterations 10 --rows 1000000`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddIntFlag(constants.ArgIterations, 5, "Number of times to run each benchmark").
+		AddIntFlag(constants.ArgRows, 100000, "Number of rows returned by the large result benchmarks").
+		AddIntFlag(constants.ArgParallelism, 4, "Number of concurrent queries run by the parallel benchmarks").
+		AddStringFlag(constants.ArgTable, "", "Plugin table to use for the cache and parallel scan benchmarks").
+		AddBoolFlag(constants.ArgHelp, false, "Help for bench", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runBenchCmd(cmd *cobra.Command, _ []string) {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runBenchCmd start")
+	defer func() {
+		utils.LogTime("runBenchCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	opts := bench.Options{
+		Iterations:  viper.GetInt(constants.ArgIterations),
+		Rows:        viper.GetInt(constants.ArgRows),
+		Parallelism: viper.GetInt(constants.ArgParallelism),
+		Table:       viper.GetString(constants.ArgTable),
+	}
+	if err := validateBenchOptions(opts); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	client, errorsAndWarnings := initialisation.GetDbClient(ctx, constants.InvokerQuery, nil)
+	if errorsAndWarnings.GetError() != nil {
+		error_helpers.ShowError(ctx, error_helpers.NewConnectionError(error_helpers.ErrorCodeDatabaseConnectionFailed, errorsAndWarnings.GetError()))
+		exitCode = constants.ExitCodeDatabaseConnectionFailed
+		return
+	}
+	defer client.Close(ctx)
+
+	report := bench.Run(ctx, client, opts)
+
+	jsonOutput, err := json.MarshalIndent(report, "", "  ")
+	error_helpers.FailOnError(err)
+	fmt.Println(string(jsonOutput))
+
+	for _, r := range report.Results {
+		if r.Error != "" {
+			exitCode = constants.ExitCodeBenchFailed
+			break
+		}
+	}
+}
+
+func validateBenchOptions(opts bench.Options) error {
+	if opts.Iterations < 1 {
+		return sperr.New("--%s must be at least 1", constants.ArgIterations)
+	}
+	if opts.Rows < 1 {
+		return sperr.New("--%s must be at least 1", constants.ArgRows)
+	}
+	if opts.Parallelism < 1 {
+		return sperr.New("--%s must be at least 1", constants.ArgParallelism)
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -18,9 +20,11 @@ import (
 	"github.com/turbot/steampipe/pkg/control"
 	"github.com/turbot/steampipe/pkg/control/controldisplay"
 	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlpolicy"
 	"github.com/turbot/steampipe/pkg/control/controlstatus"
 	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/modusage"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -33,6 +37,9 @@ var checkTimingMode = constants.CheckTimingModeOff
 // variable used to assign the output mode flag
 var checkOutputMode = constants.CheckOutputModeText
 
+// empty strings are not quoted by default in check csv output, as there are no null values
+var checkCsvQuoteMode = constants.CsvQuoteModeNeeded
+
 func checkCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "check [flags] [mod/benchmark/control/\"all\"]",
@@ -69,7 +76,10 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 		AddModLocationFlag().
 		AddBoolFlag(constants.ArgHeader, true, "Include column headers for csv and table output").
 		AddBoolFlag(constants.ArgHelp, false, "Help for check", cmdconfig.FlagOptions.WithShortHand("h")).
-		AddStringFlag(constants.ArgSeparator, ",", "Separator string for csv output").
+		AddStringFlag(constants.ArgSeparator, ",", "Separator string for csv output - a single character, or 'tab'").
+		AddVarFlag(enumflag.New(&checkCsvQuoteMode, constants.ArgQuoteMode, constants.CsvQuoteModeIds, enumflag.EnumCaseInsensitive),
+			constants.ArgQuoteMode,
+			fmt.Sprintf("Quoting of csv output fields; one of: %s", strings.Join(constants.FlagValues(constants.CsvQuoteModeIds), ", "))).
 		AddVarFlag(enumflag.New(&checkOutputMode, constants.ArgOutput, constants.CheckOutputModeIds, enumflag.EnumCaseInsensitive),
 			constants.ArgOutput,
 			fmt.Sprintf("Output format; one of: %s", strings.Join(constants.FlagValues(constants.CheckOutputModeIds), ", "))).
@@ -80,15 +90,20 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 		AddStringSliceFlag(constants.ArgSearchPath, nil, "Set a custom search_path for the steampipe user for a check session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, nil, "Set a prefix to the current search path for a check session (comma-separated)").
 		AddStringFlag(constants.ArgTheme, "dark", "Set the output theme for 'text' output: light, dark or plain").
-		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported formats: csv, html, json, md, nunit3, sps (snapshot), asff").
-		AddBoolFlag(constants.ArgProgress, true, "Display control execution progress").
+		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported formats: csv, html, json, md, nunit3, sps (snapshot), asff, webhook:<url>. File names may include {name}, {control}, {date}, {time} and {timestamp} variables ({control} writes a file per control), and may be s3://, gs:// or azblob:// urls").
+		AddProgressFlag("Display control execution progress").
 		AddBoolFlag(constants.ArgDryRun, false, "Show which controls will be run without running them").
+		AddStringFlag(constants.ArgPolicy, "", "Evaluate the results against the thresholds in a policy file - the exit code is set by the policy verdict").
+		AddBoolFlag(constants.ArgExportMetadata, true, "Include run metadata (versions, connections and variables) in csv, html and json exports").
+		AddBoolFlag(constants.ArgTrackUsage, false, "Record the tables and columns used by each control, for 'steampipe mod usage'").
+		AddBoolFlag(constants.ArgResourceUsage, false, "After the run, report the API calls, hydrate calls, cache hits and duration of each control, most expensive first").
 		AddStringSliceFlag(constants.ArgTag, nil, "Filter controls based on their tag values ('--tag key=value')").
 		AddStringSliceFlag(constants.ArgVarFile, nil, "Specify an .spvar file containing variable values").
 		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
 		// Cobra will interpret values passed to a StringSliceFlag as CSV,
 		// where args passed to StringArrayFlag are not parsed and used raw
 		AddStringArrayFlag(constants.ArgVariable, nil, "Specify the value of a variable").
+		AddVariablesWorkspaceFlag().
 		AddStringFlag(constants.ArgWhere, "", "SQL 'where' clause, or named query, used to filter controls (cannot be used with '--tag')").
 		AddIntFlag(constants.ArgDatabaseQueryTimeout, constants.DatabaseDefaultCheckQueryTimeout, "The query timeout").
 		AddIntFlag(constants.ArgMaxParallel, constants.DefaultMaxConnections, "The maximum number of concurrent database connections to open").
@@ -104,10 +119,11 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 	return cmd
 }
 
-// exitCode=0 no runtime errors, no control alarms or errors
+// exitCode=0 no runtime errors, no control alarms or errors (or the results meet the policy, if '--policy' is set)
 // exitCode=1 no runtime errors, 1 or more control alarms, no control errors
 // exitCode=2 no runtime errors, 1 or more control errors
 // exitCode=3+ runtime errors
+// exitCode=46 no runtime errors, the results do not meet the policy (if '--policy' is set)
 
 func runCheckCmd(cmd *cobra.Command, args []string) {
 	utils.LogTime("runCheckCmd start")
@@ -140,6 +156,18 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 		error_helpers.FailOnError(sperr.New("cannot execute 'all' with other benchmarks/controls"))
 	}
 
+	// load the policy before running any controls, so an invalid policy fails fast
+	var policy *controlpolicy.Policy
+	if policyPath := viper.GetString(constants.ArgPolicy); policyPath != "" {
+		var err error
+		policy, err = controlpolicy.LoadPolicy(policyPath)
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeConfigLoadFailed
+			return
+		}
+	}
+
 	// show the status spinner
 	statushooks.Show(ctx)
 
@@ -149,7 +177,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	// getting status updates all the way down from the service layer
 	initData := control.NewInitData(ctx)
 	if initData.Result.Error != nil {
-		exitCode = constants.ExitCodeInitializationFailed
+		exitCode = error_helpers.ExitCode(initData.Result.Error, constants.ExitCodeInitializationFailed)
 		error_helpers.ShowError(ctx, initData.Result.Error)
 		return
 	}
@@ -162,7 +190,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	initData.Result.DisplayMessages()
 
 	// pull out useful properties
-	totalAlarms, totalErrors := 0, 0
+	totalAlarms, totalErrors, failedTrees := 0, 0, 0
 
 	// get the execution trees
 	// depending on the set of arguments and the export targets, we may get more than one
@@ -172,11 +200,19 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	trees, err := getExecutionTrees(ctx, initData, args...)
 	error_helpers.FailOnError(err)
 
+	var usageRecorder *modusage.Recorder
+	if viper.GetBool(constants.ArgTrackUsage) {
+		usageRecorder = modusage.NewRecorder()
+	}
+
 	// execute controls synchronously (execute returns the number of alarms and errors)
 	for _, namedTree := range trees {
+		namedTree.tree.UsageRecorder = usageRecorder
+		namedTree.tree.RecordResourceUsage = viper.GetBool(constants.ArgResourceUsage)
 		err = executeTree(ctx, namedTree.tree, initData)
 		if err != nil {
 			error_helpers.ShowError(ctx, err)
+			failedTrees++
 			continue
 		}
 
@@ -191,6 +227,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 		}
 
 		printTiming(namedTree.tree)
+		printResourceUsage(namedTree.tree)
 
 		err = exportExecutionTree(ctx, namedTree, initData, viper.GetStringSlice(constants.ArgExport))
 		if err != nil {
@@ -199,8 +236,47 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if usageRecorder != nil {
+		if err := usageRecorder.Save(viper.GetString(constants.ArgModLocation)); err != nil {
+			error_helpers.ShowWarning(fmt.Sprintf("failed to save usage: %s", err.Error()))
+		}
+	}
+
 	// set the defined exit code after successful execution
 	exitCode = getExitCode(totalAlarms, totalErrors)
+
+	// if there is a policy, the verdict determines the exit code
+	if policy != nil && !viper.GetBool(constants.ArgDryRun) {
+		exitCode = evaluateCheckPolicy(policy, trees, failedTrees)
+	}
+}
+
+// evaluateCheckPolicy evaluates the results of the execution trees against the policy, shows the verdict
+// and returns the exit code
+// the policy fails if any of the execution trees failed to execute, as their results are incomplete
+func evaluateCheckPolicy(policy *controlpolicy.Policy, trees []*namedExecutionTree, failedTrees int) int {
+	executionTrees := make([]*controlexecute.ExecutionTree, len(trees))
+	for i, namedTree := range trees {
+		executionTrees[i] = namedTree.tree
+	}
+	verdict := policy.Evaluate(executionTrees...)
+	if failedTrees > 0 {
+		verdict.Fail(fmt.Sprintf("%d of %d runs failed to execute", failedTrees, len(trees)))
+	}
+
+	// show the verdict after text output - for other formats, write to stderr so the output can still be parsed
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat == constants.OutputFormatText || outputFormat == constants.OutputFormatBrief {
+		fmt.Println()
+		fmt.Print(verdict.String())
+	} else {
+		fmt.Fprint(os.Stderr, verdict.String())
+	}
+
+	if !verdict.Passed {
+		return constants.ExitCodeControlsPolicyFailed
+	}
+	return constants.ExitCodeSuccessful
 }
 
 // exportExecutionTree relies on the fact that the given tree is already executed
@@ -218,7 +294,7 @@ func exportExecutionTree(ctx context.Context, namedTree *namedExecutionTree, ini
 	}
 
 	// print the location where the file is exported if progress=true
-	if len(exportMsg) > 0 && viper.GetBool(constants.ArgProgress) {
+	if len(exportMsg) > 0 && statushooks.ProgressEnabled() {
 		fmt.Printf("\n")
 		fmt.Println(strings.Join(exportMsg, "\n"))
 		fmt.Printf("\n")
@@ -340,9 +416,9 @@ func validateCheckArgs(ctx context.Context, cmd *cobra.Command, args []string) b
 		return false
 	}
 
-	// only 1 character is allowed for '--separator'
-	if len(viper.GetString(constants.ArgSeparator)) > 1 {
-		error_helpers.ShowError(ctx, fmt.Errorf("'--%s' can be 1 character long at most", constants.ArgSeparator))
+	// only 1 character (or 'tab') is allowed for '--separator'
+	if _, err := display.ParseCSVSeparator(viper.GetString(constants.ArgSeparator)); err != nil {
+		error_helpers.ShowError(ctx, err)
 		return false
 	}
 
@@ -395,6 +471,54 @@ func shouldPrintTiming() bool {
 		(outputFormat == constants.OutputFormatText || outputFormat == constants.OutputFormatBrief)
 }
 
+func printResourceUsage(tree *controlexecute.ExecutionTree) {
+	if !shouldPrintResourceUsage() {
+		return
+	}
+	headers := []string{"Control", "Benchmark", "API Calls", "Hydrate Calls", "Cache Hits", "Rows Fetched", "Duration"}
+	var rows [][]string
+	var total controlexecute.ResourceUsage
+	var totalDuration time.Duration
+	for _, u := range tree.ResourceUsageReport() {
+		rows = append(rows, []string{
+			u.Control,
+			u.Benchmark,
+			strconv.FormatInt(u.ApiCalls(), 10),
+			strconv.FormatInt(u.HydrateCalls, 10),
+			fmt.Sprintf("%d/%d", u.CacheHits, u.Scans),
+			strconv.FormatInt(u.RowsFetched, 10),
+			u.Duration.String(),
+		})
+		total.Scans += u.Scans
+		total.CacheHits += u.CacheHits
+		total.HydrateCalls += u.HydrateCalls
+		total.RowsFetched += u.RowsFetched
+		totalDuration += u.Duration
+	}
+	if len(rows) == 0 {
+		return
+	}
+	rows = append(rows, []string{
+		"Total",
+		"",
+		strconv.FormatInt(total.ApiCalls(), 10),
+		strconv.FormatInt(total.HydrateCalls, 10),
+		fmt.Sprintf("%d/%d", total.CacheHits, total.Scans),
+		strconv.FormatInt(total.RowsFetched, 10),
+		totalDuration.String(),
+	})
+	// blank line after renderer output
+	fmt.Println()
+	fmt.Println("Resource usage:")
+	display.ShowWrappedTable(headers, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+}
+
+func shouldPrintResourceUsage() bool {
+	outputFormat := viper.GetString(constants.ArgOutput)
+	return viper.GetBool(constants.ArgResourceUsage) && !viper.GetBool(constants.ArgDryRun) &&
+		(outputFormat == constants.OutputFormatText || outputFormat == constants.OutputFormatBrief)
+}
+
 func displayControlResults(ctx context.Context, executionTree *controlexecute.ExecutionTree, formatter controldisplay.Formatter) error {
 	reader, err := formatter.Format(ctx, executionTree)
 	if err != nil {
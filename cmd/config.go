@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/secrets"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// Config management commands
+func configCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "config [command]",
+		Args:  cobra.NoArgs,
+		Short: "Steampipe config management",
+		Long: `Steampipe config management.
+
+Encrypt sensitive values in Steampipe config (.spc) files, so they can be safely
+stored, e.g. in git. Encrypted values are transparently decrypted when config is loaded.
+
+Values are encrypted using age (https://age-encryption.org) with an X25519 key. The
+key is read from the STEAMPIPE_CONFIG_KEY environment variable (an AGE-SECRET-KEY-1...
+identity) if set, otherwise it is created the first time values are encrypted and
+stored in the OS keyring. If no OS keyring is available, the key is stored in a key
+file in the Steampipe internal directory, encrypted with a passphrase - this is read
+from the STEAMPIPE_CONFIG_PASSPHRASE environment variable if set, otherwise prompted for.
+
+Every option may also be set using a STEAMPIPE_* environment variable - use
+'steampipe config env' to list them.
+
+Examples:
+
+  # Encrypt sensitive values in all config files in the config directory
+  steampipe config encrypt
+
+  # Decrypt all encrypted values in a config file
+  steampipe config decrypt ~/.steampipe/config/aws.spc`,
+	}
+
+	cmd.AddCommand(configEncryptCmd())
+	cmd.AddCommand(configDecryptCmd())
+	cmd.AddCommand(configEnvCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for config")
+
+	return cmd
+}
+
+func configEncryptCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "encrypt [flags] [file...]",
+		Args:  cobra.ArbitraryArgs,
+		Run:   runConfigEncryptCmd,
+		Short: "Encrypt sensitive values in config files",
+		Long: `Encrypt sensitive values in config files.
+
+Encrypt the literal string values of sensitive attributes (by default, any attribute
+whose name contains password, passphrase, secret, token, private_key, access_key,
+api_key or credentials). If no files are specified, all config files in the config
+directory are encrypted. Formatting and comments are preserved.
+
+Examples:
+
+  # Encrypt sensitive values in all config files in the config directory
+  steampipe config encrypt
+
+  # Encrypt the 'client_secret' and 'pat' attributes in a config file
+  steampipe config encrypt --attribute client_secret --attribute pat ~/.steampipe/config/github.spc`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringSliceFlag(constants.ArgAttribute, nil, "Attribute names (or substrings of names) to encrypt - overrides the default sensitive attributes").
+		AddBoolFlag(constants.ArgHelp, false, "Help for config encrypt", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func configDecryptCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "decrypt [flags] [file...]",
+		Args:  cobra.ArbitraryArgs,
+		Run:   runConfigDecryptCmd,
+		Short: "Decrypt encrypted values in config files",
+		Long: `Decrypt encrypted values in config files.
+
+Replace all encrypted values with their plaintext. If no files are specified,
+all config files in the config directory are decrypted.
+
+Examples:
+
+  # Decrypt all config files in the config directory
+  steampipe config decrypt`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for config decrypt", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func configEnvCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "env",
+		Args:  cobra.NoArgs,
+		Run:   runConfigEnvCmd,
+		Short: "List the environment variables which set config options",
+		Long: `List the environment variables which set config options.
+
+Every options block setting and command flag may be set using an environment
+variable, so Steampipe may be configured without config files (e.g. in a container).
+Environment variables take precedence over options set in config files, but not
+over command line flags.
+
+Flags which are not listed with an options block setting are set using the
+environment variable STEAMPIPE_<FLAG>, e.g. STEAMPIPE_SNAPSHOT_TITLE for --snapshot-title.
+
+Examples:
+
+  # List the environment variables
+  steampipe config env
+
+  # List the environment variables as json
+  steampipe config env --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for config env", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runConfigEncryptCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runConfigEncryptCmd start")
+	defer func() {
+		utils.LogTime("runConfigEncryptCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	key, created, err := secrets.EnsureKey()
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeConfigEncryptionFailed
+		return
+	}
+	if created {
+		fmt.Printf("Created config encryption key, stored in %s - keep the key safe, it is required to load the encrypted config.\n", secrets.KeyLocation())
+	}
+
+	sensitiveAttributes := secrets.DefaultSensitiveAttributes
+	if attributes := viper.GetStringSlice(constants.ArgAttribute); len(attributes) > 0 {
+		sensitiveAttributes = attributes
+	}
+
+	updateConfigFiles(cmd, args, "Encrypted", func(path string, data []byte) ([]byte, int, error) {
+		return secrets.EncryptHcl(path, data, key, sensitiveAttributes)
+	})
+}
+
+func runConfigDecryptCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runConfigDecryptCmd start")
+	defer func() {
+		utils.LogTime("runConfigDecryptCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	updateConfigFiles(cmd, args, "Decrypted", func(path string, data []byte) ([]byte, int, error) {
+		return secrets.DecryptHcl(path, data, secrets.LoadKey)
+	})
+}
+
+// updateConfigFiles applies the update function to each of the given files (or all config files in the
+// config directory if no files are given) and writes back any changed files
+func updateConfigFiles(cmd *cobra.Command, paths []string, verb string, update func(path string, data []byte) ([]byte, int, error)) {
+	ctx := cmd.Context()
+
+	if len(paths) == 0 {
+		var err error
+		paths, err = filehelpers.ListFilesWithContext(ctx, filepaths.EnsureConfigDir(), &filehelpers.ListOptions{
+			Flags:   filehelpers.FilesFlat,
+			Include: filehelpers.InclusionsFromExtensions([]string{constants.ConfigExtension}),
+		})
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeFileSystemAccessFailure
+			return
+		}
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeFileSystemAccessFailure
+			continue
+		}
+		updated, count, err := update(path, data)
+		if err != nil {
+			error_helpers.ShowErrorWithMessage(ctx, err, fmt.Sprintf("failed to update %s", path))
+			exitCode = constants.ExitCodeConfigEncryptionFailed
+			continue
+		}
+		if count == 0 {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeFileSystemAccessFailure
+			continue
+		}
+		if err := os.WriteFile(path, updated, info.Mode()); err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeFileSystemAccessFailure
+			continue
+		}
+		fmt.Printf("%s %d %s in %s\n", verb, count, utils.Pluralize("value", count), path)
+	}
+}
+
+func runConfigEnvCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runConfigEnvCmd start")
+	defer func() {
+		utils.LogTime("runConfigEnvCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	bindings := allEnvVarBindings(cmd.Root())
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(bindings, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+
+	var rows [][]string
+	for _, b := range bindings {
+		description := b.Description
+		if b.Deprecated {
+			description += " (deprecated)"
+		}
+		rows = append(rows, []string{b.EnvVar, b.Setting, description})
+	}
+	display.ShowWrappedTable([]string{"Environment Variable", "Setting", "Description"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+}
+
+// allEnvVarBindings returns the environment variable bindings of all commands, with each env var listed once
+func allEnvVarBindings(root *cobra.Command) []cmdconfig.EnvVarBinding {
+	var res []cmdconfig.EnvVarBinding
+	seen := make(map[string]struct{})
+	var visit func(c *cobra.Command)
+	visit = func(c *cobra.Command) {
+		for _, b := range cmdconfig.EnvVarBindings(c) {
+			if _, ok := seen[b.EnvVar]; ok {
+				continue
+			}
+			seen[b.EnvVar] = struct{}{}
+			res = append(res, b)
+		}
+		for _, child := range c.Commands() {
+			visit(child)
+		}
+	}
+	visit(root)
+	return res
+}
@@ -0,0 +1,707 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/connection"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/contexthelpers"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+	pb "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/proto"
+	"github.com/turbot/steampipe/pkg/statestore"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+	"golang.org/x/exp/maps"
+)
+
+// Connection management commands
+func connectionCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "connection [command]",
+		Args:  cobra.NoArgs,
+		Short: "Steampipe connection management",
+		Long: `Steampipe connection management.
+
+Inspect, refresh, check and discover the connections of the Steampipe service.`,
+	}
+
+	cmd.AddCommand(connectionStateCmd())
+	cmd.AddCommand(connectionShowCmd())
+	cmd.AddCommand(connectionRefreshCmd())
+	cmd.AddCommand(connectionDiscoverCmd())
+	cmd.AddCommand(connectionCheckCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for connection")
+
+	return cmd
+}
+
+func connectionStateCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "state",
+		Args:  cobra.NoArgs,
+		Run:   runConnectionStateCmd,
+		Short: "Show the current state of all connections",
+		Long: `Show the current state of all connections.
+
+Output the state and last error of each connection of the running Steampipe service,
+in a format which can be consumed by monitoring agents. If the service is not running,
+the output reports this and contains no connections.
+
+The prometheus output uses the text exposition format, so can be served to a Prometheus
+scraper, e.g. by the node exporter textfile collector.
+
+Examples:
+
+  # Show connection state as json
+  steampipe connection state
+
+  # Write connection state metrics for the node exporter textfile collector
+  steampipe connection state --output prometheus > /var/lib/node_exporter/steampipe.prom`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatJSON, "Output format: json or prometheus").
+		AddBoolFlag(constants.ArgHelp, false, "Help for connection state", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runConnectionStateCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runConnectionStateCmd start")
+	defer func() {
+		utils.LogTime("runConnectionStateCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatJSON && outputFormat != constants.OutputFormatPrometheus {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatJSON, constants.OutputFormatPrometheus))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	connectionStateMap, err := db_local.LoadServiceConnectionState(ctx)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connection state")
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+	export := steampipeconfig.NewConnectionStateExport(connectionStateMap, connectionStateMap != nil)
+
+	switch outputFormat {
+	case constants.OutputFormatPrometheus:
+		fmt.Print(export.Prometheus())
+	default:
+		jsonOutput, err := json.MarshalIndent(export, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+	}
+}
+
+func connectionShowCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "show [flags] connection",
+		Args:  cobra.ExactArgs(1),
+		Run:   runConnectionShowCmd,
+		Short: "Show the state of a connection",
+		Long: `Show the state of a connection.
+
+Show the state, last error and definition of a connection of the running Steampipe
+service. For aggregator connections, the child connections which are included in the
+aggregator are shown, along with the child connections which are excluded (e.g. as they
+are disabled or in error) and the reason they are excluded.
+
+Examples:
+
+  # Show the state of the 'aws_all' aggregator connection
+  steampipe connection show aws_all
+
+  # Show the state of the 'aws_all' connection as json
+  steampipe connection show aws_all --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatText, "Output format: text or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for connection show", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runConnectionShowCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runConnectionShowCmd start")
+	defer func() {
+		utils.LogTime("runConnectionShowCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatText && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatText, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	connectionStateMap, err := db_local.LoadServiceConnectionState(ctx)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connection state")
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+	if connectionStateMap == nil {
+		error_helpers.ShowError(ctx, sperr.New("the Steampipe service is not running"))
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+	state, ok := connectionStateMap[args[0]]
+	if !ok {
+		error_helpers.ShowError(ctx, sperr.New("connection '%s' does not exist", args[0]))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(state, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+	showConnectionState(state)
+}
+
+func showConnectionState(state *steampipeconfig.ConnectionState) {
+	rows := [][]string{
+		{"Name:", state.ConnectionName},
+		{"Plugin:", state.Plugin},
+	}
+	if state.GetType() != "" {
+		rows = append(rows, []string{"Type:", state.GetType()})
+	}
+	rows = append(rows, []string{"State:", state.State})
+	if state.Error() != "" {
+		rows = append(rows, []string{"Error:", state.Error()})
+	}
+	rows = append(rows,
+		[]string{"Schema mode:", state.SchemaMode},
+		[]string{"Last updated:", state.ConnectionModTime.Local().Format(time.RFC1123)},
+		[]string{"Defined in:", fmt.Sprintf("%s:%d", state.FileName, state.StartLineNumber)},
+	)
+	if state.GetType() == modconfig.ConnectionTypeAggregator {
+		rows = append(rows,
+			[]string{"Connections:", strings.Join(state.Connections, ", ")},
+			[]string{"Included:", strings.Join(state.IncludedConnections, ", ")},
+		)
+		excludedNames := maps.Keys(state.ExcludedConnections)
+		sort.Strings(excludedNames)
+		var excluded []string
+		for _, name := range excludedNames {
+			excluded = append(excluded, fmt.Sprintf("%s (%s)", name, state.ExcludedConnections[name]))
+		}
+		rows = append(rows, []string{"Excluded:", strings.Join(excluded, "\n")})
+	}
+	for _, row := range rows {
+		lines := strings.Split(row[1], "\n")
+		fmt.Printf("%-14s%s\n", row[0], lines[0])
+		for _, line := range lines[1:] {
+			fmt.Printf("%-14s%s\n", "", line)
+		}
+	}
+}
+
+func connectionRefreshCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "refresh [flags] connection...",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   runConnectionRefreshCmd,
+		Short: "Force the schema of connections to be re-imported",
+		Long: `Force the schema of connections to be re-imported.
+
+Re-import the schema of the given connections, even if their config and plugin
+have not changed, e.g. after a plugin upgrade. Connections may be specified by
+name or by a glob pattern. The service is started if it is not running, and the
+command waits until all of the connections have been refreshed.
+
+Examples:
+
+  # Refresh the 'aws_prod' connection
+  steampipe connection refresh aws_prod
+
+  # Refresh all connections whose name starts with 'aws_'
+  steampipe connection refresh "aws_*"
+
+  # Refresh connections and output the full refresh result as JSON
+  steampipe connection refresh "aws_*" --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatText, "Output format: text or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for connection refresh", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runConnectionRefreshCmd(cmd *cobra.Command, args []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runConnectionRefreshCmd start")
+	defer func() {
+		utils.LogTime("runConnectionRefreshCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatText && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatText, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	connectionNames, err := steampipeconfig.GlobalConfig.ConnectionNamesMatching(args)
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	statushooks.Show(ctx)
+	defer statushooks.Done(ctx)
+
+	// start service
+	statushooks.SetStatus(ctx, "Starting service")
+	client, res := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	error_helpers.FailOnError(res.Error)
+	defer client.Close(ctx)
+
+	conn, err := client.AcquireManagementConnection(ctx)
+	error_helpers.FailOnError(err)
+	defer conn.Release()
+
+	// wait for any refresh which is in progress (e.g. if the service has just started) to complete
+	statushooks.SetStatus(ctx, "Loading connection state")
+	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilReady(connectionNames...))
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connection state")
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+	// disabled connections are not refreshed
+	var disabledConnections []string
+	connectionNames = slices.DeleteFunc(connectionNames, func(name string) bool {
+		if state, ok := connectionStateMap[name]; ok && state.Disabled() {
+			disabledConnections = append(disabledConnections, name)
+			return true
+		}
+		return false
+	})
+	if len(connectionNames) == 0 {
+		statushooks.Done(ctx)
+		if outputFormat == constants.OutputFormatJSON {
+			showConnectionRefreshResultJSON(connectionNames, disabledConnections, nil)
+			return
+		}
+		fmt.Printf("No connections refreshed - all matching connections are disabled: %s.\n", strings.Join(disabledConnections, ", "))
+		return
+	}
+
+	pluginManager, err := pluginmanager.GetPluginManager()
+	error_helpers.FailOnError(err)
+
+	statushooks.SetStatus(ctx, fmt.Sprintf("Refreshing %d %s", len(connectionNames), utils.Pluralize("connection", len(connectionNames))))
+	requestTime := time.Now()
+	// the refresh is executed asynchronously by the plugin manager
+	if _, err := pluginManager.RefreshConnections(&pb.RefreshConnectionsRequest{Connections: connectionNames}); err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to refresh connections")
+		exitCode = constants.ExitCodeConnectionRefreshFailed
+		return
+	}
+
+	connectionStateMap, err = waitForConnectionRefresh(ctx, conn.Conn(), connectionNames, requestTime)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to refresh connections")
+		exitCode = constants.ExitCodeConnectionRefreshFailed
+		return
+	}
+
+	if outputFormat == constants.OutputFormatJSON {
+		// the plugin manager saves the full result of the refresh once it is complete
+		statushooks.SetStatus(ctx, "Loading refresh result")
+		report, err := waitForRefreshConnectionsReport(ctx, statestore.New(conn.Conn()), connectionNames, requestTime)
+		if err != nil {
+			error_helpers.ShowErrorWithMessage(ctx, err, "failed to load refresh result")
+			exitCode = constants.ExitCodeConnectionRefreshFailed
+			return
+		}
+		statushooks.Done(ctx)
+		showConnectionRefreshResultJSON(connectionNames, disabledConnections, report)
+		return
+	}
+	statushooks.Done(ctx)
+
+	showConnectionRefreshResult(connectionNames, disabledConnections, connectionStateMap)
+}
+
+// waitForConnectionRefresh waits until all the given connections have been updated since the given time
+// and are either ready or in error
+func waitForConnectionRefresh(ctx context.Context, conn *pgx.Conn, connectionNames []string, since time.Time) (steampipeconfig.ConnectionStateMap, error) {
+	// allow the same time as LoadConnectionState allows connections to become ready
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	for {
+		connectionStateMap, err := steampipeconfig.LoadConnectionState(timeoutCtx, conn)
+		if err != nil {
+			return nil, err
+		}
+		refreshed := 0
+		for _, name := range connectionNames {
+			if state, ok := connectionStateMap[name]; ok && state.Loaded() && state.ConnectionModTime.After(since) {
+				refreshed++
+			}
+		}
+		if refreshed == len(connectionNames) {
+			return connectionStateMap, nil
+		}
+		statushooks.SetStatus(ctx, fmt.Sprintf("Refreshing connections: %d of %d complete", refreshed, len(connectionNames)))
+
+		select {
+		case <-timeoutCtx.Done():
+			return nil, sperr.New("timed out waiting for connections to refresh")
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// waitForRefreshConnectionsReport waits for the report of a refresh which started after the given time
+// and which refreshed all the given connections
+func waitForRefreshConnectionsReport(ctx context.Context, store statestore.Store, connectionNames []string, since time.Time) (*steampipeconfig.RefreshConnectionsReport, error) {
+	// the connections are already refreshed - we are only waiting for the refresh to complete
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	for {
+		report, err := steampipeconfig.LoadRefreshConnectionsReport(timeoutCtx, store)
+		if err != nil {
+			// the report may be in the process of being written
+			log.Printf("[TRACE] failed to load refresh connections report: %s", err.Error())
+		} else if report != nil && report.StartTime.After(since) && report.Includes(connectionNames) {
+			return report, nil
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return nil, sperr.New("timed out waiting for the refresh result")
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// connectionRefreshOutput is the JSON output of the connection refresh command
+type connectionRefreshOutput struct {
+	// the connections which were refreshed
+	Connections []string `json:"connections"`
+	// matching connections which were not refreshed as they are disabled
+	Disabled []string `json:"disabled"`
+	// the result of the refresh - as other connections may have changed, this may include connections which were not requested
+	Refresh *steampipeconfig.RefreshConnectionsReport `json:"refresh,omitempty"`
+}
+
+func showConnectionRefreshResultJSON(connectionNames, disabledConnections []string, report *steampipeconfig.RefreshConnectionsReport) {
+	output := connectionRefreshOutput{
+		Connections: append([]string{}, connectionNames...),
+		Disabled:    append([]string{}, disabledConnections...),
+		Refresh:     report,
+	}
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	error_helpers.FailOnError(err)
+	fmt.Println(string(jsonOutput))
+
+	if report == nil {
+		return
+	}
+	if report.Error != "" {
+		exitCode = constants.ExitCodeConnectionRefreshFailed
+	}
+	for _, name := range connectionNames {
+		if _, failed := report.Failed[name]; failed {
+			exitCode = constants.ExitCodeConnectionRefreshFailed
+		}
+	}
+}
+
+func showConnectionRefreshResult(connectionNames, disabledConnections []string, connectionStateMap steampipeconfig.ConnectionStateMap) {
+	var failed int
+	for _, name := range connectionNames {
+		state := connectionStateMap[name]
+		if state.State == constants.ConnectionStateError {
+			failed++
+			fmt.Printf("%s: %s\n", name, typehelpers.SafeString(state.ConnectionError))
+		}
+	}
+	fmt.Printf("Refreshed %d %s", len(connectionNames)-failed, utils.Pluralize("connection", len(connectionNames)-failed))
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+		exitCode = constants.ExitCodeConnectionRefreshFailed
+	}
+	if len(disabledConnections) > 0 {
+		fmt.Printf(" (skipped disabled %s: %s)", utils.Pluralize("connection", len(disabledConnections)), strings.Join(disabledConnections, ", "))
+	}
+	fmt.Println(".")
+}
+
+func connectionDiscoverCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "discover",
+		Args:  cobra.NoArgs,
+		Run:   runConnectionDiscoverCmd,
+		Short: "Sync connections discovered from the credentials of existing connections",
+		Long: `Sync connections discovered from the credentials of existing connections.
+
+Execute each 'discovery' block in the connection config, which uses an existing
+connection to enumerate the accounts (or projects, subscriptions etc.) it has
+access to, and writes a connection for each of them to a generated config file.
+The running service also re-syncs the discovered connections periodically, so
+they reflect any changes in access.
+
+Example discovery config:
+
+  discovery "aws_accounts" {
+    connection      = "aws_org"
+    connection_name = "aws_{{ .id }}"
+    config          = <<-EOT
+      profile = "{{ .name }}"
+      regions = ["*"]
+    EOT
+  }
+
+Examples:
+
+  # Sync all discovered connections
+  steampipe connection discover`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for connection discover", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runConnectionDiscoverCmd(cmd *cobra.Command, _ []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runConnectionDiscoverCmd start")
+	defer func() {
+		utils.LogTime("runConnectionDiscoverCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	config := steampipeconfig.GlobalConfig
+	if len(config.ConnectionDiscoveries) == 0 {
+		fmt.Println("No discovery blocks are defined in the connection config.")
+		return
+	}
+
+	statushooks.Show(ctx)
+	defer statushooks.Done(ctx)
+
+	// start service
+	statushooks.SetStatus(ctx, "Starting service")
+	client, res := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	error_helpers.FailOnError(res.Error)
+	defer client.Close(ctx)
+
+	conn, err := client.AcquireManagementConnection(ctx)
+	error_helpers.FailOnError(err)
+	defer conn.Release()
+
+	// wait for the discovering connections to be ready
+	statushooks.SetStatus(ctx, "Loading connection state")
+	var sourceConnections []string
+	for _, discovery := range config.ConnectionDiscoveries {
+		if _, ok := config.Connections[discovery.Connection]; ok {
+			sourceConnections = append(sourceConnections, discovery.Connection)
+		}
+	}
+	if _, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilReady(sourceConnections...)); err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connection state")
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+
+	statushooks.SetStatus(ctx, "Discovering connections")
+	results := connection.SyncConnectionDiscoveries(ctx, conn.Conn(), config)
+	statushooks.Done(ctx)
+
+	for _, res := range results {
+		if res.Error != nil {
+			error_helpers.ShowError(ctx, res.Error)
+			exitCode = constants.ExitCodeConnectionDiscoveryFailed
+			continue
+		}
+		fmt.Printf("%s: %d %s (%d added, %d removed) in %s\n", res.Name, len(res.Connections), utils.Pluralize("connection", len(res.Connections)), len(res.Added), len(res.Removed), res.FilePath)
+		if len(res.Skipped) > 0 {
+			error_helpers.ShowWarning(fmt.Sprintf("%s: skipped %d %s already defined in other config files: %s", res.Name, len(res.Skipped), utils.Pluralize("connection", len(res.Skipped)), strings.Join(res.Skipped, ", ")))
+		}
+	}
+}
+
+func connectionCheckCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "check [flags] [connection...]",
+		Args:  cobra.ArbitraryArgs,
+		Run:   runConnectionCheckCmd,
+		Short: "Check the health of connections",
+		Long: `Check the health of connections.
+
+For each connection, run a lightweight probe query which selects a single row from
+a table of the connection, bypassing the cache. This requires the plugin to
+authenticate with the connection credentials, so validates that the credentials
+are valid. The latency of the probe and any error are reported for each connection.
+
+Connections may be specified by name or by a glob pattern - if no connections are
+specified, all connections are checked. Disabled connections are skipped.
+
+Examples:
+
+  # Check all connections
+  steampipe connection check
+
+  # Check all connections whose name starts with 'aws_', and output the result as JSON
+  steampipe connection check "aws_*" --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddIntFlag(constants.ArgProbeTimeout, int(connection.DefaultHealthCheckTimeout.Seconds()), "The time in seconds allowed for the probe query of each connection").
+		AddBoolFlag(constants.ArgHelp, false, "Help for connection check", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runConnectionCheckCmd(cmd *cobra.Command, args []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runConnectionCheckCmd start")
+	defer func() {
+		utils.LogTime("runConnectionCheckCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	timeout, err := connection.HealthCheckTimeout(viper.GetInt(constants.ArgProbeTimeout))
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	// if no connections are specified, check all connections
+	if len(args) == 0 {
+		args = []string{"*"}
+	}
+	connectionNames, err := steampipeconfig.GlobalConfig.ConnectionNamesMatching(args)
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	statushooks.Show(ctx)
+	defer statushooks.Done(ctx)
+
+	// start service
+	statushooks.SetStatus(ctx, "Starting service")
+	client, res := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	error_helpers.FailOnError(res.Error)
+	defer client.Close(ctx)
+
+	conn, err := client.AcquireManagementConnection(ctx)
+	error_helpers.FailOnError(err)
+	defer conn.Release()
+
+	// wait for the connections to be loaded
+	statushooks.SetStatus(ctx, "Loading connection state")
+	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilReady(connectionNames...))
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connection state")
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+	schemaMetadata, err := client.GetSchemaFromDB(ctx)
+	error_helpers.FailOnErrorWithMessage(err, "failed to load the connection schemas")
+
+	statushooks.SetStatus(ctx, fmt.Sprintf("Checking %d %s", len(connectionNames), utils.Pluralize("connection", len(connectionNames))))
+	results := connection.CheckConnectionHealth(ctx, client, connectionStateMap, schemaMetadata, connectionNames, timeout)
+	statushooks.Done(ctx)
+
+	for _, health := range results {
+		if health.Status == connection.ConnectionHealthError {
+			exitCode = constants.ExitCodeConnectionCheckFailed
+		}
+	}
+
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(results, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+	showConnectionCheckResult(results)
+}
+
+func showConnectionCheckResult(results []*connection.ConnectionHealth) {
+	var rows [][]string
+	for _, health := range results {
+		latency := ""
+		if health.Table != "" {
+			latency = fmt.Sprintf("%dms", health.LatencyMs)
+		}
+		rows = append(rows, []string{health.Connection, health.Plugin, health.Status, health.Table, latency, health.Error})
+	}
+	display.ShowWrappedTable([]string{"Connection", "Plugin", "Status", "Table", "Latency", "Error"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardexecute"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardserver"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/db/db_client"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/export"
 	"github.com/turbot/steampipe/pkg/initialisation"
@@ -50,15 +51,17 @@ The current mod is the working directory, or the directory specified by the --mo
 		AddBoolFlag(constants.ArgModInstall, true, "Specify whether to install mod dependencies before running the dashboard").
 		AddStringFlag(constants.ArgDashboardListen, string(dashboardserver.ListenTypeLocal), "Accept connections from: local (localhost only) or network (open)").
 		AddIntFlag(constants.ArgDashboardPort, constants.DashboardServerDefaultPort, "Dashboard server port").
+		AddDashboardServerFlags().
 		AddBoolFlag(constants.ArgBrowser, true, "Specify whether to launch the browser after starting the dashboard server").
 		AddStringSliceFlag(constants.ArgSearchPath, nil, "Set a custom search_path for the steampipe user for a dashboard session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, nil, "Set a prefix to the current search path for a dashboard session (comma-separated)").
 		AddIntFlag(constants.ArgMaxParallel, constants.DefaultMaxConnections, "The maximum number of concurrent database connections to open").
 		AddStringSliceFlag(constants.ArgVarFile, nil, "Specify an .spvar file containing variable values").
-		AddBoolFlag(constants.ArgProgress, true, "Display dashboard execution progress respected when a dashboard name argument is passed").
+		AddProgressFlag("Display dashboard execution progress respected when a dashboard name argument is passed").
 		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
 		// Cobra will interpret values passed to a StringSliceFlag as CSV, where args passed to StringArrayFlag are not parsed and used raw
 		AddStringArrayFlag(constants.ArgVariable, nil, "Specify the value of a variable").
+		AddVariablesWorkspaceFlag().
 		AddBoolFlag(constants.ArgInput, true, "Enable interactive prompts").
 		AddStringFlag(constants.ArgOutput, constants.OutputFormatNone, "Select a console output format: none, snapshot").
 		AddBoolFlag(constants.ArgSnapshot, false, "Create snapshot in Turbot Pipes with the default (workspace) visibility").
@@ -74,10 +77,91 @@ The current mod is the working directory, or the directory specified by the --mo
 		AddBoolFlag(constants.ArgServiceMode, false, "Hidden flag to specify whether this is starting as a service", cmdconfig.FlagOptions.Hidden())
 
 	cmd.AddCommand(getListSubCmd(listSubCmdOptions{parentCmd: cmd}))
+	cmd.AddCommand(dashboardAssetsCmd())
 
 	return cmd
 }
 
+// Dashboard assets management commands
+func dashboardAssetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "assets [command]",
+		Args:  cobra.NoArgs,
+		Short: "Dashboard assets management",
+		Long:  `Dashboard assets management.`,
+	}
+	cmd.AddCommand(dashboardAssetsInstallCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for dashboard assets")
+	return cmd
+}
+
+// Install the dashboard assets
+func dashboardAssetsInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Args:  cobra.NoArgs,
+		Run:   runDashboardAssetsInstallCmd,
+		Short: "Install the dashboard assets for this version",
+		Long: `Install the dashboard assets for this version, replacing any installed assets.
+
+The assets are usually installed automatically when the dashboard server first starts.
+Use this command to install them ahead of time, or to install them from a local archive
+on a machine without network access.
+
+The archive is a gzipped tar of the dashboard assets directory for this version. If
+--checksum is given, the sha256 checksum of the archive must match it.
+
+Examples:
+
+  # Download and install the dashboard assets
+  steampipe dashboard assets install
+
+  # Install the dashboard assets from a local archive
+  steampipe dashboard assets install --archive dashboard_assets.tar.gz --checksum sha256:4f3c...
+`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgArchive, "", "Install the assets from a local gzipped tar archive instead of downloading them").
+		AddStringFlag(constants.ArgChecksum, "", "The expected sha256 checksum of the archive").
+		AddBoolFlag(constants.ArgHelp, false, "Help for dashboard assets install", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runDashboardAssetsInstallCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	defer func() {
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	archivePath := viper.GetString(constants.ArgArchive)
+	checksum := viper.GetString(constants.ArgChecksum)
+	if checksum != "" && archivePath == "" {
+		error_helpers.ShowError(ctx, fmt.Errorf("--%s may only be used with --%s", constants.ArgChecksum, constants.ArgArchive))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	var err error
+	if archivePath != "" {
+		err = dashboardassets.InstallFromArchive(archivePath, checksum)
+	} else {
+		statushooks.SetStatus(ctx, "Installing dashboard assets…")
+		err = dashboardassets.Install(ctx)
+		statushooks.Done(ctx)
+	}
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeDashboardAssetsFailed
+		return
+	}
+	fmt.Println("Installed the dashboard assets")
+}
+
 func runDashboardCmd(cmd *cobra.Command, args []string) {
 	dashboardCtx := cmd.Context()
 
@@ -124,6 +208,8 @@ func runDashboardCmd(cmd *cobra.Command, args []string) {
 	serverListen := dashboardserver.ListenType(viper.GetString(constants.ArgDashboardListen))
 	error_helpers.FailOnError(serverListen.IsValid())
 
+	error_helpers.FailOnError(dashboardserver.ValidateServerArgs())
+
 	serverHost := ""
 	if serverListen == dashboardserver.ListenTypeLocal {
 		serverHost = "127.0.0.1"
@@ -148,7 +234,7 @@ func runDashboardCmd(cmd *cobra.Command, args []string) {
 	initData := initDashboard(dashboardCtx)
 	defer initData.Cleanup(dashboardCtx)
 	if initData.Result.Error != nil {
-		exitCode = constants.ExitCodeInitializationFailed
+		exitCode = error_helpers.ExitCode(initData.Result.Error, constants.ExitCodeInitializationFailed)
 		error_helpers.FailOnError(initData.Result.Error)
 	}
 
@@ -214,7 +300,7 @@ func validateDashboardArgs(ctx context.Context, args []string) (string, error) {
 func displaySnapshot(snapshot *dashboardtypes.SteampipeSnapshot) {
 	switch viper.GetString(constants.ArgOutput) {
 	case constants.OutputFormatNone:
-		if viper.GetBool(constants.ArgProgress) &&
+		if statushooks.ProgressEnabled() &&
 			!viper.IsSet(constants.ArgOutput) &&
 			!viper.GetBool(constants.ArgShare) &&
 			!viper.GetBool(constants.ArgSnapshot) {
@@ -254,7 +340,8 @@ func getInitData(ctx context.Context) *initialisation.InitData {
 	i := initialisation.NewInitData()
 	i.Workspace = w
 	i.Result.Warnings = errAndWarnings.Warnings
-	i.Init(ctx, constants.InvokerDashboard)
+	// dashboards only read data - use a read only client so that a dashboard query cannot write to the database
+	i.Init(ctx, constants.InvokerDashboard, db_client.WithReadOnly(true))
 
 	if len(viper.GetStringSlice(constants.ArgExport)) > 0 {
 		i.RegisterExporters(dashboardExporters()...)
@@ -323,7 +410,7 @@ func runSingleDashboard(ctx context.Context, targetName string, inputs map[strin
 	}
 
 	// print the location where the file is exported
-	if len(exportMsg) > 0 && viper.GetBool(constants.ArgProgress) {
+	if len(exportMsg) > 0 && statushooks.ProgressEnabled() {
 		fmt.Printf("\n")
 		fmt.Println(strings.Join(exportMsg, "\n"))
 		fmt.Printf("\n")
@@ -360,7 +447,7 @@ func publishSnapshotIfNeeded(ctx context.Context, snapshot *dashboardtypes.Steam
 		// reword "402 Payment Required" error
 		return handlePublishSnapshotError(err)
 	}
-	if viper.GetBool(constants.ArgProgress) {
+	if statushooks.ProgressEnabled() {
 		fmt.Println(message)
 	}
 	return nil
@@ -404,7 +491,7 @@ func onServerStarted(ctx context.Context, serverPort dashboardserver.ListenPort,
 }
 
 func buildDashboardURL(serverPort dashboardserver.ListenPort, w *workspace.Workspace) string {
-	url := fmt.Sprintf("http://localhost:%d", serverPort)
+	url := fmt.Sprintf("http://localhost:%d%s", serverPort, dashboardserver.NormaliseBasePath(viper.GetString(constants.ArgDashboardBasePath)))
 	if len(w.SourceSnapshots) == 1 {
 		for snapshotName := range w.GetResourceMaps().Snapshots {
 			url += fmt.Sprintf("/%s", snapshotName)
@@ -443,6 +530,7 @@ func saveDashboardState(serverPort dashboardserver.ListenPort, serverListen dash
 		ListenType: string(serverListen),
 		Listen:     constants.DashboardListenAddresses,
 	}
+	state.SetServerArgs()
 
 	if serverListen == dashboardserver.ListenTypeNetwork {
 		addrs, _ := utils.LocalPublicAddresses()
@@ -482,7 +570,7 @@ func createSnapshotContext(ctx context.Context, target string) context.Context {
 	contexthelpers.StartCancelHandler(cancel)
 
 	// if progress is disabled, OR output is none, do not show status hooks
-	if !viper.GetBool(constants.ArgProgress) {
+	if !statushooks.ProgressEnabled() {
 		snapshotCtx = statushooks.DisableStatusHooks(snapshotCtx)
 	}
 
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/installationstate"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+	"github.com/turbot/steampipe/pkg/onboarding"
+	"github.com/turbot/steampipe/pkg/plugin"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+func initCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "init",
+		Args:  cobra.NoArgs,
+		Run:   runInitCmd,
+		Short: "Set up Steampipe with connections for the cloud credentials on this machine",
+		Long: `Set up Steampipe with connections for the cloud credentials on this machine.
+
+Detects AWS profiles, the active gcloud project and the default Azure CLI subscription,
+installs the plugins required to query them, and adds a connection for each to the
+init.spc file in the Steampipe config directory. Finally, a test query is run against
+each new connection to verify it works.
+
+Connections which already exist are skipped.
+
+Examples:
+
+  # Choose which connections to create
+  steampipe init
+
+  # Create connections for all detected credentials without prompting
+  steampipe init --yes`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgYes, false, "Accept all suggestions without prompting").
+		AddBoolFlag(constants.ArgHelp, false, "Help for init", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runInitCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runInitCmd start")
+	defer func() {
+		utils.LogTime("runInitCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	credentials := onboarding.DetectCredentials()
+	if len(credentials) == 0 {
+		fmt.Println("No AWS, GCP or Azure credentials found. Install a plugin with 'steampipe plugin install' and configure its connection in the config directory.")
+		return
+	}
+	if err := onboarding.ValidateConnectionNames(credentials); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInitFailed
+		return
+	}
+
+	// choose the connections to create
+	var selected []onboarding.DetectedCredential
+	for _, c := range credentials {
+		if _, exists := steampipeconfig.GlobalConfig.Connections[c.ConnectionName]; exists {
+			fmt.Printf("Skipping %s - connection '%s' already exists\n", c.Source, c.ConnectionName)
+			continue
+		}
+		if err := steampipeconfig.ValidateConnectionName(c.ConnectionName); err != nil {
+			fmt.Printf("Skipping %s - %s\n", c.Source, err.Error())
+			continue
+		}
+		ok, err := confirmInitStep(ctx, fmt.Sprintf("Found %s. Create connection '%s'? (y/n)", c.Source, c.ConnectionName))
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeInitFailed
+			return
+		}
+		if ok {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println("No connections to create.")
+		return
+	}
+
+	// install any plugins required by the selected connections
+	installedPlugins, err := installInitPlugins(ctx, onboarding.SuggestedPlugins(selected))
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInitFailed
+		return
+	}
+	var toCreate []onboarding.DetectedCredential
+	for _, c := range selected {
+		if installedPlugins[c.Plugin] {
+			toCreate = append(toCreate, c)
+		}
+	}
+	if len(toCreate) == 0 {
+		fmt.Println("No connections to create.")
+		return
+	}
+
+	configPath, err := onboarding.WriteConnectionConfig(toCreate)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to write connection config")
+		exitCode = constants.ExitCodeInitFailed
+		return
+	}
+	fmt.Printf("Added %s to %s\n", utils.Pluralize("connection", len(toCreate)), configPath)
+
+	// reload the config so the new connections are picked up
+	config, errorsAndWarnings := steampipeconfig.LoadSteampipeConfig(ctx, viper.GetString(constants.ArgModLocation), cmd.Name())
+	if errorsAndWarnings.GetError() != nil {
+		error_helpers.ShowErrorWithMessage(ctx, errorsAndWarnings.GetError(), "failed to reload config")
+		exitCode = constants.ExitCodeInitFailed
+		return
+	}
+	steampipeconfig.GlobalConfig = config
+
+	if failed := smokeTestConnections(ctx, toCreate); failed > 0 {
+		exitCode = constants.ExitCodeInitFailed
+		return
+	}
+	fmt.Println("\nSteampipe is ready. Run 'steampipe query' to start querying.")
+}
+
+// confirmInitStep prompts the user to confirm a step, unless --yes is set
+func confirmInitStep(ctx context.Context, msg string) (bool, error) {
+	if viper.GetBool(constants.ArgYes) {
+		return true, nil
+	}
+	return utils.UserConfirmation(ctx, msg)
+}
+
+// installInitPlugins installs any of the given plugins which are not already installed (if the user confirms)
+// returns the plugins which are available after installation
+func installInitPlugins(ctx context.Context, plugins []string) (map[string]bool, error) {
+	res := make(map[string]bool)
+	var newlyInstalled []string
+	state, err := installationstate.Load()
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "could not load state")
+	}
+
+	for _, pluginName := range plugins {
+		installed, err := plugin.Exists(ctx, pluginName)
+		if err != nil {
+			return nil, err
+		}
+		if installed {
+			res[pluginName] = true
+			continue
+		}
+
+		ok, err := confirmInitStep(ctx, fmt.Sprintf("Plugin '%s' is not installed. Install it? (y/n)", pluginName))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		org, name, constraint := ociinstaller.NewSteampipeImageRef(pluginName).GetOrgNameAndConstraint()
+		resolved, err := plugin.GetLatestPluginVersionByConstraint(ctx, state.InstallationID, utils.RedactedRequestPayloadFields(), org, name, constraint)
+		if err != nil || resolved == nil {
+			error_helpers.ShowWarning(fmt.Sprintf("plugin '%s' not found", pluginName))
+			continue
+		}
+
+		statushooks.SetStatus(ctx, fmt.Sprintf("Installing plugin %s…", pluginName))
+		progress := make(chan struct{}, 5)
+		go func() {
+			for range progress {
+			}
+		}()
+		// skip the default config - we write our own connection config for the detected credentials
+		_, err = plugin.Install(ctx, *resolved, progress, ociinstaller.WithSkipConfig(true))
+		close(progress)
+		statushooks.Done(ctx)
+		if err != nil {
+			error_helpers.ShowWarning(fmt.Sprintf("failed to install plugin '%s': %s", pluginName, err.Error()))
+			continue
+		}
+		fmt.Printf("Installed plugin %s\n", pluginName)
+		res[pluginName] = true
+		newlyInstalled = append(newlyInstalled, pluginName)
+	}
+
+	// if the service is running, notify it so it refreshes any existing connections which were missing their plugin
+	if len(newlyInstalled) > 0 {
+		if err := db_local.SendPluginInstalledNotification(ctx, newlyInstalled); err != nil {
+			log.Printf("[WARN] failed to send plugin installed notification: %s", err.Error())
+		}
+	}
+	return res, nil
+}
+
+// smokeTestConnections waits for the new connections to load, then runs a test query against each
+// returns the number of connections which failed
+func smokeTestConnections(ctx context.Context, credentials []onboarding.DetectedCredential) int {
+	client, errorsAndWarnings := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	if errorsAndWarnings.GetError() != nil {
+		error_helpers.ShowErrorWithMessage(ctx, errorsAndWarnings.GetError(), "failed to start service")
+		return len(credentials)
+	}
+	defer client.Close(ctx)
+
+	var connectionNames []string
+	for _, c := range credentials {
+		connectionNames = append(connectionNames, c.ConnectionName)
+	}
+	connectionStateMap, err := waitForInitConnections(ctx, client, connectionNames)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connections")
+		return len(credentials)
+	}
+
+	fmt.Println()
+	failed := 0
+	for _, c := range credentials {
+		if state, ok := connectionStateMap[c.ConnectionName]; ok && state.State == constants.ConnectionStateError {
+			fmt.Printf("%s %s: %s\n", constants.Red("✘"), c.ConnectionName, state.Error())
+			failed++
+			continue
+		}
+		query := onboarding.SmokeTestQuery(c)
+		if query == "" {
+			fmt.Printf("%s %s: loaded\n", constants.Green("✔"), c.ConnectionName)
+			continue
+		}
+		statushooks.SetStatus(ctx, fmt.Sprintf("Testing connection %s…", c.ConnectionName))
+		_, err := client.ExecuteSync(ctx, query)
+		statushooks.Done(ctx)
+		if err != nil {
+			fmt.Printf("%s %s: %s\n", constants.Red("✘"), c.ConnectionName, strings.TrimSpace(err.Error()))
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s: ok\n", constants.Green("✔"), c.ConnectionName)
+	}
+	return failed
+}
+
+// waitForInitConnections waits until all the given connections are present in the connection state and loaded
+// (if the service was already running, the new connections are added asynchronously by the config file watcher)
+func waitForInitConnections(ctx context.Context, client *db_local.LocalDbClient, connectionNames []string) (steampipeconfig.ConnectionStateMap, error) {
+	conn, err := client.AcquireManagementConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	statushooks.SetStatus(ctx, "Loading connections…")
+	defer statushooks.Done(ctx)
+
+	var connectionStateMap steampipeconfig.ConnectionStateMap
+	err = retry.Do(ctx, retry.WithMaxDuration(time.Minute, retry.NewConstant(500*time.Millisecond)), func(ctx context.Context) error {
+		var loadErr error
+		connectionStateMap, loadErr = steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilReady(connectionNames...))
+		if loadErr != nil {
+			return loadErr
+		}
+		for _, name := range connectionNames {
+			if _, ok := connectionStateMap[name]; !ok {
+				return retry.RetryableError(fmt.Errorf("connection '%s' has not been added", name))
+			}
+		}
+		return nil
+	})
+	return connectionStateMap, err
+}
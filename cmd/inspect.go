@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/contexthelpers"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/inspect"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+var inspectOutputFormats = []string{constants.OutputFormatTable, constants.OutputFormatJSON, constants.OutputFormatCSV, constants.OutputFormatLine}
+
+func inspectCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "inspect [command]",
+		Args:  cobra.NoArgs,
+		Short: "Run built-in reports on connections and the database",
+		Long: `Run built-in reports on connections and the database.
+
+The reports only read database metadata and connection state, so they are cheap to
+run and do not call the APIs of the plugins. Use them as a quick check that connections
+are loaded and have tables.
+
+Examples:
+
+  # Show the number of tables of each connection
+  steampipe inspect counts
+
+  # Show the number of tables of some connections
+  steampipe inspect counts aws_prod aws_dev
+
+  # Show the connections which failed to load, as json
+  steampipe inspect errors --output json`,
+	}
+
+	for _, snippet := range inspect.Snippets() {
+		cmd.AddCommand(inspectSnippetCmd(snippet))
+	}
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for inspect")
+
+	return cmd
+}
+
+func inspectSnippetCmd(snippet *inspect.Snippet) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   fmt.Sprintf("%s [connection ...]", snippet.Name),
+		Args:  cobra.ArbitraryArgs,
+		Run:   runInspectCmd,
+		Short: snippet.Short,
+		Long: fmt.Sprintf(`%s
+
+If connections are given, only they are reported on.
+
+Examples:
+
+  # Run the report
+  steampipe inspect %s
+
+  # Output the report as csv
+  steampipe inspect %s --output csv`, snippet.Description, snippet.Name, snippet.Name),
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, fmt.Sprintf("Output format: %s", strings.Join(inspectOutputFormats, ", "))).
+		AddBoolFlag(constants.ArgHeader, true, "Include column headers csv and table output").
+		AddStringFlag(constants.ArgSeparator, ",", "Separator string for csv output - a single character, or 'tab'").
+		AddBoolFlag(constants.ArgHelp, false, fmt.Sprintf("Help for inspect %s", snippet.Name), cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runInspectCmd(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runInspectCmd start")
+	defer func() {
+		utils.LogTime("runInspectCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	snippet, ok := inspect.GetSnippet(cmd.Name())
+	if !ok {
+		error_helpers.ShowError(ctx, sperr.New("unknown report: '%s'", cmd.Name()))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if !helpers.StringSliceContains(inspectOutputFormats, outputFormat) {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s]", outputFormat, strings.Join(inspectOutputFormats, ", ")))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	if _, err := display.ParseCSVSeparator(viper.GetString(constants.ArgSeparator)); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	statushooks.Show(ctx)
+	defer statushooks.Done(ctx)
+
+	// start service
+	statushooks.SetStatus(ctx, "Starting service")
+	client, res := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	error_helpers.FailOnError(res.Error)
+	defer client.Close(ctx)
+
+	statushooks.SetStatus(ctx, "Running report")
+	result, err := client.Execute(ctx, snippet.Query, inspect.QueryArgs(args)...)
+	error_helpers.FailOnErrorWithMessage(err, fmt.Sprintf("failed to run report '%s'", snippet.Name))
+	statushooks.Done(ctx)
+
+	if rowErrors := display.ShowOutput(ctx, result, display.WithTimingDisabled()); rowErrors > 0 {
+		exitCode = constants.ExitCodeQueryExecutionFailed
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/turbot/steampipe/pkg/cmdconfig"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/network"
 )
 
 func loginCmd() *cobra.Command {
@@ -36,6 +37,12 @@ func loginCmd() *cobra.Command {
 func runLoginCmd(cmd *cobra.Command, _ []string) {
 	ctx := cmd.Context()
 
+	if err := network.CheckOnline("login"); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeLoginCloudConnectionFailed
+		return
+	}
+
 	log.Printf("[TRACE] login, pipes host %s", viper.Get(constants.ArgPipesHost))
 	log.Printf("[TRACE] opening login web page")
 	// start login flow - this will open a web page prompting user to login, and will give the user a code to enter
@@ -2,20 +2,30 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/actionplan"
 	"github.com/turbot/steampipe/pkg/cmdconfig"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/initialisation"
 	"github.com/turbot/steampipe/pkg/modinstaller"
+	"github.com/turbot/steampipe/pkg/modusage"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/parse"
 	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/turbot/steampipe/pkg/workspace"
 )
 
 // mod management commands
@@ -46,6 +56,9 @@ Examples:
     
     # Uninstall a mod
     steampipe mod uninstall github.com/turbot/steampipe-mod-aws-compliance
+
+    # Show the tables and columns used by the mod controls
+    steampipe mod usage
 	`,
 	}
 
@@ -54,6 +67,7 @@ Examples:
 	cmd.AddCommand(modUpdateCmd())
 	cmd.AddCommand(modListCmd())
 	cmd.AddCommand(modInitCmd())
+	cmd.AddCommand(modUsageCmd())
 	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for mod")
 
 	cmdconfig.OnCmd(cmd).
@@ -147,15 +161,26 @@ func modUninstallCmd() *cobra.Command {
 		Short: "Uninstall a mod and its dependencies",
 		Long: `Uninstall a mod and its dependencies.
 
+The files which will be updated and the mod directories which will be deleted
+are shown, and must be confirmed unless --force is set.
+
 Example:
   
   # Uninstall a mod
-  steampipe mod uninstall github.com/turbot/steampipe-mod-azure-compliance`,
+  steampipe mod uninstall github.com/turbot/steampipe-mod-azure-compliance
+
+  # Uninstall a mod without confirmation
+  steampipe mod uninstall github.com/turbot/steampipe-mod-azure-compliance --force
+
+  # Show the actions uninstall would take as json, without uninstalling
+  steampipe mod uninstall github.com/turbot/steampipe-mod-azure-compliance --output json`,
 	}
 
 	cmdconfig.OnCmd(cmd).
 		AddBoolFlag(constants.ArgPrune, true, "Remove unused dependencies after uninstallation is complete").
 		AddBoolFlag(constants.ArgDryRun, false, "Show which mods would be uninstalled without modifying them").
+		AddBoolFlag(constants.ArgForce, false, "Uninstall without asking for confirmation").
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatText, "Output format for the plan of actions: text or json (json output only uninstalls if --force is set)").
 		AddBoolFlag(constants.ArgHelp, false, "Help for uninstall", cmdconfig.FlagOptions.WithShortHand("h")).
 		AddModLocationFlag()
 
@@ -173,6 +198,10 @@ func runModUninstallCmd(cmd *cobra.Command, args []string) {
 		}
 	}()
 
+	if !validateActionPlanOutputFormat(ctx) {
+		return
+	}
+
 	// try to load the workspace mod definition
 	// - if it does not exist, this will return a nil mod and a nil error
 	workspaceMod, err := parse.LoadModfile(viper.GetString(constants.ArgModLocation))
@@ -181,12 +210,35 @@ func runModUninstallCmd(cmd *cobra.Command, args []string) {
 		fmt.Println("No mods installed.")
 		return
 	}
-	opts := modinstaller.NewInstallOpts(workspaceMod, args...)
-	trimGitUrls(opts)
-	installData, err := modinstaller.UninstallWorkspaceDependencies(ctx, opts)
+
+	// a dry run makes no changes, so needs no confirmation
+	if !viper.GetBool(constants.ArgDryRun) {
+		plan := actionplan.New("steampipe mod uninstall")
+		err = modinstaller.PlanUninstallWorkspaceDependencies(ctx, newModUninstallOpts(workspaceMod, args), plan)
+		error_helpers.FailOnError(err)
+		if !confirmActionPlan(ctx, plan) {
+			return
+		}
+		// the plan is built by a dry run of the uninstall, which modifies the workspace mod - so reload it
+		workspaceMod, err = parse.LoadModfile(viper.GetString(constants.ArgModLocation))
+		error_helpers.FailOnErrorWithMessage(err, "failed to load mod definition")
+	}
+
+	installData, err := modinstaller.UninstallWorkspaceDependencies(ctx, newModUninstallOpts(workspaceMod, args))
 	error_helpers.FailOnError(err)
 
-	fmt.Println(modinstaller.BuildUninstallSummary(installData))
+	// json output is the plan only (a dry run has no plan, so always shows the summary)
+	if viper.GetBool(constants.ArgDryRun) || viper.GetString(constants.ArgOutput) == constants.OutputFormatText {
+		fmt.Println(modinstaller.BuildUninstallSummary(installData))
+	}
+}
+
+func newModUninstallOpts(workspaceMod *modconfig.Mod, args []string) *modinstaller.InstallOpts {
+	opts := modinstaller.NewInstallOpts(workspaceMod, args...)
+	// for uninstall, --force only skips confirmation - it must not suppress uninstall errors
+	opts.Force = false
+	trimGitUrls(opts)
+	return opts
 }
 
 // update
@@ -203,13 +255,21 @@ Example:
   steampipe mod update github.com/turbot/steampipe-mod-aws-compliance
 
   # Update all mods specified in the mod.sp and their dependencies to the latest versions that meet their constraints, and install any that are missing
-  steampipe mod update`,
+  steampipe mod update
+
+  # Update the version constraints in mod.sp to the latest version with the same major version, and update the mods
+  steampipe mod update --minor
+
+  # Update the version constraint of a mod in mod.sp to its latest version (including major versions), and update it
+  steampipe mod update github.com/turbot/steampipe-mod-aws-compliance --latest`,
 	}
 
 	cmdconfig.OnCmd(cmd).
 		AddBoolFlag(constants.ArgPrune, true, "Remove unused dependencies after update is complete").
 		AddBoolFlag(constants.ArgForce, false, "Update mods even if plugin/cli version requirements are not met (cannot be used with --dry-run)").
 		AddBoolFlag(constants.ArgDryRun, false, "Show which mods would be updated without modifying them").
+		AddBoolFlag(constants.ArgLatest, false, "Update the version constraints in mod.sp to the latest available versions (including new major versions)").
+		AddBoolFlag(constants.ArgMinor, false, "Update the version constraints in mod.sp to the latest available versions with the same major version").
 		AddBoolFlag(constants.ArgHelp, false, "Help for update", cmdconfig.FlagOptions.WithShortHand("h")).
 		AddModLocationFlag()
 
@@ -227,6 +287,12 @@ func runModUpdateCmd(cmd *cobra.Command, args []string) {
 		}
 	}()
 
+	if viper.GetBool(constants.ArgLatest) && viper.GetBool(constants.ArgMinor) {
+		error_helpers.ShowError(ctx, sperr.New("only one of --%s and --%s may be set", constants.ArgLatest, constants.ArgMinor))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
 	// try to load the workspace mod definition
 	// - if it does not exist, this will return a nil mod and a nil error
 	workspaceMod, err := parse.LoadModfile(viper.GetString(constants.ArgModLocation))
@@ -295,6 +361,160 @@ func runModListCmd(cmd *cobra.Command, _ []string) {
 	fmt.Println(treeString)
 }
 
+// usage
+func modUsageCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "usage",
+		Args:  cobra.NoArgs,
+		Run:   runModUsageCmd,
+		Short: "Show the tables and columns used by the mod",
+		Long: `Show the tables and columns used by the mod.
+
+Usage is recorded from the scan metadata of controls run with 'steampipe check --track-usage'.
+The recorded usage is compared with the current query of each control and the current
+table schemas, to find controls which reference tables or columns which no longer exist
+(for example after a plugin upgrade).
+
+Examples:
+
+  # Record usage for all controls
+  steampipe check all --track-usage
+
+  # Show usage, and any missing tables or columns
+  steampipe mod usage`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for usage", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddModLocationFlag()
+	return cmd
+}
+
+func runModUsageCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("cmd.runModUsageCmd")
+	defer func() {
+		utils.LogTime("cmd.runModUsageCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	workspacePath := viper.GetString(constants.ArgModLocation)
+	usage, err := modusage.Load(workspacePath)
+	error_helpers.FailOnErrorWithMessage(err, "failed to load usage")
+	if len(usage.Resources) == 0 {
+		fmt.Println("No usage recorded. Run 'steampipe check --track-usage' to record the tables and columns used by controls.")
+		return
+	}
+
+	// load the current query of each resource
+	w, inputVariables, errAndWarnings := workspace.LoadWorkspaceVars(ctx)
+	error_helpers.FailOnError(errAndWarnings.GetError())
+	errAndWarnings = w.LoadWorkspaceMod(ctx, inputVariables)
+	error_helpers.FailOnError(errAndWarnings.GetError())
+	resourceSql := make(map[string]string)
+	err = w.Mod.WalkResources(func(item modconfig.HclResource) (bool, error) {
+		if queryProvider, ok := item.(modconfig.QueryProvider); ok {
+			if sql := getQueryProviderSql(queryProvider); sql != "" {
+				resourceSql[item.Name()] = sql
+			}
+		}
+		return true, nil
+	})
+	error_helpers.FailOnError(err)
+
+	// load the current schema of the used tables
+	client, errAndWarnings := initialisation.GetDbClient(ctx, constants.InvokerQuery, nil)
+	if errAndWarnings.GetError() != nil {
+		error_helpers.ShowError(ctx, errAndWarnings.GetError())
+		exitCode = constants.ExitCodeInitializationFailed
+		return
+	}
+	defer client.Close(ctx)
+	schema, err := getTableSchemas(ctx, client, usage.Tables())
+	error_helpers.FailOnErrorWithMessage(err, "failed to load table schemas")
+
+	report := modusage.BuildReport(usage, resourceSql, schema)
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(report, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+	} else {
+		displayModUsageReport(report)
+	}
+
+	if len(report.MissingColumns) > 0 {
+		exitCode = constants.ExitCodeModUsageMissingColumns
+	}
+}
+
+// getQueryProviderSql returns the SQL of the query provider, or of the named query it uses
+func getQueryProviderSql(queryProvider modconfig.QueryProvider) string {
+	if sql := queryProvider.GetSQL(); sql != nil {
+		return *sql
+	}
+	if query := queryProvider.GetQuery(); query != nil && query.GetSQL() != nil {
+		return *query.GetSQL()
+	}
+	return ""
+}
+
+// getTableSchemas returns a map of table name to the set of columns of that table, for the given tables
+// (the columns of tables with the same name in different schemas are combined)
+func getTableSchemas(ctx context.Context, client db_common.Client, tables []string) (map[string]map[string]bool, error) {
+	res, err := client.ExecuteSync(ctx, "select table_name, column_name from information_schema.columns where table_name = any($1)", tables)
+	if err != nil {
+		return nil, err
+	}
+	schema := make(map[string]map[string]bool)
+	for _, row := range res.Rows {
+		data := row.(*queryresult.RowResult).Data
+		table, column := typehelpers.ToString(data[0]), typehelpers.ToString(data[1])
+		if schema[table] == nil {
+			schema[table] = make(map[string]bool)
+		}
+		schema[table][column] = true
+	}
+	return schema, nil
+}
+
+func displayModUsageReport(report *modusage.Report) {
+	var rows [][]string
+	for _, t := range report.Tables {
+		rows = append(rows, []string{t.Table, strings.Join(t.Columns, ", "), strings.Join(t.Resources, "\n")})
+	}
+	display.ShowWrappedTable([]string{"Table", "Columns", "Used By"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+
+	if len(report.MissingColumns) == 0 {
+		fmt.Println("\nAll tables and columns used by the mod exist.")
+	} else {
+		fmt.Printf("\n%s referenced by the mod no longer exist:\n\n", utils.Pluralize("table or column", len(report.MissingColumns)))
+		rows = nil
+		for _, m := range report.MissingColumns {
+			column := m.Column
+			if column == "" {
+				column = "(table not found)"
+			}
+			rows = append(rows, []string{m.Resource, m.Table, column})
+		}
+		display.ShowWrappedTable([]string{"Resource", "Table", "Column"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+	}
+
+	if len(report.StaleResources) > 0 {
+		fmt.Printf("\nUsage was recorded for %s which no longer exist in the workspace.\n", utils.Pluralize("resource", len(report.StaleResources)))
+	}
+}
+
 // init
 func modInitCmd() *cobra.Command {
 	var cmd = &cobra.Command{
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,21 +17,29 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
+	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/actionplan"
 	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/connection"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/contexthelpers"
 	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/filepaths"
 	"github.com/turbot/steampipe/pkg/installationstate"
+	"github.com/turbot/steampipe/pkg/network"
 	"github.com/turbot/steampipe/pkg/ociinstaller"
 	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
 	"github.com/turbot/steampipe/pkg/plugin"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/turbot/steampipe/pkg/version"
+	"golang.org/x/sync/semaphore"
 )
 
 type installedPlugin struct {
@@ -71,7 +83,16 @@ Examples:
   steampipe plugin list
 
   # Uninstall a plugin
-  steampipe plugin uninstall aws`,
+  steampipe plugin uninstall aws
+
+  # Restore the version of a plugin installed before it was last updated
+  steampipe plugin rollback aws
+
+  # Install, update and uninstall plugins to match the plugins.spc manifest of the workspace
+  steampipe plugin sync
+
+  # Check installed plugins are compatible with this version of Steampipe
+  steampipe plugin check-compat`,
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
 			utils.LogTime("cmd.plugin.PersistentPostRun start")
 			defer utils.LogTime("cmd.plugin.PersistentPostRun end")
@@ -82,6 +103,11 @@ Examples:
 	cmd.AddCommand(pluginListCmd())
 	cmd.AddCommand(pluginUninstallCmd())
 	cmd.AddCommand(pluginUpdateCmd())
+	cmd.AddCommand(pluginRollbackCmd())
+	cmd.AddCommand(pluginSyncCmd())
+	cmd.AddCommand(pluginDebugCmd())
+	cmd.AddCommand(pluginCheckCompatCmd())
+	cmd.AddCommand(pluginVerifyConnectionsCmd())
 	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for plugin")
 
 	return cmd
@@ -116,13 +142,26 @@ Examples:
   steampipe plugin install --progress=false aws
 
   # Skip creation of default plugin config file
-  steampipe plugin install --skip-config aws`,
+  steampipe plugin install --skip-config aws
+
+  # Install a plugin from a local OCI archive, without registry access
+  steampipe plugin install aws@0.118.0 --from-archive ./aws-0.118.0.tar
+
+  # Install another version of a plugin alongside the installed version
+  # (connections use it by setting plugin_version = "0.118.0")
+  steampipe plugin install aws@0.118.0 --side-by-side
+
+  # Install plugins, at most two at a time
+  steampipe plugin install aws azure gcp --concurrency 2`,
 	}
 
 	cmdconfig.
 		OnCmd(cmd).
-		AddBoolFlag(constants.ArgProgress, true, "Display installation progress").
+		AddProgressFlag("Display installation progress").
 		AddBoolFlag(constants.ArgSkipConfig, false, "Skip creating the default config file for plugin").
+		AddStringFlag(constants.ArgFromArchive, "", "Install the plugin from a local OCI archive (an OCI image layout directory or tarball) rather than the registry").
+		AddIntFlag(constants.ArgConcurrency, constants.DefaultPluginInstallConcurrency, "The maximum number of plugins to install concurrently").
+		AddBoolFlag(constants.ArgSideBySide, false, "Install the plugin in a directory addressed by its digest, alongside any other installed versions of the plugin").
 		AddBoolFlag(constants.ArgHelp, false, "Help for plugin install", cmdconfig.FlagOptions.WithShortHand("h"))
 	return cmd
 }
@@ -156,12 +195,89 @@ Examples:
 	cmdconfig.
 		OnCmd(cmd).
 		AddBoolFlag(constants.ArgAll, false, "Update all plugins to its latest available version").
-		AddBoolFlag(constants.ArgProgress, true, "Display installation progress").
+		AddIntFlag(constants.ArgConcurrency, constants.DefaultPluginInstallConcurrency, "The maximum number of plugins to update concurrently").
+		AddProgressFlag("Display installation progress").
 		AddBoolFlag(constants.ArgHelp, false, "Help for plugin update", cmdconfig.FlagOptions.WithShortHand("h"))
 
 	return cmd
 }
 
+// Roll back a plugin update
+func pluginRollbackCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "rollback [flags] [registry/org/]name[@version]",
+		Args:  cobra.ArbitraryArgs,
+		Run:   runPluginRollbackCmd,
+		Short: "Restore the previously installed version of one or more plugins",
+		Long: `Restore the previously installed version of one or more plugins.
+
+When a plugin is updated, the previously installed version is kept. Rolling back
+restores it and refreshes the connections which use the plugin. The version which
+was rolled back is kept in its place, so a rollback may itself be rolled back.
+
+Examples:
+
+  # Roll back an update of a common plugin (turbot/aws)
+  steampipe plugin rollback aws
+
+  # Roll back an update of a plugin installed for a specific version stream
+  steampipe plugin rollback aws@^0.92`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for plugin rollback", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+// Sync plugins with the workspace plugin manifest
+func pluginSyncCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sync [flags]",
+		Args:  cobra.NoArgs,
+		Run:   runPluginSyncCmd,
+		Short: "Install, update and uninstall plugins to match the workspace plugin manifest",
+		Long: `Install, update and uninstall plugins to match the workspace plugin manifest.
+
+The manifest is the plugins.spc file in the workspace directory, which lists the
+plugins the workspace requires and (optionally) the versions they must satisfy:
+
+  plugin "aws" {
+    version = "^0.118"
+  }
+
+  plugin "theapsgroup/gitlab" {}
+
+Plugins which are not installed, or whose installed version does not satisfy the
+manifest, are installed at the latest version satisfying it. Installed plugins which
+are not in the manifest are uninstalled, unless --prune=false is set (locally built
+plugins are never uninstalled).
+
+Examples:
+
+  # Sync plugins with the plugins.spc file of the current directory
+  steampipe plugin sync
+
+  # Show the changes sync would make, without making them
+  steampipe plugin sync --dry-run
+
+  # Sync plugins with a manifest in another location, without uninstalling any plugins
+  steampipe plugin sync --manifest ~/manifests/plugins.spc --prune=false`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddStringFlag(constants.ArgManifest, "", "Path of the plugin manifest (defaults to plugins.spc in the workspace directory)").
+		AddBoolFlag(constants.ArgPrune, true, "Uninstall plugins which are not in the manifest").
+		AddBoolFlag(constants.ArgDryRun, false, "Show the changes which would be made, without making them").
+		AddIntFlag(constants.ArgConcurrency, constants.DefaultPluginInstallConcurrency, "The maximum number of plugins to install concurrently").
+		AddProgressFlag("Display installation progress").
+		AddBoolFlag(constants.ArgHelp, false, "Help for plugin sync", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
 // List plugins
 func pluginListCmd() *cobra.Command {
 	var cmd = &cobra.Command{
@@ -206,20 +322,149 @@ Uninstall a Steampipe plugin, removing it from use. The plugin name format is
 [registry/org/]name. (Version is not relevant in uninstall, since only one
 version of a plugin can be installed at a time.)
 
+The files which will be deleted and the connection schemas which will be dropped
+are shown, and must be confirmed unless --force is set.
+
 Example:
 
   # Uninstall a common plugin (turbot/aws)
   steampipe plugin uninstall aws
 
+  # Uninstall a plugin without confirmation
+  steampipe plugin uninstall aws --force
+
+  # Show the actions uninstall would take as json, without uninstalling
+  steampipe plugin uninstall aws --output json
+
 `,
 	}
 
 	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgForce, false, "Uninstall without asking for confirmation").
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatText, "Output format for the plan of actions: text or json (json output only uninstalls if --force is set)").
 		AddBoolFlag(constants.ArgHelp, false, "Help for plugin uninstall", cmdconfig.FlagOptions.WithShortHand("h"))
 
 	return cmd
 }
 
+// Enable or disable trace logging for a plugin
+func pluginDebugCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "debug [flags] [registry/org/]name",
+		Args:  cobra.ExactArgs(1),
+		Run:   runPluginDebugCmd,
+		Short: "Enable or disable trace logging for a plugin",
+		Long: `Enable or disable trace logging for a plugin.
+
+Trace logging includes the API requests, paging and retries made by the plugin.
+The trace logs are written to a dedicated log file for the plugin, which is tailed
+until interrupted.
+
+The log level of a plugin is set when it starts, so turning trace logging on or off
+restarts the plugin - the service does not need to be restarted. Any running
+instance of the plugin is stopped, which fails queries of its connections that are
+in progress and clears its query cache; it is restarted with the new log level the
+next time it is used.
+
+The name may be either the label of a plugin block or a plugin name
+([registry/org/]name), in which case all instances of the plugin are affected.
+
+Examples:
+
+  # Enable trace logging for the aws plugin and tail the logs
+  steampipe plugin debug aws --on
+
+  # Tail the trace logs for the aws plugin (trace logging must already be enabled)
+  steampipe plugin debug aws
+
+  # Disable trace logging for the aws plugin
+  steampipe plugin debug aws --off`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgOn, false, "Enable trace logging for the plugin and tail the logs").
+		AddBoolFlag(constants.ArgOff, false, "Disable trace logging for the plugin").
+		AddBoolFlag(constants.ArgHelp, false, "Help for plugin debug", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+// Check installed plugins are compatible with this CLI version
+func pluginCheckCompatCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "check-compat",
+		Args:  cobra.NoArgs,
+		Run:   runPluginCheckCompatCmd,
+		Short: "Check installed plugins are compatible with this version of Steampipe",
+		Long: `Check installed plugins are compatible with this version of Steampipe.
+
+The plugin sdk version each installed plugin was built with is read from the plugin binary.
+Plugins built with an sdk version which is no longer supported must be updated, and plugins
+built with an sdk version newer than this version of Steampipe supports require Steampipe
+to be upgraded. For each incompatible plugin, the minimum Steampipe or plugin version
+required is reported.
+
+Exits with a non-zero exit code if any plugin is incompatible.
+
+Examples:
+
+  # Check installed plugins
+  steampipe plugin check-compat
+
+  # Check installed plugins, with json output
+  steampipe plugin check-compat --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for plugin check-compat", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+// Verify the credentials of the connections of a plugin
+func pluginVerifyConnectionsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "verify-connections [flags] [registry/org/]name",
+		Args:  cobra.ExactArgs(1),
+		Run:   runPluginVerifyConnectionsCmd,
+		Short: "Verify the credentials of the connections of a plugin",
+		Long: `Verify the credentials of the connections of a plugin.
+
+For each connection of the plugin, the plugin lists a single resource, bypassing
+the cache. The result for each connection is one of:
+
+  reachable     the API accepted the connection credentials
+  forbidden     the API rejected the connection credentials or their permissions
+  timeout       the API did not respond within the timeout
+  schema_error  the connection failed to load (e.g. invalid connection config), so
+                its schema could not be imported
+  error         the probe failed for some other reason
+  skipped       the connection was not probed (e.g. it is disabled)
+
+The name may be either the label of a plugin block or a plugin name
+([registry/org/]name), in which case the connections of all instances of the
+plugin are verified. Aggregator connections are not verified.
+
+Exits with a non-zero exit code if any connection is not reachable.
+
+Examples:
+
+  # Verify the aws connections
+  steampipe plugin verify-connections aws
+
+  # Verify the aws connections, with json output
+  steampipe plugin verify-connections aws --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddIntFlag(constants.ArgProbeTimeout, int(connection.DefaultHealthCheckTimeout.Seconds()), "The time in seconds allowed for the probe of each connection").
+		AddBoolFlag(constants.ArgHelp, false, "Help for plugin verify-connections", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
 var pluginInstallSteps = []string{
 	"Downloading",
 	"Installing Plugin",
@@ -247,9 +492,26 @@ func runPluginInstallCmd(cmd *cobra.Command, args []string) {
 	// - aws@0.118.0
 	// - aws@^0.118
 	// - ghcr.io/turbot/steampipe/plugins/turbot/aws:1.0.0
+	// when installing from an archive, the registry is not accessed, so network access is not required
+	archivePath := viper.GetString(constants.ArgFromArchive)
+	if archivePath != "" {
+		if len(args) != 1 {
+			error_helpers.ShowError(ctx, sperr.New("exactly one plugin must be specified when installing from an archive"))
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+		if _, err := os.Stat(archivePath); err != nil {
+			error_helpers.ShowError(ctx, sperr.WrapWithMessage(err, "cannot read archive %s", archivePath))
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+	} else if err := network.CheckOnline("plugin install"); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodePluginInstallFailure
+		return
+	}
+
 	plugins := append([]string{}, args...)
-	showProgress := viper.GetBool(constants.ArgProgress)
-	installReports := make(display.PluginInstallReports, 0, len(plugins))
 
 	if len(plugins) == 0 {
 		if len(steampipeconfig.GlobalConfig.Plugins) == 0 {
@@ -261,6 +523,10 @@ func runPluginInstallCmd(cmd *cobra.Command, args []string) {
 		// get the list of plugins to install
 		for imageRef := range steampipeconfig.GlobalConfig.Plugins {
 			ref := ociinstaller.NewSteampipeImageRef(imageRef)
+			// side by side installs are installed for the plugin_version of connections (see installPinnedPluginVersions)
+			if _, _, constraint := ref.GetOrgNameAndConstraint(); ociinstaller.IsDigestConstraint(constraint) {
+				continue
+			}
 			plugins = append(plugins, ref.GetFriendlyName())
 		}
 	}
@@ -272,42 +538,84 @@ func runPluginInstallCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	installs := make([]pluginInstall, len(plugins))
+	for i, pluginName := range plugins {
+		installs[i] = pluginInstall{name: pluginName, sideBySide: viper.GetBool(constants.ArgSideBySide)}
+	}
+
 	// a leading blank line - since we always output multiple lines
 	fmt.Println()
+	installReports := installPlugins(ctx, state.InstallationID, installs, archivePath)
+	// the version of a plugin installed from an archive is not resolved from the hub
+	if archivePath == "" {
+		installReports = append(installReports, installPinnedPluginVersions(ctx, state.InstallationID, plugins)...)
+	}
+	display.PrintInstallReports(installReports, false)
+
+	// a concluding blank line - since we always output multiple lines
+	fmt.Println()
+}
+
+// pluginInstall is a plugin to install
+type pluginInstall struct {
+	// the name of the plugin, as passed to 'plugin install'
+	name string
+	// if set, the latest version of the plugin satisfying this constraint is installed
+	version string
+	// install the plugin in a directory addressed by its digest, alongside the other installed versions of the plugin
+	sideBySide bool
+}
+
+// displayName returns the name of the plugin install shown in the progress bars and install reports
+func (i pluginInstall) displayName() string {
+	if i.version == "" {
+		return i.name
+	}
+	return fmt.Sprintf("%s (%s)", i.name, i.version)
+}
+
+// installPlugins installs the plugins concurrently (with at most the number set by --concurrency installed at once),
+// returning the install reports and setting the exit code if any install fails
+func installPlugins(ctx context.Context, installationID string, installs []pluginInstall, archivePath string) display.PluginInstallReports {
+	showProgress := statushooks.ProgressEnabled()
+	installReports := make(display.PluginInstallReports, 0, len(installs))
 	progressBars := uiprogress.New()
 	installWaitGroup := &sync.WaitGroup{}
-	reportChannel := make(chan *display.PluginInstallReport, len(plugins))
+	reportChannel := make(chan *display.PluginInstallReport, len(installs))
+	// limit the number of plugins which are resolved and installed concurrently
+	installLimiter := newPluginInstallLimiter()
 
 	if showProgress {
 		progressBars.Start()
 	}
-	for _, pluginName := range plugins {
+	totalBar := createTotalProgressBar(len(installs), progressBars)
+	// resolve the telemetry_redact fields before starting the install goroutines, as they read viper
+	redacted := utils.RedactedRequestPayloadFields()
+	for _, install := range installs {
 		installWaitGroup.Add(1)
-		bar := createProgressBar(pluginName, progressBars)
+		bar := createProgressBar(install.displayName(), progressBars)
 
-		ref := ociinstaller.NewSteampipeImageRef(pluginName)
-		org, name, constraint := ref.GetOrgNameAndConstraint()
-		orgAndName := fmt.Sprintf("%s/%s", org, name)
-		var resolved plugin.ResolvedPluginVersion
-		if ref.IsFromSteampipeHub() {
-			rpv, err := plugin.GetLatestPluginVersionByConstraint(ctx, state.InstallationID, org, name, constraint)
-			if err != nil || rpv == nil {
-				report := &display.PluginInstallReport{
-					Plugin:         pluginName,
+		go func(install pluginInstall, bar *uiprogress.Bar) {
+			if err := installLimiter.Acquire(ctx, 1); err != nil {
+				reportChannel <- pluginInstallFailedReport(install.displayName(), err, false)
+				installWaitGroup.Done()
+				return
+			}
+			defer installLimiter.Release(1)
+
+			resolved, reinstall, ok := resolvePluginInstallVersion(ctx, installationID, redacted, install, archivePath)
+			if !ok {
+				reportChannel <- &display.PluginInstallReport{
+					Plugin:         install.displayName(),
 					Skipped:        true,
 					SkipReason:     constants.InstallMessagePluginNotFound,
 					IsUpdateReport: false,
 				}
-				reportChannel <- report
 				installWaitGroup.Done()
-				continue
+				return
 			}
-			resolved = *rpv
-		} else {
-			resolved = plugin.NewResolvedPluginVersion(orgAndName, constraint, constraint)
-		}
-
-		go doPluginInstall(ctx, bar, pluginName, resolved, installWaitGroup, reportChannel)
+			doPluginInstall(ctx, bar, install, resolved, reinstall, installWaitGroup, reportChannel)
+		}(install, bar)
 	}
 	go func() {
 		installWaitGroup.Wait()
@@ -315,6 +623,7 @@ func runPluginInstallCmd(cmd *cobra.Command, args []string) {
 	}()
 	installCount := 0
 	for report := range reportChannel {
+		totalBar.Incr()
 		installReports = append(installReports, report)
 		if !report.Skipped {
 			installCount++
@@ -327,30 +636,219 @@ func runPluginInstallCmd(cmd *cobra.Command, args []string) {
 	}
 
 	if installCount > 0 {
-		// TODO do we need to refresh connections here
+		// if the service is running, notify it so it refreshes any connections which were missing their plugin
+		notifyPluginsInstalled(ctx, installReports)
 
 		// reload the config, since an installation should have created a new config file
-		var cmd = viper.Get(constants.ConfigKeyActiveCommand).(*cobra.Command)
-		config, errorsAndWarnings := steampipeconfig.LoadSteampipeConfig(ctx, viper.GetString(constants.ArgModLocation), cmd.Name())
-		if errorsAndWarnings.GetError() != nil {
-			error_helpers.ShowWarning(fmt.Sprintf("Failed to reload config - install report may be incomplete (%s)", errorsAndWarnings.GetError()))
-		} else {
-			steampipeconfig.GlobalConfig = config
+		reloadPluginConfig(ctx)
+
+		statushooks.Done(ctx)
+	}
+	return installReports
+}
+
+// reloadPluginConfig reloads the config after plugins have been installed, so it includes the installed versions
+func reloadPluginConfig(ctx context.Context) {
+	var cmd = viper.Get(constants.ConfigKeyActiveCommand).(*cobra.Command)
+	config, errorsAndWarnings := steampipeconfig.LoadSteampipeConfig(ctx, viper.GetString(constants.ArgModLocation), cmd.Name())
+	if errorsAndWarnings.GetError() != nil {
+		error_helpers.ShowWarning(fmt.Sprintf("Failed to reload config - install report may be incomplete (%s)", errorsAndWarnings.GetError()))
+		return
+	}
+	steampipeconfig.GlobalConfig = config
+}
+
+// installPinnedPluginVersions installs the versions of the given plugins selected by the plugin_version property
+// of connections, which are not satisfied by an installed version of the plugin
+// each version is installed side by side, leaving the install of the stream (e.g. aws@latest) unchanged,
+// so connections which do not set plugin_version are unaffected
+func installPinnedPluginVersions(ctx context.Context, installationID string, plugins []string) display.PluginInstallReports {
+	pins := steampipeconfig.GlobalConfig.PluginVersionPins()
+	var installs []pluginInstall
+	for _, pluginName := range plugins {
+		imageRef := ociinstaller.NewSteampipeImageRef(pluginName).DisplayImageRef()
+		for _, pin := range pins[imageRef] {
+			if !steampipeconfig.GlobalConfig.PluginVersionInstalled(imageRef, pin) {
+				installs = append(installs, pluginInstall{name: pluginName, version: pin, sideBySide: true})
+			}
+		}
+	}
+	if len(installs) == 0 {
+		return nil
+	}
+	return installPlugins(ctx, installationID, installs, "")
+}
+
+func runPluginSyncCmd(cmd *cobra.Command, _ []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runPluginSyncCmd start")
+	defer func() {
+		utils.LogTime("runPluginSyncCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
 		}
+	}()
 
+	manifestPath := viper.GetString(constants.ArgManifest)
+	if manifestPath == "" {
+		manifestPath = filepath.Join(viper.GetString(constants.ArgModLocation), filepaths.PluginManifestFileName)
+	}
+	manifest, err := steampipeconfig.LoadPluginManifest(manifestPath)
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeConfigLoadFailed
+		return
+	}
+
+	install, remove := manifest.SyncPlan(steampipeconfig.GlobalConfig.PluginVersions)
+	if !viper.GetBool(constants.ArgPrune) {
+		remove = nil
+	}
+	if len(install) == 0 && len(remove) == 0 {
+		fmt.Println("Installed plugins match the manifest")
+		return
+	}
+	if viper.GetBool(constants.ArgDryRun) {
+		for _, p := range install {
+			fmt.Printf("Would install %s\n", p)
+		}
+		for _, p := range remove {
+			fmt.Printf("Would uninstall %s\n", ociinstaller.NewSteampipeImageRef(p).GetFriendlyName())
+		}
+		return
+	}
+
+	if len(install) > 0 {
+		if err := network.CheckOnline("plugin sync"); err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodePluginInstallFailure
+			return
+		}
+		state, err := installationstate.Load()
+		if err != nil {
+			error_helpers.ShowError(ctx, fmt.Errorf("could not load state"))
+			exitCode = constants.ExitCodePluginLoadingError
+			return
+		}
+		// a leading blank line - since we always output multiple lines
+		fmt.Println()
+		// the plugins are installed at the manifest versions
+		versions := manifest.VersionConstraints()
+		installs := make([]pluginInstall, len(install))
+		for i, pluginName := range install {
+			installs[i] = pluginInstall{name: pluginName, version: versions[ociinstaller.NewSteampipeImageRef(pluginName).DisplayImageRef()]}
+		}
+		installReports := installPlugins(ctx, state.InstallationID, installs, "")
+		display.PrintInstallReports(installReports, false)
+	}
+
+	if len(remove) > 0 {
+		connectionMap, _, _, res := getPluginConnectionMap(ctx)
+		if res.Error != nil {
+			error_helpers.ShowError(ctx, res.Error)
+			exitCode = constants.ExitCodePluginListFailure
+			return
+		}
+		reports := steampipeconfig.PluginRemoveReports{}
+		for _, p := range remove {
+			name := ociinstaller.NewSteampipeImageRef(p).GetFriendlyName()
+			statushooks.SetStatus(ctx, fmt.Sprintf("Uninstalling %s", name))
+			report, err := plugin.Remove(ctx, p, connectionMap)
+			if err != nil {
+				error_helpers.ShowErrorWithMessage(ctx, err, fmt.Sprintf("Failed to uninstall plugin '%s'", name))
+				exitCode = constants.ExitCodePluginInstallFailure
+				continue
+			}
+			report.ShortName = name
+			reports = append(reports, *report)
+		}
 		statushooks.Done(ctx)
+		reports.Print()
 	}
-	display.PrintInstallReports(installReports, false)
 
 	// a concluding blank line - since we always output multiple lines
 	fmt.Println()
 }
 
-func doPluginInstall(ctx context.Context, bar *uiprogress.Bar, pluginName string, resolvedPlugin plugin.ResolvedPluginVersion, wg *sync.WaitGroup, returnChannel chan *display.PluginInstallReport) {
+// resolvePluginInstallVersion resolves the version of a plugin to install
+// if the install sets a version, the plugin is resolved to the latest version satisfying it
+// reinstall is set if the plugin must be installed even if it is already installed:
+// for side by side installs (as the install directory depends on the resolved image),
+// and if the installed version does not satisfy the version of the install
+// ok is false if no version of the plugin could be found
+func resolvePluginInstallVersion(ctx context.Context, installationID string, redacted []string, install pluginInstall, archivePath string) (resolved plugin.ResolvedPluginVersion, reinstall, ok bool) {
+	ref := ociinstaller.NewSteampipeImageRef(install.name)
+	org, name, constraint := ref.GetOrgNameAndConstraint()
+	orgAndName := fmt.Sprintf("%s/%s", org, name)
+	reinstall = install.sideBySide ||
+		(install.version != "" && steampipeconfig.CheckInstalledPluginVersion(ref.DisplayImageRef(), install.version, steampipeconfig.GlobalConfig.PluginVersions[ref.DisplayImageRef()]) != nil)
+
+	// the version of a plugin installed from an archive is not resolved from the hub
+	if !ref.IsFromSteampipeHub() || archivePath != "" {
+		return plugin.NewResolvedPluginVersion(orgAndName, constraint, constraint), reinstall, true
+	}
+
+	versionConstraint := constraint
+	if install.version != "" {
+		versionConstraint = install.version
+	}
+	rpv, err := plugin.GetLatestPluginVersionByConstraint(ctx, installationID, redacted, org, name, versionConstraint)
+	if err != nil || rpv == nil {
+		return resolved, false, false
+	}
+	resolved = *rpv
+	// install the version in the folder of the requested stream
+	// (side by side installs are installed in the folder of their digest)
+	resolved.Constraint = constraint
+	return resolved, reinstall, true
+}
+
+// newPluginInstallLimiter returns a semaphore limiting the number of plugins installed or updated concurrently
+func newPluginInstallLimiter() *semaphore.Weighted {
+	concurrency := viper.GetInt64(constants.ArgConcurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return semaphore.NewWeighted(concurrency)
+}
+
+// pluginInstallFailedReport returns the report of a plugin whose installation failed
+func pluginInstallFailedReport(pluginName string, err error, isUpdate bool) *display.PluginInstallReport {
+	return &display.PluginInstallReport{
+		Plugin:         pluginName,
+		Skipped:        true,
+		Failed:         true,
+		SkipReason:     err.Error(),
+		IsUpdateReport: isUpdate,
+	}
+}
+
+// notifyPluginsInstalled sends a notification to the running service (if any) that the plugins have been installed,
+// so that the plugin manager reloads the connection config and refreshes connections
+func notifyPluginsInstalled(ctx context.Context, reports []*display.PluginInstallReport) {
+	var plugins []string
+	for _, report := range reports {
+		if !report.Skipped {
+			plugins = append(plugins, report.Plugin)
+		}
+	}
+	if err := db_local.SendPluginInstalledNotification(ctx, plugins); err != nil {
+		// the connections will be refreshed the next time the service starts
+		log.Printf("[WARN] failed to send plugin installed notification: %s", err.Error())
+	}
+}
+
+// if reinstall is set, the plugin is installed even if it is already installed
+// (this is used for side by side installs, and when the installed version does not satisfy the version of the install)
+func doPluginInstall(ctx context.Context, bar *uiprogress.Bar, install pluginInstall, resolvedPlugin plugin.ResolvedPluginVersion, reinstall bool, wg *sync.WaitGroup, returnChannel chan *display.PluginInstallReport) {
 	var report *display.PluginInstallReport
 
-	pluginAlreadyInstalled, _ := plugin.Exists(ctx, pluginName)
-	if pluginAlreadyInstalled {
+	pluginAlreadyInstalled, _ := plugin.Exists(ctx, install.name)
+	if pluginAlreadyInstalled && !reinstall {
 		// set the bar to MAX
 		//nolint:golint,errcheck // the error happens if we set this over the max value
 		bar.Set(len(pluginInstallSteps))
@@ -359,7 +857,7 @@ func doPluginInstall(ctx context.Context, bar *uiprogress.Bar, pluginName string
 			return helpers.Resize(constants.InstallMessagePluginAlreadyInstalled, 20)
 		})
 		report = &display.PluginInstallReport{
-			Plugin:         pluginName,
+			Plugin:         install.displayName(),
 			Skipped:        true,
 			SkipReason:     constants.InstallMessagePluginAlreadyInstalled,
 			IsUpdateReport: false,
@@ -378,7 +876,7 @@ func doPluginInstall(ctx context.Context, bar *uiprogress.Bar, pluginName string
 			}
 		})
 
-		report = installPlugin(ctx, resolvedPlugin, false, bar)
+		report = installPlugin(ctx, resolvedPlugin, false, install.sideBySide, bar)
 	}
 	returnChannel <- report
 	wg.Done()
@@ -402,8 +900,14 @@ func runPluginUpdateCmd(cmd *cobra.Command, args []string) {
 	// - aws@0.118.0
 	// - aws@^0.118
 	// - ghcr.io/turbot/steampipe/plugins/turbot/aws:1.0.0
+	if err := network.CheckOnline("plugin update"); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodePluginInstallFailure
+		return
+	}
+
 	plugins, err := resolveUpdatePluginsFromArgs(args)
-	showProgress := viper.GetBool(constants.ArgProgress)
+	showProgress := statushooks.ProgressEnabled()
 
 	if err != nil {
 		fmt.Println()
@@ -444,6 +948,10 @@ func runPluginUpdateCmd(cmd *cobra.Command, args []string) {
 		for k, v := range pluginVersions {
 			ref := ociinstaller.NewSteampipeImageRef(k)
 			org, name, constraint := ref.GetOrgNameAndConstraint()
+			// side by side installs are of a specific image, so are never updated
+			if ociinstaller.IsDigestConstraint(constraint) {
+				continue
+			}
 			key := fmt.Sprintf("%s/%s@%s", org, name, constraint)
 
 			plugins = append(plugins, key)
@@ -487,7 +995,7 @@ func runPluginUpdateCmd(cmd *cobra.Command, args []string) {
 	defer cancel()
 
 	statushooks.SetStatus(ctx, "Checking for available updates")
-	reports := plugin.GetUpdateReport(timeoutCtx, state.InstallationID, runUpdatesFor)
+	reports := plugin.GetUpdateReport(timeoutCtx, state.InstallationID, utils.RedactedRequestPayloadFields(), runUpdatesFor)
 	statushooks.Done(ctx)
 	if len(reports) == 0 {
 		// this happens if for some reason the update server could not be contacted,
@@ -504,12 +1012,23 @@ func runPluginUpdateCmd(cmd *cobra.Command, args []string) {
 		progressBars.Start()
 	}
 
+	// limit the number of plugins which are updated concurrently
+	updateLimiter := newPluginInstallLimiter()
+	totalBar := createTotalProgressBar(len(reports), progressBars)
 	sorted := utils.SortedMapKeys(reports)
 	for _, key := range sorted {
 		report := reports[key]
 		updateWaitGroup.Add(1)
 		bar := createProgressBar(report.ShortNameWithConstraint(), progressBars)
-		go doPluginUpdate(ctx, bar, report, updateWaitGroup, reportChannel)
+		go func(report plugin.VersionCheckReport, bar *uiprogress.Bar) {
+			if err := updateLimiter.Acquire(ctx, 1); err != nil {
+				reportChannel <- pluginInstallFailedReport(report.ShortNameWithConstraint(), err, true)
+				updateWaitGroup.Done()
+				return
+			}
+			defer updateLimiter.Release(1)
+			doPluginUpdate(ctx, bar, report, updateWaitGroup, reportChannel)
+		}(report, bar)
 	}
 	go func() {
 		updateWaitGroup.Wait()
@@ -518,6 +1037,7 @@ func runPluginUpdateCmd(cmd *cobra.Command, args []string) {
 	installCount := 0
 
 	for updateResult := range reportChannel {
+		totalBar.Incr()
 		updateResults = append(updateResults, updateResult)
 		if !updateResult.Skipped {
 			installCount++
@@ -527,8 +1047,28 @@ func runPluginUpdateCmd(cmd *cobra.Command, args []string) {
 		progressBars.Stop()
 	}
 
+	if installCount > 0 {
+		// if the service is running, notify it so it refreshes the connections using the updated plugins
+		notifyPluginsInstalled(ctx, updateResults)
+	}
+
 	display.PrintInstallReports(updateResults, true)
 
+	if installCount > 0 {
+		// the updated version of a plugin may no longer satisfy the plugin_version of the connections which used it
+		// - install the pinned versions side by side
+		reloadPluginConfig(ctx)
+		var updated []string
+		for _, report := range reports {
+			if plugin.UpdateRequired(report) {
+				updated = append(updated, report.ShortNameWithConstraint())
+			}
+		}
+		if pinnedReports := installPinnedPluginVersions(ctx, state.InstallationID, updated); len(pinnedReports) > 0 {
+			display.PrintInstallReports(pinnedReports, false)
+		}
+	}
+
 	// a concluding blank line - since we always output multiple lines
 	fmt.Println()
 }
@@ -547,7 +1087,7 @@ func doPluginUpdate(ctx context.Context, bar *uiprogress.Bar, pvr plugin.Version
 			return helpers.Resize(pluginInstallSteps[b.Current()-1], 20)
 		})
 		rp := plugin.NewResolvedPluginVersion(pvr.ShortName(), pvr.CheckResponse.Version, pvr.CheckResponse.Constraint)
-		report = installPlugin(ctx, rp, true, bar)
+		report = installPlugin(ctx, rp, true, false, bar)
 	} else {
 		// update NOT required, return already installed report
 		bar.AppendFunc(func(b *uiprogress.Bar) string {
@@ -568,6 +1108,18 @@ func doPluginUpdate(ctx context.Context, bar *uiprogress.Bar, pvr plugin.Version
 	wg.Done()
 }
 
+// createTotalProgressBar creates a bar showing the number of plugins whose install or update has completed
+func createTotalProgressBar(total int, parentProgressBars *uiprogress.Progress) *uiprogress.Bar {
+	bar := parentProgressBars.AddBar(total)
+	bar.PrependFunc(func(b *uiprogress.Bar) string {
+		return helpers.Resize("Total", 30)
+	})
+	bar.AppendFunc(func(b *uiprogress.Bar) string {
+		return fmt.Sprintf("%d of %d complete", b.Current(), total)
+	})
+	return bar
+}
+
 func createProgressBar(plugin string, parentProgressBars *uiprogress.Progress) *uiprogress.Bar {
 	bar := parentProgressBars.AddBar(len(pluginInstallSteps))
 	bar.PrependFunc(func(b *uiprogress.Bar) string {
@@ -576,7 +1128,7 @@ func createProgressBar(plugin string, parentProgressBars *uiprogress.Progress) *
 	return bar
 }
 
-func installPlugin(ctx context.Context, resolvedPlugin plugin.ResolvedPluginVersion, isUpdate bool, bar *uiprogress.Bar) *display.PluginInstallReport {
+func installPlugin(ctx context.Context, resolvedPlugin plugin.ResolvedPluginVersion, isUpdate, sideBySide bool, bar *uiprogress.Bar) *display.PluginInstallReport {
 	// start a channel for progress publications from plugin.Install
 	progress := make(chan struct{}, 5)
 	defer func() {
@@ -592,7 +1144,18 @@ func installPlugin(ctx context.Context, resolvedPlugin plugin.ResolvedPluginVers
 		}
 	}()
 
-	image, err := plugin.Install(ctx, resolvedPlugin, progress, ociinstaller.WithSkipConfig(viper.GetBool(constants.ArgSkipConfig)))
+	opts := []ociinstaller.PluginInstallOption{ociinstaller.WithSkipConfig(viper.GetBool(constants.ArgSkipConfig))}
+	if archivePath := viper.GetString(constants.ArgFromArchive); archivePath != "" {
+		opts = append(opts, ociinstaller.WithArchive(archivePath))
+	}
+	if sideBySide {
+		opts = append(opts, ociinstaller.WithSideBySide(true))
+	}
+	// keep the version being updated, so the update can be rolled back
+	if isUpdate {
+		opts = append(opts, ociinstaller.WithBackup(true))
+	}
+	image, err := plugin.Install(ctx, resolvedPlugin, progress, opts...)
 	if err != nil {
 		msg := ""
 		// used to build data for the plugin install report to be used for display purposes
@@ -601,7 +1164,7 @@ func installPlugin(ctx context.Context, resolvedPlugin plugin.ResolvedPluginVers
 			exitCode = constants.ExitCodePluginNotFound
 			msg = constants.InstallMessagePluginNotFound
 		} else {
-			msg = err.Error()
+			return pluginInstallFailedReport(fmt.Sprintf("%s@%s", name, constraint), err, isUpdate)
 		}
 		return &display.PluginInstallReport{
 			Plugin:         fmt.Sprintf("%s@%s", name, constraint),
@@ -613,6 +1176,10 @@ func installPlugin(ctx context.Context, resolvedPlugin plugin.ResolvedPluginVers
 
 	// used to build data for the plugin install report to be used for display purposes
 	org, name, _ := image.ImageRef.GetOrgNameAndConstraint()
+	installedConstraint := resolvedPlugin.Constraint
+	if sideBySide {
+		installedConstraint = ociinstaller.DigestConstraint(string(image.OCIDescriptor.Digest))
+	}
 	versionString := ""
 	if image.Config.Plugin.Version != "" {
 		versionString = " v" + image.Config.Plugin.Version
@@ -622,7 +1189,7 @@ func installPlugin(ctx context.Context, resolvedPlugin plugin.ResolvedPluginVers
 		docURL = fmt.Sprintf("https://%s/%s", org, name)
 	}
 	return &display.PluginInstallReport{
-		Plugin:         fmt.Sprintf("%s@%s", name, resolvedPlugin.Constraint),
+		Plugin:         fmt.Sprintf("%s@%s", name, installedConstraint),
 		Skipped:        false,
 		Version:        versionString,
 		DocURL:         docURL,
@@ -816,6 +1383,10 @@ func runPluginUninstallCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if !validateActionPlanOutputFormat(ctx) {
+		return
+	}
+
 	connectionMap, _, _, res := getPluginConnectionMap(ctx)
 	if res.Error != nil {
 		error_helpers.ShowError(ctx, res.Error)
@@ -823,9 +1394,26 @@ func runPluginUninstallCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	reports := steampipeconfig.PluginRemoveReports{}
-	statushooks.SetStatus(ctx, fmt.Sprintf("Uninstalling %s", utils.Pluralize("plugin", len(args))))
+	// build the plan of actions for the installed plugins, and get confirmation before uninstalling them
+	plan := actionplan.New("steampipe plugin uninstall")
+	var toRemove []string
 	for _, p := range args {
+		if err := plugin.PlanRemove(plan, p, connectionMap); err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				exitCode = constants.ExitCodePluginNotFound
+			}
+			error_helpers.ShowErrorWithMessage(ctx, err, fmt.Sprintf("Failed to uninstall plugin '%s'", p))
+			continue
+		}
+		toRemove = append(toRemove, p)
+	}
+	if len(toRemove) == 0 || !confirmActionPlan(ctx, plan) {
+		return
+	}
+
+	reports := steampipeconfig.PluginRemoveReports{}
+	statushooks.SetStatus(ctx, fmt.Sprintf("Uninstalling %s", utils.Pluralize("plugin", len(toRemove))))
+	for _, p := range toRemove {
 		statushooks.SetStatus(ctx, fmt.Sprintf("Uninstalling %s", p))
 		if report, err := plugin.Remove(ctx, p, connectionMap); err != nil {
 			if strings.Contains(err.Error(), "not found") {
@@ -838,7 +1426,352 @@ func runPluginUninstallCmd(cmd *cobra.Command, args []string) {
 		}
 	}
 	statushooks.Done(ctx)
-	reports.Print()
+	// json output is the plan only
+	if viper.GetString(constants.ArgOutput) == constants.OutputFormatText {
+		reports.Print()
+	}
+}
+
+func runPluginRollbackCmd(cmd *cobra.Command, args []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runPluginRollbackCmd start")
+
+	defer func() {
+		utils.LogTime("runPluginRollbackCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	if len(args) == 0 {
+		fmt.Println()
+		error_helpers.ShowError(ctx, fmt.Errorf("you need to provide at least one plugin to roll back"))
+		fmt.Println()
+		cmd.Help()
+		fmt.Println()
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	var rolledBack []string
+	for _, p := range args {
+		restored, err := plugin.Rollback(ctx, p)
+		statushooks.Done(ctx)
+		if err != nil {
+			error_helpers.ShowErrorWithMessage(ctx, err, fmt.Sprintf("Failed to roll back plugin '%s'", p))
+			exitCode = constants.ExitCodePluginLoadingError
+			continue
+		}
+		rolledBack = append(rolledBack, restored.Name)
+		fmt.Printf("Rolled back %s to v%s\n", restored.Name, restored.Version)
+	}
+
+	if len(rolledBack) > 0 {
+		// if the service is running, notify it so it refreshes the connections using the restored plugins
+		if err := db_local.SendPluginInstalledNotification(ctx, rolledBack); err != nil {
+			// the connections will be refreshed the next time the service starts
+			log.Printf("[WARN] failed to send plugin installed notification: %s", err.Error())
+		}
+	}
+}
+
+func runPluginDebugCmd(cmd *cobra.Command, args []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runPluginDebugCmd start")
+	defer func() {
+		utils.LogTime("runPluginDebugCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	on := viper.GetBool(constants.ArgOn)
+	off := viper.GetBool(constants.ArgOff)
+	if on && off {
+		error_helpers.ShowError(ctx, sperr.New("only one of --%s and --%s may be specified", constants.ArgOn, constants.ArgOff))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	name := args[0]
+	pluginInstances := resolvePluginInstances(name)
+	if len(pluginInstances) == 0 {
+		error_helpers.ShowError(ctx, sperr.New("no plugin instance found for '%s' - is it used by a connection?", name))
+		exitCode = constants.ExitCodePluginNotFound
+		return
+	}
+
+	if on || off {
+		signalled, err := pluginmanager.SetPluginDebug(pluginInstances, on)
+		if err != nil {
+			error_helpers.ShowErrorWithMessage(ctx, err, fmt.Sprintf("failed to update trace logging for plugin '%s'", name))
+			exitCode = constants.ExitCodePluginDebugFailure
+			return
+		}
+		status := "disabled"
+		if on {
+			status = "enabled"
+		}
+		fmt.Printf("Trace logging %s for plugin '%s'.\n", status, name)
+		if !signalled {
+			fmt.Println("The plugin manager is not running - this will take effect when the service next starts.")
+		} else {
+			fmt.Println("Any running instance of the plugin has been stopped - it will restart with the new log level when next used.")
+		}
+		if off {
+			return
+		}
+	} else {
+		debugState, err := pluginmanager.LoadDebugState()
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodePluginDebugFailure
+			return
+		}
+		if !debugState.IsEnabled(pluginInstances[0]) {
+			error_helpers.ShowError(ctx, sperr.New("trace logging is not enabled for plugin '%s' - run %s to enable", name, constants.Bold(fmt.Sprintf("steampipe plugin debug %s --on", name))))
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+	}
+
+	// tail the trace logs of all the plugin instances
+	// (the tail goroutines may fail concurrently, so the exit code is set under a lock)
+	var wg sync.WaitGroup
+	var exitCodeMut sync.Mutex
+	for _, pluginInstance := range pluginInstances {
+		pluginInstance := pluginInstance
+		fmt.Printf("Tailing %s (press Ctrl+C to stop)\n", filepaths.PluginDebugLogPath(pluginInstance))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			getPath := func() string { return filepaths.PluginDebugLogPath(pluginInstance) }
+			if err := utils.TailFile(ctx, getPath, os.Stdout); err != nil {
+				error_helpers.ShowError(ctx, err)
+				exitCodeMut.Lock()
+				exitCode = constants.ExitCodePluginDebugFailure
+				exitCodeMut.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func runPluginCheckCompatCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runPluginCheckCompatCmd start")
+	defer func() {
+		utils.LogTime("runPluginCheckCompatCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	compatibility, err := plugin.GetPluginCompatibility(ctx)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to check plugin compatibility")
+		exitCode = constants.ExitCodePluginLoadingError
+		return
+	}
+
+	// for plugins which must be updated, resolve the version to update to
+	resolvePluginCompatibilityUpdateVersions(ctx, compatibility)
+
+	incompatibleCount := 0
+	for _, c := range compatibility {
+		if !c.Compatible {
+			incompatibleCount++
+		}
+	}
+
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(compatibility, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+	} else {
+		headers := []string{"Plugin", "Version", "SDK Version", "Compatible", "Fix"}
+		var rows [][]string
+		for _, c := range compatibility {
+			rows = append(rows, []string{c.ShortName(), c.Version, c.SdkVersion, fmt.Sprintf("%v", c.Compatible), c.Fix()})
+		}
+		display.ShowWrappedTable(headers, rows, &display.ShowWrappedTableOptions{AutoMerge: false, HideEmptyColumns: true})
+		if incompatibleCount == 0 {
+			fmt.Printf("\nAll installed plugins are compatible with Steampipe v%s.\n", version.SteampipeVersion.String())
+		} else {
+			fmt.Printf("\n%d %s %s incompatible with Steampipe v%s.\n", incompatibleCount, utils.Pluralize("plugin", incompatibleCount), utils.Pluralize("is", incompatibleCount), version.SteampipeVersion.String())
+		}
+	}
+
+	if incompatibleCount > 0 {
+		exitCode = constants.ExitCodePluginIncompatible
+	}
+}
+
+// resolvePluginCompatibilityUpdateVersions sets the update version of plugins which are too old to the latest
+// version available from the hub (this is best effort - if the version cannot be resolved, the fix refers to the sdk version)
+func resolvePluginCompatibilityUpdateVersions(ctx context.Context, compatibility []plugin.PluginCompatibility) {
+	state, err := installationstate.Load()
+	if err != nil {
+		log.Printf("[WARN] could not load state: %s", err)
+		return
+	}
+	for i, c := range compatibility {
+		if c.Compatible || c.TooNew {
+			continue
+		}
+		ref := ociinstaller.NewSteampipeImageRef(c.Plugin)
+		if !ref.IsFromSteampipeHub() {
+			continue
+		}
+		org, name, constraint := ref.GetOrgNameAndConstraint()
+		resolved, err := plugin.GetLatestPluginVersionByConstraint(ctx, state.InstallationID, utils.RedactedRequestPayloadFields(), org, name, constraint)
+		if err != nil || resolved == nil {
+			log.Printf("[TRACE] could not resolve latest version of %s: %v", c.Plugin, err)
+			continue
+		}
+		compatibility[i].UpdateVersion = resolved.Version
+	}
+}
+
+// resolvePluginInstances returns the plugin instances for the given name
+// this may be a plugin instance label, or a plugin name, in which case all instances of the plugin are returned
+func resolvePluginInstances(name string) []string {
+	if _, ok := steampipeconfig.GlobalConfig.PluginsInstances[name]; ok {
+		return []string{name}
+	}
+	var res []string
+	for _, p := range steampipeconfig.GlobalConfig.Plugins[modconfig.ResolvePluginImageRef(name)] {
+		res = append(res, p.Instance)
+	}
+	return res
+}
+
+func runPluginVerifyConnectionsCmd(cmd *cobra.Command, args []string) {
+	// setup a cancel context and start cancel handler
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runPluginVerifyConnectionsCmd start")
+	defer func() {
+		utils.LogTime("runPluginVerifyConnectionsCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	timeout, err := connection.HealthCheckTimeout(viper.GetInt(constants.ArgProbeTimeout))
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	name := args[0]
+	pluginInstances := resolvePluginInstances(name)
+	if len(pluginInstances) == 0 {
+		error_helpers.ShowError(ctx, sperr.New("no plugin instance found for '%s' - is it used by a connection?", name))
+		exitCode = constants.ExitCodePluginNotFound
+		return
+	}
+	connectionNames := pluginConnectionNames(pluginInstances)
+	if len(connectionNames) == 0 {
+		fmt.Printf("Plugin '%s' has no connections to verify.\n", name)
+		return
+	}
+
+	statushooks.Show(ctx)
+	defer statushooks.Done(ctx)
+
+	// start service
+	statushooks.SetStatus(ctx, "Starting service")
+	client, res := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	error_helpers.FailOnError(res.Error)
+	defer client.Close(ctx)
+
+	conn, err := client.AcquireManagementConnection(ctx)
+	error_helpers.FailOnError(err)
+	defer conn.Release()
+
+	// wait for the connections to be loaded
+	statushooks.SetStatus(ctx, "Loading connection state")
+	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilReady(connectionNames...))
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to load connection state")
+		exitCode = constants.ExitCodeConnectionStateFailed
+		return
+	}
+	schemaMetadata, err := client.GetSchemaFromDB(ctx)
+	error_helpers.FailOnErrorWithMessage(err, "failed to load the connection schemas")
+
+	statushooks.SetStatus(ctx, fmt.Sprintf("Verifying %d %s", len(connectionNames), utils.Pluralize("connection", len(connectionNames))))
+	results := connection.VerifyConnections(ctx, client, connectionStateMap, schemaMetadata, connectionNames, timeout)
+	statushooks.Done(ctx)
+
+	for _, result := range results {
+		if result.Result != connection.ConnectionVerifyReachable && result.Result != connection.ConnectionVerifySkipped {
+			exitCode = constants.ExitCodeConnectionCheckFailed
+		}
+	}
+
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(results, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+	showConnectionVerificationResult(results)
+}
+
+// pluginConnectionNames returns the (sorted) names of the connections which use the given plugin instances
+// aggregator connections are excluded, as their child connections are verified individually
+func pluginConnectionNames(pluginInstances []string) []string {
+	instanceLookup := utils.SliceToLookup(pluginInstances)
+	var res []string
+	for name, c := range steampipeconfig.GlobalConfig.Connections {
+		if c.Type == modconfig.ConnectionTypeAggregator {
+			continue
+		}
+		if _, ok := instanceLookup[typehelpers.SafeString(c.PluginInstance)]; ok {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func showConnectionVerificationResult(results []*connection.ConnectionVerification) {
+	var rows [][]string
+	for _, result := range results {
+		latency := ""
+		if result.Table != "" {
+			latency = fmt.Sprintf("%dms", result.LatencyMs)
+		}
+		rows = append(rows, []string{result.Connection, result.Result, result.Table, latency, result.Error})
+	}
+	display.ShowWrappedTable([]string{"Connection", "Result", "Table", "Latency", "Error"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
 }
 
 func getPluginList(ctx context.Context) (pluginList []plugin.PluginListItem, failedPluginMap, missingPluginMap map[string][]*modconfig.Connection, res error_helpers.ErrorAndWarnings) {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,15 +12,19 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/go-kit/logging"
 	"github.com/turbot/go-kit/types"
 	sdklogging "github.com/turbot/steampipe-plugin-sdk/v5/logging"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/telemetry"
 	"github.com/turbot/steampipe/pkg/cmdconfig"
 	"github.com/turbot/steampipe/pkg/connection"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/metrics"
 	"github.com/turbot/steampipe/pkg/pluginmanager_service"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 )
@@ -30,7 +35,9 @@ func pluginManagerCmd() *cobra.Command {
 		Run:    runPluginManagerCmd,
 		Hidden: true,
 	}
-	cmdconfig.OnCmd(cmd)
+	cmdconfig.OnCmd(cmd).
+		AddIntFlag(constants.ArgRefreshConcurrency, 0, "The number of connections to update in parallel when refreshing connections").
+		AddIntFlag(constants.ArgMetricsPort, 0, "Serve Prometheus metrics on this port (0 to disable)")
 	return cmd
 }
 
@@ -51,6 +58,15 @@ func runPluginManagerCmd(cmd *cobra.Command, _ []string) {
 }
 
 func doRunPluginManager(cmd *cobra.Command) error {
+	// initialise telemetry - spans are only exported if enabled by the otel level env var,
+	// which is inherited from the CLI process which started the plugin manager (see tracing.SetEnv)
+	shutdownTelemetry, err := telemetry.Init(constants.AppName + "-plugin-manager")
+	if err != nil {
+		log.Printf("[WARN] failed to initialise telemetry: %s", err.Error())
+	} else {
+		defer shutdownTelemetry()
+	}
+
 	pluginManager, err := createPluginManager(cmd)
 	if err != nil {
 		return err
@@ -67,11 +83,37 @@ func doRunPluginManager(cmd *cobra.Command) error {
 		defer connectionWatcher.Close()
 	}
 
+	// reload connection config on SIGHUP (sent by `steampipe service reload`)
+	// this is handled regardless of whether the connection watcher is running
+	startReloadSignalHandler(pluginManager)
+	// reload the plugin debug state on SIGUSR1 (sent by `steampipe plugin debug`)
+	startDebugSignalHandler(pluginManager)
+
+	// serve metrics if a metrics port is set
+	if metricsPort := viper.GetInt(constants.ArgMetricsPort); metricsPort > 0 {
+		metricsServer, err := metrics.Serve(metricsListenAddresses(), metricsPort, pluginManager.Pool())
+		if err != nil {
+			return err
+		}
+		defer metricsServer.Close()
+	}
+
 	log.Printf("[INFO] about to serve")
 	pluginManager.Serve()
 	return nil
 }
 
+// metricsListenAddresses returns the listen addresses given to the running database service
+// - the metrics are served on the same addresses, so they are only accessible from the network if the database is
+func metricsListenAddresses() []string {
+	dbState, err := db_local.GetState()
+	if err != nil || dbState == nil {
+		log.Printf("[WARN] failed to load the database state - metrics will only be served on the loopback interface")
+		return nil
+	}
+	return dbState.GivenListenAddresses
+}
+
 func createPluginManager(cmd *cobra.Command) (*pluginmanager_service.PluginManager, error) {
 	ctx := cmd.Context()
 	logger := createPluginManagerLog()
@@ -107,6 +149,49 @@ func createPluginManager(cmd *cobra.Command) (*pluginmanager_service.PluginManag
 	return pluginManager, nil
 }
 
+// startReloadSignalHandler starts a goroutine which reloads the connection config and refreshes connections
+// whenever the plugin manager receives a SIGHUP
+func startReloadSignalHandler(pluginManager *pluginmanager_service.PluginManager) {
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	go func() {
+		for range reloadCh {
+			log.Printf("[INFO] plugin manager received SIGHUP - reloading connection config")
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[WARN] failed to reload connection config: %s", helpers.ToError(r).Error())
+					}
+				}()
+				// this is a signal handler and not bound to any context
+				connection.ReloadConnectionConfig(context.Background(), pluginManager)
+			}()
+		}
+	}()
+}
+
+// startDebugSignalHandler starts a goroutine which reloads the plugin debug state
+// whenever the plugin manager receives a SIGUSR1
+func startDebugSignalHandler(pluginManager *pluginmanager_service.PluginManager) {
+	debugCh := make(chan os.Signal, 1)
+	signal.Notify(debugCh, syscall.SIGUSR1)
+	go func() {
+		for range debugCh {
+			log.Printf("[INFO] plugin manager received SIGUSR1 - reloading plugin debug state")
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[WARN] failed to reload plugin debug state: %s", helpers.ToError(r).Error())
+					}
+				}()
+				if err := pluginManager.ReloadPluginDebugState(); err != nil {
+					log.Printf("[WARN] failed to reload plugin debug state: %s", err.Error())
+				}
+			}()
+		}
+	}()
+}
+
 func shouldRunConnectionWatcher() bool {
 	// if EnvConnectionWatcher is set, overwrite the value in DefaultConnectionOptions
 	if envStr, ok := os.LookupEnv(constants.EnvConnectionWatcher); ok {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -27,6 +28,7 @@ import (
 	"github.com/turbot/steampipe/pkg/query/queryexecute"
 	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/turbot/steampipe/pkg/workspace"
@@ -34,9 +36,11 @@ import (
 
 // variable used to assign the timing mode flag
 var queryTimingMode = constants.QueryTimingModeOff
+var queryTimingSource = constants.QueryTimingSourceScanMetadata
 
 // variable used to assign the output mode flag
 var queryOutputMode = constants.QueryOutputModeTable
+var queryCsvQuoteMode = constants.CsvQuoteModeMinimal
 
 func queryCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -84,22 +88,37 @@ Examples:
 		AddModLocationFlag().
 		AddBoolFlag(constants.ArgHelp, false, "Help for query", cmdconfig.FlagOptions.WithShortHand("h")).
 		AddBoolFlag(constants.ArgHeader, true, "Include column headers csv and table output").
-		AddStringFlag(constants.ArgSeparator, ",", "Separator string for csv output").
+		AddStringFlag(constants.ArgSeparator, ",", "Separator string for csv output - a single character, or 'tab'").
+		AddVarFlag(enumflag.New(&queryCsvQuoteMode, constants.ArgQuoteMode, constants.CsvQuoteModeIds, enumflag.EnumCaseInsensitive),
+			constants.ArgQuoteMode,
+			fmt.Sprintf("Quoting of csv output fields; one of: %s", strings.Join(constants.FlagValues(constants.CsvQuoteModeIds), ", "))).
+		AddStringFlag(constants.ArgMaxColWidth, strconv.Itoa(constants.MaxColumnWidth), "Maximum width of table output columns - if set, longer values are truncated (use json output to see full values); auto fits the table to the terminal width, hiding columns if needed").
+		AddStringSliceFlag(constants.ArgPriorityColumns, nil, "Columns to keep visible when columns are hidden to fit the terminal width with --max-col-width auto (comma-separated)").
+		AddStringFlag(constants.ArgTimezone, "", "Timezone used to display timestamptz values in table and line output: local, utc or an IANA timezone name").
+		AddStringFlag(constants.ArgTimestampFormat, "", "Format used to display timestamps in table and line output: rfc3339, rfc3339ms, rfc1123, datetime, kitchen or a Go time layout").
+		AddStringFlag(constants.ArgNullString, "", "String used to display null values in table, line and csv output (default \"<null>\" for table and line, empty for csv)").
 		AddVarFlag(enumflag.New(&queryOutputMode, constants.ArgOutput, constants.QueryOutputModeIds, enumflag.EnumCaseInsensitive),
 			constants.ArgOutput,
 			fmt.Sprintf("Output format; one of: %s", strings.Join(constants.FlagValues(constants.QueryOutputModeIds), ", "))).
+		AddBoolFlag(constants.ArgTyped, false, "Include a column schema and preserve native value types in json output").
+		AddBoolFlag(constants.ArgStableOrder, false, "Sort result rows into a stable order (by the primary key columns where known, then all columns), so output and exports can be diffed").
 		AddVarFlag(enumflag.New(&queryTimingMode, constants.ArgTiming, constants.QueryTimingModeIds, enumflag.EnumCaseInsensitive),
 			constants.ArgTiming,
 			fmt.Sprintf("Display query timing; one of: %s", strings.Join(constants.FlagValues(constants.QueryTimingModeIds), ", ")),
 			cmdconfig.FlagOptions.NoOptDefVal(constants.ArgOn)).
+		AddVarFlag(enumflag.New(&queryTimingSource, constants.ArgTimingSource, constants.QueryTimingSourceIds, enumflag.EnumCaseInsensitive),
+			constants.ArgTimingSource,
+			fmt.Sprintf("Source of query timing data; one of: %s (explain re-runs the query using EXPLAIN ANALYZE, off reports the duration only)", strings.Join(constants.FlagValues(constants.QueryTimingSourceIds), ", "))).
 		AddBoolFlag(constants.ArgWatch, true, "Watch SQL files in the current workspace (works only in interactive mode)").
 		AddStringSliceFlag(constants.ArgSearchPath, nil, "Set a custom search_path for the steampipe user for a query session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, nil, "Set a prefix to the current search path for a query session (comma-separated)").
+		AddStringSliceFlag(constants.ArgConnection, nil, "Query the given connection(s) by adding them to the start of the search path for this query session (comma-separated)").
 		AddStringSliceFlag(constants.ArgVarFile, nil, "Specify a file containing variable values").
 		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
 		// Cobra will interpret values passed to a StringSliceFlag as CSV,
 		// where args passed to StringArrayFlag are not parsed and used raw
 		AddStringArrayFlag(constants.ArgVariable, nil, "Specify the value of a variable").
+		AddVariablesWorkspaceFlag().
 		AddBoolFlag(constants.ArgInput, true, "Enable interactive prompts").
 		AddBoolFlag(constants.ArgSnapshot, false, "Create snapshot in Turbot Pipes with the default (workspace) visibility").
 		AddBoolFlag(constants.ArgShare, false, "Create snapshot in Turbot Pipes with 'anyone_with_link' visibility").
@@ -108,7 +127,7 @@ Examples:
 		AddIntFlag(constants.ArgDatabaseQueryTimeout, 0, "The query timeout").
 		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported format: sps (snapshot)").
 		AddStringFlag(constants.ArgSnapshotLocation, "", "The location to write snapshots - either a local file path or a Turbot Pipes workspace").
-		AddBoolFlag(constants.ArgProgress, true, "Display snapshot upload status")
+		AddProgressFlag("Display snapshot upload status")
 
 	cmd.AddCommand(getListSubCmd(listSubCmdOptions{parentCmd: cmd}))
 
@@ -129,6 +148,10 @@ func runQueryCmd(cmd *cobra.Command, args []string) {
 	err := validateQueryArgs(ctx, args)
 	error_helpers.FailOnError(err)
 
+	// add any connections passed with the connection arg to the search path prefix
+	err = setConnectionSearchPathPrefix()
+	error_helpers.FailOnError(err)
+
 	// if diagnostic mode is set, print out config and return
 	if _, ok := os.LookupEnv(constants.EnvConfigDump); ok {
 		cmdconfig.DisplayConfig()
@@ -155,7 +178,7 @@ func runQueryCmd(cmd *cobra.Command, args []string) {
 	// start the initializer
 	initData := query.NewInitData(initCtx, args)
 	if initData.Result.Error != nil {
-		exitCode = constants.ExitCodeInitializationFailed
+		exitCode = error_helpers.ExitCode(initData.Result.Error, constants.ExitCodeInitializationFailed)
 		error_helpers.ShowError(ctx, initData.Result.Error)
 		return
 	}
@@ -203,16 +226,58 @@ func validateQueryArgs(ctx context.Context, args []string) error {
 		return err
 	}
 
-	validOutputFormats := []string{constants.OutputFormatLine, constants.OutputFormatCSV, constants.OutputFormatTable, constants.OutputFormatJSON, constants.OutputFormatSnapshot, constants.OutputFormatSnapshotShort, constants.OutputFormatNone}
 	output := viper.GetString(constants.ArgOutput)
-	if !helpers.StringSliceContains(validOutputFormats, output) {
+	if !helpers.StringSliceContains(constants.QueryOutputFormats, output) {
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return sperr.New("invalid output format: '%s', must be one of [%s]", output, strings.Join(constants.QueryOutputFormats, ", "))
+	}
+	if _, err := utils.ParseTimezone(viper.GetString(constants.ArgTimezone)); err != nil {
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return sperr.New("invalid timezone '%s'", viper.GetString(constants.ArgTimezone))
+	}
+	if timestampFormat := viper.GetString(constants.ArgTimestampFormat); timestampFormat != "" {
+		if _, err := utils.TimestampLayout(timestampFormat); err != nil {
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return err
+		}
+	}
+	if _, err := display.ParseCSVSeparator(viper.GetString(constants.ArgSeparator)); err != nil {
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return err
+	}
+	if err := display.ValidateMaxColumnWidth(viper.GetString(constants.ArgMaxColWidth)); err != nil {
 		exitCode = constants.ExitCodeInsufficientOrWrongInputs
-		return sperr.New("invalid output format: '%s', must be one of [%s]", output, strings.Join(validOutputFormats, ", "))
+		return err
+	}
+	if viper.GetBool(constants.ArgTyped) && output != constants.OutputFormatJSON {
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return sperr.New("--%s is only supported for json output", constants.ArgTyped)
 	}
 
 	return nil
 }
 
+// setConnectionSearchPathPrefix adds the connections passed with the connection arg to the start of the search path prefix
+// this only applies to this invocation - the workspace config is not modified
+func setConnectionSearchPathPrefix() error {
+	connections := helpers.RemoveFromStringSlice(viper.GetStringSlice(constants.ArgConnection), "")
+	if len(connections) == 0 {
+		return nil
+	}
+	// we can only validate the connection names for the local database
+	if viper.GetString(constants.ArgWorkspaceDatabase) == constants.DefaultWorkspaceDatabase && steampipeconfig.GlobalConfig != nil {
+		for _, c := range connections {
+			if _, ok := steampipeconfig.GlobalConfig.Connections[c]; !ok {
+				exitCode = constants.ExitCodeInsufficientOrWrongInputs
+				return sperr.New("connection '%s' does not exist", c)
+			}
+		}
+	}
+	searchPathPrefix := append(connections, viper.GetStringSlice(constants.ArgSearchPathPrefix)...)
+	viper.Set(constants.ArgSearchPathPrefix, searchPathPrefix)
+	return nil
+}
+
 func executeSnapshotQuery(initData *query.InitData, ctx context.Context) int {
 	// start cancel handler to intercept interrupts and cancel the context
 	// NOTE: use the initData Cancel function to ensure any initialisation is cancelled if needed
@@ -248,6 +313,11 @@ func executeSnapshotQuery(initData *query.InitData, ctx context.Context) int {
 			error_helpers.FailOnError(err)
 		}
 
+		// sort the query result rows if a stable order was requested
+		if viper.GetBool(constants.ArgStableOrder) {
+			sortSnapshotQueryRows(snap)
+		}
+
 		// set the filename root for the snapshot (in case needed)
 		if !existingResource {
 			snap.FileNameRoot = "query"
@@ -286,7 +356,7 @@ func executeSnapshotQuery(initData *query.InitData, ctx context.Context) int {
 			error_helpers.FailOnErrorWithMessage(err, "failed to export snapshot")
 		}
 		// print the location where the file is exported
-		if len(exportMsg) > 0 && viper.GetBool(constants.ArgProgress) {
+		if len(exportMsg) > 0 && statushooks.ProgressEnabled() {
 			fmt.Printf("\n")
 			fmt.Println(strings.Join(exportMsg, "\n"))
 			fmt.Printf("\n")
@@ -331,6 +401,16 @@ func snapshotToQueryResult(snap *dashboardtypes.SteampipeSnapshot) (*queryresult
 	return res, nil
 }
 
+func sortSnapshotQueryRows(snap *dashboardtypes.SteampipeSnapshot) {
+	tablePanel, ok := snap.Panels[modconfig.SnapshotQueryTableName]
+	if !ok {
+		return
+	}
+	if leafRun, ok := tablePanel.(*dashboardexecute.LeafRun); ok && leafRun.Data != nil {
+		leafRun.Data.SortRows()
+	}
+}
+
 // convert the given command line query into a query resource and add to workspace
 // this is to allow us to use existing dashboard execution code
 func ensureSnapshotQueryResource(name string, resolvedQuery *modconfig.ResolvedQuery, w *workspace.Workspace) (queryProvider modconfig.HclResource, existingResource bool) {
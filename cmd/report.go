@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/sbom"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// Report commands
+func reportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "report [command]",
+		Args:  cobra.NoArgs,
+		Short: "Steampipe installation reports",
+		Long: `Steampipe installation reports.
+
+Generate reports about the Steampipe installation, for supply-chain and compliance reviews.
+
+Examples:
+
+  # Generate an SPDX software bill of materials for the installed components
+  steampipe report sbom
+
+  # Generate a CycloneDX software bill of materials
+  steampipe report sbom --output cyclonedx`,
+	}
+
+	cmd.AddCommand(reportSbomCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for report")
+
+	return cmd
+}
+
+func reportSbomCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sbom",
+		Args:  cobra.NoArgs,
+		Run:   runReportSbomCmd,
+		Short: "Generate a software bill of materials for the installed components",
+		Long: `Generate a software bill of materials for the installed components.
+
+The bill of materials lists the Steampipe CLI, the embedded Postgres database and FDW,
+the installed plugins (with their image digests and licenses) and the mods installed
+in the current workspace.
+
+Examples:
+
+  # Generate an SPDX software bill of materials
+  steampipe report sbom > steampipe.spdx.json
+
+  # Generate a CycloneDX software bill of materials
+  steampipe report sbom --output cyclonedx > steampipe.cdx.json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, sbom.FormatSPDX, fmt.Sprintf("Output format: %s or %s", sbom.FormatSPDX, sbom.FormatCycloneDX)).
+		AddBoolFlag(constants.ArgHelp, false, "Help for report sbom", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddModLocationFlag()
+
+	return cmd
+}
+
+func runReportSbomCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runReportSbomCmd start")
+	defer func() {
+		utils.LogTime("runReportSbomCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if !helpers.StringSliceContains([]string{sbom.FormatSPDX, sbom.FormatCycloneDX}, outputFormat) {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, sbom.FormatSPDX, sbom.FormatCycloneDX))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	bom, err := sbom.Build(ctx, viper.GetString(constants.ArgModLocation))
+	error_helpers.FailOnErrorWithMessage(err, "failed to build software bill of materials")
+
+	var output []byte
+	if outputFormat == sbom.FormatCycloneDX {
+		output, err = bom.ToCycloneDX()
+	} else {
+		output, err = bom.ToSPDX()
+	}
+	error_helpers.FailOnError(err)
+
+	fmt.Println(string(output))
+}
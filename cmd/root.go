@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	filehelpers "github.com/turbot/go-kit/files"
@@ -59,10 +59,16 @@ func InitCmd() {
 	rootCmd.PersistentFlags().String(constants.ArgWorkspaceProfile, "default", "The workspace profile to use") // workspace profile profile is a global flag since install-dir(global) can be set through the workspace profile
 	rootCmd.PersistentFlags().String(constants.ArgInstallDir, defaultInstallDir, "Path to the Config Directory")
 	rootCmd.PersistentFlags().Bool(constants.ArgSchemaComments, true, "Include schema comments when importing connection schemas")
+	rootCmd.PersistentFlags().Bool(constants.ArgOffline, false, "Disable all outbound network requests (update checks, telemetry, Turbot Pipes and hub lookups)")
+	rootCmd.PersistentFlags().Bool(constants.ArgStrictConfig, false, "Fail if the config or workspace profiles contain settings which would otherwise be ignored")
+	rootCmd.PersistentFlags().String(constants.ArgExitCodeMap, "", fmt.Sprintf("Remap exit codes, e.g. 'alarm=0,connection_error=3,250=4'. Keys may be an exit code or one of: %s", strings.Join(constants.ExitCodeClasses(), ", ")))
 
 	error_helpers.FailOnError(viper.BindPFlag(constants.ArgInstallDir, rootCmd.PersistentFlags().Lookup(constants.ArgInstallDir)))
 	error_helpers.FailOnError(viper.BindPFlag(constants.ArgWorkspaceProfile, rootCmd.PersistentFlags().Lookup(constants.ArgWorkspaceProfile)))
 	error_helpers.FailOnError(viper.BindPFlag(constants.ArgSchemaComments, rootCmd.PersistentFlags().Lookup(constants.ArgSchemaComments)))
+	error_helpers.FailOnError(viper.BindPFlag(constants.ArgExitCodeMap, rootCmd.PersistentFlags().Lookup(constants.ArgExitCodeMap)))
+	error_helpers.FailOnError(viper.BindPFlag(constants.ArgOffline, rootCmd.PersistentFlags().Lookup(constants.ArgOffline)))
+	error_helpers.FailOnError(viper.BindPFlag(constants.ArgStrictConfig, rootCmd.PersistentFlags().Lookup(constants.ArgStrictConfig)))
 
 	AddCommands()
 
@@ -98,6 +104,15 @@ func AddCommands() {
 		dashboardCmd(),
 		variableCmd(),
 		loginCmd(),
+		benchCmd(),
+		configCmd(),
+		connectionCmd(),
+		initCmd(),
+		reportCmd(),
+		searchCmd(),
+		inspectCmd(),
+		telemetryCmd(),
+		scheduleCmd(),
 	)
 }
 
@@ -112,12 +127,9 @@ func Execute() int {
 }
 
 // create the root context - add a status renderer
+// (this is replaced in the pre run hook if the progress arg is set)
 func createRootContext() context.Context {
-	statusRenderer := statushooks.NullHooks
-	// if the client is a TTY, inject a status spinner
-	if isatty.IsTerminal(os.Stdout.Fd()) {
-		statusRenderer = statushooks.NewStatusSpinnerHook()
-	}
+	statusRenderer := statushooks.NewStatusHooksForProgress()
 
 	ctx := statushooks.AddStatusHooksToContext(context.Background(), statusRenderer)
 	return ctx
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/scheduler"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// Schedule management commands
+func scheduleCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "schedule [command]",
+		Args:  cobra.NoArgs,
+		Short: "Steampipe schedule management",
+		Long: `Steampipe schedule management.
+
+Inspect, run, pause and resume the jobs which the Steampipe service runs periodically.
+The state of each job is recorded in the steampipe_internal.steampipe_schedule table.`,
+	}
+
+	cmd.AddCommand(scheduleListCmd())
+	cmd.AddCommand(scheduleRunNowCmd())
+	cmd.AddCommand(schedulePauseCmd())
+	cmd.AddCommand(scheduleResumeCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for schedule")
+
+	return cmd
+}
+
+func scheduleListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Run:   runScheduleListCmd,
+		Short: "List the scheduled jobs of the service",
+		Long: `List the scheduled jobs of the service.
+
+Show the interval, state, last run and next run of each job scheduled by the
+running Steampipe service.
+
+Examples:
+
+  # List scheduled jobs
+  steampipe schedule list
+
+  # List scheduled jobs as json
+  steampipe schedule list --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for schedule list", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func scheduleRunNowCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "run-now <job>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runScheduleRunNowCmd,
+		Short: "Run a scheduled job immediately",
+		Long: `Run a scheduled job immediately.
+
+The job is run by the service, even if it is paused. The command returns once the
+run has been requested - use 'steampipe schedule list' to see the result.
+
+Examples:
+
+  # Sync discovered connections now
+  steampipe schedule run-now connection_discovery`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for schedule run-now", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func schedulePauseCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "pause <job>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runSchedulePauseCmd(true),
+		Short: "Pause a scheduled job",
+		Long: `Pause a scheduled job.
+
+A paused job is not run at its interval until it is resumed - it may still be run
+using 'steampipe schedule run-now'. Jobs remain paused when the service restarts.
+
+Examples:
+
+  # Stop syncing discovered connections
+  steampipe schedule pause connection_discovery`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for schedule pause", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func scheduleResumeCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "resume <job>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runSchedulePauseCmd(false),
+		Short: "Resume a paused scheduled job",
+		Long: `Resume a paused scheduled job.
+
+Examples:
+
+  # Resume syncing discovered connections
+  steampipe schedule resume connection_discovery`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for schedule resume", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runScheduleListCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runScheduleListCmd start")
+	defer func() {
+		utils.LogTime("runScheduleListCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	conn := getScheduleServiceConnection(ctx)
+	if conn == nil {
+		return
+	}
+	defer conn.Close(ctx)
+
+	states, err := scheduler.LoadScheduleState(ctx, conn)
+	error_helpers.FailOnErrorWithMessage(err, "failed to load the schedule")
+
+	if outputFormat == constants.OutputFormatJSON {
+		// output an empty array rather than null
+		if states == nil {
+			states = []*scheduler.ScheduleState{}
+		}
+		jsonOutput, err := json.MarshalIndent(states, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+	showScheduleState(states)
+}
+
+func runScheduleRunNowCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runScheduleRunNowCmd start")
+	defer func() {
+		utils.LogTime("runScheduleRunNowCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	conn := getScheduleServiceConnection(ctx)
+	if conn == nil {
+		return
+	}
+	defer conn.Close(ctx)
+
+	if err := scheduler.RequestRunNow(ctx, conn, args[0]); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	fmt.Printf("Requested a run of '%s'.\n", args[0])
+}
+
+func runSchedulePauseCmd(paused bool) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		utils.LogTime("runSchedulePauseCmd start")
+		defer func() {
+			utils.LogTime("runSchedulePauseCmd end")
+			if r := recover(); r != nil {
+				error_helpers.ShowError(ctx, helpers.ToError(r))
+				exitCode = constants.ExitCodeUnknownErrorPanic
+			}
+		}()
+
+		conn := getScheduleServiceConnection(ctx)
+		if conn == nil {
+			return
+		}
+		defer conn.Close(ctx)
+
+		if err := scheduler.SetPaused(ctx, conn, args[0], paused); err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+		if paused {
+			fmt.Printf("Paused '%s'.\n", args[0])
+		} else {
+			fmt.Printf("Resumed '%s'.\n", args[0])
+		}
+	}
+}
+
+// getScheduleServiceConnection returns a connection to the running service - the scheduled jobs are run by the service,
+// so if it is not running, an error is shown and nil is returned
+func getScheduleServiceConnection(ctx context.Context) *pgx.Conn {
+	state, err := db_local.GetState()
+	error_helpers.FailOnError(err)
+	if state == nil {
+		error_helpers.ShowError(ctx, sperr.New("the Steampipe service is not running - scheduled jobs are run by the service"))
+		exitCode = constants.ExitCodeServiceProbeFailed
+		return nil
+	}
+	conn, err := db_local.CreateLocalDbConnection(ctx, &db_local.CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, error_helpers.NewConnectionError(error_helpers.ErrorCodeDatabaseConnectionFailed, err), "failed to connect to the service")
+		exitCode = constants.ExitCodeDatabaseConnectionFailed
+		return nil
+	}
+	return conn
+}
+
+func showScheduleState(states []*scheduler.ScheduleState) {
+	if len(states) == 0 {
+		fmt.Println("No jobs are scheduled.")
+		return
+	}
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Local().Format(time.DateTime)
+	}
+	var rows [][]string
+	for _, s := range states {
+		status := "scheduled"
+		switch {
+		case s.Running:
+			status = "running"
+		case s.Paused:
+			status = "paused"
+		}
+		lastRun := formatTime(s.LastRunTime)
+		if s.LastRunDurationMs != nil {
+			lastRun = fmt.Sprintf("%s (%s)", lastRun, time.Duration(*s.LastRunDurationMs)*time.Millisecond)
+		}
+		nextRun := formatTime(s.NextRunTime)
+		if s.Paused {
+			nextRun = ""
+		}
+		rows = append(rows, []string{
+			s.Name,
+			(time.Duration(s.IntervalSeconds) * time.Second).String(),
+			status,
+			lastRun,
+			nextRun,
+			typehelpers.SafeString(s.LastError),
+			s.Description,
+		})
+	}
+	display.ShowWrappedTable([]string{"Job", "Interval", "Status", "Last Run", "Next Run", "Last Error", "Description"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+}
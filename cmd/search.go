@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/contexthelpers"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+func searchCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "search <term>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runSearchCmd,
+		Short: "Search table and column names and descriptions",
+		Long: `Search table and column names and descriptions.
+
+Search the names and descriptions of the tables and columns of all connections,
+and list the matching tables with the connections which provide them.
+
+Examples:
+
+  # Find tables relating to encryption
+  steampipe search encryption
+
+  # Output the matches as json
+  steampipe search "public access" --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Output format: table or json").
+		AddBoolFlag(constants.ArgHelp, false, "Help for search", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runSearchCmd(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	contexthelpers.StartCancelHandler(cancel)
+
+	utils.LogTime("runSearchCmd start")
+	defer func() {
+		utils.LogTime("runSearchCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if outputFormat != constants.OutputFormatTable && outputFormat != constants.OutputFormatJSON {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s, %s]", outputFormat, constants.OutputFormatTable, constants.OutputFormatJSON))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	term := strings.TrimSpace(args[0])
+	if term == "" {
+		error_helpers.ShowError(ctx, sperr.New("search term must not be empty"))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	statushooks.Show(ctx)
+	defer statushooks.Done(ctx)
+
+	// start service
+	statushooks.SetStatus(ctx, "Starting service")
+	client, res := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	error_helpers.FailOnError(res.Error)
+	defer client.Close(ctx)
+
+	// wait for connections to load, so the schema is complete
+	conn, err := client.AcquireManagementConnection(ctx)
+	error_helpers.FailOnError(err)
+	statushooks.SetStatus(ctx, "Loading connection state")
+	_, err = steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilReady())
+	conn.Release()
+	error_helpers.FailOnErrorWithMessage(err, "failed to load connection state")
+
+	statushooks.SetStatus(ctx, "Loading schema")
+	schemaMetadata, err := client.GetSchemaFromDB(ctx)
+	error_helpers.FailOnErrorWithMessage(err, "failed to load schema")
+	statushooks.Done(ctx)
+
+	matches := schemaMetadata.Search(term)
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(matches, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+	showSearchMatches(term, matches)
+}
+
+func showSearchMatches(term string, matches []*db_common.SchemaSearchMatch) {
+	if len(matches) == 0 {
+		fmt.Printf("No tables or columns match '%s'.\n", term)
+		return
+	}
+	headers := []string{"Table", "Connections", "Matching Columns", "Description"}
+	var rows [][]string
+	for _, m := range matches {
+		rows = append(rows, []string{m.Table, strings.Join(m.Connections, ","), strings.Join(m.Columns, ","), m.Description})
+	}
+	display.ShowWrappedTable(headers, rows, &display.ShowWrappedTableOptions{Truncate: true})
+}
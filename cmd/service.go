@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	psutils "github.com/shirou/gopsutil/process"
@@ -15,15 +18,19 @@ import (
 	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/actionplan"
 	"github.com/turbot/steampipe/pkg/cmdconfig"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/contexthelpers"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardserver"
 	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/gc"
 	"github.com/turbot/steampipe/pkg/pluginmanager"
 	pb "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/proto"
+	"github.com/turbot/steampipe/pkg/servicestatus"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/utils"
 )
@@ -43,6 +50,11 @@ connection from any Postgres compatible database client.`,
 	cmd.AddCommand(serviceStatusCmd())
 	cmd.AddCommand(serviceStopCmd())
 	cmd.AddCommand(serviceRestartCmd())
+	cmd.AddCommand(serviceProbeCmd())
+	cmd.AddCommand(serviceReloadCmd())
+	cmd.AddCommand(serviceGcCmd())
+	cmd.AddCommand(serviceMaintenanceCmd())
+	cmd.AddCommand(serviceUserCmd())
 	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for service")
 	return cmd
 }
@@ -64,15 +76,20 @@ connection from any Postgres compatible database client.`,
 		OnCmd(cmd).
 		AddModLocationFlag().
 		AddBoolFlag(constants.ArgHelp, false, "Help for service start", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddProgressFlag("Display service startup progress").
 		AddIntFlag(constants.ArgDatabasePort, constants.DatabaseDefaultPort, "Database service port").
 		AddStringFlag(constants.ArgDatabaseListenAddresses, string(db_local.ListenTypeNetwork), "Accept connections from: `local` (an alias for `localhost` only), `network` (an alias for `*`), or a comma separated list of hosts and/or IP addresses").
 		AddStringFlag(constants.ArgServicePassword, "", "Set the database password for this session").
+		AddIntFlag(constants.ArgRefreshConcurrency, 0, "The number of connections to update in parallel when refreshing connections").
+		AddIntFlag(constants.ArgMaxQueryDuration, 0, "The maximum duration (in seconds) of a query executed by a non-superuser session (0 for no limit)").
+		AddIntFlag(constants.ArgMetricsPort, 0, "Serve Prometheus metrics of the service on this port (0 to disable)").
 		// default is false and hides the database user password from service start prompt
 		AddBoolFlag(constants.ArgServiceShowPassword, false, "View database password for connecting from another machine").
 		// dashboard server
 		AddBoolFlag(constants.ArgDashboard, false, "Run the dashboard webserver with the service").
 		AddStringFlag(constants.ArgDashboardListen, string(dashboardserver.ListenTypeNetwork), "Accept connections from: local (localhost only) or network (open) (dashboard)").
 		AddIntFlag(constants.ArgDashboardPort, constants.DashboardServerDefaultPort, "Report server port").
+		AddDashboardServerFlags().
 		// foreground enables the service to run in the foreground - till exit
 		AddBoolFlag(constants.ArgForeground, false, "Run the service in the foreground").
 
@@ -82,6 +99,7 @@ connection from any Postgres compatible database client.`,
 		// Cobra will interpret values passed to a StringSliceFlag as CSV,
 		// where args passed to StringArrayFlag are not parsed and used raw
 		AddStringArrayFlag(constants.ArgVariable, nil, "Specify the value of a variable (only applies if '--dashboard' flag is also set)").
+		AddVariablesWorkspaceFlag().
 
 		// hidden flags for internal use
 		AddStringFlag(constants.ArgInvoker, string(constants.InvokerService), "Invoked by \"service\" or \"query\"", cmdconfig.FlagOptions.Hidden())
@@ -90,6 +108,8 @@ connection from any Postgres compatible database client.`,
 }
 
 // serviceStatusCmd :: handler for service status
+var serviceStatusOutputFormats = []string{constants.OutputFormatText, constants.OutputFormatJSON}
+
 func serviceStatusCmd() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "status",
@@ -98,14 +118,30 @@ func serviceStatusCmd() *cobra.Command {
 		Short: "Status of the Steampipe service",
 		Long: `Status of the Steampipe service.
 
-Report current status of the Steampipe database service.`,
+Report current status of the Steampipe database service.
+
+The json output includes the health of the service - the uptime of the
+database, the installed FDW version, the plugin manager and its plugins, the
+number of connections in each state and the utilization of the database
+sessions - for consumption by monitoring agents. The status is one of
+healthy, degraded, stopped or not_installed.
+
+Examples:
+
+  # Show the status of the service
+  steampipe service status
+
+  # Show the health of the service as json
+  steampipe service status --output json`,
 	}
 
 	cmdconfig.OnCmd(cmd).
 		AddBoolFlag(constants.ArgHelp, false, "Help for service status", cmdconfig.FlagOptions.WithShortHand("h")).
 		// default is false and hides the database user password from service start prompt
 		AddBoolFlag(constants.ArgServiceShowPassword, false, "View database password for connecting from another machine").
-		AddBoolFlag(constants.ArgAll, false, "Bypasses the INSTALL_DIR and reports status of all running steampipe services")
+		AddBoolFlag(constants.ArgAll, false, "Bypasses the INSTALL_DIR and reports status of all running steampipe services").
+		AddBoolFlag(constants.ArgWatch, false, "Continuously display the service status, active queries, plugin memory usage and connection refresh progress").
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatText, "Output format: text or json")
 
 	return cmd
 }
@@ -117,13 +153,29 @@ func serviceStopCmd() *cobra.Command {
 		Args:  cobra.NoArgs,
 		Run:   runServiceStopCmd,
 		Short: "Stop Steampipe service",
-		Long:  `Stop the Steampipe service.`,
+		Long: `Stop the Steampipe service.
+
+A forced stop shows the client sessions which will be terminated and the
+processes which will be stopped before stopping them.
+
+Examples:
+
+  # Stop the service
+  steampipe service stop
+
+  # Stop the service, terminating any client sessions
+  steampipe service stop --force
+
+  # Show the actions a forced stop would take as json, without stopping the service
+  steampipe service stop --dry-run --output json`,
 	}
 
 	cmdconfig.
 		OnCmd(cmd).
 		AddBoolFlag(constants.ArgHelp, false, "Help for service stop", cmdconfig.FlagOptions.WithShortHand("h")).
-		AddBoolFlag(constants.ArgForce, false, "Forces all services to shutdown, releasing all open connections and ports")
+		AddBoolFlag(constants.ArgForce, false, "Forces all services to shutdown, releasing all open connections and ports").
+		AddBoolFlag(constants.ArgDryRun, false, "Show the actions a forced stop would take, without stopping the service").
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatText, "Output format for the plan of actions of a forced stop: text or json")
 
 	return cmd
 }
@@ -141,11 +193,129 @@ func serviceRestartCmd() *cobra.Command {
 	cmdconfig.
 		OnCmd(cmd).
 		AddBoolFlag(constants.ArgHelp, false, "Help for service restart", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddProgressFlag("Display service startup progress").
 		AddBoolFlag(constants.ArgForce, false, "Forces the service to restart, releasing all open connections and ports")
 
 	return cmd
 }
 
+// serviceProbeCmd :: handler for service probe
+func serviceProbeCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "probe",
+		Args:  cobra.NoArgs,
+		Run:   runServiceProbeCmd,
+		Short: "Probe the health of the Steampipe service",
+		Long: `Probe the health of the Steampipe service.
+
+Checks whether the Steampipe service is live (the database and plugin manager are running)
+or, if --ready is set, whether it is ready to serve queries (the database is accepting connections
+and all connections have finished refreshing).
+
+The command exits with code 0 if the probe succeeds and a non-zero code otherwise,
+so it may be used as a liveness or readiness probe by container orchestrators.`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for service probe", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddBoolFlag(constants.ArgReady, false, "Check the service is ready to serve queries, rather than just running")
+
+	return cmd
+}
+
+// serviceReloadCmd :: handler for service reload
+func serviceReloadCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "reload",
+		Args:  cobra.NoArgs,
+		Run:   runServiceReloadCmd,
+		Short: "Reload the Steampipe service configuration",
+		Long: `Reload the Steampipe service configuration.
+
+Re-reads all connection config (.spc) files and applies any connection and option
+changes by refreshing connections. The database service is not restarted, so
+connected clients are not disconnected.
+
+Sending a SIGHUP to the plugin manager process has the same effect.`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for service reload", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+// serviceGcCmd :: handler for service gc
+func serviceGcCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "gc",
+		Args:  cobra.NoArgs,
+		Run:   runServiceGcCmd,
+		Short: "Clean up files, processes and schemas left behind by Steampipe",
+		Long: `Clean up files, processes and schemas left behind by Steampipe.
+
+Removes abandoned plugin, database and dashboard install temp directories, log files,
+service state files which refer to processes which are no longer running and, if the
+service is running and no clients are connected, temporary schemas left behind by
+disconnected sessions.
+
+Also stops the database, plugin manager and plugin processes of this installation
+which were left running by a previous run which crashed or was killed. These are also
+stopped when the service starts.
+
+The age at which log files and temp directories are removed is set by the
+log_retention_days and temp_dir_retention_hours 'general' options. Files are also
+cleaned up periodically by Steampipe commands.`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for service gc", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func serviceMaintenanceCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "maintenance [command]",
+		Args:  cobra.NoArgs,
+		Short: "Steampipe service database maintenance",
+		Long: `Steampipe service database maintenance.
+
+The service periodically vacuums and analyzes the tables of the steampipe_internal
+schema and all materialized views, reclaiming storage and keeping planner statistics
+current. The interval is set by the maintenance_interval 'database' option, in
+minutes (0 disables scheduled maintenance).`,
+	}
+
+	cmd.AddCommand(serviceMaintenanceRunCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for service maintenance")
+
+	return cmd
+}
+
+func serviceMaintenanceRunCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "run",
+		Args:  cobra.NoArgs,
+		Run:   runServiceMaintenanceRunCmd,
+		Short: "Run database maintenance now",
+		Long: `Run database maintenance now.
+
+Vacuum and analyze the internal tables and materialized views of the running
+service, and show the time taken for each.
+
+Examples:
+
+  # Run maintenance
+  steampipe service maintenance run`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for service maintenance run", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
 func runServiceStartCmd(cmd *cobra.Command, _ []string) {
 	ctx := cmd.Context()
 	utils.LogTime("runServiceStartCmd start")
@@ -162,7 +332,7 @@ func runServiceStartCmd(cmd *cobra.Command, _ []string) {
 		}
 	}()
 
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, os.Kill)
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	listenAddresses := db_local.StartListenType(viper.GetString(constants.ArgDatabaseListenAddresses)).ToListenAddresses()
@@ -261,7 +431,8 @@ func startServiceAndRefreshConnections(ctx context.Context, listenAddresses []st
 		error_helpers.FailOnError(startResult.Error)
 	}
 
-	if startResult.Status == db_local.ServiceStarted {
+	// (unless nothing has changed since the last successful refresh)
+	if startResult.Status == db_local.ServiceStarted && !startResult.SkipRefreshConnections {
 		// ask the plugin manager to refresh connections
 		// this is executed asyncronously by the plugin manager
 		// we ignore this error, since RefreshConnections is async and all errors will flow through
@@ -320,8 +491,10 @@ func startDashboardServer(ctx context.Context) (*dashboardserver.DashboardServic
 func runServiceInForeground(ctx context.Context) {
 	fmt.Println("Hit Ctrl+C to stop the service")
 
+	// a SIGTERM stops the service even if clients are connected - so the service is not left running
+	// when the foreground process is terminated (e.g. by a process supervisor)
 	sigIntChannel := make(chan os.Signal, 1)
-	signal.Notify(sigIntChannel, os.Interrupt)
+	signal.Notify(sigIntChannel, os.Interrupt, syscall.SIGTERM)
 
 	checkTimer := time.NewTicker(100 * time.Millisecond)
 	defer checkTimer.Stop()
@@ -340,7 +513,7 @@ func runServiceInForeground(ctx context.Context) {
 				fmt.Println("Steampipe service stopped.")
 				return
 			}
-		case <-sigIntChannel:
+		case sig := <-sigIntChannel:
 			fmt.Print("\r")
 			dashboardserver.StopDashboardService(ctx)
 			// if we have received this signal, then the user probably wants to shut down
@@ -353,7 +526,7 @@ func runServiceInForeground(ctx context.Context) {
 			}
 
 			// we know there will be at least 1 client (connectionWatcher)
-			if connectedClients.TotalClients > 1 {
+			if connectedClients.TotalClients > 1 && sig != syscall.SIGTERM {
 				if lastCtrlC.IsZero() || time.Since(lastCtrlC) > 30*time.Second {
 					lastCtrlC = time.Now()
 					fmt.Println(buildForegroundClientsConnectedMsg())
@@ -457,6 +630,7 @@ to force a restart.
 
 	// if the dashboard was running, start it
 	if currentDashboardState != nil {
+		currentDashboardState.RestoreServerArgs()
 		err = dashboardserver.RunForService(ctx, dashboardserver.ListenType(currentDashboardState.ListenType), dashboardserver.ListenPort(currentDashboardState.Port))
 		error_helpers.FailOnError(err)
 
@@ -478,11 +652,42 @@ func runServiceStatusCmd(cmd *cobra.Command, _ []string) {
 		}
 	}()
 
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if !helpers.StringSliceContains(serviceStatusOutputFormats, outputFormat) {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s]", outputFormat, strings.Join(serviceStatusOutputFormats, ", ")))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	if outputFormat == constants.OutputFormatJSON {
+		if viper.GetBool(constants.ArgWatch) || viper.GetBool(constants.ArgAll) {
+			error_helpers.ShowError(ctx, fmt.Errorf("%s cannot be used with %s or %s", constants.Bold("--output json"), constants.Bold("--watch"), constants.Bold("--all")))
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+		jsonOutput, err := json.MarshalIndent(servicestatus.GetHealth(ctx), "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+
 	if !db_local.IsDBInstalled() || !db_local.IsFDWInstalled() {
 		fmt.Println("Steampipe service is not installed.")
 		return
 	}
 
+	if viper.GetBool(constants.ArgWatch) {
+		if viper.GetBool(constants.ArgAll) {
+			error_helpers.ShowError(ctx, fmt.Errorf("%s cannot be used with %s", constants.Bold("--watch"), constants.Bold("--all")))
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+		// setup a cancel context and start cancel handler - the watch runs until Ctrl+C
+		ctx, cancel := context.WithCancel(ctx)
+		contexthelpers.StartCancelHandler(cancel)
+		servicestatus.Watch(ctx, servicestatus.WatchInterval)
+		return
+	}
+
 	if viper.GetBool(constants.ArgAll) {
 		showAllStatus(ctx)
 	} else {
@@ -498,6 +703,64 @@ func runServiceStatusCmd(cmd *cobra.Command, _ []string) {
 	}
 }
 
+func runServiceReloadCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceReloadCmd start")
+	defer func() {
+		utils.LogTime("runServiceReloadCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			if exitCode == constants.ExitCodeSuccessful {
+				exitCode = constants.ExitCodeServiceReloadFailure
+			}
+		}
+	}()
+
+	dbState, err := db_local.GetState()
+	error_helpers.FailOnErrorWithMessage(err, "could not reload service")
+	if dbState == nil {
+		fmt.Println("Steampipe service is not running.")
+		return
+	}
+
+	err = pluginmanager.Reload()
+	error_helpers.FailOnErrorWithMessage(err, "could not reload service")
+
+	fmt.Println("Steampipe service configuration reloaded. Connections are being refreshed.")
+}
+
+func runServiceProbeCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceProbeCmd start")
+	defer func() {
+		utils.LogTime("runServiceProbeCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeServiceProbeFailed
+		}
+	}()
+
+	readiness := viper.GetBool(constants.ArgReady)
+	probeResult := db_local.ProbeService(ctx, readiness)
+
+	probeType := "liveness"
+	passed := probeResult.Live()
+	if readiness {
+		probeType = "readiness"
+		passed = probeResult.Ready()
+	}
+
+	if !passed {
+		exitCode = constants.ExitCodeServiceProbeFailed
+		fmt.Printf("Steampipe service %s probe failed:\n", probeType)
+		for _, failure := range probeResult.Failures {
+			fmt.Printf("  %s\n", failure)
+		}
+		return
+	}
+	fmt.Printf("Steampipe service %s probe succeeded.\n", probeType)
+}
+
 func composeStateError(dbStateErr error, pmStateErr error, dashboardStateErr error) error {
 	msg := "could not get Steampipe service status:"
 
@@ -539,6 +802,20 @@ func runServiceStopCmd(cmd *cobra.Command, _ []string) {
 	}()
 
 	force := cmdconfig.Viper().GetBool(constants.ArgForce)
+	if force || viper.GetBool(constants.ArgDryRun) {
+		if !validateActionPlanOutputFormat(ctx) {
+			return
+		}
+		plan := getForceStopPlan(ctx)
+		// --force is the confirmation of the plan
+		error_helpers.FailOnError(plan.Show(viper.GetString(constants.ArgOutput)))
+		if viper.GetBool(constants.ArgDryRun) {
+			return
+		}
+	}
+	// the json output of a forced stop is the plan only
+	showStatus := !force || viper.GetString(constants.ArgOutput) != constants.OutputFormatJSON
+
 	if force {
 		dashboardStopError := dashboardserver.StopDashboardService(ctx)
 		status, dbStopError = db_local.StopServices(ctx, force, constants.InvokerService)
@@ -597,6 +874,9 @@ func runServiceStopCmd(cmd *cobra.Command, _ []string) {
 		}
 	}
 
+	if !showStatus {
+		return
+	}
 	switch status {
 	case db_local.ServiceStopped:
 		fmt.Println("Steampipe database service stopped.")
@@ -619,6 +899,21 @@ to force a shutdown.
 	}
 }
 
+// getForceStopPlan returns the plan of the actions taken by 'service stop --force'
+// planning is best-effort - a forced stop is used when the service is broken, so it must not depend on the service state
+func getForceStopPlan(ctx context.Context) *actionplan.Plan {
+	plan := actionplan.New("steampipe service stop --force")
+	dashboardState, err := dashboardserver.GetDashboardServiceState()
+	if err != nil {
+		log.Printf("[WARN] failed to load the dashboard server state: %s", err.Error())
+	}
+	if dashboardState != nil {
+		plan.Add(actionplan.ActionStopProcess, strconv.Itoa(dashboardState.Pid), "dashboard server")
+	}
+	db_local.PlanForceStop(ctx, plan)
+	return plan
+}
+
 func showAllStatus(ctx context.Context) {
 	var processes []*psutils.Process
 	var err error
@@ -689,6 +984,9 @@ Managing the Steampipe service:
   # View database password for connecting from another machine
   steampipe service status --show-password
 
+  # Watch the service status, active queries and plugin memory usage
+  steampipe service status --watch
+
   # Restart the service
   steampipe service restart
 
@@ -821,3 +1119,91 @@ Not shutting down service as there as clients connected.
 To force shutdown, press Ctrl+C again.
 	`
 }
+
+func runServiceGcCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceGcCmd start")
+	defer func() {
+		utils.LogTime("runServiceGcCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeServiceGcFailure
+		}
+	}()
+
+	// an explicit gc also removes stale service state files
+	policy := gc.PolicyFromConfig()
+	policy.StateFiles = true
+	res := gc.CleanupFiles(ctx, policy)
+	for _, err := range res.Errors {
+		error_helpers.ShowWarning(err.Error())
+	}
+	fmt.Printf("Removed %d temp %s, %d log %s and %d stale state %s.\n",
+		len(res.TempDirs), utils.Pluralize("directory", len(res.TempDirs)),
+		len(res.LogFiles), utils.Pluralize("file", len(res.LogFiles)),
+		len(res.StateFiles), utils.Pluralize("file", len(res.StateFiles)))
+
+	reaped, err := db_local.ReapOrphanedProcesses(ctx)
+	error_helpers.FailOnErrorWithMessage(err, "could not stop orphaned processes")
+	fmt.Printf("Stopped %d orphaned %s.\n", len(reaped), utils.Pluralize("process", len(reaped)))
+
+	dbState, err := db_local.GetState()
+	error_helpers.FailOnErrorWithMessage(err, "could not clean up temporary schemas")
+	if dbState == nil {
+		fmt.Println("Steampipe service is not running - skipped temporary schema cleanup.")
+		return
+	}
+
+	dropped, skipReason, err := db_local.DropOrphanedTempSchemas(ctx)
+	error_helpers.FailOnErrorWithMessage(err, "could not clean up temporary schemas")
+	if skipReason != "" {
+		fmt.Printf("Skipped temporary schema cleanup: %s.\n", skipReason)
+		return
+	}
+	fmt.Printf("Removed %d temporary %s.\n", len(dropped), utils.Pluralize("schema", len(dropped)))
+}
+
+func runServiceMaintenanceRunCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceMaintenanceRunCmd start")
+	defer func() {
+		utils.LogTime("runServiceMaintenanceRunCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeServiceMaintenanceFailure
+		}
+	}()
+
+	dbState, err := db_local.GetState()
+	error_helpers.FailOnErrorWithMessage(err, "could not run maintenance")
+	if dbState == nil {
+		error_helpers.ShowError(ctx, sperr.New("the Steampipe service is not running"))
+		exitCode = constants.ExitCodeServiceMaintenanceFailure
+		return
+	}
+
+	conn, err := db_local.CreateLocalDbConnection(ctx, &db_local.CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, error_helpers.NewConnectionError(error_helpers.ErrorCodeDatabaseConnectionFailed, err), "failed to connect to the service")
+		exitCode = constants.ExitCodeDatabaseConnectionFailed
+		return
+	}
+	defer conn.Close(ctx)
+
+	statushooks.SetStatus(ctx, "Running maintenance")
+	res, err := db_local.RunMaintenance(ctx, conn)
+	statushooks.Done(ctx)
+	for _, r := range res {
+		if r.Error != nil {
+			error_helpers.ShowWarning(fmt.Sprintf("failed to vacuum %s: %s", r.Table, r.Error.Error()))
+			continue
+		}
+		fmt.Printf("Vacuumed %s (%s)\n", r.Table, r.Duration.Round(time.Millisecond))
+	}
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "maintenance failed")
+		exitCode = constants.ExitCodeServiceMaintenanceFailure
+	} else if res.Error() != nil {
+		exitCode = constants.ExitCodeServiceMaintenanceFailure
+	}
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/utils"
+	"golang.org/x/term"
+)
+
+var serviceUserListOutputFormats = []string{constants.OutputFormatTable, constants.OutputFormatJSON}
+
+func serviceUserCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "user [command]",
+		Args:  cobra.NoArgs,
+		Short: "Manage service database users",
+		Long: `Manage service database users.
+
+Service users may connect to the Steampipe service with their own password, and
+may only read from the schemas of the connections they are given access to. This
+allows a service to be shared without sharing the password of the steampipe user.
+
+Access is retained when connections are refreshed. The service must be running.`,
+	}
+
+	cmd.AddCommand(serviceUserAddCmd())
+	cmd.AddCommand(serviceUserRemoveCmd())
+	cmd.AddCommand(serviceUserListCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for service user")
+
+	return cmd
+}
+
+func serviceUserAddCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "add <name>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runServiceUserAddCmd,
+		Short: "Add a service user",
+		Long: `Add a service user with read only access to the given connections.
+
+The password may be read from stdin (--password-stdin) or from the
+STEAMPIPE_SERVICE_USER_PASSWORD env var - otherwise one is generated. A
+generated password is shown once, and cannot be retrieved later.
+
+Examples:
+
+  # Add a user with access to two connections
+  steampipe service user add analyst --connection aws_prod --connection aws_dev
+
+  # Add a user with a password read from a file
+  steampipe service user add analyst --connection aws_prod --password-stdin < password.txt`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringSliceFlag(constants.ArgConnection, nil, "The connection(s) the user may read from (comma-separated)").
+		AddBoolFlag(constants.ArgPasswordStdin, false, "Read the password of the user from stdin - if not given, a password is generated").
+		AddBoolFlag(constants.ArgHelp, false, "Help for service user add", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func serviceUserRemoveCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "remove <name>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runServiceUserRemoveCmd,
+		Short: "Remove a service user",
+		Long: `Remove a service user, and all the privileges granted to it.
+
+Examples:
+
+  # Remove a user
+  steampipe service user remove analyst`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for service user remove", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func serviceUserListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Run:   runServiceUserListCmd,
+		Short: "List the service users",
+		Long: `List the service users and the connections they may read from.
+
+Examples:
+
+  # List the users
+  steampipe service user list
+
+  # List the users as json
+  steampipe service user list --output json`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, fmt.Sprintf("Output format: %s", strings.Join(serviceUserListOutputFormats, ", "))).
+		AddBoolFlag(constants.ArgHelp, false, "Help for service user list", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runServiceUserAddCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceUserAddCmd start")
+	defer func() {
+		utils.LogTime("runServiceUserAddCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeServiceUserFailure
+		}
+	}()
+
+	name := args[0]
+	connections := helpers.RemoveFromStringSlice(viper.GetStringSlice(constants.ArgConnection), "")
+	if len(connections) == 0 {
+		error_helpers.ShowError(ctx, sperr.New("at least one connection must be given with --%s", constants.ArgConnection))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	if err := db_local.ValidateServiceUserName(name); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	password, err := getServiceUserPassword()
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	dbState, conn, ok := getServiceUserDbConnection(ctx)
+	if !ok {
+		return
+	}
+	defer conn.Close(ctx)
+
+	addedPassword, err := db_local.AddServiceUser(ctx, conn, name, password, connections)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to add service user")
+		exitCode = constants.ExitCodeServiceUserFailure
+		return
+	}
+
+	fmt.Printf("Added service user %s with access to %s.\n", name, strings.Join(connections, ", "))
+	fmt.Println()
+	connectionString := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(name, addedPassword),
+		Host:   fmt.Sprintf("localhost:%d", dbState.Port),
+		Path:   "/" + dbState.Database,
+	}
+	// a given password is already known, so is not written to the terminal
+	if password != "" {
+		connectionString.User = url.User(name)
+		fmt.Printf("  Connection string: %s\n", connectionString.String())
+		return
+	}
+	fmt.Printf("  Password: %s\n", addedPassword)
+	fmt.Printf("  Connection string: %s\n", connectionString.String())
+	fmt.Println()
+	fmt.Println("The password cannot be retrieved later - keep it somewhere safe.")
+}
+
+// getServiceUserPassword returns the password given for a service user, or an empty string if a password should be generated
+// the password is read from stdin if --password-stdin is set (prompting for it if stdin is a terminal),
+// otherwise from the STEAMPIPE_SERVICE_USER_PASSWORD env var
+// (the password is never accepted as an arg, as it would be visible in the shell history and process list)
+func getServiceUserPassword() (string, error) {
+	if !viper.GetBool(constants.ArgPasswordStdin) {
+		return os.Getenv(constants.EnvServiceUserPassword), nil
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Print("Password: ")
+		password, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", sperr.WrapWithMessage(err, "failed to read password")
+		}
+		return validateServiceUserPassword(string(password))
+	}
+	password, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to read password from stdin")
+	}
+	return validateServiceUserPassword(strings.TrimRight(string(password), "\r\n"))
+}
+
+func validateServiceUserPassword(password string) (string, error) {
+	if password == "" {
+		return "", sperr.New("the password read from stdin is empty")
+	}
+	return password, nil
+}
+
+func runServiceUserRemoveCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceUserRemoveCmd start")
+	defer func() {
+		utils.LogTime("runServiceUserRemoveCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeServiceUserFailure
+		}
+	}()
+
+	_, conn, ok := getServiceUserDbConnection(ctx)
+	if !ok {
+		return
+	}
+	defer conn.Close(ctx)
+
+	if err := db_local.RemoveServiceUser(ctx, conn, args[0]); err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to remove service user")
+		exitCode = constants.ExitCodeServiceUserFailure
+		return
+	}
+	fmt.Printf("Removed service user %s.\n", args[0])
+}
+
+func runServiceUserListCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runServiceUserListCmd start")
+	defer func() {
+		utils.LogTime("runServiceUserListCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeServiceUserFailure
+		}
+	}()
+
+	outputFormat := viper.GetString(constants.ArgOutput)
+	if !helpers.StringSliceContains(serviceUserListOutputFormats, outputFormat) {
+		error_helpers.ShowError(ctx, sperr.New("invalid output format: '%s', must be one of [%s]", outputFormat, strings.Join(serviceUserListOutputFormats, ", ")))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+
+	_, conn, ok := getServiceUserDbConnection(ctx)
+	if !ok {
+		return
+	}
+	defer conn.Close(ctx)
+
+	users, err := db_local.ListServiceUsers(ctx, conn)
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to list service users")
+		exitCode = constants.ExitCodeServiceUserFailure
+		return
+	}
+
+	if outputFormat == constants.OutputFormatJSON {
+		// output an empty array rather than null if there are no users
+		if users == nil {
+			users = []*db_local.ServiceUser{}
+		}
+		jsonOutput, err := json.MarshalIndent(users, "", "  ")
+		error_helpers.FailOnError(err)
+		fmt.Println(string(jsonOutput))
+		return
+	}
+	var rows [][]string
+	for _, user := range users {
+		rows = append(rows, []string{user.Name, strings.Join(user.Connections, ", "), user.CreatedAt.Local().Format("2006-01-02 15:04:05")})
+	}
+	display.ShowWrappedTable([]string{"Name", "Connections", "Created"}, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+}
+
+// getServiceUserDbConnection returns a superuser connection to the running service
+// if the service is not running or the connection fails, the error is shown, the exit code set and false returned
+func getServiceUserDbConnection(ctx context.Context) (*db_local.RunningDBInstanceInfo, *pgx.Conn, bool) {
+	dbState, err := db_local.GetState()
+	error_helpers.FailOnErrorWithMessage(err, "could not get the service state")
+	if dbState == nil {
+		error_helpers.ShowError(ctx, sperr.New("the Steampipe service is not running"))
+		exitCode = constants.ExitCodeServiceProbeFailed
+		return nil, nil, false
+	}
+
+	conn, err := db_local.CreateLocalDbConnection(ctx, &db_local.CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, error_helpers.NewConnectionError(error_helpers.ErrorCodeDatabaseConnectionFailed, err), "failed to connect to the service")
+		exitCode = constants.ExitCodeDatabaseConnectionFailed
+		return nil, nil, false
+	}
+	return dbState, conn, true
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/task"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// Telemetry commands
+func telemetryCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "telemetry [command]",
+		Args:  cobra.NoArgs,
+		Short: "View the usage data sent by Steampipe",
+		Long: `View the usage data sent by Steampipe.
+
+When the daily update check runs, Steampipe sends the CLI version, the platform,
+an anonymous installation id and the versions of installed Turbot plugins to the
+Steampipe hub. Use these commands to view exactly what is sent, or to record it
+locally for your own analysis (this works even if update checks are disabled).
+
+Fields may be removed from the data which is sent using the 'telemetry_redact'
+general option (or STEAMPIPE_TELEMETRY_REDACT), e.g. "signature,os_platform".
+
+Examples:
+
+  # Show the usage data which would be sent
+  steampipe telemetry show
+
+  # Append the usage data to a local file
+  steampipe telemetry export ~/usage.jsonl`,
+	}
+
+	cmd.AddCommand(telemetryShowCmd())
+	cmd.AddCommand(telemetryExportCmd())
+	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for telemetry")
+
+	return cmd
+}
+
+func telemetryShowCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "show",
+		Args:  cobra.NoArgs,
+		Run:   runTelemetryShowCmd,
+		Short: "Show the usage data which would be sent",
+		Long: `Show the usage data which would be sent.
+
+Displays the url and payload of each request the update check sends,
+with any redacted fields removed.`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for telemetry show", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func telemetryExportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export [file]",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   runTelemetryExportCmd,
+		Short: "Record the usage data locally",
+		Long: `Record the usage data locally.
+
+Appends the usage data, as a single line of JSON, to the given file (or the usage
+report file in the Steampipe internal directory if no file is given).
+
+To record the usage data automatically each time the update check runs, set the
+'telemetry_export' general option (or STEAMPIPE_TELEMETRY_EXPORT).`,
+	}
+
+	cmdconfig.OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for telemetry export", cmdconfig.FlagOptions.WithShortHand("h"))
+
+	return cmd
+}
+
+func runTelemetryShowCmd(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runTelemetryShowCmd start")
+	defer func() {
+		utils.LogTime("runTelemetryShowCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	report, err := task.BuildUsageReport()
+	error_helpers.FailOnError(err)
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	error_helpers.FailOnError(err)
+	fmt.Println(string(jsonBytes))
+}
+
+func runTelemetryExportCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	utils.LogTime("runTelemetryExportCmd start")
+	defer func() {
+		utils.LogTime("runTelemetryExportCmd end")
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	path := filepaths.UsageReportFilePath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	report, err := task.BuildUsageReport()
+	error_helpers.FailOnError(err)
+
+	if err := task.ExportUsageReport(report, path); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeFileSystemAccessFailure
+		return
+	}
+	fmt.Printf("Exported usage report to %s\n", path)
+}
@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -10,6 +13,7 @@ import (
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/inputvars"
 	"github.com/turbot/steampipe/pkg/workspace"
 )
 
@@ -23,6 +27,9 @@ func variableCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(variableListCmd())
+	cmd.AddCommand(variableSetCmd())
+	cmd.AddCommand(variableGetCmd())
+	cmd.AddCommand(variableUnsetCmd())
 	cmd.Flags().BoolP(constants.ArgHelp, "h", false, "Help for variable")
 
 	cmdconfig.
@@ -56,6 +63,8 @@ Example:
 		AddBoolFlag("outdated", false, "Check each variable in the list for updates").
 		AddBoolFlag(constants.ArgHelp, false, "Help for variable list", cmdconfig.FlagOptions.WithShortHand("h")).
 		AddModLocationFlag().
+		AddVariablesWorkspaceFlag().
+		AddCloudFlags().
 		AddStringFlag(constants.ArgOutput, constants.OutputFormatTable, "Select a console output format: table or json")
 
 	return cmd
@@ -90,3 +99,202 @@ func runVariableListCmd(cmd *cobra.Command, _ []string) {
 		display.ShowVarsListTable(vars)
 	}
 }
+
+// Set the stored value of a variable
+func variableSetCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "set [flags] <name> [value]",
+		Args:  cobra.RangeArgs(1, 2),
+		Run:   runVariableSetCmd,
+		Short: "Store the value of a variable for the workspace",
+		Long: `Store the value of a variable for the workspace.
+
+Stored values are applied automatically when the workspace variables are loaded, e.g.
+by check and dashboard, so stable values do not need to be passed with --var. Values
+passed with --var, --var-file, SP_VAR_ environment variables or the workspace
+steampipe.spvars file take precedence over stored values.
+
+The value is parsed in the same way as a --var value. If no value is given, it is read
+from stdin. Secret values are encrypted using the config encryption key (see 'steampipe config').
+
+Examples:
+
+  # Store the organization id used by the workspace mod
+  steampipe variable set org_id o-a1b2c3d4e5
+
+  # Store a secret value, read from stdin
+  cat token.txt | steampipe variable set --secret api_token`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddBoolFlag(constants.ArgSecret, false, "Encrypt the stored value").
+		AddBoolFlag(constants.ArgHelp, false, "Help for variable set", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddModLocationFlag()
+
+	return cmd
+}
+
+// Get the stored value of a variable
+func variableGetCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "get [flags] [name]",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   runVariableGetCmd,
+		Short: "Show the stored value of a variable for the workspace",
+		Long: `Show the stored value of a variable for the workspace.
+
+If no name is given, the names of all variables stored for the workspace are listed.
+
+Examples:
+
+  # Show the stored value of the 'org_id' variable
+  steampipe variable get org_id
+
+  # List the variables stored for the workspace
+  steampipe variable get`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for variable get", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddModLocationFlag()
+
+	return cmd
+}
+
+// Remove the stored value of a variable
+func variableUnsetCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "unset [flags] <name>",
+		Args:  cobra.ExactArgs(1),
+		Run:   runVariableUnsetCmd,
+		Short: "Remove the stored value of a variable for the workspace",
+		Long: `Remove the stored value of a variable for the workspace.
+
+Example:
+
+  # Remove the stored value of the 'org_id' variable
+  steampipe variable unset org_id`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddBoolFlag(constants.ArgHelp, false, "Help for variable unset", cmdconfig.FlagOptions.WithShortHand("h")).
+		AddModLocationFlag()
+
+	return cmd
+}
+
+func runVariableSetCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	defer func() {
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	name := args[0]
+	var value string
+	if len(args) == 2 {
+		value = args[1]
+	} else {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			error_helpers.ShowError(ctx, err)
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			return
+		}
+		value = strings.TrimRight(string(content), "\r\n")
+	}
+
+	store, err := inputvars.LoadVariableStore()
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeFileSystemAccessFailure
+		return
+	}
+	workspacePath := viper.GetString(constants.ArgModLocation)
+	if err := store.Set(workspacePath, name, value, viper.GetBool(constants.ArgSecret)); err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeConfigEncryptionFailed
+		return
+	}
+	if err := store.Save(); err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to save variable store")
+		exitCode = constants.ExitCodeFileSystemAccessFailure
+		return
+	}
+	fmt.Printf("Stored value of variable '%s' for workspace %s\n", name, workspacePath)
+}
+
+func runVariableGetCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	defer func() {
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	store, err := inputvars.LoadVariableStore()
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeFileSystemAccessFailure
+		return
+	}
+	workspacePath := viper.GetString(constants.ArgModLocation)
+
+	// if no name is given, list the stored variables
+	if len(args) == 0 {
+		for _, name := range store.Names(workspacePath) {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	name := args[0]
+	value, ok, err := store.Get(workspacePath, name)
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeConfigEncryptionFailed
+		return
+	}
+	if !ok {
+		error_helpers.ShowError(ctx, fmt.Errorf("no value is stored for variable '%s' for workspace %s", name, workspacePath))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	fmt.Println(value)
+}
+
+func runVariableUnsetCmd(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	defer func() {
+		if r := recover(); r != nil {
+			error_helpers.ShowError(ctx, helpers.ToError(r))
+			exitCode = constants.ExitCodeUnknownErrorPanic
+		}
+	}()
+
+	store, err := inputvars.LoadVariableStore()
+	if err != nil {
+		error_helpers.ShowError(ctx, err)
+		exitCode = constants.ExitCodeFileSystemAccessFailure
+		return
+	}
+	name := args[0]
+	workspacePath := viper.GetString(constants.ArgModLocation)
+	if !store.Unset(workspacePath, name) {
+		error_helpers.ShowError(ctx, fmt.Errorf("no value is stored for variable '%s' for workspace %s", name, workspacePath))
+		exitCode = constants.ExitCodeInsufficientOrWrongInputs
+		return
+	}
+	if err := store.Save(); err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "failed to save variable store")
+		exitCode = constants.ExitCodeFileSystemAccessFailure
+		return
+	}
+	fmt.Printf("Removed stored value of variable '%s' for workspace %s\n", name, workspacePath)
+}
@@ -13,6 +13,7 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe/cmd"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -27,12 +28,12 @@ func main() {
 		if r := recover(); r != nil {
 			error_helpers.ShowError(ctx, helpers.ToError(r))
 			if exitCode == 0 {
-				exitCode = constants.ExitCodeUnknownErrorPanic
+				exitCode = error_helpers.ExitCode(helpers.ToError(r), constants.ExitCodeUnknownErrorPanic)
 			}
 		}
 		utils.LogTime("main end")
 		utils.DisplayProfileData(os.Stdout)
-		os.Exit(exitCode)
+		os.Exit(cmdconfig.MapExitCode(exitCode))
 	}()
 
 	// ensure steampipe is not being run as root
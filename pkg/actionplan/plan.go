@@ -0,0 +1,116 @@
+package actionplan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the types of action a plan may contain
+const (
+	ActionDeletePath       = "delete_path"
+	ActionUpdateFile       = "update_file"
+	ActionDropSchema       = "drop_schema"
+	ActionTerminateSession = "terminate_session"
+	ActionStopProcess      = "stop_process"
+)
+
+var actionLabels = map[string]string{
+	ActionDeletePath:       "Delete",
+	ActionUpdateFile:       "Update",
+	ActionDropSchema:       "Drop schema",
+	ActionTerminateSession: "Terminate session",
+	ActionStopProcess:      "Stop process",
+}
+
+// Action is a single destructive action of a plan
+type Action struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Plan is the list of destructive actions a command will take
+// the plan is shown (or output as json, for tooling) before the command runs, so the actions may be confirmed
+type Plan struct {
+	Command string    `json:"command"`
+	Actions []*Action `json:"actions"`
+}
+
+func New(command string) *Plan {
+	return &Plan{
+		Command: command,
+		Actions: []*Action{},
+	}
+}
+
+// Add adds an action to the plan - the reason is optional
+func (p *Plan) Add(actionType, target, reason string) {
+	p.Actions = append(p.Actions, &Action{Type: actionType, Target: target, Reason: reason})
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+func (p *Plan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("%s has nothing to do.\n", p.Command)
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s will take the following %d %s:\n\n", p.Command, len(p.Actions), utils.Pluralize("action", len(p.Actions))))
+	for _, a := range p.Actions {
+		b.WriteString(fmt.Sprintf("  - %s %s", actionLabels[a.Type], a.Target))
+		if a.Reason != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", a.Reason))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Show outputs the plan in the given output format (text or json)
+func (p *Plan) Show(outputFormat string) error {
+	if outputFormat == constants.OutputFormatJSON {
+		jsonOutput, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+	fmt.Println(p.String())
+	return nil
+}
+
+// Confirm shows the plan in the given output format and returns whether the actions should be taken
+//   - if force is set, the actions are taken without asking for confirmation
+//   - json output is for tooling, so is never interactive - the actions are only taken if force is set
+//   - otherwise the user is asked to confirm the actions, unless stdin is not a terminal (e.g. in scripts), in which case
+//     the actions are taken without confirmation, as they were before plans were shown
+//
+// an empty plan is never confirmed
+func (p *Plan) Confirm(ctx context.Context, outputFormat string, force bool) (bool, error) {
+	if err := p.Show(outputFormat); err != nil {
+		return false, err
+	}
+	if p.Empty() {
+		return false, nil
+	}
+	if force {
+		return true, nil
+	}
+	if outputFormat == constants.OutputFormatJSON {
+		return false, nil
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return true, nil
+	}
+	return utils.UserConfirmation(ctx, "Do you want to continue? (y/n)")
+}
@@ -0,0 +1,61 @@
+package actionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestPlanString(t *testing.T) {
+	plan := New("steampipe plugin uninstall")
+	if expected := "steampipe plugin uninstall has nothing to do.\n"; plan.String() != expected {
+		t.Errorf("expected %q, got %q", expected, plan.String())
+	}
+
+	plan.Add(ActionDeletePath, "/plugins/aws@latest", "")
+	plan.Add(ActionDropSchema, "aws_prod", "connection uses the plugin")
+	expected := `steampipe plugin uninstall will take the following 2 actions:
+
+  - Delete /plugins/aws@latest
+  - Drop schema aws_prod (connection uses the plugin)
+`
+	if plan.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, plan.String())
+	}
+}
+
+func TestPlanConfirmJSON(t *testing.T) {
+	plan := New("steampipe mod uninstall")
+	plan.Add(ActionUpdateFile, "mod.sp", "")
+
+	// json output is never interactive - it only confirms if forced
+	for force, expected := range map[bool]bool{true: true, false: false} {
+		confirmed, err := plan.Confirm(context.Background(), constants.OutputFormatJSON, force)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if confirmed != expected {
+			t.Errorf("force=%v: expected confirmed=%v, got %v", force, expected, confirmed)
+		}
+	}
+
+	// an empty plan is never confirmed
+	if confirmed, _ := New("steampipe mod uninstall").Confirm(context.Background(), constants.OutputFormatJSON, true); confirmed {
+		t.Errorf("expected an empty plan not to be confirmed")
+	}
+}
+
+func TestPlanConfirmNonInteractive(t *testing.T) {
+	// stdin is not a terminal when running tests - text output must not require confirmation
+	// (so scripts which ran these commands before plans were shown continue to work)
+	plan := New("steampipe plugin uninstall")
+	plan.Add(ActionDeletePath, "/plugins/aws@latest", "")
+	confirmed, err := plan.Confirm(context.Background(), constants.OutputFormatText, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !confirmed {
+		t.Errorf("expected the plan to be confirmed when stdin is not a terminal")
+	}
+}
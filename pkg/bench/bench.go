@@ -0,0 +1,281 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/version"
+	"golang.org/x/sync/errgroup"
+)
+
+// Options controls the size of the benchmark suite
+type Options struct {
+	// number of times each benchmark is run
+	Iterations int `json:"iterations"`
+	// number of rows returned by the large result benchmark
+	Rows int `json:"rows"`
+	// number of concurrent queries executed by the parallel benchmark
+	Parallelism int `json:"parallelism"`
+	// optional plugin table used for the cache and parallel scan benchmarks
+	Table string `json:"table,omitempty"`
+}
+
+// Result is the result of a single benchmark
+type Result struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Iterations  int      `json:"iterations"`
+	Timings     *Timings `json:"timings,omitempty"`
+	// rows returned per iteration (if relevant)
+	Rows    int64  `json:"rows,omitempty"`
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Report is the result of running the benchmark suite
+type Report struct {
+	SteampipeVersion string    `json:"steampipe_version"`
+	OS               string    `json:"os"`
+	Arch             string    `json:"arch"`
+	NumCPU           int       `json:"num_cpu"`
+	StartTime        time.Time `json:"start_time"`
+	DurationMs       float64   `json:"duration_ms"`
+	Options          Options   `json:"options"`
+	Results          []*Result `json:"results"`
+}
+
+type benchmark struct {
+	name        string
+	description string
+	// if this returns a non-empty string, the benchmark is skipped with this reason
+	skip func(opts Options) string
+	// setup is called once before the iterations are run
+	setup func(ctx context.Context, client db_common.Client, opts Options) error
+	// run executes a single iteration and returns the number of rows returned
+	run func(ctx context.Context, client db_common.Client, opts Options) (int64, error)
+}
+
+// Run executes the benchmark suite using the given client
+func Run(ctx context.Context, client db_common.Client, opts Options) *Report {
+	report := &Report{
+		SteampipeVersion: version.SteampipeVersion.String(),
+		OS:               runtime.GOOS,
+		Arch:             runtime.GOARCH,
+		NumCPU:           runtime.NumCPU(),
+		StartTime:        time.Now(),
+		Options:          opts,
+	}
+
+	for _, b := range benchmarks() {
+		if ctx.Err() != nil {
+			break
+		}
+		statushooks.SetStatus(ctx, fmt.Sprintf("Running benchmark: %s", b.name))
+		report.Results = append(report.Results, runBenchmark(ctx, client, b, opts))
+	}
+	statushooks.Done(ctx)
+
+	report.DurationMs = toMs(time.Since(report.StartTime))
+	return report
+}
+
+func runBenchmark(ctx context.Context, client db_common.Client, b *benchmark, opts Options) *Result {
+	log.Printf("[INFO] running benchmark %s", b.name)
+	res := &Result{
+		Name:        b.name,
+		Description: b.description,
+	}
+	if b.skip != nil {
+		if reason := b.skip(opts); reason != "" {
+			res.Skipped = reason
+			return res
+		}
+	}
+	if b.setup != nil {
+		if err := b.setup(ctx, client, opts); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+	}
+
+	durations := make([]time.Duration, 0, opts.Iterations)
+	for i := 0; i < opts.Iterations; i++ {
+		start := time.Now()
+		rows, err := b.run(ctx, client, opts)
+		if err != nil {
+			res.Error = err.Error()
+			break
+		}
+		durations = append(durations, time.Since(start))
+		res.Rows = rows
+	}
+	res.Iterations = len(durations)
+	if len(durations) > 0 {
+		timings := newTimings(durations)
+		res.Timings = &timings
+	}
+	return res
+}
+
+func benchmarks() []*benchmark {
+	requiresTable := func(opts Options) string {
+		if opts.Table == "" {
+			return "no table specified"
+		}
+		return ""
+	}
+
+	return []*benchmark{
+		{
+			name:        "schema_load",
+			description: "Load the schema metadata for all connections",
+			run: func(ctx context.Context, client db_common.Client, _ Options) (int64, error) {
+				schema, err := client.GetSchemaFromDB(ctx)
+				if err != nil {
+					return 0, err
+				}
+				return int64(len(schema.Schemas)), nil
+			},
+		},
+		{
+			name:        "round_trip",
+			description: "Execute a trivial query",
+			run: func(ctx context.Context, client db_common.Client, _ Options) (int64, error) {
+				return executeQuery(ctx, client, "select 1")
+			},
+		},
+		{
+			name:        "large_result",
+			description: "Stream a large result set",
+			run: func(ctx context.Context, client db_common.Client, opts Options) (int64, error) {
+				return executeQuery(ctx, client, largeResultQuery(opts.Rows))
+			},
+		},
+		{
+			name:        "parallel_query",
+			description: "Stream large result sets from concurrent queries",
+			run: func(ctx context.Context, client db_common.Client, opts Options) (int64, error) {
+				return executeParallel(ctx, client, largeResultQuery(opts.Rows), opts.Parallelism)
+			},
+		},
+		{
+			name:        "cache_miss",
+			description: "Scan a plugin table with the query cache cleared",
+			skip:        requiresTable,
+			run: func(ctx context.Context, client db_common.Client, opts Options) (int64, error) {
+				return executeUncached(ctx, client, tableQuery(opts.Table))
+			},
+		},
+		{
+			name:        "cache_hit",
+			description: "Scan a plugin table which is cached",
+			skip:        requiresTable,
+			setup: func(ctx context.Context, client db_common.Client, opts Options) error {
+				// populate the cache
+				_, err := executeQuery(ctx, client, tableQuery(opts.Table))
+				return err
+			},
+			run: func(ctx context.Context, client db_common.Client, opts Options) (int64, error) {
+				return executeQuery(ctx, client, tableQuery(opts.Table))
+			},
+		},
+		{
+			name:        "parallel_scan",
+			description: "Scan a plugin table from concurrent queries with the query cache cleared",
+			skip:        requiresTable,
+			run: func(ctx context.Context, client db_common.Client, opts Options) (int64, error) {
+				if err := clearCache(ctx, client); err != nil {
+					return 0, err
+				}
+				return executeParallel(ctx, client, tableQuery(opts.Table), opts.Parallelism)
+			},
+		},
+	}
+}
+
+func largeResultQuery(rows int) string {
+	return fmt.Sprintf("select i, md5(i::text) as hash, now() as ts from generate_series(1, %d) as i", rows)
+}
+
+func tableQuery(table string) string {
+	return fmt.Sprintf("select * from %s", table)
+}
+
+// executeQuery executes the query, draining the result stream, and returns the number of rows
+func executeQuery(ctx context.Context, client db_common.Client, query string) (int64, error) {
+	result, err := client.Execute(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return drainResult(result.RowChan)
+}
+
+// drainResult reads all rows from the row channel, returning the row count and the first error
+// NOTE: the channel is always drained so the query execution does not block
+func drainResult(rowChan *chan *queryresult.RowResult) (int64, error) {
+	var rows int64
+	var err error
+	for row := range *rowChan {
+		if row.Error != nil {
+			if err == nil {
+				err = row.Error
+			}
+			continue
+		}
+		rows++
+	}
+	return rows, err
+}
+
+// executeUncached clears the query cache and executes the query in the same session
+func executeUncached(ctx context.Context, client db_common.Client, query string) (int64, error) {
+	sessionResult := client.AcquireSession(ctx)
+	if sessionResult.Error != nil {
+		return 0, sessionResult.Error
+	}
+	session := sessionResult.Session
+	defer session.Close(false)
+
+	if err := db_common.CacheClear(ctx, session.Connection.Conn()); err != nil {
+		return 0, err
+	}
+	result, err := client.ExecuteInSession(ctx, session, nil, query)
+	if err != nil {
+		return 0, err
+	}
+	return drainResult(result.RowChan)
+}
+
+func clearCache(ctx context.Context, client db_common.Client) error {
+	sessionResult := client.AcquireSession(ctx)
+	if sessionResult.Error != nil {
+		return sessionResult.Error
+	}
+	defer sessionResult.Session.Close(false)
+	return db_common.CacheClear(ctx, sessionResult.Session.Connection.Conn())
+}
+
+// executeParallel executes the query parallelism times concurrently and returns the total number of rows
+func executeParallel(ctx context.Context, client db_common.Client, query string, parallelism int) (int64, error) {
+	var totalRows int64
+	var rowsMut sync.Mutex
+	g, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < parallelism; i++ {
+		g.Go(func() error {
+			rows, err := executeQuery(ctx, client, query)
+			rowsMut.Lock()
+			totalRows += rows
+			rowsMut.Unlock()
+			return err
+		})
+	}
+	err := g.Wait()
+	return totalRows, err
+}
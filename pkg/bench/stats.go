@@ -0,0 +1,49 @@
+package bench
+
+import (
+	"sort"
+	"time"
+)
+
+// Timings contains summary statistics for the durations of the iterations of a benchmark
+type Timings struct {
+	MinMs    float64 `json:"min_ms"`
+	MaxMs    float64 `json:"max_ms"`
+	MeanMs   float64 `json:"mean_ms"`
+	MedianMs float64 `json:"median_ms"`
+	TotalMs  float64 `json:"total_ms"`
+}
+
+func newTimings(durations []time.Duration) Timings {
+	if len(durations) == 0 {
+		return Timings{}
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	var median time.Duration
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		median = sorted[mid]
+	}
+
+	return Timings{
+		MinMs:    toMs(sorted[0]),
+		MaxMs:    toMs(sorted[len(sorted)-1]),
+		MeanMs:   toMs(total / time.Duration(len(sorted))),
+		MedianMs: toMs(median),
+		TotalMs:  toMs(total),
+	}
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,41 @@
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTimings(t *testing.T) {
+	tests := map[string]struct {
+		durations []time.Duration
+		expected  Timings
+	}{
+		"empty": {
+			durations: nil,
+			expected:  Timings{},
+		},
+		"single": {
+			durations: []time.Duration{10 * time.Millisecond},
+			expected:  Timings{MinMs: 10, MaxMs: 10, MeanMs: 10, MedianMs: 10, TotalMs: 10},
+		},
+		"odd": {
+			durations: []time.Duration{30 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond},
+			expected:  Timings{MinMs: 10, MaxMs: 30, MeanMs: 20, MedianMs: 20, TotalMs: 60},
+		},
+		"even": {
+			durations: []time.Duration{40 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond},
+			expected:  Timings{MinMs: 10, MaxMs: 40, MeanMs: 25, MedianMs: 25, TotalMs: 100},
+		},
+		"sub millisecond": {
+			durations: []time.Duration{1500 * time.Microsecond},
+			expected:  Timings{MinMs: 1.5, MaxMs: 1.5, MeanMs: 1.5, MedianMs: 1.5, TotalMs: 1.5},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := newTimings(test.durations); got != test.expected {
+				t.Errorf("newTimings() = %+v, expected %+v", got, test.expected)
+			}
+		})
+	}
+}
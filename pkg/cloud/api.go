@@ -7,12 +7,15 @@ import (
 	"github.com/spf13/viper"
 	steampipecloud "github.com/turbot/steampipe-cloud-sdk-go"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/network"
 )
 
 func newSteampipeCloudClient(token string) *steampipecloud.APIClient {
 	// Create a default configuration
 	configuration := steampipecloud.NewConfiguration()
 	configuration.Host = viper.GetString(constants.ArgPipesHost)
+	// use the configured CA bundle, proxy and TLS settings
+	configuration.HTTPClient = network.HttpClient()
 
 	// Add your Turbot Pipes user token as an auth header
 	if token != "" {
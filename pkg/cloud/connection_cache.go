@@ -0,0 +1,194 @@
+package cloud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/secrets"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// CloudConnectionCacheTtl is the time for which a cached workspace connection string is used without a Turbot Pipes lookup
+const CloudConnectionCacheTtl = time.Hour
+
+// cloudConnectionCacheEntry is the cached result of a cloud metadata lookup for a workspace database
+// the connection string contains the database password, so it is stored encrypted using the config encryption key
+// (the connection string is removed from the cached metadata)
+//
+// caching must never create a key or prompt for the passphrase of the key file - if no key exists, or it cannot be
+// loaded without prompting, connection strings are not cached
+type cloudConnectionCacheEntry struct {
+	Metadata                  *steampipeconfig.CloudMetadata `json:"metadata"`
+	EncryptedConnectionString string                         `json:"connection_string"`
+	ResolvedAt                time.Time                      `json:"resolved_at"`
+}
+
+func (e *cloudConnectionCacheEntry) expired() bool {
+	return time.Since(e.ResolvedAt) >= CloudConnectionCacheTtl
+}
+
+// cloudConnectionCacheLock serialises access to the cache file
+var cloudConnectionCacheLock sync.Mutex
+
+// GetCachedCloudMetadata returns the cloud metadata for the workspace database, using the connection cache where possible
+// - if the cache contains an entry younger than CloudConnectionCacheTtl, it is returned without a Turbot Pipes lookup
+// - otherwise the metadata is looked up and cached
+// expired entries are never used - they are removed from the cache when it is next saved
+func GetCachedCloudMetadata(ctx context.Context, workspaceDatabase, token string) (*steampipeconfig.CloudMetadata, error) {
+	encryptionKey, err := secrets.LoadKeyIfAvailable()
+	if err != nil {
+		log.Printf("[WARN] failed to load the config encryption key - the connection string will not be cached: %s", err.Error())
+	}
+	if encryptionKey == nil {
+		log.Printf("[TRACE] no config encryption key is available - the connection string will not be cached")
+		return GetCloudMetadata(ctx, workspaceDatabase, token)
+	}
+
+	key := cloudConnectionCacheKey(workspaceDatabase, token)
+	cache := loadCloudConnectionCache()
+
+	if entry, cached := cache[key]; cached && !entry.expired() {
+		cloudMetadata, err := entry.toCloudMetadata(encryptionKey)
+		if err == nil {
+			log.Printf("[TRACE] using cached connection string for workspace database %s", workspaceDatabase)
+			return cloudMetadata, nil
+		}
+		log.Printf("[WARN] failed to decrypt the cached connection string for workspace database %s: %s", workspaceDatabase, err.Error())
+	}
+
+	cloudMetadata, err := GetCloudMetadata(ctx, workspaceDatabase, token)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := newCloudConnectionCacheEntry(encryptionKey, cloudMetadata)
+	if err != nil {
+		log.Printf("[WARN] failed to cache the connection string for workspace database %s: %s", workspaceDatabase, err.Error())
+		return cloudMetadata, nil
+	}
+	cache[key] = entry
+	if err := saveCloudConnectionCache(cache); err != nil {
+		log.Printf("[WARN] failed to save the cloud connection cache: %s", err.Error())
+	}
+	return cloudMetadata, nil
+}
+
+// EvictCachedConnectionString removes any cache entry with the given connection string
+// this is called when authentication using the connection string fails (e.g. the database password has been rotated),
+// so the connection string is looked up again the next time it is required
+// if the config encryption key cannot be loaded without prompting, the entries cannot be matched so all are removed
+func EvictCachedConnectionString(connectionString string) {
+	encryptionKey, err := secrets.LoadKeyIfAvailable()
+	if err != nil {
+		log.Printf("[WARN] failed to load the config encryption key - all connection strings will be evicted: %s", err.Error())
+	}
+	cache := loadCloudConnectionCache()
+	evicted := false
+	for key, entry := range cache {
+		if encryptionKey == nil {
+			delete(cache, key)
+			evicted = true
+			continue
+		}
+		if cloudMetadata, err := entry.toCloudMetadata(encryptionKey); err == nil && cloudMetadata.ConnectionString == connectionString {
+			delete(cache, key)
+			evicted = true
+		}
+	}
+	if !evicted {
+		return
+	}
+	log.Printf("[INFO] authentication failed - removed the cached connection string from the cloud connection cache")
+	if err := saveCloudConnectionCache(cache); err != nil {
+		log.Printf("[WARN] failed to save the cloud connection cache: %s", err.Error())
+	}
+}
+
+func newCloudConnectionCacheEntry(key *age.X25519Identity, cloudMetadata *steampipeconfig.CloudMetadata) (*cloudConnectionCacheEntry, error) {
+	encryptedConnectionString, err := secrets.EncryptValue(key, cloudMetadata.ConnectionString)
+	if err != nil {
+		return nil, err
+	}
+	metadata := *cloudMetadata
+	metadata.ConnectionString = ""
+	return &cloudConnectionCacheEntry{
+		Metadata:                  &metadata,
+		EncryptedConnectionString: encryptedConnectionString,
+		ResolvedAt:                time.Now(),
+	}, nil
+}
+
+func (e *cloudConnectionCacheEntry) toCloudMetadata(key *age.X25519Identity) (*steampipeconfig.CloudMetadata, error) {
+	connectionString, err := secrets.DecryptValue(key, e.EncryptedConnectionString)
+	if err != nil {
+		return nil, err
+	}
+	res := &steampipeconfig.CloudMetadata{}
+	if e.Metadata != nil {
+		*res = *e.Metadata
+	}
+	res.ConnectionString = connectionString
+	return res, nil
+}
+
+// cloudConnectionCacheKey returns the cache key for the workspace database and token
+// the token is included (hashed) so that a different token does not use a connection string resolved for another actor
+func cloudConnectionCacheKey(workspaceDatabase, token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return workspaceDatabase + "|" + hex.EncodeToString(hash[:8])
+}
+
+func loadCloudConnectionCache() map[string]*cloudConnectionCacheEntry {
+	cloudConnectionCacheLock.Lock()
+	defer cloudConnectionCacheLock.Unlock()
+
+	res := make(map[string]*cloudConnectionCacheEntry)
+	cachePath := filepaths.CloudConnectionCacheFilePath()
+	if !filehelpers.FileExists(cachePath) {
+		return res
+	}
+	content, err := os.ReadFile(cachePath)
+	if err != nil {
+		log.Printf("[WARN] failed to read the cloud connection cache: %s", err.Error())
+		return res
+	}
+	if err := json.Unmarshal(content, &res); err != nil {
+		// the cache is invalid - it will be overwritten
+		log.Printf("[WARN] failed to parse the cloud connection cache: %s", err.Error())
+		return make(map[string]*cloudConnectionCacheEntry)
+	}
+	// ignore any entries which are not encrypted (i.e. written by a previous version) - they are removed when the cache is saved
+	for key, entry := range res {
+		if !secrets.IsEncrypted(entry.EncryptedConnectionString) {
+			delete(res, key)
+		}
+	}
+	return res
+}
+
+// saveCloudConnectionCache writes the cache file, removing any expired entries
+func saveCloudConnectionCache(cache map[string]*cloudConnectionCacheEntry) error {
+	cloudConnectionCacheLock.Lock()
+	defer cloudConnectionCacheLock.Unlock()
+
+	for key, entry := range cache {
+		if entry.expired() {
+			delete(cache, key)
+		}
+	}
+	content, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	// only the current user may read the cache
+	return os.WriteFile(filepaths.CloudConnectionCacheFilePath(), content, 0600)
+}
@@ -24,7 +24,8 @@ func PublishSnapshot(ctx context.Context, snapshot *dashboardtypes.SteampipeSnap
 	}
 
 	// if snapshot location is a workspace handle, upload it
-	if steampipeconfig.IsCloudWorkspaceIdentifier(snapshotLocation) {
+	// (remote object store locations are written by the snapshot exporter)
+	if steampipeconfig.IsCloudWorkspaceIdentifier(snapshotLocation) && !export.IsRemoteDestination(snapshotLocation) {
 		url, err := uploadSnapshot(ctx, snapshot, share)
 		if err != nil {
 			return "", sperr.Wrap(err)
@@ -46,6 +47,9 @@ func exportSnapshot(snapshot *dashboardtypes.SteampipeSnapshot) (string, error)
 	fileName := export.GenerateDefaultExportFileName(snapshot.FileNameRoot, exporter.FileExtension())
 	dirName := viper.GetString(constants.ArgSnapshotLocation)
 	filePath := path.Join(dirName, fileName)
+	if export.IsRemoteDestination(dirName) {
+		filePath = export.JoinDestination(dirName, fileName)
+	}
 
 	err := exporter.Export(context.Background(), snapshot, filePath)
 	if err != nil {
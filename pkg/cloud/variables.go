@@ -0,0 +1,104 @@
+package cloud
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	steampipecloud "github.com/turbot/steampipe-cloud-sdk-go"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+)
+
+// GetWorkspaceVariableValues returns the values of the mod variables set in the given Turbot Pipes workspace
+//
+// modAliases is a map of the alias of each mod in the workspace to the prefix to use for its variable names
+// (the workspace mod uses an empty prefix, dependency mods use '<mod>.')
+//
+// the result is a map of variable name to value - secret values are included, so values must never be logged
+func GetWorkspaceVariableValues(ctx context.Context, workspaceHandle, token string, modAliases map[string]string) (map[string]any, error) {
+	if token == "" {
+		return nil, error_helpers.MissingCloudTokenError
+	}
+	client := newSteampipeCloudClient(token)
+
+	parts := strings.Split(workspaceHandle, "/")
+	if len(parts) != 2 {
+		return nil, sperr.New("invalid 'variables-workspace' argument '%s' - must be in format <identity>/<workspace>", workspaceHandle)
+	}
+	identityHandle := parts[0]
+	workspaceName := parts[1]
+
+	identity, _, err := client.Identities.Get(ctx, identityHandle).Execute()
+	if err != nil {
+		if error_helpers.IsInvalidCloudToken(err) {
+			return nil, error_helpers.InvalidCloudTokenError
+		}
+		return nil, sperr.New("Invalid 'variables-workspace' argument '%s'.\nPlease check the identity and workspace names and try again.", workspaceHandle)
+	}
+
+	res := make(map[string]any)
+	for alias, prefix := range modAliases {
+		variables, err := listWorkspaceModVariables(ctx, client, identity.Type, identityHandle, workspaceName, alias)
+		if error_helpers.IsInvalidWorkspaceDatabaseArg(err) {
+			// the mod is not installed in the workspace
+			log.Printf("[TRACE] mod '%s' is not installed in workspace '%s'", alias, workspaceHandle)
+			continue
+		}
+		if err != nil {
+			if error_helpers.IsInvalidCloudToken(err) {
+				return nil, error_helpers.InvalidCloudTokenError
+			}
+			return nil, sperr.WrapWithMessage(err, "failed to load variables for mod '%s' from workspace '%s'", alias, workspaceHandle)
+		}
+
+		for _, v := range variables {
+			value, ok := workspaceVariableValue(v)
+			if !ok {
+				continue
+			}
+			res[prefix+v.GetName()] = value
+		}
+	}
+	return res, nil
+}
+
+func listWorkspaceModVariables(ctx context.Context, client *steampipecloud.APIClient, identityType, identityHandle, workspaceHandle, modAlias string) ([]steampipecloud.WorkspaceModVariable, error) {
+	var res []steampipecloud.WorkspaceModVariable
+	var nextToken string
+	for {
+		var resp steampipecloud.ListWorkspaceModVariablesResponse
+		var err error
+		if identityType == "user" {
+			req := client.UserWorkspaceModVariables.List(ctx, identityHandle, workspaceHandle, modAlias)
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, _, err = req.Execute()
+		} else {
+			req := client.OrgWorkspaceModVariables.List(ctx, identityHandle, workspaceHandle, modAlias)
+			if nextToken != "" {
+				req = req.NextToken(nextToken)
+			}
+			resp, _, err = req.Execute()
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, resp.GetItems()...)
+
+		nextToken = resp.GetNextToken()
+		if nextToken == "" {
+			return res, nil
+		}
+	}
+}
+
+// workspaceVariableValue returns the value explicitly set for the variable in the workspace
+// variables which only have their mod default are ignored, so the local mod default is used
+func workspaceVariableValue(v steampipecloud.WorkspaceModVariable) (any, bool) {
+	if v.GetName() == "" || v.ValueSetting == nil {
+		return nil, false
+	}
+	return v.ValueSetting, true
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/thediveo/enumflag/v2"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -120,7 +121,32 @@ func (c *CmdBuilder) AddCloudFlags() *CmdBuilder {
 // AddWorkspaceDatabaseFlag is helper function to add the workspace-databse flag to a command
 func (c *CmdBuilder) AddWorkspaceDatabaseFlag() *CmdBuilder {
 	return c.
-		AddStringFlag(constants.ArgWorkspaceDatabase, constants.DefaultWorkspaceDatabase, "Turbot Pipes workspace database")
+		AddStringFlag(constants.ArgWorkspaceDatabase, constants.DefaultWorkspaceDatabase, "Turbot Pipes workspace database (a comma separated list of databases may be given - the first available database is used)")
+}
+
+// AddVariablesWorkspaceFlag is helper function to add the variables-workspace flag to a command
+func (c *CmdBuilder) AddVariablesWorkspaceFlag() *CmdBuilder {
+	return c.
+		AddStringFlag(constants.ArgVariablesWorkspace, "", "Turbot Pipes workspace to load default variable values from, in format <identity>/<workspace>")
+}
+
+// AddDashboardServerFlags is helper function to add the dashboard server base path and auth flags to a command
+func (c *CmdBuilder) AddDashboardServerFlags() *CmdBuilder {
+	return c.
+		AddStringFlag(constants.ArgDashboardBasePath, "", "The URL path the dashboard server is served under, e.g. when behind a reverse proxy").
+		AddStringFlag(constants.ArgDashboardAuth, "none", "Dashboard server authentication: none, or trusted-header (the user is authenticated by a trusted reverse proxy)").
+		AddStringFlag(constants.ArgDashboardAuthHeader, constants.DashboardDefaultAuthHeader, "The header containing the authenticated user (for trusted-header auth)").
+		AddStringFlag(constants.ArgDashboardTrustedProxies, constants.DashboardDefaultTrustedProxies, "Comma separated IP addresses or CIDRs of the reverse proxies trusted to set the auth header (for trusted-header auth)")
+}
+
+// AddProgressFlag is helper function to add the progress flag to a command
+// this accepts true/false, or a progress mode: tty (spinner), plain (plain text progress lines) or none
+func (c *CmdBuilder) AddProgressFlag(desc string) *CmdBuilder {
+	progressMode := constants.ProgressModeTrue
+	return c.AddVarFlag(enumflag.New(&progressMode, constants.ArgProgress, constants.ProgressModeIds, enumflag.EnumCaseInsensitive),
+		constants.ArgProgress,
+		fmt.Sprintf("%s; one of: true, false, %s, %s, %s", desc, constants.ProgressTTY, constants.ProgressPlain, constants.ProgressNone),
+		FlagOptions.NoOptDefVal("true"))
 }
 
 // AddModLocationFlag is helper function to add the mod-location flag to a command
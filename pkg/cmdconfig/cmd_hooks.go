@@ -27,9 +27,13 @@ import (
 	"github.com/turbot/steampipe/pkg/constants/runtime"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/network"
 	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/task"
+	"github.com/turbot/steampipe/pkg/tracing"
+	"github.com/turbot/steampipe/pkg/upgradeadvisor"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/turbot/steampipe/pkg/version"
 )
@@ -84,7 +88,17 @@ func preRunHook(cmd *cobra.Command, args []string) {
 	// display any warnings
 	ew.ShowWarnings()
 	// check for error
-	error_helpers.FailOnError(ew.Error)
+	error_helpers.FailOnError(error_helpers.NewExitCodeError(constants.ExitCodeConfigLoadFailed, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigLoadFailed, ew.Error)))
+
+	// validate the exit code map now, rather than when the command exits
+	_, err := ParseExitCodeMap(viper.GetString(constants.ArgExitCodeMap))
+	error_helpers.FailOnError(error_helpers.NewExitCodeError(constants.ExitCodeInsufficientOrWrongInputs, err))
+
+	// now the args and config are loaded, set the status hooks for the progress mode
+	if viper.IsSet(constants.ArgProgress) {
+		ctx = statushooks.AddStatusHooksToContext(ctx, statushooks.NewStatusHooksForProgress())
+		cmd.SetContext(ctx)
+	}
 
 	// if the log level was set in the general config
 	if logLevelNeedsReset() {
@@ -98,11 +112,25 @@ func preRunHook(cmd *cobra.Command, args []string) {
 		)
 	}
 
+	// if tracing is enabled by the telemetry level, set my environment to enable it
+	// so that this gets inherited by any other process started by this process
+	error_helpers.FailOnErrorWithMessage(tracing.SetEnv(), "Failed to setup tracing")
+
 	// recreate the logger
 	// this will put the new log level (if any) to effect as well as start streaming to the
 	// log file.
 	createLogger(logBuffer, cmd)
 
+	// if the CLI version has changed since the last run, report any required migrations,
+	// incompatible plugins and deprecated options
+	// otherwise, just warn about any incompatible plugins (unless this is the plugin check-compat command, which lists them)
+	// no point doing this for the plugin-manager since that would have been done by the initiating CLI process
+	if !task.IsPluginManagerCmd(cmd) {
+		if adviceShown := upgradeadvisor.DisplayAdvice(ctx); !adviceShown && !task.IsPluginCheckCompatCmd(cmd) {
+			upgradeadvisor.DisplayIncompatiblePluginsWarning(ctx)
+		}
+	}
+
 	// runScheduledTasks skips running tasks if this instance is the plugin manager
 	waitForTasksChannel = runScheduledTasks(ctx, cmd, args, ew)
 
@@ -145,14 +173,27 @@ func runScheduledTasks(ctx context.Context, cmd *cobra.Command, args []string, e
 	taskUpdateCtx, cancelFn := context.WithCancel(ctx)
 	tasksCancelFn = cancelFn
 
+	// build the usage report here (rather than in the task runner) to avoid concurrent viper access
+	var usageReport *task.UsageReport
+	if viper.GetBool(constants.ArgTelemetryExport) {
+		var err error
+		if usageReport, err = task.BuildUsageReport(); err != nil {
+			log.Printf("[WARN] failed to build usage report: %s", err.Error())
+		}
+	}
+
 	return task.RunTasks(
 		taskUpdateCtx,
 		cmd,
 		args,
+		task.WithUsageReportExport(usageReport),
 		// pass the config value in rather than runRasks querying viper directly - to avoid concurrent map access issues
 		// (we can use the update-check viper config here, since initGlobalConfig has already set it up
 		// with values from the config files and ENV settings - update-check cannot be set from the command line)
-		task.WithUpdateCheck(viper.GetBool(constants.ArgUpdateCheck)),
+		// (update checks are never run in offline mode)
+		task.WithUpdateCheck(viper.GetBool(constants.ArgUpdateCheck) && !network.IsOffline()),
+		// likewise the telemetry_redact fields, which are used by the update checks
+		task.WithTelemetryRedact(utils.RedactedRequestPayloadFields()),
 		// show deprecation warnings
 		task.WithPreHook(func(_ context.Context) {
 			displayDeprecationWarnings(ew)
@@ -228,7 +269,7 @@ func initGlobalConfig() error_helpers.ErrorAndWarnings {
 
 	// set the rest of the defaults from ENV
 	// ENV takes precedence over any default configuration
-	setDefaultsFromEnv()
+	setDefaultsFromEnv(cmd)
 
 	// if an explicit workspace profile was set, add to viper as highest precedence default
 	// NOTE: if install_dir/mod_location are set these will already have been passed to viper by BootstrapViper
@@ -237,8 +278,15 @@ func initGlobalConfig() error_helpers.ErrorAndWarnings {
 		SetDefaultsFromConfig(loader.ConfiguredProfile.ConfigMap(cmd))
 	}
 
+	// now strict-config has been resolved from all sources, report any config which has been ignored
+	ew := handleIgnoredConfig(loader.Warnings)
+	if ew.Error != nil {
+		return ew
+	}
+	loadConfigErrorsAndWarnings.Merge(ew)
+
 	// handle deprecated cloud-host and cloud-token args and env vars
-	ew := handleDeprecations()
+	ew = handleDeprecations()
 	if ew.Error != nil {
 		return ew
 	}
@@ -263,6 +311,18 @@ func initGlobalConfig() error_helpers.ErrorAndWarnings {
 	return loadConfigErrorsAndWarnings
 }
 
+// handleIgnoredConfig reports config which has been ignored when loading
+// in strict config mode this is an error, otherwise the ignored config is reported as warnings
+func handleIgnoredConfig(ignored []string) error_helpers.ErrorAndWarnings {
+	if len(ignored) == 0 {
+		return error_helpers.EmptyErrorsAndWarning()
+	}
+	if viper.GetBool(constants.ArgStrictConfig) {
+		return error_helpers.NewErrorsAndWarning(sperr.New("invalid config (--%s is set):\n%s", constants.ArgStrictConfig, strings.Join(ignored, "\n")))
+	}
+	return error_helpers.NewErrorsAndWarning(nil, ignored...)
+}
+
 func handleDeprecations() error_helpers.ErrorAndWarnings {
 	var ew = error_helpers.ErrorAndWarnings{}
 	// if deprecated cloud-token or cloud-host is set, show a warning and copy the value to the new arg
@@ -369,7 +429,7 @@ func getWorkspaceProfileLoader(ctx context.Context) (*steampipeconfig.WorkspaceP
 }
 
 // now validate  config values have appropriate values
-// (currently validates telemetry)
+// (currently validates telemetry and telemetry redaction)
 func validateConfig() error_helpers.ErrorAndWarnings {
 	var res = error_helpers.ErrorAndWarnings{}
 	telemetry := viper.GetString(constants.ArgTelemetry)
@@ -377,10 +437,35 @@ func validateConfig() error_helpers.ErrorAndWarnings {
 		res.Error = sperr.New(`invalid value of 'telemetry' (%s), must be one of: %s`, telemetry, strings.Join(constants.TelemetryLevels, ", "))
 		return res
 	}
+	for _, field := range utils.RedactedRequestPayloadFields() {
+		if !helpers.StringSliceContains(utils.RedactableRequestPayloadFields, field) {
+			res.Error = sperr.New(`invalid field in 'telemetry_redact' (%s), must be one of: %s`, field, strings.Join(utils.RedactableRequestPayloadFields, ", "))
+			return res
+		}
+	}
+	// (if verify is not set, the mode depends on whether a verify key is configured)
+	imageVerify := viper.GetString(constants.ArgImageVerify)
+	if imageVerify != "" && !helpers.StringSliceContains(constants.ImageVerifyModes, imageVerify) {
+		res.Error = sperr.New(`invalid value of 'verify' (%s), must be one of: %s`, imageVerify, strings.Join(constants.ImageVerifyModes, ", "))
+		return res
+	}
 	if _, legacyDiagnosticsSet := os.LookupEnv(plugin.EnvLegacyDiagnosticsLevel); legacyDiagnosticsSet {
 		res.AddWarning(fmt.Sprintf("Environment variable %s is deprecated - use %s", plugin.EnvLegacyDiagnosticsLevel, plugin.EnvDiagnosticsLevel))
 	}
 	res.Error = plugin.ValidateDiagnosticsEnvVar()
+	if res.Error != nil {
+		return res
+	}
+
+	// build the http transport from the network options - this validates the CA certificate file
+	networkWarnings, err := network.Configure()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	for _, w := range networkWarnings {
+		res.AddWarning(w)
+	}
 
 	return res
 }
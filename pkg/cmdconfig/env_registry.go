@@ -0,0 +1,194 @@
+package cmdconfig
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	sdklogging "github.com/turbot/steampipe-plugin-sdk/v5/logging"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// EnvVarBinding binds an environment variable to the viper config keys it sets
+// values from the environment take precedence over options set in config files, but not over command line flags
+// or the settings of an explicitly selected workspace profile
+type EnvVarBinding struct {
+	EnvVar string `json:"env_var"`
+	// the viper keys the value is set for - if empty, the env var is read directly where it is used
+	ConfigKeys []string   `json:"-"`
+	Type       EnvVarType `json:"-"`
+	// the options block setting or flag which the env var corresponds to, e.g. database.port or --output
+	Setting     string `json:"setting,omitempty"`
+	Description string `json:"description"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
+// envVarRegistry is the registry of the environment variables with explicit names
+// every options block setting has an entry - command flags which do not have an entry here are bound to a
+// generated environment variable (see flagEnvVarName)
+var envVarRegistry = []EnvVarBinding{
+	// workspace
+	{EnvVar: constants.EnvInstallDir, ConfigKeys: []string{constants.ArgInstallDir}, Type: String, Setting: "--install-dir", Description: "Path to the Steampipe install directory"},
+	{EnvVar: constants.EnvWorkspaceProfile, ConfigKeys: []string{constants.ArgWorkspaceProfile}, Type: String, Setting: "--workspace", Description: "The workspace profile to use"},
+	{EnvVar: constants.EnvWorkspaceProfileLocation, Description: "Path to the directory containing workspace profiles"},
+	{EnvVar: constants.EnvWorkspaceChDir, ConfigKeys: []string{constants.ArgModLocation}, Type: String, Setting: "--mod-location", Description: "Path to the workspace working directory", Deprecated: true},
+	{EnvVar: constants.EnvModLocation, ConfigKeys: []string{constants.ArgModLocation}, Type: String, Setting: "--mod-location", Description: "Path to the workspace working directory"},
+	{EnvVar: constants.EnvWorkspaceDatabase, ConfigKeys: []string{constants.ArgWorkspaceDatabase}, Type: String, Setting: "--workspace-database", Description: "Turbot Pipes workspace database"},
+	{EnvVar: constants.EnvSnapshotLocation, ConfigKeys: []string{constants.ArgSnapshotLocation}, Type: String, Setting: "--snapshot-location", Description: "The location to write snapshots to"},
+	{EnvVar: constants.EnvVariablesWorkspace, ConfigKeys: []string{constants.ArgVariablesWorkspace}, Type: String, Setting: "--variables-workspace", Description: "The Turbot Pipes workspace to load variable values from"},
+	{EnvVar: constants.EnvIntrospection, ConfigKeys: []string{constants.ArgIntrospection}, Type: String, Setting: "workspace.introspection", Description: "Introspection tables to create: none, info or control"},
+	{EnvVar: constants.EnvQueryTimeout, ConfigKeys: []string{constants.ArgDatabaseQueryTimeout}, Type: Int, Setting: "--query-timeout", Description: "The query timeout, in seconds"},
+	{EnvVar: constants.EnvMaxParallel, ConfigKeys: []string{constants.ArgMaxParallel}, Type: Int, Setting: "--max-parallel", Description: "The maximum number of parallel executions"},
+	{EnvVar: constants.EnvDisplayWidth, ConfigKeys: []string{constants.ArgDisplayWidth}, Type: Int, Description: "The width of the terminal display"},
+	{EnvVar: constants.EnvExitCodeMap, ConfigKeys: []string{constants.ArgExitCodeMap}, Type: String, Setting: "--exit-code-map", Description: "Remap exit codes, e.g. 'alarm=0'"},
+	{EnvVar: constants.EnvInputVarPrefix + "<name>", Description: "The value of the mod variable <name>"},
+
+	// turbot pipes
+	{EnvVar: constants.EnvCloudHost, ConfigKeys: []string{constants.ArgPipesHost}, Type: String, Setting: "--pipes-host", Description: "Turbot Pipes host", Deprecated: true},
+	{EnvVar: constants.EnvCloudToken, ConfigKeys: []string{constants.ArgPipesToken}, Type: String, Setting: "--pipes-token", Description: "Turbot Pipes authentication token", Deprecated: true},
+	{EnvVar: constants.EnvPipesHost, ConfigKeys: []string{constants.ArgPipesHost}, Type: String, Setting: "--pipes-host", Description: "Turbot Pipes host"},
+	{EnvVar: constants.EnvPipesToken, ConfigKeys: []string{constants.ArgPipesToken}, Type: String, Setting: "--pipes-token", Description: "Turbot Pipes authentication token"},
+
+	// general options
+	{EnvVar: constants.EnvUpdateCheck, ConfigKeys: []string{constants.ArgUpdateCheck}, Type: Bool, Setting: "general.update_check", Description: "Check for CLI and plugin updates"},
+	{EnvVar: constants.EnvOffline, ConfigKeys: []string{constants.ArgOffline}, Type: Bool, Setting: "--offline", Description: "Run without network access"},
+	{EnvVar: constants.EnvTelemetry, ConfigKeys: []string{constants.ArgTelemetry}, Type: String, Setting: "general.telemetry", Description: "Telemetry level: none or info"},
+	{EnvVar: constants.EnvTelemetryRedact, ConfigKeys: []string{constants.ArgTelemetryRedact}, Type: String, Setting: "general.telemetry_redact", Description: "Redaction applied to telemetry"},
+	{EnvVar: constants.EnvTelemetryExport, ConfigKeys: []string{constants.ArgTelemetryExport}, Type: Bool, Setting: "general.telemetry_export", Description: "Export usage reports to a local file"},
+	{EnvVar: sdklogging.EnvLogLevel, Setting: "general.log_level", Description: "Log level: trace, debug, info, warn or error"},
+	{EnvVar: constants.EnvMemoryMaxMb, ConfigKeys: []string{constants.ArgMemoryMaxMb}, Type: Int, Setting: "general.memory_max_mb", Description: "Soft memory limit of the CLI process, in MB"},
+	{EnvVar: constants.EnvLogRetentionDays, ConfigKeys: []string{constants.ArgLogRetentionDays}, Type: Int, Setting: "general.log_retention_days", Description: "The number of days log files are kept"},
+	{EnvVar: constants.EnvTempDirRetentionHours, ConfigKeys: []string{constants.ArgTempDirRetentionHours}, Type: Int, Setting: "general.temp_dir_retention_hours", Description: "The number of hours temporary directories are kept"},
+	{EnvVar: constants.EnvStrictConfig, ConfigKeys: []string{constants.ArgStrictConfig}, Type: Bool, Setting: "general.strict_config", Description: "Treat config which would otherwise be ignored as an error"},
+
+	// plugin options
+	{EnvVar: constants.EnvMemoryMaxMbPlugin, ConfigKeys: []string{constants.ArgMemoryMaxMbPlugin}, Type: Int, Setting: "plugin.memory_max_mb", Description: "Soft memory limit of each plugin process, in MB"},
+
+	// database options
+	{EnvVar: constants.EnvCacheEnabled, ConfigKeys: []string{constants.ArgClientCacheEnabled, constants.ArgServiceCacheEnabled}, Type: Bool, Setting: "database.cache", Description: "Enable the query cache"},
+	{EnvVar: constants.EnvCacheTTL, ConfigKeys: []string{constants.ArgCacheTtl}, Type: Int, Setting: "connection.cache_ttl", Description: "The query cache ttl, in seconds"},
+	{EnvVar: constants.EnvCacheMaxTTL, ConfigKeys: []string{constants.ArgCacheMaxTtl}, Type: Int, Setting: "database.cache_max_ttl", Description: "The maximum query cache ttl, in seconds"},
+	{EnvVar: constants.EnvCacheMaxSize, ConfigKeys: []string{constants.ArgMaxCacheSizeMb}, Type: Int, Setting: "database.cache_max_size_mb", Description: "The maximum size of the query cache, in MB"},
+	{EnvVar: constants.EnvDatabaseListen, ConfigKeys: []string{constants.ArgDatabaseListenAddresses}, Type: String, Setting: "database.listen", Description: "The addresses the service listens on: local or network"},
+	{EnvVar: constants.EnvDatabasePort, ConfigKeys: []string{constants.ArgDatabasePort}, Type: Int, Setting: "database.port", Description: "The port the service listens on"},
+	{EnvVar: constants.EnvDatabaseSearchPath, ConfigKeys: []string{constants.ConfigKeyServerSearchPath}, Type: StringSlice, Setting: "database.search_path", Description: "The search path of the service"},
+	{EnvVar: constants.EnvDatabaseSearchPathPrefix, ConfigKeys: []string{constants.ConfigKeyServerSearchPathPrefix}, Type: StringSlice, Setting: "database.search_path_prefix", Description: "The search path prefix of the service"},
+	{EnvVar: constants.EnvDatabaseStartTimeout, ConfigKeys: []string{constants.ArgDatabaseStartTimeout}, Type: Int, Setting: "database.start_timeout", Description: "The time allowed for the service to start, in seconds"},
+	{EnvVar: constants.EnvRefreshConcurrency, ConfigKeys: []string{constants.ArgRefreshConcurrency}, Type: Int, Setting: "database.refresh_concurrency", Description: "The number of connections refreshed in parallel"},
+	{EnvVar: constants.EnvMaxQueryDuration, ConfigKeys: []string{constants.ArgMaxQueryDuration}, Type: Int, Setting: "database.max_query_duration", Description: "The maximum duration of a query, in seconds"},
+	{EnvVar: constants.EnvSchemaNameCase, ConfigKeys: []string{constants.ArgSchemaNameCase}, Type: String, Setting: "database.schema_name_case", Description: "Case of the schema names of connections: preserve or lower"},
+	{EnvVar: constants.EnvSchemaNameInvalidChars, ConfigKeys: []string{constants.ArgSchemaNameInvalidChars}, Type: String, Setting: "database.schema_name_invalid_chars", Description: "Handling of connection names which are not valid identifiers: error, quote or transliterate"},
+	{EnvVar: constants.EnvMaintenanceInterval, ConfigKeys: []string{constants.ArgMaintenanceInterval}, Type: Int, Setting: "database.maintenance_interval", Description: "The interval at which the service vacuums its internal tables, in minutes (0 to disable)"},
+	{EnvVar: constants.EnvStateStorage, ConfigKeys: []string{constants.ArgStateStorage}, Type: String, Setting: "database.state_storage", Description: "Where the connection state files are saved: file (the install dir) or database (shared by all nodes using the database)"},
+	{EnvVar: constants.EnvServicePassword, ConfigKeys: []string{constants.ArgServicePassword}, Type: String, Setting: "--database-password", Description: "The password of the service"},
+	{EnvVar: constants.EnvDatabaseSSLPassword, ConfigKeys: []string{constants.ArgDatabaseSSLPassword}, Type: String, Description: "The passphrase of the service ssl private key"},
+	{EnvVar: constants.EnvDatabaseArchive, ConfigKeys: []string{constants.ArgDatabaseArchive}, Type: String, Description: "A local OCI archive (image layout directory or tarball) to install the database from, rather than the registry"},
+	{EnvVar: constants.EnvFdwArchive, ConfigKeys: []string{constants.ArgFdwArchive}, Type: String, Description: "A local OCI archive (image layout directory or tarball) to install the FDW from, rather than the registry"},
+	{EnvVar: constants.EnvInstallDatabase, Description: "The name of the database created when the service is installed"},
+	{EnvVar: constants.EnvConnectionWatcher, Setting: "connection.watch", Description: "Watch connection config files for changes"},
+
+	// dashboard options
+	{EnvVar: constants.EnvDashboardBrowser, ConfigKeys: []string{constants.ArgBrowser}, Type: Bool, Setting: "dashboard.browser", Description: "Open the dashboard in the browser"},
+	{EnvVar: constants.EnvDashboardListen, ConfigKeys: []string{constants.ArgDashboardListen}, Type: String, Setting: "dashboard.listen", Description: "The addresses the dashboard server listens on: local or network"},
+	{EnvVar: constants.EnvDashboardPort, ConfigKeys: []string{constants.ArgDashboardPort}, Type: Int, Setting: "dashboard.port", Description: "The port the dashboard server listens on"},
+	{EnvVar: constants.EnvDashboardStartTimeout, ConfigKeys: []string{constants.ArgDashboardStartTimeout}, Type: Int, Setting: "dashboard.start_timeout", Description: "The time allowed for the dashboard server to start, in seconds"},
+
+	// terminal, query and check options
+	{EnvVar: constants.EnvOutput, ConfigKeys: []string{constants.ArgOutput}, Type: String, Setting: "query.output", Description: "The output format"},
+	{EnvVar: constants.EnvSeparator, ConfigKeys: []string{constants.ArgSeparator}, Type: String, Setting: "query.separator", Description: "The csv separator"},
+	{EnvVar: constants.EnvHeader, ConfigKeys: []string{constants.ArgHeader}, Type: Bool, Setting: "query.header", Description: "Include column headers in csv and table output"},
+	{EnvVar: constants.EnvQuoteMode, ConfigKeys: []string{constants.ArgQuoteMode}, Type: String, Setting: "query.quote_mode", Description: "When to quote csv fields: needed, all, nonnumeric or none"},
+	{EnvVar: constants.EnvMulti, ConfigKeys: []string{constants.ArgMultiLine}, Type: Bool, Setting: "query.multi", Description: "Enable multi-line input in interactive mode"},
+	{EnvVar: constants.EnvTiming, ConfigKeys: []string{constants.ArgTiming}, Type: String, Setting: "query.timing", Description: "Show query timing: on, off or verbose"},
+	{EnvVar: constants.EnvAutoComplete, ConfigKeys: []string{constants.ArgAutoComplete}, Type: Bool, Setting: "query.autocomplete", Description: "Enable autocomplete in interactive mode"},
+	{EnvVar: constants.EnvTimezone, ConfigKeys: []string{constants.ArgTimezone}, Type: String, Setting: "query.timezone", Description: "The timezone timestamps are displayed in"},
+	{EnvVar: constants.EnvTimestampFormat, ConfigKeys: []string{constants.ArgTimestampFormat}, Type: String, Setting: "query.timestamp_format", Description: "The format timestamps are displayed in"},
+	{EnvVar: constants.EnvSearchPath, ConfigKeys: []string{constants.ArgSearchPath}, Type: StringSlice, Setting: "terminal.search_path", Description: "The search path of client sessions"},
+	{EnvVar: constants.EnvSearchPathPrefix, ConfigKeys: []string{constants.ArgSearchPathPrefix}, Type: StringSlice, Setting: "terminal.search_path_prefix", Description: "The search path prefix of client sessions"},
+	{EnvVar: constants.EnvWatch, ConfigKeys: []string{constants.ArgWatch}, Type: Bool, Setting: "terminal.watch", Description: "Watch mod files for changes"},
+
+	// network options
+	{EnvVar: constants.EnvCaCertFile, ConfigKeys: []string{constants.ArgCaCertFile}, Type: String, Setting: "network.ca_cert_file", Description: "A PEM file of additional CA certificates to trust"},
+	{EnvVar: constants.EnvHttpProxy, ConfigKeys: []string{constants.ArgHttpProxy}, Type: String, Setting: "network.http_proxy", Description: "The proxy used for http requests"},
+	{EnvVar: constants.EnvHttpsProxy, ConfigKeys: []string{constants.ArgHttpsProxy}, Type: String, Setting: "network.https_proxy", Description: "The proxy used for https requests"},
+	{EnvVar: constants.EnvNoProxy, ConfigKeys: []string{constants.ArgNoProxy}, Type: String, Setting: "network.no_proxy", Description: "Hosts which are not proxied"},
+	{EnvVar: constants.EnvTlsSkipVerify, ConfigKeys: []string{constants.ArgTlsSkipVerify}, Type: Bool, Setting: "network.tls_skip_verify", Description: "Skip verification of server certificates"},
+
+	// image options
+	{EnvVar: constants.EnvImageVerify, ConfigKeys: []string{constants.ArgImageVerify}, Type: String, Setting: "image.verify", Description: "Signature verification of installed images: strict, warn or off (defaults to warn if a verify key is set, otherwise off)"},
+	{EnvVar: constants.EnvImageVerifyKey, ConfigKeys: []string{constants.ArgImageVerifyKey}, Type: String, Setting: "image.verify_key", Description: "A PEM file of the public keys image signatures are verified with"},
+
+	// secrets
+	{EnvVar: constants.EnvConfigKey, Description: "The age identity used to encrypt and decrypt config values"},
+	{EnvVar: constants.EnvConfigPassphrase, Description: "The passphrase protecting the config encryption key file"},
+	{EnvVar: constants.EnvWebhookSecret, Description: "The secret used to sign webhook export payloads"},
+}
+
+// flags which are not bound to an environment variable
+var unboundFlags = map[string]struct{}{
+	constants.ArgHelp:     {},
+	constants.ArgVersion:  {},
+	constants.ArgVariable: {},
+}
+
+// EnvVarBindings returns the environment variable bindings which apply to the command - the registry entries,
+// followed by generated bindings for the flags of the command (including inherited flags) which have no registry entry
+func EnvVarBindings(cmd *cobra.Command) []EnvVarBinding {
+	res := append([]EnvVarBinding{}, envVarRegistry...)
+
+	// determine which config keys and env vars already have an explicit binding
+	boundKeys := make(map[string]struct{})
+	envVars := make(map[string]struct{})
+	for _, b := range envVarRegistry {
+		for _, k := range b.ConfigKeys {
+			boundKeys[k] = struct{}{}
+		}
+		envVars[b.EnvVar] = struct{}{}
+	}
+
+	var flagBindings []EnvVarBinding
+	addFlag := func(f *pflag.Flag) {
+		if _, ok := unboundFlags[f.Name]; ok {
+			return
+		}
+		if _, ok := boundKeys[f.Name]; ok {
+			return
+		}
+		envVar := flagEnvVarName(f.Name)
+		if _, ok := envVars[envVar]; ok {
+			return
+		}
+		envVars[envVar] = struct{}{}
+		flagBindings = append(flagBindings, EnvVarBinding{
+			EnvVar:      envVar,
+			ConfigKeys:  []string{f.Name},
+			Type:        flagEnvVarType(f),
+			Setting:     "--" + f.Name,
+			Description: f.Usage,
+		})
+	}
+	cmd.LocalFlags().VisitAll(addFlag)
+	cmd.InheritedFlags().VisitAll(addFlag)
+
+	sort.Slice(flagBindings, func(i, j int) bool { return flagBindings[i].EnvVar < flagBindings[j].EnvVar })
+	return append(res, flagBindings...)
+}
+
+// flagEnvVarName returns the name of the environment variable generated for a flag, e.g. STEAMPIPE_SNAPSHOT_TITLE for --snapshot-title
+func flagEnvVarName(flagName string) string {
+	return "STEAMPIPE_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func flagEnvVarType(f *pflag.Flag) EnvVarType {
+	switch f.Value.Type() {
+	case "bool":
+		return Bool
+	case "int", "int32", "int64":
+		return Int
+	case "stringSlice", "stringArray":
+		return StringSlice
+	default:
+		return String
+	}
+}
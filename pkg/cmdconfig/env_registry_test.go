@@ -0,0 +1,53 @@
+package cmdconfig
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestEnvVarBindings(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String(constants.ArgOutput, "", "output")
+	cmd.Flags().String("snapshot-title", "", "snapshot title")
+	cmd.Flags().Bool("share", false, "share")
+	cmd.Flags().StringSlice("arg", nil, "args")
+	cmd.Flags().Bool(constants.ArgHelp, false, "help")
+
+	bindings := make(map[string]EnvVarBinding)
+	envVarsByKey := make(map[string][]string)
+	for _, b := range EnvVarBindings(cmd) {
+		if _, ok := bindings[b.EnvVar]; ok {
+			t.Errorf("env var %s is bound more than once", b.EnvVar)
+		}
+		bindings[b.EnvVar] = b
+		for _, k := range b.ConfigKeys {
+			envVarsByKey[k] = append(envVarsByKey[k], b.EnvVar)
+		}
+	}
+
+	// a flag with a registry entry uses the registry env var
+	if got := envVarsByKey[constants.ArgOutput]; len(got) != 1 || got[0] != constants.EnvOutput {
+		t.Errorf("output is bound to %v, want [%s]", got, constants.EnvOutput)
+	}
+	// other flags are bound to generated env vars
+	tests := map[string]EnvVarType{
+		"STEAMPIPE_SNAPSHOT_TITLE": String,
+		"STEAMPIPE_SHARE":          Bool,
+		"STEAMPIPE_ARG":            StringSlice,
+	}
+	for envVar, wantType := range tests {
+		b, ok := bindings[envVar]
+		if !ok {
+			t.Errorf("no binding for %s", envVar)
+			continue
+		}
+		if b.Type != wantType {
+			t.Errorf("%s has type %s, want %s", envVar, b.Type, wantType)
+		}
+	}
+	if _, ok := bindings["STEAMPIPE_HELP"]; ok {
+		t.Errorf("the help flag should not be bound")
+	}
+}
@@ -6,6 +6,8 @@ const (
 	String EnvVarType = iota
 	Int
 	Bool
+	// StringSlice is a comma separated list
+	StringSlice
 )
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type=EnvVarType
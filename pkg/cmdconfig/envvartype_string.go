@@ -11,11 +11,12 @@ func _() {
 	_ = x[String-0]
 	_ = x[Int-1]
 	_ = x[Bool-2]
+	_ = x[StringSlice-3]
 }
 
-const _EnvVarType_name = "StringIntBool"
+const _EnvVarType_name = "StringIntBoolStringSlice"
 
-var _EnvVarType_index = [...]uint8{0, 6, 9, 13}
+var _EnvVarType_index = [...]uint8{0, 6, 9, 13, 24}
 
 func (i EnvVarType) String() string {
 	if i < 0 || i >= EnvVarType(len(_EnvVarType_index)-1) {
@@ -0,0 +1,61 @@
+package cmdconfig
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// ParseExitCodeMap parses an exit code map of the form 'alarm=0,connection_error=3,250=4'
+// the keys may be either an exit code class or an exit code
+func ParseExitCodeMap(exitCodeMap string) (map[string]int, error) {
+	res := make(map[string]int)
+	if strings.TrimSpace(exitCodeMap) == "" {
+		return res, nil
+	}
+	for _, item := range strings.Split(exitCodeMap, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(item), "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, sperr.New("invalid exit code map item '%s' - must be of the form <class or exit code>=<exit code>", item)
+		}
+		if _, err := strconv.Atoi(key); err != nil && !helpers.StringSliceContains(constants.ExitCodeClasses(), key) {
+			return nil, sperr.New("invalid exit code map key '%s' - must be an exit code or one of: %s", key, strings.Join(constants.ExitCodeClasses(), ", "))
+		}
+		exitCode, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || exitCode < 0 || exitCode > 255 {
+			return nil, sperr.New("invalid exit code '%s' for '%s' - must be an integer between 0 and 255", value, key)
+		}
+		res[key] = exitCode
+	}
+	return res, nil
+}
+
+// MapExitCode applies the exit code map (if any) to the exit code
+// a mapping for the exit code itself takes precedence over a mapping for its class
+func MapExitCode(exitCode int) int {
+	exitCodeMap, err := ParseExitCodeMap(viper.GetString(constants.ArgExitCodeMap))
+	if err != nil {
+		// this will already have been reported by the pre run hook
+		log.Printf("[WARN] ignoring invalid exit code map: %s", err.Error())
+		return exitCode
+	}
+	return mapExitCode(exitCode, exitCodeMap)
+}
+
+func mapExitCode(exitCode int, exitCodeMap map[string]int) int {
+	if mapped, ok := exitCodeMap[strconv.Itoa(exitCode)]; ok {
+		return mapped
+	}
+	if class := constants.ExitCodeClass(exitCode); class != "" {
+		if mapped, ok := exitCodeMap[class]; ok {
+			return mapped
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,34 @@
+package cmdconfig
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestMapExitCode(t *testing.T) {
+	exitCodeMap, err := ParseExitCodeMap("alarm=0, connection_error=3,82=4")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tests := map[int]int{
+		constants.ExitCodeControlsAlarm:            0,
+		constants.ExitCodeControlsError:            constants.ExitCodeControlsError,
+		constants.ExitCodeConnectionStateFailed:    3,
+		constants.ExitCodeDatabaseConnectionFailed: 4,
+		constants.ExitCodeSuccessful:               constants.ExitCodeSuccessful,
+	}
+	for exitCode, expected := range tests {
+		if res := mapExitCode(exitCode, exitCodeMap); res != expected {
+			t.Errorf("exit code %d: expected %d, got %d", exitCode, expected, res)
+		}
+	}
+}
+
+func TestParseExitCodeMapInvalid(t *testing.T) {
+	for _, exitCodeMap := range []string{"alarm", "unknown=1", "alarm=x", "alarm=256", "=1"} {
+		if _, err := ParseExitCodeMap(exitCodeMap); err == nil {
+			t.Errorf("expected error parsing '%s'", exitCodeMap)
+		}
+	}
+}
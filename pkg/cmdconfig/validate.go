@@ -8,6 +8,8 @@ import (
 	"github.com/turbot/steampipe/pkg/cloud"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/network"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"strings"
 )
@@ -25,6 +27,11 @@ func ValidateSnapshotArgs(ctx context.Context) error {
 		return nil
 	}
 
+	// in offline mode, snapshots may only be saved to a local file location
+	if err := validateSnapshotOffline(share); err != nil {
+		return err
+	}
+
 	token := viper.GetString(constants.ArgPipesToken)
 
 	// determine whether snapshot location is a cloud workspace or a file location
@@ -34,7 +41,7 @@ func ValidateSnapshotArgs(ctx context.Context) error {
 	}
 
 	// if workspace-database or snapshot-location are a cloud workspace handle, cloud token must be set
-	requireCloudToken := steampipeconfig.IsCloudWorkspaceIdentifier(viper.GetString(constants.ArgWorkspaceDatabase)) ||
+	requireCloudToken := steampipeconfig.IsCloudWorkspaceDatabase(viper.GetString(constants.ArgWorkspaceDatabase)) ||
 		steampipeconfig.IsCloudWorkspaceIdentifier(viper.GetString(constants.ArgSnapshotLocation))
 
 	// verify cloud token and workspace has been set
@@ -61,6 +68,11 @@ func validateSnapshotLocation(ctx context.Context, cloudToken string) error {
 		return setSnapshotLocationFromDefaultWorkspace(ctx, cloudToken)
 	}
 
+	// if it is a cloud object store location (e.g. s3://bucket/snapshots), there is nothing to validate
+	if export.IsRemoteDestination(snapshotLocation) {
+		return nil
+	}
+
 	// if it is NOT a workspace handle, assume it is a local file location:
 	// tildefy it and ensure it exists
 	if !steampipeconfig.IsCloudWorkspaceIdentifier(snapshotLocation) {
@@ -80,6 +92,17 @@ func validateSnapshotLocation(ctx context.Context, cloudToken string) error {
 	return nil
 }
 
+func validateSnapshotOffline(share bool) error {
+	snapshotLocation := viper.GetString(constants.ArgSnapshotLocation)
+	if share || snapshotLocation == "" || steampipeconfig.IsCloudWorkspaceIdentifier(snapshotLocation) {
+		return network.CheckOnline("uploading a snapshot to Turbot Pipes")
+	}
+	if export.IsRemoteDestination(snapshotLocation) {
+		return network.CheckOnline("uploading a snapshot to a remote location")
+	}
+	return nil
+}
+
 func setSnapshotLocationFromDefaultWorkspace(ctx context.Context, cloudToken string) error {
 	workspaceHandle, err := cloud.GetUserWorkspaceHandle(ctx, cloudToken)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"github.com/turbot/steampipe/pkg/filepaths"
 	"log"
 	"os"
+	"strings"
 
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
@@ -111,6 +112,8 @@ func setBaseDefaults() error {
 		constants.ArgDatabaseStartTimeout: constants.DBStartTimeout.Seconds(),
 		constants.ArgServiceCacheEnabled:  true,
 		constants.ArgCacheMaxTtl:          300,
+		constants.ArgMaintenanceInterval:  constants.DefaultMaintenanceInterval,
+		constants.ArgStateStorage:         constants.StateStorageFile,
 
 		// dashboard
 		constants.ArgDashboardStartTimeout: constants.DashboardStartTimeout.Seconds(),
@@ -118,6 +121,10 @@ func setBaseDefaults() error {
 		// memory
 		constants.ArgMemoryMaxMbPlugin: 1024,
 		constants.ArgMemoryMaxMb:       1024,
+
+		// garbage collection
+		constants.ArgLogRetentionDays:      7,
+		constants.ArgTempDirRetentionHours: 24,
 	}
 
 	for k, v := range defaults {
@@ -144,47 +151,12 @@ func setDirectoryDefaultsFromEnv() {
 	}
 }
 
-// setDefaultsFromEnv sets default values from env vars
-func setDefaultsFromEnv() {
+// setDefaultsFromEnv sets default values from the env vars in the registry and the env vars generated for the command flags
+func setDefaultsFromEnv(cmd *cobra.Command) {
 	// NOTE: EnvWorkspaceProfile has already been set as a viper default as we have already loaded workspace profiles
 	// (EnvInstallDir has already been set at same time but we set it again to make sure it has the correct precedence)
-
-	// a map of known environment variables to map to viper keys
-	envMappings := map[string]envMapping{
-		constants.EnvInstallDir:     {[]string{constants.ArgInstallDir}, String},
-		constants.EnvWorkspaceChDir: {[]string{constants.ArgModLocation}, String},
-		constants.EnvModLocation:    {[]string{constants.ArgModLocation}, String},
-		constants.EnvIntrospection:  {[]string{constants.ArgIntrospection}, String},
-		constants.EnvTelemetry:      {[]string{constants.ArgTelemetry}, String},
-		constants.EnvUpdateCheck:    {[]string{constants.ArgUpdateCheck}, Bool},
-		// deprecated
-		constants.EnvCloudHost:             {[]string{constants.ArgPipesHost}, String},
-		constants.EnvCloudToken:            {[]string{constants.ArgPipesToken}, String},
-		constants.EnvPipesHost:             {[]string{constants.ArgPipesHost}, String},
-		constants.EnvPipesToken:            {[]string{constants.ArgPipesToken}, String},
-		constants.EnvSnapshotLocation:      {[]string{constants.ArgSnapshotLocation}, String},
-		constants.EnvWorkspaceDatabase:     {[]string{constants.ArgWorkspaceDatabase}, String},
-		constants.EnvServicePassword:       {[]string{constants.ArgServicePassword}, String},
-		constants.EnvDisplayWidth:          {[]string{constants.ArgDisplayWidth}, Int},
-		constants.EnvMaxParallel:           {[]string{constants.ArgMaxParallel}, Int},
-		constants.EnvQueryTimeout:          {[]string{constants.ArgDatabaseQueryTimeout}, Int},
-		constants.EnvDatabaseStartTimeout:  {[]string{constants.ArgDatabaseStartTimeout}, Int},
-		constants.EnvDatabaseSSLPassword:   {[]string{constants.ArgDatabaseSSLPassword}, String},
-		constants.EnvDashboardStartTimeout: {[]string{constants.ArgDashboardStartTimeout}, Int},
-		constants.EnvCacheTTL:              {[]string{constants.ArgCacheTtl}, Int},
-		constants.EnvCacheMaxTTL:           {[]string{constants.ArgCacheMaxTtl}, Int},
-		constants.EnvMemoryMaxMb:           {[]string{constants.ArgMemoryMaxMb}, Int},
-		constants.EnvMemoryMaxMbPlugin:     {[]string{constants.ArgMemoryMaxMbPlugin}, Int},
-
-		// we need this value to go into different locations
-		constants.EnvCacheEnabled: {[]string{
-			constants.ArgClientCacheEnabled,
-			constants.ArgServiceCacheEnabled,
-		}, Bool},
-	}
-
-	for envVar, v := range envMappings {
-		setConfigFromEnv(envVar, v.configVar, v.varType)
+	for _, b := range EnvVarBindings(cmd) {
+		setConfigFromEnv(b.EnvVar, b.ConfigKeys, b.Type)
 	}
 }
 
@@ -207,6 +179,14 @@ func SetDefaultFromEnv(k string, configVar string, varType EnvVarType) {
 			if intVal, err := types.ToInt64(val); err == nil {
 				viper.SetDefault(configVar, intVal)
 			}
+		case StringSlice:
+			var values []string
+			for _, v := range strings.Split(val, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			viper.SetDefault(configVar, values)
 		default:
 			// must be an invalid value in the map above
 			panic(fmt.Sprintf("invalid env var mapping type: %s", varType))
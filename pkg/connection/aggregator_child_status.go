@@ -0,0 +1,61 @@
+package connection
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/introspection"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"golang.org/x/exp/maps"
+)
+
+// updateAggregatorChildStatus records the child connections which are included in and excluded from each aggregator
+// connection in the connection state table, so users can see why an aggregator is missing data
+// the options the view schema of each aggregator was created with are also recorded (see updateAggregatorViews)
+// NOTE: failures are added as warnings to the refresh result
+func (s *refreshConnectionState) updateAggregatorChildStatus(ctx context.Context) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		s.res.AddWarning("failed to update aggregator child connection status: " + err.Error())
+		return
+	}
+	defer conn.Release()
+
+	// load the current state - connections may have failed during the update
+	stateMap, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn())
+	if err != nil {
+		s.res.AddWarning("failed to update aggregator child connection status: " + err.Error())
+		return
+	}
+
+	var queries []db_common.QueryWithArgs
+	connectionMap := steampipeconfig.GetGlobalConfig().Connections
+	for name, connection := range connectionMap {
+		if connection.Type != modconfig.ConnectionTypeAggregator {
+			continue
+		}
+		included, excluded := steampipeconfig.AggregatorChildStatus(connection, connectionMap, stateMap)
+		if len(excluded) > 0 {
+			log.Printf("[INFO] aggregator connection '%s' excludes child connections: %s", name, strings.Join(maps.Keys(excluded), ","))
+		}
+		aggregatorViews := s.aggregatorViews[name]
+		queries = append(queries, introspection.GetSetAggregatorChildStatusSql(name, included, excluded, aggregatorViews)...)
+
+		// also update the final state, which is written to the connection state file
+		if finalState, ok := s.connectionUpdates.FinalConnectionState[name]; ok {
+			finalState.IncludedConnections = included
+			finalState.ExcludedConnections = excluded
+			finalState.AggregatorViews = aggregatorViews
+		}
+	}
+	if len(queries) == 0 {
+		return
+	}
+	if _, err := db_local.ExecuteSqlWithArgsInTransaction(ctx, conn.Conn(), queries...); err != nil {
+		s.res.AddWarning("failed to update aggregator child connection status: " + err.Error())
+	}
+}
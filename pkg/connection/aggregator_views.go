@@ -0,0 +1,142 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// updateAggregatorViews (re)creates the view schema for all aggregator connections which set
+// connection_column or dedup_keys, and drops the view schema for any other aggregator or deleted connection
+//
+// the views select from the aggregator schema - as this is dropped and recreated when the connection is updated
+// (dropping the dependent views), the views of an aggregator are recreated if it or any of its children is updated,
+// or if its view options have changed since the views were created (as recorded in the connection state)
+// the options of the views of each aggregator are then recorded by updateAggregatorChildStatus
+// NOTE: failures are added as warnings to the refresh result
+func (s *refreshConnectionState) updateAggregatorViews(ctx context.Context) {
+	s.aggregatorViews = make(map[string]string)
+	var errors []error
+	for _, connection := range steampipeconfig.GetGlobalConfig().Connections {
+		if connection.Type != modconfig.ConnectionTypeAggregator {
+			continue
+		}
+		aggregatorViews := s.requiredAggregatorViews(connection)
+		recordedViews := s.recordedAggregatorViews(connection.Name)
+		if !s.aggregatorViewsUpdateRequired(connection, aggregatorViews, recordedViews) {
+			s.aggregatorViews[connection.Name] = recordedViews
+			continue
+		}
+		if err := s.updateAggregatorViewsForConnection(ctx, connection, aggregatorViews); err != nil {
+			// the views may be out of date - make sure they are recreated by the next refresh
+			s.aggregatorViews[connection.Name] = ""
+			errors = append(errors, err)
+			continue
+		}
+		s.aggregatorViews[connection.Name] = aggregatorViews
+	}
+
+	// drop the view schemas of any deleted connections
+	for connectionName := range s.connectionUpdates.Delete {
+		if _, err := s.pool.Exec(ctx, db_common.GetDeleteAggregatorViewsQuery(connectionName)); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	if len(errors) > 0 {
+		err := error_helpers.CombineErrorsWithPrefix("failed to update aggregator views", errors...)
+		log.Printf("[WARN] %s", err.Error())
+		s.res.AddWarning(err.Error())
+	}
+}
+
+// requiredAggregatorViews returns the options the view schema of the aggregator must be created with
+// (empty if the aggregator must not have a view schema, e.g. as it is not ready)
+func (s *refreshConnectionState) requiredAggregatorViews(connection *modconfig.Connection) string {
+	connectionState, ok := s.connectionUpdates.FinalConnectionState[connection.Name]
+	if !ok || connectionState.State != constants.ConnectionStateReady {
+		return ""
+	}
+	return connection.AggregatorViewsOptions()
+}
+
+// recordedAggregatorViews returns the options the existing view schema of the aggregator was created with
+// (empty if it has no view schema)
+func (s *refreshConnectionState) recordedAggregatorViews(connectionName string) string {
+	if currentState, ok := s.connectionUpdates.CurrentConnectionState[connectionName]; ok {
+		return currentState.AggregatorViews
+	}
+	return ""
+}
+
+// aggregatorViewsUpdateRequired returns whether the view schema of the aggregator must be recreated or dropped
+func (s *refreshConnectionState) aggregatorViewsUpdateRequired(connection *modconfig.Connection, aggregatorViews, recordedViews string) bool {
+	if aggregatorViews != recordedViews {
+		return true
+	}
+	// if the aggregator has no views, there is nothing to recreate
+	if aggregatorViews == "" {
+		return false
+	}
+	// the views are dropped if the aggregator schema is recreated, and the aggregator tables may change if a child is updated
+	if _, updated := s.connectionUpdates.Update[connection.Name]; updated {
+		return true
+	}
+	for childName := range connection.Connections {
+		if _, updated := s.connectionUpdates.Update[childName]; updated {
+			return true
+		}
+		if _, deleted := s.connectionUpdates.Delete[childName]; deleted {
+			return true
+		}
+	}
+	return false
+}
+
+// updateAggregatorViewsForConnection creates the view schema of the aggregator with the given options,
+// or drops it if no options are given
+func (s *refreshConnectionState) updateAggregatorViewsForConnection(ctx context.Context, connection *modconfig.Connection, aggregatorViews string) error {
+	if aggregatorViews == "" {
+		_, err := s.pool.Exec(ctx, db_common.GetDeleteAggregatorViewsQuery(connection.Name))
+		return err
+	}
+
+	log.Printf("[INFO] updating aggregator views for connection '%s'", connection.Name)
+	tableColumns, err := s.getTableColumns(ctx, connection.Name)
+	if err != nil {
+		return err
+	}
+
+	sql := db_common.GetAggregatorViewsQuery(connection.Name, tableColumns, connection.ConnectionColumn, connection.DedupKeys)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		_ = tx.Rollback(ctx)
+		return fmt.Errorf("connection '%s': %s", connection.Name, err.Error())
+	}
+	return tx.Commit(ctx)
+}
+
+// getTableColumns returns a map of table name to column names for all tables in the given schema
+func (s *refreshConnectionState) getTableColumns(ctx context.Context, schemaName string) (map[string][]string, error) {
+	rows, err := s.pool.Query(ctx, "select table_name, column_name from information_schema.columns where table_schema = $1 order by table_name, ordinal_position", schemaName)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]string)
+	var tableName, columnName string
+	_, err = pgx.ForEachRow(rows, []any{&tableName, &columnName}, func() error {
+		res[tableName] = append(res[tableName], columnName)
+		return nil
+	})
+	return res, err
+}
@@ -0,0 +1,87 @@
+package connection
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestAggregatorViewsUpdateRequired(t *testing.T) {
+	aggregator := &modconfig.Connection{
+		Name:             "aws_all",
+		Type:             modconfig.ConnectionTypeAggregator,
+		ConnectionColumn: true,
+		Connections: map[string]*modconfig.Connection{
+			"aws_dev":  {Name: "aws_dev"},
+			"aws_prod": {Name: "aws_prod"},
+		},
+	}
+	views := aggregator.AggregatorViewsOptions()
+	dedupViews := (&modconfig.Connection{Type: modconfig.ConnectionTypeAggregator, ConnectionColumn: true, DedupKeys: []string{"arn"}}).AggregatorViewsOptions()
+
+	tests := map[string]struct {
+		state    string
+		recorded string
+		update   []string
+		delete   []string
+		expected bool
+	}{
+		"unchanged":               {state: constants.ConnectionStateReady, recorded: views},
+		"unrelated update":        {state: constants.ConnectionStateReady, recorded: views, update: []string{"gcp"}, delete: []string{"azure"}},
+		"aggregator updated":      {state: constants.ConnectionStateReady, recorded: views, update: []string{"aws_all"}, expected: true},
+		"child updated":           {state: constants.ConnectionStateReady, recorded: views, update: []string{"aws_dev"}, expected: true},
+		"child deleted":           {state: constants.ConnectionStateReady, recorded: views, delete: []string{"aws_prod"}, expected: true},
+		"options changed":         {state: constants.ConnectionStateReady, recorded: dedupViews, expected: true},
+		"views not created":       {state: constants.ConnectionStateReady, recorded: "", expected: true},
+		"aggregator in error":     {state: constants.ConnectionStateError, recorded: views, expected: true},
+		"error without views":     {state: constants.ConnectionStateError, recorded: ""},
+		"error with child update": {state: constants.ConnectionStateError, recorded: "", update: []string{"aws_dev"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			updates := &steampipeconfig.ConnectionUpdates{
+				Update: steampipeconfig.ConnectionStateMap{},
+				Delete: make(map[string]struct{}),
+				FinalConnectionState: steampipeconfig.ConnectionStateMap{
+					"aws_all": {ConnectionName: "aws_all", State: test.state},
+				},
+				CurrentConnectionState: steampipeconfig.ConnectionStateMap{
+					"aws_all": {ConnectionName: "aws_all", State: constants.ConnectionStateReady, AggregatorViews: test.recorded},
+				},
+			}
+			for _, connectionName := range test.update {
+				updates.Update[connectionName] = &steampipeconfig.ConnectionState{ConnectionName: connectionName}
+			}
+			for _, connectionName := range test.delete {
+				updates.Delete[connectionName] = struct{}{}
+			}
+			s := &refreshConnectionState{connectionUpdates: updates}
+
+			required := s.requiredAggregatorViews(aggregator)
+			if got := s.aggregatorViewsUpdateRequired(aggregator, required, s.recordedAggregatorViews(aggregator.Name)); got != test.expected {
+				t.Errorf("expected update required %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestAggregatorViewsOptions(t *testing.T) {
+	tests := map[string]struct {
+		connection *modconfig.Connection
+		expected   string
+	}{
+		"no views":          {connection: &modconfig.Connection{Type: modconfig.ConnectionTypeAggregator}},
+		"not an aggregator": {connection: &modconfig.Connection{ConnectionColumn: true}},
+		"connection column": {connection: &modconfig.Connection{Type: modconfig.ConnectionTypeAggregator, ConnectionColumn: true}, expected: "connection_column=true;dedup_keys="},
+		"dedup keys":        {connection: &modconfig.Connection{Type: modconfig.ConnectionTypeAggregator, DedupKeys: []string{"arn", "region"}}, expected: "connection_column=false;dedup_keys=arn,region"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.connection.AggregatorViewsOptions(); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// updateColumnMasks applies the mask_columns and masking_policy config of all connections, hiding the masked columns
+// from non-admin database users (and service users) using column level privileges
+// for connections which use a masking policy other than hide, a schema of masked views is also (re)created, in which
+// the masked columns are redacted, and the masked view schema is dropped for any other or deleted connection
+//
+// this is applied to every ready connection after each refresh, as the connection schema (and its privileges)
+// may have been recreated - this also restores access to columns which are no longer masked
+// NOTE: failures are added as warnings to the refresh result
+func (s *refreshConnectionState) updateColumnMasks(ctx context.Context) {
+	var errors []error
+	serviceUsers, err := s.getServiceUsersByConnection(ctx)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	// build a single query to restore access to all unmasked connections
+	var unmaskedQueries strings.Builder
+	for _, connection := range steampipeconfig.GetGlobalConfig().Connections {
+		connectionState, ok := s.connectionUpdates.FinalConnectionState[connection.Name]
+		if !ok || connectionState.State != constants.ConnectionStateReady {
+			if err := s.deleteMaskedViews(ctx, connection.Name); err != nil {
+				errors = append(errors, err)
+			}
+			continue
+		}
+
+		roles := append([]string{constants.DatabaseUsersRole}, serviceUsers[connection.Name]...)
+		maskColumns := connection.GetMaskColumns()
+		if len(maskColumns) == 0 {
+			unmaskedQueries.WriteString(db_common.GetColumnMaskQuery(connection.Name, nil, nil, roles))
+			unmaskedQueries.WriteString(db_common.GetDeleteMaskedViewsQuery(connection.Name))
+			continue
+		}
+		if err := s.updateColumnMasksForConnection(ctx, connection, maskColumns, roles); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if unmaskedQueries.Len() > 0 {
+		if _, err := s.pool.Exec(ctx, unmaskedQueries.String()); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	// drop the masked view schemas of any deleted connections
+	for connectionName := range s.connectionUpdates.Delete {
+		if err := s.deleteMaskedViews(ctx, connectionName); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	if len(errors) > 0 {
+		err := error_helpers.CombineErrorsWithPrefix("failed to update column masks", errors...)
+		log.Printf("[WARN] %s", err.Error())
+		s.res.AddWarning(err.Error())
+	}
+}
+
+// updateColumnMasksForConnection hides the masked columns of the connection from the given roles and,
+// if required by the masking policy, recreates the masked view schema of the connection
+func (s *refreshConnectionState) updateColumnMasksForConnection(ctx context.Context, connection *modconfig.Connection, maskColumns []string, roles []string) error {
+	log.Printf("[INFO] masking columns %v for connection '%s' using policy '%s'", maskColumns, connection.Name, connection.GetMaskingPolicy())
+
+	tableColumns, err := s.getTableColumnTypes(ctx, connection.Name)
+	if err != nil {
+		return err
+	}
+
+	var sql strings.Builder
+	sql.WriteString(db_common.GetColumnMaskQuery(connection.Name, getColumnNames(tableColumns), maskColumns, roles))
+	// the aggregator views would otherwise expose the masked columns
+	// (service users are not granted access to the aggregator view schema)
+	if connection.RequiresAggregatorViews() {
+		viewSchemaName := db_common.GetAggregatorViewSchemaName(connection.Name)
+		viewTableColumns, err := s.getTableColumns(ctx, viewSchemaName)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(db_common.GetColumnMaskQuery(viewSchemaName, viewTableColumns, maskColumns, []string{constants.DatabaseUsersRole}))
+	}
+	if connection.RequiresMaskedViews() {
+		sql.WriteString(db_common.GetMaskedViewsQuery(connection.Name, tableColumns, maskColumns, connection.GetMaskingPolicy(), roles))
+	} else {
+		sql.WriteString(db_common.GetDeleteMaskedViewsQuery(connection.Name))
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql.String()); err != nil {
+		_ = tx.Rollback(ctx)
+		return fmt.Errorf("connection '%s': %s", connection.Name, err.Error())
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *refreshConnectionState) deleteMaskedViews(ctx context.Context, connectionName string) error {
+	_, err := s.pool.Exec(ctx, db_common.GetDeleteMaskedViewsQuery(connectionName))
+	return err
+}
+
+// getTableColumnTypes returns a map of table name to the columns (with their data types) for all tables in the given schema
+func (s *refreshConnectionState) getTableColumnTypes(ctx context.Context, schemaName string) (map[string][]db_common.TableColumn, error) {
+	rows, err := s.pool.Query(ctx, "select table_name, column_name, data_type from information_schema.columns where table_schema = $1 order by table_name, ordinal_position", schemaName)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]db_common.TableColumn)
+	var tableName string
+	var column db_common.TableColumn
+	_, err = pgx.ForEachRow(rows, []any{&tableName, &column.Name, &column.DataType}, func() error {
+		res[tableName] = append(res[tableName], column)
+		return nil
+	})
+	return res, err
+}
+
+// getServiceUsersByConnection returns a map of connection name to the service users which may access the connection
+func (s *refreshConnectionState) getServiceUsersByConnection(ctx context.Context) (map[string][]string, error) {
+	query := fmt.Sprintf("select name, connections from %s.%s order by name", constants.InternalSchema, constants.ServiceUserTable)
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]string)
+	var name string
+	var connections []string
+	_, err = pgx.ForEachRow(rows, []any{&name, &connections}, func() error {
+		for _, connectionName := range connections {
+			res[connectionName] = append(res[connectionName], name)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func getColumnNames(tableColumns map[string][]db_common.TableColumn) map[string][]string {
+	res := make(map[string][]string, len(tableColumns))
+	for table, columns := range tableColumns {
+		for _, c := range columns {
+			res[table] = append(res[table], c.Name)
+		}
+	}
+	return res
+}
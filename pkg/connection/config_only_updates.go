@@ -0,0 +1,85 @@
+package connection
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// getConfigOnlyUpdates returns the names of the connections whose plugin specific config has changed,
+// if these are the ONLY changes between the previous and updated config
+// (any other change - e.g. an added or deleted connection, a changed plugin or a changed search path - requires a full refresh)
+func getConfigOnlyUpdates(prev, updated *steampipeconfig.SteampipeConfig) ([]string, bool) {
+	if prev == nil || updated == nil || !searchPathOptionsEqual(prev, updated) {
+		return nil, false
+	}
+	if len(prev.Connections) != len(updated.Connections) {
+		return nil, false
+	}
+
+	var configUpdates []string
+	for name, updatedConnection := range updated.Connections {
+		prevConnection, ok := prev.Connections[name]
+		if !ok {
+			return nil, false
+		}
+		if (prevConnection.Error == nil) != (updatedConnection.Error == nil) {
+			return nil, false
+		}
+		if prevConnection.Equals(updatedConnection) {
+			continue
+		}
+		if !prevConnection.ConfigOnlyChanged(updatedConnection) {
+			return nil, false
+		}
+		configUpdates = append(configUpdates, name)
+	}
+	return configUpdates, len(configUpdates) > 0
+}
+
+func searchPathOptionsEqual(prev, updated *steampipeconfig.SteampipeConfig) bool {
+	if (prev.DatabaseOptions == nil) != (updated.DatabaseOptions == nil) {
+		return false
+	}
+	if prev.DatabaseOptions == nil {
+		return true
+	}
+	return typehelpers.SafeString(prev.DatabaseOptions.SearchPath) == typehelpers.SafeString(updated.DatabaseOptions.SearchPath) &&
+		typehelpers.SafeString(prev.DatabaseOptions.SearchPathPrefix) == typehelpers.SafeString(updated.DatabaseOptions.SearchPathPrefix)
+}
+
+// canSkipRefreshForConfigUpdates returns whether the given config updates can be applied by the plugin alone,
+// without refreshing connections
+// this is the case if all updated connections are ready and have a static schema
+// (for a dynamic schema, the config may determine the schema so a refresh is required)
+func canSkipRefreshForConfigUpdates(ctx context.Context, pool *pgxpool.Pool, configUpdates []string) bool {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to acquire connection from pool: %s", err.Error())
+		return false
+	}
+	defer conn.Release()
+
+	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn())
+	if err != nil {
+		log.Printf("[WARN] failed to load connection state: %s", err.Error())
+		return false
+	}
+
+	for _, name := range configUpdates {
+		connectionState, ok := connectionStateMap[name]
+		if !ok {
+			return false
+		}
+		if connectionState.State != constants.ConnectionStateReady || connectionState.SchemaMode != plugin.SchemaModeStatic {
+			log.Printf("[TRACE] connection %s is %s with schema mode '%s' - refresh required", name, connectionState.State, connectionState.SchemaMode)
+			return false
+		}
+	}
+	return true
+}
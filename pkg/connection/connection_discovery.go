@@ -0,0 +1,151 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// ConnectionDiscoveryResult is the result of syncing the connections of a connection discovery
+type ConnectionDiscoveryResult struct {
+	Name string
+	// the generated config file containing the discovered connections
+	FilePath    string
+	Connections []string
+	Added       []string
+	Removed     []string
+	// discovered connections which were not created as a connection with the same name is already defined
+	Skipped []string
+	Error   error
+}
+
+// SyncConnectionDiscoveries executes all connection discoveries in the config, and writes the discovered connections
+// to the generated config file of each discovery (if they have changed)
+// if the service is running, the connection watcher will then pick up the changes
+func SyncConnectionDiscoveries(ctx context.Context, conn *pgx.Conn, config *steampipeconfig.SteampipeConfig) []*ConnectionDiscoveryResult {
+	var res []*ConnectionDiscoveryResult
+	for _, name := range utils.SortedMapKeys(config.ConnectionDiscoveries) {
+		res = append(res, syncConnectionDiscovery(ctx, conn, config, config.ConnectionDiscoveries[name]))
+	}
+	return res
+}
+
+func syncConnectionDiscovery(ctx context.Context, conn *pgx.Conn, config *steampipeconfig.SteampipeConfig, discovery *modconfig.ConnectionDiscovery) *ConnectionDiscoveryResult {
+	filePath := steampipeconfig.DiscoveredConnectionsFilePath(discovery.Name)
+	res := &ConnectionDiscoveryResult{Name: discovery.Name, FilePath: filePath}
+
+	source, ok := config.Connections[discovery.Connection]
+	if !ok {
+		res.Error = sperr.New("discovery '%s': connection '%s' does not exist", discovery.Name, discovery.Connection)
+		return res
+	}
+	if source.Type == modconfig.ConnectionTypeAggregator {
+		res.Error = sperr.New("discovery '%s': connection '%s' is an aggregator - discovery must use a plugin connection", discovery.Name, discovery.Connection)
+		return res
+	}
+	query, err := discovery.GetQuery(source.Plugin)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+
+	items, err := executeDiscoveryQuery(ctx, conn, discovery.Connection, query)
+	if err != nil {
+		res.Error = sperr.WrapWithMessage(err, "discovery '%s': query failed", discovery.Name)
+		return res
+	}
+	discovered, err := steampipeconfig.NewDiscoveredConnections(discovery, items)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+
+	// do not create connections which are already defined in other config files (or the discovering connection itself)
+	// - this would cause a duplicate connection error when loading the config
+	discovered = slices.DeleteFunc(discovered, func(c *steampipeconfig.DiscoveredConnection) bool {
+		if existing, ok := config.Connections[c.Name]; ok && existing.DeclRange.Filename != filePath {
+			res.Skipped = append(res.Skipped, c.Name)
+			return true
+		}
+		return false
+	})
+
+	previous := config.DiscoveredConnectionNames(discovery.Name)
+	for _, c := range discovered {
+		res.Connections = append(res.Connections, c.Name)
+		if !slices.Contains(previous, c.Name) {
+			res.Added = append(res.Added, c.Name)
+		}
+	}
+	for _, name := range previous {
+		if !slices.Contains(res.Connections, name) {
+			res.Removed = append(res.Removed, name)
+		}
+	}
+
+	// only write the file if it has changed, to avoid needlessly triggering the connection watcher
+	content := steampipeconfig.BuildDiscoveredConnectionsConfig(discovery, source, discovered)
+	if existing, err := os.ReadFile(filePath); err == nil && string(existing) == content {
+		return res
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		res.Error = sperr.WrapWithMessage(err, "discovery '%s': failed to write discovered connections", discovery.Name)
+	}
+	return res
+}
+
+// executeDiscoveryQuery executes the discovery query against the schema of the discovering connection
+// and returns the rows as maps of column name to value
+func executeDiscoveryQuery(ctx context.Context, conn *pgx.Conn, connectionName, query string) ([]map[string]string, error) {
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	// the transaction is read only - always roll back
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// set the search path so the query may use unqualified table names
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL search_path TO %s", db_common.PgEscapeName(connectionName))); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	if !slices.ContainsFunc(fields, func(f pgconn.FieldDescription) bool { return f.Name == "id" }) {
+		return nil, sperr.New("the discovery query must return an 'id' column")
+	}
+
+	var res []map[string]string
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		item := make(map[string]string, len(fields))
+		for i, f := range fields {
+			if values[i] != nil {
+				item[f.Name] = fmt.Sprint(values[i])
+			} else {
+				item[f.Name] = ""
+			}
+		}
+		res = append(res, item)
+	}
+	return res, rows.Err()
+}
@@ -33,7 +33,8 @@ func (u *connectionStateTableUpdater) start(ctx context.Context) error {
 	log.Println("[DEBUG] connectionStateTableUpdater.start start")
 	defer log.Println("[DEBUG] connectionStateTableUpdater.start end")
 
-	var queries []db_common.QueryWithArgs
+	// prune old connection state history
+	queries := []db_common.QueryWithArgs{introspection.GetConnectionStateHistoryPruneSql()}
 
 	// update the conection state table to set appropriate state for all connections
 	// set updates to "updating"
@@ -42,10 +43,12 @@ func (u *connectionStateTableUpdater) start(ctx context.Context) error {
 		if _, updatingConnection := u.updates.Update[name]; updatingConnection {
 			connectionState.State = constants.ConnectionStateUpdating
 			connectionState.CommentsSet = false
+			queries = append(queries, introspection.GetConnectionStateHistoryInsertSql(name, connectionState.State, nil))
 		} else if validationError, connectionIsInvalid := u.updates.InvalidConnections[name]; connectionIsInvalid {
 			// if this connection has an error, set to error
 			connectionState.State = constants.ConnectionStateError
 			connectionState.ConnectionError = &validationError.Message
+			queries = append(queries, introspection.GetConnectionStateHistoryInsertSql(name, connectionState.State, connectionState.ConnectionError))
 		}
 		// get the sql to update the connection state in the table to match the struct
 		queries = append(queries, introspection.GetUpsertConnectionStateSql(connectionState)...)
@@ -59,12 +62,14 @@ func (u *connectionStateTableUpdater) start(ctx context.Context) error {
 		}
 
 		queries = append(queries, introspection.GetSetConnectionStateSql(name, constants.ConnectionStateDeleting)...)
+		queries = append(queries, introspection.GetConnectionStateHistoryInsertSql(name, constants.ConnectionStateDeleting, nil))
 	}
 
 	// set any connections with import_schema=disabled to "disabled"
 	// also build a lookup of disabled connections
 	for name := range u.updates.Disabled {
 		queries = append(queries, introspection.GetSetConnectionStateSql(name, constants.ConnectionStateDisabled)...)
+		queries = append(queries, introspection.GetConnectionStateHistoryInsertSql(name, constants.ConnectionStateDisabled, nil))
 	}
 	conn, err := u.pool.Acquire(ctx)
 	if err != nil {
@@ -83,6 +88,7 @@ func (u *connectionStateTableUpdater) onConnectionReady(ctx context.Context, con
 
 	connection := u.updates.FinalConnectionState[name]
 	queries := introspection.GetSetConnectionStateSql(connection.ConnectionName, constants.ConnectionStateReady)
+	queries = append(queries, introspection.GetConnectionStateHistoryInsertSql(connection.ConnectionName, constants.ConnectionStateReady, nil))
 	for _, q := range queries {
 		if _, err := conn.Exec(ctx, q.Query, q.Args...); err != nil {
 			return err
@@ -127,6 +133,8 @@ func (u *connectionStateTableUpdater) onConnectionError(ctx context.Context, con
 	defer log.Println("[DEBUG] connectionStateTableUpdater.onConnectionError end")
 
 	queries := introspection.GetConnectionStateErrorSql(connectionName, err)
+	errorString := err.Error()
+	queries = append(queries, introspection.GetConnectionStateHistoryInsertSql(connectionName, constants.ConnectionStateError, &errorString))
 	for _, q := range queries {
 		if _, err := conn.Exec(ctx, q.Query, q.Args...); err != nil {
 			return err
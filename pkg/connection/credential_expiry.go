@@ -0,0 +1,34 @@
+package connection
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// credentialExpiredConnections returns the (sorted) connections which failed to refresh because their credentials have expired
+// (the schema import calls GetSchema on the plugin, which fails if the credentials of a dynamic schema connection have expired)
+func credentialExpiredConnections(res *steampipeconfig.RefreshConnectionResult) []string {
+	expired := make(map[string]struct{})
+	for connectionName, failure := range res.FailedConnections {
+		if error_helpers.IsCredentialExpiryError(failure) {
+			expired[connectionName] = struct{}{}
+		}
+	}
+	return utils.SortedMapKeys(expired)
+}
+
+// notifyCredentialExpiry notifies the plugin manager of any connections which failed because their credentials have expired,
+// so it can re-resolve their config and restart their sessions
+func notifyCredentialExpiry(ctx context.Context, pluginManager pluginManager, res *steampipeconfig.RefreshConnectionResult) {
+	expired := credentialExpiredConnections(res)
+	if len(expired) == 0 {
+		return
+	}
+	log.Printf("[INFO] the credentials of %s have expired", strings.Join(expired, ","))
+	pluginManager.OnConnectionCredentialsExpired(ctx, expired)
+}
@@ -0,0 +1,22 @@
+package connection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+func TestCredentialExpiredConnections(t *testing.T) {
+	res := &steampipeconfig.RefreshConnectionResult{}
+	res.AddFailedConnection("aws_prod", "rpc error: ExpiredToken: The security token included in the request is expired")
+	res.AddFailedConnection("aws_dev", "rpc error: ExpiredTokenException")
+	res.AddFailedConnection("github", "401 Bad credentials")
+
+	if got := strings.Join(credentialExpiredConnections(res), ","); got != "aws_dev,aws_prod" {
+		t.Errorf("expected the connections with expired credentials to be aws_dev,aws_prod, got %s", got)
+	}
+	if got := credentialExpiredConnections(&steampipeconfig.RefreshConnectionResult{}); len(got) != 0 {
+		t.Errorf("expected no connections, got %v", got)
+	}
+}
@@ -0,0 +1,166 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"golang.org/x/exp/maps"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	ConnectionHealthOk      = "ok"
+	ConnectionHealthError   = "error"
+	ConnectionHealthSkipped = "skipped"
+
+	// the maximum number of connections which are probed concurrently
+	maxParallelHealthChecks = 10
+	// the maximum number of tables probed for a connection, when tables fail because they require quals
+	maxHealthCheckProbes = 5
+	// DefaultHealthCheckTimeout is the default time allowed for each connection probe
+	DefaultHealthCheckTimeout = 30 * time.Second
+)
+
+// ConnectionHealth is the result of the health check of a single connection
+type ConnectionHealth struct {
+	Connection string `json:"connection"`
+	Plugin     string `json:"plugin"`
+	// ok, error or skipped
+	Status string `json:"status"`
+	// the table queried by the probe
+	Table string `json:"table,omitempty"`
+	// the duration of the probe query
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthCheckTimeout returns the time allowed for each connection probe, from the --timeout value in seconds
+func HealthCheckTimeout(seconds int) (time.Duration, error) {
+	if seconds <= 0 {
+		return 0, sperr.New("--%s must be greater than 0", constants.ArgProbeTimeout)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// CheckConnectionHealth checks the health of the given connections
+// for each connection, a probe query selects a single row from an exemplar table of the connection (bypassing the cache),
+// which requires the plugin to authenticate with the connection credentials
+// connections which are disabled or not yet loaded are skipped, and connections in error report the connection error
+// the results are returned in the same order as connectionNames
+func CheckConnectionHealth(ctx context.Context, client db_common.Client, connectionStateMap steampipeconfig.ConnectionStateMap, schemaMetadata *db_common.SchemaMetadata, connectionNames []string, timeout time.Duration) []*ConnectionHealth {
+	res := make([]*ConnectionHealth, len(connectionNames))
+	sem := semaphore.NewWeighted(maxParallelHealthChecks)
+	var wg sync.WaitGroup
+	for i, name := range connectionNames {
+		health := &ConnectionHealth{Connection: name}
+		res[i] = health
+
+		state, ok := connectionStateMap[name]
+		if !ok {
+			health.Status = ConnectionHealthSkipped
+			health.Error = "connection state not found"
+			continue
+		}
+		health.Plugin = state.Plugin
+		switch {
+		case state.Disabled():
+			health.Status = ConnectionHealthSkipped
+			health.Error = "connection is disabled"
+			continue
+		case state.State == constants.ConnectionStateError:
+			health.Status = ConnectionHealthError
+			health.Error = typehelpers.SafeString(state.ConnectionError)
+			continue
+		case !state.Loaded():
+			health.Status = ConnectionHealthSkipped
+			health.Error = fmt.Sprintf("connection is %s", state.State)
+			continue
+		}
+
+		tables := healthCheckTables(schemaMetadata, name)
+		if len(tables) == 0 {
+			health.Status = ConnectionHealthSkipped
+			health.Error = "connection has no tables"
+			continue
+		}
+
+		if err := sem.Acquire(ctx, 1); err != nil {
+			health.Status = ConnectionHealthError
+			health.Error = err.Error()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				sem.Release(1)
+				wg.Done()
+			}()
+			probeConnection(ctx, client, health, tables, timeout)
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// probeConnection queries the tables in turn, until a query succeeds or fails for a reason other than missing quals
+func probeConnection(ctx context.Context, client db_common.Client, health *ConnectionHealth, tables []string, timeout time.Duration) {
+	for _, table := range tables {
+		health.Table = table
+		latency, err := probeTable(ctx, client, health.Connection, table, timeout)
+		health.LatencyMs = latency.Milliseconds()
+		if err == nil {
+			health.Status = ConnectionHealthOk
+			health.Error = ""
+			return
+		}
+		health.Status = ConnectionHealthError
+		health.Error = err.Error()
+		// if the table cannot be listed without quals, try the next table
+		if !isMissingQualsError(err) {
+			return
+		}
+		log.Printf("[TRACE] health check of %s: table %s requires quals - trying the next table", health.Connection, table)
+	}
+}
+
+func probeTable(ctx context.Context, client db_common.Client, connectionName, table string, timeout time.Duration) (time.Duration, error) {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("/*+ no_cache */ select 1 from %s.%s limit 1", db_common.PgEscapeName(connectionName), db_common.PgEscapeName(table))
+	start := time.Now()
+	_, err := client.ExecuteSync(probeCtx, query)
+	latency := time.Since(start)
+	if err != nil && probeCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("probe query timed out after %s", timeout)
+	}
+	return latency, err
+}
+
+// healthCheckTables returns the (sorted) tables of the connection which may be probed - at most maxHealthCheckProbes
+func healthCheckTables(schemaMetadata *db_common.SchemaMetadata, connectionName string) []string {
+	if schemaMetadata == nil {
+		return nil
+	}
+	tables := maps.Keys(schemaMetadata.Schemas[connectionName])
+	sort.Strings(tables)
+	if len(tables) > maxHealthCheckProbes {
+		tables = tables[:maxHealthCheckProbes]
+	}
+	return tables
+}
+
+// isMissingQualsError returns whether the error is due to a table which requires key column quals to be listed
+func isMissingQualsError(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "required qual")
+}
@@ -0,0 +1,125 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// probeTestClient is a client whose probe queries of the 'slow' connection block until the probe is cancelled,
+// and whose probe queries of tables named 'keyed_*' fail because the table requires quals
+type probeTestClient struct {
+	db_common.Client
+}
+
+func (c *probeTestClient) ExecuteSync(ctx context.Context, query string, _ ...any) (*queryresult.SyncQueryResult, error) {
+	switch {
+	case strings.Contains(query, `"slow".`):
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case strings.Contains(query, `"keyed_`):
+		return nil, fmt.Errorf("rpc error: please provide a required qual")
+	case strings.Contains(query, `"broken".`):
+		return nil, fmt.Errorf("403 Forbidden")
+	}
+	return &queryresult.SyncQueryResult{}, nil
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	tests := map[string]struct {
+		seconds  int
+		expected time.Duration
+		err      bool
+	}{
+		"default":  {seconds: int(DefaultHealthCheckTimeout.Seconds()), expected: DefaultHealthCheckTimeout},
+		"seconds":  {seconds: 5, expected: 5 * time.Second},
+		"zero":     {seconds: 0, err: true},
+		"negative": {seconds: -1, err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := HealthCheckTimeout(test.seconds)
+			if test.err {
+				if err == nil || !strings.Contains(err.Error(), "--timeout") {
+					t.Errorf("expected a --timeout error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if got != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckConnectionHealth(t *testing.T) {
+	connectionError := "failed to start plugin"
+	connectionStateMap := steampipeconfig.ConnectionStateMap{
+		"ok":       {ConnectionName: "ok", Plugin: "aws", State: constants.ConnectionStateReady},
+		"slow":     {ConnectionName: "slow", Plugin: "aws", State: constants.ConnectionStateReady},
+		"keyed":    {ConnectionName: "keyed", Plugin: "github", State: constants.ConnectionStateReady},
+		"broken":   {ConnectionName: "broken", Plugin: "github", State: constants.ConnectionStateReady},
+		"empty":    {ConnectionName: "empty", Plugin: "aws", State: constants.ConnectionStateReady},
+		"disabled": {ConnectionName: "disabled", Plugin: "aws", State: constants.ConnectionStateDisabled},
+		"pending":  {ConnectionName: "pending", Plugin: "aws", State: constants.ConnectionStatePending},
+		"error":    {ConnectionName: "error", Plugin: "aws", State: constants.ConnectionStateError, ConnectionError: &connectionError},
+	}
+	schemaMetadata := &db_common.SchemaMetadata{Schemas: map[string]map[string]db_common.TableSchema{
+		"ok":     {"aws_account": {}},
+		"slow":   {"aws_account": {}},
+		"keyed":  {"keyed_a": {}, "keyed_b": {}, "user": {}},
+		"broken": {"github_user": {}},
+	}}
+	connectionNames := []string{"ok", "slow", "keyed", "broken", "empty", "disabled", "pending", "error", "unknown"}
+
+	timeout := 50 * time.Millisecond
+	start := time.Now()
+	res := CheckConnectionHealth(context.Background(), &probeTestClient{}, connectionStateMap, schemaMetadata, connectionNames, timeout)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the slow probe to time out after %s, took %s", timeout, elapsed)
+	}
+
+	expected := []ConnectionHealth{
+		{Connection: "ok", Plugin: "aws", Status: ConnectionHealthOk, Table: "aws_account"},
+		{Connection: "slow", Plugin: "aws", Status: ConnectionHealthError, Table: "aws_account", Error: "probe query timed out after 50ms"},
+		{Connection: "keyed", Plugin: "github", Status: ConnectionHealthOk, Table: "user"},
+		{Connection: "broken", Plugin: "github", Status: ConnectionHealthError, Table: "github_user", Error: "403 Forbidden"},
+		{Connection: "empty", Plugin: "aws", Status: ConnectionHealthSkipped, Error: "connection has no tables"},
+		{Connection: "disabled", Plugin: "aws", Status: ConnectionHealthSkipped, Error: "connection is disabled"},
+		{Connection: "pending", Plugin: "aws", Status: ConnectionHealthSkipped, Error: "connection is pending"},
+		{Connection: "error", Plugin: "aws", Status: ConnectionHealthError, Error: connectionError},
+		{Connection: "unknown", Status: ConnectionHealthSkipped, Error: "connection state not found"},
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(res))
+	}
+	for i, health := range res {
+		// the latency is not deterministic
+		health.LatencyMs = 0
+		if *health != expected[i] {
+			t.Errorf("result %d: expected %+v, got %+v", i, expected[i], *health)
+		}
+	}
+}
+
+func TestHealthCheckTables(t *testing.T) {
+	schemaMetadata := &db_common.SchemaMetadata{Schemas: map[string]map[string]db_common.TableSchema{
+		"aws": {"f": {}, "b": {}, "a": {}, "e": {}, "d": {}, "c": {}},
+	}}
+	if got := healthCheckTables(schemaMetadata, "aws"); strings.Join(got, ",") != "a,b,c,d,e" {
+		t.Errorf("expected the first %d sorted tables, got %v", maxHealthCheckProbes, got)
+	}
+	if got := healthCheckTables(nil, "aws"); len(got) != 0 {
+		t.Errorf("expected no tables without schema metadata, got %v", got)
+	}
+}
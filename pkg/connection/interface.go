@@ -6,6 +6,7 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/shared"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 )
 
@@ -19,5 +20,7 @@ type pluginManager interface {
 	LoadPluginRateLimiters(map[string]string) (PluginLimiterMap, error)
 	SendPostgresSchemaNotification(context.Context) error
 	SendPostgresErrorsAndWarningsNotification(context.Context, error_helpers.ErrorAndWarnings)
+	SendPostgresRefreshProgressNotification(context.Context, *steampipeconfig.RefreshProgressNotification) error
 	UpdatePluginColumnsTable(context.Context, map[string]*proto.Schema, []string) error
+	OnConnectionCredentialsExpired(context.Context, []string)
 }
@@ -0,0 +1,37 @@
+package connection
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// RefreshPoolSize returns the size of the connection pool used to refresh connections
+// this is the refresh concurrency if set, otherwise constants.DefaultRefreshPoolSize
+func RefreshPoolSize() int {
+	if refreshConcurrency := viper.GetInt(constants.ArgRefreshConcurrency); refreshConcurrency > 0 {
+		return refreshConcurrency
+	}
+	return constants.DefaultRefreshPoolSize
+}
+
+// refreshMaxParallel returns the number of connection updates/clones which may be executed in parallel
+// this is the refresh concurrency if set, otherwise a single update is run at a time
+// (unless overridden by the legacy STEAMPIPE_UPDATE_SCHEMA_MAX_PARALLEL env var)
+func refreshMaxParallel() int64 {
+	if refreshConcurrency := viper.GetInt(constants.ArgRefreshConcurrency); refreshConcurrency > 0 {
+		return int64(refreshConcurrency)
+	}
+	// default to running a single update at a time
+	var maxParallel = int64(1)
+	// allow override of this behaviour vis env var
+	if envMaxStr, ok := os.LookupEnv("STEAMPIPE_UPDATE_SCHEMA_MAX_PARALLEL"); ok {
+		envMax, err := strconv.Atoi(envMaxStr)
+		if err == nil && envMax > 0 {
+			maxParallel = int64(envMax)
+		}
+	}
+	return maxParallel
+}
@@ -7,7 +7,11 @@ import (
 	"time"
 
 	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/pkg/metrics"
+	"github.com/turbot/steampipe/pkg/statestore"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // only allow one execution of refresh connections
@@ -31,7 +35,10 @@ func RefreshConnections(ctx context.Context, pluginManager pluginManager, forceU
 	defer log.Printf("[INFO] refreshConnections completion time (%fs)", time.Since(t).Seconds())
 
 	// first grab the queue lock
-	if !queueLock.TryLock() {
+	if len(forceUpdateConnectionNames) > 0 {
+		// the queued execution will not force update our connections - so wait to queue
+		queueLock.Lock()
+	} else if !queueLock.TryLock() {
 		// someone has it - they will execute so we have nothing to do
 		log.Printf("[INFO] another execution is already queued - returning")
 		return &steampipeconfig.RefreshConnectionResult{}
@@ -51,15 +58,56 @@ func RefreshConnections(ctx context.Context, pluginManager pluginManager, forceU
 	log.Printf("[INFO] acquired refreshExecuteLock, released refreshQueueLock")
 
 	// now refresh connections
+	startTime := time.Now()
+	// trace the refresh, and record its duration in the metrics
+	ctx, span := tracing.StartSpan(ctx, "RefreshConnections", attribute.StringSlice("steampipe.force_update_connections", forceUpdateConnectionNames))
+	defer func() {
+		if res == nil {
+			// the refresh panicked - the result is set when the panic is recovered
+			span.End()
+			return
+		}
+		tracing.EndSpan(span, res.Error)
+		metrics.ObserveRefreshConnections(time.Since(startTime), res.Error)
+	}()
+
+	// hash the inputs of the refresh before starting, so any change made during the refresh is picked up by the next refresh
+	// (the saved hash is deleted until the refresh completes)
+	store := statestore.New(pluginManager.Pool())
+	steampipeconfig.DeleteRefreshConnectionsHash(ctx, store)
+	refreshHash, err := steampipeconfig.RefreshConnectionsHash(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to build refresh connections hash: %s", err.Error())
+	}
 
 	// package up all necessary data into a state object
 	state, err := newRefreshConnectionState(ctx, pluginManager, forceUpdateConnectionNames)
 	if err != nil {
-		return steampipeconfig.NewErrorRefreshConnectionResult(err)
+		res = steampipeconfig.NewErrorRefreshConnectionResult(err)
+		saveRefreshConnectionsReport(ctx, store, res, nil, startTime, "")
+		return res
 	}
 
 	// now do the refresh
 	state.refreshConnections(ctx)
+	saveRefreshConnectionsReport(ctx, store, state.res, state.connectionUpdates, startTime, refreshHash)
+	notifyCredentialExpiry(ctx, pluginManager, state.res)
 
 	return state.res
 }
+
+// saveRefreshConnectionsReport saves a report of the refresh, which is used by the CLI to report refresh results
+// if the refresh succeeded for all connections, the hash of its inputs is also saved,
+// so the next service start may skip the refresh if nothing has changed
+func saveRefreshConnectionsReport(ctx context.Context, store statestore.Store, res *steampipeconfig.RefreshConnectionResult, connectionUpdates *steampipeconfig.ConnectionUpdates, startTime time.Time, refreshHash string) {
+	report := steampipeconfig.NewRefreshConnectionsReport(res, connectionUpdates, startTime)
+	if err := report.Save(ctx, store); err != nil {
+		log.Printf("[WARN] failed to save refresh connections report: %s", err.Error())
+	}
+	if refreshHash == "" || report.Error != "" || len(report.Failed) > 0 {
+		return
+	}
+	if err := steampipeconfig.SaveRefreshConnectionsHash(ctx, store, refreshHash); err != nil {
+		log.Printf("[WARN] failed to save refresh connections hash: %s", err.Error())
+	}
+}
@@ -2,10 +2,10 @@ package connection
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,8 +22,11 @@ import (
 	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/introspection"
+	"github.com/turbot/steampipe/pkg/statestore"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/tracing"
 	"github.com/turbot/steampipe/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
 	"golang.org/x/sync/semaphore"
 )
@@ -50,6 +53,11 @@ type refreshConnectionState struct {
 	// if a plugin has an entry in this map, all connections schemas can be cloned from the exemplar schema
 	exemplarCommentsMap map[string]string
 	pluginManager       pluginManager
+	// reports the progress of the connection updates
+	progress *refreshProgress
+	// the options the view schema of each aggregator has been created with (empty if it has no view schema)
+	// - this is set by updateAggregatorViews and recorded in the connection state by updateAggregatorChildStatus
+	aggregatorViews map[string]string
 }
 
 func newRefreshConnectionState(ctx context.Context, pluginManager pluginManager, forceUpdateConnectionNames []string) (*refreshConnectionState, error) {
@@ -135,11 +143,12 @@ func (s *refreshConnectionState) refreshConnections(ctx context.Context) {
 
 	// delete the connection state file - it will be rewritten when we are complete
 	log.Printf("[INFO] deleting connections state file")
-	steampipeconfig.DeleteConnectionStateFile()
+	store := statestore.New(s.pool)
+	steampipeconfig.DeleteConnectionStateFile(ctx, store)
 	defer func() {
 		if s.res.Error == nil {
 			log.Printf("[INFO] saving connections state file")
-			steampipeconfig.SaveConnectionStateFile(s.res, s.connectionUpdates)
+			steampipeconfig.SaveConnectionStateFile(ctx, store, s.res, s.connectionUpdates)
 		}
 	}()
 
@@ -166,6 +175,8 @@ func (s *refreshConnectionState) refreshConnections(ctx context.Context) {
 	// if there are no updates, just return
 	if !s.connectionUpdates.HasUpdates() {
 		log.Println("[INFO] no updates required")
+		// the aggregator view and column mask options may still have changed
+		s.executePostUpdateQueries(ctx)
 		return
 	}
 
@@ -178,9 +189,21 @@ func (s *refreshConnectionState) refreshConnections(ctx context.Context) {
 		return
 	}
 
+	// now all schemas are updated, recreate any aggregator views and apply column masks
+	s.executePostUpdateQueries(ctx)
+
 	s.res.UpdatedConnections = true
 }
 
+// executePostUpdateQueries updates the objects and privileges which depend on the connection schemas,
+// and records the child connections included in each aggregator
+// NOTE: aggregator views must be created before column masks are applied, as masks are also applied to the views
+func (s *refreshConnectionState) executePostUpdateQueries(ctx context.Context) {
+	s.updateAggregatorViews(ctx)
+	s.updateColumnMasks(ctx)
+	s.updateAggregatorChildStatus(ctx)
+}
+
 func (s *refreshConnectionState) setFailedConnectionsToError(ctx context.Context) error {
 	conn, err := s.pool.Acquire(ctx)
 	if err != nil {
@@ -372,6 +395,10 @@ func (s *refreshConnectionState) executeUpdateQueries(ctx context.Context) {
 	s.exemplarCommentsMap = make(map[string]string)
 	log.Printf("[INFO] executing %d update %s", numUpdates, utils.Pluralize("query", numUpdates))
 
+	// report progress as each connection is updated
+	s.progress = newRefreshProgress(s.pluginManager, numUpdates)
+	s.progress.start(ctx)
+
 	// execute initial updates
 	log.Printf("[INFO] executing initial updates")
 	var errors []error
@@ -444,6 +471,15 @@ func (s *refreshConnectionState) executeUpdateQueries(ctx context.Context) {
 			}
 		}
 	}
+	// the remaining updates were committed after the notification above was sent - notify again,
+	// otherwise clients which reloaded their schema metadata on the first notification would not see them
+	if len(remainingUpdates)+len(connectionUpdates.MissingComments)+len(connectionUpdates.InvalidConnections) > 0 {
+		log.Printf("[INFO] updated all remaining schemas - sending notification")
+		if err := s.pluginManager.SendPostgresSchemaNotification(ctx); err != nil {
+			// just log
+			log.Printf("[WARN] failed to send schema update Postgres notification: %s", err.Error())
+		}
+	}
 	log.Printf("[INFO] executeUpdateQueries complete")
 	return
 }
@@ -483,15 +519,7 @@ func (s *refreshConnectionState) executeUpdateSetsInParallel(ctx context.Context
 	var wg sync.WaitGroup
 	var errChan = make(chan *connectionError)
 
-	// default to running a single update at a time
-	var maxParallel = int64(1)
-	// allow override of this behaviour vis env var
-	if envMaxStr, ok := os.LookupEnv("STEAMPIPE_UPDATE_SCHEMA_MAX_PARALLEL"); ok {
-		envMax, err := strconv.Atoi(envMaxStr)
-		if err == nil {
-			maxParallel = int64(envMax)
-		}
-	}
+	maxParallel := refreshMaxParallel()
 	log.Printf("[INFO] executeUpdateSetsInParallel - maxParallel= %d", maxParallel)
 
 	sem := semaphore.NewWeighted(maxParallel)
@@ -556,45 +584,96 @@ func (s *refreshConnectionState) executeUpdateForConnections(ctx context.Context
 	for _, connectionState := range connectionStates {
 		connectionName := connectionState.ConnectionName
 		pluginSchemaName := utils.PluginFQNToSchemaName(connectionState.Plugin)
-		var sql string
 
-		s.exemplarSchemaMapMut.Lock()
 		// is this plugin in the exemplarSchemaMap
+		s.exemplarSchemaMapMut.Lock()
 		exemplarSchemaName, haveExemplarSchema := s.exemplarSchemaMap[connectionState.Plugin]
-		if haveExemplarSchema && cloneSchemaEnabled {
-			// we can clone!
-			sql = getCloneSchemaQuery(exemplarSchemaName, connectionState)
-		} else {
-			// just get sql to execute update query, and update the connection state table, in a transaction
-			sql = db_common.GetUpdateConnectionQuery(connectionName, pluginSchemaName)
-		}
 		s.exemplarSchemaMapMut.Unlock()
 
-		// the only error this will return is the failure to update the state table
-		// - all other errors are written to the state table
-		if err := s.executeUpdateQuery(ctx, sql, connectionName); err != nil {
+		// if we can, clone the exemplar schema rather than importing the schema from the plugin
+		if haveExemplarSchema && cloneSchemaEnabled && connectionState.CanCloneSchema() {
+			queryErr, err := s.runUpdateQuery(ctx, getCloneSchemaQuery(exemplarSchemaName, connectionState), connectionName)
+			if err != nil {
+				errChan <- &connectionError{connectionName, err}
+				s.progress.onConnectionUpdated(ctx, connectionName, constants.ConnectionStateError)
+				continue
+			}
+			if queryErr == nil {
+				s.progress.onConnectionUpdated(ctx, connectionName, constants.ConnectionStateReady)
+				continue
+			}
+			// if the clone failed, fall back to importing the schema
+			log.Printf("[WARN] failed to clone schema '%s' for connection '%s' - importing schema from plugin: %s", exemplarSchemaName, connectionName, queryErr.Error())
+		}
+
+		// get sql to execute update query, and update the connection state table, in a transaction
+		// (any column type overrides are applied to the imported foreign tables in the same transaction)
+		sql := db_common.GetUpdateConnectionQuery(connectionName, pluginSchemaName) +
+			db_common.GetColumnTypesQuery(connectionName, connectionState.ColumnTypes)
+
+		queryErr, err := s.runUpdateQuery(ctx, sql, connectionName)
+		if err == nil && queryErr != nil {
+			// write the error to the state table
+			// - this will only return an error if we fail to update the state table
+			err = s.onUpdateQueryFailed(ctx, connectionName, queryErr)
+		}
+		if err != nil {
 			errChan <- &connectionError{connectionName, err}
+			s.progress.onConnectionUpdated(ctx, connectionName, constants.ConnectionStateError)
+			continue
+		}
+		if queryErr != nil {
+			s.progress.onConnectionUpdated(ctx, connectionName, constants.ConnectionStateError)
 		} else {
-			// we can clone this plugin, add to exemplarSchemaMap
-			// (AFTER executing the update query)
-			if !haveExemplarSchema && connectionState.CanCloneSchema() {
+			s.progress.onConnectionUpdated(ctx, connectionName, constants.ConnectionStateReady)
+		}
+		// if the update succeeded, we can use this schema as the exemplar for other connections using this plugin
+		// (AFTER executing the update query)
+		if queryErr == nil && !haveExemplarSchema && connectionState.CanCloneSchema() {
+			s.exemplarSchemaMapMut.Lock()
+			if _, ok := s.exemplarSchemaMap[connectionState.Plugin]; !ok {
 				s.exemplarSchemaMap[connectionState.Plugin] = connectionName
 			}
+			s.exemplarSchemaMapMut.Unlock()
 		}
 	}
 }
 
-func (s *refreshConnectionState) executeUpdateQuery(ctx context.Context, sql, connectionName string) (err error) {
-	log.Println("[DEBUG] refreshConnectionState.executeUpdateQuery start")
-	defer log.Println("[DEBUG] refreshConnectionState.executeUpdateQuery end")
+// onUpdateQueryFailed records the failure of the update query for the connection, and writes the error to the connection state table
+// - an error is only returned if we fail to update the connection state table
+func (s *refreshConnectionState) onUpdateQueryFailed(ctx context.Context, connectionName string, queryErr error) error {
+	// update failed connections in result
+	s.res.AddFailedConnection(connectionName, queryErr.Error())
+
+	// update the state table
+	//(the transaction will be aborted - create a connection for the update)
+	if conn, poolErr := s.pool.Acquire(ctx); poolErr == nil {
+		defer conn.Release()
+		if statusErr := s.tableUpdater.onConnectionError(ctx, conn.Conn(), connectionName, queryErr); statusErr != nil {
+			// NOTE: do not return the error - unless we failed to update the connection state table
+			return error_helpers.CombineErrorsWithPrefix(fmt.Sprintf("failed to update connection %s and failed to update connection_state table", connectionName), queryErr, statusErr)
+		}
+	}
+	return nil
+}
+
+// runUpdateQuery executes the update query for the connection, and sets the connection ready in the connection state table,
+// in a transaction
+// returns the error executing the query (if any), and the error updating the connection state table (if any)
+func (s *refreshConnectionState) runUpdateQuery(ctx context.Context, sql, connectionName string) (queryErr, err error) {
+	log.Println("[DEBUG] refreshConnectionState.runUpdateQuery start")
+	defer log.Println("[DEBUG] refreshConnectionState.runUpdateQuery end")
+
+	ctx, span := tracing.StartSpan(ctx, "RefreshConnections.runUpdateQuery", attribute.String("steampipe.connection", connectionName))
+	defer func() { tracing.EndSpan(span, errors.Join(queryErr, err)) }()
 
 	// create a transaction
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return sperr.WrapWithMessage(err, "failed to create transaction to perform update query")
+		return nil, sperr.WrapWithMessage(err, "failed to create transaction to perform update query")
 	}
 	defer func() {
-		if err != nil {
+		if queryErr != nil || err != nil {
 			tx.Rollback(ctx)
 		} else {
 			tx.Commit(ctx)
@@ -602,29 +681,15 @@ func (s *refreshConnectionState) executeUpdateQuery(ctx context.Context, sql, co
 	}()
 
 	// execute update sql
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		// update failed connections in result
-		s.res.AddFailedConnection(connectionName, err.Error())
-
-		// update the state table
-		//(the transaction will be aborted - create a connection for the update)
-		if conn, poolErr := s.pool.Acquire(ctx); poolErr == nil {
-			defer conn.Release()
-			if statusErr := s.tableUpdater.onConnectionError(ctx, conn.Conn(), connectionName, err); statusErr != nil {
-				// NOTE: do not return the error - unless we failed to update the connection state table
-				return error_helpers.CombineErrorsWithPrefix(fmt.Sprintf("failed to update connection %s and failed to update connection_state table", connectionName), err, statusErr)
-			}
-		}
-		return nil
+	if _, queryErr = tx.Exec(ctx, sql); queryErr != nil {
+		return queryErr, nil
 	}
 
 	// update state table (inside transaction)
-	err = s.tableUpdater.onConnectionReady(ctx, tx.Conn(), connectionName)
-	if err != nil {
-		return sperr.WrapWithMessage(err, "failed to update connection state table")
+	if err = s.tableUpdater.onConnectionReady(ctx, tx.Conn(), connectionName); err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to update connection state table")
 	}
-	return nil
+	return nil, nil
 }
 
 // set connection comments
@@ -873,7 +938,9 @@ func (s *refreshConnectionState) setIncompleteConnectionStateToError(ctx context
 	}
 	defer conn.Release()
 
-	queries := introspection.GetIncompleteConnectionStateErrorSql(connectionStateError)
+	// record the transition in the connection state history before updating the state
+	queries := []db_common.QueryWithArgs{introspection.GetIncompleteConnectionStateHistoryErrorSql(connectionStateError)}
+	queries = append(queries, introspection.GetIncompleteConnectionStateErrorSql(connectionStateError)...)
 
 	if _, err = db_local.ExecuteSqlWithArgsInTransaction(ctx, conn.Conn(), queries...); err != nil {
 		log.Printf("[WARN] setAllConnectionStateToError failed to set connection states to error: %s", err.Error())
@@ -0,0 +1,67 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// the minimum interval between refresh progress notifications
+// (the first and final progress are always sent)
+const refreshProgressNotificationInterval = 500 * time.Millisecond
+
+// refreshProgress tracks the number of connection updates which are complete,
+// and reports progress via the status hooks and a postgres notification
+type refreshProgress struct {
+	pluginManager pluginManager
+	total         int
+	complete      int
+	lastSent      time.Time
+	mut           sync.Mutex
+}
+
+func newRefreshProgress(pluginManager pluginManager, total int) *refreshProgress {
+	return &refreshProgress{
+		pluginManager: pluginManager,
+		total:         total,
+	}
+}
+
+// start reports the initial progress
+func (p *refreshProgress) start(ctx context.Context) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.report(ctx, "", "")
+}
+
+// onConnectionUpdated records that the update for the given connection is complete, with the given connection state
+func (p *refreshProgress) onConnectionUpdated(ctx context.Context, connectionName, state string) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.complete++
+	p.report(ctx, connectionName, state)
+}
+
+func (p *refreshProgress) report(ctx context.Context, connectionName, state string) {
+	msg := fmt.Sprintf("Refreshing connections: %d of %d", p.complete, p.total)
+	if connectionName != "" {
+		msg = fmt.Sprintf("%s (%s %s)", msg, connectionName, state)
+	}
+	statushooks.SetProgress(ctx, msg, p.complete, p.total)
+
+	// throttle the notifications - always send the first and final progress
+	if !p.lastSent.IsZero() && p.complete < p.total && time.Since(p.lastSent) < refreshProgressNotificationInterval {
+		return
+	}
+	p.lastSent = time.Now()
+	notification := steampipeconfig.NewRefreshProgressNotification(p.complete, p.total, connectionName, state)
+	if err := p.pluginManager.SendPostgresRefreshProgressNotification(ctx, notification); err != nil {
+		// just log
+		log.Printf("[WARN] failed to send refresh progress Postgres notification: %s", err.Error())
+	}
+}
@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// reloadConnectionConfigMut serialises config reloads, which may be triggered concurrently by the ConnectionWatcher,
+// a SIGHUP and plugin install notifications - so a reload cannot overwrite the config set by a later reload
+var reloadConnectionConfigMut sync.Mutex
+
+// ReloadConnectionConfig re-reads all connection config files and applies any changes to the plugin manager,
+// then refreshes connections asynchronously
+// this is called by the ConnectionWatcher when a config file changes, and by the plugin manager on SIGHUP
+// or when it is notified that plugins have been installed
+// NOTE: the database service is not restarted, so client sessions are not dropped
+func ReloadConnectionConfig(ctx context.Context, pluginManager pluginManager) {
+	reloadConnectionConfigMut.Lock()
+	defer reloadConnectionConfigMut.Unlock()
+
+	config, errorsAndWarnings := steampipeconfig.LoadConnectionConfig(ctx)
+	// send notification if there were any errors or warnings
+	if !errorsAndWarnings.Empty() {
+		pluginManager.SendPostgresErrorsAndWarningsNotification(ctx, errorsAndWarnings)
+		// if there was an error return
+		if errorsAndWarnings.GetError() != nil {
+			log.Printf("[WARN] error loading updated connection config: %v", errorsAndWarnings.GetError())
+			return
+		}
+	}
+
+	log.Printf("[INFO] loaded updated config")
+
+	// We need to update the viper config and GlobalConfig
+	// as these are both used by RefreshConnectionAndSearchPathsWithLocalClient
+
+	// set the global steampipe config (retaining the previous config so we can determine what has changed)
+	prevConfig := steampipeconfig.SetGlobalConfig(config)
+
+	// call on changed callback - we must call this BEFORE calling refresh connections
+	// convert config to format expected by plugin manager
+	// (plugin manager cannot reference steampipe config to avoid circular deps)
+	configMap := NewConnectionConfigMap(config.Connections)
+	pluginManager.OnConnectionConfigChanged(ctx, configMap, config.PluginsInstances)
+
+	// The only configurations from GlobalConfig which have
+	// impact during Refresh are Database options and the Connections
+	// themselves.
+	//
+	// It is safe to ignore the Workspace Profile here since this
+	// code runs in the plugin-manager and has been started with the
+	// install-dir properly set from the active Workspace Profile
+	//
+	// Workspace Profile does not have any setting which can alter
+	// behavior in service mode (namely search path). Therefore, it is safe
+	// to use the GlobalConfig here and ignore Workspace Profile in general
+	cmdconfig.SetDefaultsFromConfig(config.ConfigMap())
+
+	// if only the config of static schema connections has changed (e.g. credential rotation),
+	// the updated config has already been sent to the running plugins by OnConnectionConfigChanged
+	// - the connection schemas are unaffected so there is no need to refresh connections
+	if configUpdates, ok := getConfigOnlyUpdates(prevConfig, config); ok && canSkipRefreshForConfigUpdates(ctx, pluginManager.Pool(), configUpdates) {
+		log.Printf("[INFO] only the config of %s has changed - skipping RefreshConnections", strings.Join(configUpdates, ","))
+		return
+	}
+
+	log.Printf("[INFO] calling RefreshConnections asyncronously")
+
+	// call RefreshConnections asyncronously
+	// the RefreshConnections implements its own locking to ensure only a single execution and a single queues execution
+	go RefreshConnections(ctx, pluginManager)
+}
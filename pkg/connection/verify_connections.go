@@ -0,0 +1,114 @@
+package connection
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+const (
+	// the probe query succeeded, so the connection credentials are valid
+	ConnectionVerifyReachable = "reachable"
+	// the probe query was rejected by the API because of the connection credentials or their permissions
+	ConnectionVerifyForbidden = "forbidden"
+	// the probe query did not complete within the timeout
+	ConnectionVerifyTimeout = "timeout"
+	// the connection failed to load, so its schema could not be imported (e.g. invalid connection config)
+	ConnectionVerifySchemaError = "schema_error"
+	// the probe query failed for some other reason
+	ConnectionVerifyError = "error"
+	// the connection was not probed (e.g. it is disabled)
+	ConnectionVerifySkipped = "skipped"
+)
+
+// error message fragments which indicate the API rejected the request because of the credentials
+// (these are the messages returned by the APIs of the most commonly used plugins)
+var credentialErrorFragments = []string{
+	"unauthorized",
+	"unauthenticated",
+	"forbidden",
+	"access denied",
+	"accessdenied",
+	"not authorized",
+	"permission denied",
+	"authentication failed",
+	"invalid credentials",
+	"invalidclienttokenid",
+	"signaturedoesnotmatch",
+	"expiredtoken",
+	"token has expired",
+	"invalid token",
+	"invalid api key",
+}
+
+// matches the http status codes returned by APIs for requests with invalid or insufficient credentials
+var credentialErrorStatusRegex = regexp.MustCompile(`\b(401|403)\b`)
+
+// error message fragments which indicate a probe timed out
+var timeoutErrorFragments = []string{
+	"timed out",
+	"timeout",
+	"deadline exceeded",
+}
+
+// ConnectionVerification is the result of the verification of a single connection
+type ConnectionVerification struct {
+	Connection string `json:"connection"`
+	// reachable, forbidden, timeout, schema_error, error or skipped
+	Result string `json:"result"`
+	// the table queried by the probe
+	Table     string `json:"table,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// VerifyConnections probes each of the given connections by listing a single resource via the plugin
+// and classifies the result, distinguishing credential problems (the API rejected the request)
+// from schema problems (the connection failed to load)
+func VerifyConnections(ctx context.Context, client db_common.Client, connectionStateMap steampipeconfig.ConnectionStateMap, schemaMetadata *db_common.SchemaMetadata, connectionNames []string, timeout time.Duration) []*ConnectionVerification {
+	healthResults := CheckConnectionHealth(ctx, client, connectionStateMap, schemaMetadata, connectionNames, timeout)
+	res := make([]*ConnectionVerification, len(healthResults))
+	for i, health := range healthResults {
+		res[i] = &ConnectionVerification{
+			Connection: health.Connection,
+			Result:     classifyConnectionHealth(health),
+			Table:      health.Table,
+			LatencyMs:  health.LatencyMs,
+			Error:      health.Error,
+		}
+	}
+	return res
+}
+
+// classifyConnectionHealth returns the verification result for the health check result of a connection
+func classifyConnectionHealth(health *ConnectionHealth) string {
+	switch {
+	case health.Status == ConnectionHealthOk:
+		return ConnectionVerifyReachable
+	case health.Status == ConnectionHealthSkipped:
+		return ConnectionVerifySkipped
+	case errorContainsAny(health.Error, timeoutErrorFragments):
+		return ConnectionVerifyTimeout
+	case errorContainsAny(health.Error, credentialErrorFragments) || credentialErrorStatusRegex.MatchString(health.Error):
+		return ConnectionVerifyForbidden
+	case health.Table == "":
+		// no table was probed, so the error is the error loading the connection
+		return ConnectionVerifySchemaError
+	default:
+		return ConnectionVerifyError
+	}
+}
+
+func errorContainsAny(errorMessage string, fragments []string) bool {
+	errorMessage = strings.ToLower(errorMessage)
+	for _, fragment := range fragments {
+		if strings.Contains(errorMessage, fragment) {
+			return true
+		}
+	}
+	return false
+}
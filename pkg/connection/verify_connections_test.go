@@ -0,0 +1,27 @@
+package connection
+
+import "testing"
+
+func TestClassifyConnectionHealth(t *testing.T) {
+	tests := map[string]struct {
+		health *ConnectionHealth
+		want   string
+	}{
+		"ok":             {&ConnectionHealth{Status: ConnectionHealthOk, Table: "aws_account"}, ConnectionVerifyReachable},
+		"disabled":       {&ConnectionHealth{Status: ConnectionHealthSkipped, Error: "connection is disabled"}, ConnectionVerifySkipped},
+		"timeout":        {&ConnectionHealth{Status: ConnectionHealthError, Table: "aws_account", Error: "probe query timed out after 30s"}, ConnectionVerifyTimeout},
+		"access denied":  {&ConnectionHealth{Status: ConnectionHealthError, Table: "aws_account", Error: "operation error STS: GetCallerIdentity, AccessDenied: not allowed"}, ConnectionVerifyForbidden},
+		"status code":    {&ConnectionHealth{Status: ConnectionHealthError, Table: "github_user", Error: "GET https://api.github.com/user: 401 Bad credentials"}, ConnectionVerifyForbidden},
+		"load error":     {&ConnectionHealth{Status: ConnectionHealthError, Error: "failed to parse connection config: Unsupported argument"}, ConnectionVerifySchemaError},
+		"load forbidden": {&ConnectionHealth{Status: ConnectionHealthError, Error: "failed to get schema: 403 Forbidden"}, ConnectionVerifyForbidden},
+		"other error":    {&ConnectionHealth{Status: ConnectionHealthError, Table: "aws_account", Error: "connection reset by peer"}, ConnectionVerifyError},
+		"id with digits": {&ConnectionHealth{Status: ConnectionHealthError, Table: "aws_account", Error: "resource i-4011234 not found"}, ConnectionVerifyError},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := classifyConnectionHealth(test.health); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -7,10 +7,12 @@ const (
 	ArgForce                   = "force"
 	ArgAll                     = "all"
 	ArgTiming                  = "timing"
+	ArgTimingSource            = "timing-source"
 	ArgOn                      = "on"
 	ArgOff                     = "off"
 	ArgVerbose                 = "verbose"
 	ArgClear                   = "clear"
+	ArgBypassOnce              = "bypass-once"
 	ArgDatabaseListenAddresses = "database-listen"
 	ArgDatabasePort            = "database-port"
 	ArgDatabaseQueryTimeout    = "query-timeout"
@@ -20,21 +22,29 @@ const (
 	ArgDashboardListen         = "dashboard-listen"
 	ArgDashboardPort           = "dashboard-port"
 	ArgDashboardStartTimeout   = "dashboard-start-timeout"
+	ArgDashboardBasePath       = "dashboard-base-path"
+	ArgDashboardAuth           = "dashboard-auth"
+	ArgDashboardAuthHeader     = "dashboard-auth-header"
+	ArgDashboardTrustedProxies = "dashboard-trusted-proxies"
 	ArgSkipConfig              = "skip-config"
 	ArgForeground              = "foreground"
 	ArgInvoker                 = "invoker"
 	ArgUpdateCheck             = "update-check"
 	ArgTelemetry               = "telemetry"
+	ArgTelemetryRedact         = "telemetry-redact"
+	ArgTelemetryExport         = "telemetry-export"
 	ArgInstallDir              = "install-dir"
 	ArgPipesInstallDir         = "pipes-install-dir"
 	ArgWorkspaceDatabase       = "workspace-database"
 	ArgSchemaComments          = "schema-comments"
+	ArgExitCodeMap             = "exit-code-map"
 	ArgCloudHost               = "cloud-host"
 	ArgCloudToken              = "cloud-token"
 	ArgPipesHost               = "pipes-host"
 	ArgPipesToken              = "pipes-token"
 	ArgSearchPath              = "search-path"
 	ArgSearchPathPrefix        = "search-path-prefix"
+	ArgConnection              = "connection"
 	ArgWatch                   = "watch"
 	ArgTheme                   = "theme"
 	ArgProgress                = "progress"
@@ -49,6 +59,9 @@ const (
 	ArgConnectionString        = "connection-string"
 	ArgDisplayWidth            = "display-width"
 	ArgPrune                   = "prune"
+	ArgPolicy                  = "policy"
+	ArgLatest                  = "latest"
+	ArgMinor                   = "minor"
 	ArgModInstall              = "mod-install"
 	ArgServiceMode             = "service-mode"
 	ArgBrowser                 = "browser"
@@ -71,6 +84,55 @@ const (
 	ArgDatabaseSSLPassword     = "database-ssl-password"
 	ArgMemoryMaxMb             = "memory-max-mb"
 	ArgMemoryMaxMbPlugin       = "memory-max-mb-plugin"
+	ArgReady                   = "ready"
+	ArgTyped                   = "typed"
+	ArgNullString              = "null-string"
+	ArgMaxColWidth             = "max-col-width"
+	ArgWidthReset              = "reset"
+	ArgPriorityColumns         = "priority-columns"
+	ArgQuoteMode               = "quote-mode"
+	ArgTimezone                = "timezone"
+	ArgTimestampFormat         = "timestamp-format"
+	ArgIterations              = "iterations"
+	ArgRows                    = "rows"
+	ArgParallelism             = "parallelism"
+	ArgTable                   = "table"
+	ArgStableOrder             = "stable-order"
+	ArgAttribute               = "attribute"
+	ArgSecret                  = "secret"
+	ArgArchive                 = "archive"
+	ArgChecksum                = "checksum"
+	ArgProbeTimeout            = "timeout"
+	ArgLogRetentionDays        = "log-retention-days"
+	ArgTempDirRetentionHours   = "temp-dir-retention-hours"
+	ArgYes                     = "yes"
+	ArgPasswordStdin           = "password-stdin"
+	ArgTrackUsage              = "track-usage"
+	ArgResourceUsage           = "resource-usage"
+	ArgExportMetadata          = "export-metadata"
+	ArgRefreshConcurrency      = "refresh-concurrency"
+	ArgOffline                 = "offline"
+	ArgMaxQueryDuration        = "max-query-duration"
+	ArgSchemaNameCase          = "schema-name-case"
+	ArgSchemaNameInvalidChars  = "schema-name-invalid-chars"
+	ArgMaintenanceInterval     = "maintenance-interval"
+	ArgStateStorage            = "state-storage"
+	ArgMetricsPort             = "metrics-port"
+	ArgVariablesWorkspace      = "variables-workspace"
+	ArgCaCertFile              = "ca-cert-file"
+	ArgHttpProxy               = "http-proxy"
+	ArgHttpsProxy              = "https-proxy"
+	ArgNoProxy                 = "no-proxy"
+	ArgTlsSkipVerify           = "tls-skip-verify"
+	ArgFromArchive             = "from-archive"
+	ArgSideBySide              = "side-by-side"
+	ArgConcurrency             = "concurrency"
+	ArgManifest                = "manifest"
+	ArgDatabaseArchive         = "database-archive"
+	ArgFdwArchive              = "fdw-archive"
+	ArgStrictConfig            = "strict-config"
+	ArgImageVerify             = "image-verify"
+	ArgImageVerifyKey          = "image-verify-key"
 )
 
 // metaquery mode arguments
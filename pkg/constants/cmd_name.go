@@ -4,4 +4,26 @@ const (
 	CmdNameQuery     = "query"
 	CmdNameCheck     = "check"
 	CmdNameDashboard = "dashboard"
+	CmdNameBench     = "bench"
+	CmdNameInspect   = "inspect"
+	CmdNameMod       = "mod"
+	CmdNamePlugin    = "plugin"
+	CmdNameReport    = "report"
+	CmdNameSearch    = "search"
+	CmdNameService   = "service"
 )
+
+// WorkspaceProfileCommands are the commands which may have a command block in a workspace profile,
+// setting the default flag values of the command (and its subcommands)
+var WorkspaceProfileCommands = []string{
+	CmdNameQuery,
+	CmdNameCheck,
+	CmdNameDashboard,
+	CmdNameBench,
+	CmdNameInspect,
+	CmdNameMod,
+	CmdNamePlugin,
+	CmdNameReport,
+	CmdNameSearch,
+	CmdNameService,
+}
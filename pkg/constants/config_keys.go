@@ -10,4 +10,12 @@ const (
 	ConfigKeyServerSearchPath            = "server-search-path"
 	ConfigKeyServerSearchPathPrefix      = "server-search-path-prefix"
 	ConfigKeyBypassHomeDirModfileWarning = "bypass-home-dir-modfile-warning"
+	ConfigKeyColumnWidths                = "column-widths"
+	// set by the '.cache bypass-once' meta-command - the next query entered at the interactive prompt bypasses the cache
+	ConfigKeyCacheBypassOnce = "cache-bypass-once"
+	// the connection strings of the failover endpoints of the workspace database
+	ConfigKeyFailoverConnectionStrings = "failover-connection-strings"
+	// the steampipe executable used to run the plugin manager, if this is not the running process
+	// (e.g. when steampipe is embedded in another program)
+	ConfigKeySteampipeExecutable = "steampipe-executable"
 )
@@ -11,7 +11,11 @@ var DashboardListenAddresses = []string{"localhost", "127.0.0.1"}
 
 const (
 	DashboardServerDefaultPort    = 9194
-	DashboardAssetsImageRefFormat = "us-docker.pkg.dev/steampipe/steampipe/assets:%s"
+	DashboardAssetsImageRepo      = "us-docker.pkg.dev/steampipe/steampipe/assets"
+	DashboardAssetsImageRefFormat = DashboardAssetsImageRepo + ":%s"
+	DashboardDefaultAuthHeader    = "X-Forwarded-User"
+	// by default, only trust a reverse proxy running on the same host
+	DashboardDefaultTrustedProxies = "127.0.0.1,::1"
 )
 
 var (
@@ -22,7 +22,16 @@ const (
 	DatabaseUser                     = "steampipe"
 	DatabaseName                     = "steampipe"
 	DatabaseUsersRole                = "steampipe_users"
+	DatabaseAdminRole                = "steampipe_admin"
 	DefaultMaxConnections            = 10
+	// DatabaseServiceUsersRole is the role of the users added with 'steampipe service user add' - members
+	// may connect to the steampipe database with a password, but are only granted access to their connection schemas
+	DatabaseServiceUsersRole = "steampipe_service_users"
+	// DefaultRefreshPoolSize is the size of the connection pool used by the plugin manager to refresh connections
+	// (used if refresh_concurrency is not set) - in testing, a size of 20 seemed optimal
+	DefaultRefreshPoolSize = 20
+	// DefaultMaintenanceInterval is the default interval (in minutes) at which the service vacuums the internal tables
+	DefaultMaintenanceInterval = 24 * 60
 )
 
 // constants for installing db and fdw images
@@ -59,6 +68,25 @@ const (
 	PluginInstanceTable = "steampipe_plugin"
 	PluginColumnTable   = "steampipe_plugin_column"
 
+	// ConnectionStateHistoryTable is the table used to record every connection state transition
+	ConnectionStateHistoryTable = "steampipe_connection_state_history"
+	// ConnectionStateHistoryRetentionDays is the number of days connection state history is retained
+	ConnectionStateHistoryRetentionDays = 7
+
+	// ScheduleTable is the table used to record the state of the jobs scheduled by the service
+	ScheduleTable = "steampipe_schedule"
+
+	// CommandRequestTable is the table used to record the commands sent to the service (e.g. to reload connections),
+	// so the service can verify the sender is a member of the admin role
+	CommandRequestTable = "steampipe_command_request"
+
+	// ServiceUserTable is the table used to record the service users and the connections they may access
+	// (only the root user may read it)
+	ServiceUserTable = "steampipe_service_user"
+
+	// StateTable is the table used to save the connection state files when the database state storage is used
+	StateTable = "steampipe_state"
+
 	// LegacyConnectionStateTable is the table used to store steampipe connection state
 	LegacyConnectionStateTable       = "steampipe_connection_state"
 	ConnectionTable                  = "steampipe_connection"
@@ -83,6 +111,11 @@ const (
 	FunctionCacheSet             = "meta_cache"
 	FunctionConnectionCacheClear = "meta_connection_cache_clear"
 	FunctionCacheSetTtl          = "meta_cache_ttl"
+	// FunctionCheckCommandPermission is the trigger function which restricts privileged settings to the admin role
+	FunctionCheckCommandPermission = "check_command_permission"
+	// FunctionGrantServiceUserAccess is the event trigger function which grants service users access
+	// to their connection schemas when the schemas are (re)created
+	FunctionGrantServiceUserAccess = "grant_service_user_access"
 
 	// legacy
 	LegacyCommandSchema = "steampipe_command"
@@ -112,6 +145,43 @@ var ReservedConnectionNames = []string{
 
 const ReservedConnectionNamePrefix = "steampipe_"
 
+// masking policies - how the columns listed in the mask_columns connection option are masked from non-admin users
+const (
+	// MaskingPolicyHide revokes access to the masked columns in the connection schema
+	MaskingPolicyHide = "hide"
+	// the other policies also create a schema of views in which the masked columns are replaced by
+	// null, the text 'REDACTED', or a sha256 hash of the value
+	MaskingPolicyNull   = "null"
+	MaskingPolicyRedact = "redact"
+	MaskingPolicyHash   = "hash"
+)
+
+// MaskingPolicies is a handy array of all masking policies
+var MaskingPolicies = []string{
+	MaskingPolicyHide,
+	MaskingPolicyNull,
+	MaskingPolicyRedact,
+	MaskingPolicyHash,
+}
+
+// ColumnTypeOverrideTypes are the types the column_types connection option may set for a column of a foreign table
+// the values returned by the plugin are converted to the type from their text representation,
+// so e.g. a jsonb column may be read as text, but not a text column as jsonb unless it contains valid json
+// a numeric column may not be read as a date or timestamp - epoch times are not converted
+var ColumnTypeOverrideTypes = []string{
+	"text",
+	"jsonb",
+	"bigint",
+	"numeric",
+	"double precision",
+	"boolean",
+	"date",
+	"timestamp",
+	"timestamptz",
+	"inet",
+	"cidr",
+}
+
 // introspection table names
 const (
 	IntrospectionTableQuery              = "steampipe_query"
@@ -134,7 +204,8 @@ const (
 )
 
 const (
-	RuntimeParamsKeyApplicationName = "application_name"
+	RuntimeParamsKeyApplicationName            = "application_name"
+	RuntimeParamsKeyDefaultTransactionReadOnly = "default_transaction_read_only"
 )
 
 // Invoker is a pseudoEnum for the command/operation which starts the service
@@ -17,6 +17,11 @@ const DefaultConnectionConfigContent = `
 #   cache              = true                  # true, false
 #   cache_max_ttl      = 900                   # max expiration (TTL) in seconds
 #   cache_max_size_mb  = 1024                  # max total size of cache across all plugins
+#   max_query_duration = 300                   # maximum time (in seconds) a query from a non-superuser session may run (0 for no limit)
+#   schema_name_case          = "preserve"   # preserve, lower - the case of the schema created for each connection
+#   schema_name_invalid_chars = "error"      # error, quote, transliterate - how connection names which are not valid identifiers are handled
+#   maintenance_interval      = 1440         # interval (in minutes) at which the service vacuums and analyzes its internal tables (0 to disable)
+#   state_storage             = "file"       # file, database - where the connection state files are saved (use database when multiple nodes share a database)
 # }
 
 # options "dashboard" {
@@ -27,8 +32,26 @@ const DefaultConnectionConfigContent = `
 # options "general" {
 #   update_check = true    		# true, false
 #   telemetry    = "info"  		# info, none
+#   telemetry_redact = "signature,os_platform"	# comma-separated string; fields to remove from the usage data sent by the update check (signature, os_platform, arch)
+#   telemetry_export = false	# record the usage data locally each time the update check runs (even if update checks are disabled)
 #   log_level    = "info"  		# trace, debug, info, warn, error
 #   memory_max_mb    = "1024"	# the maximum memory to allow the CLI process in MB 
+#   log_retention_days       = 7	# the number of days to keep log files before they are garbage collected
+#   temp_dir_retention_hours = 24	# the number of hours to keep abandoned temporary install directories before they are garbage collected
+#   strict_config            = false	# fail if the config or workspace profiles contain settings which would otherwise be ignored
+# }
+
+# options "network" {
+#   ca_cert_file    = "~/certs/corporate-ca.pem"   # PEM file of additional CA certificates to trust
+#   https_proxy     = "http://proxy.example.com:3128"  # proxy used for https requests (defaults to the HTTPS_PROXY env var)
+#   http_proxy      = "http://proxy.example.com:3128"  # proxy used for http requests (defaults to the HTTP_PROXY env var)
+#   no_proxy        = "localhost,.internal"            # hosts which should not use the proxy (defaults to the NO_PROXY env var)
+#   tls_skip_verify = false                             # disable TLS certificate verification - NOT recommended
+# }
+
+# options "image" {
+#   verify     = "warn"                     # strict, warn, off - verify the cosign signatures of installed plugin and database images (defaults to warn if verify_key is set, otherwise off)
+#   verify_key = "~/keys/steampipe.pub"     # PEM file of the public keys image signatures are verified with
 # }
 
 # options "plugin" {
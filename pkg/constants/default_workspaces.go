@@ -15,7 +15,7 @@ const DefaultWorkspaceContent = `
 #   mod_location       = "~/src/steampipe-mod-aws-insights"  
 #   query_timeout      = 300
 #   snapshot_location  = "acme/dev"
-#   workspace_database = "local" 
+#   workspace_database = "local"    # or a comma separated list for failover, e.g. "acme/prod,acme/prod-dr"
 #   search_path        = "aws,aws_1,aws_2,gcp,gcp_1,gcp_2,slack,github"
 #   search_path_prefix = "aws_all"
 #   watch              = true
@@ -33,14 +33,18 @@ const DefaultWorkspaceContent = `
 #     header       = true    # true, false
 #     multi        = false   # true, false
 #     output       = "table" # json, csv, table, line
-#     separator    = ","     # any single char
-#     timing       = on   # off, on, verbose
+#     separator    = ","     # any single char, or "tab"
+#     quote_mode   = "minimal" # minimal, all, needed
+#     timing       = "on"  # off, on, verbose
+#     timezone     = "local" # local, utc or an IANA timezone name
+#     timestamp_format = "rfc3339" # rfc3339, rfc3339ms, rfc1123, datetime, kitchen or a Go time layout
 #   }
 # 
 #   options "check" {
 #     header    = true    # true, false
 #     output    = "text"  # brief, csv, html, json, md, text, snapshot or none (default "text")
-#     separator = ","     # any single char
+#     separator = ","     # any single char, or "tab"
+#     quote_mode = "needed" # minimal, all, needed
 #     timing    = true    # true, false
 #   }
 #   
@@ -8,7 +8,16 @@ const (
 	SpinnerShowTimeout = 1 * time.Second
 
 	MaxColumnWidth = 1024
+	// MaxColumnWidthAuto may be passed to --max-col-width to fit the table output to the terminal width
+	MaxColumnWidthAuto = "auto"
+
+	// TruncationIndicator is appended to column values which have been truncated in table output
+	TruncationIndicator = "…"
 
 	// NullString is the string which is displayed for null column values
 	NullString = "<null>"
+
+	// TimezoneLocal and TimezoneUTC are the special values which may be passed to --timezone
+	TimezoneLocal = "local"
+	TimezoneUTC   = "utc"
 )
@@ -3,20 +3,29 @@ package constants
 // Environment Variables
 const (
 	EnvUpdateCheck     = "STEAMPIPE_UPDATE_CHECK"
+	EnvOffline         = "STEAMPIPE_OFFLINE"
 	EnvInstallDir      = "STEAMPIPE_INSTALL_DIR"
 	EnvInstallDatabase = "STEAMPIPE_INITDB_DATABASE_NAME"
 	EnvServicePassword = "STEAMPIPE_DATABASE_PASSWORD"
 	EnvMaxParallel     = "STEAMPIPE_MAX_PARALLEL"
 
-	EnvDatabaseStartTimeout  = "STEAMPIPE_DATABASE_START_TIMEOUT"
-	EnvDatabaseSSLPassword   = "STEAMPIPE_DATABASE_SSL_PASSWORD"
-	EnvDashboardStartTimeout = "STEAMPIPE_DASHBOARD_START_TIMEOUT"
+	EnvDatabaseStartTimeout   = "STEAMPIPE_DATABASE_START_TIMEOUT"
+	EnvDatabaseSSLPassword    = "STEAMPIPE_DATABASE_SSL_PASSWORD"
+	EnvServiceUserPassword    = "STEAMPIPE_SERVICE_USER_PASSWORD"
+	EnvRefreshConcurrency     = "STEAMPIPE_REFRESH_CONCURRENCY"
+	EnvMaxQueryDuration       = "STEAMPIPE_MAX_QUERY_DURATION"
+	EnvSchemaNameCase         = "STEAMPIPE_SCHEMA_NAME_CASE"
+	EnvSchemaNameInvalidChars = "STEAMPIPE_SCHEMA_NAME_INVALID_CHARS"
+	EnvMaintenanceInterval    = "STEAMPIPE_MAINTENANCE_INTERVAL"
+	EnvStateStorage           = "STEAMPIPE_STATE_STORAGE"
+	EnvDashboardStartTimeout  = "STEAMPIPE_DASHBOARD_START_TIMEOUT"
 
-	EnvSnapshotLocation  = "STEAMPIPE_SNAPSHOT_LOCATION"
-	EnvWorkspaceDatabase = "STEAMPIPE_WORKSPACE_DATABASE"
-	EnvWorkspaceProfile  = "STEAMPIPE_WORKSPACE"
-	EnvCloudHost         = "STEAMPIPE_CLOUD_HOST"
-	EnvCloudToken        = "STEAMPIPE_CLOUD_TOKEN"
+	EnvSnapshotLocation   = "STEAMPIPE_SNAPSHOT_LOCATION"
+	EnvWorkspaceDatabase  = "STEAMPIPE_WORKSPACE_DATABASE"
+	EnvWorkspaceProfile   = "STEAMPIPE_WORKSPACE"
+	EnvVariablesWorkspace = "STEAMPIPE_VARIABLES_WORKSPACE"
+	EnvCloudHost          = "STEAMPIPE_CLOUD_HOST"
+	EnvCloudToken         = "STEAMPIPE_CLOUD_TOKEN"
 
 	EnvPipesHost  = "PIPES_HOST"
 	EnvPipesToken = "PIPES_TOKEN"
@@ -27,11 +36,14 @@ const (
 	EnvCacheMaxTTL  = "STEAMPIPE_CACHE_MAX_TTL"
 	EnvCacheMaxSize = "STEAMPIPE_CACHE_MAX_SIZE_MB"
 	EnvQueryTimeout = "STEAMPIPE_QUERY_TIMEOUT"
+	EnvExitCodeMap  = "STEAMPIPE_EXIT_CODE_MAP"
 
 	EnvConnectionWatcher        = "STEAMPIPE_CONNECTION_WATCHER"
 	EnvWorkspaceChDir           = "STEAMPIPE_WORKSPACE_CHDIR"
 	EnvModLocation              = "STEAMPIPE_MOD_LOCATION"
 	EnvTelemetry                = "STEAMPIPE_TELEMETRY"
+	EnvTelemetryRedact          = "STEAMPIPE_TELEMETRY_REDACT"
+	EnvTelemetryExport          = "STEAMPIPE_TELEMETRY_EXPORT"
 	EnvIntrospection            = "STEAMPIPE_INTROSPECTION"
 	EnvWorkspaceProfileLocation = "STEAMPIPE_WORKSPACE_PROFILES_LOCATION"
 
@@ -43,4 +55,53 @@ const (
 
 	EnvMemoryMaxMb       = "STEAMPIPE_MEMORY_MAX_MB"
 	EnvMemoryMaxMbPlugin = "STEAMPIPE_PLUGIN_MEMORY_MAX_MB"
+
+	// EnvCaCertFile is a PEM file of additional CA certificates to trust for outbound requests
+	EnvCaCertFile = "STEAMPIPE_CA_CERT_FILE"
+
+	// EnvDatabaseArchive is a local OCI archive containing the database image, used to install the database
+	// without registry access
+	EnvDatabaseArchive = "STEAMPIPE_DATABASE_ARCHIVE"
+	// EnvFdwArchive is a local OCI archive containing the FDW image, used to install the FDW without registry access
+	EnvFdwArchive = "STEAMPIPE_FDW_ARCHIVE"
+
+	// EnvWebhookSecret is the secret used to sign webhook export payloads
+	EnvWebhookSecret = "STEAMPIPE_WEBHOOK_SECRET"
+
+	// options block settings which are not otherwise flags
+	EnvLogRetentionDays         = "STEAMPIPE_LOG_RETENTION_DAYS"
+	EnvTempDirRetentionHours    = "STEAMPIPE_TEMP_DIR_RETENTION_HOURS"
+	EnvStrictConfig             = "STEAMPIPE_STRICT_CONFIG"
+	EnvImageVerify              = "STEAMPIPE_IMAGE_VERIFY"
+	EnvImageVerifyKey           = "STEAMPIPE_IMAGE_VERIFY_KEY"
+	EnvDatabaseListen           = "STEAMPIPE_DATABASE_LISTEN"
+	EnvDatabasePort             = "STEAMPIPE_DATABASE_PORT"
+	EnvDatabaseSearchPath       = "STEAMPIPE_DATABASE_SEARCH_PATH"
+	EnvDatabaseSearchPathPrefix = "STEAMPIPE_DATABASE_SEARCH_PATH_PREFIX"
+	EnvDashboardBrowser         = "STEAMPIPE_DASHBOARD_BROWSER"
+	EnvDashboardListen          = "STEAMPIPE_DASHBOARD_LISTEN"
+	EnvDashboardPort            = "STEAMPIPE_DASHBOARD_PORT"
+	EnvOutput                   = "STEAMPIPE_OUTPUT"
+	EnvSeparator                = "STEAMPIPE_SEPARATOR"
+	EnvHeader                   = "STEAMPIPE_HEADER"
+	EnvQuoteMode                = "STEAMPIPE_QUOTE_MODE"
+	EnvMulti                    = "STEAMPIPE_MULTI"
+	EnvTiming                   = "STEAMPIPE_TIMING"
+	EnvAutoComplete             = "STEAMPIPE_AUTOCOMPLETE"
+	EnvTimezone                 = "STEAMPIPE_TIMEZONE"
+	EnvTimestampFormat          = "STEAMPIPE_TIMESTAMP_FORMAT"
+	EnvSearchPath               = "STEAMPIPE_SEARCH_PATH"
+	EnvSearchPathPrefix         = "STEAMPIPE_SEARCH_PATH_PREFIX"
+	EnvWatch                    = "STEAMPIPE_WATCH"
+	EnvHttpProxy                = "STEAMPIPE_HTTP_PROXY"
+	EnvHttpsProxy               = "STEAMPIPE_HTTPS_PROXY"
+	EnvNoProxy                  = "STEAMPIPE_NO_PROXY"
+	EnvTlsSkipVerify            = "STEAMPIPE_TLS_SKIP_VERIFY"
+
+	// EnvConfigKey is the age identity (AGE-SECRET-KEY-1...) used to encrypt/decrypt config values
+	// (if not set, the key stored in the OS keyring or the key file in the internal directory is used)
+	EnvConfigKey = "STEAMPIPE_CONFIG_KEY"
+	// EnvConfigPassphrase is the passphrase protecting the config encryption key file
+	// (if not set, the passphrase is prompted for)
+	EnvConfigPassphrase = "STEAMPIPE_CONFIG_PASSPHRASE"
 )
@@ -8,15 +8,34 @@ const (
 	ExitCodePluginListFailure           = 12  // plugin - listing failed
 	ExitCodePluginNotFound              = 13  // plugin - not found
 	ExitCodePluginInstallFailure        = 14  // plugin - install failed
+	ExitCodePluginDebugFailure          = 15  // plugin - failed to update or tail trace logging
+	ExitCodePluginIncompatible          = 16  // plugin - one or more installed plugins are incompatible with the CLI
 	ExitCodeSnapshotCreationFailed      = 21  // snapshot - creation failed
 	ExitCodeSnapshotUploadFailed        = 22  // snapshot - upload failed
+	ExitCodeDashboardAssetsFailed       = 23  // dashboard - assets install failed
 	ExitCodeServiceSetupFailure         = 31  // service - setup failed
 	ExitCodeServiceStartupFailure       = 32  // service - start failed
 	ExitCodeServiceStopFailure          = 33  // service - stop failed
+	ExitCodeServiceProbeFailed          = 34  // service - probe failed (service not live or not ready)
+	ExitCodeServiceReloadFailure        = 35  // service - reload failed
+	ExitCodeServiceGcFailure            = 36  // service - garbage collection failed
+	ExitCodeServiceMaintenanceFailure   = 37  // service - maintenance failed
+	ExitCodeServiceUserFailure          = 38  // service - failed to add, remove or list service users
 	ExitCodeQueryExecutionFailed        = 41  // query - 1 or more queries failed - change in behavior(previously the exitCode used to be the number of queries that failed)
+	ExitCodeBenchFailed                 = 45  // bench - 1 or more benchmarks failed
+	ExitCodeControlsPolicyFailed        = 46  // check - no runtime errors, the results do not meet the thresholds of the policy
 	ExitCodeLoginCloudConnectionFailed  = 51  // login - connecting to cloud failed
 	ExitCodeModInitFailed               = 61  // mod - init failed
 	ExitCodeModInstallFailed            = 62  // mod - install failed
+	ExitCodeModUsageMissingColumns      = 63  // mod - usage found references to tables or columns which no longer exist
+	ExitCodeConfigEncryptionFailed      = 71  // config - encryption or decryption failed
+	ExitCodeConfigLoadFailed            = 72  // config - the steampipe config or workspace mod could not be loaded
+	ExitCodeConnectionStateFailed       = 81  // connection - failed to load connection state
+	ExitCodeDatabaseConnectionFailed    = 82  // connection - failed to connect to the steampipe database
+	ExitCodeConnectionRefreshFailed     = 83  // connection - one or more connections failed to refresh
+	ExitCodeConnectionDiscoveryFailed   = 84  // connection - one or more connection discoveries failed
+	ExitCodeConnectionCheckFailed       = 85  // connection - one or more connections failed the health check
+	ExitCodeInitFailed                  = 91  // init - onboarding failed
 	ExitCodeInvalidExecutionEnvironment = 249 // common - when steampipe is run in an unsupported environment
 	ExitCodeInitializationFailed        = 250 // common - initialization failed
 	ExitCodeBindPortUnavailable         = 251 // common(service/dashboard) - port binding failed
@@ -25,3 +44,85 @@ const (
 	ExitCodeInsufficientOrWrongInputs   = 254 // common - runtime error(insufficient or wrong input)
 	ExitCodeUnknownErrorPanic           = 255 // common - runtime error(unknown panic)
 )
+
+// exit code classes - these group the exit codes by the class of failure,
+// allowing all exit codes of a class to be remapped using --exit-code-map
+const (
+	ExitCodeClassAlarm           = "alarm"            // check - 1 or more controls in alarm
+	ExitCodeClassControlError    = "control_error"    // check - 1 or more controls in error
+	ExitCodeClassPolicyFailure   = "policy_failure"   // check - the results do not meet the thresholds of the policy
+	ExitCodeClassQueryError      = "query_error"      // query/bench - 1 or more queries or benchmarks failed
+	ExitCodeClassConfigError     = "config_error"     // config or mod could not be loaded, or invalid arguments
+	ExitCodeClassConnectionError = "connection_error" // failed to connect to the database or load connection state
+	ExitCodeClassPluginError     = "plugin_error"     // plugin command failed
+	ExitCodeClassModError        = "mod_error"        // mod command failed
+	ExitCodeClassServiceError    = "service_error"    // service command failed
+)
+
+var exitCodeClasses = map[string][]int{
+	ExitCodeClassAlarm:         {ExitCodeControlsAlarm},
+	ExitCodeClassControlError:  {ExitCodeControlsError},
+	ExitCodeClassPolicyFailure: {ExitCodeControlsPolicyFailed},
+	ExitCodeClassQueryError:    {ExitCodeQueryExecutionFailed, ExitCodeBenchFailed},
+	ExitCodeClassConfigError: {
+		ExitCodeConfigEncryptionFailed,
+		ExitCodeConfigLoadFailed,
+		ExitCodeNoModFile,
+		ExitCodeInsufficientOrWrongInputs,
+	},
+	ExitCodeClassConnectionError: {
+		ExitCodeConnectionStateFailed,
+		ExitCodeDatabaseConnectionFailed,
+		ExitCodeConnectionRefreshFailed,
+		ExitCodeConnectionDiscoveryFailed,
+		ExitCodeConnectionCheckFailed,
+		ExitCodeLoginCloudConnectionFailed,
+	},
+	ExitCodeClassPluginError: {
+		ExitCodePluginLoadingError,
+		ExitCodePluginListFailure,
+		ExitCodePluginNotFound,
+		ExitCodePluginInstallFailure,
+		ExitCodePluginDebugFailure,
+		ExitCodePluginIncompatible,
+	},
+	ExitCodeClassModError: {ExitCodeModInitFailed, ExitCodeModInstallFailed, ExitCodeModUsageMissingColumns},
+	ExitCodeClassServiceError: {
+		ExitCodeServiceSetupFailure,
+		ExitCodeServiceStartupFailure,
+		ExitCodeServiceStopFailure,
+		ExitCodeServiceProbeFailed,
+		ExitCodeServiceReloadFailure,
+		ExitCodeServiceGcFailure,
+		ExitCodeServiceMaintenanceFailure,
+		ExitCodeServiceUserFailure,
+		ExitCodeBindPortUnavailable,
+	},
+}
+
+// ExitCodeClasses returns the names of all exit code classes
+func ExitCodeClasses() []string {
+	return []string{
+		ExitCodeClassAlarm,
+		ExitCodeClassControlError,
+		ExitCodeClassPolicyFailure,
+		ExitCodeClassQueryError,
+		ExitCodeClassConfigError,
+		ExitCodeClassConnectionError,
+		ExitCodeClassPluginError,
+		ExitCodeClassModError,
+		ExitCodeClassServiceError,
+	}
+}
+
+// ExitCodeClass returns the class of the given exit code, or an empty string if it does not belong to a class
+func ExitCodeClass(exitCode int) string {
+	for class, exitCodes := range exitCodeClasses {
+		for _, c := range exitCodes {
+			if c == exitCode {
+				return class
+			}
+		}
+	}
+	return ""
+}
@@ -56,6 +56,51 @@ var QueryTimingValueLookup = map[string]struct{}{
 	"false":              {},
 }
 
+// timing source flag values
+const (
+	TimingSourceScanMetadata = "scan_metadata"
+	TimingSourceExplain      = "explain"
+)
+
+type QueryTimingSource enumflag.Flag
+
+const (
+	// scan_metadata is the default - read the scan metadata recorded by the fdw
+	QueryTimingSourceScanMetadata QueryTimingSource = iota
+	QueryTimingSourceExplain
+	QueryTimingSourceOff
+)
+
+var QueryTimingSourceIds = map[QueryTimingSource][]string{
+	QueryTimingSourceScanMetadata: {TimingSourceScanMetadata},
+	QueryTimingSourceExplain:      {TimingSourceExplain},
+	QueryTimingSourceOff:          {constants.ArgOff},
+}
+
+// csv quote mode flag values
+const (
+	// quote fields which require it, and empty strings (so they are distinct from nulls)
+	CsvQuoteMinimal = "minimal"
+	// quote all fields except nulls
+	CsvQuoteAll = "all"
+	// quote only fields which require it - nulls and empty strings are both written as an empty field
+	CsvQuoteNeeded = "needed"
+)
+
+type CsvQuoteMode enumflag.Flag
+
+const (
+	CsvQuoteModeMinimal CsvQuoteMode = iota
+	CsvQuoteModeAll
+	CsvQuoteModeNeeded
+)
+
+var CsvQuoteModeIds = map[CsvQuoteMode][]string{
+	CsvQuoteModeMinimal: {CsvQuoteMinimal},
+	CsvQuoteModeAll:     {CsvQuoteAll},
+	CsvQuoteModeNeeded:  {CsvQuoteNeeded},
+}
+
 type CheckTimingMode enumflag.Flag
 
 const (
@@ -107,3 +152,29 @@ func FlagValues[T comparable](mappings map[T][]string) []string {
 	return res
 
 }
+
+// progress flag values
+const (
+	ProgressTTY   = "tty"
+	ProgressPlain = "plain"
+	ProgressNone  = "none"
+)
+
+type ProgressMode enumflag.Flag
+
+const (
+	// true is the default - display a spinner if the output is a terminal
+	ProgressModeTrue ProgressMode = iota
+	ProgressModeFalse
+	ProgressModeTTY
+	ProgressModePlain
+	ProgressModeNone
+)
+
+var ProgressModeIds = map[ProgressMode][]string{
+	ProgressModeTrue:  {"true"},
+	ProgressModeFalse: {"false"},
+	ProgressModeTTY:   {ProgressTTY},
+	ProgressModePlain: {ProgressPlain},
+	ProgressModeNone:  {ProgressNone},
+}
@@ -0,0 +1,10 @@
+package constants
+
+// constants for image signature verification config
+const (
+	ImageVerifyStrict = "strict"
+	ImageVerifyWarn   = "warn"
+	ImageVerifyOff    = "off"
+)
+
+var ImageVerifyModes = []string{ImageVerifyStrict, ImageVerifyWarn, ImageVerifyOff}
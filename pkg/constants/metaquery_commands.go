@@ -22,6 +22,10 @@ const (
 	CmdCache            = ".cache"              // cache control
 	CmdCacheTtl         = ".cache_ttl"          // set cache ttl
 	CmdAutoComplete     = ".autocomplete"       // enable or disable auto complete
+	CmdNullValue        = ".nullvalue"          // set the string used to display null values
+	CmdWidth            = ".width"              // set the max width of table output columns
+	CmdBrowse           = ".browse"             // browse connections, tables and columns
+	CmdCancel           = ".cancel"             // cancel the query running in a session
 )
 
 // ArgFromMetaquery converts a metaquery of form '.header' into the config argument used to set the mode, i.e. 'header'
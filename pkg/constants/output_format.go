@@ -10,4 +10,8 @@ const (
 	OutputFormatBrief         = "brief"
 	OutputFormatSnapshot      = "snapshot"
 	OutputFormatSnapshotShort = "sps"
+	OutputFormatPrometheus    = "prometheus"
 )
+
+// QueryOutputFormats are the valid output formats of the query command
+var QueryOutputFormats = []string{OutputFormatLine, OutputFormatCSV, OutputFormatTable, OutputFormatJSON, OutputFormatSnapshot, OutputFormatSnapshotShort, OutputFormatNone}
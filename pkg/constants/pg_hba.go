@@ -5,13 +5,14 @@ hostssl all root samehost trust
 host all root samehost trust
 `
 
-// PgHbaTemplate is to be formatted with two variables:
+// PgHbaTemplate is to be formatted with three variables:
 //   - databaseName
 //   - username
+//   - serviceUsersRole
 //
 // Example:
 //
-//	fmt.Sprintf(template, datName, username)
+//	fmt.Sprintf(template, datName, username, serviceUsersRole)
 var PgHbaTemplate string = `
 # PostgreSQL Client Authentication Configuration File
 # ===================================================
@@ -43,4 +44,14 @@ hostssl %[1]s %[2]s samehost trust
 host    %[1]s %[2]s samehost trust
 hostssl %[1]s %[2]s all scram-sha-256
 host    %[1]s %[2]s all scram-sha-256
+
+# Service users (added with 'steampipe service user add') are members of the
+# service users role. They may only read from the connection schemas they have
+# been granted access to.
+#
+# The configuration is:
+# * Access from any host, including samehost, requires a password
+#
+hostssl %[1]s +%[3]s all scram-sha-256
+host    %[1]s +%[3]s all scram-sha-256
 `
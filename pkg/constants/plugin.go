@@ -9,4 +9,7 @@ const (
 	ConnectionErrorPluginNotInstalled          = "plugin not installed"
 
 	SteampipeHubOCIBase = "hub.steampipe.io/"
+
+	// the default number of plugins installed or updated concurrently
+	DefaultPluginInstallConcurrency = 4
 )
@@ -0,0 +1,19 @@
+package constants
+
+// constants for the mapping of connection names to schema names
+const (
+	// schema names have the same case as the connection name
+	SchemaNameCasePreserve = "preserve"
+	// schema names are the lower case connection name, so they can be used in queries without quoting
+	SchemaNameCaseLower = "lower"
+
+	// connection names containing characters which are invalid in an unquoted identifier are rejected
+	SchemaNameInvalidCharsError = "error"
+	// any characters are allowed - the schemas of these connections must be quoted in queries
+	SchemaNameInvalidCharsQuote = "quote"
+	// invalid characters are transliterated, e.g. 'Prod-Café' becomes 'Prod_Cafe'
+	SchemaNameInvalidCharsTransliterate = "transliterate"
+)
+
+var SchemaNameCaseModes = []string{SchemaNameCasePreserve, SchemaNameCaseLower}
+var SchemaNameInvalidCharsModes = []string{SchemaNameInvalidCharsError, SchemaNameInvalidCharsQuote, SchemaNameInvalidCharsTransliterate}
@@ -0,0 +1,12 @@
+package constants
+
+// constants for the storage of the connection state files
+const (
+	// the state files are saved in the internal directory of the install dir
+	StateStorageFile = "file"
+	// the state files are saved in the internal schema of the database, so they are shared by every node
+	// of a deployment which uses the same database
+	StateStorageDatabase = "database"
+)
+
+var StateStorageModes = []string{StateStorageFile, StateStorageDatabase}
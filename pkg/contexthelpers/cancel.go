@@ -5,14 +5,18 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
 )
 
+// StartCancelHandler cancels the context when the process is interrupted (Ctrl+C) or terminated (SIGTERM),
+// rather than exiting immediately - so in-progress work can clean up, e.g. removing install temp dirs
+// and stopping any database process it started
 func StartCancelHandler(cancel context.CancelFunc) {
 	sigIntChannel := make(chan os.Signal, 1)
-	signal.Notify(sigIntChannel, os.Interrupt)
+	signal.Notify(sigIntChannel, os.Interrupt, syscall.SIGTERM)
 	go func() {
-		<-sigIntChannel
-		log.Println("[TRACE] cancel handler got SIGINT")
+		sig := <-sigIntChannel
+		log.Printf("[TRACE] cancel handler got %s", sig)
 		// call context cancellation function
 		cancel()
 		// leave the channel open - any subsequent interrupts hits will be ignored
@@ -37,7 +37,7 @@ func (e *ControlExporter) Export(ctx context.Context, input export.ExportSourceD
 		return err
 	}
 
-	return export.Write(destPath, res)
+	return export.Write(ctx, destPath, res)
 }
 
 func (e *ControlExporter) FileExtension() string {
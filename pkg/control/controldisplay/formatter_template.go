@@ -56,7 +56,10 @@ func (tf TemplateFormatter) Format(ctx context.Context, tree *controlexecute.Exe
 			},
 			Config: TemplateRenderConfig{
 				RenderHeader: viper.GetBool(constants.ArgHeader),
-				Separator:    viper.GetString(constants.ArgSeparator),
+				Separator:    csvSeparator(),
+				QuoteMode:    viper.GetString(constants.ArgQuoteMode),
+				// the metadata is only available once the tree has been executed
+				RenderMetadata: viper.GetBool(constants.ArgExportMetadata) && tree.Metadata != nil,
 			},
 			Data: tree,
 		}
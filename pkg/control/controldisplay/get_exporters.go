@@ -13,5 +13,7 @@ func GetExporters(ctx context.Context) ([]export.Exporter, error) {
 		return nil, err
 	}
 	exporters := formatResolver.controlExporters()
+	// add the webhook exporter
+	exporters = append(exporters, NewWebhookExporter())
 	return exporters, nil
 }
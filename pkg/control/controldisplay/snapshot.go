@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/spf13/viper"
 	"github.com/turbot/steampipe/pkg/cloud"
-	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/control/controlexecute"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardexecute"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
@@ -67,7 +65,7 @@ func PublishSnapshot(ctx context.Context, e *controlexecute.ExecutionTree, shoul
 	if err != nil {
 		return err
 	}
-	if viper.GetBool(constants.ArgProgress) {
+	if statushooks.ProgressEnabled() {
 		statushooks.Done(ctx)
 		fmt.Println(message)
 	}
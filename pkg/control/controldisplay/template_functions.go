@@ -1,15 +1,14 @@
 package controldisplay
 
 import (
-	"bytes"
-	"encoding/csv"
 	"fmt"
-	"strings"
-	"sync"
 	"text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/display"
 )
 
 // templateFuncs merges desired functions from sprig with custom functions that we
@@ -33,7 +32,7 @@ func templateFuncs(renderContext TemplateRenderContext) template.FuncMap {
 	// custom steampipe functions - ones we couldn't find in sprig
 	formatterTemplateFuncMap := template.FuncMap{
 		"durationInSeconds": durationInSeconds,
-		"toCsvCell":         toCSVCellFnFactory(renderContext.Config.Separator),
+		"toCsvCell":         toCSVCellFnFactory(renderContext.Config.Separator, renderContext.Config.QuoteMode),
 	}
 	for k, v := range formatterTemplateFuncMap {
 		funcs[k] = v
@@ -44,25 +43,26 @@ func templateFuncs(renderContext TemplateRenderContext) template.FuncMap {
 
 // toCsvCell escapes a value for csv
 // we need to do this in a factory function, so that we can
-// set the separator for the CSV writer for this render session
-func toCSVCellFnFactory(comma string) func(interface{}) string {
-	csvWriterBuffer := bytes.NewBufferString("")
-	csvBufferLock := sync.Mutex{}
-
-	csvWriter := csv.NewWriter(csvWriterBuffer)
+// set the separator and quote mode for this render session
+// values are formatted with fmt, so nil values are written as '<nil>'
+func toCSVCellFnFactory(comma string, quoteMode string) func(interface{}) string {
+	separator := ','
 	if len(comma) > 0 {
-		csvWriter.Comma = []rune(comma)[0]
+		separator = []rune(comma)[0]
 	}
 
 	return func(v interface{}) string {
-		csvBufferLock.Lock()
-		defer csvBufferLock.Unlock()
+		return display.QuoteCSVField(fmt.Sprintf("%v", v), false, separator, quoteMode)
+	}
+}
 
-		csvWriterBuffer.Reset()
-		csvWriter.Write([]string{fmt.Sprintf("%v", v)})
-		csvWriter.Flush()
-		return strings.TrimSpace(csvWriterBuffer.String())
+// csvSeparator returns the separator for csv output - the separator has been validated by the check command
+func csvSeparator() string {
+	separator, err := display.ParseCSVSeparator(viper.GetString(constants.ArgSeparator))
+	if err != nil {
+		return viper.GetString(constants.ArgSeparator)
 	}
+	return string(separator)
 }
 
 // durationInSeconds returns the passed in duration as seconds
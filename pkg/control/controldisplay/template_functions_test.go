@@ -2,11 +2,39 @@ package controldisplay
 
 import (
 	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
 )
 
+func TestToCsvCell(t *testing.T) {
+	tests := map[string]struct {
+		separator string
+		quoteMode string
+		value     interface{}
+		expected  string
+	}{
+		"string":                    {value: "alarm", expected: "alarm"},
+		"separator":                 {value: "a,b", expected: `"a,b"`},
+		"pipe separator":            {separator: "|", value: "a,b", expected: "a,b"},
+		"quote":                     {value: `a"b`, expected: `"a""b"`},
+		"empty string":              {value: "", expected: `""`},
+		"nil":                       {value: nil, expected: "<nil>"},
+		"nil quote all":             {quoteMode: constants.CsvQuoteAll, value: nil, expected: `"<nil>"`},
+		"number quote all":          {quoteMode: constants.CsvQuoteAll, value: 1, expected: `"1"`},
+		"empty string quote needed": {quoteMode: constants.CsvQuoteNeeded, value: "", expected: ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := toCSVCellFnFactory(test.separator, test.quoteMode)(test.value); got != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
 func BenchmarkToCsvCell(b *testing.B) {
 	// the factory is called once per render execution
-	toCsvCell := toCSVCellFnFactory("|")
+	toCsvCell := toCSVCellFnFactory("|", constants.CsvQuoteNeeded)
 	for i := 0; i < b.N; i++ {
 		toCsvCell(i)
 	}
@@ -5,6 +5,9 @@ import "github.com/turbot/steampipe/pkg/control/controlexecute"
 type TemplateRenderConfig struct {
 	RenderHeader bool
 	Separator    string
+	QuoteMode    string
+	// if set, the run metadata is included in the output
+	RenderMetadata bool
 }
 
 type TemplateRenderConstants struct {
@@ -0,0 +1,172 @@
+package controldisplay
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/network"
+)
+
+const (
+	webhookExporterName = "webhook"
+	// the header containing the HMAC-SHA256 signature of the payload (if a webhook secret is set)
+	webhookSignatureHeader = "X-Steampipe-Signature-256"
+	webhookMaxRetries      = 3
+	webhookTimeout         = 30 * time.Second
+)
+
+// webhookRetryBackoff is the initial delay before retrying a failed webhook request (a var so tests may override it)
+var webhookRetryBackoff = time.Second
+
+// WebhookExporter POSTs a summary of the check results to a url, e.g. --export webhook:https://example.com/hook
+//
+// if the STEAMPIPE_WEBHOOK_SECRET environment variable is set, the payload is signed using HMAC-SHA256
+// and the signature is sent in the X-Steampipe-Signature-256 header
+type WebhookExporter struct {
+	export.ExporterBase
+}
+
+func NewWebhookExporter() *WebhookExporter {
+	return &WebhookExporter{}
+}
+
+// webhookPayload is the summarized check result sent to the webhook
+type webhookPayload struct {
+	Name      string                      `json:"name"`
+	Title     string                      `json:"title,omitempty"`
+	StartTime time.Time                   `json:"start_time"`
+	EndTime   time.Time                   `json:"end_time"`
+	Summary   controlstatus.StatusSummary `json:"summary"`
+	// the results in alarm or error
+	Findings []webhookFinding `json:"findings"`
+}
+
+type webhookFinding struct {
+	Control    string                     `json:"control"`
+	Title      string                     `json:"title,omitempty"`
+	Severity   string                     `json:"severity,omitempty"`
+	Status     string                     `json:"status"`
+	Reason     string                     `json:"reason"`
+	Resource   string                     `json:"resource"`
+	Dimensions []controlexecute.Dimension `json:"dimensions,omitempty"`
+}
+
+func (e *WebhookExporter) Export(ctx context.Context, input export.ExportSourceData, destPath string) error {
+	tree, ok := input.(*controlexecute.ExecutionTree)
+	if !ok {
+		return fmt.Errorf("WebhookExporter input must be *controlexecute.ExecutionTree")
+	}
+	// fail fast rather than retrying requests which cannot succeed
+	if err := network.CheckOnline("sending check results to a webhook"); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(newWebhookPayload(tree))
+	if err != nil {
+		return err
+	}
+
+	backoff := retry.WithMaxRetries(webhookMaxRetries, retry.NewExponential(webhookRetryBackoff))
+	return retry.Do(ctx, backoff, func(ctx context.Context) error {
+		err := e.post(ctx, destPath, body)
+		if err != nil {
+			log.Printf("[WARN] webhook export to %s failed: %s", destPath, err.Error())
+		}
+		return err
+	})
+}
+
+func (e *WebhookExporter) post(ctx context.Context, url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := os.Getenv(constants.EnvWebhookSecret); secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookPayload(body, secret))
+	}
+
+	// use the configured CA bundle, proxy and TLS settings
+	resp, err := network.HttpClient().Do(req)
+	if err != nil {
+		// network errors are retryable
+		return retry.RetryableError(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return retry.RetryableError(fmt.Errorf("webhook returned status %s", resp.Status))
+	default:
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+}
+
+// signWebhookPayload returns the hex encoded HMAC-SHA256 signature of the payload
+func signWebhookPayload(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookPayload(tree *controlexecute.ExecutionTree) *webhookPayload {
+	payload := &webhookPayload{
+		Name:      tree.Root.GroupId,
+		Title:     tree.Root.Title,
+		StartTime: tree.StartTime,
+		EndTime:   tree.EndTime,
+		Findings:  []webhookFinding{},
+	}
+	if tree.Root.Summary != nil {
+		payload.Summary = tree.Root.Summary.Status
+	}
+	for _, run := range tree.ControlRuns {
+		for _, row := range run.Rows {
+			if row.Status != constants.ControlAlarm && row.Status != constants.ControlError {
+				continue
+			}
+			payload.Findings = append(payload.Findings, webhookFinding{
+				Control:    run.FullName,
+				Title:      run.Title,
+				Severity:   run.Severity,
+				Status:     row.Status,
+				Reason:     row.Reason,
+				Resource:   row.Resource,
+				Dimensions: row.Dimensions,
+			})
+		}
+	}
+	return payload
+}
+
+func (e *WebhookExporter) FileExtension() string {
+	return ""
+}
+
+func (e *WebhookExporter) Name() string {
+	return webhookExporterName
+}
+
+// IsDestinationExporter implements export.DestinationExporter
+func (e *WebhookExporter) IsDestinationExporter() {}
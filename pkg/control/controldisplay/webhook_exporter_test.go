@@ -0,0 +1,215 @@
+package controldisplay
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/network"
+)
+
+// webhookTestServer responds to each request with the next of the given status codes (repeating the last),
+// recording the requests it receives
+type webhookTestServer struct {
+	*httptest.Server
+	statusCodes []int
+	mut         sync.Mutex
+	bodies      [][]byte
+	signatures  []string
+}
+
+func newWebhookTestServer(t *testing.T, statusCodes ...int) *webhookTestServer {
+	prevBackoff := webhookRetryBackoff
+	webhookRetryBackoff = time.Millisecond
+	t.Cleanup(func() { webhookRetryBackoff = prevBackoff })
+
+	s := &webhookTestServer{statusCodes: statusCodes}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mut.Lock()
+		defer s.mut.Unlock()
+		s.bodies = append(s.bodies, body)
+		s.signatures = append(s.signatures, r.Header.Get(webhookSignatureHeader))
+		status := s.statusCodes[len(s.statusCodes)-1]
+		if len(s.bodies) <= len(s.statusCodes) {
+			status = s.statusCodes[len(s.bodies)-1]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newWebhookTestTree() *controlexecute.ExecutionTree {
+	run := &controlexecute.ControlRun{FullName: "mod.control.c1", Title: "Control 1", Severity: "high"}
+	run.Rows = controlexecute.ResultRows{
+		{Status: constants.ControlOk, Resource: "r1", Reason: "r1 is ok"},
+		{Status: constants.ControlAlarm, Resource: "r2", Reason: "r2 is in alarm"},
+		{Status: constants.ControlError, Resource: "r3", Reason: "r3 failed"},
+	}
+	root := &controlexecute.ResultGroup{
+		GroupId: "mod.benchmark.b1",
+		Title:   "Benchmark 1",
+		Summary: &controlexecute.GroupSummary{Status: controlstatus.StatusSummary{Ok: 1, Alarm: 1, Error: 1}},
+	}
+	return &controlexecute.ExecutionTree{Root: root, ControlRuns: []*controlexecute.ControlRun{run}}
+}
+
+func TestWebhookExportRetry(t *testing.T) {
+	tests := map[string]struct {
+		statusCodes      []int
+		expectedRequests int
+		err              bool
+	}{
+		"success": {
+			statusCodes:      []int{http.StatusOK},
+			expectedRequests: 1,
+		},
+		"retry server errors": {
+			statusCodes:      []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusAccepted},
+			expectedRequests: 3,
+		},
+		"retry rate limit": {
+			statusCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedRequests: 2,
+		},
+		"client error is not retried": {
+			statusCodes:      []int{http.StatusBadRequest},
+			expectedRequests: 1,
+			err:              true,
+		},
+		"retries exhausted": {
+			statusCodes:      []int{http.StatusBadGateway},
+			expectedRequests: webhookMaxRetries + 1,
+			err:              true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newWebhookTestServer(t, test.statusCodes...)
+
+			err := NewWebhookExporter().Export(context.Background(), newWebhookTestTree(), server.URL)
+			if test.err != (err != nil) {
+				t.Errorf("expected error: %v, got %v", test.err, err)
+			}
+			if len(server.bodies) != test.expectedRequests {
+				t.Errorf("expected %d requests, got %d", test.expectedRequests, len(server.bodies))
+			}
+		})
+	}
+}
+
+func TestWebhookExportOffline(t *testing.T) {
+	viper.Set(constants.ArgOffline, true)
+	defer viper.Set(constants.ArgOffline, nil)
+	server := newWebhookTestServer(t, http.StatusOK)
+
+	err := NewWebhookExporter().Export(context.Background(), newWebhookTestTree(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "offline mode") {
+		t.Errorf("expected an offline mode error, got %v", err)
+	}
+	if len(server.bodies) != 0 {
+		t.Errorf("expected no requests in offline mode, got %d", len(server.bodies))
+	}
+}
+
+// the webhook is sent using the network options, so a server using a certificate from the configured CA bundle is trusted
+func TestWebhookExportCaCertFile(t *testing.T) {
+	received := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caCertFile, caCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(constants.ArgCaCertFile, caCertFile)
+	defer func() {
+		viper.Set(constants.ArgCaCertFile, nil)
+		_, _ = network.Configure()
+	}()
+	if _, err := network.Configure(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewWebhookExporter().Export(context.Background(), newWebhookTestTree(), server.URL); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if received != 1 {
+		t.Errorf("expected 1 request, got %d", received)
+	}
+}
+
+func TestWebhookExportSignature(t *testing.T) {
+	tests := map[string]string{
+		"signed":   "webhook-secret",
+		"unsigned": "",
+	}
+	for name, secret := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(constants.EnvWebhookSecret, secret)
+			server := newWebhookTestServer(t, http.StatusOK)
+
+			if err := NewWebhookExporter().Export(context.Background(), newWebhookTestTree(), server.URL); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if secret == "" {
+				if server.signatures[0] != "" {
+					t.Errorf("expected no signature if no secret is set, got %s", server.signatures[0])
+				}
+				return
+			}
+			// the receiver verifies the signature using the body it received
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(server.bodies[0])
+			expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if server.signatures[0] != expected {
+				t.Errorf("expected signature %s, got %s", expected, server.signatures[0])
+			}
+		})
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	server := newWebhookTestServer(t, http.StatusOK)
+	if err := NewWebhookExporter().Export(context.Background(), newWebhookTestTree(), server.URL); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(server.bodies[0], &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Name != "mod.benchmark.b1" || payload.Summary.Alarm != 1 || payload.Summary.Error != 1 {
+		t.Errorf("unexpected payload name or summary: %+v", payload)
+	}
+	// only the results in alarm or error are sent
+	if len(payload.Findings) != 2 || payload.Findings[0].Resource != "r2" || payload.Findings[1].Resource != "r3" {
+		t.Errorf("expected the findings for r2 and r3, got %+v", payload.Findings)
+	}
+}
+
+func TestWebhookExportInvalidInput(t *testing.T) {
+	if err := NewWebhookExporter().Export(context.Background(), nil, "http://127.0.0.1:1"); err == nil {
+		t.Errorf("expected an error exporting data which is not an execution tree")
+	}
+}
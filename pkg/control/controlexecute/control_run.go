@@ -54,6 +54,8 @@ type ControlRun struct {
 
 	// execution duration
 	Duration time.Duration `json:"-"`
+	// the resource usage of the control query - only populated if the tree is recording resource usage
+	ResourceUsage *ResourceUsage `json:"-"`
 	// parent result group
 	Group *ResultGroup `json:"-"`
 	// execution tree
@@ -275,6 +277,30 @@ func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 	log.Printf("[TRACE] wait result for, %s\n", control.Name())
 	r.waitForResults(ctx)
 	log.Printf("[TRACE] finish result for, %s\n", control.Name())
+
+	r.recordUsage()
+}
+
+// if usage tracking is enabled, record the tables and columns fetched by the control query
+// and if resource usage is being recorded, the resource usage of the control query
+// (the timing result is populated before the row channel is closed, so is available once the results are read)
+func (r *ControlRun) recordUsage() {
+	if (r.Tree.UsageRecorder == nil && !r.Tree.RecordResourceUsage) || r.GetRunStatus() == dashboardtypes.RunError {
+		return
+	}
+	select {
+	case timingResult := <-r.queryResult.TimingResult:
+		if timingResult == nil {
+			return
+		}
+		if r.Tree.UsageRecorder != nil {
+			r.Tree.UsageRecorder.Record(r.Control.Name(), timingResult.Scans)
+		}
+		if r.Tree.RecordResourceUsage {
+			r.ResourceUsage = newResourceUsage(timingResult.Scans)
+		}
+	default:
+	}
 }
 
 // try to acquire a database session - retry up to 4 times if there is an error
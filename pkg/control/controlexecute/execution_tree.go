@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/spf13/viper"
@@ -14,6 +15,8 @@ import (
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/control/controlstatus"
 	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/modusage"
 	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
@@ -30,12 +33,18 @@ type ExecutionTree struct {
 	StartTime   time.Time                      `json:"start_time"`
 	EndTime     time.Time                      `json:"end_time"`
 	Progress    *controlstatus.ControlProgress `json:"progress"`
+	// metadata describing the run, populated when execution completes
+	Metadata *RunMetadata `json:"-"`
 	// map of dimension property name to property value to color map
 	DimensionColorGenerator *DimensionColorGenerator `json:"-"`
 	// the current session search path
 	SearchPath []string             `json:"-"`
 	Workspace  *workspace.Workspace `json:"-"`
-	client     db_common.Client
+	// if set, the tables and columns fetched by each control are recorded
+	UsageRecorder *modusage.Recorder `json:"-"`
+	// if set, the resource usage of each control is recorded (see ResourceUsageReport)
+	RecordResourceUsage bool `json:"-"`
+	client              db_common.Client
 	// an optional map of control names used to filter the controls which are run
 	controlNameFilterMap map[string]bool
 }
@@ -97,6 +106,51 @@ func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, clien
 // IsExportSourceData implements ExportSourceData
 func (*ExecutionTree) IsExportSourceData() {}
 
+// ControlPartitions implements ControlPartitionedSourceData
+// it returns an execution tree for each control run, so each control may be exported separately
+func (e *ExecutionTree) ControlPartitions() []export.ControlPartition {
+	res := make([]export.ControlPartition, len(e.ControlRuns))
+	for i, run := range e.ControlRuns {
+		res[i] = export.ControlPartition{Name: run.FullName, Data: e.controlRunTree(run)}
+	}
+	return res
+}
+
+// controlRunTree returns an execution tree containing only the given (executed) control run,
+// in the same form as a tree created to run a single control
+func (e *ExecutionTree) controlRunTree(run *ControlRun) *ExecutionTree {
+	root := &ResultGroup{
+		GroupId:       RootResultGroupName,
+		Groups:        []*ResultGroup{},
+		Tags:          make(map[string]string),
+		Summary:       NewGroupSummary(),
+		Severity:      make(map[string]controlstatus.StatusSummary),
+		updateLock:    new(sync.Mutex),
+		NodeType:      modconfig.BlockTypeBenchmark,
+		Title:         run.Title,
+		DimensionKeys: run.DimensionKeys,
+		Duration:      run.Duration,
+	}
+	root.addControl(run)
+	root.Summary.Status = *run.Summary
+	if len(run.Severity) != 0 {
+		root.Summary.Severity[run.Severity] = *run.Summary
+	}
+
+	return &ExecutionTree{
+		Root:                    root,
+		ControlRuns:             []*ControlRun{run},
+		StartTime:               e.StartTime,
+		EndTime:                 e.EndTime,
+		Progress:                e.Progress,
+		Metadata:                e.Metadata,
+		DimensionColorGenerator: e.DimensionColorGenerator,
+		SearchPath:              e.SearchPath,
+		Workspace:               e.Workspace,
+		client:                  e.client,
+	}
+}
+
 // AddControl checks whether control should be included in the tree
 // if so, creates a ControlRun, which is added to the parent group
 func (e *ExecutionTree) AddControl(ctx context.Context, control *modconfig.Control, group *ResultGroup) {
@@ -121,6 +175,7 @@ func (e *ExecutionTree) Execute(ctx context.Context) error {
 	defer func() {
 		e.EndTime = time.Now()
 		e.Progress.Finish(ctx)
+		e.Metadata = newRunMetadata(ctx, e.Workspace, e.StartTime, e.EndTime)
 	}()
 
 	// TODO should we always wait even with non custom search path?
@@ -0,0 +1,43 @@
+package controlexecute
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+func TestControlPartitions(t *testing.T) {
+	tree := &ExecutionTree{
+		ControlRuns: []*ControlRun{
+			{FullName: "mod.control.c1", Severity: "high", Summary: &controlstatus.StatusSummary{Alarm: 2, Ok: 1}},
+			{FullName: "mod.control.c2", Summary: &controlstatus.StatusSummary{Ok: 3}},
+		},
+	}
+
+	partitions := tree.ControlPartitions()
+	if len(partitions) != 2 {
+		t.Fatalf("expected 2 partitions, got %d", len(partitions))
+	}
+	for i, p := range partitions {
+		run := tree.ControlRuns[i]
+		if p.Name != run.FullName {
+			t.Errorf("expected partition name %s, got %s", run.FullName, p.Name)
+		}
+		controlTree, ok := p.Data.(*ExecutionTree)
+		if !ok {
+			t.Fatalf("expected the partition data to be an execution tree")
+		}
+		if len(controlTree.ControlRuns) != 1 || controlTree.ControlRuns[0] != run {
+			t.Errorf("expected the tree for %s to contain only its control run", run.FullName)
+		}
+		if len(controlTree.Root.Children) != 1 || controlTree.Root.Children[0] != run {
+			t.Errorf("expected the control run to be the only child of the root of the tree for %s", run.FullName)
+		}
+		if controlTree.Root.Summary.Status != *run.Summary {
+			t.Errorf("expected the summary of the tree for %s to be %v, got %v", run.FullName, *run.Summary, controlTree.Root.Summary.Status)
+		}
+	}
+	if severity := partitions[0].Data.(*ExecutionTree).Root.Summary.Severity["high"]; severity.Alarm != 2 {
+		t.Errorf("expected the severity summary to include the control run, got %v", severity)
+	}
+}
@@ -0,0 +1,79 @@
+package controlexecute
+
+import (
+	"sort"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// ResourceUsage is the resource usage of a control query, sourced from the scan metadata of the query
+type ResourceUsage struct {
+	Scans        int64 `json:"scans"`
+	CacheHits    int64 `json:"cache_hits"`
+	HydrateCalls int64 `json:"hydrate_calls"`
+	RowsFetched  int64 `json:"rows_fetched"`
+}
+
+func newResourceUsage(scans []*queryresult.ScanMetadataRow) *ResourceUsage {
+	res := &ResourceUsage{}
+	for _, scan := range scans {
+		res.Scans++
+		if scan.CacheHit {
+			res.CacheHits++
+			continue
+		}
+		res.HydrateCalls += scan.HydrateCalls
+		res.RowsFetched += scan.RowsFetched
+	}
+	return res
+}
+
+// ApiCalls returns an estimate of the number of API calls made by the query:
+// each scan which is not served from the cache lists or gets the resources, and each hydrate call fetches additional data
+func (u *ResourceUsage) ApiCalls() int64 {
+	return u.Scans - u.CacheHits + u.HydrateCalls
+}
+
+// ControlResourceUsage is the resource usage and wall time of a single control run
+type ControlResourceUsage struct {
+	Control   string
+	Benchmark string
+	ResourceUsage
+	Duration time.Duration
+}
+
+// ResourceUsageReport returns the resource usage of each control run of the tree, most expensive first
+// (only controls which were executed with resource usage recording enabled are included)
+func (e *ExecutionTree) ResourceUsageReport() []ControlResourceUsage {
+	var res []ControlResourceUsage
+	for _, r := range e.ControlRuns {
+		if r.ResourceUsage == nil {
+			continue
+		}
+		usage := ControlResourceUsage{
+			Control:       r.Control.GetUnqualifiedName(),
+			ResourceUsage: *r.ResourceUsage,
+			Duration:      r.Duration,
+		}
+		if r.Group != nil && r.Group.GroupItem != nil {
+			usage.Benchmark = r.Group.GroupItem.GetUnqualifiedName()
+		}
+		res = append(res, usage)
+	}
+	sortResourceUsage(res)
+	return res
+}
+
+// sortResourceUsage sorts by cost - the estimated number of API calls, then the wall time
+func sortResourceUsage(usage []ControlResourceUsage) {
+	sort.SliceStable(usage, func(i, j int) bool {
+		if apiCallsI, apiCallsJ := usage[i].ApiCalls(), usage[j].ApiCalls(); apiCallsI != apiCallsJ {
+			return apiCallsI > apiCallsJ
+		}
+		if usage[i].Duration != usage[j].Duration {
+			return usage[i].Duration > usage[j].Duration
+		}
+		return usage[i].Control < usage[j].Control
+	})
+}
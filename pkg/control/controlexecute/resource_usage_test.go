@@ -0,0 +1,39 @@
+package controlexecute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+func TestNewResourceUsage(t *testing.T) {
+	usage := newResourceUsage([]*queryresult.ScanMetadataRow{
+		{Table: "aws_s3_bucket", RowsFetched: 10, HydrateCalls: 30},
+		{Table: "aws_s3_bucket", CacheHit: true, RowsFetched: 10},
+		{Table: "aws_iam_role", RowsFetched: 5, HydrateCalls: 5},
+	})
+	expected := ResourceUsage{Scans: 3, CacheHits: 1, HydrateCalls: 35, RowsFetched: 15}
+	if *usage != expected {
+		t.Errorf("expected %+v, got %+v", expected, *usage)
+	}
+	if apiCalls := usage.ApiCalls(); apiCalls != 37 {
+		t.Errorf("expected 37 api calls, got %d", apiCalls)
+	}
+}
+
+func TestSortResourceUsage(t *testing.T) {
+	usage := []ControlResourceUsage{
+		{Control: "cheap", ResourceUsage: ResourceUsage{Scans: 1, CacheHits: 1}, Duration: 5 * time.Second},
+		{Control: "slow", ResourceUsage: ResourceUsage{Scans: 1, HydrateCalls: 9}, Duration: 10 * time.Second},
+		{Control: "expensive", ResourceUsage: ResourceUsage{Scans: 2, HydrateCalls: 100}, Duration: time.Second},
+		{Control: "fast", ResourceUsage: ResourceUsage{Scans: 1, HydrateCalls: 9}, Duration: time.Second},
+	}
+	sortResourceUsage(usage)
+	expected := []string{"expensive", "slow", "fast", "cheap"}
+	for i, u := range usage {
+		if u.Control != expected[i] {
+			t.Errorf("expected %s at position %d, got %s", expected[i], i, u.Control)
+		}
+	}
+}
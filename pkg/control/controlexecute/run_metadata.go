@@ -0,0 +1,132 @@
+package controlexecute
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+	"github.com/turbot/steampipe/pkg/plugin"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/versionmap"
+	"github.com/turbot/steampipe/pkg/version"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+// RunMetadata describes the environment a check run was executed in
+// it is included in exports so that they are self-describing
+type RunMetadata struct {
+	SteampipeVersion string                  `json:"steampipe_version"`
+	StartTime        time.Time               `json:"start_time"`
+	EndTime          time.Time               `json:"end_time"`
+	Mod              *RunMetadataMod         `json:"mod,omitempty"`
+	Dependencies     []RunMetadataMod        `json:"dependencies"`
+	Connections      []RunMetadataConnection `json:"connections"`
+	Variables        []RunMetadataVariable   `json:"variables"`
+}
+
+type RunMetadataMod struct {
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+type RunMetadataConnection struct {
+	Name          string `json:"name"`
+	Type          string `json:"type,omitempty"`
+	Plugin        string `json:"plugin,omitempty"`
+	PluginVersion string `json:"plugin_version,omitempty"`
+}
+
+type RunMetadataVariable struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Redacted bool   `json:"redacted"`
+}
+
+// newRunMetadata builds the metadata for a check run
+// failure to load any of the metadata is logged but is not fatal
+func newRunMetadata(ctx context.Context, w *workspace.Workspace, startTime, endTime time.Time) *RunMetadata {
+	m := &RunMetadata{
+		SteampipeVersion: version.VersionString,
+		StartTime:        startTime,
+		EndTime:          endTime,
+		Dependencies:     []RunMetadataMod{},
+		Connections:      []RunMetadataConnection{},
+		Variables:        []RunMetadataVariable{},
+	}
+	if w != nil {
+		m.setWorkspaceMetadata(ctx, w)
+	}
+	m.setConnectionMetadata(ctx)
+	return m
+}
+
+func (m *RunMetadata) setWorkspaceMetadata(ctx context.Context, w *workspace.Workspace) {
+	if w.Mod != nil {
+		m.Mod = &RunMetadataMod{Name: w.Mod.ShortName}
+		if w.Mod.Version != nil {
+			m.Mod.Version = w.Mod.Version.String()
+		}
+	}
+
+	workspaceLock, err := versionmap.LoadWorkspaceLock(ctx, w.Path)
+	if err != nil {
+		log.Printf("[WARN] failed to load workspace lock for run metadata: %s", err.Error())
+	} else {
+		for _, dep := range workspaceLock.InstallCache.FlatMap() {
+			d := RunMetadataMod{Name: dep.Name, Constraint: dep.Constraint}
+			if dep.Version != nil {
+				d.Version = dep.Version.String()
+			}
+			m.Dependencies = append(m.Dependencies, d)
+		}
+		sort.Slice(m.Dependencies, func(i, j int) bool {
+			return m.Dependencies[i].Name < m.Dependencies[j].Name
+		})
+	}
+
+	for name, value := range w.VariableValues {
+		m.Variables = append(m.Variables, newRunMetadataVariable(name, value))
+	}
+	sort.Slice(m.Variables, func(i, j int) bool {
+		return m.Variables[i].Name < m.Variables[j].Name
+	})
+}
+
+func (m *RunMetadata) setConnectionMetadata(ctx context.Context) {
+	if steampipeconfig.GlobalConfig == nil {
+		return
+	}
+	installedPlugins, err := plugin.GetInstalledPlugins(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to load installed plugins for run metadata: %s", err.Error())
+	}
+	for name, connection := range steampipeconfig.GlobalConfig.Connections {
+		c := RunMetadataConnection{
+			Name:   name,
+			Type:   connection.Type,
+			Plugin: connection.Plugin,
+		}
+		if connection.Plugin != "" {
+			org, pluginName, _ := ociinstaller.NewSteampipeImageRef(connection.Plugin).GetOrgNameAndConstraint()
+			if pluginVersion, ok := installedPlugins[fmt.Sprintf("%s/%s", org, pluginName)]; ok && pluginVersion != nil {
+				c.PluginVersion = pluginVersion.String()
+			}
+		}
+		m.Connections = append(m.Connections, c)
+	}
+	sort.Slice(m.Connections, func(i, j int) bool {
+		return m.Connections[i].Name < m.Connections[j].Name
+	})
+}
+
+func newRunMetadataVariable(name, value string) RunMetadataVariable {
+	if modconfig.IsSecretVariableName(name) {
+		return RunMetadataVariable{Name: name, Value: modconfig.RedactedVariableValue, Redacted: true}
+	}
+	return RunMetadataVariable{Name: name, Value: value}
+}
@@ -0,0 +1,200 @@
+// Package controlpolicy evaluates the results of a check run against a policy file,
+// allowing compliance gates to be defined declaratively:
+//
+//	# the maximum number of alarms of each severity - 'all' limits the total number of alarms
+//	max_alarms = {
+//	  critical = 0
+//	  high     = 5
+//	}
+//	# the maximum number of controls in error
+//	max_errors = 0
+//	# the minimum percentage of results which are ok
+//	min_pass_rate = 95
+//	# benchmarks which must be run
+//	required_benchmarks = ["aws_compliance.benchmark.cis_v300"]
+package controlpolicy
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+)
+
+const (
+	// AllSeverities is the max_alarms key which limits the total number of alarms
+	AllSeverities = "all"
+	// NoSeverity is the severity used for the alarms of controls which do not have a severity
+	NoSeverity = "none"
+)
+
+// Policy is the set of thresholds the results of a check run must meet
+type Policy struct {
+	MaxAlarms          map[string]int `hcl:"max_alarms,optional"`
+	MaxErrors          *int           `hcl:"max_errors,optional"`
+	MinPassRate        *float64       `hcl:"min_pass_rate,optional"`
+	RequiredBenchmarks []string       `hcl:"required_benchmarks,optional"`
+}
+
+// LoadPolicy loads and validates the policy file at the given path
+func LoadPolicy(policyPath string) (*Policy, error) {
+	if _, err := os.Stat(policyPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigLoadFailed, sperr.New("policy file %s does not exist", policyPath))
+		}
+		return nil, err
+	}
+	file, diags := hclparse.NewParser().ParseHCLFile(policyPath)
+	if diags.HasErrors() {
+		return nil, error_helpers.HclDiagsToError("Failed to load policy", diags)
+	}
+	policy := &Policy{}
+	if diags := gohcl.DecodeBody(file.Body, nil, policy); diags.HasErrors() {
+		return nil, error_helpers.HclDiagsToError("Failed to load policy", diags)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigInvalid, sperr.WrapWithMessage(err, "invalid policy %s", policyPath))
+	}
+	return policy, nil
+}
+
+func (p *Policy) validate() error {
+	for severity, max := range p.MaxAlarms {
+		if max < 0 {
+			return fmt.Errorf("max_alarms for '%s' must not be negative", severity)
+		}
+	}
+	if p.MaxErrors != nil && *p.MaxErrors < 0 {
+		return fmt.Errorf("max_errors must not be negative")
+	}
+	if p.MinPassRate != nil && (*p.MinPassRate < 0 || *p.MinPassRate > 100) {
+		return fmt.Errorf("min_pass_rate must be between 0 and 100")
+	}
+	return nil
+}
+
+// Verdict is the result of evaluating a policy
+type Verdict struct {
+	Passed bool `json:"passed"`
+	// the number of alarms of each severity
+	Alarms map[string]int `json:"alarms"`
+	Errors int            `json:"errors"`
+	// the percentage of ok results, out of the ok, alarm and error results
+	PassRate float64 `json:"pass_rate"`
+	// the thresholds which were not met
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Evaluate evaluates the results of the given (executed) execution trees against the policy
+func (p *Policy) Evaluate(trees ...*controlexecute.ExecutionTree) *Verdict {
+	verdict := &Verdict{Alarms: make(map[string]int)}
+	ok, totalAlarms := 0, 0
+	benchmarks := make(map[string]bool)
+	for _, tree := range trees {
+		walkResultGroup(tree.Root, func(group *controlexecute.ResultGroup) {
+			benchmarks[group.GroupId] = true
+			if group.GroupItem != nil {
+				benchmarks[group.GroupItem.GetUnqualifiedName()] = true
+			}
+			for _, run := range group.ControlRuns {
+				if run.Summary == nil {
+					continue
+				}
+				severity := run.Severity
+				if severity == "" {
+					severity = NoSeverity
+				}
+				verdict.Alarms[severity] += run.Summary.Alarm
+				totalAlarms += run.Summary.Alarm
+				verdict.Errors += run.Summary.Error
+				ok += run.Summary.Ok
+			}
+		})
+	}
+	verdict.PassRate = passRate(ok, totalAlarms, verdict.Errors)
+
+	// sort the severities so the violations are reported in a stable order
+	severities := make([]string, 0, len(p.MaxAlarms))
+	for severity := range p.MaxAlarms {
+		severities = append(severities, severity)
+	}
+	sort.Strings(severities)
+	for _, severity := range severities {
+		max := p.MaxAlarms[severity]
+		count, description := verdict.Alarms[severity], severity+" alarms"
+		if severity == AllSeverities {
+			count, description = totalAlarms, "alarms"
+		}
+		if count > max {
+			verdict.Violations = append(verdict.Violations, fmt.Sprintf("%d %s exceed the maximum of %d", count, description, max))
+		}
+	}
+	if p.MaxErrors != nil && verdict.Errors > *p.MaxErrors {
+		verdict.Violations = append(verdict.Violations, fmt.Sprintf("%d errors exceed the maximum of %d", verdict.Errors, *p.MaxErrors))
+	}
+	if p.MinPassRate != nil && verdict.PassRate < *p.MinPassRate {
+		verdict.Violations = append(verdict.Violations, fmt.Sprintf("pass rate of %.1f%% is below the minimum of %.1f%%", verdict.PassRate, *p.MinPassRate))
+	}
+	for _, benchmark := range p.RequiredBenchmarks {
+		if !benchmarks[benchmark] {
+			verdict.Violations = append(verdict.Violations, fmt.Sprintf("required benchmark %s was not run", benchmark))
+		}
+	}
+	verdict.Passed = len(verdict.Violations) == 0
+	return verdict
+}
+
+// Fail fails the verdict for a reason other than the thresholds of the policy
+func (v *Verdict) Fail(reason string) {
+	v.Violations = append(v.Violations, reason)
+	v.Passed = false
+}
+
+// String returns the summary of the verdict shown at the end of the check run
+func (v *Verdict) String() string {
+	var b strings.Builder
+	if v.Passed {
+		fmt.Fprintf(&b, "Policy: %s\n", color.GreenString("PASSED"))
+	} else {
+		fmt.Fprintf(&b, "Policy: %s\n", color.RedString("FAILED"))
+	}
+	severities := make([]string, 0, len(v.Alarms))
+	for severity, count := range v.Alarms {
+		if count > 0 {
+			severities = append(severities, fmt.Sprintf("%s %d", severity, count))
+		}
+	}
+	sort.Strings(severities)
+	alarms := "none"
+	if len(severities) > 0 {
+		alarms = strings.Join(severities, ", ")
+	}
+	fmt.Fprintf(&b, "  Alarms: %s, Errors: %d, Pass rate: %.1f%%\n", alarms, v.Errors, v.PassRate)
+	for _, violation := range v.Violations {
+		fmt.Fprintf(&b, "  - %s\n", violation)
+	}
+	return b.String()
+}
+
+// passRate returns the percentage of ok results - if there are no results, the pass rate is 100
+func passRate(ok, alarms, errors int) float64 {
+	total := ok + alarms + errors
+	if total == 0 {
+		return 100
+	}
+	return float64(ok) * 100 / float64(total)
+}
+
+func walkResultGroup(group *controlexecute.ResultGroup, f func(*controlexecute.ResultGroup)) {
+	f(group)
+	for _, child := range group.Groups {
+		walkResultGroup(child, f)
+	}
+}
@@ -0,0 +1,103 @@
+package controlpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		content     string
+		expectedErr bool
+	}{
+		"valid": {
+			content: `
+max_alarms = {
+  critical = 0
+  high     = 5
+}
+max_errors          = 0
+min_pass_rate       = 95
+required_benchmarks = ["benchmark.cis"]
+`,
+		},
+		"negative max alarms": {
+			content:     `max_alarms = { high = -1 }`,
+			expectedErr: true,
+		},
+		"invalid pass rate": {
+			content:     `min_pass_rate = 101`,
+			expectedErr: true,
+		},
+		"unknown attribute": {
+			content:     `max_warnings = 1`,
+			expectedErr: true,
+		},
+	}
+	for name, test := range testCases {
+		policyPath := filepath.Join(t.TempDir(), "policy.spc")
+		if err := os.WriteFile(policyPath, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadPolicy(policyPath)
+		if test.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", name, test.expectedErr, err)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tree := &controlexecute.ExecutionTree{
+		Root: &controlexecute.ResultGroup{
+			GroupId: controlexecute.RootResultGroupName,
+			Groups: []*controlexecute.ResultGroup{
+				{
+					GroupId: "aws_compliance.benchmark.cis",
+					ControlRuns: []*controlexecute.ControlRun{
+						{Severity: "critical", Summary: &controlstatus.StatusSummary{Alarm: 1, Ok: 5}},
+						{Severity: "high", Summary: &controlstatus.StatusSummary{Alarm: 2, Ok: 1, Error: 1}},
+						{Summary: &controlstatus.StatusSummary{Ok: 10, Skip: 3}},
+					},
+				},
+			},
+		},
+	}
+	maxErrors := 1
+	minPassRate := 85.0
+
+	testCases := map[string]struct {
+		policy     *Policy
+		violations []string
+	}{
+		"pass": {
+			policy: &Policy{MaxAlarms: map[string]int{"critical": 1, AllSeverities: 3}, MaxErrors: &maxErrors, RequiredBenchmarks: []string{"aws_compliance.benchmark.cis"}},
+		},
+		"fail": {
+			policy: &Policy{
+				MaxAlarms:          map[string]int{"critical": 0, AllSeverities: 2},
+				MinPassRate:        &minPassRate,
+				RequiredBenchmarks: []string{"aws_compliance.benchmark.nist"},
+			},
+			violations: []string{
+				"3 alarms exceed the maximum of 2",
+				"1 critical alarms exceed the maximum of 0",
+				"pass rate of 80.0% is below the minimum of 85.0%",
+				"required benchmark aws_compliance.benchmark.nist was not run",
+			},
+		},
+	}
+	for name, test := range testCases {
+		verdict := test.policy.Evaluate(tree)
+		if verdict.Passed != (len(test.violations) == 0) {
+			t.Errorf("%s: expected passed to be %v", name, len(test.violations) == 0)
+		}
+		if !reflect.DeepEqual(verdict.Violations, test.violations) {
+			t.Errorf("%s: expected violations %v, got %v", name, test.violations, verdict.Violations)
+		}
+	}
+}
@@ -2,8 +2,6 @@ package controlstatus
 
 import (
 	"context"
-	"github.com/spf13/viper"
-	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/statushooks"
 )
 
@@ -14,7 +12,7 @@ type SnapshotControlHooks struct {
 
 func NewSnapshotControlHooks() *SnapshotControlHooks {
 	return &SnapshotControlHooks{
-		Enabled: viper.GetBool(constants.ArgProgress),
+		Enabled: statushooks.ProgressEnabled(),
 	}
 }
 
@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/spf13/viper"
-	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/utils"
 )
@@ -17,7 +15,7 @@ type StatusControlHooks struct {
 
 func NewStatusControlHooks() *StatusControlHooks {
 	return &StatusControlHooks{
-		Enabled: viper.GetBool(constants.ArgProgress),
+		Enabled: statushooks.ProgressEnabled(),
 	}
 }
 
@@ -73,5 +71,5 @@ func (c *StatusControlHooks) setStatusFromProgress(ctx context.Context, p *Contr
 		utils.Pluralize("error", p.Error),
 	)
 
-	statushooks.SetStatus(ctx, message)
+	statushooks.SetProgress(ctx, message, p.Complete+p.Error, p.Total)
 }
@@ -36,7 +36,7 @@ func NewInitData(ctx context.Context) *InitData {
 	w, errAndWarnings := workspace.LoadWorkspacePromptingForVariables(ctx)
 	if errAndWarnings.GetError() != nil {
 		return &InitData{
-			InitData: *initialisation.NewErrorInitData(fmt.Errorf("failed to load workspace: %s", error_helpers.HandleCancelError(errAndWarnings.GetError()).Error())),
+			InitData: *initialisation.NewErrorInitData(error_helpers.NewExitCodeError(constants.ExitCodeConfigLoadFailed, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigLoadFailed, fmt.Errorf("failed to load workspace: %s", error_helpers.HandleCancelError(errAndWarnings.GetError()).Error())))),
 		}
 	}
 
@@ -48,7 +48,7 @@ func NewInitData(ctx context.Context) *InitData {
 	i.Workspace = w
 	i.Result.AddWarnings(errAndWarnings.Warnings...)
 	if !w.ModfileExists() {
-		i.Result.Error = workspace.ErrorNoModDefinition
+		i.Result.Error = error_helpers.NewExitCodeError(constants.ExitCodeNoModFile, workspace.ErrorNoModDefinition)
 	}
 
 	if viper.GetString(constants.ArgOutput) == constants.OutputFormatNone {
@@ -0,0 +1,16 @@
+//go:build bundle_dashboard_assets
+
+package dashboardassets
+
+import _ "embed"
+
+// bundledAssetsArchive is a gzipped tar archive of the dashboard assets directory for this version
+// to build a binary which does not need to download the assets, run 'make steampipe_bundled' - this builds the
+// assets, packages them as pkg/dashboard/dashboardassets/dashboard_assets.tar.gz and builds with '-tags bundle_dashboard_assets'
+//
+//go:embed dashboard_assets.tar.gz
+var bundledAssetsArchive []byte
+
+func bundledAssets() []byte {
+	return bundledAssetsArchive
+}
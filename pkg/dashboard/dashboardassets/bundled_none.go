@@ -0,0 +1,9 @@
+//go:build !bundle_dashboard_assets
+
+package dashboardassets
+
+// bundledAssets returns nil as the dashboard assets are not bundled into this binary
+// (see bundled.go)
+func bundledAssets() []byte {
+	return nil
+}
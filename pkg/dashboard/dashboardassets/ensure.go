@@ -1,6 +1,7 @@
 package dashboardassets
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"log"
@@ -9,11 +10,12 @@ import (
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/steampipe-plugin-sdk/v5/logging"
 	"github.com/turbot/steampipe/pkg/filepaths"
-	"github.com/turbot/steampipe/pkg/ociinstaller"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/version"
 )
 
+// Ensure ensures the dashboard assets for this version are installed and have not been modified
+// if assets are bundled into the binary they are installed from the bundle, otherwise they are downloaded
 func Ensure(ctx context.Context) error {
 	logging.LogTime("dashboardassets.Ensure start")
 	defer logging.LogTime("dashboardassets.Ensure end")
@@ -24,20 +26,67 @@ func Ensure(ctx context.Context) error {
 		return err
 	}
 
-	if versionFile.Version == version.VersionString {
+	if versionFile.Version == version.VersionString && installedAssetsValid() {
 		return nil
 	}
 
 	statushooks.SetStatus(ctx, "Installing dashboard server…")
 
-	reportAssetsPath := filepaths.EnsureDashboardAssetsDir()
-
 	// remove the legacy report folder, if it exists
 	if _, err := os.Stat(filepaths.LegacyDashboardAssetsDir()); !os.IsNotExist(err) {
 		os.RemoveAll(filepaths.LegacyDashboardAssetsDir())
 	}
 
-	return ociinstaller.InstallAssets(ctx, reportAssetsPath)
+	if bundled := bundledAssets(); bundled != nil {
+		log.Printf("[INFO] installing the dashboard assets bundled into the binary")
+		return installFromArchiveReader(bytes.NewReader(bundled), &InstallRecord{Source: InstallSourceBundled})
+	}
+	return Install(ctx)
+}
+
+// installedAssetsValid returns whether the installed assets match the install record,
+// i.e. they were installed from the pinned image (if any) and the files have not been modified since
+func installedAssetsValid() bool {
+	assetsPath := filepaths.EnsureDashboardAssetsDir()
+	record, err := loadInstallRecord()
+	if err != nil {
+		log.Printf("[WARN] failed to load the dashboard assets install record: %s", err.Error())
+		return false
+	}
+	if record == nil {
+		// assets installed by a previous version have no install record - trust and record them
+		if err := recordInstall(assetsPath, &InstallRecord{Source: InstallSourceRegistry}); err != nil {
+			log.Printf("[WARN] failed to save the dashboard assets install record: %s", err.Error())
+		}
+		return true
+	}
+
+	if assetsDigest != "" && record.Source == InstallSourceRegistry && record.Digest != assetsDigest {
+		log.Printf("[INFO] installed dashboard assets digest %s does not match the pinned digest %s", record.Digest, assetsDigest)
+		return false
+	}
+
+	// if no file has been added, removed or changed since the install, there is no need to hash the file contents
+	statChecksum, err := dirStatChecksum(assetsPath)
+	if err != nil {
+		return false
+	}
+	if statChecksum == record.StatChecksum {
+		return true
+	}
+
+	// the files may have been modified - compare their contents
+	checksum, err := dirChecksum(assetsPath)
+	if err != nil || checksum != record.Checksum {
+		log.Printf("[WARN] the installed dashboard assets have been modified - they will be reinstalled")
+		return false
+	}
+	// the contents are unchanged (e.g. the files were touched or copied) - record the new stats
+	record.StatChecksum = statChecksum
+	if err := record.save(); err != nil {
+		log.Printf("[WARN] failed to save the dashboard assets install record: %s", err.Error())
+	}
+	return true
 }
 
 type ReportAssetsVersionFile struct {
@@ -45,7 +94,10 @@ type ReportAssetsVersionFile struct {
 }
 
 func loadReportAssetVersionFile() (*ReportAssetsVersionFile, error) {
-	versionFilePath := filepaths.ReportAssetsVersionFilePath()
+	return loadReportAssetVersionFileFromPath(filepaths.ReportAssetsVersionFilePath())
+}
+
+func loadReportAssetVersionFileFromPath(versionFilePath string) (*ReportAssetsVersionFile, error) {
 	if !filehelpers.FileExists(versionFilePath) {
 		return &ReportAssetsVersionFile{}, nil
 	}
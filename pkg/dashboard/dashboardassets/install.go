@@ -0,0 +1,270 @@
+package dashboardassets
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+	"github.com/turbot/steampipe/pkg/version"
+)
+
+// assetsDigest is the manifest digest of the dashboard assets image for this version, e.g. sha256:1234...
+// this is set using ldflags during the release build (see .goreleaser.yml and the DASHBOARD_ASSETS_DIGEST make variable)
+// if set, the image is pulled by digest, so the downloaded assets are verified against it,
+// otherwise the image is pulled by the version tag
+var assetsDigest = ""
+
+const (
+	InstallSourceRegistry = "registry"
+	InstallSourceArchive  = "archive"
+	InstallSourceBundled  = "bundled"
+)
+
+// InstallRecord records how the dashboard assets were installed, and the checksum of the installed files
+type InstallRecord struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	// the manifest digest of the image (for assets installed from the registry)
+	Digest string `json:"digest,omitempty"`
+	// the checksum of the installed assets directory
+	Checksum string `json:"checksum"`
+	// the checksum of the paths, sizes and modification times of the installed files
+	// this is compared on startup, so the file contents are only hashed if the files may have changed
+	StatChecksum string    `json:"stat_checksum,omitempty"`
+	InstalledAt  time.Time `json:"installed_at"`
+}
+
+func loadInstallRecord() (*InstallRecord, error) {
+	path := filepaths.DashboardAssetsInstallFilePath()
+	if !filehelpers.FileExists(path) {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var record InstallRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *InstallRecord) save() error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepaths.DashboardAssetsInstallFilePath(), content, 0644)
+}
+
+// Install downloads and installs the dashboard assets image for this version, replacing any installed assets
+func Install(ctx context.Context) error {
+	assetsPath := filepaths.EnsureDashboardAssetsDir()
+	digest, err := ociinstaller.InstallAssets(ctx, assetsPath, assetsDigest)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to install the dashboard assets")
+	}
+	return recordInstall(assetsPath, &InstallRecord{Source: InstallSourceRegistry, Digest: digest})
+}
+
+// InstallFromArchive installs the dashboard assets from a local gzipped tar archive of the assets directory,
+// replacing any installed assets - this allows the assets to be installed without network access
+// if a checksum is given, the sha256 checksum of the archive must match it
+// the archive must contain the assets for this version
+func InstallFromArchive(archivePath, checksum string) error {
+	content, err := os.ReadFile(archivePath)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to read dashboard assets archive")
+	}
+	if checksum != "" {
+		expected := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+		actual := sha256.Sum256(content)
+		if hex.EncodeToString(actual[:]) != expected {
+			return fmt.Errorf("the checksum of dashboard assets archive %s does not match - expected sha256:%s, got sha256:%s", archivePath, expected, hex.EncodeToString(actual[:]))
+		}
+	}
+	return installFromArchiveReader(bytes.NewReader(content), &InstallRecord{Source: InstallSourceArchive})
+}
+
+func installFromArchiveReader(r io.Reader, record *InstallRecord) error {
+	assetsPath := filepaths.EnsureDashboardAssetsDir()
+	// extract to a sibling of the assets directory, so it may be renamed into place
+	stagingPath := assetsPath + ".staging"
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingPath)
+
+	if err := extractTarGz(r, stagingPath); err != nil {
+		return sperr.WrapWithMessage(err, "failed to extract dashboard assets archive")
+	}
+
+	// verify the archive contains the assets for this version
+	versionFile, err := loadReportAssetVersionFileFromPath(filepath.Join(stagingPath, filepath.Base(filepaths.ReportAssetsVersionFilePath())))
+	if err != nil {
+		return err
+	}
+	if versionFile.Version != version.VersionString {
+		return fmt.Errorf("the dashboard assets archive contains assets for version '%s' - assets for version '%s' are required", versionFile.Version, version.VersionString)
+	}
+
+	if err := os.RemoveAll(assetsPath); err != nil {
+		return err
+	}
+	if err := os.Rename(stagingPath, assetsPath); err != nil {
+		return err
+	}
+	return recordInstall(assetsPath, record)
+}
+
+// recordInstall computes the checksums of the installed assets and saves the install record
+func recordInstall(assetsPath string, record *InstallRecord) error {
+	checksum, err := dirChecksum(assetsPath)
+	if err != nil {
+		return err
+	}
+	statChecksum, err := dirStatChecksum(assetsPath)
+	if err != nil {
+		return err
+	}
+	record.Version = version.VersionString
+	record.Checksum = checksum
+	record.StatChecksum = statChecksum
+	record.InstalledAt = time.Now()
+	return record.save()
+}
+
+// dirChecksum returns a sha256 checksum of the relative paths and contents of all files in the directory
+func dirChecksum(dir string) (string, error) {
+	h := sha256.New()
+	// WalkDir walks in lexical order, so the checksum is deterministic
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fileHash := sha256.New()
+		if _, err := io.Copy(fileHash, f); err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%x\n", filepath.ToSlash(relPath), fileHash.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// dirStatChecksum returns a sha256 checksum of the relative paths, sizes and modification times
+// of all files in the directory - unlike dirChecksum this does not read the file contents
+func dirStatChecksum(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", filepath.ToSlash(relPath), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractTarGz extracts the directories and regular files in the gzipped tar stream into dest
+func extractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// do not allow entries to be written outside the destination
+		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid archive entry '%s'", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported archive entry '%s' - only directories and regular files are supported", header.Name)
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,84 @@
+package dashboardassets
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractTarGzAndChecksum(t *testing.T) {
+	files := map[string]string{
+		"./versions.json":     `{"version":"0.23.2"}`,
+		"static/js/main.js":   "console.log('dashboard')",
+		"static/css/main.css": "body {}",
+	}
+	dest := filepath.Join(t.TempDir(), "assets")
+	if err := extractTarGz(bytes.NewReader(buildTarGz(t, files)), dest); err != nil {
+		t.Fatalf("extractTarGz failed: %s", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dest, "static", "js", "main.js"))
+	if err != nil || string(content) != files["static/js/main.js"] {
+		t.Fatalf("extracted file has unexpected content %q (%v)", content, err)
+	}
+
+	checksum, err := dirChecksum(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statChecksum, err := dirStatChecksum(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the checksums are deterministic
+	if again, _ := dirChecksum(dest); again != checksum {
+		t.Errorf("dirChecksum is not deterministic: %s != %s", checksum, again)
+	}
+	if again, _ := dirStatChecksum(dest); again != statChecksum {
+		t.Errorf("dirStatChecksum is not deterministic: %s != %s", statChecksum, again)
+	}
+	// and change when a file is modified
+	if err := os.WriteFile(filepath.Join(dest, "static", "css", "main.css"), []byte("body { color: red }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if modified, _ := dirChecksum(dest); modified == checksum {
+		t.Errorf("dirChecksum did not change when a file was modified")
+	}
+	if modified, _ := dirStatChecksum(dest); modified == statChecksum {
+		t.Errorf("dirStatChecksum did not change when a file was modified")
+	}
+}
+
+func TestExtractTarGzRejectsPathTraversal(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "assets")
+	archive := buildTarGz(t, map[string]string{"../outside.txt": "bad"})
+	if err := extractTarGz(bytes.NewReader(archive), dest); err == nil {
+		t.Errorf("expected an error extracting an entry outside the destination")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "outside.txt")); !os.IsNotExist(err) {
+		t.Errorf("entry was written outside the destination")
+	}
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
 	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/tracing"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/turbot/steampipe/pkg/workspace"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
 )
 
@@ -123,6 +125,10 @@ func (e *DashboardExecutionTree) createRootItem(rootName string) (dashboardtypes
 func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	startTime := time.Now()
 
+	// trace the execution - the spans of the runs and queries of the dashboard are children of this span
+	ctx, span := tracing.StartSpan(ctx, "DashboardExecutionTree.Execute", attribute.String("steampipe.dashboard", e.dashboardName))
+	defer func() { tracing.EndSpan(span, e.Root.GetError()) }()
+
 	searchPath := e.client.GetRequiredSessionSearchPath()
 
 	// store context
@@ -9,6 +9,8 @@ import (
 	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
 )
 
@@ -179,7 +181,9 @@ func (*LeafRun) IsSnapshotPanel() {}
 func (r *LeafRun) executeQuery(ctx context.Context) error {
 	log.Printf("[TRACE] LeafRun '%s' SQL resolved, executing", r.resource.Name())
 
+	ctx, span := tracing.StartSpan(ctx, "LeafRun.executeQuery", attribute.String("steampipe.resource", r.resource.Name()))
 	queryResult, err := r.executionTree.client.ExecuteSync(ctx, r.executeSQL, r.Args...)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		log.Printf("[TRACE] LeafRun '%s' query failed: %s", r.resource.Name(), err.Error())
 		return err
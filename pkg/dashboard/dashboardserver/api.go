@@ -1,11 +1,16 @@
 package dashboardserver
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
+	"os"
 	"path"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/static"
@@ -26,20 +31,45 @@ func startAPIAsync(ctx context.Context, webSocket *melody.Melody) chan struct{}
 		// only add the Recovery middleware
 		router.Use(gin.Recovery())
 
+		// authenticate all requests (if auth is enabled)
+		// NOTE: the auth args have already been validated
+		auth, err := newAuthConfig()
+		if err != nil {
+			error_helpers.ShowErrorWithMessage(ctx, err, "Invalid dashboard server auth config")
+			doneChan <- struct{}{}
+			return
+		}
+		router.Use(auth.middleware())
+
 		assetsDirectory := filepaths.EnsureDashboardAssetsDir()
+		basePath := NormaliseBasePath(viper.GetString(constants.ArgDashboardBasePath))
 
-		router.Use(static.Serve("/", static.LocalFile(assetsDirectory, true)))
+		router.Use(static.Serve(basePath+"/", &assetFileSystem{static.LocalFile(assetsDirectory, true)}))
 
-		router.GET("/ws", func(c *gin.Context) {
-			webSocket.HandleRequest(c.Writer, c.Request)
+		router.GET(basePath+"/ws", func(c *gin.Context) {
+			// pass the authenticated user (if any) to the websocket session
+			keys := map[string]interface{}{}
+			if user, ok := c.Get(userKey); ok {
+				keys[userKey] = user
+			}
+			webSocket.HandleRequestWithKeys(c.Writer, c.Request, keys)
 		})
 
 		router.NoRoute(func(c *gin.Context) {
+			if !strings.HasPrefix(c.Request.URL.Path+"/", basePath+"/") {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+			indexHtml, err := buildIndexHtml(assetsDirectory, basePath)
+			if err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
 			// https://stackoverflow.com/questions/49547/how-do-we-control-web-page-caching-across-all-browsers
 			c.Header("Cache-Control", "no-cache, no-store, must-revalidate") // HTTP 1.1.
 			c.Header("Pragma", "no-cache")                                   // HTTP 1.0.
 			c.Header("Expires", "0")                                         // Proxies.
-			c.File(path.Join(assetsDirectory, "index.html"))
+			c.Data(http.StatusOK, "text/html; charset=utf-8", indexHtml)
 		})
 
 		dashboardServerPort := viper.GetInt(constants.ArgDashboardPort)
@@ -61,7 +91,7 @@ func startAPIAsync(ctx context.Context, webSocket *melody.Melody) chan struct{}
 		}()
 
 		outputReady(ctx, fmt.Sprintf("Dashboard server started on %d and listening on %s", dashboardServerPort, viper.GetString(constants.ArgDashboardListen)))
-		OutputMessage(ctx, fmt.Sprintf("Visit http://localhost:%d", dashboardServerPort))
+		OutputMessage(ctx, fmt.Sprintf("Visit http://localhost:%d%s", dashboardServerPort, basePath))
 		OutputMessage(ctx, "Press Ctrl+C to exit")
 		<-ctx.Done()
 		log.Println("Shutdown Server…")
@@ -80,3 +110,44 @@ func startAPIAsync(ctx context.Context, webSocket *melody.Melody) chan struct{}
 
 	return doneChan
 }
+
+// assetFileSystem serves the dashboard assets, except for index.html
+// - this is served by the NoRoute handler, so the base path can be injected
+type assetFileSystem struct {
+	static.ServeFileSystem
+}
+
+func (a *assetFileSystem) Exists(prefix string, filepath string) bool {
+	p := strings.TrimPrefix(filepath, prefix)
+	if p == "" || strings.HasSuffix(p, "/") || path.Base(p) == "index.html" {
+		return false
+	}
+	return a.ServeFileSystem.Exists(prefix, filepath)
+}
+
+// matches root relative asset urls (but not protocol relative urls, i.e. //host/path)
+var rootRelativeUrlRegex = regexp.MustCompile(`(href|src)="/([^/])`)
+
+// buildIndexHtml returns the dashboard index.html, with asset urls prefixed with the base path
+// and the base path set in a meta tag, so the dashboard UI can use it for routing and the websocket url
+func buildIndexHtml(assetsDirectory, basePath string) ([]byte, error) {
+	indexHtml, err := os.ReadFile(path.Join(assetsDirectory, "index.html"))
+	if err != nil || basePath == "" {
+		return indexHtml, err
+	}
+	escapedBasePath := html.EscapeString(basePath)
+	// escape any '$' so it is not expanded by ReplaceAll
+	indexHtml = rootRelativeUrlRegex.ReplaceAll(indexHtml, []byte(fmt.Sprintf(`$1="%s/$2`, strings.ReplaceAll(escapedBasePath, "$", "$$"))))
+	meta := fmt.Sprintf(`<head><meta name="steampipe-base-path" content="%s" />`, escapedBasePath)
+	return bytes.Replace(indexHtml, []byte("<head>"), []byte(meta), 1), nil
+}
+
+// NormaliseBasePath returns the base path with a leading slash and no trailing slash
+// (the root path is returned as an empty string)
+func NormaliseBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
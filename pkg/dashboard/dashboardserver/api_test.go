@@ -0,0 +1,72 @@
+package dashboardserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormaliseBasePath(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"/":                 "",
+		"  ":                "",
+		"dashboards":        "/dashboards",
+		"/dashboards":       "/dashboards",
+		"/dashboards/":      "/dashboards",
+		" /a/b/ ":           "/a/b",
+		"//dashboards//":    "/dashboards",
+		"/steampipe/ui/v1/": "/steampipe/ui/v1",
+	}
+	for basePath, expected := range tests {
+		if got := NormaliseBasePath(basePath); got != expected {
+			t.Errorf("NormaliseBasePath(%q) = %q, expected %q", basePath, got, expected)
+		}
+	}
+}
+
+func TestBuildIndexHtml(t *testing.T) {
+	const indexHtml = `<html><head><link href="/static/app.css"><script src="/static/app.js"></script>` +
+		`<script src="//cdn.example.com/lib.js"></script><a href="https://example.com/">x</a></head></html>`
+	assetsDirectory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(assetsDirectory, "index.html"), []byte(indexHtml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		basePath string
+		expected string
+	}{
+		"no base path": {
+			basePath: "",
+			expected: indexHtml,
+		},
+		"base path": {
+			basePath: "/dashboards",
+			expected: `<html><head><meta name="steampipe-base-path" content="/dashboards" /><link href="/dashboards/static/app.css">` +
+				`<script src="/dashboards/static/app.js"></script><script src="//cdn.example.com/lib.js"></script>` +
+				`<a href="https://example.com/">x</a></head></html>`,
+		},
+		"base path is escaped": {
+			basePath: `/a"$1b`,
+			expected: `<html><head><meta name="steampipe-base-path" content="/a&#34;$1b" /><link href="/a&#34;$1b/static/app.css">` +
+				`<script src="/a&#34;$1b/static/app.js"></script><script src="//cdn.example.com/lib.js"></script>` +
+				`<a href="https://example.com/">x</a></head></html>`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := buildIndexHtml(assetsDirectory, test.basePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, got)
+			}
+		})
+	}
+
+	if _, err := buildIndexHtml(t.TempDir(), "/dashboards"); err == nil {
+		t.Errorf("expected an error if index.html does not exist")
+	}
+}
@@ -0,0 +1,140 @@
+package dashboardserver
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+type AuthMode string
+
+const (
+	// AuthModeNone - the dashboard server does not authenticate requests
+	AuthModeNone AuthMode = "none"
+	// AuthModeTrustedHeader - requests must come from a trusted reverse proxy, which sets the authenticated user in a header
+	AuthModeTrustedHeader AuthMode = "trusted-header"
+
+	// the session key used to store the authenticated user
+	userKey = "user"
+)
+
+// IsValid is a validator for AuthMode known values
+func (mode AuthMode) IsValid() error {
+	if helpers.StringSliceContains([]string{string(AuthModeNone), string(AuthModeTrustedHeader)}, string(mode)) {
+		return nil
+	}
+	return fmt.Errorf("invalid value for %s: %s - must be one of %s, %s", constants.ArgDashboardAuth, mode, AuthModeNone, AuthModeTrustedHeader)
+}
+
+type authConfig struct {
+	mode           AuthMode
+	header         string
+	trustedProxies []*net.IPNet
+}
+
+// ValidateServerArgs validates the dashboard server auth args
+func ValidateServerArgs() error {
+	_, err := newAuthConfig()
+	return err
+}
+
+func newAuthConfig() (*authConfig, error) {
+	mode := AuthMode(viper.GetString(constants.ArgDashboardAuth))
+	if mode == "" {
+		mode = AuthModeNone
+	}
+	if err := mode.IsValid(); err != nil {
+		return nil, err
+	}
+	c := &authConfig{
+		mode:   mode,
+		header: viper.GetString(constants.ArgDashboardAuthHeader),
+	}
+	if c.header == "" {
+		c.header = constants.DashboardDefaultAuthHeader
+	}
+
+	trustedProxies := viper.GetString(constants.ArgDashboardTrustedProxies)
+	if trustedProxies == "" {
+		trustedProxies = constants.DashboardDefaultTrustedProxies
+	}
+	for _, p := range strings.Split(trustedProxies, ",") {
+		ipNet, err := parseTrustedProxy(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		c.trustedProxies = append(c.trustedProxies, ipNet)
+	}
+	return c, nil
+}
+
+// parseTrustedProxy parses a CIDR or IP address - an IP address is converted to a single address CIDR
+func parseTrustedProxy(p string) (*net.IPNet, error) {
+	if !strings.Contains(p, "/") {
+		ip := net.ParseIP(p)
+		if ip == nil {
+			return nil, sperr.New("invalid trusted proxy '%s' - must be an IP address or CIDR", p)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(p)
+	if err != nil {
+		return nil, sperr.New("invalid trusted proxy '%s' - must be an IP address or CIDR", p)
+	}
+	return ipNet, nil
+}
+
+func (c *authConfig) isTrustedProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range c.trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// middleware returns a gin handler which authenticates requests
+// for trusted-header auth, the request must come from a trusted proxy and have the auth header set
+// - the authenticated user is stored in the gin context
+func (c *authConfig) middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if c.mode != AuthModeTrustedHeader {
+			ctx.Next()
+			return
+		}
+		if !c.isTrustedProxy(ctx.Request.RemoteAddr) {
+			log.Printf("[WARN] dashboard server rejected request from untrusted address %s", ctx.Request.RemoteAddr)
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		user := ctx.GetHeader(c.header)
+		if user == "" {
+			log.Printf("[WARN] dashboard server rejected request with no %s header", c.header)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.Set(userKey, user)
+		ctx.Next()
+	}
+}
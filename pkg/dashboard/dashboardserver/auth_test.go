@@ -0,0 +1,155 @@
+package dashboardserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestParseTrustedProxy(t *testing.T) {
+	tests := map[string]struct {
+		proxy    string
+		expected string
+		err      bool
+	}{
+		"ipv4 address": {proxy: "10.0.0.1", expected: "10.0.0.1/32"},
+		"ipv6 address": {proxy: "::1", expected: "::1/128"},
+		"ipv4 cidr":    {proxy: "10.0.0.0/8", expected: "10.0.0.0/8"},
+		"ipv6 cidr":    {proxy: "fd00::/8", expected: "fd00::/8"},
+		"host bits":    {proxy: "10.1.2.3/16", expected: "10.1.0.0/16"},
+		"hostname":     {proxy: "proxy.local", err: true},
+		"invalid cidr": {proxy: "10.0.0.0/33", err: true},
+		"empty":        {proxy: "", err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ipNet, err := parseTrustedProxy(test.proxy)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error parsing '%s'", test.proxy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if ipNet.String() != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, ipNet.String())
+			}
+		})
+	}
+}
+
+func TestIsTrustedProxy(t *testing.T) {
+	c := newTestAuthConfig(t, "10.0.0.0/8,192.168.1.1,::1")
+	tests := map[string]struct {
+		remoteAddr string
+		expected   bool
+	}{
+		"address in cidr":      {remoteAddr: "10.1.2.3:5000", expected: true},
+		"exact address":        {remoteAddr: "192.168.1.1:5000", expected: true},
+		"ipv6 loopback":        {remoteAddr: "[::1]:5000", expected: true},
+		"address with no port": {remoteAddr: "10.1.2.3", expected: true},
+		"untrusted address":    {remoteAddr: "192.168.1.2:5000", expected: false},
+		"ipv4 loopback":        {remoteAddr: "127.0.0.1:5000", expected: false},
+		"invalid address":      {remoteAddr: "not-an-address", expected: false},
+		"empty":                {remoteAddr: "", expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := c.isTrustedProxy(test.remoteAddr); got != test.expected {
+				t.Errorf("isTrustedProxy(%s) = %v, expected %v", test.remoteAddr, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewAuthConfig(t *testing.T) {
+	defer resetAuthArgs()
+	tests := map[string]struct {
+		mode           string
+		trustedProxies string
+		err            bool
+	}{
+		"default":               {},
+		"none":                  {mode: "none"},
+		"trusted header":        {mode: "trusted-header", trustedProxies: "10.0.0.0/8, 10.1.1.1"},
+		"invalid mode":          {mode: "basic", err: true},
+		"invalid trusted proxy": {mode: "trusted-header", trustedProxies: "10.0.0.0/8,proxy.local", err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			viper.Set(constants.ArgDashboardAuth, test.mode)
+			viper.Set(constants.ArgDashboardTrustedProxies, test.trustedProxies)
+			_, err := newAuthConfig()
+			if test.err != (err != nil) {
+				t.Errorf("expected error: %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestTrustedHeaderAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := map[string]struct {
+		mode           AuthMode
+		remoteAddr     string
+		user           string
+		expectedStatus int
+		expectedUser   string
+	}{
+		"no auth":                 {mode: AuthModeNone, remoteAddr: "192.168.1.2:5000", expectedStatus: http.StatusOK},
+		"trusted proxy with user": {mode: AuthModeTrustedHeader, remoteAddr: "127.0.0.1:5000", user: "alice", expectedStatus: http.StatusOK, expectedUser: "alice"},
+		"trusted proxy no user":   {mode: AuthModeTrustedHeader, remoteAddr: "127.0.0.1:5000", expectedStatus: http.StatusUnauthorized},
+		"untrusted address":       {mode: AuthModeTrustedHeader, remoteAddr: "192.168.1.2:5000", user: "alice", expectedStatus: http.StatusForbidden},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestAuthConfig(t, constants.DashboardDefaultTrustedProxies)
+			c.mode = test.mode
+
+			var user string
+			router := gin.New()
+			router.Use(c.middleware())
+			router.GET("/", func(ctx *gin.Context) {
+				user = ctx.GetString(userKey)
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.user != "" {
+				req.Header.Set(constants.DashboardDefaultAuthHeader, test.user)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, w.Code)
+			}
+			if user != test.expectedUser {
+				t.Errorf("expected user '%s', got '%s'", test.expectedUser, user)
+			}
+		})
+	}
+}
+
+func newTestAuthConfig(t *testing.T, trustedProxies string) *authConfig {
+	defer resetAuthArgs()
+	viper.Set(constants.ArgDashboardAuth, string(AuthModeTrustedHeader))
+	viper.Set(constants.ArgDashboardTrustedProxies, trustedProxies)
+	c, err := newAuthConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func resetAuthArgs() {
+	viper.Set(constants.ArgDashboardAuth, nil)
+	viper.Set(constants.ArgDashboardTrustedProxies, nil)
+}
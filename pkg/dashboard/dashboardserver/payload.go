@@ -8,6 +8,7 @@ import (
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardexecute"
+	"github.com/turbot/steampipe/pkg/network"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/version"
@@ -27,6 +28,12 @@ func buildDashboardMetadataPayload(workspaceResources *modconfig.ResourceMaps, c
 		}
 	}
 
+	telemetry := viper.GetString(constants.ArgTelemetry)
+	// the dashboard UI must not send telemetry in offline mode
+	if network.IsOffline() {
+		telemetry = constants.TelemetryNone
+	}
+
 	payload := DashboardMetadataPayload{
 		Action: "dashboard_metadata",
 		Metadata: DashboardMetadata{
@@ -34,7 +41,7 @@ func buildDashboardMetadataPayload(workspaceResources *modconfig.ResourceMaps, c
 				Version: version.VersionString,
 			},
 			InstalledMods: installedMods,
-			Telemetry:     viper.GetString(constants.ArgTelemetry),
+			Telemetry:     telemetry,
 		},
 	}
 
@@ -309,7 +309,11 @@ func (s *Server) initAsync(ctx context.Context) {
 	go func() {
 		// Return list of dashboards on connect
 		s.webSocket.HandleConnect(func(session *melody.Session) {
-			log.Println("[TRACE] client connected")
+			if user, ok := session.Get(userKey); ok {
+				log.Printf("[TRACE] client connected, user %s", user)
+			} else {
+				log.Println("[TRACE] client connected")
+			}
 			s.addSession(session)
 		})
 
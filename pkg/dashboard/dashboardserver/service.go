@@ -36,6 +36,28 @@ type DashboardServiceState struct {
 	ListenType    string       `json:"listen_type"`
 	Listen        []string     `json:"listen"`
 	StructVersion int64        `json:"struct_version"`
+	// the base path and auth args the server was started with - these are reused if the service is restarted
+	BasePath       string `json:"base_path,omitempty"`
+	Auth           string `json:"auth,omitempty"`
+	AuthHeader     string `json:"auth_header,omitempty"`
+	TrustedProxies string `json:"trusted_proxies,omitempty"`
+}
+
+// SetServerArgs sets the base path and auth args in the state from viper
+func (s *DashboardServiceState) SetServerArgs() {
+	s.BasePath = viper.GetString(constants.ArgDashboardBasePath)
+	s.Auth = viper.GetString(constants.ArgDashboardAuth)
+	s.AuthHeader = viper.GetString(constants.ArgDashboardAuthHeader)
+	s.TrustedProxies = viper.GetString(constants.ArgDashboardTrustedProxies)
+}
+
+// RestoreServerArgs sets the base path and auth args in viper from the state
+// this is used when restarting the service, so the restarted server has the same config
+func (s *DashboardServiceState) RestoreServerArgs() {
+	viper.Set(constants.ArgDashboardBasePath, s.BasePath)
+	viper.Set(constants.ArgDashboardAuth, s.Auth)
+	viper.Set(constants.ArgDashboardAuthHeader, s.AuthHeader)
+	viper.Set(constants.ArgDashboardTrustedProxies, s.TrustedProxies)
 }
 
 func loadServiceStateFile() (*DashboardServiceState, error) {
@@ -142,6 +164,13 @@ func RunForService(ctx context.Context, serverListen ListenType, serverPort List
 		fmt.Sprintf("--%s=false", constants.ArgInput),
 	}
 
+	// pass through the base path and auth args (if set)
+	for _, arg := range []string{constants.ArgDashboardBasePath, constants.ArgDashboardAuth, constants.ArgDashboardAuthHeader, constants.ArgDashboardTrustedProxies} {
+		if value := viper.GetString(arg); value != "" {
+			args = append(args, fmt.Sprintf("--%s=%s", arg, value))
+		}
+	}
+
 	for _, variableArg := range viper.GetStringSlice(constants.ArgVariable) {
 		args = append(args, fmt.Sprintf("--%s=%s", constants.ArgVariable, variableArg))
 	}
@@ -1,6 +1,8 @@
 package dashboardtypes
 
 import (
+	"sort"
+
 	"github.com/turbot/steampipe/pkg/query/queryresult"
 )
 
@@ -26,3 +28,19 @@ func NewLeafData(result *queryresult.SyncQueryResult) *LeafData {
 	}
 	return leafData
 }
+
+// SortRows sorts the rows into a stable order
+// (ordered by the primary key columns where known, then by all other columns, in column order)
+func (d *LeafData) SortRows() {
+	columnOrder := queryresult.SortColumnOrder(d.Columns)
+	rowValues := func(row map[string]interface{}) []interface{} {
+		res := make([]interface{}, len(d.Columns))
+		for i, c := range d.Columns {
+			res[i] = row[c.Name]
+		}
+		return res
+	}
+	sort.SliceStable(d.Rows, func(i, j int) bool {
+		return queryresult.CompareRows(rowValues(d.Rows[i]), rowValues(d.Rows[j]), columnOrder) < 0
+	})
+}
@@ -36,6 +36,9 @@ type DbClient struct {
 	// this flag is set if the service that this client
 	// is connected to is running in the same physical system
 	isLocalService bool
+	// this flag is set if the local service has plugins compiled with SDK pre-v5 installed
+	// (these plugins cannot have caching turned off by the service)
+	localServiceHasPreV5Plugins bool
 
 	// concurrency management for db session access
 	parallelSessionInitLock *semaphore.Weighted
@@ -54,37 +57,41 @@ type DbClient struct {
 	searchPathPrefix []string
 	// the default user search path
 	userSearchPath []string
+	// if set, the sessions default to read only transactions and write statements are rejected
+	readOnly bool
+	// the schema metadata loaded by GetSchemaFromDB
+	schemaCache *schemaMetadataCache
 	// disable timing - set whilst in process of querying the timing
 	disableTiming        bool
 	onConnectionCallback DbConnectionCallback
+	// if failover endpoints are configured, this selects the endpoint new connections are made to
+	endpoints *failoverEndpoints
 }
 
 func NewDbClient(ctx context.Context, connectionString string, onConnectionCallback DbConnectionCallback, opts ...ClientOption) (_ *DbClient, err error) {
 	utils.LogTime("db_client.NewDbClient start")
 	defer utils.LogTime("db_client.NewDbClient end")
 
+	client := &DbClient{
+		// a weighted semaphore to control the maximum number parallel
+		// initializations under way
+		parallelSessionInitLock: semaphore.NewWeighted(constants.MaxParallelClientInits),
+		sessions:                make(map[uint32]*db_common.DatabaseSession),
+		sessionsMutex:           &sync.Mutex{},
+		connectionString:        connectionString,
+		schemaCache:             newSchemaMetadataCache(DefaultSchemaCacheTtl),
+	}
+
 	wg := &sync.WaitGroup{}
 	// wrap onConnectionCallback to use wait group
-	var wrappedOnConnectionCallback DbConnectionCallback
 	if onConnectionCallback != nil {
-		wrappedOnConnectionCallback = func(ctx context.Context, conn *pgx.Conn) error {
+		client.onConnectionCallback = func(ctx context.Context, conn *pgx.Conn) error {
 			wg.Add(1)
 			defer wg.Done()
 			return onConnectionCallback(ctx, conn)
 		}
 	}
 
-	client := &DbClient{
-		// a weighted semaphore to control the maximum number parallel
-		// initializations under way
-		parallelSessionInitLock: semaphore.NewWeighted(constants.MaxParallelClientInits),
-		sessions:                make(map[uint32]*db_common.DatabaseSession),
-		sessionsMutex:           &sync.Mutex{},
-		// store the callback
-		onConnectionCallback: wrappedOnConnectionCallback,
-		connectionString:     connectionString,
-	}
-
 	defer func() {
 		if err != nil {
 			// try closing the client
@@ -96,6 +103,7 @@ func NewDbClient(ctx context.Context, connectionString string, onConnectionCallb
 	for _, o := range opts {
 		o(&config)
 	}
+	client.readOnly = config.readOnly
 
 	if err := client.establishConnectionPool(ctx, config); err != nil {
 		return nil, err
@@ -150,14 +158,18 @@ func (c *DbClient) shouldFetchTiming() bool {
 	if c.disableTiming {
 		return false
 	}
-	// only fetch timing if timing flag is set, or output is JSON
+	// only fetch timing if timing flag is set, output is JSON or usage is being tracked
 	return (viper.GetString(constants.ArgTiming) != constants.ArgOff) ||
-		(viper.GetString(constants.ArgOutput) == constants.OutputFormatJSON)
+		(viper.GetString(constants.ArgOutput) == constants.OutputFormatJSON) ||
+		viper.GetBool(constants.ArgTrackUsage) ||
+		viper.GetBool(constants.ArgResourceUsage)
 
 }
 func (c *DbClient) shouldFetchVerboseTiming() bool {
 	return (viper.GetString(constants.ArgTiming) == constants.ArgVerbose) ||
-		(viper.GetString(constants.ArgOutput) == constants.OutputFormatJSON)
+		(viper.GetString(constants.ArgOutput) == constants.OutputFormatJSON) ||
+		viper.GetBool(constants.ArgTrackUsage) ||
+		viper.GetBool(constants.ArgResourceUsage)
 }
 
 // ServerSettings returns the settings of the steampipe service that this DbClient is connected to
@@ -177,6 +189,9 @@ func (c *DbClient) RegisterNotificationListener(func(notification *pgconn.Notifi
 // closes the connection to the database and shuts down the backend
 func (c *DbClient) Close(context.Context) error {
 	log.Printf("[TRACE] DbClient.Close %v", c.userPool)
+	if c.endpoints != nil {
+		c.endpoints.stop()
+	}
 	c.closePools()
 	// nullify active sessions, since with the closing of the pools
 	// none of the sessions will be valid anymore
@@ -186,10 +201,29 @@ func (c *DbClient) Close(context.Context) error {
 }
 
 // GetSchemaFromDB  retrieves schemas for all steampipe connections (EXCEPT DISABLED CONNECTIONS)
+// the metadata is cached until the schema cache is invalidated (see InvalidateSchemaCache) or the cache ttl expires
+// NOTE: the returned metadata may be shared with other callers, so must not be modified
+func (c *DbClient) GetSchemaFromDB(ctx context.Context) (*db_common.SchemaMetadata, error) {
+	searchPath := c.GetRequiredSessionSearchPath()
+	metadata, generation := c.schemaCache.get(searchPath)
+	if metadata != nil {
+		log.Printf("[TRACE] DbClient GetSchemaFromDB - using cached schema metadata")
+		return metadata, nil
+	}
+
+	metadata, err := c.loadSchemaFromDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.schemaCache.set(searchPath, metadata, generation)
+	return metadata, nil
+}
+
+// loadSchemaFromDB loads the schemas for all steampipe connections from the database
 // NOTE: it optimises the schema extraction by extracting schema information for
 // connections backed by distinct plugins and then fanning back out.
-func (c *DbClient) GetSchemaFromDB(ctx context.Context) (*db_common.SchemaMetadata, error) {
-	log.Printf("[INFO] DbClient GetSchemaFromDB")
+func (c *DbClient) loadSchemaFromDB(ctx context.Context) (*db_common.SchemaMetadata, error) {
+	log.Printf("[INFO] DbClient loadSchemaFromDB")
 	mgmtConn, err := c.managementPool.Acquire(ctx)
 	if err != nil {
 		return nil, err
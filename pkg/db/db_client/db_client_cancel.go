@@ -0,0 +1,52 @@
+package db_client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"golang.org/x/exp/maps"
+)
+
+// CancelQuery implements Client
+// cancel the query running in the database session with the given backend pid, using pg_cancel_backend
+// only the queries running in the sessions of this client may be cancelled - the management connection
+// is a superuser, so would otherwise be able to cancel the queries of any user
+// the session itself is not terminated, so it may be used for further queries once the cancelled query has returned
+// if no query is running in the session, this is a no-op
+func (c *DbClient) CancelQuery(ctx context.Context, backendPid uint32) error {
+	if backendPid == 0 {
+		return fmt.Errorf("a backend pid must be provided to cancel a query")
+	}
+	if !slices.Contains(c.SessionBackendPids(), backendPid) {
+		return fmt.Errorf("cannot cancel the query running in backend %d - it is not a session of this client", backendPid)
+	}
+	// use a management connection - the sessions of the user pool may all be busy
+	conn, err := c.AcquireManagementConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	log.Printf("[INFO] cancelling the query running in backend %d", backendPid)
+	var cancelled bool
+	if err := conn.QueryRow(ctx, "select pg_cancel_backend($1)", int32(backendPid)).Scan(&cancelled); err != nil {
+		return err
+	}
+	// pg_cancel_backend returns false if the pid is not a postgres backend
+	if !cancelled {
+		return fmt.Errorf("failed to cancel the query running in backend %d - there is no database session with this pid", backendPid)
+	}
+	return nil
+}
+
+// SessionBackendPids implements Client
+// returns the (sorted) backend pids of the database sessions of this client
+func (c *DbClient) SessionBackendPids() []uint32 {
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+	pids := maps.Keys(c.sessions)
+	slices.Sort(pids)
+	return pids
+}
@@ -0,0 +1,30 @@
+package db_client
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+func TestCancelQueryOnlyCancelsClientSessions(t *testing.T) {
+	c := &DbClient{
+		sessions: map[uint32]*db_common.DatabaseSession{
+			200: db_common.NewDBSession(200),
+			100: db_common.NewDBSession(100),
+		},
+		sessionsMutex: &sync.Mutex{},
+	}
+	if pids := c.SessionBackendPids(); !slices.Equal(pids, []uint32{100, 200}) {
+		t.Errorf("expected session pids [100 200], got %v", pids)
+	}
+
+	// the pid is validated before connecting to the database, so these fail without a database
+	for _, pid := range []uint32{0, 300} {
+		if err := c.CancelQuery(context.Background(), pid); err == nil {
+			t.Errorf("expected an error cancelling backend %d, which is not a session of the client", pid)
+		}
+	}
+}
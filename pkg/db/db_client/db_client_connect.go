@@ -2,15 +2,18 @@ package db_client
 
 import (
 	"context"
+	"log"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/viper"
-	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/constants/runtime"
 	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/plugin"
 	"github.com/turbot/steampipe/pkg/utils"
 )
 
@@ -21,6 +24,38 @@ const (
 
 type DbConnectionCallback func(context.Context, *pgx.Conn) error
 
+// isLocalHost returns whether the given database host refers to this machine
+// - i.e. it is empty, 'localhost', a loopback address or a unix socket directory
+func isLocalHost(host string) bool {
+	// a host starting with a slash is the directory of a unix domain socket
+	if host == "" || strings.HasPrefix(host, "/") || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	// strip any zone from an ipv6 address, e.g. ::1%lo0
+	if idx := strings.Index(host, "%"); idx != -1 {
+		host = host[:idx]
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// hasPreV5Plugins returns whether any installed plugin was built with an sdk version earlier than v5
+// if this cannot be determined, assume there are pre-v5 plugins so that the cache workaround is applied
+func hasPreV5Plugins(ctx context.Context) bool {
+	compatibility, err := plugin.GetPluginCompatibility(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to determine the sdk version of installed plugins: %s", err.Error())
+		return true
+	}
+	for _, c := range compatibility {
+		if c.UsesPreV5Sdk() {
+			log.Printf("[TRACE] plugin %s uses sdk v%s - the client cache setting will follow the service cache setting", c.Plugin, c.SdkVersion)
+			return true
+		}
+	}
+	return false
+}
+
 func (c *DbClient) establishConnectionPool(ctx context.Context, overrides clientConfig) error {
 	utils.LogTime("db_client.establishConnectionPool start")
 	defer utils.LogTime("db_client.establishConnectionPool end")
@@ -30,23 +65,19 @@ func (c *DbClient) establishConnectionPool(ctx context.Context, overrides client
 		return err
 	}
 
-	locals := []string{
-		"127.0.0.1",
-		"::1",
-		"localhost",
-	}
-
 	// when connected to a service which is running a plugin compiled with SDK pre-v5, the plugin
 	// will not have the ability to turn off caching (feature introduced in SDKv5)
 	//
-	// the 'isLocalService' is used to set the client end cache to 'false' if caching is turned off in the local service
+	// the 'isLocalService' flag is used (along with 'localServiceHasPreV5Plugins') to set the client end cache
+	// to 'false' if caching is turned off in the local service
 	//
 	// this is a temporary workaround to make sure
 	// that we can turn off caching for plugins compiled with SDK pre-V5
 	// worst case scenario is that we don't switch off the cache for pre-V5 plugins
 	// refer to: https://github.com/turbot/steampipe/blob/f7f983a552a07e50e526fcadf2ccbfdb7b247cc0/pkg/db/db_client/db_client_session.go#L66
-	if helpers.StringSliceContains(locals, config.ConnConfig.Host) {
-		c.isLocalService = true
+	c.isLocalService = isLocalHost(config.ConnConfig.Host)
+	if c.isLocalService {
+		c.localServiceHasPreV5Plugins = hasPreV5Plugins(ctx)
 	}
 
 	// MinConns should default to 0, but when not set, it actually get very high values (e.g. 80217984)
@@ -57,6 +88,9 @@ func (c *DbClient) establishConnectionPool(ctx context.Context, overrides client
 	config.MaxConns = int32(db_common.MaxDbConnections())
 	config.MaxConnLifetime = MaxConnLifeTime
 	config.MaxConnIdleTime = MaxConnIdleTime
+	// the query exec mode is left as parsed from the connection string, so a 'default_query_exec_mode' set by the user
+	// is respected - by default pgx caches prepared statements per connection, so a control query which is executed
+	// repeatedly with different variable bindings during a check run is only parsed once per connection
 	if c.onConnectionCallback != nil {
 		config.AfterConnect = c.onConnectionCallback
 	}
@@ -65,6 +99,20 @@ func (c *DbClient) establishConnectionPool(ctx context.Context, overrides client
 	config.ConnConfig.Config.RuntimeParams = map[string]string{
 		constants.RuntimeParamsKeyApplicationName: runtime.ClientConnectionAppName,
 	}
+	// for a read only client, default all transactions in the user sessions to read only
+	if overrides.readOnly {
+		config.ConnConfig.Config.RuntimeParams[constants.RuntimeParamsKeyDefaultTransactionReadOnly] = "on"
+	}
+
+	// if there are failover endpoints, direct new connections to the first healthy endpoint
+	if len(overrides.failoverConnectionStrings) > 0 {
+		c.endpoints, err = newFailoverEndpoints(c.connectionString, overrides.failoverConnectionStrings, overrides.onAuthenticationFailure)
+		if err != nil {
+			return err
+		}
+		c.endpoints.start(ctx)
+		config.BeforeConnect = c.endpoints.beforeConnect
+	}
 
 	// apply any overrides
 	// this is used to set the pool size and lifetimes of the connections from up top
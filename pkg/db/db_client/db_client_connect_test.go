@@ -0,0 +1,27 @@
+package db_client
+
+import "testing"
+
+func TestIsLocalHost(t *testing.T) {
+	tests := map[string]bool{
+		"":                      true,
+		"localhost":             true,
+		"LOCALHOST":             true,
+		"127.0.0.1":             true,
+		"127.0.1.1":             true,
+		"::1":                   true,
+		"[::1]":                 true,
+		"::1%lo0":               true,
+		"/tmp":                  true,
+		"/var/run/postgresql":   true,
+		"10.0.0.1":              false,
+		"::2":                   false,
+		"db.example.com":        false,
+		"localhost.example.com": false,
+	}
+	for host, expected := range tests {
+		if res := isLocalHost(host); res != expected {
+			t.Errorf("isLocalHost(%q): expected %v, got %v", host, expected, res)
+		}
+	}
+}
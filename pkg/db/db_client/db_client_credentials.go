@@ -0,0 +1,43 @@
+package db_client
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// the maximum time taken to notify the service that the credentials of a connection have expired
+const credentialsExpiredNotificationTimeout = 5 * time.Second
+
+// notifyIfCredentialsExpired notifies the service if a query failed because the credentials of a connection have expired
+// the plugin manager refreshes the credentials of the connection named in the error, so subsequent queries succeed
+//
+// this is how expiry is detected for static schema plugins (e.g. aws), which make no API calls when their schema
+// is imported, so their credentials are only found to have expired when a table is queried
+//
+// the notification is sent as a command, so the service only acts on it if it was sent by a member of the admin role
+// (otherwise any database user could force connections to be reloaded and their caches cleared)
+func (c *DbClient) notifyIfCredentialsExpired(err error) {
+	if err == nil || c.userPool == nil || !error_helpers.IsCredentialExpiryError(err.Error()) {
+		return
+	}
+	// notify asynchronously, so the query error is returned without waiting
+	// (the query context is not used, as it may already be cancelled)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), credentialsExpiredNotificationTimeout)
+		defer cancel()
+		conn, connErr := c.userPool.Acquire(ctx)
+		if connErr != nil {
+			log.Printf("[WARN] failed to notify the service that connection credentials have expired: %s", connErr.Error())
+			return
+		}
+		defer conn.Release()
+		if notifyErr := db_common.SendPostgresCommandNotification(ctx, conn.Conn(), steampipeconfig.NewCredentialsExpiredNotification(err.Error())); notifyErr != nil {
+			log.Printf("[WARN] failed to notify the service that connection credentials have expired: %s", notifyErr.Error())
+		}
+	}()
+}
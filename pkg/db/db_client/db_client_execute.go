@@ -19,6 +19,7 @@ import (
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/tracing"
 	"github.com/turbot/steampipe/pkg/utils"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -104,6 +105,9 @@ func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.Data
 		return nil, fmt.Errorf("nil database connection passed to ExecuteInSession")
 	}
 	startTime := time.Now()
+	// trace the execution of the query - if the query starts, the span is ended once its rows have been read
+	ctx, span := tracing.StartSpan(ctx, "DbClient.ExecuteInSession", tracing.StatementAttributes(query)...)
+
 	// get a context with a timeout for the query to execute within
 	// we don't use the cancelFn from this timeout context, since usage will lead to 'pgx'
 	// prematurely closing the database connection that this query executed in
@@ -111,9 +115,29 @@ func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.Data
 
 	var tx *sql.Tx
 
+	// if the query has a no_cache hint, or a cache bypass was requested, disable the cache for this query only
+	bypassCache := c.shouldBypassCache(ctx, query)
+	restoreCache := func() {
+		// the cache is only bypassed if it is enabled for the session, so re-enable it
+		if bypassCache {
+			if err := db_common.SetCacheEnabled(ctx, true, session.Connection.Conn()); err != nil {
+				log.Printf("[WARN] failed to re-enable the cache after bypassing it: %s", err.Error())
+			}
+		}
+	}
+
 	defer func() {
 		if err != nil {
+			c.notifyIfCredentialsExpired(err)
 			err = error_helpers.HandleQueryTimeoutError(err)
+			if _, ok := error_helpers.AsTypedError(err); !ok {
+				code := error_helpers.ErrorCodeQueryFailed
+				if error_helpers.IsCancelledError(err) {
+					code = error_helpers.ErrorCodeQueryCancelled
+				}
+				err = error_helpers.NewQueryError(code, err)
+			}
+			restoreCache()
 			// stop spinner in case of error
 			statushooks.Done(ctxExecute)
 			// error - rollback transaction if we have one
@@ -124,9 +148,25 @@ func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.Data
 			if onComplete != nil {
 				onComplete()
 			}
+			tracing.EndSpan(span, err)
 		}
 	}()
 
+	// a read only client rejects statements which would write, rather than relying on the database to fail them
+	if c.readOnly && db_common.IsWriteStatement(query) {
+		bypassCache = false
+		err = fmt.Errorf("the query was rejected as this client is read only - statements which write data or modify the schema are not permitted")
+		return
+	}
+
+	if bypassCache {
+		log.Printf("[TRACE] bypassing the cache for query")
+		if err = db_common.SetCacheEnabled(ctxExecute, false, session.Connection.Conn()); err != nil {
+			bypassCache = false
+			return
+		}
+	}
+
 	// start query
 	var rows pgx.Rows
 	rows, err = c.startQueryWithRetries(ctxExecute, session, query, args...)
@@ -134,20 +174,34 @@ func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.Data
 		return
 	}
 
-	colDefs := fieldDescriptionsToColumns(rows.FieldDescriptions(), session.Connection.Conn())
+	fieldDescriptions := rows.FieldDescriptions()
+	colDefs := fieldDescriptionsToColumns(fieldDescriptions, session.Connection.Conn())
 
 	result := queryresult.NewResult(colDefs)
 
+	onRowsClosed := func() {
+		restoreCache()
+		// if a stable row order was requested, identify the primary key columns so rows are ordered by these first
+		// (this must be done before the result is closed, as the rows are sorted once they have all been read)
+		if viper.GetBool(constants.ArgStableOrder) {
+			if err := setPrimaryKeyColumns(ctxExecute, session.Connection.Conn(), fieldDescriptions, colDefs); err != nil {
+				log.Printf("[WARN] failed to identify the primary key columns of the query result: %s", err.Error())
+			}
+		}
+	}
+
 	// read the rows in a go routine
 	go func() {
 		// define a callback which fetches the timing information
 		// this will be invoked after reading rows is complete but BEFORE closing the rows object (which closes the connection)
 		timingCallback := func() {
-			c.getQueryTiming(ctxExecute, startTime, session, result.TimingResult)
+			c.getQueryTiming(ctxExecute, startTime, session, query, args, result.TimingResult)
 		}
 
 		// read in the rows and stream to the query result object
-		c.readRows(ctxExecute, rows, result, timingCallback)
+		// (restore the cache and identify the primary key columns once the rows are closed, before the result is closed)
+		c.readRows(ctxExecute, rows, result, timingCallback, onRowsClosed)
+		tracing.EndSpan(span, rows.Err())
 
 		// call the completion callback - if one was provided
 		if onComplete != nil {
@@ -158,6 +212,33 @@ func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.Data
 	return result, nil
 }
 
+// shouldBypassCache returns whether the cache should be bypassed for the query - either because it has a no_cache hint
+// or because the context requests a cache bypass (see db_common.WithCacheBypass)
+func (c *DbClient) shouldBypassCache(ctx context.Context, query string) bool {
+	if !db_common.IsCacheBypassRequested(ctx) && !db_common.HasNoCacheHint(query) {
+		return false
+	}
+	// if caching is disabled for the session, there is nothing to bypass
+	// (and the cache must not be enabled once the query completes)
+	return c.sessionCacheEnabled()
+}
+
+// sessionCacheEnabled returns whether the cache is enabled for the sessions of this client
+// this mirrors the cache settings applied to each session when it is acquired (see AcquireSession)
+func (c *DbClient) sessionCacheEnabled() bool {
+	if c.isLocalService && c.localServiceHasPreV5Plugins && !viper.GetBool(constants.ArgServiceCacheEnabled) {
+		return false
+	}
+	if viper.IsSet(constants.ArgClientCacheEnabled) {
+		return viper.GetBool(constants.ArgClientCacheEnabled)
+	}
+	// the session uses the server setting
+	if serverSettings := c.ServerSettings(); serverSettings != nil {
+		return serverSettings.CacheEnabled
+	}
+	return true
+}
+
 func (c *DbClient) getExecuteContext(ctx context.Context) context.Context {
 	queryTimeout := time.Duration(viper.GetInt(constants.ArgDatabaseQueryTimeout)) * time.Second
 	// if timeout is zero, do not set a timeout
@@ -172,7 +253,7 @@ func (c *DbClient) getExecuteContext(ctx context.Context) context.Context {
 	return newCtx
 }
 
-func (c *DbClient) getQueryTiming(ctx context.Context, startTime time.Time, session *db_common.DatabaseSession, resultChannel chan *queryresult.TimingResult) {
+func (c *DbClient) getQueryTiming(ctx context.Context, startTime time.Time, session *db_common.DatabaseSession, query string, args []any, resultChannel chan *queryresult.TimingResult) {
 	// do not fetch if timing is disabled, unless output not JSON
 	if !c.shouldFetchTiming() {
 		return
@@ -190,76 +271,10 @@ func (c *DbClient) getQueryTiming(ctx context.Context, startTime time.Time, sess
 		resultChannel <- timingResult
 	}()
 
-	// load the timing summary
-	summary, err := c.loadTimingSummary(ctx, session)
-	if err != nil {
-		log.Printf("[WARN] getQueryTiming: failed to read scan metadata, err: %s", err)
-		return
-	}
-
-	// only load the individual scan  metadata if output is JSON or timing is verbose
-	var scans []*queryresult.ScanMetadataRow
-	if c.shouldFetchVerboseTiming() {
-		scans, err = c.loadTimingMetadata(ctx, session)
-		if err != nil {
-			log.Printf("[WARN] getQueryTiming: failed to read scan metadata, err: %s", err)
-			return
-		}
+	collector := newTimingCollector(viper.GetString(constants.ArgTimingSource))
+	if err := collector.CollectTiming(ctx, session, query, args, c.shouldFetchVerboseTiming(), timingResult); err != nil {
+		log.Printf("[WARN] getQueryTiming: failed to collect timing data, err: %s", err)
 	}
-
-	// populate hydrate calls and rows fetched
-	timingResult.Initialise(summary, scans)
-}
-
-func (c *DbClient) loadTimingSummary(ctx context.Context, session *db_common.DatabaseSession) (*queryresult.QueryRowSummary, error) {
-	var summary = &queryresult.QueryRowSummary{}
-	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
-		query := fmt.Sprintf(`select uncached_rows_fetched,
-cached_rows_fetched,
-hydrate_calls, 
-scan_count,
-connection_count from %s.%s `, constants.InternalSchema, constants.ForeignTableScanMetadataSummary)
-		//query := fmt.Sprintf("select id, 'table' as table, cache_hit, rows_fetched, hydrate_calls, start_time, duration, columns, 'limit' as limit, quals from %s.%s where id > %d", constants.InternalSchema, constants.ForeignTableScanMetadata, session.ScanMetadataMaxId)
-		rows, err := tx.Query(ctx, query)
-		if err != nil {
-			return err
-		}
-
-		// scan into summary
-		summary, err = pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[queryresult.QueryRowSummary])
-		// no rows counts as an error
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-	return summary, err
-}
-
-func (c *DbClient) loadTimingMetadata(ctx context.Context, session *db_common.DatabaseSession) ([]*queryresult.ScanMetadataRow, error) {
-	var scans []*queryresult.ScanMetadataRow
-
-	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
-		query := fmt.Sprintf(`
-select connection,
-"table",
-cache_hit, 
-rows_fetched, 
-hydrate_calls, 
-start_time,
-duration_ms,
-columns,
-"limit",
-quals from %s.%s order by duration_ms desc`, constants.InternalSchema, constants.ForeignTableScanMetadata)
-		rows, err := tx.Query(ctx, query)
-		if err != nil {
-			return err
-		}
-
-		scans, err = pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[queryresult.ScanMetadataRow])
-		return err
-	})
-	return scans, err
 }
 
 // run query in a goroutine, so we can check for cancellation
@@ -280,7 +295,7 @@ func (c *DbClient) startQuery(ctx context.Context, conn *pgx.Conn, query string,
 	return
 }
 
-func (c *DbClient) readRows(ctx context.Context, rows pgx.Rows, result *queryresult.Result, timingCallback func()) {
+func (c *DbClient) readRows(ctx context.Context, rows pgx.Rows, result *queryresult.Result, timingCallback func(), onRowsClosed func()) {
 	// defer this, so that these get cleaned up even if there is an unforeseen error
 	defer func() {
 		// we are done fetching results. time for display. clear the status indication
@@ -290,8 +305,10 @@ func (c *DbClient) readRows(ctx context.Context, rows pgx.Rows, result *queryres
 		// close the sql rows object
 		rows.Close()
 		if err := rows.Err(); err != nil {
+			c.notifyIfCredentialsExpired(err)
 			result.StreamError(err)
 		}
+		onRowsClosed()
 		// close the channels in the result object
 		result.Close()
 
@@ -27,13 +27,18 @@ func (c *DbClient) startQueryWithRetries(ctx context.Context, session *db_common
 	backoff := retry.NewConstant(backoffInterval)
 
 	conn := session.Connection.Conn()
+	// if the query has been prepared as a named statement in the session, execute the prepared statement
+	statement := query
+	if name, ok := session.PreparedStatementName(query); ok {
+		statement = name
+	}
 
 	var res pgx.Rows
 	count := 0
 	err := retry.Do(ctx, retry.WithMaxDuration(maxDuration, backoff), func(ctx context.Context) error {
 		count++
 		log.Println("[TRACE] starting", count)
-		rows, queryError := c.startQuery(ctx, conn, query, args...)
+		rows, queryError := c.startQuery(ctx, conn, statement, args...)
 		// if there is no error, just return
 		if queryError == nil {
 			log.Println("[TRACE] no queryError")
@@ -109,9 +114,10 @@ func (c *DbClient) startQueryWithRetries(ctx context.Context, session *db_common
 		}
 
 		// so schema _is_ in the state map
+		// if the connection is disabled, return an error explaining this rather than the bare relation not found error
 		if connectionState.Disabled() {
 			log.Println("[TRACE] schema", missingSchema, "is disabled")
-			return queryError
+			return connectionState.DisabledError()
 		}
 
 		// if the connection is ready (and has been for more than the backoff interval) , just return the relation not found error
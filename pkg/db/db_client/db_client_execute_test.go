@@ -0,0 +1,86 @@
+package db_client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// the query of a control which is run repeatedly with different variable bindings
+const testControlQuery = "select $1::text as value"
+
+// executeControlQuery executes the control query in the session with the given binding
+func executeControlQuery(tb testing.TB, c *DbClient, session *db_common.DatabaseSession, binding string) {
+	result, err := c.ExecuteInSession(context.Background(), session, nil, testControlQuery, binding)
+	if err != nil {
+		tb.Fatalf("failed to execute the control query: %s", err.Error())
+	}
+	for row := range *result.RowChan {
+		if row.Error != nil {
+			tb.Fatalf("failed to read the control query rows: %s", row.Error.Error())
+		}
+		if len(row.Data) != 1 || row.Data[0] != binding {
+			tb.Fatalf("expected %s, got %v", binding, row.Data)
+		}
+	}
+}
+
+// runControlQueries runs the control query with each binding, acquiring and closing a session for each run
+func runControlQueries(tb testing.TB, c *DbClient, server *fakePostgres, execMode pgx.QueryExecMode, bindings []string) {
+	pool := server.newPool(tb, execMode)
+	for _, binding := range bindings {
+		conn, err := pool.Acquire(context.Background())
+		if err != nil {
+			tb.Fatalf("failed to acquire a connection: %s", err.Error())
+		}
+		session := db_common.NewDBSession(1)
+		session.Connection = conn
+		executeControlQuery(tb, c, session, binding)
+		session.Close(false)
+	}
+}
+
+// the control query is only parsed once per connection, however many times it is run with different variable bindings
+func TestControlQueryPlanReuse(t *testing.T) {
+	server := newFakePostgres(t)
+	c := &DbClient{disableTiming: true}
+
+	runControlQueries(t, c, server, pgx.QueryExecModeCacheStatement, []string{"us-east-1", "us-west-2", "eu-west-1"})
+
+	parsed := server.parsedStatements()
+	if len(parsed) != 1 || parsed[0].SQL != testControlQuery {
+		t.Errorf("expected the control query to be parsed once, got %v", parsed)
+	}
+	// the cached statement is named, so the plan is retained by the connection between executions
+	if parsed[0].Name == "" {
+		t.Errorf("expected the control query to be prepared as a named statement")
+	}
+}
+
+// BenchmarkControlQueryExecMode compares the statements parsed when running a control query with different
+// variable bindings, using the default pgx exec mode and executing each query as an unnamed statement
+func BenchmarkControlQueryExecMode(b *testing.B) {
+	execModes := map[string]pgx.QueryExecMode{
+		"cache_statement": pgx.QueryExecModeCacheStatement,
+		"exec":            pgx.QueryExecModeExec,
+	}
+	for name, execMode := range execModes {
+		b.Run(name, func(b *testing.B) {
+			server := newFakePostgres(b)
+			c := &DbClient{disableTiming: true}
+			bindings := make([]string, b.N)
+			for i := range bindings {
+				bindings[i] = fmt.Sprintf("binding_%d", i)
+			}
+
+			b.ResetTimer()
+			runControlQueries(b, c, server, execMode, bindings)
+			b.StopTimer()
+
+			b.ReportMetric(float64(len(server.parsedStatements()))/float64(b.N), "parses/op")
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package db_client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+const (
+	// the interval at which the health of the failover endpoints is checked
+	endpointHealthCheckInterval = 15 * time.Second
+	// the time allowed to connect to an endpoint when checking its health
+	endpointHealthCheckTimeout = 5 * time.Second
+)
+
+// databaseEndpoint is a database which the client may connect to
+type databaseEndpoint struct {
+	connectionString string
+	config           *pgconn.Config
+	healthy          bool
+}
+
+func (e *databaseEndpoint) String() string {
+	return fmt.Sprintf("%s:%d", e.config.Host, e.config.Port)
+}
+
+// sameCredentials returns whether the other endpoint has the same database and credentials as this endpoint
+// (i.e. it is a different host serving the same database)
+func (e *databaseEndpoint) sameCredentials(other *databaseEndpoint) bool {
+	return e.config.Database == other.config.Database && e.config.User == other.config.User && e.config.Password == other.config.Password
+}
+
+// failoverEndpoints selects the endpoint which new connections of the client are made to
+// the endpoints are health checked periodically - new connections are made to the first healthy endpoint
+// (in the order the endpoints were configured), so when the primary endpoint becomes unavailable connections fail over
+// to the next healthy endpoint, and fail back once the primary is healthy again
+type failoverEndpoints struct {
+	// the primary endpoint followed by the failover endpoints
+	endpoints []*databaseEndpoint
+	// the index of the endpoint new connections are made to
+	active int
+	mut    sync.RWMutex
+	cancel context.CancelFunc
+	// if set, this is called when an endpoint fails its health check as authentication failed
+	onAuthenticationFailure func(connectionString string)
+}
+
+func newFailoverEndpoints(connectionString string, failoverConnectionStrings []string, onAuthenticationFailure func(string)) (*failoverEndpoints, error) {
+	res := &failoverEndpoints{onAuthenticationFailure: onAuthenticationFailure}
+	for _, endpointConnectionString := range append([]string{connectionString}, failoverConnectionStrings...) {
+		config, err := pgconn.ParseConfig(endpointConnectionString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid connection string: %s", err.Error())
+		}
+		res.endpoints = append(res.endpoints, &databaseEndpoint{connectionString: endpointConnectionString, config: config, healthy: true})
+	}
+	return res, nil
+}
+
+// start checks the health of the endpoints and starts a goroutine which re-checks their health periodically
+func (f *failoverEndpoints) start(ctx context.Context) {
+	f.checkHealth(ctx)
+
+	ctx, f.cancel = context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(endpointHealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.checkHealth(ctx)
+			}
+		}
+	}()
+}
+
+func (f *failoverEndpoints) stop() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// checkHealth connects to each endpoint in parallel and updates the active endpoint
+func (f *failoverEndpoints) checkHealth(ctx context.Context) {
+	healthy := make([]bool, len(f.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range f.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *databaseEndpoint) {
+			defer wg.Done()
+			err := probeEndpoint(ctx, endpoint)
+			if err != nil {
+				log.Printf("[TRACE] database endpoint %s is unhealthy: %s", endpoint, err.Error())
+				if db_common.IsAuthenticationError(err) && f.onAuthenticationFailure != nil {
+					f.onAuthenticationFailure(endpoint.connectionString)
+				}
+			}
+			healthy[i] = err == nil
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	for i, endpoint := range f.endpoints {
+		endpoint.healthy = healthy[i]
+	}
+	active := f.firstHealthy()
+	if active != f.active {
+		log.Printf("[WARN] database endpoint %s is unavailable - new connections will be made to %s", f.endpoints[f.active], f.endpoints[active])
+		f.active = active
+	}
+}
+
+// firstHealthy returns the index of the first healthy endpoint
+// if no endpoint is healthy, the active endpoint is retained
+func (f *failoverEndpoints) firstHealthy() int {
+	for i, endpoint := range f.endpoints {
+		if endpoint.healthy {
+			return i
+		}
+	}
+	return f.active
+}
+
+// beforeConnect is the BeforeConnect hook of the connection pools
+// it directs the new connection to the active endpoint - the other healthy endpoints which serve the same database
+// with the same credentials are added as fallbacks, so the connection fails over immediately if the active endpoint
+// becomes unavailable between health checks
+func (f *failoverEndpoints) beforeConnect(_ context.Context, config *pgx.ConnConfig) error {
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	active := f.endpoints[f.active]
+	config.Host = active.config.Host
+	config.Port = active.config.Port
+	config.Database = active.config.Database
+	config.User = active.config.User
+	config.Password = active.config.Password
+	config.TLSConfig = active.config.TLSConfig
+	config.Fallbacks = append([]*pgconn.FallbackConfig{}, active.config.Fallbacks...)
+	for i, endpoint := range f.endpoints {
+		if i == f.active || !endpoint.healthy || !active.sameCredentials(endpoint) {
+			continue
+		}
+		config.Fallbacks = append(config.Fallbacks, &pgconn.FallbackConfig{
+			Host:      endpoint.config.Host,
+			Port:      endpoint.config.Port,
+			TLSConfig: endpoint.config.TLSConfig,
+		})
+	}
+	return nil
+}
+
+// probeEndpoint opens (and closes) a connection to the endpoint
+func probeEndpoint(ctx context.Context, endpoint *databaseEndpoint) error {
+	probeCtx, cancel := context.WithTimeout(ctx, endpointHealthCheckTimeout)
+	defer cancel()
+
+	conn, err := pgconn.ConnectConfig(probeCtx, endpoint.config)
+	if err != nil {
+		return err
+	}
+	return conn.Close(probeCtx)
+}
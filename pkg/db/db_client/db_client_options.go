@@ -28,6 +28,11 @@ func (c PoolOverrides) apply(config *pgxpool.Config) {
 type clientConfig struct {
 	userPoolSettings       PoolOverrides
 	managementPoolSettings PoolOverrides
+	readOnly               bool
+	// connection strings of endpoints to fail over to if the primary endpoint is unavailable
+	failoverConnectionStrings []string
+	// called with the connection string of an endpoint when authentication to it fails
+	onAuthenticationFailure func(connectionString string)
 }
 
 type ClientOption func(*clientConfig)
@@ -43,3 +48,28 @@ func WithManagementPoolOverride(s PoolOverrides) ClientOption {
 		cc.managementPoolSettings = s
 	}
 }
+
+// WithReadOnly sets whether the client is read only
+// the sessions of a read only client default to read only transactions, and statements which
+// write data or modify the schema are rejected by the client before they are sent to the database
+func WithReadOnly(readOnly bool) ClientOption {
+	return func(cc *clientConfig) {
+		cc.readOnly = readOnly
+	}
+}
+
+// WithFailoverConnectionStrings sets the connection strings of the endpoints which the client fails over to
+// if the endpoint of its connection string is unavailable - the endpoints are used in order of preference
+func WithFailoverConnectionStrings(connectionStrings []string) ClientOption {
+	return func(cc *clientConfig) {
+		cc.failoverConnectionStrings = connectionStrings
+	}
+}
+
+// WithAuthenticationFailureHandler sets a function which is called with the connection string of an endpoint
+// when a health check of the endpoint fails to authenticate (e.g. because the database password has been rotated)
+func WithAuthenticationFailureHandler(f func(connectionString string)) ClientOption {
+	return func(cc *clientConfig) {
+		cc.onAuthenticationFailure = f
+	}
+}
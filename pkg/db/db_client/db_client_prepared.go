@@ -0,0 +1,43 @@
+package db_client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// Prepare implements Client
+// prepare the query as a named statement in the given DatabaseSession
+// the statement may then be executed repeatedly (with different args) using ExecutePrepared, and is only planned once
+// the statement is dropped when the session is closed
+func (c *DbClient) Prepare(ctx context.Context, session *db_common.DatabaseSession, name, query string) error {
+	if session == nil {
+		return fmt.Errorf("nil session passed to Prepare")
+	}
+	if session.Connection == nil {
+		return fmt.Errorf("nil database connection passed to Prepare")
+	}
+	// a read only client rejects statements which would write (see ExecuteInSession)
+	if c.readOnly && db_common.IsWriteStatement(query) {
+		return fmt.Errorf("the query was rejected as this client is read only - statements which write data or modify the schema are not permitted")
+	}
+	return session.Prepare(ctx, name, query)
+}
+
+// ExecutePrepared implements Client
+// execute the named statement which was prepared in the given DatabaseSession using Prepare
+// as with ExecuteInSession, the caller is responsible for the lifecycle of the DatabaseSession
+// NOTE: The returned Result MUST be fully read - otherwise the connection will block and will prevent further communication
+func (c *DbClient) ExecutePrepared(ctx context.Context, session *db_common.DatabaseSession, onComplete func(), name string, args ...any) (*queryresult.Result, error) {
+	if session == nil {
+		return nil, fmt.Errorf("nil session passed to ExecutePrepared")
+	}
+	query, ok := session.PreparedQuery(name)
+	if !ok {
+		return nil, fmt.Errorf("prepared statement '%s' does not exist in this session", name)
+	}
+	// the session resolves the query to the prepared statement when it is started
+	return c.ExecuteInSession(ctx, session, onComplete, query, args...)
+}
@@ -0,0 +1,111 @@
+package db_client
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// executePreparedValue executes the prepared statement and returns the value of the single row
+func executePreparedValue(t *testing.T, c *DbClient, session *db_common.DatabaseSession, name string, args ...any) any {
+	t.Helper()
+	result, err := c.ExecutePrepared(context.Background(), session, nil, name, args...)
+	if err != nil {
+		t.Fatalf("failed to execute prepared statement %s: %s", name, err.Error())
+	}
+	var values []any
+	for row := range *result.RowChan {
+		if row.Error != nil {
+			t.Fatalf("failed to read the rows of prepared statement %s: %s", name, row.Error.Error())
+		}
+		values = append(values, row.Data...)
+	}
+	if len(values) != 1 {
+		t.Fatalf("expected a single value, got %v", values)
+	}
+	return values[0]
+}
+
+func TestPreparedStatements(t *testing.T) {
+	server := newFakePostgres(t)
+	pool := server.newPool(t, pgx.QueryExecModeCacheStatement)
+	ctx := context.Background()
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("failed to acquire a connection: %s", err.Error())
+	}
+	session := db_common.NewDBSession(1)
+	session.Connection = conn
+	c := &DbClient{disableTiming: true}
+
+	const query = "select $1::text as value"
+	if err := c.Prepare(ctx, session, "by_value", query); err != nil {
+		t.Fatalf("failed to prepare statement: %s", err.Error())
+	}
+	// the statement is planned once, and reused for each execution
+	for _, arg := range []string{"a", "b", "c"} {
+		if got := executePreparedValue(t, c, session, "by_value", arg); got != arg {
+			t.Errorf("expected %s, got %v", arg, got)
+		}
+	}
+	expectedParsed := []fakeStatement{{Name: "steampipe_prepared_by_value", SQL: query}}
+	if parsed := server.parsedStatements(); !slices.Equal(parsed, expectedParsed) {
+		t.Errorf("expected the statement to be parsed once, got %v", parsed)
+	}
+
+	if _, err := c.ExecutePrepared(ctx, session, nil, "unknown"); err == nil {
+		t.Errorf("expected an error executing a statement which has not been prepared")
+	}
+
+	// preparing a different query with the same name replaces the statement
+	const replacement = "select $1::text as value, $2::text as other"
+	if err := c.Prepare(ctx, session, "by_value", replacement); err != nil {
+		t.Fatalf("failed to replace statement: %s", err.Error())
+	}
+	if closed := server.closedStatements(); !slices.Equal(closed, []string{"steampipe_prepared_by_value"}) {
+		t.Errorf("expected the replaced statement to be deallocated, got %v", closed)
+	}
+	if got, _ := session.PreparedQuery("by_value"); got != replacement {
+		t.Errorf("expected the statement to be replaced, got %s", got)
+	}
+
+	// the statements are dropped when the session is closed
+	if err := c.Prepare(ctx, session, "other", query); err != nil {
+		t.Fatalf("failed to prepare statement: %s", err.Error())
+	}
+	session.Close(false)
+	closed := server.closedStatements()
+	slices.Sort(closed[1:])
+	if expected := []string{"steampipe_prepared_by_value", "steampipe_prepared_by_value", "steampipe_prepared_other"}; !slices.Equal(closed, expected) {
+		t.Errorf("expected the session statements to be deallocated on close, got %v", closed)
+	}
+	if _, ok := session.PreparedQuery("other"); ok {
+		t.Errorf("expected no prepared statements after the session is closed")
+	}
+}
+
+func TestPrepareReadOnly(t *testing.T) {
+	server := newFakePostgres(t)
+	pool := server.newPool(t, pgx.QueryExecModeCacheStatement)
+	ctx := context.Background()
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("failed to acquire a connection: %s", err.Error())
+	}
+	session := db_common.NewDBSession(1)
+	session.Connection = conn
+	defer session.Close(false)
+	c := &DbClient{readOnly: true}
+
+	if err := c.Prepare(ctx, session, "write", "insert into t values ($1)"); err == nil {
+		t.Errorf("expected a read only client to reject preparing a write statement")
+	}
+	if parsed := server.parsedStatements(); len(parsed) != 0 {
+		t.Errorf("expected no statements to be parsed, got %v", parsed)
+	}
+}
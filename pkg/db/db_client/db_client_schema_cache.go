@@ -0,0 +1,84 @@
+package db_client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// DefaultSchemaCacheTtl is the time after which cached schema metadata is reloaded
+// this is used by clients which do not receive the schema update notifications sent when connections are refreshed,
+// i.e. clients connected to a remote server
+const DefaultSchemaCacheTtl = 5 * time.Minute
+
+// schemaMetadataCache caches the schema metadata loaded by GetSchemaFromDB
+type schemaMetadataCache struct {
+	mut      sync.Mutex
+	metadata *db_common.SchemaMetadata
+	// the search path the metadata was loaded for
+	searchPath string
+	loadTime   time.Time
+	// incremented whenever the cache is invalidated, so a load which was in progress is not cached
+	generation int
+	// if zero, cached metadata does not expire
+	ttl time.Duration
+}
+
+func newSchemaMetadataCache(ttl time.Duration) *schemaMetadataCache {
+	return &schemaMetadataCache{ttl: ttl}
+}
+
+// get returns the cached metadata for the search path (or nil), and the current cache generation
+func (c *schemaMetadataCache) get(searchPath []string) (*db_common.SchemaMetadata, int) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.metadata == nil || c.searchPath != strings.Join(searchPath, ",") {
+		return nil, c.generation
+	}
+	if c.ttl > 0 && time.Since(c.loadTime) > c.ttl {
+		return nil, c.generation
+	}
+	return c.metadata, c.generation
+}
+
+// set caches the metadata, unless the cache has been invalidated since the given generation
+func (c *schemaMetadataCache) set(searchPath []string, metadata *db_common.SchemaMetadata, generation int) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	c.metadata = metadata
+	c.searchPath = strings.Join(searchPath, ",")
+	c.loadTime = time.Now()
+}
+
+func (c *schemaMetadataCache) invalidate() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.metadata = nil
+	c.generation++
+}
+
+func (c *schemaMetadataCache) setTtl(ttl time.Duration) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.ttl = ttl
+}
+
+// InvalidateSchemaCache clears the cached schema metadata, so it is reloaded by the next call to GetSchemaFromDB
+func (c *DbClient) InvalidateSchemaCache() {
+	c.schemaCache.invalidate()
+}
+
+// SetSchemaCacheTtl sets the time after which cached schema metadata is reloaded
+// a ttl of zero means the cached metadata does not expire - it is only reloaded after InvalidateSchemaCache is called
+func (c *DbClient) SetSchemaCacheTtl(ttl time.Duration) {
+	c.schemaCache.setTtl(ttl)
+}
@@ -0,0 +1,47 @@
+package db_client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+func TestSchemaMetadataCache(t *testing.T) {
+	searchPath := []string{"public", "aws"}
+	metadata := db_common.NewSchemaMetadata()
+
+	c := newSchemaMetadataCache(0)
+	cached, generation := c.get(searchPath)
+	if cached != nil {
+		t.Fatalf("expected an empty cache")
+	}
+	c.set(searchPath, metadata, generation)
+	if cached, _ := c.get(searchPath); cached != metadata {
+		t.Errorf("expected the cached metadata to be returned")
+	}
+	// metadata cached for a different search path is not returned
+	if cached, _ := c.get([]string{"public", "gcp"}); cached != nil {
+		t.Errorf("expected no metadata for a different search path")
+	}
+
+	// after invalidation, nothing is returned
+	_, generation = c.get(searchPath)
+	c.invalidate()
+	if cached, _ := c.get(searchPath); cached != nil {
+		t.Errorf("expected no metadata after invalidation")
+	}
+	// and a load started before the invalidation is not cached
+	c.set(searchPath, metadata, generation)
+	if cached, _ := c.get(searchPath); cached != nil {
+		t.Errorf("expected metadata loaded before invalidation not to be cached")
+	}
+
+	// cached metadata expires after the ttl
+	c = newSchemaMetadataCache(time.Millisecond)
+	c.set(searchPath, metadata, 0)
+	time.Sleep(5 * time.Millisecond)
+	if cached, _ := c.get(searchPath); cached != nil {
+		t.Errorf("expected cached metadata to expire")
+	}
+}
@@ -56,12 +56,12 @@ func (c *DbClient) AcquireSession(ctx context.Context) (sessionResult *db_common
 		}
 	}()
 
-	// if this is connected to a local service (localhost) and if the server cache
-	// is disabled, override the client setting to always disable
+	// if this is connected to a local service (localhost) which has pre-V5 plugins installed
+	// and if the server cache is disabled, override the client setting to always disable
 	//
 	// this is a temporary workaround to make sure
 	// that we turn off caching for plugins compiled with SDK pre-V5
-	if c.isLocalService && !viper.GetBool(constants.ArgServiceCacheEnabled) {
+	if c.isLocalService && c.localServiceHasPreV5Plugins && !viper.GetBool(constants.ArgServiceCacheEnabled) {
 		if err := db_common.SetCacheEnabled(ctx, false, databaseConnection.Conn()); err != nil {
 			sessionResult.Error = err
 			return sessionResult
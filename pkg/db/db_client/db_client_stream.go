@@ -0,0 +1,61 @@
+package db_client
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// StreamQuery implements Client
+// execute the query and pass each row to onRow as it is read, rather than collecting the rows into a result
+// rows are not buffered - the next row is not read from the database until onRow returns, so memory use
+// does not grow with the size of the result and a slow consumer applies backpressure to the query
+// if onRow returns an error, the query is cancelled and the error is returned
+func (c *DbClient) StreamQuery(ctx context.Context, query string, onRow queryresult.RowCallback, args ...any) (err error) {
+	// acquire a session
+	sessionResult := c.AcquireSession(ctx)
+	if sessionResult.Error != nil {
+		return sessionResult.Error
+	}
+
+	// create a cancellable context so that the query can be cancelled if the callback fails
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// set if the query is cancelled because the callback failed
+	var callbackFailed bool
+	defer func() {
+		// if the callback failed, the query was cancelled and the connection may be left mid-query
+		// close it, so the pool discards it when the session is released rather than reusing it
+		if callbackFailed {
+			_ = sessionResult.Session.Connection.Conn().Close(context.Background())
+		}
+		// if the query was cancelled, wait for pgx to clean up the connection before releasing the session
+		sessionResult.Session.Close(callbackFailed || error_helpers.IsContextCanceled(ctx))
+	}()
+
+	result, err := c.ExecuteInSession(streamCtx, sessionResult.Session, nil, query, args...)
+	if err != nil {
+		return error_helpers.WrapError(err)
+	}
+
+	for row := range *result.RowChan {
+		// once there is an error, drain the remaining rows so the query can complete
+		if err != nil {
+			continue
+		}
+		if row.Error != nil {
+			err = error_helpers.WrapError(row.Error)
+			continue
+		}
+		if err = onRow(result.Cols, row.Data); err != nil {
+			callbackFailed = true
+			cancel()
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package db_client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// the query streamed by the tests - the fake postgres server returns $2 rows, each containing $1
+const testStreamQuery = "select $1::text as value from generate_series(1, $2::int)"
+
+func TestStreamQuery(t *testing.T) {
+	errCallback := errors.New("callback failed")
+
+	testCases := map[string]struct {
+		rowCount int
+		// the callback fails when it is passed the row with this (1-based) index
+		failOnRow     int
+		expectedCalls int
+		expectedErr   error
+	}{
+		"each row is passed to the callback": {
+			rowCount:      5,
+			expectedCalls: 5,
+		},
+		"no rows": {
+			rowCount: 0,
+		},
+		"callback error cancels the query": {
+			rowCount:      5,
+			failOnRow:     2,
+			expectedCalls: 2,
+			expectedErr:   errCallback,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := newFakePostgres(t)
+			c := &DbClient{
+				userPool:      server.newPool(t, pgx.QueryExecModeCacheStatement),
+				sessions:      make(map[uint32]*db_common.DatabaseSession),
+				sessionsMutex: &sync.Mutex{},
+				disableTiming: true,
+			}
+			ctx := context.Background()
+
+			var calls int
+			var values []any
+			err := c.StreamQuery(ctx, testStreamQuery, func(cols []*queryresult.ColumnDef, row []any) error {
+				calls++
+				// each call is passed a single row
+				if len(cols) != 1 || len(row) != 1 {
+					t.Errorf("expected a single column, got columns %v and row %v", cols, row)
+				}
+				values = append(values, row...)
+				if calls == test.failOnRow {
+					return errCallback
+				}
+				return nil
+			}, "a", strconv.Itoa(test.rowCount))
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			// once the callback fails, no further rows are passed to it
+			if calls != test.expectedCalls {
+				t.Errorf("expected %d callback calls, got %d", test.expectedCalls, calls)
+			}
+			for _, value := range values {
+				if value != "a" {
+					t.Errorf("expected each row to contain 'a', got %v", values)
+					break
+				}
+			}
+
+			// the session has been released, so the pool can be used for further queries
+			values = nil
+			err = c.StreamQuery(ctx, testStreamQuery, func(_ []*queryresult.ColumnDef, row []any) error {
+				values = append(values, row...)
+				return nil
+			}, "b", "2")
+			if err != nil {
+				t.Fatalf("failed to stream a query after the previous stream: %s", err.Error())
+			}
+			if !slices.Equal(values, []any{"b", "b"}) {
+				t.Errorf("expected [b b], got %v", values)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package db_client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// the OID of the postgres text type - all parameters and columns of the fake postgres server are text
+const fakePostgresTextOID = 25
+
+var fakePostgresParamRegex = regexp.MustCompile(`\$(\d+)`)
+
+// fakePostgres is a minimal postgres server implementing the extended query protocol
+// each statement returns a single text column 'value', with a row containing the first parameter
+// if there is a second parameter, it is the number of rows returned (otherwise a single row is returned)
+// it records the statements which are parsed (i.e. planned) and closed (i.e. deallocated)
+type fakePostgres struct {
+	listener net.Listener
+
+	mut sync.Mutex
+	// the name and SQL of each parsed statement, in order
+	parsed []fakeStatement
+	// the name of each closed statement, in order
+	closed []string
+}
+
+type fakeStatement struct {
+	Name string
+	SQL  string
+}
+
+func newFakePostgres(t testing.TB) *fakePostgres {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	s := &fakePostgres{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// newPool returns a pool with a single connection to the fake server, using the given query exec mode
+func (s *fakePostgres) newPool(t testing.TB, execMode pgx.QueryExecMode) *pgxpool.Pool {
+	config, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://steampipe@%s/steampipe?sslmode=disable&pool_max_conns=1", s.listener.Addr().String()))
+	if err != nil {
+		t.Fatalf("failed to parse the pool config: %s", err.Error())
+	}
+	config.ConnConfig.DefaultQueryExecMode = execMode
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("failed to create the pool: %s", err.Error())
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func (s *fakePostgres) parsedStatements() []fakeStatement {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return append([]fakeStatement(nil), s.parsed...)
+}
+
+func (s *fakePostgres) closedStatements() []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return append([]string(nil), s.closed...)
+}
+
+func (s *fakePostgres) serve(conn net.Conn) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	// the statements prepared on this connection, and the parameters of the bound portal
+	statements := make(map[string]string)
+	var params [][]byte
+	var resultFormat int16
+	rowDescription := func(format int16) *pgproto3.RowDescription {
+		return &pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{{
+			Name:         []byte("value"),
+			DataTypeOID:  fakePostgresTextOID,
+			DataTypeSize: -1,
+			TypeModifier: -1,
+			Format:       format,
+		}}}
+	}
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Parse:
+			s.mut.Lock()
+			s.parsed = append(s.parsed, fakeStatement{Name: msg.Name, SQL: msg.Query})
+			s.mut.Unlock()
+			statements[msg.Name] = msg.Query
+			backend.Send(&pgproto3.ParseComplete{})
+		case *pgproto3.Describe:
+			if msg.ObjectType == 'S' {
+				paramOIDs := make([]uint32, len(fakePostgresParamRegex.FindAllString(statements[msg.Name], -1)))
+				for i := range paramOIDs {
+					paramOIDs[i] = fakePostgresTextOID
+				}
+				backend.Send(&pgproto3.ParameterDescription{ParameterOIDs: paramOIDs})
+				backend.Send(rowDescription(0))
+			} else {
+				backend.Send(rowDescription(resultFormat))
+			}
+		case *pgproto3.Bind:
+			params = msg.Parameters
+			resultFormat = 0
+			if len(msg.ResultFormatCodes) > 0 {
+				resultFormat = msg.ResultFormatCodes[0]
+			}
+			backend.Send(&pgproto3.BindComplete{})
+		case *pgproto3.Execute:
+			var value []byte
+			if len(params) > 0 {
+				value = params[0]
+			}
+			rowCount := 1
+			if len(params) > 1 {
+				rowCount, _ = strconv.Atoi(string(params[1]))
+			}
+			for i := 0; i < rowCount; i++ {
+				backend.Send(&pgproto3.DataRow{Values: [][]byte{value}})
+			}
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(fmt.Sprintf("SELECT %d", rowCount))})
+		case *pgproto3.Close:
+			if msg.ObjectType == 'S' {
+				s.mut.Lock()
+				s.closed = append(s.closed, msg.Name)
+				s.mut.Unlock()
+				delete(statements, msg.Name)
+			}
+			backend.Send(&pgproto3.CloseComplete{})
+		case *pgproto3.Sync:
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case *pgproto3.Query:
+			backend.Send(&pgproto3.EmptyQueryResponse{})
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case *pgproto3.Terminate:
+			return
+		}
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
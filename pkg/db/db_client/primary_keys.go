@@ -0,0 +1,70 @@
+package db_client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// primaryKeyColumnsSql returns the attribute numbers of the primary key columns of the given relations
+// foreign tables (i.e. plugin tables) have no primary key, so for these the get key columns of the table are used,
+// as recorded in the plugin column table for the plugin of the connection (whose name is the schema of the table)
+var primaryKeyColumnsSql = fmt.Sprintf(`SELECT i.indrelid::int8, a.attnum::int8
+FROM pg_index i
+JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+WHERE i.indisprimary AND i.indrelid::int8 = ANY($1)
+UNION
+SELECT c.oid::int8, a.attnum::int8
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN %[1]s.%[2]s conn ON conn.name = n.nspname
+JOIN %[1]s.%[3]s pc ON pc.plugin = conn.plugin AND pc.table_name = c.relname AND pc.get_config IS NOT NULL
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attname = pc.name
+WHERE c.relkind = 'f' AND c.oid::int8 = ANY($1)`, constants.InternalSchema, constants.ConnectionTable, constants.PluginColumnTable)
+
+// setPrimaryKeyColumns identifies the result columns which are part of the primary key of their source table
+// (as determined from the table oid and attribute number of the field description)
+// ctx should be the execution context of the query, so that cancelling the query also cancels this lookup
+// this must be called once the rows of the query have been read, as it executes a query on the connection
+func setPrimaryKeyColumns(ctx context.Context, conn *pgx.Conn, fieldDescriptions []pgconn.FieldDescription, cols []*queryresult.ColumnDef) error {
+	var tableOids []int64
+	for _, f := range fieldDescriptions {
+		if f.TableOID != 0 {
+			tableOids = append(tableOids, int64(f.TableOID))
+		}
+	}
+	if len(tableOids) == 0 {
+		return nil
+	}
+
+	rows, err := conn.Query(ctx, primaryKeyColumnsSql, tableOids)
+	if err != nil {
+		return err
+	}
+	type tableColumn struct {
+		tableOid  int64
+		attNumber int64
+	}
+	primaryKeys := make(map[tableColumn]struct{})
+	for rows.Next() {
+		var c tableColumn
+		if err := rows.Scan(&c.tableOid, &c.attNumber); err != nil {
+			rows.Close()
+			return err
+		}
+		primaryKeys[c] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, f := range fieldDescriptions {
+		_, isPrimaryKey := primaryKeys[tableColumn{int64(f.TableOID), int64(f.TableAttributeNumber)}]
+		cols[i].PrimaryKey = isPrimaryKey
+	}
+	return nil
+}
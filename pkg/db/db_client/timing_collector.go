@@ -0,0 +1,197 @@
+package db_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// TimingCollector fetches the timing data for a query which has completed and populates the timing result
+// the duration of the result has already been set
+type TimingCollector interface {
+	CollectTiming(ctx context.Context, session *db_common.DatabaseSession, query string, args []any, verbose bool, timingResult *queryresult.TimingResult) error
+}
+
+// newTimingCollector returns the TimingCollector for the given timing source
+// (the scan metadata collector is the default)
+func newTimingCollector(source string) TimingCollector {
+	switch source {
+	case constants.TimingSourceExplain:
+		return explainTimingCollector{}
+	case constants.ArgOff:
+		return offTimingCollector{}
+	default:
+		return scanMetadataTimingCollector{}
+	}
+}
+
+// offTimingCollector collects no timing data - only the query duration is reported
+type offTimingCollector struct{}
+
+func (offTimingCollector) CollectTiming(context.Context, *db_common.DatabaseSession, string, []any, bool, *queryresult.TimingResult) error {
+	return nil
+}
+
+// scanMetadataTimingCollector reads the scan metadata recorded by the fdw for the last query executed in the session
+type scanMetadataTimingCollector struct{}
+
+func (scanMetadataTimingCollector) CollectTiming(ctx context.Context, session *db_common.DatabaseSession, _ string, _ []any, verbose bool, timingResult *queryresult.TimingResult) error {
+	// load the timing summary
+	summary, err := loadTimingSummary(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	// only load the individual scan metadata if output is JSON or timing is verbose
+	var scans []*queryresult.ScanMetadataRow
+	if verbose {
+		scans, err = loadTimingMetadata(ctx, session)
+		if err != nil {
+			return err
+		}
+	}
+
+	// populate hydrate calls and rows fetched
+	timingResult.Initialise(summary, scans)
+	return nil
+}
+
+func loadTimingSummary(ctx context.Context, session *db_common.DatabaseSession) (*queryresult.QueryRowSummary, error) {
+	var summary = &queryresult.QueryRowSummary{}
+	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
+		query := fmt.Sprintf(`select uncached_rows_fetched,
+cached_rows_fetched,
+hydrate_calls,
+scan_count,
+connection_count from %s.%s `, constants.InternalSchema, constants.ForeignTableScanMetadataSummary)
+		rows, err := tx.Query(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		// scan into summary
+		summary, err = pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[queryresult.QueryRowSummary])
+		// no rows counts as an error
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	return summary, err
+}
+
+func loadTimingMetadata(ctx context.Context, session *db_common.DatabaseSession) ([]*queryresult.ScanMetadataRow, error) {
+	var scans []*queryresult.ScanMetadataRow
+
+	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
+		query := fmt.Sprintf(`
+select connection,
+"table",
+cache_hit,
+rows_fetched,
+hydrate_calls,
+start_time,
+duration_ms,
+columns,
+"limit",
+quals from %s.%s order by duration_ms desc`, constants.InternalSchema, constants.ForeignTableScanMetadata)
+		rows, err := tx.Query(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		scans, err = pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[queryresult.ScanMetadataRow])
+		return err
+	})
+	return scans, err
+}
+
+// explainTimingCollector re-runs the query using EXPLAIN ANALYZE and reads the timing of the foreign scans from the plan
+// NOTE: as the query is executed again, the timing may differ from the original execution (e.g. due to caching)
+// - the query is run in a transaction which is rolled back, so any side effects are discarded
+// - cache hits and hydrate calls are not available in the plan, so are not reported
+type explainTimingCollector struct{}
+
+func (explainTimingCollector) CollectTiming(ctx context.Context, session *db_common.DatabaseSession, query string, args []any, _ bool, timingResult *queryresult.TimingResult) error {
+	tx, err := session.Connection.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var plan []byte
+	if err := tx.QueryRow(ctx, "EXPLAIN (ANALYZE, VERBOSE, FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return err
+	}
+	scans, err := scansFromExplainPlan(plan, time.Now())
+	if err != nil {
+		return err
+	}
+
+	summary := queryresult.NewQueryRowSummary()
+	for _, scan := range scans {
+		summary.Update(*scan)
+	}
+	timingResult.Initialise(summary, scans)
+	return nil
+}
+
+// explainPlanNode is a node of a plan returned by EXPLAIN (ANALYZE, VERBOSE, FORMAT JSON)
+type explainPlanNode struct {
+	NodeType        string             `json:"Node Type"`
+	RelationName    string             `json:"Relation Name"`
+	Schema          string             `json:"Schema"`
+	Output          []string           `json:"Output"`
+	ActualRows      float64            `json:"Actual Rows"`
+	ActualLoops     float64            `json:"Actual Loops"`
+	ActualTotalTime float64            `json:"Actual Total Time"`
+	Plans           []*explainPlanNode `json:"Plans"`
+}
+
+// scansFromExplainPlan returns the foreign scans in the given EXPLAIN ANALYZE json plan
+// the start time of the scans is not available in the plan, so is set to the given time
+func scansFromExplainPlan(plan []byte, startTime time.Time) ([]*queryresult.ScanMetadataRow, error) {
+	var explainResult []struct {
+		Plan *explainPlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explainResult); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %s", err.Error())
+	}
+
+	var scans []*queryresult.ScanMetadataRow
+	var addScans func(node *explainPlanNode)
+	addScans = func(node *explainPlanNode) {
+		if node == nil {
+			return
+		}
+		if node.NodeType == "Foreign Scan" {
+			// the actual rows and time are averages per loop
+			loops := math.Max(node.ActualLoops, 1)
+			scans = append(scans, &queryresult.ScanMetadataRow{
+				Connection:  node.Schema,
+				Table:       node.RelationName,
+				RowsFetched: int64(math.Round(node.ActualRows * loops)),
+				StartTime:   startTime,
+				DurationMs:  int64(math.Round(node.ActualTotalTime * loops)),
+				Columns:     node.Output,
+			})
+		}
+		for _, child := range node.Plans {
+			addScans(child)
+		}
+	}
+	for _, r := range explainResult {
+		addScans(r.Plan)
+	}
+	// order by duration, slowest first, as for the scan metadata
+	sort.SliceStable(scans, func(i, j int) bool { return scans[i].DurationMs > scans[j].DurationMs })
+	return scans, nil
+}
@@ -0,0 +1,34 @@
+package db_client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScansFromExplainPlan(t *testing.T) {
+	plan := []byte(`[{"Plan": {"Node Type": "Hash Join", "Plans": [
+	{"Node Type": "Foreign Scan", "Relation Name": "aws_s3_bucket", "Schema": "aws_dev", "Output": ["name"], "Actual Rows": 10, "Actual Loops": 1, "Actual Total Time": 120.4},
+	{"Node Type": "Hash", "Plans": [
+		{"Node Type": "Foreign Scan", "Relation Name": "aws_account", "Schema": "aws_prod", "Actual Rows": 2, "Actual Loops": 3, "Actual Total Time": 500}
+	]}
+]}, "Execution Time": 1700}]`)
+
+	scans, err := scansFromExplainPlan(plan, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scans) != 2 {
+		t.Fatalf("expected 2 scans, got %d", len(scans))
+	}
+	// scans are ordered slowest first
+	if scans[0].Connection != "aws_prod" || scans[0].Table != "aws_account" || scans[0].RowsFetched != 6 || scans[0].DurationMs != 1500 {
+		t.Errorf("unexpected first scan: %+v", scans[0])
+	}
+	if scans[1].Connection != "aws_dev" || scans[1].Table != "aws_s3_bucket" || scans[1].RowsFetched != 10 || scans[1].DurationMs != 120 {
+		t.Errorf("unexpected second scan: %+v", scans[1])
+	}
+
+	if _, err := scansFromExplainPlan([]byte("not json"), time.Now()); err == nil {
+		t.Error("expected an error for an invalid plan")
+	}
+}
@@ -0,0 +1,32 @@
+package db_common
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/turbot/steampipe/pkg/contexthelpers"
+)
+
+var contextKeyCacheBypass = contexthelpers.ContextKey("cache_bypass")
+
+// matches a no_cache optimizer hint comment, e.g. /*+ no_cache */
+var noCacheHintRegex = regexp.MustCompile(`(?is)/\*\+[^*]*\bno_cache\b[^*]*\*/`)
+
+// HasNoCacheHint returns whether the query contains a no_cache hint comment, i.e. /*+ no_cache */
+// a query with this hint bypasses the cache
+func HasNoCacheHint(query string) bool {
+	return noCacheHintRegex.MatchString(query)
+}
+
+// WithCacheBypass returns a context which requests that queries executed with it bypass the cache
+// this is used to bypass the cache for a single user query (see the '.cache bypass-once' meta-command),
+// without affecting any other queries the client executes
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyCacheBypass, true)
+}
+
+// IsCacheBypassRequested returns whether the context requests that queries bypass the cache
+func IsCacheBypassRequested(ctx context.Context) bool {
+	bypass, _ := ctx.Value(contextKeyCacheBypass).(bool)
+	return bypass
+}
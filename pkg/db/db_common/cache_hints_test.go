@@ -0,0 +1,33 @@
+package db_common
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHasNoCacheHint(t *testing.T) {
+	tests := map[string]bool{
+		"/*+ no_cache */ select * from aws_s3_bucket":          true,
+		"select /*+NO_CACHE*/ * from aws_s3_bucket":            true,
+		"select /*+ parallel no_cache */ * from aws_s3_bucket": true,
+		"select * from aws_s3_bucket":                          false,
+		"/* no_cache */ select * from aws_s3_bucket":           false,
+		"select 'no_cache' from aws_s3_bucket":                 false,
+		"/*+ no_caches */ select * from aws_s3_bucket":         false,
+	}
+	for query, expected := range tests {
+		if got := HasNoCacheHint(query); got != expected {
+			t.Errorf("HasNoCacheHint(%s): expected %v, got %v", query, expected, got)
+		}
+	}
+}
+
+func TestWithCacheBypass(t *testing.T) {
+	ctx := context.Background()
+	if IsCacheBypassRequested(ctx) {
+		t.Errorf("expected no cache bypass for a context without a bypass request")
+	}
+	if !IsCacheBypassRequested(WithCacheBypass(ctx)) {
+		t.Errorf("expected a cache bypass for a context with a bypass request")
+	}
+}
@@ -25,6 +25,18 @@ type Client interface {
 
 	ExecuteSyncInSession(context.Context, *DatabaseSession, string, ...any) (*queryresult.SyncQueryResult, error)
 	ExecuteInSession(context.Context, *DatabaseSession, func(), string, ...any) (*queryresult.Result, error)
+	// prepare a named statement in a session, which may be executed repeatedly without being planned again
+	// (the statement is dropped when the session is closed)
+	Prepare(context.Context, *DatabaseSession, string, string) error
+	ExecutePrepared(context.Context, *DatabaseSession, func(), string, ...any) (*queryresult.Result, error)
+	// stream the rows of a query to a callback, without collecting them
+	StreamQuery(context.Context, string, queryresult.RowCallback, ...any) error
+
+	// cancel the query running in the session with the given backend pid, without terminating the session
+	// (only the sessions of this client may be cancelled)
+	CancelQuery(context.Context, uint32) error
+	// the backend pids of the sessions of this client
+	SessionBackendPids() []uint32
 
 	ResetPools(context.Context)
 	GetSchemaFromDB(context.Context) (*SchemaMetadata, error)
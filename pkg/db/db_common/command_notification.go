@@ -0,0 +1,54 @@
+package db_common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// SendPostgresCommandNotification sends a postgres notification which requests the service to run a command
+// the notification is recorded in the command request table, which only members of the admin role may insert into,
+// so the service can verify the request with VerifyPostgresCommandNotification
+func SendPostgresCommandNotification(ctx context.Context, conn *pgx.Conn, notification any) error {
+	notificationBytes, err := json.Marshal(notification)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "error marshalling Postgres notification")
+	}
+
+	log.Printf("[TRACE] Send command notification")
+
+	sql := fmt.Sprintf(`WITH request AS (INSERT INTO %s.%s (payload) VALUES ($1) RETURNING payload)
+SELECT pg_notify('%s', payload) FROM request`, constants.InternalSchema, constants.CommandRequestTable, constants.PostgresNotificationChannel)
+	if _, err = conn.Exec(ctx, sql, string(notificationBytes)); err != nil {
+		if IsPermissionDeniedError(err) {
+			return sperr.New("permission denied - only members of the %s role may send commands to the service", constants.DatabaseAdminRole)
+		}
+		return sperr.WrapWithMessage(err, "error sending Postgres notification")
+	}
+	return nil
+}
+
+// VerifyPostgresCommandNotification returns whether a command notification was sent by SendPostgresCommandNotification,
+// i.e. by a member of the admin role - the request is removed, so each request is only run once
+func VerifyPostgresCommandNotification(ctx context.Context, conn *pgx.Conn, payload string) (bool, error) {
+	sql := fmt.Sprintf(`DELETE FROM %s.%s WHERE payload = $1 RETURNING requested_by`, constants.InternalSchema, constants.CommandRequestTable)
+	rows, err := conn.Query(ctx, sql, payload)
+	if err != nil {
+		return false, err
+	}
+	requestedBy, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return false, err
+	}
+	if len(requestedBy) == 0 {
+		return false, nil
+	}
+	log.Printf("[INFO] command requested by %s", strings.Join(requestedBy, ","))
+	return true, nil
+}
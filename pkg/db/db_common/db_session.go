@@ -1,6 +1,8 @@
 package db_common
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -11,18 +13,86 @@ import (
 // the purpose is to be able
 //   - to store the current search path of the connection without having to make a database round-trip
 //   - To store the last scan_metadata id used on this connection
+//   - to store the named statements prepared on the connection, so they can be dropped when the session is closed
 type DatabaseSession struct {
 	BackendPid uint32   `json:"backend_pid"`
 	SearchPath []string `json:"-"`
 
 	// this gets rewritten, since the database/sql gives back a new instance everytime
 	Connection *pgxpool.Conn `json:"-"`
+
+	// map of the SQL of the named statements prepared in this session, keyed by name
+	preparedStatements map[string]string
 }
 
 func NewDBSession(backendPid uint32) *DatabaseSession {
 	return &DatabaseSession{
-		BackendPid: backendPid,
+		BackendPid:         backendPid,
+		preparedStatements: make(map[string]string),
+	}
+}
+
+// Prepare prepares the query as a named statement on the session connection
+// if a statement with this name has already been prepared for a different query, it is replaced
+func (s *DatabaseSession) Prepare(ctx context.Context, name, query string) error {
+	if name == "" {
+		return fmt.Errorf("a prepared statement name must be provided")
+	}
+	if s.Connection == nil {
+		return fmt.Errorf("the session has no database connection")
+	}
+	if s.preparedStatements == nil {
+		s.preparedStatements = make(map[string]string)
+	}
+	conn := s.Connection.Conn()
+	if existing, ok := s.preparedStatements[name]; ok {
+		if existing == query {
+			return nil
+		}
+		// postgres will not prepare a statement with the name of an existing statement
+		if err := conn.Deallocate(ctx, preparedStatementName(name)); err != nil {
+			return err
+		}
+		delete(s.preparedStatements, name)
+	}
+	if _, err := conn.Prepare(ctx, preparedStatementName(name), query); err != nil {
+		return err
+	}
+	s.preparedStatements[name] = query
+	return nil
+}
+
+// PreparedQuery returns the SQL of the named statement, and whether it has been prepared in this session
+func (s *DatabaseSession) PreparedQuery(name string) (string, bool) {
+	query, ok := s.preparedStatements[name]
+	return query, ok
+}
+
+// PreparedStatementName returns the name of the statement the query was prepared as in this session (if any)
+// executing the statement by name uses the plan of the prepared statement
+func (s *DatabaseSession) PreparedStatementName(query string) (string, bool) {
+	for name, preparedQuery := range s.preparedStatements {
+		if preparedQuery == query {
+			return preparedStatementName(name), true
+		}
+	}
+	return "", false
+}
+
+// deallocatePreparedStatements drops the named statements prepared in this session
+// - the connection is returned to the pool, and must not keep statements which other sessions do not know about
+func (s *DatabaseSession) deallocatePreparedStatements() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn := s.Connection.Conn()
+	for name := range s.preparedStatements {
+		if err := conn.Deallocate(ctx, preparedStatementName(name)); err != nil {
+			return err
+		}
+		delete(s.preparedStatements, name)
 	}
+	return nil
 }
 
 func (s *DatabaseSession) Close(waitForCleanup bool) {
@@ -36,8 +106,20 @@ func (s *DatabaseSession) Close(waitForCleanup bool) {
 				log.Printf("[TRACE] DatabaseSession.Close connection cleanup complete")
 			}
 		}
+		if err := s.deallocatePreparedStatements(); err != nil {
+			// the statements could not be dropped - close the connection so the pool discards it
+			log.Printf("[WARN] DatabaseSession.Close failed to deallocate prepared statements: %s", err.Error())
+			_ = s.Connection.Conn().Close(context.Background())
+			s.preparedStatements = make(map[string]string)
+		}
 		s.Connection.Release()
 	}
 	s.Connection = nil
 
 }
+
+// preparedStatementName returns the name a named statement is prepared as
+// the name is prefixed so it cannot clash with the statements prepared by the pgx statement cache
+func preparedStatementName(name string) string {
+	return "steampipe_prepared_" + name
+}
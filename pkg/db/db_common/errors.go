@@ -36,3 +36,16 @@ func GetMissingSchemaFromIsRelationNotFoundError(err error) (string, string, boo
 	}
 	return "", "", true
 }
+
+// IsPermissionDeniedError returns whether the error is a postgres insufficient privilege error
+func IsPermissionDeniedError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "42501"
+}
+
+// IsAuthenticationError returns whether the error is a postgres authentication failure
+// (i.e. the password is invalid, or the user may not connect)
+func IsAuthenticationError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "28P01" || pgErr.Code == "28000")
+}
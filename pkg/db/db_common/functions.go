@@ -58,6 +58,55 @@ end;
 begin
 	INSERT INTO steampipe_internal.steampipe_settings("name","value") VALUES ('cache_ttl',duration);
 end;
+`,
+	},
+	{
+		// trigger function which rejects inserts of privileged commands by roles which are not members of steampipe_admin
+		// the first trigger argument is the name of the command column, the remaining arguments are the privileged commands
+		Name:     constants.FunctionCheckCommandPermission,
+		Params:   map[string]string{},
+		Returns:  "trigger",
+		Language: "plpgsql",
+		Body: `
+declare
+	command text;
+begin
+	command = to_jsonb(NEW) ->> TG_ARGV[0];
+	IF command = ANY(TG_ARGV[1:]) AND NOT pg_has_role(current_user, 'steampipe_admin', 'member') THEN
+		RAISE EXCEPTION 'permission denied for %: only members of the steampipe_admin role may run this command', command USING ERRCODE = 'insufficient_privilege';
+	END IF;
+	RETURN NEW;
+end;
+`,
+	},
+	{
+		// event trigger function which grants service users access to their connection schemas when the schemas
+		// are created - connection schemas are dropped and recreated when connections are refreshed, which drops the grants
+		// (the schemas are only created by the root user, so the default privileges apply to the tables it imports)
+		Name:     constants.FunctionGrantServiceUserAccess,
+		Params:   map[string]string{},
+		Returns:  "event_trigger",
+		Language: "plpgsql",
+		Body: `
+declare
+	schema_name text;
+	user_name text;
+begin
+	IF NOT (SELECT rolsuper FROM pg_roles WHERE rolname = current_user) THEN
+		RETURN;
+	END IF;
+	FOR schema_name IN SELECT n.nspname FROM pg_event_trigger_ddl_commands() c JOIN pg_namespace n ON n.oid = c.objid WHERE c.command_tag = 'CREATE SCHEMA' LOOP
+		FOR user_name IN SELECT u.name FROM steampipe_internal.steampipe_service_user u WHERE schema_name = ANY(u.connections) LOOP
+			-- the role of the user may have been dropped outside steampipe - this must not fail the creation of the schema
+			BEGIN
+				EXECUTE format('GRANT USAGE ON SCHEMA %I TO %I', schema_name, user_name);
+				EXECUTE format('ALTER DEFAULT PRIVILEGES IN SCHEMA %I GRANT SELECT ON TABLES TO %I', schema_name, user_name);
+			EXCEPTION WHEN undefined_object THEN
+				RAISE WARNING 'service user % does not exist - skipping grant on schema %', user_name, schema_name;
+			END;
+		END LOOP;
+	END LOOP;
+end;
 `,
 	},
 }
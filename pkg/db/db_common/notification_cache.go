@@ -19,8 +19,15 @@ type NotificationListener struct {
 	conn          *pgx.Conn
 
 	onNotification func(*pgconn.Notification)
-	mut            sync.Mutex
-	cancel         context.CancelFunc
+	// observers are called for every notification, before it is passed to (or cached for) onNotification
+	observers []func(*pgconn.Notification)
+	// errorObservers are called if the listener stops because of an error waiting for notifications
+	// (e.g. the connection was dropped) - no further notifications will be received
+	errorObservers []func(error)
+	// set once Stop is called, so closing the connection is not reported as an error
+	stopped bool
+	mut     sync.Mutex
+	cancel  context.CancelFunc
 }
 
 func NewNotificationListener(ctx context.Context, conn *pgx.Conn) (*NotificationListener, error) {
@@ -50,6 +57,9 @@ func NewNotificationListener(ctx context.Context, conn *pgx.Conn) (*Notification
 }
 
 func (c *NotificationListener) Stop(ctx context.Context) {
+	c.mut.Lock()
+	c.stopped = true
+	c.mut.Unlock()
 	c.conn.Close(ctx)
 	// stop the listener goroutine
 	c.cancel()
@@ -68,6 +78,24 @@ func (c *NotificationListener) RegisterListener(onNotification func(*pgconn.Noti
 	c.notifications = nil
 }
 
+// AddObserver adds a function which is called for every notification received
+// unlike the listener registered with RegisterListener, observers are not sent notifications received before they were added
+func (c *NotificationListener) AddObserver(observer func(*pgconn.Notification)) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.observers = append(c.observers, observer)
+}
+
+// AddErrorObserver adds a function which is called if the listener stops because of an error,
+// so that anything relying on notifications can fall back to another mechanism
+func (c *NotificationListener) AddErrorObserver(observer func(error)) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.errorObservers = append(c.errorObservers, observer)
+}
+
 func (c *NotificationListener) listenToPgNotificationsAsync(ctx context.Context) {
 	log.Printf("[INFO] notificationListener listenToPgNotificationsAsync")
 
@@ -77,12 +105,22 @@ func (c *NotificationListener) listenToPgNotificationsAsync(ctx context.Context)
 			notification, err := c.conn.WaitForNotification(ctx)
 			if err != nil && !error_helpers.IsContextCancelledError(err) {
 				log.Printf("[WARN] Error waiting for notification: %s", err)
+				c.mut.Lock()
+				if !c.stopped {
+					for _, observer := range c.errorObservers {
+						observer(err)
+					}
+				}
+				c.mut.Unlock()
 				return
 			}
 
 			if notification != nil {
 				log.Printf("[INFO] got notification")
 				c.mut.Lock()
+				for _, observer := range c.observers {
+					observer(notification)
+				}
 				// if we have a callback, call it
 				if c.onNotification != nil {
 					log.Printf("[INFO] call notification handler")
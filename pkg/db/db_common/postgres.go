@@ -17,8 +17,32 @@ func PgEscapeName(name string) string {
 // PgEscapeString escapes strings which are to be inserted
 // use a custom escape tag to avoid chance of clash with the escaped text
 // https://medium.com/@lnishada/postgres-dollar-quoting-6d23e4f186ec
+// if the text contains the tag (which would end the quoted string early), a numbered tag is used instead
 func PgEscapeString(str string) string {
-	return fmt.Sprintf(`$steampipe_escape$%s$steampipe_escape$`, str)
+	tag := "$steampipe_escape$"
+	for i := 1; !isSafeDollarQuoteTag(str, tag); i++ {
+		tag = fmt.Sprintf("$steampipe_escape_%d$", i)
+	}
+	return fmt.Sprintf(`%s%s%s`, tag, str, tag)
+}
+
+// isSafeDollarQuoteTag returns whether the first occurrence of tag after the opening tag is the closing tag,
+// i.e. the tag does not occur in the text, nor is formed by the end of the text and the closing tag
+func isSafeDollarQuoteTag(str, tag string) bool {
+	return strings.Index(str+tag, tag) == len(str)
+}
+
+// GetDropGeneratedSchemaQuery returns the sql to drop a schema generated by steampipe, which is identified by its comment
+// a schema with the same name which was not generated by steampipe (e.g. the schema of a connection whose name
+// happens to end with the suffix of a generated schema) is left untouched
+func GetDropGeneratedSchemaQuery(schemaName, comment string) string {
+	return fmt.Sprintf(`do $$
+begin
+  if obj_description(to_regnamespace(%s), 'pg_namespace') = %s then
+    execute format('drop schema %%I cascade', %s);
+  end if;
+end $$;
+`, PgEscapeString(PgEscapeName(schemaName)), PgEscapeString(comment), PgEscapeString(schemaName))
 }
 
 // PgEscapeSearchPath applies postgres escaping to search path and remove whitespace
@@ -0,0 +1,21 @@
+package db_common
+
+import "testing"
+
+func TestPgEscapeString(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected string
+	}{
+		{str: "secret", expected: `$steampipe_escape$secret$steampipe_escape$`},
+		{str: "", expected: `$steampipe_escape$$steampipe_escape$`},
+		{str: "a$steampipe_escape$; drop table t; --", expected: `$steampipe_escape_1$a$steampipe_escape$; drop table t; --$steampipe_escape_1$`},
+		{str: "a$steampipe_escape", expected: `$steampipe_escape_1$a$steampipe_escape$steampipe_escape_1$`},
+		{str: "$steampipe_escape$$steampipe_escape_1$", expected: `$steampipe_escape_2$$steampipe_escape$$steampipe_escape_1$$steampipe_escape_2$`},
+	}
+	for _, test := range tests {
+		if res := PgEscapeString(test.str); res != test.expected {
+			t.Errorf("PgEscapeString(%q): expected %s, got %s", test.str, test.expected, res)
+		}
+	}
+}
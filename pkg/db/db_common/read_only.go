@@ -0,0 +1,143 @@
+package db_common
+
+import (
+	"regexp"
+	"strings"
+)
+
+// the first keywords of statements which write data, modify the schema or change permissions
+var writeStatementKeywords = map[string]struct{}{
+	"insert":   {},
+	"update":   {},
+	"delete":   {},
+	"merge":    {},
+	"copy":     {},
+	"create":   {},
+	"alter":    {},
+	"drop":     {},
+	"truncate": {},
+	"comment":  {},
+	"grant":    {},
+	"revoke":   {},
+	"refresh":  {},
+	"reindex":  {},
+	"vacuum":   {},
+	"cluster":  {},
+	"lock":     {},
+	"import":   {},
+	"security": {},
+	"do":       {},
+	"call":     {},
+}
+
+// the keywords of the statements which may be nested in a WITH, EXPLAIN or PREPARE statement and which write data
+var nestedWriteKeywords = []string{"insert", "update", "delete", "merge", "create"}
+
+var (
+	keywordRegex = regexp.MustCompile(`[a-z_]+`)
+	// matches a dollar quote tag, e.g. $$ or $body$
+	dollarQuoteRegex = regexp.MustCompile(`^\$[a-zA-Z_]*\$`)
+)
+
+// IsWriteStatement returns whether the query contains a statement which writes data, modifies the schema
+// or makes the transaction writable
+// NOTE: this is a best effort check of the statement keywords, used by read only clients to reject statements
+// before they are sent to the database - the database enforces the read only transaction for anything it misses
+func IsWriteStatement(query string) bool {
+	// reject any attempt to change the read only setting, wherever it appears (e.g. in a call to set_config)
+	if strings.Contains(strings.ToLower(query), "transaction_read_only") {
+		return true
+	}
+
+	for _, statement := range strings.Split(stripCommentsAndLiterals(query), ";") {
+		keywords := keywordRegex.FindAllString(strings.ToLower(statement), -1)
+		if len(keywords) == 0 {
+			continue
+		}
+		if _, ok := writeStatementKeywords[keywords[0]]; ok {
+			return true
+		}
+		switch keywords[0] {
+		case "with", "explain", "prepare":
+			for _, k := range keywords[1:] {
+				if isNestedWriteKeyword(k) {
+					return true
+				}
+			}
+		case "begin", "start", "set":
+			// e.g. BEGIN READ WRITE, SET TRANSACTION READ WRITE
+			if strings.Contains(strings.Join(keywords, " "), "read write") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNestedWriteKeyword(keyword string) bool {
+	for _, k := range nestedWriteKeywords {
+		if keyword == k {
+			return true
+		}
+	}
+	return false
+}
+
+// stripCommentsAndLiterals removes comments, string literals and quoted identifiers from the query
+// so that the remaining text only contains keywords, unquoted identifiers and punctuation
+func stripCommentsAndLiterals(query string) string {
+	var sb strings.Builder
+	for i := 0; i < len(query); {
+		rest := query[i:]
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end == -1 {
+				return sb.String()
+			}
+			i += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end == -1 {
+				return sb.String()
+			}
+			i += end + 4
+			sb.WriteByte(' ')
+		case rest[0] == '\'' || rest[0] == '"':
+			end := quotedLength(rest, rest[0])
+			if end == -1 {
+				return sb.String()
+			}
+			i += end
+			sb.WriteByte(' ')
+		case dollarQuoteRegex.MatchString(rest):
+			tag := dollarQuoteRegex.FindString(rest)
+			end := strings.Index(rest[len(tag):], tag)
+			if end == -1 {
+				return sb.String()
+			}
+			i += len(tag) + end + len(tag)
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(rest[0])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// quotedLength returns the length of the quoted text at the start of s, including the quotes,
+// or -1 if the quote is not terminated - a doubled quote character is an escaped quote
+func quotedLength(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return -1
+}
@@ -0,0 +1,38 @@
+package db_common
+
+import "testing"
+
+func TestIsWriteStatement(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  bool
+	}{
+		"select":                   {query: "select * from aws_s3_bucket", want: false},
+		"select in parentheses":    {query: "(select 1) union (select 2)", want: false},
+		"with select":              {query: "with b as (select * from aws_s3_bucket) select * from b", want: false},
+		"show":                     {query: "show search_path", want: false},
+		"set":                      {query: "set statement_timeout = 1000", want: false},
+		"keyword in literal":       {query: "select 'drop table foo; insert'", want: false},
+		"keyword in identifier":    {query: `select "delete" from foo`, want: false},
+		"keyword in comment":       {query: "-- drop table foo\nselect 1", want: false},
+		"keyword in block comment": {query: "/* insert */ select 1", want: false},
+		"keyword in dollar quote":  {query: "select $$ delete $$", want: false},
+		"insert":                   {query: "insert into foo values (1)", want: true},
+		"upper case":               {query: "DROP TABLE foo", want: true},
+		"leading comment":          {query: "/* comment */ create table foo (id int)", want: true},
+		"second statement":         {query: "select 1; truncate foo", want: true},
+		"with delete":              {query: "with d as (delete from foo returning *) select * from d", want: true},
+		"explain analyze update":   {query: "explain analyze update foo set id = 1", want: true},
+		"refresh":                  {query: "refresh materialized view foo", want: true},
+		"begin read write":         {query: "begin read write", want: true},
+		"set read only":            {query: "set default_transaction_read_only = off", want: true},
+		"set_config read only":     {query: "select set_config('transaction_read_only', 'off', false)", want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsWriteStatement(test.query); got != test.want {
+				t.Errorf("IsWriteStatement(%q) = %v, want %v", test.query, got, test.want)
+			}
+		})
+	}
+}
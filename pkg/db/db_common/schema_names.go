@@ -0,0 +1,76 @@
+package db_common
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"golang.org/x/text/unicode/norm"
+)
+
+// the maximum length of a postgres identifier, in bytes
+const maxIdentifierLength = 63
+
+// SchemaNameForConnection returns the name of the schema for the connection with the given name
+//
+// nameCase is one of the SchemaNameCase modes and invalidChars one of the SchemaNameInvalidChars modes
+// the mapping is deterministic, so a connection is always given the same schema
+func SchemaNameForConnection(connectionName, nameCase, invalidChars string) (string, error) {
+	name := connectionName
+	if nameCase == constants.SchemaNameCaseLower {
+		name = strings.ToLower(name)
+	}
+
+	switch invalidChars {
+	case constants.SchemaNameInvalidCharsTransliterate:
+		name = TransliterateSchemaName(name)
+	case constants.SchemaNameInvalidCharsQuote:
+		// any characters are valid in a quoted identifier
+		if strings.TrimSpace(name) == "" {
+			return "", errors.New("Schema name cannot be blank.")
+		}
+		if len(name) > maxIdentifierLength {
+			return "", fmt.Errorf("Schema name length should not exceed %d characters.", maxIdentifierLength)
+		}
+		if strings.HasPrefix(name, "pg_") {
+			return "", errors.New("Schema name should not start with `pg_`")
+		}
+	default:
+		if ok, message := IsSchemaNameValid(name); !ok {
+			return "", errors.New(message)
+		}
+	}
+	return name, nil
+}
+
+// TransliterateSchemaName converts a name into a valid unquoted identifier (preserving the case of the name)
+// accented characters are replaced by the unaccented character and any other invalid characters by an underscore,
+// e.g. 'Prod-Café' becomes 'Prod_Cafe'
+// names which are too long are truncated and given a suffix derived from the full name, so they remain distinct
+func TransliterateSchemaName(name string) string {
+	var sb strings.Builder
+	// decompose accented characters so the combining accents can be dropped
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))):
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	res := sb.String()
+	// names must not start with a digit or 'pg_'
+	if res == "" || unicode.IsDigit(rune(res[0])) || strings.HasPrefix(res, "pg_") {
+		res = "_" + res
+	}
+	if len(res) > maxIdentifierLength {
+		suffix := fmt.Sprintf("_%x", sha256.Sum256([]byte(name)))[:9]
+		res = res[:maxIdentifierLength-len(suffix)] + suffix
+	}
+	return res
+}
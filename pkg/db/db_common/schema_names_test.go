@@ -0,0 +1,52 @@
+package db_common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestSchemaNameForConnection(t *testing.T) {
+	longName := strings.Repeat("a", 70)
+	tests := []struct {
+		name         string
+		nameCase     string
+		invalidChars string
+		expected     string
+		wantErr      bool
+	}{
+		{name: "aws_prod", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsError, expected: "aws_prod"},
+		{name: "AWS_Prod", nameCase: constants.SchemaNameCaseLower, invalidChars: constants.SchemaNameInvalidCharsError, expected: "aws_prod"},
+		{name: "aws-prod", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsError, wantErr: true},
+		{name: "aws-prod", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsQuote, expected: "aws-prod"},
+		{name: "pg_aws", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsQuote, wantErr: true},
+		{name: "Prod-Café", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsTransliterate, expected: "Prod_Cafe"},
+		{name: "Prod-Café", nameCase: constants.SchemaNameCaseLower, invalidChars: constants.SchemaNameInvalidCharsTransliterate, expected: "prod_cafe"},
+		{name: "123 account", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsTransliterate, expected: "_123_account"},
+		{name: "pg_aws", nameCase: constants.SchemaNameCasePreserve, invalidChars: constants.SchemaNameInvalidCharsTransliterate, expected: "_pg_aws"},
+	}
+	for _, test := range tests {
+		res, err := SchemaNameForConnection(test.name, test.nameCase, test.invalidChars)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s (%s, %s): expected an error", test.name, test.nameCase, test.invalidChars)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s (%s, %s): unexpected error: %s", test.name, test.nameCase, test.invalidChars, err.Error())
+			continue
+		}
+		if res != test.expected {
+			t.Errorf("%s (%s, %s): expected %s, got %s", test.name, test.nameCase, test.invalidChars, test.expected, res)
+		}
+	}
+
+	// long names are truncated, with a suffix to keep them distinct
+	res1 := TransliterateSchemaName(longName + "1")
+	res2 := TransliterateSchemaName(longName + "2")
+	if len(res1) != maxIdentifierLength || res1 == res2 {
+		t.Errorf("expected distinct names of length %d, got %s and %s", maxIdentifierLength, res1, res2)
+	}
+}
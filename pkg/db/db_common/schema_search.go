@@ -0,0 +1,69 @@
+package db_common
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// SchemaSearchMatch is a table whose name, description or columns match a search term
+// tables of the same name in multiple connections (i.e. connections using the same plugin) are combined
+type SchemaSearchMatch struct {
+	Table       string   `json:"table"`
+	Description string   `json:"description,omitempty"`
+	Connections []string `json:"connections"`
+	// true if the table name or description matched the search term
+	TableMatched bool `json:"table_matched"`
+	// the columns whose name or description matched the search term
+	Columns []string `json:"columns,omitempty"`
+}
+
+// Search returns the tables whose name or description, or whose column names or descriptions,
+// contain the search term (case insensitive)
+// matches are sorted with table matches first, then by table name
+func (m *SchemaMetadata) Search(term string) []*SchemaSearchMatch {
+	term = strings.ToLower(term)
+	contains := func(s string) bool { return strings.Contains(strings.ToLower(s), term) }
+
+	matchMap := make(map[string]*SchemaSearchMatch)
+	for schemaName, tables := range m.Schemas {
+		if schemaName == m.TemporarySchemaName {
+			continue
+		}
+		for tableName, table := range tables {
+			// if we have already matched this table in another connection, just add the connection
+			if match, ok := matchMap[tableName]; ok {
+				match.Connections = append(match.Connections, schemaName)
+				continue
+			}
+			match := &SchemaSearchMatch{
+				Table:        tableName,
+				Description:  table.Description,
+				Connections:  []string{schemaName},
+				TableMatched: contains(tableName) || contains(table.Description),
+			}
+			for columnName, column := range table.Columns {
+				if contains(columnName) || contains(column.Description) {
+					match.Columns = append(match.Columns, columnName)
+				}
+			}
+			if match.TableMatched || len(match.Columns) > 0 {
+				sort.Strings(match.Columns)
+				matchMap[tableName] = match
+			}
+		}
+	}
+
+	res := maps.Values(matchMap)
+	for _, match := range res {
+		sort.Strings(match.Connections)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].TableMatched != res[j].TableMatched {
+			return res[i].TableMatched
+		}
+		return res[i].Table < res[j].Table
+	})
+	return res
+}
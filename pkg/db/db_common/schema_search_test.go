@@ -0,0 +1,40 @@
+package db_common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaMetadataSearch(t *testing.T) {
+	bucket := TableSchema{
+		Name:        "aws_s3_bucket",
+		Description: "An S3 bucket",
+		Columns: map[string]ColumnSchema{
+			"name":                                 {Name: "name", Description: "The bucket name"},
+			"server_side_encryption_configuration": {Name: "server_side_encryption_configuration"},
+			"versioning_enabled":                   {Name: "versioning_enabled", Description: "Is Encryption... no, versioning enabled"},
+		},
+	}
+	m := &SchemaMetadata{
+		Schemas: map[string]map[string]TableSchema{
+			"aws_prod": {"aws_s3_bucket": bucket},
+			"aws_dev":  {"aws_s3_bucket": bucket},
+			"aws_kms": {
+				"aws_kms_key": {Name: "aws_kms_key", Description: "A KMS encryption key"},
+			},
+			"pg_temp_1": {"aws_kms_key": {Name: "aws_kms_key", Description: "A KMS encryption key"}},
+		},
+		TemporarySchemaName: "pg_temp_1",
+	}
+
+	expected := []*SchemaSearchMatch{
+		{Table: "aws_kms_key", Description: "A KMS encryption key", Connections: []string{"aws_kms"}, TableMatched: true},
+		{Table: "aws_s3_bucket", Description: "An S3 bucket", Connections: []string{"aws_dev", "aws_prod"}, Columns: []string{"server_side_encryption_configuration", "versioning_enabled"}},
+	}
+	if actual := m.Search("ENCRYPTION"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected search results: %+v", actual)
+	}
+	if actual := m.Search("no such thing"); len(actual) != 0 {
+		t.Errorf("expected no results, got %+v", actual)
+	}
+}
@@ -11,4 +11,6 @@ type ServerSettings struct {
 	CacheMaxTtl      int       `db:"cache_max_ttl"`
 	CacheMaxSizeMb   int       `db:"cache_max_size_mb"`
 	CacheEnabled     bool      `db:"cache_enabled"`
+	// the maximum duration (in seconds) of a statement executed by a non-superuser session - 0 means no limit
+	MaxQueryDuration int `db:"max_query_duration"`
 }
@@ -24,7 +24,9 @@ func ExecuteSystemClientCall(ctx context.Context, conn *pgx.Conn, executor Syste
 		return sperr.New("ExecuteSystemClientCall called with appname other than client: %s", conn.Config().RuntimeParams[constants.RuntimeParamsKeyApplicationName])
 	}
 
-	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) (e error) {
+	// system calls (e.g. creating the introspection tables) may write, so use a read write transaction
+	// - this overrides the default transaction mode of the sessions of a read only client
+	return pgx.BeginTxFunc(ctx, conn, pgx.TxOptions{AccessMode: pgx.ReadWrite}, func(tx pgx.Tx) (e error) {
 		// if the appName is the ClientAppName, we need to set it to ClientSystemAppName
 		// and then revert when done
 		_, err := tx.Exec(ctx, fmt.Sprintf("SET application_name TO '%s'", runtime.ClientSystemConnectionAppName))
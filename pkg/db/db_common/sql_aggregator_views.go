@@ -0,0 +1,111 @@
+package db_common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// ConnectionColumnName is the name of the column added to aggregator views which contains the name of the
+// child connection which returned the row
+const ConnectionColumnName = "_ctx_connection"
+
+// the name of the context column populated by the FDW for every plugin table
+const ctxColumnName = "_ctx"
+
+// GetAggregatorViewSchemaName returns the name of the schema which contains the views for an aggregator connection
+func GetAggregatorViewSchemaName(connectionName string) string {
+	return fmt.Sprintf("%s_view", connectionName)
+}
+
+// getAggregatorViewSchemaComment returns the comment of the view schema for an aggregator connection
+// this identifies the schema as generated, so it is only ever dropped if it was created by steampipe
+func getAggregatorViewSchemaComment(connectionName string) string {
+	return fmt.Sprintf("steampipe aggregator views: %s", connectionName)
+}
+
+// GetAggregatorViewsQuery returns the sql to (re)create the view schema for an aggregator connection
+//
+// a view is created for every table in the aggregator schema
+//   - if addConnectionColumn is set, the view has an additional _ctx_connection column containing the name of the
+//     child connection which returned the row
+//   - if dedupKeys are specified, for any table which has ALL the key columns, rows are de-duplicated on those columns
+//     (this is used for global resources which are returned once for every child connection)
+//     the row returned from the first child connection (in alphabetical order) is used
+//
+// tableColumns is a map of table name to the column names of that table
+func GetAggregatorViewsQuery(connectionName string, tableColumns map[string][]string, addConnectionColumn bool, dedupKeys []string) string {
+	viewSchemaName := PgEscapeName(GetAggregatorViewSchemaName(connectionName))
+	schemaName := PgEscapeName(connectionName)
+
+	var statements strings.Builder
+	statements.WriteString(GetDeleteAggregatorViewsQuery(connectionName))
+	statements.WriteString(fmt.Sprintf("create schema %s;\n", viewSchemaName))
+	statements.WriteString(fmt.Sprintf("comment on schema %s is %s;\n", viewSchemaName, PgEscapeString(getAggregatorViewSchemaComment(connectionName))))
+	statements.WriteString(fmt.Sprintf("grant usage on schema %s to steampipe_users;\n", viewSchemaName))
+	statements.WriteString(fmt.Sprintf("alter default privileges in schema %s grant select on tables to steampipe_users;\n", viewSchemaName))
+
+	// sort table names so the query is deterministic
+	tables := maps.Keys(tableColumns)
+	sort.Strings(tables)
+	for _, table := range tables {
+		columns := tableColumns[table]
+		statements.WriteString(fmt.Sprintf("create view %s.%s as %s;\n",
+			viewSchemaName,
+			PgEscapeName(table),
+			getAggregatorViewSelect(schemaName, table, columns, addConnectionColumn, dedupKeys)))
+	}
+	statements.WriteString(fmt.Sprintf("grant select on all tables in schema %s to steampipe_users;\n", viewSchemaName))
+
+	return statements.String()
+}
+
+// GetDeleteAggregatorViewsQuery returns the sql to drop the view schema for an aggregator connection
+// NOTE: the schema is only dropped if it was created by steampipe - a connection may be named '<connection>_view'
+func GetDeleteAggregatorViewsQuery(connectionName string) string {
+	return GetDropGeneratedSchemaQuery(GetAggregatorViewSchemaName(connectionName), getAggregatorViewSchemaComment(connectionName))
+}
+
+func getAggregatorViewSelect(schemaName, table string, columns []string, addConnectionColumn bool, dedupKeys []string) string {
+	columnLookup := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		columnLookup[c] = struct{}{}
+	}
+	_, hasCtx := columnLookup[ctxColumnName]
+	connectionExpression := fmt.Sprintf("%s->>'connection_name'", PgEscapeName(ctxColumnName))
+
+	// only de-duplicate tables which have all the key columns
+	dedup := len(dedupKeys) > 0
+	for _, k := range dedupKeys {
+		if _, ok := columnLookup[k]; !ok {
+			dedup = false
+			break
+		}
+	}
+
+	var query strings.Builder
+	query.WriteString("select ")
+	var escapedKeys []string
+	if dedup {
+		for _, k := range dedupKeys {
+			escapedKeys = append(escapedKeys, PgEscapeName(k))
+		}
+		query.WriteString(fmt.Sprintf("distinct on (%s) ", strings.Join(escapedKeys, ", ")))
+	}
+	query.WriteString("*")
+	if addConnectionColumn && hasCtx {
+		query.WriteString(fmt.Sprintf(", %s as %s", connectionExpression, PgEscapeName(ConnectionColumnName)))
+	}
+	query.WriteString(fmt.Sprintf(" from %s.%s", schemaName, PgEscapeName(table)))
+	if dedup {
+		orderBy := escapedKeys
+		// make the choice of row deterministic
+		if hasCtx {
+			orderBy = append(orderBy, connectionExpression)
+		}
+		query.WriteString(fmt.Sprintf(" order by %s", strings.Join(orderBy, ", ")))
+	}
+	return query.String()
+}
@@ -0,0 +1,91 @@
+package db_common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetAggregatorViewsQuery(t *testing.T) {
+	tableColumns := map[string][]string{
+		"aws_iam_user": {"name", "arn", "_ctx"},
+		"aws_region":   {"name", "account_id"},
+	}
+	expected := `do $$
+begin
+  if obj_description(to_regnamespace($steampipe_escape$"aws_all_view"$steampipe_escape$), 'pg_namespace') = $steampipe_escape$steampipe aggregator views: aws_all$steampipe_escape$ then
+    execute format('drop schema %I cascade', $steampipe_escape$aws_all_view$steampipe_escape$);
+  end if;
+end $$;
+create schema "aws_all_view";
+comment on schema "aws_all_view" is $steampipe_escape$steampipe aggregator views: aws_all$steampipe_escape$;
+grant usage on schema "aws_all_view" to steampipe_users;
+alter default privileges in schema "aws_all_view" grant select on tables to steampipe_users;
+create view "aws_all_view"."aws_iam_user" as select distinct on ("arn") *, "_ctx"->>'connection_name' as "_ctx_connection" from "aws_all"."aws_iam_user" order by "arn", "_ctx"->>'connection_name';
+create view "aws_all_view"."aws_region" as select * from "aws_all"."aws_region";
+grant select on all tables in schema "aws_all_view" to steampipe_users;
+`
+	res := GetAggregatorViewsQuery("aws_all", tableColumns, true, []string{"arn"})
+	if res != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, res)
+	}
+}
+
+func TestGetAggregatorViewSelect(t *testing.T) {
+	tests := []struct {
+		name                string
+		columns             []string
+		addConnectionColumn bool
+		dedupKeys           []string
+		expected            string
+	}{
+		{
+			name:     "no options",
+			columns:  []string{"name", "_ctx"},
+			expected: `select * from "aws_all"."t"`,
+		},
+		{
+			name:                "connection column",
+			columns:             []string{"name", "_ctx"},
+			addConnectionColumn: true,
+			expected:            `select *, "_ctx"->>'connection_name' as "_ctx_connection" from "aws_all"."t"`,
+		},
+		{
+			name:                "connection column without ctx",
+			columns:             []string{"name"},
+			addConnectionColumn: true,
+			expected:            `select * from "aws_all"."t"`,
+		},
+		{
+			name:      "dedup keys",
+			columns:   []string{"name", "region", "_ctx"},
+			dedupKeys: []string{"name", "region"},
+			expected:  `select distinct on ("name", "region") * from "aws_all"."t" order by "name", "region", "_ctx"->>'connection_name'`,
+		},
+		{
+			name:      "dedup keys missing from table",
+			columns:   []string{"name", "_ctx"},
+			dedupKeys: []string{"name", "region"},
+			expected:  `select * from "aws_all"."t"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := getAggregatorViewSelect(`"aws_all"`, "t", test.columns, test.addConnectionColumn, test.dedupKeys)
+			if res != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestGetDeleteAggregatorViewsQueryOnlyDropsGeneratedSchema(t *testing.T) {
+	// a connection named 'aws_all_view' has the same schema name as the views of aggregator connection 'aws_all'
+	// - the schema must only be dropped if it carries the aggregator views comment
+	res := GetDeleteAggregatorViewsQuery("aws_all")
+	if strings.Contains(res, "drop schema if exists") {
+		t.Errorf("view schema is dropped unconditionally:\n%s", res)
+	}
+	if !strings.Contains(res, "= $steampipe_escape$steampipe aggregator views: aws_all$steampipe_escape$ then") {
+		t.Errorf("view schema drop is not conditional on the schema comment:\n%s", res)
+	}
+}
@@ -0,0 +1,64 @@
+package db_common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// GetColumnMaskQuery returns the sql to hide the given columns in the given schema from the given roles
+// (steampipe_users, and any service users with access to the schema)
+//
+// select is granted on all tables in the schema (restoring access to any previously masked columns),
+// then for any table containing a masked column, the table level select is revoked and select is granted
+// on the remaining columns only
+// NOTE: admin users are not affected
+//
+// tableColumns is a map of table name to the column names of that table
+func GetColumnMaskQuery(schemaName string, tableColumns map[string][]string, maskColumns []string, roles []string) string {
+	escapedSchemaName := PgEscapeName(schemaName)
+	escapedRoles := escapeRoles(roles)
+	maskLookup := make(map[string]struct{}, len(maskColumns))
+	for _, c := range maskColumns {
+		maskLookup[c] = struct{}{}
+	}
+
+	var statements strings.Builder
+	statements.WriteString(fmt.Sprintf("grant select on all tables in schema %s to %s;\n", escapedSchemaName, escapedRoles))
+
+	// sort table names so the query is deterministic
+	tables := maps.Keys(tableColumns)
+	sort.Strings(tables)
+	for _, table := range tables {
+		var visibleColumns []string
+		masked := false
+		for _, c := range tableColumns[table] {
+			if _, ok := maskLookup[c]; ok {
+				masked = true
+				continue
+			}
+			visibleColumns = append(visibleColumns, PgEscapeName(c))
+		}
+		if !masked {
+			continue
+		}
+		escapedTable := fmt.Sprintf("%s.%s", escapedSchemaName, PgEscapeName(table))
+		// NOTE: revoking the table level privilege also revokes any column level privileges
+		statements.WriteString(fmt.Sprintf("revoke select on %s from %s;\n", escapedTable, escapedRoles))
+		if len(visibleColumns) > 0 {
+			statements.WriteString(fmt.Sprintf("grant select (%s) on %s to %s;\n", strings.Join(visibleColumns, ", "), escapedTable, escapedRoles))
+		}
+	}
+	return statements.String()
+}
+
+// escapeRoles returns the escaped role names as a comma separated list, for use in a grant or revoke
+func escapeRoles(roles []string) string {
+	escapedRoles := make([]string, len(roles))
+	for i, r := range roles {
+		escapedRoles[i] = PgEscapeName(r)
+	}
+	return strings.Join(escapedRoles, ", ")
+}
@@ -0,0 +1,84 @@
+package db_common
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestGetColumnMaskQuery(t *testing.T) {
+	tests := map[string]struct {
+		schemaName   string
+		tableColumns map[string][]string
+		maskColumns  []string
+		roles        []string
+		expected     string
+	}{
+		"masked columns": {
+			schemaName: "aws",
+			tableColumns: map[string][]string{
+				"aws_iam_user": {"name", "secret", "arn"},
+			},
+			maskColumns: []string{"secret"},
+			roles:       []string{constants.DatabaseUsersRole},
+			expected: `grant select on all tables in schema "aws" to "steampipe_users";
+revoke select on "aws"."aws_iam_user" from "steampipe_users";
+grant select ("name", "arn") on "aws"."aws_iam_user" to "steampipe_users";
+`,
+		},
+		"all columns masked": {
+			schemaName: "aws",
+			tableColumns: map[string][]string{
+				"aws_iam_access_key": {"secret", "token"},
+			},
+			maskColumns: []string{"secret", "token"},
+			roles:       []string{constants.DatabaseUsersRole},
+			expected: `grant select on all tables in schema "aws" to "steampipe_users";
+revoke select on "aws"."aws_iam_access_key" from "steampipe_users";
+`,
+		},
+		"unmasked tables are untouched": {
+			schemaName: "aws",
+			tableColumns: map[string][]string{
+				"aws_region":   {"name"},
+				"aws_iam_user": {"name", "secret"},
+				"aws_account":  {"account_id"},
+			},
+			maskColumns: []string{"secret"},
+			roles:       []string{constants.DatabaseUsersRole},
+			expected: `grant select on all tables in schema "aws" to "steampipe_users";
+revoke select on "aws"."aws_iam_user" from "steampipe_users";
+grant select ("name") on "aws"."aws_iam_user" to "steampipe_users";
+`,
+		},
+		"no masked columns": {
+			schemaName: "aws",
+			tableColumns: map[string][]string{
+				"aws_region": {"name"},
+			},
+			roles: []string{constants.DatabaseUsersRole},
+			expected: `grant select on all tables in schema "aws" to "steampipe_users";
+`,
+		},
+		"roles and identifiers are escaped": {
+			schemaName: `my"conn`,
+			tableColumns: map[string][]string{
+				`Users Table`: {"Name", `se"cret`},
+			},
+			maskColumns: []string{`se"cret`},
+			roles:       []string{constants.DatabaseUsersRole, `Analyst"; drop role admin; --`},
+			expected: `grant select on all tables in schema "my""conn" to "steampipe_users", "Analyst""; drop role admin; --";
+revoke select on "my""conn"."Users Table" from "steampipe_users", "Analyst""; drop role admin; --";
+grant select ("Name") on "my""conn"."Users Table" to "steampipe_users", "Analyst""; drop role admin; --";
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := GetColumnMaskQuery(test.schemaName, test.tableColumns, test.maskColumns, test.roles)
+			if res != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package db_common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// GetColumnTypesQuery returns the sql to override the types of columns of the foreign tables of a connection
+// this is executed after the foreign schema is imported, in the same transaction
+//
+// columnTypes is a map of column to type, where the column is either a column name, which matches the column
+// in every table, or 'table.column' - if both match a column, the table specific type is used
+// NOTE: the types must have been validated, as they are not escaped
+func GetColumnTypesQuery(connectionName string, columnTypes map[string]string) string {
+	if len(columnTypes) == 0 {
+		return ""
+	}
+	var overrides []string
+	for _, column := range utils.SortedMapKeys(columnTypes) {
+		table, columnName, hasTable := strings.Cut(column, ".")
+		if !hasTable {
+			table, columnName = "", column
+		}
+		overrides = append(overrides, fmt.Sprintf("(%s, %s, %s)", PgEscapeString(table), PgEscapeString(columnName), PgEscapeString(columnTypes[column])))
+	}
+
+	// the foreign tables are not known until the schema is imported, so the matching columns are found and altered by
+	// an anonymous code block - altering the type of a foreign table column only changes its declared type,
+	// the fdw converts the text representation of the values returned by the plugin to the declared type
+	//
+	// where both a table specific and a bare column override match a column, 'distinct on' keeps the first row,
+	// and the order puts the table specific override (for which overrides.table_name = '' is false) first
+	//
+	// a numeric column cannot be read as a date or timestamp (e.g. an epoch time), as its text representation
+	// is not a valid date - this is reported as an error rather than failing when the table is queried
+	return fmt.Sprintf(`do $column_types$
+declare
+  c record;
+begin
+  for c in
+    select distinct on (columns.table_name, columns.column_name) columns.table_name, columns.column_name, columns.data_type, overrides.column_type
+    from information_schema.columns
+    join (values %s) as overrides(table_name, column_name, column_type)
+      on columns.column_name = overrides.column_name and (overrides.table_name = '' or overrides.table_name = columns.table_name)
+    where columns.table_schema = %s
+    order by columns.table_name, columns.column_name, overrides.table_name = ''
+  loop
+    if c.data_type in ('smallint', 'integer', 'bigint', 'numeric', 'real', 'double precision') and c.column_type in ('date', 'timestamp', 'timestamptz') then
+      raise exception 'column_types cannot convert column %%.%% from %% to %% - numeric values (such as epoch times) cannot be read as dates or timestamps', c.table_name, c.column_name, c.data_type, c.column_type;
+    end if;
+    execute format('alter foreign table %%I.%%I alter column %%I type %%s', %s, c.table_name, c.column_name, c.column_type);
+  end loop;
+end
+$column_types$;
+`, strings.Join(overrides, ", "), PgEscapeString(connectionName), PgEscapeString(connectionName))
+}
@@ -0,0 +1,46 @@
+package db_common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetColumnTypesQuery(t *testing.T) {
+	if res := GetColumnTypesQuery("aws", nil); res != "" {
+		t.Errorf("expected no query when there are no column types, got %s", res)
+	}
+
+	res := GetColumnTypesQuery("aws", map[string]string{"tags": "text", "aws_s3_bucket.creation_date": "date"})
+	// overrides are ordered by column, with the table (if any) split from the column name
+	expectedValues := `(values ($steampipe_escape$aws_s3_bucket$steampipe_escape$, $steampipe_escape$creation_date$steampipe_escape$, $steampipe_escape$date$steampipe_escape$), ` +
+		`($steampipe_escape$$steampipe_escape$, $steampipe_escape$tags$steampipe_escape$, $steampipe_escape$text$steampipe_escape$))`
+	if !strings.Contains(res, expectedValues) {
+		t.Errorf("expected query to contain %s, got %s", expectedValues, res)
+	}
+	if !strings.Contains(res, `where columns.table_schema = $steampipe_escape$aws$steampipe_escape$`) {
+		t.Errorf("expected query to be limited to the connection schema, got %s", res)
+	}
+}
+
+func TestGetColumnTypesQueryPrecedence(t *testing.T) {
+	res := GetColumnTypesQuery("aws", map[string]string{"tags": "text", "aws_s3_bucket.tags": "jsonb"})
+	// both overrides match aws_s3_bucket.tags - 'distinct on' keeps the first row for each column,
+	// and ordering by overrides.table_name = '' puts the table specific override (false) before the bare column override (true)
+	for _, expected := range []string{
+		"select distinct on (columns.table_name, columns.column_name)",
+		"order by columns.table_name, columns.column_name, overrides.table_name = ''",
+	} {
+		if !strings.Contains(res, expected) {
+			t.Errorf("expected query to contain %s, got %s", expected, res)
+		}
+	}
+}
+
+func TestGetColumnTypesQueryRejectsNumericDates(t *testing.T) {
+	res := GetColumnTypesQuery("aws", map[string]string{"created_at": "timestamptz"})
+	// numeric columns (e.g. epoch times) cannot be read as dates or timestamps
+	expected := "if c.data_type in ('smallint', 'integer', 'bigint', 'numeric', 'real', 'double precision') and c.column_type in ('date', 'timestamp', 'timestamptz') then\n      raise exception"
+	if !strings.Contains(res, expected) {
+		t.Errorf("expected query to reject numeric columns read as timestamps, got %s", res)
+	}
+}
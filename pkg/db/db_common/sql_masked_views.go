@@ -0,0 +1,117 @@
+package db_common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"golang.org/x/exp/maps"
+)
+
+// the text which replaces masked values using the redact masking policy
+const redactedValue = "REDACTED"
+
+// TableColumn is the name and (postgres) data type of a table column
+type TableColumn struct {
+	Name     string
+	DataType string
+}
+
+// GetMaskedViewSchemaName returns the name of the schema which contains the masked views for a connection
+func GetMaskedViewSchemaName(connectionName string) string {
+	return fmt.Sprintf("%s_masked", connectionName)
+}
+
+// getMaskedViewSchemaComment returns the comment of the masked view schema for a connection
+// this identifies the schema as generated, so it is only ever dropped if it was created by steampipe
+func getMaskedViewSchemaComment(connectionName string) string {
+	return fmt.Sprintf("steampipe masked views: %s", connectionName)
+}
+
+// GetMaskedViewsQuery returns the sql to (re)create the masked view schema for a connection
+//
+// a view is created for every table in the connection schema, in which any masked column is replaced
+// using the masking policy (for non-admin users only - admin users see the unmasked value):
+//   - null: the column is null
+//   - redact: text and jsonb columns contain 'REDACTED', other columns are null
+//   - hash: text and jsonb columns contain the hex encoded sha256 hash of the value, other columns are null
+//     (so masked values may still be compared and joined on)
+//
+// the views are owned by the (superuser) owner of the schema, so may read the masked columns which are hidden
+// from the roles in the connection schema - usage and select are granted to the given roles
+//
+// tableColumns is a map of table name to the columns of that table
+func GetMaskedViewsQuery(connectionName string, tableColumns map[string][]TableColumn, maskColumns []string, maskingPolicy string, roles []string) string {
+	viewSchemaName := PgEscapeName(GetMaskedViewSchemaName(connectionName))
+	schemaName := PgEscapeName(connectionName)
+	escapedRoles := escapeRoles(roles)
+	maskLookup := make(map[string]struct{}, len(maskColumns))
+	for _, c := range maskColumns {
+		maskLookup[c] = struct{}{}
+	}
+
+	var statements strings.Builder
+	statements.WriteString(GetDeleteMaskedViewsQuery(connectionName))
+	statements.WriteString(fmt.Sprintf("create schema %s;\n", viewSchemaName))
+	statements.WriteString(fmt.Sprintf("comment on schema %s is %s;\n", viewSchemaName, PgEscapeString(getMaskedViewSchemaComment(connectionName))))
+	statements.WriteString(fmt.Sprintf("grant usage on schema %s to %s;\n", viewSchemaName, escapedRoles))
+
+	// sort table names so the query is deterministic
+	tables := maps.Keys(tableColumns)
+	sort.Strings(tables)
+	for _, table := range tables {
+		var selectColumns []string
+		for _, c := range tableColumns[table] {
+			escapedColumn := PgEscapeName(c.Name)
+			if _, ok := maskLookup[c.Name]; !ok {
+				selectColumns = append(selectColumns, escapedColumn)
+				continue
+			}
+			selectColumns = append(selectColumns, fmt.Sprintf("case when pg_has_role(current_user, '%s', 'member') then %s else %s end as %s",
+				constants.DatabaseAdminRole,
+				escapedColumn,
+				getMaskedValueExpression(escapedColumn, c.DataType, maskingPolicy),
+				escapedColumn))
+		}
+		statements.WriteString(fmt.Sprintf("create view %s.%s as select %s from %s.%s;\n",
+			viewSchemaName,
+			PgEscapeName(table),
+			strings.Join(selectColumns, ", "),
+			schemaName,
+			PgEscapeName(table)))
+	}
+	statements.WriteString(fmt.Sprintf("grant select on all tables in schema %s to %s;\n", viewSchemaName, escapedRoles))
+
+	return statements.String()
+}
+
+// GetDeleteMaskedViewsQuery returns the sql to drop the masked view schema for a connection
+// NOTE: the schema is only dropped if it was created by steampipe - a connection may be named '<connection>_masked'
+func GetDeleteMaskedViewsQuery(connectionName string) string {
+	return GetDropGeneratedSchemaQuery(GetMaskedViewSchemaName(connectionName), getMaskedViewSchemaComment(connectionName))
+}
+
+// getMaskedValueExpression returns the expression which replaces the value of a masked column
+// the expression must have the same type as the column, so values which cannot be represented are null
+// (the type of the null is resolved from the column by the case expression)
+func getMaskedValueExpression(escapedColumn, dataType, maskingPolicy string) string {
+	isText := dataType == "text" || dataType == "character varying"
+	isJson := dataType == "jsonb"
+	switch {
+	case maskingPolicy == constants.MaskingPolicyRedact && isText:
+		return PgEscapeString(redactedValue)
+	case maskingPolicy == constants.MaskingPolicyRedact && isJson:
+		return fmt.Sprintf("to_jsonb(%s::text)", PgEscapeString(redactedValue))
+	case maskingPolicy == constants.MaskingPolicyHash && isText:
+		return getHashExpression(escapedColumn)
+	case maskingPolicy == constants.MaskingPolicyHash && isJson:
+		return fmt.Sprintf("to_jsonb(%s)", getHashExpression(escapedColumn+"::text"))
+	default:
+		return "null"
+	}
+}
+
+func getHashExpression(value string) string {
+	return fmt.Sprintf("encode(sha256(convert_to(%s, 'UTF8')), 'hex')", value)
+}
@@ -0,0 +1,65 @@
+package db_common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestGetMaskedValueExpression(t *testing.T) {
+	tests := []struct {
+		dataType string
+		policy   string
+		expected string
+	}{
+		{dataType: "text", policy: constants.MaskingPolicyNull, expected: `null`},
+		{dataType: "text", policy: constants.MaskingPolicyRedact, expected: `$steampipe_escape$REDACTED$steampipe_escape$`},
+		{dataType: "jsonb", policy: constants.MaskingPolicyRedact, expected: `to_jsonb($steampipe_escape$REDACTED$steampipe_escape$::text)`},
+		{dataType: "bigint", policy: constants.MaskingPolicyRedact, expected: `null`},
+		{dataType: "text", policy: constants.MaskingPolicyHash, expected: `encode(sha256(convert_to("secret", 'UTF8')), 'hex')`},
+		{dataType: "jsonb", policy: constants.MaskingPolicyHash, expected: `to_jsonb(encode(sha256(convert_to("secret"::text, 'UTF8')), 'hex'))`},
+		{dataType: "inet", policy: constants.MaskingPolicyHash, expected: `null`},
+	}
+	for _, test := range tests {
+		if res := getMaskedValueExpression(`"secret"`, test.dataType, test.policy); res != test.expected {
+			t.Errorf("%s (%s): expected %s, got %s", test.dataType, test.policy, test.expected, res)
+		}
+	}
+}
+
+func TestGetMaskedViewsQuery(t *testing.T) {
+	tableColumns := map[string][]TableColumn{
+		"aws_iam_user": {{Name: "name", DataType: "text"}, {Name: "secret", DataType: "text"}},
+		"aws_region":   {{Name: "name", DataType: "text"}},
+	}
+	expected := `do $$
+begin
+  if obj_description(to_regnamespace($steampipe_escape$"aws_masked"$steampipe_escape$), 'pg_namespace') = $steampipe_escape$steampipe masked views: aws$steampipe_escape$ then
+    execute format('drop schema %I cascade', $steampipe_escape$aws_masked$steampipe_escape$);
+  end if;
+end $$;
+create schema "aws_masked";
+comment on schema "aws_masked" is $steampipe_escape$steampipe masked views: aws$steampipe_escape$;
+grant usage on schema "aws_masked" to "steampipe_users", "analyst";
+create view "aws_masked"."aws_iam_user" as select "name", case when pg_has_role(current_user, 'steampipe_admin', 'member') then "secret" else null end as "secret" from "aws"."aws_iam_user";
+create view "aws_masked"."aws_region" as select "name" from "aws"."aws_region";
+grant select on all tables in schema "aws_masked" to "steampipe_users", "analyst";
+`
+	res := GetMaskedViewsQuery("aws", tableColumns, []string{"secret"}, constants.MaskingPolicyNull, []string{constants.DatabaseUsersRole, "analyst"})
+	if res != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, res)
+	}
+}
+
+func TestGetDeleteMaskedViewsQueryOnlyDropsGeneratedSchema(t *testing.T) {
+	// a connection named 'aws_masked' has the same schema name as the masked views of connection 'aws'
+	// - the schema must only be dropped if it carries the masked views comment
+	res := GetDeleteMaskedViewsQuery("aws")
+	if strings.Contains(res, "drop schema if exists") {
+		t.Errorf("masked view schema is dropped unconditionally:\n%s", res)
+	}
+	if !strings.Contains(res, "= $steampipe_escape$steampipe masked views: aws$steampipe_escape$ then") {
+		t.Errorf("masked view schema drop is not conditional on the schema comment:\n%s", res)
+	}
+}
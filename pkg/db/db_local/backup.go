@@ -58,19 +58,9 @@ const (
 	onlyMatViewRefreshListFileName = "only_refresh.lst"
 )
 
-// prepareBackup creates a backup file of the public schema for the current database, if we are migrating
-// if a backup was taken, this returns the name of the database that was backed up
-func prepareBackup(ctx context.Context) (*string, error) {
-	found, location, err := findDifferentPgInstallation(ctx)
-	if err != nil {
-		log.Println("[TRACE] Error while finding different PG Version:", err)
-		return nil, err
-	}
-	// nothing found - nothing to do
-	if !found {
-		return nil, nil
-	}
-
+// prepareBackup creates a backup file of the public schema of the database of the previous installation being migrated
+// this returns the name of the database that was backed up
+func prepareBackup(ctx context.Context, migration *dbMigration) (*string, error) {
 	// ensure there is no orphaned instance of postgres running
 	// (if the service state file was in-tact, we would already have found it and
 	// failed before now with a suitable message
@@ -80,9 +70,9 @@ func prepareBackup(ctx context.Context) (*string, error) {
 		return nil, err
 	}
 
-	runConfig, err := startDatabaseInLocation(ctx, location)
+	runConfig, err := startDatabaseInLocation(ctx, migration.fromLocation)
 	if err != nil {
-		log.Printf("[TRACE] Error while starting old db in %s: %v", location, err)
+		log.Printf("[TRACE] Error while starting old db in %s: %v", migration.fromLocation, err)
 		return nil, err
 	}
 	//nolint:golint,errcheck // this will probably never error - if it does, it's not something we can recover from with code
@@ -228,6 +218,7 @@ func findDifferentPgInstallation(ctx context.Context) (bool, string, error) {
 }
 
 // restoreDBBackup loads the back up file into the database
+// if the restore fails, the backup is retained and an error wrapping errDbRestoreFailed is returned
 func restoreDBBackup(ctx context.Context) error {
 	backupFilePath := filepaths.DatabaseBackupFilePath()
 	if !files.FileExists(backupFilePath) {
@@ -236,6 +227,37 @@ func restoreDBBackup(ctx context.Context) error {
 	}
 	log.Printf("[TRACE] restoreDBBackup: backup file '%s' found, restoring", backupFilePath)
 
+	if err := restoreDBBackupFile(ctx); err != nil {
+		// keep a copy of the backup, in case the migration cannot be retried
+		if err := retainBackup(ctx); err != nil {
+			error_helpers.ShowWarning(fmt.Sprintf("Failed to save backup file: %v", err))
+		}
+		return fmt.Errorf("%w: %w", errDbRestoreFailed, err)
+	}
+
+	if err := retainBackup(ctx); err != nil {
+		error_helpers.ShowWarning(fmt.Sprintf("Failed to save backup file: %v", err))
+	}
+
+	// get the location of the other instance which was backed up
+	found, location, err := findDifferentPgInstallation(ctx)
+	if err != nil {
+		return err
+	}
+
+	// remove it
+	if found {
+		if err := os.RemoveAll(location); err != nil {
+			log.Printf("[WARN] Could not remove old installation at %s.", location)
+		}
+	}
+
+	return nil
+}
+
+// restoreDBBackupFile restores the objects and data in the backup file, then refreshes the materialized views
+func restoreDBBackupFile(ctx context.Context) error {
+
 	// load the db status
 	runningInfo, err := GetState()
 	if err != nil {
@@ -290,24 +312,6 @@ func restoreDBBackup(ctx context.Context) error {
 		//
 		error_helpers.ShowWarning("Could not REFRESH Materialized Views while restoring data. Please REFRESH manually.")
 	}
-
-	if err := retainBackup(ctx); err != nil {
-		error_helpers.ShowWarning(fmt.Sprintf("Failed to save backup file: %v", err))
-	}
-
-	// get the location of the other instance which was backed up
-	found, location, err := findDifferentPgInstallation(ctx)
-	if err != nil {
-		return err
-	}
-
-	// remove it
-	if found {
-		if err := os.RemoveAll(location); err != nil {
-			log.Printf("[WARN] Could not remove old installation at %s.", location)
-		}
-	}
-
 	return nil
 }
 
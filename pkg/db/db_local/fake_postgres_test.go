@@ -0,0 +1,126 @@
+package db_local
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+// the OID of the postgres text type - all columns of the fake postgres server are text
+const fakePostgresTextOID = 25
+
+// fakePostgres is a minimal postgres server, serving TLS with the service certificates of the install dir
+// every statement returns the text columns 'name' and 'state', with a row for each of the given connection states
+type fakePostgres struct {
+	listener net.Listener
+	// connection name to connection state
+	connectionStates map[string]string
+	tlsConfig        *tls.Config
+}
+
+// newFakePostgres starts a fake postgres server
+// NOTE: the service certificates must exist in the install dir
+func newFakePostgres(t testing.TB, connectionStates map[string]string) *fakePostgres {
+	cert, err := tls.LoadX509KeyPair(filepaths.GetServerCertLocation(), filepaths.GetServerCertKeyLocation())
+	if err != nil {
+		t.Fatalf("failed to load the server certificate: %s", err.Error())
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	s := &fakePostgres{
+		listener:         listener,
+		connectionStates: connectionStates,
+		tlsConfig:        &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakePostgres) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakePostgres) serve(conn net.Conn) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+
+	msg, err := backend.ReceiveStartupMessage()
+	if err != nil {
+		return
+	}
+	// upgrade to TLS if requested, then receive the actual startup message
+	if _, ok := msg.(*pgproto3.SSLRequest); ok {
+		if _, err := conn.Write([]byte("S")); err != nil {
+			return
+		}
+		tlsConn := tls.Server(conn, s.tlsConfig)
+		defer tlsConn.Close()
+		backend = pgproto3.NewBackend(tlsConn, tlsConn)
+		if _, err := backend.ReceiveStartupMessage(); err != nil {
+			return
+		}
+	}
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	rowDescription := &pgproto3.RowDescription{}
+	for _, name := range []string{"name", "state"} {
+		rowDescription.Fields = append(rowDescription.Fields, pgproto3.FieldDescription{
+			Name:         []byte(name),
+			DataTypeOID:  fakePostgresTextOID,
+			DataTypeSize: -1,
+			TypeModifier: -1,
+		})
+	}
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		switch msg.(type) {
+		case *pgproto3.Parse:
+			backend.Send(&pgproto3.ParseComplete{})
+		case *pgproto3.Describe:
+			backend.Send(&pgproto3.ParameterDescription{})
+			backend.Send(rowDescription)
+		case *pgproto3.Bind:
+			backend.Send(&pgproto3.BindComplete{})
+		case *pgproto3.Execute:
+			for name, state := range s.connectionStates {
+				backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte(name), []byte(state)}})
+			}
+			backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT")})
+		case *pgproto3.Sync:
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case *pgproto3.Query:
+			// a ping
+			backend.Send(&pgproto3.EmptyQueryResponse{})
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case *pgproto3.Terminate:
+			return
+		}
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
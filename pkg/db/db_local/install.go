@@ -2,16 +2,15 @@ package db_local
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
 	"sync"
 
-	"github.com/fatih/color"
 	"github.com/jackc/pgx/v5"
 	psutils "github.com/shirou/gopsutil/process"
+	"github.com/spf13/viper"
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe/pkg/constants"
@@ -24,16 +23,6 @@ import (
 
 var ensureMux sync.Mutex
 
-func noBackupWarning() string {
-	warningMessage := `Steampipe database has been upgraded from Postgres 12 to Postgres 14.
-
-Unfortunately the data in your public schema failed migration using the standard pg_dump and pg_restore tools. Your data has been preserved in the ~/.steampipe/db directory.
-
-If you need to restore the contents of your public schema, please open an issue at https://github.com/turbot/steampipe.`
-
-	return fmt.Sprintf("%s: %v\n", color.YellowString("Warning"), warningMessage)
-}
-
 // EnsureDBInstalled makes sure that the embedded postgres database is installed and ready to run
 func EnsureDBInstalled(ctx context.Context) (err error) {
 	utils.LogTime("db_local.EnsureDBInstalled start")
@@ -51,6 +40,14 @@ func EnsureDBInstalled(ctx context.Context) (err error) {
 		close(doneChan)
 	}()
 
+	// hold the service lock while installing, so the temporary postgres process used by the install
+	// is not reaped by a concurrent steampipe run
+	lock, err := utils.LockFile(filepaths.ServiceLockFilePath())
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	if IsDBInstalled() {
 		// check if the FDW need updating, and init the db if required
 		err := prepareDb(ctx)
@@ -74,6 +71,13 @@ func EnsureDBInstalled(ctx context.Context) (err error) {
 		return fmt.Errorf("Cleanup any Steampipe processes... FAILED!")
 	}
 
+	// if there is a previous database installation, its data must be migrated to the new version
+	migration, err := findDbMigration(ctx)
+	if err != nil {
+		log.Printf("[TRACE] findDbMigration failed: %v", err)
+		return err
+	}
+
 	statushooks.SetStatus(ctx, "Installing database…")
 
 	err = downloadAndInstallDbFiles(ctx)
@@ -81,19 +85,24 @@ func EnsureDBInstalled(ctx context.Context) (err error) {
 		return err
 	}
 
-	statushooks.SetStatus(ctx, "Preparing backups…")
+	// if the data of the previous installation is being migrated and the install fails,
+	// remove the new installation, so the migration is retried from the previous installation
+	defer func() {
+		if err != nil && migration != nil {
+			rollbackDbMigration()
+			err = migration.failed(err)
+		}
+	}()
 
 	// call prepareBackup to generate the db dump file if necessary
 	// NOTE: this returns the existing database name - we use this when creating the new database
-	dbName, err := prepareBackup(ctx)
-	if err != nil {
-		if errors.Is(err, errDbInstanceRunning) {
-			// remove the installation - otherwise, the backup won't get triggered, even if the user stops the service
-			os.RemoveAll(filepaths.DatabaseInstanceDir())
+	var dbName *string
+	if migration != nil {
+		statushooks.SetStatus(ctx, "Preparing backups…")
+		dbName, err = prepareBackup(ctx, migration)
+		if err != nil {
 			return err
 		}
-		// ignore all other errors with the backup, displaying a warning instead
-		statushooks.Message(ctx, noBackupWarning())
 	}
 
 	// install the fdw
@@ -131,7 +140,11 @@ func downloadAndInstallDbFiles(ctx context.Context) error {
 	}
 
 	statushooks.SetStatus(ctx, "Download & install embedded PostgreSQL database…")
-	_, err = ociinstaller.InstallDB(ctx, filepaths.GetDatabaseLocation())
+	if archivePath := viper.GetString(constants.ArgDatabaseArchive); archivePath != "" {
+		_, err = ociinstaller.InstallDBFromArchive(ctx, filepaths.GetDatabaseLocation(), archivePath)
+	} else {
+		_, err = ociinstaller.InstallDB(ctx, filepaths.GetDatabaseLocation())
+	}
 	if err != nil {
 		log.Printf("[TRACE] %v", err)
 		return fmt.Errorf("Download & install embedded PostgreSQL database... FAILED!")
@@ -246,6 +259,9 @@ func installFDW(ctx context.Context, firstSetup bool) (string, error) {
 		}()
 	}
 	statushooks.SetStatus(ctx, fmt.Sprintf("Download & install %s…", constants.Bold("steampipe-postgres-fdw")))
+	if archivePath := viper.GetString(constants.ArgFdwArchive); archivePath != "" {
+		return ociinstaller.InstallFdwFromArchive(ctx, filepaths.GetDatabaseLocation(), archivePath)
+	}
 	return ociinstaller.InstallFdw(ctx, filepaths.GetDatabaseLocation())
 }
 
@@ -289,17 +305,23 @@ func runInstall(ctx context.Context, oldDbName *string) error {
 		return fmt.Errorf("Starting database... FAILED!")
 	}
 
-	statushooks.SetStatus(ctx, "Connection to database…")
-	client, err := createMaintenanceClient(ctx, port)
-	if err != nil {
-		return fmt.Errorf("Connection to database... FAILED!")
-	}
+	// stop the database once the install completes - or if any of the following steps fail or are cancelled,
+	// so the database process is not left running
+	var client *pgx.Conn
 	defer func() {
 		statushooks.SetStatus(ctx, "Completing configuration")
-		client.Close(ctx)
+		if client != nil {
+			client.Close(ctx)
+		}
 		doThreeStepPostgresExit(ctx, process)
 	}()
 
+	statushooks.SetStatus(ctx, "Connection to database…")
+	client, err = createMaintenanceClient(ctx, port)
+	if err != nil {
+		return fmt.Errorf("Connection to database... FAILED!")
+	}
+
 	statushooks.SetStatus(ctx, "Generating database passwords…")
 	// generate a password file for use later
 	_, err = readPasswordFile()
@@ -498,7 +520,7 @@ func installDatabaseWithPermissions(ctx context.Context, databaseName string, ra
 }
 
 func writePgHbaContent(databaseName string, username string) error {
-	content := fmt.Sprintf(constants.PgHbaTemplate, databaseName, username)
+	content := fmt.Sprintf(constants.PgHbaTemplate, databaseName, username, constants.DatabaseServiceUsersRole)
 	return os.WriteFile(filepaths.GetPgHbaConfLocation(), []byte(content), 0600)
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/introspection"
+	"github.com/turbot/steampipe/pkg/statestore"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -141,8 +142,23 @@ func setupInternal(ctx context.Context, conn *pgx.Conn) error {
 		fmt.Sprintf(`DROP FOREIGN TABLE IF EXISTS %s.%s;`, constants.InternalSchema, constants.ForeignTableScanMetadataSummary),
 		fmt.Sprintf(`DROP FOREIGN TABLE IF EXISTS %s.%s;`, constants.InternalSchema, constants.ForeignTableScanMetadata),
 		fmt.Sprintf(`DROP FOREIGN TABLE IF EXISTS %s.%s;`, constants.InternalSchema, constants.ForeignTableSettings),
+		// create the admin role - members may run privileged commands, such as clearing the cache
+		getAdminRoleCreateString(),
 		fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s;`, constants.InternalSchema),
 		fmt.Sprintf(`GRANT USAGE ON SCHEMA %s TO %s;`, constants.InternalSchema, constants.DatabaseUsersRole),
+		// commands sent to the service are recorded in the command request table, which only the admin role may insert into
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (payload TEXT NOT NULL, requested_by TEXT NOT NULL DEFAULT current_user, request_time TIMESTAMPTZ NOT NULL DEFAULT now());`, constants.InternalSchema, constants.CommandRequestTable),
+		fmt.Sprintf(`DELETE FROM %s.%s;`, constants.InternalSchema, constants.CommandRequestTable),
+		fmt.Sprintf(`GRANT INSERT ON %s.%s TO %s;`, constants.InternalSchema, constants.CommandRequestTable, constants.DatabaseAdminRole),
+		// create the service users role and the table recording the connections each service user may access
+		getServiceUsersRoleCreateString(),
+		// only steampipe users may create objects in the public schema - service users are read only
+		fmt.Sprintf(`GRANT USAGE, CREATE ON SCHEMA public TO %s;`, constants.DatabaseUsersRole),
+		`REVOKE CREATE ON SCHEMA public FROM PUBLIC;`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (name TEXT PRIMARY KEY, connections TEXT[] NOT NULL, created_at TIMESTAMPTZ NOT NULL DEFAULT now());`, constants.InternalSchema, constants.ServiceUserTable),
+		// the state table is used by the database state store - steampipe users may read it, so the CLI can load the refresh report
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (key TEXT PRIMARY KEY, content TEXT NOT NULL, updated_at TIMESTAMPTZ NOT NULL DEFAULT now());`, constants.InternalSchema, constants.StateTable),
+		fmt.Sprintf(`GRANT SELECT ON %s.%s TO %s;`, constants.InternalSchema, constants.StateTable, constants.DatabaseUsersRole),
 		fmt.Sprintf("IMPORT FOREIGN SCHEMA \"%s\" FROM SERVER steampipe INTO %s;", constants.InternalSchema, constants.InternalSchema),
 		fmt.Sprintf("GRANT INSERT ON %s.%s TO %s;", constants.InternalSchema, constants.ForeignTableSettings, constants.DatabaseUsersRole),
 		fmt.Sprintf("GRANT SELECT ON %s.%s TO %s;", constants.InternalSchema, constants.ForeignTableScanMetadataSummary, constants.DatabaseUsersRole),
@@ -155,6 +171,14 @@ func setupInternal(ctx context.Context, conn *pgx.Conn) error {
 		fmt.Sprintf("GRANT SELECT ON %s.%s TO %s;", constants.LegacyCommandSchema, constants.LegacyCommandTableScanMetadata, constants.DatabaseUsersRole),
 	}
 	queries = append(queries, getFunctionAddStrings(db_common.Functions)...)
+	// restrict clearing the cache to the admin role
+	// (users may still enable or disable the cache and set the cache ttl for their own session)
+	queries = append(queries,
+		getCommandPermissionTriggerString(constants.InternalSchema, constants.ForeignTableSettings, constants.ForeignTableSettingsKeyColumn, constants.ForeignTableSettingsCacheClearTimeKey, "connection_cache_clear"),
+		getCommandPermissionTriggerString(constants.LegacyCommandSchema, constants.LegacyCommandTableCache, constants.LegacyCommandTableCacheOperationColumn, constants.LegacyCommandCacheClear),
+		// grant service users access to their connection schemas whenever the schemas are recreated
+		getServiceUserAccessTriggerString(),
+	)
 	if _, err := ExecuteSqlInTransaction(ctx, conn, queries...); err != nil {
 		return sperr.WrapWithMessage(err, "failed to initialise functions")
 	}
@@ -162,6 +186,60 @@ func setupInternal(ctx context.Context, conn *pgx.Conn) error {
 	return nil
 }
 
+// getAdminRoleCreateString returns the sql to create the admin role, if it does not exist
+// when the role is created, it is granted to the steampipe user - after that, membership is managed by the database owner
+func getAdminRoleCreateString() string {
+	return fmt.Sprintf(`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '%[1]s') THEN
+		CREATE ROLE %[1]s;
+		GRANT %[2]s TO %[1]s;
+		GRANT %[1]s TO %[3]s;
+	END IF;
+END
+$$;`, constants.DatabaseAdminRole, constants.DatabaseUsersRole, constants.DatabaseUser)
+}
+
+// getServiceUsersRoleCreateString returns the sql to create the service users role, if it does not exist,
+// and allow its members to connect to the steampipe database
+func getServiceUsersRoleCreateString() string {
+	return fmt.Sprintf(`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '%[1]s') THEN
+		CREATE ROLE %[1]s;
+	END IF;
+	EXECUTE format('GRANT CONNECT ON DATABASE %%I TO %[1]s', current_database());
+END
+$$;`, constants.DatabaseServiceUsersRole)
+}
+
+// getServiceUserAccessTriggerString returns the sql to add the event trigger which grants service users
+// access to their connection schemas when the schemas are created
+func getServiceUserAccessTriggerString() string {
+	return fmt.Sprintf(`DROP EVENT TRIGGER IF EXISTS %[1]s;
+CREATE EVENT TRIGGER %[1]s ON ddl_command_end WHEN TAG IN ('CREATE SCHEMA') EXECUTE FUNCTION %[2]s.%[1]s();`,
+		constants.FunctionGrantServiceUserAccess,
+		constants.InternalSchema,
+	)
+}
+
+// getCommandPermissionTriggerString returns the sql to add a trigger to a command table which rejects
+// inserts of the given commands by roles which are not members of the admin role
+func getCommandPermissionTriggerString(schema, table, commandColumn string, commands ...string) string {
+	args := []string{fmt.Sprintf("'%s'", commandColumn)}
+	for _, command := range commands {
+		args = append(args, fmt.Sprintf("'%s'", command))
+	}
+	return fmt.Sprintf(`DROP TRIGGER IF EXISTS %[3]s ON %[1]s.%[2]s;
+CREATE TRIGGER %[3]s BEFORE INSERT ON %[1]s.%[2]s FOR EACH ROW EXECUTE FUNCTION %[4]s.%[3]s(%[5]s);`,
+		schema,
+		table,
+		constants.FunctionCheckCommandPermission,
+		constants.InternalSchema,
+		strings.Join(args, ", "),
+	)
+}
+
 func getFunctionAddStrings(functions []db_common.SQLFunction) []string {
 	var addStrings []string
 	for _, function := range functions {
@@ -210,22 +288,28 @@ func validateFunction(f db_common.SQLFunction) error {
 - delete and recreate the table
 - update status of existing connection state to pending or imncomplete as appropriate
 - write back connection state
+
+if nothing has changed since the last successful refresh, the existing connection state is still valid and is retained
+returns whether refresh connections is required
 */
-func initializeConnectionStateTable(ctx context.Context, conn *pgx.Conn) error {
+func initializeConnectionStateTable(ctx context.Context, conn *pgx.Conn) (bool, error) {
 	// load the state (if the table is there)
 	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn)
 	if err != nil {
 		// ignore relation not found error
 		if !db_common.IsRelationNotFoundError(err) {
-			return err
+			return true, err
 		}
 
 		// create an empty connectionStateMap
 		connectionStateMap = steampipeconfig.ConnectionStateMap{}
 	}
-	// if any connections are in a ready  state, set them to pending - we need to run refresh connections before we know this connection is still valid
-	// if any connections are not in a ready or error state, set them to pending_incomplete
-	connectionStateMap.SetConnectionsToPendingOrIncomplete()
+	refreshRequired := steampipeconfig.RefreshConnectionsRequired(ctx, statestore.New(conn), connectionStateMap)
+	if refreshRequired {
+		// if any connections are in a ready  state, set them to pending - we need to run refresh connections before we know this connection is still valid
+		// if any connections are not in a ready or error state, set them to pending_incomplete
+		connectionStateMap.SetConnectionsToPendingOrIncomplete()
+	}
 
 	// migration: ensure filename and line numbers are set for all connection states
 	connectionStateMap.PopulateFilename()
@@ -234,6 +318,8 @@ func initializeConnectionStateTable(ctx context.Context, conn *pgx.Conn) error {
 	queries := introspection.GetConnectionStateTableDropSql()
 	queries = append(queries, introspection.GetConnectionStateTableCreateSql()...)
 	queries = append(queries, introspection.GetConnectionStateTableGrantSql()...)
+	// create the connection state history table if needed - this is NOT recreated, so history is retained across restarts
+	queries = append(queries, introspection.GetConnectionStateHistoryTableCreateSql(), introspection.GetConnectionStateHistoryTableGrantSql())
 
 	// add insert queries for all connection state
 	for _, s := range connectionStateMap {
@@ -249,7 +335,7 @@ func initializeConnectionStateTable(ctx context.Context, conn *pgx.Conn) error {
 		}
 	}
 	_, err = ExecuteSqlWithArgsInTransaction(ctx, conn, queries...)
-	return err
+	return refreshRequired, err
 }
 
 func PopulatePluginTable(ctx context.Context, conn *pgx.Conn) error {
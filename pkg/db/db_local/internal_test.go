@@ -0,0 +1,14 @@
+package db_local
+
+import (
+	"testing"
+)
+
+func TestGetCommandPermissionTriggerString(t *testing.T) {
+	res := getCommandPermissionTriggerString("steampipe_command", "cache", "operation", "cache_clear")
+	expected := `DROP TRIGGER IF EXISTS check_command_permission ON steampipe_command.cache;
+CREATE TRIGGER check_command_permission BEFORE INSERT ON steampipe_command.cache FOR EACH ROW EXECUTE FUNCTION steampipe_internal.check_command_permission('operation', 'cache_clear');`
+	if res != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, res)
+	}
+}
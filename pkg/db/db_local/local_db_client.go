@@ -2,6 +2,7 @@ package db_local
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	pb "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/proto"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/utils"
 )
 
@@ -53,7 +55,8 @@ func GetLocalClient(ctx context.Context, invoker constants.Invoker, onConnection
 
 	// after creating the client, refresh connections
 	// NOTE: we cannot do this until after creating the client to ensure we do not miss notifications
-	if startResult.Status == ServiceStarted {
+	// (unless nothing has changed since the last successful refresh)
+	if startResult.Status == ServiceStarted && !startResult.SkipRefreshConnections {
 		// ask the plugin manager to refresh connections
 		// this is executed asyncronously by the plugin manager
 		// we ignore this error, since RefreshConnections is async and all errors will flow through
@@ -108,9 +111,36 @@ func (c *LocalDbClient) initNotificationListener(ctx context.Context) error {
 	}
 	c.notificationListener = listener
 
+	// the schema metadata cache is invalidated by the schema update notification sent when connections are refreshed,
+	// so the cached metadata does not need to expire
+	listener.AddObserver(c.invalidateSchemaCacheOnSchemaUpdate)
+	listener.AddErrorObserver(c.expireSchemaCacheOnListenerError)
+	c.SetSchemaCacheTtl(0)
+
 	return nil
 }
 
+// invalidateSchemaCacheOnSchemaUpdate invalidates the schema metadata cache if the notification is a schema update
+func (c *LocalDbClient) invalidateSchemaCacheOnSchemaUpdate(notification *pgconn.Notification) {
+	n := &steampipeconfig.PostgresNotification{}
+	if err := json.Unmarshal([]byte(notification.Payload), n); err != nil {
+		log.Printf("[WARN] failed to parse notification: %s", err.Error())
+		return
+	}
+	if n.Type == steampipeconfig.PgNotificationSchemaUpdate {
+		log.Printf("[INFO] schema update notification received - invalidating the schema metadata cache")
+		c.InvalidateSchemaCache()
+	}
+}
+
+// expireSchemaCacheOnListenerError falls back to expiring the schema metadata cache if the notification listener stops,
+// as schema update notifications will no longer be received
+func (c *LocalDbClient) expireSchemaCacheOnListenerError(err error) {
+	log.Printf("[WARN] notification listener stopped (%s) - schema metadata will be reloaded every %s", err.Error(), db_client.DefaultSchemaCacheTtl)
+	c.InvalidateSchemaCache()
+	c.SetSchemaCacheTtl(db_client.DefaultSchemaCacheTtl)
+}
+
 // Close implements Client
 // close the connection to the database and shuts down the db service if we are the last connection
 func (c *LocalDbClient) Close(ctx context.Context) error {
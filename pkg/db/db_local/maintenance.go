@@ -0,0 +1,80 @@
+package db_local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// MaintenanceResult is the result of vacuuming and analyzing a table
+type MaintenanceResult struct {
+	Table    string
+	Duration time.Duration
+	Error    error
+}
+
+// MaintenanceResults is the result of a maintenance run
+type MaintenanceResults []*MaintenanceResult
+
+// Error returns the combined errors of the tables which failed, or nil if all succeeded
+func (r MaintenanceResults) Error() error {
+	var errs []error
+	for _, res := range r {
+		if res.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.Table, res.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunMaintenance runs VACUUM (ANALYZE) on the tables of the internal schema (connection state, schedule, plugin
+// tables etc.) and on all materialized views, so their storage is reclaimed and the planner statistics are current
+// (the scan metadata and settings tables of the internal schema are foreign tables, which hold no data, so are not included)
+//
+// the connection must be a superuser connection - each table is vacuumed separately, and a failure does not stop the run
+func RunMaintenance(ctx context.Context, conn *pgx.Conn) (MaintenanceResults, error) {
+	tables, err := getMaintenanceTables(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var res MaintenanceResults
+	for _, table := range tables {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		startTime := time.Now()
+		// VACUUM cannot run inside a transaction, so is executed directly
+		_, err := conn.Exec(ctx, fmt.Sprintf("VACUUM (ANALYZE) %s", table))
+		if err != nil {
+			log.Printf("[WARN] failed to vacuum %s: %s", table, err.Error())
+		}
+		res = append(res, &MaintenanceResult{Table: table, Duration: time.Since(startTime), Error: err})
+	}
+	log.Printf("[INFO] maintenance of %d tables complete", len(res))
+	return res, nil
+}
+
+// getMaintenanceTables returns the escaped qualified names of the internal tables and materialized views
+func getMaintenanceTables(ctx context.Context, conn *pgx.Conn) ([]string, error) {
+	rows, err := conn.Query(ctx, `SELECT schemaname, tablename FROM pg_tables WHERE schemaname = $1
+UNION ALL
+SELECT schemaname, matviewname FROM pg_matviews
+ORDER BY 1, 2`, constants.InternalSchema)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (string, error) {
+		var schema, table string
+		if err := row.Scan(&schema, &table); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.%s", db_common.PgEscapeName(schema), db_common.PgEscapeName(table)), nil
+	})
+}
@@ -0,0 +1,20 @@
+package db_local
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaintenanceResultsError(t *testing.T) {
+	res := MaintenanceResults{
+		{Table: "steampipe_internal.steampipe_connection"},
+		{Table: "steampipe_internal.steampipe_schedule", Error: errors.New("canceling statement due to user request")},
+	}
+	expected := "steampipe_internal.steampipe_schedule: canceling statement due to user request"
+	if err := res.Error(); err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+	if err := res[:1].Error(); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package db_local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+// errDbRestoreFailed is returned by postServiceStart if the backup of a previous database installation could not
+// be restored - the service is stopped and the migration rolled back
+var errDbRestoreFailed = errors.New("failed to restore the database backup")
+
+// dbMigration is a migration of the data of a previous database installation to the current database version
+//
+// the data is migrated by dumping the public schema of the previous installation with pg_dump when the new version
+// is installed, and restoring the dump with pg_restore when the service next starts
+// the previous installation is only removed once the restore succeeds - if any step fails, the migration is
+// rolled back by removing the new installation, so it is retried from the previous installation the next time
+// the service starts
+type dbMigration struct {
+	fromVersion  string
+	fromLocation string
+}
+
+// findDbMigration returns the migration required to move the data of a previous database installation
+// to the current version, or nil if there is no previous installation
+// a previous installation which has no data is removed, as there is nothing to migrate
+func findDbMigration(ctx context.Context) (*dbMigration, error) {
+	found, location, err := findDifferentPgInstallation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	if !hasDatabaseData(location) {
+		log.Printf("[INFO] previous database installation %s has no data - removing it", location)
+		if err := os.RemoveAll(location); err != nil {
+			return nil, err
+		}
+		// there may be more than one previous installation
+		return findDbMigration(ctx)
+	}
+	return &dbMigration{
+		fromVersion:  filepath.Base(location),
+		fromLocation: location,
+	}, nil
+}
+
+// hasDatabaseData returns whether the data directory of the database installation has been initialised
+func hasDatabaseData(location string) bool {
+	return files.FileExists(filepath.Join(location, "data", "PG_VERSION"))
+}
+
+// failed returns the error to show when the migration fails
+func (m *dbMigration) failed(err error) error {
+	return fmt.Errorf(`failed to migrate the Steampipe database from Postgres %s to Postgres %s: %w
+
+The previous installation has not been changed, and the migration will be retried the next time the service starts.
+To start with an empty database instead, move %s out of %s.`,
+		m.fromVersion, constants.DatabaseVersion, err, m.fromLocation, filepaths.EnsureDatabaseDir())
+}
+
+// rollbackDbMigration removes the installation of the current database version and any database backup
+// which has not been restored, leaving the previous installation in place
+// NOTE: the service must not be running
+func rollbackDbMigration() {
+	instanceDir := filepaths.DatabaseInstanceDir()
+	log.Printf("[INFO] rolling back database migration - removing %s", instanceDir)
+	if err := os.RemoveAll(instanceDir); err != nil {
+		log.Printf("[WARN] failed to remove the database installation %s: %s", instanceDir, err.Error())
+	}
+	if err := os.Remove(filepaths.DatabaseBackupFilePath()); err != nil && !os.IsNotExist(err) {
+		log.Printf("[WARN] failed to remove the database backup %s: %s", filepaths.DatabaseBackupFilePath(), err.Error())
+	}
+}
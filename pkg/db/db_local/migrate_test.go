@@ -0,0 +1,59 @@
+package db_local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestFindDbMigration(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+
+	createInstallation := func(version string, withData bool) string {
+		location := filepath.Join(filepaths.EnsureDatabaseDir(), version)
+		files := []string{filepath.Join(location, "postgres", "bin", "postgres")}
+		if withData {
+			files = append(files, filepath.Join(location, "data", "PG_VERSION"))
+		}
+		for _, f := range files {
+			if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(f, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return location
+	}
+
+	ctx := context.Background()
+	createInstallation(constants.DatabaseVersion, true)
+	if migration, err := findDbMigration(ctx); err != nil || migration != nil {
+		t.Fatalf("expected no migration for the current version, got %v (%v)", migration, err)
+	}
+
+	// a previous installation without data is removed
+	emptyLocation := createInstallation("12.1.0", false)
+	if migration, err := findDbMigration(ctx); err != nil || migration != nil {
+		t.Fatalf("expected no migration for an installation without data, got %v (%v)", migration, err)
+	}
+	if filehelpers.DirectoryExists(emptyLocation) {
+		t.Errorf("expected %s to be removed", emptyLocation)
+	}
+
+	location := createInstallation("12.1.0", true)
+	migration, err := findDbMigration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migration == nil || migration.fromVersion != "12.1.0" || migration.fromLocation != location {
+		t.Errorf("expected a migration from %s, got %+v", location, migration)
+	}
+}
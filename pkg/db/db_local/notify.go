@@ -9,6 +9,8 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
 )
 
 // SendPostgresNotification send a postgres notification that the schema has chganged
@@ -27,3 +29,24 @@ func SendPostgresNotification(_ context.Context, conn *pgx.Conn, notification an
 	}
 	return nil
 }
+
+// SendPluginInstalledNotification notifies the running service that plugins have been installed or updated,
+// so that it refreshes connections
+// if the service is not running, this is a no-op - connections will be refreshed when it next starts
+func SendPluginInstalledNotification(ctx context.Context, plugins []string) error {
+	state, err := GetState()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return nil
+	}
+
+	conn, err := CreateLocalDbConnection(ctx, &CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	return db_common.SendPostgresCommandNotification(ctx, conn, steampipeconfig.NewPluginInstalledNotification(plugins))
+}
@@ -0,0 +1,212 @@
+package db_local
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	psutils "github.com/shirou/gopsutil/process"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the kinds of service process which may be orphaned
+const (
+	orphanKindPostgres      = "postgres"
+	orphanKindPluginManager = "plugin manager"
+	orphanKindPlugin        = "plugin"
+)
+
+// OrphanedProcess is a service process of this installation which was left running by a previous run
+// which crashed or was killed, and which is not referenced by the service state files
+type OrphanedProcess struct {
+	Pid  int32
+	Kind string
+}
+
+// the minimum age of a process before it may be considered orphaned
+// (a process started by a concurrent steampipe run may not yet be recorded in the service state files)
+const orphanMinAge = time.Minute
+
+// ReapOrphanedProcesses finds and stops the orphaned service processes of this installation:
+//   - postgres processes using the data directory, if the service is not running
+//   - plugin manager processes for the install dir which are not the running plugin manager
+//   - plugin processes whose parent is not a plugin manager of this installation
+//
+// processes of other installations (i.e. with a different install dir) and processes which were started
+// less than a minute ago are never stopped
+// the service lock is held while reaping, so processes being started or installed by a concurrent steampipe
+// run are not stopped - if the lock is held by another process, nothing is reaped
+// a failure to stop a process is logged, and does not stop the others being reaped
+func ReapOrphanedProcesses(ctx context.Context) ([]OrphanedProcess, error) {
+	lock, err := utils.TryLockFile(filepaths.ServiceLockFilePath())
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		log.Printf("[INFO] the service is being started or installed by another process - not reaping orphaned processes")
+		return nil, nil
+	}
+	defer lock.Unlock()
+
+	return reapOrphanedProcesses(ctx)
+}
+
+// reapOrphanedProcesses stops the orphaned service processes - the caller must hold the service lock
+func reapOrphanedProcesses(ctx context.Context) ([]OrphanedProcess, error) {
+	orphans, err := findOrphanedProcesses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reaped []OrphanedProcess
+	for _, orphan := range orphans {
+		if err := stopOrphanedProcess(ctx, orphan); err != nil {
+			log.Printf("[WARN] failed to stop orphaned %s process %d: %s", orphan.kind, orphan.process.Pid, err.Error())
+			continue
+		}
+		log.Printf("[INFO] stopped orphaned %s process %d", orphan.kind, orphan.process.Pid)
+		reaped = append(reaped, OrphanedProcess{Pid: orphan.process.Pid, Kind: orphan.kind})
+	}
+	return reaped, nil
+}
+
+type orphanedProcess struct {
+	process *psutils.Process
+	kind    string
+}
+
+func findOrphanedProcesses(ctx context.Context) ([]*orphanedProcess, error) {
+	// if the service is running, the postgres process using the data directory is not an orphan
+	dbState, err := GetState()
+	if err != nil {
+		return nil, err
+	}
+	// the plugin manager referenced by the state file is not an orphan
+	var pluginManagerPid int32
+	pluginManagerState, err := pluginmanager.LoadState()
+	if err != nil {
+		return nil, err
+	}
+	if pluginManagerState != nil && pluginManagerState.Running {
+		pluginManagerPid = int32(pluginManagerState.Pid)
+	}
+
+	allProcesses, err := psutils.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// order the orphans so plugin managers are stopped before their plugins (so they are not restarted)
+	var managers, others []*orphanedProcess
+	for _, p := range allProcesses {
+		// the command line of processes owned by other users may not be readable - they cannot be ours
+		cmdLine, err := p.CmdlineSliceWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		kind := getServiceProcessKind(cmdLine)
+		if kind == "" || !isOlderThan(ctx, p, orphanMinAge) {
+			continue
+		}
+		switch kind {
+		case orphanKindPostgres:
+			if dbState == nil {
+				others = append(others, &orphanedProcess{process: p, kind: kind})
+			}
+		case orphanKindPluginManager:
+			if p.Pid != pluginManagerPid {
+				managers = append(managers, &orphanedProcess{process: p, kind: kind})
+			}
+		case orphanKindPlugin:
+			if !hasPluginManagerParent(ctx, p) {
+				others = append(others, &orphanedProcess{process: p, kind: kind})
+			}
+		}
+	}
+	return append(managers, others...), nil
+}
+
+// isOlderThan returns whether the process was started more than the given duration ago
+// if the start time cannot be determined, the process is assumed to be new
+func isOlderThan(ctx context.Context, p *psutils.Process, age time.Duration) bool {
+	createTime, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		return false
+	}
+	return time.Since(time.UnixMilli(createTime)) > age
+}
+
+// hasPluginManagerParent returns whether the parent of the (plugin) process is a plugin manager of this installation
+// (which may not be the running plugin manager, e.g. if it has not yet written its state file)
+// NOTE: a plugin whose parent is an orphaned plugin manager is stopped by that plugin manager
+func hasPluginManagerParent(ctx context.Context, p *psutils.Process) bool {
+	parent, err := p.ParentWithContext(ctx)
+	if err != nil {
+		return false
+	}
+	cmdLine, err := parent.CmdlineSliceWithContext(ctx)
+	if err != nil {
+		return false
+	}
+	return getServiceProcessKind(cmdLine) == orphanKindPluginManager
+}
+
+// getServiceProcessKind returns the kind of service process of this installation the command line belongs to,
+// or an empty string if it is not a service process of this installation
+func getServiceProcessKind(cmdLine []string) string {
+	if len(cmdLine) == 0 {
+		return ""
+	}
+	// only postgres processes using the data directory of this installation are included
+	if isSteampipePostgresProcess(context.Background(), cmdLine) && getArgValue(cmdLine, "-D") == filepaths.GetDataLocation() {
+		return orphanKindPostgres
+	}
+	if len(cmdLine) > 1 && cmdLine[1] == "plugin-manager" && getArgValue(cmdLine, "--"+constants.ArgInstallDir) == filepaths.SteampipeDir {
+		return orphanKindPluginManager
+	}
+	// plugins are only ever started by the plugin manager, from the plugin directory
+	if isWithinDir(filepaths.EnsurePluginDir(), cmdLine[0]) {
+		return orphanKindPlugin
+	}
+	return ""
+}
+
+// isWithinDir returns whether the (absolute) path is within the dir
+func isWithinDir(dir, path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// getArgValue returns the value following the given arg in the command line
+func getArgValue(cmdLine []string, arg string) string {
+	for i := 0; i < len(cmdLine)-1; i++ {
+		if cmdLine[i] == arg {
+			return cmdLine[i+1]
+		}
+	}
+	return ""
+}
+
+func stopOrphanedProcess(ctx context.Context, orphan *orphanedProcess) error {
+	switch orphan.kind {
+	case orphanKindPostgres:
+		return doThreeStepPostgresExit(ctx, orphan.process)
+	case orphanKindPluginManager:
+		// send a SIGTERM, to give the plugin manager a chance to stop its plugins
+		return orphan.process.SendSignal(syscall.SIGTERM)
+	default:
+		// the plugin may already have been stopped by its plugin manager
+		if running, err := orphan.process.IsRunningWithContext(ctx); err == nil && !running {
+			return nil
+		}
+		return orphan.process.Kill()
+	}
+}
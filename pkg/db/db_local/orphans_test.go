@@ -0,0 +1,58 @@
+package db_local
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestGetServiceProcessKind(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+
+	otherInstallDir := t.TempDir()
+	postgres := filepath.Join(filepaths.EnsureDatabaseDir(), "14.2.0", "postgres", "bin", "postgres")
+	testCases := map[string]struct {
+		cmdLine  []string
+		expected string
+	}{
+		"postgres": {
+			cmdLine:  []string{postgres, "-p", "9193", "-c", "application_name=steampipe", "-D", filepaths.GetDataLocation()},
+			expected: orphanKindPostgres,
+		},
+		"postgres of a previous version": {
+			cmdLine: []string{postgres, "-c", "application_name=steampipe", "-D", filepath.Join(filepaths.EnsureDatabaseDir(), "14.1.0", "data")},
+		},
+		"postgres of another install dir": {
+			cmdLine: []string{postgres, "-c", "application_name=steampipe", "-D", filepath.Join(otherInstallDir, "db", "14.2.0", "data")},
+		},
+		"postgres not started by steampipe": {
+			cmdLine: []string{"/usr/lib/postgresql/14/bin/postgres", "-D", filepaths.GetDataLocation()},
+		},
+		"plugin manager": {
+			cmdLine:  []string{"/usr/local/bin/steampipe", "plugin-manager", "--install-dir", filepaths.SteampipeDir},
+			expected: orphanKindPluginManager,
+		},
+		"plugin manager of another install dir": {
+			cmdLine: []string{"/usr/local/bin/steampipe", "plugin-manager", "--install-dir", otherInstallDir},
+		},
+		"plugin": {
+			cmdLine:  []string{filepath.Join(filepaths.EnsurePluginDir(), "hub.steampipe.io", "plugins", "turbot", "aws@latest", "steampipe-plugin-aws.plugin")},
+			expected: orphanKindPlugin,
+		},
+		"plugin of another install dir": {
+			cmdLine: []string{filepath.Join(otherInstallDir, "plugins", "hub.steampipe.io", "plugins", "turbot", "aws@latest", "steampipe-plugin-aws.plugin")},
+		},
+		"steampipe query": {
+			cmdLine: []string{"/usr/local/bin/steampipe", "query", "--install-dir", filepaths.SteampipeDir},
+		},
+		"empty": {},
+	}
+	for name, test := range testCases {
+		if res := getServiceProcessKind(test.cmdLine); res != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", name, test.expected, res)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package db_local
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// ProbeResult contains the health of the individual components of the local service
+type ProbeResult struct {
+	// is the postgres process running
+	DatabaseRunning bool
+	// is postgres accepting connections
+	DatabaseAccepting bool
+	// is the plugin manager process running
+	PluginManagerRunning bool
+	// have all connections finished loading (i.e. none are pending or updating)
+	ConnectionsLoaded bool
+	// count of connections in each state
+	ConnectionSummary steampipeconfig.ConnectionStateSummary
+	// the reasons for any failed checks
+	Failures []string
+}
+
+// Live returns whether the service processes are running
+func (r *ProbeResult) Live() bool {
+	return r.DatabaseRunning && r.PluginManagerRunning
+}
+
+// Ready returns whether the service is able to serve queries
+func (r *ProbeResult) Ready() bool {
+	return r.Live() && r.DatabaseAccepting && r.ConnectionsLoaded
+}
+
+func (r *ProbeResult) addFailure(format string, args ...any) {
+	r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+}
+
+// ProbeService checks the health of the database, the plugin manager and the connection state
+// if readiness is false, only the liveness checks are performed (i.e. are the processes running)
+// NOTE: this never starts the service
+func ProbeService(ctx context.Context, readiness bool) *ProbeResult {
+	res := &ProbeResult{}
+
+	dbState, err := GetState()
+	if err != nil {
+		res.addFailure("failed to get database state: %s", err.Error())
+	}
+	res.DatabaseRunning = dbState != nil
+	if dbState == nil && err == nil {
+		res.addFailure("database service is not running")
+	}
+
+	pmState, err := pluginmanager.LoadState()
+	if err != nil {
+		res.addFailure("failed to get plugin manager state: %s", err.Error())
+	}
+	res.PluginManagerRunning = pmState != nil && pmState.Running
+	if !res.PluginManagerRunning && err == nil {
+		res.addFailure("plugin manager is not running")
+	}
+
+	// if we are only checking liveness or the database is not running, we are done
+	if !readiness || !res.DatabaseRunning {
+		return res
+	}
+
+	conn, err := CreateLocalDbConnection(ctx, &CreateDbOptions{DatabaseName: dbState.Database, Username: constants.DatabaseSuperUser})
+	if err != nil {
+		res.addFailure("database is not accepting connections: %s", err.Error())
+		return res
+	}
+	defer conn.Close(ctx)
+	res.DatabaseAccepting = true
+
+	// load the connection state without waiting
+	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn)
+	if err != nil {
+		log.Printf("[WARN] ProbeService failed to load connection state: %s", err.Error())
+		res.addFailure("failed to load connection state: %s", err.Error())
+		return res
+	}
+	res.ConnectionSummary = connectionStateMap.GetSummary()
+	res.ConnectionsLoaded = connectionStateMap.Loaded()
+	if !res.ConnectionsLoaded {
+		res.addFailure("connection refresh in progress: %s", steampipeconfig.GetLoadingConnectionStatusMessage(connectionStateMap))
+	}
+	return res
+}
@@ -0,0 +1,133 @@
+package db_local
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+)
+
+// the port of a listener which has been closed, so connections to it are refused
+func getUnreachablePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	return port
+}
+
+func TestProbeService(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+
+	// the running services are faked by state files with the pid of this process
+	// dbPort is only used if the database is running - 0 means use a fake postgres server
+	testCases := map[string]struct {
+		readiness            bool
+		databaseRunning      bool
+		dbPort               int
+		pluginManagerRunning bool
+		connectionStates     map[string]string
+		expectedLive         bool
+		expectedReady        bool
+		expectedAccepting    bool
+		expectedFailure      string
+	}{
+		"not running": {
+			readiness:       true,
+			expectedFailure: "database service is not running",
+		},
+		"plugin manager not running": {
+			databaseRunning: true,
+			expectedFailure: "plugin manager is not running",
+		},
+		"live": {
+			databaseRunning:      true,
+			pluginManagerRunning: true,
+			expectedLive:         true,
+		},
+		"DB unreachable": {
+			readiness:            true,
+			databaseRunning:      true,
+			dbPort:               getUnreachablePort(t),
+			pluginManagerRunning: true,
+			expectedLive:         true,
+			expectedFailure:      "database is not accepting connections",
+		},
+		"connections loading": {
+			readiness:            true,
+			databaseRunning:      true,
+			pluginManagerRunning: true,
+			connectionStates:     map[string]string{"aws": constants.ConnectionStateReady, "gcp": constants.ConnectionStatePending},
+			expectedLive:         true,
+			expectedAccepting:    true,
+			expectedFailure:      "connection refresh in progress",
+		},
+		"healthy": {
+			readiness:            true,
+			databaseRunning:      true,
+			pluginManagerRunning: true,
+			connectionStates:     map[string]string{"aws": constants.ConnectionStateReady, "gcp": constants.ConnectionStateReady},
+			expectedLive:         true,
+			expectedAccepting:    true,
+			expectedReady:        true,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			filepaths.SteampipeDir = t.TempDir()
+			if err := ensureCertificates(); err != nil {
+				t.Fatalf("failed to create the service certificates: %s", err.Error())
+			}
+			if test.databaseRunning {
+				port := test.dbPort
+				if port == 0 {
+					port = newFakePostgres(t, test.connectionStates).port()
+				}
+				info := &RunningDBInstanceInfo{
+					Pid:                     os.Getpid(),
+					ResolvedListenAddresses: []string{"127.0.0.1"},
+					Port:                    port,
+					Invoker:                 constants.InvokerService,
+					Database:                "steampipe",
+				}
+				if err := info.Save(); err != nil {
+					t.Fatalf("failed to save the database state: %s", err.Error())
+				}
+			}
+			if test.pluginManagerRunning {
+				if err := (&pluginmanager.State{Pid: os.Getpid()}).Save(); err != nil {
+					t.Fatalf("failed to save the plugin manager state: %s", err.Error())
+				}
+			}
+
+			res := ProbeService(context.Background(), test.readiness)
+
+			if res.Live() != test.expectedLive {
+				t.Errorf("expected live %v, got %v (failures: %v)", test.expectedLive, res.Live(), res.Failures)
+			}
+			if res.Ready() != test.expectedReady {
+				t.Errorf("expected ready %v, got %v (failures: %v)", test.expectedReady, res.Ready(), res.Failures)
+			}
+			if res.DatabaseAccepting != test.expectedAccepting {
+				t.Errorf("expected database accepting %v, got %v", test.expectedAccepting, res.DatabaseAccepting)
+			}
+			if test.expectedFailure == "" {
+				if len(res.Failures) > 0 {
+					t.Errorf("expected no failures, got %v", res.Failures)
+				}
+				return
+			}
+			if len(res.Failures) == 0 || !strings.HasPrefix(res.Failures[0], test.expectedFailure) {
+				t.Errorf("expected failure '%s', got %v", test.expectedFailure, res.Failures)
+			}
+		})
+	}
+}
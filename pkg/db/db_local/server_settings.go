@@ -2,6 +2,7 @@ package db_local
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -25,6 +26,7 @@ func setupServerSettingsTable(ctx context.Context, conn *pgx.Conn) error {
 		CacheMaxTtl:      viper.GetInt(constants.ArgCacheMaxTtl),
 		CacheMaxSizeMb:   viper.GetInt(constants.ArgMaxCacheSizeMb),
 		CacheEnabled:     viper.GetBool(constants.ArgServiceCacheEnabled),
+		MaxQueryDuration: viper.GetInt(constants.ArgMaxQueryDuration),
 	}
 
 	queries := []db_common.QueryWithArgs{
@@ -32,6 +34,7 @@ func setupServerSettingsTable(ctx context.Context, conn *pgx.Conn) error {
 		serversettings.CreateServerSettingsTable(ctx),
 		serversettings.GrantsOnServerSettingsTable(ctx),
 		serversettings.GetPopulateServerSettingsSql(ctx, settings),
+		getMaxQueryDurationSql(),
 	}
 
 	log.Println("[TRACE] saved server settings:", settings)
@@ -39,3 +42,32 @@ func setupServerSettingsTable(ctx context.Context, conn *pgx.Conn) error {
 	_, err := ExecuteSqlWithArgsInTransaction(ctx, conn, queries...)
 	return err
 }
+
+// getMaxQueryDurationSql returns the sql to set the statement timeout of the non-superuser roles - the steampipe user
+// and the service users - to the max query duration in the server settings table (or to reset it if there is no limit)
+// the timeout is set on the roles, so it applies to every session of those roles, including those of other clients
+// NOTE: superuser sessions (used by the service itself) are not limited
+func getMaxQueryDurationSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{Query: fmt.Sprintf(`DO $$
+DECLARE
+	max_duration INTEGER;
+	role_name TEXT;
+BEGIN
+	SELECT max_query_duration INTO max_duration FROM %[1]s.%[2]s;
+	FOR role_name IN
+		SELECT rolname FROM pg_roles WHERE rolname = '%[3]s' AND NOT rolsuper
+		UNION
+		SELECT r.rolname FROM pg_roles r
+			JOIN pg_auth_members m ON m.member = r.oid
+			JOIN pg_roles g ON g.oid = m.roleid
+		WHERE g.rolname = '%[4]s' AND NOT r.rolsuper
+	LOOP
+		IF coalesce(max_duration, 0) > 0 THEN
+			EXECUTE format('ALTER ROLE %%I SET statement_timeout = %%L', role_name, max_duration || 's');
+		ELSE
+			EXECUTE format('ALTER ROLE %%I RESET statement_timeout', role_name);
+		END IF;
+	END LOOP;
+END
+$$;`, constants.InternalSchema, constants.ServerSettingsTable, constants.DatabaseUser, constants.DatabaseServiceUsersRole)}
+}
@@ -0,0 +1,30 @@
+package db_local
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetMaxQueryDurationSql(t *testing.T) {
+	query := getMaxQueryDurationSql().Query
+
+	expected := []string{
+		// the duration is read from the server settings table
+		"SELECT max_query_duration INTO max_duration FROM steampipe_internal.steampipe_server_settings",
+		// and applied to the steampipe user and the members of the service users role
+		"WHERE rolname = 'steampipe' AND NOT rolsuper",
+		"WHERE g.rolname = 'steampipe_service_users' AND NOT r.rolsuper",
+		"EXECUTE format('ALTER ROLE %I SET statement_timeout = %L', role_name, max_duration || 's')",
+		// or reset if there is no limit
+		"EXECUTE format('ALTER ROLE %I RESET statement_timeout', role_name)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(query, e) {
+			t.Errorf("expected the max query duration sql to contain:\n%s\ngot:\n%s", e, query)
+		}
+	}
+	// superusers are never limited
+	if strings.Contains(query, "'root'") {
+		t.Errorf("expected the max query duration not to be applied to the superuser")
+	}
+}
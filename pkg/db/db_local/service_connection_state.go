@@ -0,0 +1,29 @@
+package db_local
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// LoadServiceConnectionState loads the current connection state from the running service,
+// without starting the service or waiting for connections to load
+// if the service is not running, nil is returned
+func LoadServiceConnectionState(ctx context.Context) (steampipeconfig.ConnectionStateMap, error) {
+	dbState, err := GetState()
+	if err != nil {
+		return nil, err
+	}
+	if dbState == nil {
+		return nil, nil
+	}
+
+	conn, err := CreateLocalDbConnection(ctx, &CreateDbOptions{DatabaseName: dbState.Database, Username: constants.DatabaseSuperUser})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	return steampipeconfig.LoadConnectionState(ctx, conn)
+}
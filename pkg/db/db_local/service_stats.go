@@ -0,0 +1,156 @@
+package db_local
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// ActiveQuery is a query currently being executed by the service
+type ActiveQuery struct {
+	Pid             int
+	ApplicationName string
+	State           string
+	Duration        time.Duration
+	Query           string
+}
+
+// PoolUtilization is the usage of the client sessions of the service, relative to max_connections
+type PoolUtilization struct {
+	Sessions       int `json:"sessions"`
+	Active         int `json:"active"`
+	Idle           int `json:"idle"`
+	MaxConnections int `json:"max_connections"`
+	// the proportion of max_connections in use
+	Utilization float64 `json:"utilization"`
+}
+
+// ServiceStats contains the runtime statistics of the local service
+type ServiceStats struct {
+	ActiveQueries []ActiveQuery
+	// the proportion of database block reads which were served from the postgres buffer cache
+	// (this is not the steampipe query cache)
+	BufferCacheHitRatio float64
+	// count of connections in each state
+	ConnectionSummary steampipeconfig.ConnectionStateSummary
+	// have all connections finished loading (i.e. none are pending or updating)
+	ConnectionsLoaded bool
+	// a message describing the connections which are loading (if any)
+	LoadingMessage string
+	// the time the postgres server started
+	StartTime time.Time
+	Pool      PoolUtilization
+}
+
+// GetServiceStats returns the active queries, buffer cache hit ratio, pool utilization and connection refresh progress of the local service
+func GetServiceStats(ctx context.Context, databaseName string) (*ServiceStats, error) {
+	conn, err := CreateLocalDbConnection(ctx, &CreateDbOptions{DatabaseName: databaseName, Username: constants.DatabaseSuperUser})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	res := &ServiceStats{}
+	if res.ActiveQueries, err = getActiveQueries(ctx, conn); err != nil {
+		return nil, err
+	}
+	if res.BufferCacheHitRatio, err = getBufferCacheHitRatio(ctx, conn); err != nil {
+		return nil, err
+	}
+	if err := getPoolUtilization(ctx, conn, res); err != nil {
+		return nil, err
+	}
+
+	connectionStateMap, err := steampipeconfig.LoadConnectionState(ctx, conn)
+	if err != nil {
+		log.Printf("[WARN] GetServiceStats failed to load connection state: %s", err.Error())
+		return nil, err
+	}
+	res.ConnectionSummary = connectionStateMap.GetSummary()
+	res.ConnectionsLoaded = connectionStateMap.Loaded()
+	if !res.ConnectionsLoaded {
+		res.LoadingMessage = steampipeconfig.GetLoadingConnectionStatusMessage(connectionStateMap)
+	}
+	return res, nil
+}
+
+func getActiveQueries(ctx context.Context, conn *pgx.Conn) ([]ActiveQuery, error) {
+	query := `
+SELECT
+  pid,
+  application_name,
+  state,
+  extract(epoch from (now() - query_start)),
+  query
+FROM
+  pg_stat_activity
+WHERE
+  -- only client backends which are running a query
+  backend_type = $1
+  AND state <> 'idle'
+  AND query_start IS NOT NULL
+  -- exclude this connection
+  AND pid <> pg_backend_pid()
+ORDER BY query_start`
+
+	rows, err := conn.Query(ctx, query, "client backend")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []ActiveQuery
+	for rows.Next() {
+		var q ActiveQuery
+		var durationSeconds float64
+		if err := rows.Scan(&q.Pid, &q.ApplicationName, &q.State, &durationSeconds, &q.Query); err != nil {
+			return nil, err
+		}
+		q.Duration = time.Duration(durationSeconds * float64(time.Second))
+		res = append(res, q)
+	}
+	return res, rows.Err()
+}
+
+func getBufferCacheHitRatio(ctx context.Context, conn *pgx.Conn) (float64, error) {
+	query := `
+SELECT
+  coalesce(sum(blks_hit)::float / nullif(sum(blks_hit) + sum(blks_read), 0), 0)
+FROM
+  pg_stat_database`
+
+	var hitRatio float64
+	err := conn.QueryRow(ctx, query).Scan(&hitRatio)
+	return hitRatio, err
+}
+
+// getPoolUtilization populates the start time and pool utilization of the stats
+// (the session used to retrieve the stats is excluded)
+func getPoolUtilization(ctx context.Context, conn *pgx.Conn, stats *ServiceStats) error {
+	query := `
+SELECT
+  pg_postmaster_start_time(),
+  current_setting('max_connections')::int,
+  count(*),
+  count(*) FILTER (WHERE state = 'active'),
+  count(*) FILTER (WHERE state = 'idle')
+FROM
+  pg_stat_activity
+WHERE
+  backend_type = $1
+  AND pid <> pg_backend_pid()`
+
+	pool := &stats.Pool
+	err := conn.QueryRow(ctx, query, "client backend").Scan(&stats.StartTime, &pool.MaxConnections, &pool.Sessions, &pool.Active, &pool.Idle)
+	if err != nil {
+		return err
+	}
+	if pool.MaxConnections > 0 {
+		pool.Utilization = float64(pool.Sessions) / float64(pool.MaxConnections)
+	}
+	return nil
+}
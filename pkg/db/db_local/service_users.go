@@ -0,0 +1,207 @@
+package db_local
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// maxServiceUserNameLength is the maximum length of a postgres identifier
+const maxServiceUserNameLength = 63
+
+var serviceUserNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// ServiceUser is a database user added with 'steampipe service user add'
+//
+// service users may log in to the steampipe database with a password, and may only read from the schemas
+// of their connections - the grants are reapplied by an event trigger whenever a connection schema is recreated
+type ServiceUser struct {
+	Name        string    `json:"name"`
+	Connections []string  `json:"connections"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ValidateServiceUserName returns an error if the name may not be used for a service user
+func ValidateServiceUserName(name string) error {
+	if len(name) > maxServiceUserNameLength {
+		return sperr.New("service user name '%s' is too long - it must be at most %d characters", name, maxServiceUserNameLength)
+	}
+	if !serviceUserNameRegex.MatchString(name) {
+		return sperr.New("service user name '%s' is invalid - it must start with a lowercase letter or underscore, and contain only lowercase letters, digits and underscores", name)
+	}
+	// do not allow names which clash with the roles managed by steampipe or postgres
+	if name == constants.DatabaseSuperUser || name == "postgres" || strings.HasPrefix(name, "pg_") || strings.HasPrefix(name, "steampipe") {
+		return sperr.New("service user name '%s' is reserved", name)
+	}
+	return nil
+}
+
+// AddServiceUser creates a service user which may only read from the schemas of the given connections,
+// and returns its password - if no password is given, one is generated
+// the pg_hba config is rewritten and reloaded, so the user may log in from any host
+//
+// the connection must be a superuser connection to the steampipe database
+func AddServiceUser(ctx context.Context, conn *pgx.Conn, name, password string, connections []string) (string, error) {
+	if err := ValidateServiceUserName(name); err != nil {
+		return "", err
+	}
+	if len(connections) == 0 {
+		return "", sperr.New("at least one connection must be given")
+	}
+	var exists bool
+	if err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = $1)", name).Scan(&exists); err != nil {
+		return "", err
+	}
+	if exists {
+		return "", sperr.New("a database role named '%s' already exists", name)
+	}
+
+	// the connections must exist - but the schemas of connections which have not loaded yet may not,
+	// so access to those is granted by the event trigger when they are created
+	if err := validateServiceUserConnections(ctx, conn, connections); err != nil {
+		return "", err
+	}
+	schemas, err := getExistingSchemas(ctx, conn, connections)
+	if err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		password = generatePassword()
+	}
+
+	if _, err := ExecuteSqlWithArgsInTransaction(ctx, conn, getServiceUserAddSql(name, password, connections, schemas)...); err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to create service user '%s'", name)
+	}
+	log.Printf("[INFO] added service user '%s' with access to connections: %s", name, strings.Join(connections, ", "))
+
+	// installations which predate service users do not allow them to log in - so ensure the pg_hba config is current
+	var databaseName string
+	if err := conn.QueryRow(ctx, "SELECT current_database()").Scan(&databaseName); err != nil {
+		return "", err
+	}
+	if err := writePgHbaContent(databaseName, constants.DatabaseUser); err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to update the database authentication config")
+	}
+	if _, err := conn.Exec(ctx, "SELECT pg_reload_conf()"); err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to reload the database authentication config")
+	}
+	return password, nil
+}
+
+// RemoveServiceUser drops a service user, and all the privileges granted to it
+//
+// the connection must be a superuser connection to the steampipe database
+func RemoveServiceUser(ctx context.Context, conn *pgx.Conn, name string) error {
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s.%s WHERE name = $1)", constants.InternalSchema, constants.ServiceUserTable)
+	if err := conn.QueryRow(ctx, query, name).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sperr.New("service user '%s' does not exist", name)
+	}
+
+	escapedName := db_common.PgEscapeName(name)
+	queries := []db_common.QueryWithArgs{
+		{Query: fmt.Sprintf("DROP OWNED BY %s", escapedName)},
+		{Query: fmt.Sprintf("DROP ROLE %s", escapedName)},
+		{Query: fmt.Sprintf("DELETE FROM %s.%s WHERE name = $1", constants.InternalSchema, constants.ServiceUserTable), Args: []any{name}},
+	}
+	if _, err := ExecuteSqlWithArgsInTransaction(ctx, conn, queries...); err != nil {
+		return sperr.WrapWithMessage(err, "failed to remove service user '%s'", name)
+	}
+	log.Printf("[INFO] removed service user '%s'", name)
+	return nil
+}
+
+// ListServiceUsers returns the service users, sorted by name
+//
+// the connection must be a superuser connection to the steampipe database
+func ListServiceUsers(ctx context.Context, conn *pgx.Conn) ([]*ServiceUser, error) {
+	query := fmt.Sprintf("SELECT name, connections, created_at FROM %s.%s ORDER BY name", constants.InternalSchema, constants.ServiceUserTable)
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*ServiceUser, error) {
+		user := &ServiceUser{}
+		err := row.Scan(&user.Name, &user.Connections, &user.CreatedAt)
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// validateServiceUserConnections returns an error if any of the connections are not in the connection table
+func validateServiceUserConnections(ctx context.Context, conn *pgx.Conn, connections []string) error {
+	query := fmt.Sprintf("SELECT name FROM %s.%s WHERE name = ANY($1)", constants.InternalSchema, constants.ConnectionTable)
+	rows, err := conn.Query(ctx, query, connections)
+	if err != nil {
+		return err
+	}
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	foundMap := make(map[string]bool, len(found))
+	for _, name := range found {
+		foundMap[name] = true
+	}
+	var missing []string
+	for _, name := range connections {
+		if !foundMap[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return sperr.New("unknown %s: %s", utils.Pluralize("connection", len(missing)), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// getExistingSchemas returns those of the given schemas which exist
+func getExistingSchemas(ctx context.Context, conn *pgx.Conn, schemas []string) ([]string, error) {
+	rows, err := conn.Query(ctx, "SELECT nspname FROM pg_namespace WHERE nspname = ANY($1) ORDER BY nspname", schemas)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// getServiceUserAddSql returns the sql to create a service user, record its connections and grant it read access
+// to the (existing) schemas of the connections
+// the search path of the user is set to its connections, so tables may be referenced without a schema
+func getServiceUserAddSql(name, password string, connections, schemas []string) []db_common.QueryWithArgs {
+	escapedName := db_common.PgEscapeName(name)
+	queries := []db_common.QueryWithArgs{
+		{Query: fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD %s IN ROLE %s", escapedName, db_common.PgEscapeString(password), constants.DatabaseServiceUsersRole)},
+		{Query: fmt.Sprintf("ALTER ROLE %s SET search_path = %s", escapedName, strings.Join(db_common.PgEscapeSearchPath(connections), ", "))},
+		{
+			Query: fmt.Sprintf("INSERT INTO %s.%s (name, connections) VALUES ($1, $2)", constants.InternalSchema, constants.ServiceUserTable),
+			Args:  []any{name, connections},
+		},
+		// apply the max query duration of the running service to the new user
+		getMaxQueryDurationSql(),
+	}
+	for _, schema := range schemas {
+		escapedSchema := db_common.PgEscapeName(schema)
+		queries = append(queries,
+			db_common.QueryWithArgs{Query: fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", escapedSchema, escapedName)},
+			db_common.QueryWithArgs{Query: fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s GRANT SELECT ON TABLES TO %s", escapedSchema, escapedName)},
+			db_common.QueryWithArgs{Query: fmt.Sprintf("GRANT SELECT ON ALL TABLES IN SCHEMA %s TO %s", escapedSchema, escapedName)},
+		)
+	}
+	return queries
+}
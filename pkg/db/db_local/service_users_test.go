@@ -0,0 +1,49 @@
+package db_local
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateServiceUserName(t *testing.T) {
+	testCases := map[string]bool{
+		"analyst":                  true,
+		"_team_1":                  true,
+		"Analyst":                  false,
+		"1team":                    false,
+		"team-a":                   false,
+		"root":                     false,
+		"postgres":                 false,
+		"pg_reader":                false,
+		"steampipe_reader":         false,
+		strings.Repeat("a", 63):    true,
+		strings.Repeat("a", 64):    false,
+		`analyst"; drop role root`: false,
+	}
+	for name, valid := range testCases {
+		if err := ValidateServiceUserName(name); (err == nil) != valid {
+			t.Errorf("%s: expected valid=%v, got error %v", name, valid, err)
+		}
+	}
+}
+
+func TestGetServiceUserAddSql(t *testing.T) {
+	queries := getServiceUserAddSql("analyst", "secret", []string{"aws_prod", "aws_dev"}, []string{"aws_prod"})
+	var res []string
+	for _, q := range queries {
+		res = append(res, q.Query)
+	}
+	expected := []string{
+		`CREATE ROLE "analyst" LOGIN PASSWORD $steampipe_escape$secret$steampipe_escape$ IN ROLE steampipe_service_users`,
+		`ALTER ROLE "analyst" SET search_path = "aws_prod", "aws_dev"`,
+		`INSERT INTO steampipe_internal.steampipe_service_user (name, connections) VALUES ($1, $2)`,
+		getMaxQueryDurationSql().Query,
+		// only the schemas which exist are granted - the event trigger grants the others when they are created
+		`GRANT USAGE ON SCHEMA "aws_prod" TO "analyst"`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA "aws_prod" GRANT SELECT ON TABLES TO "analyst"`,
+		`GRANT SELECT ON ALL TABLES IN SCHEMA "aws_prod" TO "analyst"`,
+	}
+	if strings.Join(res, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(res, "\n"))
+	}
+}
@@ -3,6 +3,7 @@ package db_local
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -32,6 +33,9 @@ type StartResult struct {
 	DbState            *RunningDBInstanceInfo
 	PluginManagerState *pluginmanager.State
 	PluginManager      *pluginmanager.PluginManagerClient
+	// set if the service was started and nothing has changed since the last successful connection refresh
+	// - the connection state is still valid, so refresh connections is not required
+	SkipRefreshConnections bool
 }
 
 func (r *StartResult) SetError(err error) *StartResult {
@@ -86,8 +90,16 @@ func StartServices(ctx context.Context, listenAddresses []string, port int, invo
 	// if we were not successful, stop services again
 	defer func() {
 		if res.Status == ServiceStarted && res.Error != nil {
-			StopServices(ctx, false, invoker)
+			_, stopErr := StopServices(ctx, false, invoker)
 			res.Status = ServiceFailedToStart
+			// if the data of a previous database installation could not be restored, roll back the migration
+			// so it is retried from the previous installation the next time the service starts
+			if errors.Is(res.Error, errDbRestoreFailed) && stopErr == nil {
+				if migration, _ := findDbMigration(ctx); migration != nil {
+					rollbackDbMigration()
+					res.Error = migration.failed(res.Error)
+				}
+			}
 		}
 	}()
 
@@ -145,10 +157,14 @@ func ensurePluginManager(ctx context.Context) (*pluginmanager.PluginManagerClien
 
 	if !state.Running {
 		// get the location of the currently running steampipe process
-		executable, err := os.Executable()
-		if err != nil {
-			log.Printf("[WARN] plugin manager start() - failed to get steampipe executable path: %s", err)
-			return nil, nil, err
+		// (unless steampipe is embedded, in which case the steampipe executable is configured)
+		executable := viper.GetString(constants.ConfigKeySteampipeExecutable)
+		if executable == "" {
+			executable, err = os.Executable()
+			if err != nil {
+				log.Printf("[WARN] plugin manager start() - failed to get steampipe executable path: %s", err)
+				return nil, nil, err
+			}
 		}
 		if state, err = pluginmanager.StartNewInstance(executable); err != nil {
 			log.Printf("[WARN] StartServices plugin manager failed to start: %s", err)
@@ -179,9 +195,11 @@ func postServiceStart(ctx context.Context, res *StartResult) error {
 	// ensure connection state table contains entries for all connections in connection config
 	// (this is to allow for the race condition between polling connection state and calling refresh connections,
 	// which does not update the connection_state with added connections until it has built the ConnectionUpdates
-	if err := initializeConnectionStateTable(ctx, conn); err != nil {
+	refreshRequired, err := initializeConnectionStateTable(ctx, conn)
+	if err != nil {
 		return err
 	}
+	res.SkipRefreshConnections = !refreshRequired
 	if err := PopulatePluginTable(ctx, conn); err != nil {
 		return err
 	}
@@ -205,7 +223,7 @@ func postServiceStart(ctx context.Context, res *StartResult) error {
 
 	// if there is an unprocessed db backup file, restore it now
 	if err := restoreDBBackup(ctx); err != nil {
-		return sperr.WrapWithMessage(err, "failed to migrate db public schema")
+		return fmt.Errorf("failed to migrate db public schema: %w", err)
 	}
 
 	return nil
@@ -242,6 +260,22 @@ func startDB(ctx context.Context, listenAddresses []string, port int, invoker co
 	// remove the stale info file, ignoring errors - will overwrite anyway
 	_ = removeRunningInstanceInfo()
 
+	// hold the service lock until the service state is saved, so the postgres process we start
+	// is not reaped by a concurrent steampipe run
+	lock, err := utils.LockFile(filepaths.ServiceLockFilePath())
+	if err != nil {
+		return res.SetError(err)
+	}
+	defer lock.Unlock()
+
+	// stop any service processes left running by a previous run which crashed or was killed
+	// (an orphaned postgres process holds the lock on the data directory, so the service could not start)
+	if reaped, err := reapOrphanedProcesses(ctx); err != nil {
+		log.Printf("[WARN] failed to check for orphaned processes: %s", err.Error())
+	} else if len(reaped) > 0 {
+		error_helpers.ShowWarning(fmt.Sprintf("stopped %d %s left running by a previous Steampipe run", len(reaped), utils.Pluralize("process", len(reaped))))
+	}
+
 	if err := utils.EnsureDirectoryPermission(filepaths.GetDataLocation()); err != nil {
 		return res.SetError(fmt.Errorf("%s does not have the necessary permissions to start the service", filepaths.GetDataLocation()))
 	}
@@ -0,0 +1,87 @@
+package db_local
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/actionplan"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/constants/runtime"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+)
+
+// forceStopPlanSessionTimeout bounds listing the client sessions of the service, which may be hung
+const forceStopPlanSessionTimeout = 5 * time.Second
+
+// PlanForceStop adds the actions StopServices takes when forced to the plan:
+//   - the client sessions of the running service are terminated
+//   - the plugin manager (and its plugins) is stopped
+//   - every steampipe postgres process is stopped - including those of other installations
+//
+// a forced stop is used when the service is broken, so planning is best-effort:
+// a step which fails is logged and skipped, and never prevents the service being stopped
+func PlanForceStop(ctx context.Context, plan *actionplan.Plan) {
+	dbState, err := GetState()
+	if err != nil {
+		log.Printf("[WARN] failed to load the service state: %s", err.Error())
+	}
+	if dbState != nil {
+		sessionCtx, cancel := context.WithTimeout(ctx, forceStopPlanSessionTimeout)
+		if err := addTerminateSessionActions(sessionCtx, plan); err != nil {
+			log.Printf("[WARN] failed to list the client sessions of the service: %s", err.Error())
+		}
+		cancel()
+	}
+
+	pluginManagerState, err := pluginmanager.LoadState()
+	if err != nil {
+		log.Printf("[WARN] failed to load the plugin manager state: %s", err.Error())
+	}
+	if pluginManagerState != nil && pluginManagerState.Running {
+		plan.Add(actionplan.ActionStopProcess, strconv.Itoa(pluginManagerState.Pid), "plugin manager, and the plugins it started")
+	}
+
+	processes, err := FindAllSteampipePostgresInstances(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to list the steampipe postgres processes: %s", err.Error())
+	}
+	for _, p := range processes {
+		reason := "steampipe postgres"
+		if cmdLine, err := p.CmdlineSliceWithContext(ctx); err == nil {
+			if dataDir := getArgValue(cmdLine, "-D"); dataDir != "" {
+				reason = fmt.Sprintf("steampipe postgres, data directory %s", dataDir)
+			}
+		}
+		plan.Add(actionplan.ActionStopProcess, strconv.Itoa(int(p.Pid)), reason)
+	}
+}
+
+// addTerminateSessionActions adds an action for every client session of the running service
+// (other than those of this execution)
+func addTerminateSessionActions(ctx context.Context, plan *actionplan.Plan) error {
+	rootClient, err := CreateLocalDbConnection(ctx, &CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		return err
+	}
+	defer rootClient.Close(ctx)
+
+	query := `SELECT pid, coalesce(usename, ''), application_name, coalesce(host(client_addr), '')
+FROM pg_stat_activity
+WHERE client_port IS NOT NULL AND backend_type = $1 AND application_name != $2
+ORDER BY pid`
+	rows, err := rootClient.Query(ctx, query, "client backend", runtime.ClientConnectionAppName)
+	if err != nil {
+		return err
+	}
+	var pid int
+	var userName, appName, clientAddr string
+	_, err = pgx.ForEachRow(rows, []any{&pid, &userName, &appName, &clientAddr}, func() error {
+		plan.Add(actionplan.ActionTerminateSession, strconv.Itoa(pid), fmt.Sprintf("user %s, application '%s', client %s", userName, appName, clientAddr))
+		return nil
+	})
+	return err
+}
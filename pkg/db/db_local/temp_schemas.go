@@ -0,0 +1,59 @@
+package db_local
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// DropOrphanedTempSchemas drops the temporary schemas (pg_temp_N and pg_toast_temp_N) left behind by
+// client sessions which have disconnected.
+//
+// Postgres has no reliable way to map a temp schema to the session which owns it, so this is only done
+// if no other sessions are connected - this includes the sessions of the service itself (e.g. the plugin manager),
+// as these may also own temp schemas. If other sessions are connected, nothing is dropped and a skip reason is returned.
+func DropOrphanedTempSchemas(ctx context.Context) (dropped []string, skipReason string, err error) {
+	rootClient, err := CreateLocalDbConnection(ctx, &CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		return nil, "", err
+	}
+	defer rootClient.Close(ctx)
+
+	var clientCount int
+	err = rootClient.QueryRow(ctx, `select count(*) from pg_stat_activity
+where backend_type = 'client backend'
+  and pid <> pg_backend_pid()`).Scan(&clientCount)
+	if err != nil {
+		return nil, "", err
+	}
+	if clientCount > 0 {
+		log.Printf("[TRACE] DropOrphanedTempSchemas - %d clients connected, skipping", clientCount)
+		return nil, fmt.Sprintf("%d %s connected", clientCount, utils.Pluralize("session", clientCount)), nil
+	}
+
+	rows, err := rootClient.Query(ctx, `select nspname from pg_namespace
+where (nspname like 'pg\_temp\_%' or nspname like 'pg\_toast\_temp\_%')
+  and oid <> pg_my_temp_schema()
+order by nspname`)
+	if err != nil {
+		return nil, "", err
+	}
+	schemas, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, schema := range schemas {
+		if _, err := rootClient.Exec(ctx, fmt.Sprintf("drop schema if exists %s cascade", db_common.PgEscapeName(schema))); err != nil {
+			log.Printf("[TRACE] DropOrphanedTempSchemas - failed to drop schema %s: %s", schema, err)
+			return dropped, "", err
+		}
+		dropped = append(dropped, schema)
+	}
+	return dropped, "", nil
+}
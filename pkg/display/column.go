@@ -20,7 +20,13 @@ func ColumnNames(columns []*queryresult.ColumnDef) []string {
 	return colNames
 }
 
-type columnValueSettings struct{ nullString string }
+type columnValueSettings struct {
+	nullString string
+	// if set, the layout used to format timestamp values
+	timeFormat string
+	// if set, the location used to display timestamptz values
+	timeLocation *time.Location
+}
 
 type ColumnValueOption func(opt *columnValueSettings)
 
@@ -30,6 +36,15 @@ func WithNullString(nullString string) ColumnValueOption {
 	}
 }
 
+// WithTimeFormat sets the layout used to format timestamp values and the location used to display timestamptz values
+// an empty layout or nil location leaves the default behaviour unchanged
+func WithTimeFormat(layout string, location *time.Location) ColumnValueOption {
+	return func(opt *columnValueSettings) {
+		opt.timeFormat = layout
+		opt.timeLocation = location
+	}
+}
+
 // ColumnValuesAsString converts a slice of columns into strings
 func ColumnValuesAsString(values []interface{}, columns []*queryresult.ColumnDef, opts ...ColumnValueOption) ([]string, error) {
 	rowAsString := make([]string, len(columns))
@@ -69,9 +84,26 @@ func ColumnValueAsString(val interface{}, col *queryresult.ColumnDef, opts ...Co
 			return "", err
 		}
 		return string(bytes), nil
+	case "TIMESTAMPTZ":
+		t, ok := val.(time.Time)
+		if ok && (opt.timeFormat != "" || opt.timeLocation != nil) {
+			if opt.timeLocation != nil {
+				t = t.In(opt.timeLocation)
+			}
+			layout := opt.timeFormat
+			if layout == "" {
+				layout = "2006-01-02 15:04:05 MST"
+			}
+			return t.Format(layout), nil
+		}
+		return typeHelpers.ToString(val), nil
 	case "TIMESTAMP", "DATE", "TIME", "INTERVAL":
 		t, ok := val.(time.Time)
 		if ok {
+			// timestamps without a timezone cannot be converted to another location, but may use a custom format
+			if opt.timeFormat != "" && col.DataType == "TIMESTAMP" {
+				return t.Format(opt.timeFormat), nil
+			}
 			return t.Format("2006-01-02 15:04:05"), nil
 		}
 		fallthrough
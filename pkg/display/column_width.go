@@ -0,0 +1,47 @@
+package display
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// columnWidth returns the maximum width to use for the given column in table output,
+// and whether values wider than this should be truncated
+//
+// widths may be set per column (using the .width metaquery) or for all columns (using --max-col-width or .width)
+// if no width has been configured, the default max width is used and long values are wrapped rather than truncated
+func columnWidth(colName string) (int, bool) {
+	v := cmdconfig.Viper()
+	if widths := v.GetStringMap(constants.ConfigKeyColumnWidths); widths != nil {
+		// viper keys are case insensitive
+		if w, ok := widths[strings.ToLower(colName)].(int); ok && w > 0 {
+			return w, true
+		}
+	}
+	if v.IsSet(constants.ArgMaxColWidth) {
+		if w := v.GetInt(constants.ArgMaxColWidth); w > 0 {
+			return w, true
+		}
+	}
+	return constants.MaxColumnWidth, false
+}
+
+// truncateColumnValue truncates each line of the value to the given width,
+// adding a truncation indicator to any line which has been truncated
+func truncateColumnValue(val string, width int) string {
+	if width <= 0 {
+		return val
+	}
+	lines := strings.Split(val, "\n")
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) <= width {
+			continue
+		}
+		runes := []rune(line)
+		lines[i] = string(runes[:width-1]) + constants.TruncationIndicator
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package display
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestColumnWidth(t *testing.T) {
+	v := cmdconfig.Viper()
+	defer func() {
+		v.Set(constants.ArgMaxColWidth, nil)
+		v.Set(constants.ConfigKeyColumnWidths, nil)
+	}()
+
+	tests := map[string]struct {
+		maxColWidth      interface{}
+		columnWidths     map[string]interface{}
+		column           string
+		expectedWidth    int
+		expectedTruncate bool
+	}{
+		"not configured": {
+			column:        "name",
+			expectedWidth: constants.MaxColumnWidth,
+		},
+		"max width": {
+			maxColWidth:      30,
+			column:           "name",
+			expectedWidth:    30,
+			expectedTruncate: true,
+		},
+		"column width overrides max width": {
+			maxColWidth:      30,
+			columnWidths:     map[string]interface{}{"name": 10},
+			column:           "Name",
+			expectedWidth:    10,
+			expectedTruncate: true,
+		},
+		"other column uses max width": {
+			maxColWidth:      30,
+			columnWidths:     map[string]interface{}{"name": 10},
+			column:           "arn",
+			expectedWidth:    30,
+			expectedTruncate: true,
+		},
+		"auto": {
+			maxColWidth:   constants.MaxColumnWidthAuto,
+			column:        "name",
+			expectedWidth: constants.MaxColumnWidth,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v.Set(constants.ArgMaxColWidth, test.maxColWidth)
+			v.Set(constants.ConfigKeyColumnWidths, test.columnWidths)
+
+			width, truncate := columnWidth(test.column)
+			if width != test.expectedWidth || truncate != test.expectedTruncate {
+				t.Errorf("expected (%d, %v), got (%d, %v)", test.expectedWidth, test.expectedTruncate, width, truncate)
+			}
+		})
+	}
+}
+
+func TestTruncateColumnValue(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		width    int
+		expected string
+	}{
+		"short value":  {value: "abc", width: 5, expected: "abc"},
+		"exact width":  {value: "abcde", width: 5, expected: "abcde"},
+		"long value":   {value: "abcdef", width: 5, expected: "abcd…"},
+		"multi-byte":   {value: "äöüßéè", width: 4, expected: "äöü…"},
+		"each line":    {value: "abcdef\nab\nabcdefgh", width: 4, expected: "abc…\nab\nabc…"},
+		"no width":     {value: "abcdef", width: 0, expected: "abcdef"},
+		"width of one": {value: "abc", width: 1, expected: "…"},
+		"empty value":  {value: "", width: 3, expected: ""},
+	}
+	for name, test := range tests {
+		if actual := truncateColumnValue(test.value, test.width); actual != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", name, test.expected, actual)
+		}
+	}
+}
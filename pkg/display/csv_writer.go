@@ -0,0 +1,114 @@
+package display
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// csvWriter writes csv records, distinguishing null values from empty strings
+//
+// encoding/csv writes both null values and empty strings as an empty field, which is ambiguous
+// for consumers of the data. Instead, (in the same way as the postgres COPY command) null values are
+// written as an empty field and empty strings are written as a quoted empty field ("")
+// (the quoting may be changed by setting QuoteMode)
+type csvWriter struct {
+	// the field delimiter
+	Comma rune
+	// one of constants.CsvQuoteMinimal (the default), constants.CsvQuoteAll or constants.CsvQuoteNeeded
+	QuoteMode string
+	w         *bufio.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{
+		Comma:     ',',
+		QuoteMode: constants.CsvQuoteMinimal,
+		w:         bufio.NewWriter(w),
+	}
+}
+
+// Write writes a single csv record, where none of the fields are null
+func (w *csvWriter) Write(record []string) error {
+	return w.WriteWithNulls(record, nil)
+}
+
+// WriteWithNulls writes a single csv record - if nulls[i] is true, field i is written as a null value
+func (w *csvWriter) WriteWithNulls(record []string, nulls []bool) error {
+	for i, field := range record {
+		if i > 0 {
+			if _, err := w.w.WriteRune(w.Comma); err != nil {
+				return err
+			}
+		}
+		isNull := i < len(nulls) && nulls[i]
+		if _, err := w.w.WriteString(QuoteCSVField(field, isNull, w.Comma, w.QuoteMode)); err != nil {
+			return err
+		}
+	}
+	_, err := w.w.WriteRune('\n')
+	return err
+}
+
+// QuoteCSVField returns the field as it is written to csv with the given delimiter and quote mode
+func QuoteCSVField(field string, isNull bool, comma rune, quoteMode string) string {
+	quote := fieldNeedsQuotes(field, comma)
+	switch quoteMode {
+	case constants.CsvQuoteAll:
+		quote = quote || !isNull
+	case constants.CsvQuoteNeeded:
+		// nothing to add
+	default:
+		// empty strings are quoted to distinguish them from null values
+		quote = quote || (field == "" && !isNull)
+	}
+	if !quote {
+		return field
+	}
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// fieldNeedsQuotes reports whether our field must be enclosed in quotes
+// (this uses the same rules as encoding/csv)
+func fieldNeedsQuotes(field string, comma rune) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` {
+		return true
+	}
+	if strings.ContainsRune(field, comma) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
+
+// ParseCSVSeparator parses the value of --separator - a single character, or 'tab' (or \t) for a tab
+// if the value is empty, the default separator (a comma) is returned
+func ParseCSVSeparator(value string) (rune, error) {
+	switch value {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, fmt.Errorf("invalid --%s '%s' - must be a single character, or 'tab'", constants.ArgSeparator, value)
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	if r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid --%s '%s' - the separator cannot be a quote or newline", constants.ArgSeparator, value)
+	}
+	return r, nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (w *csvWriter) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,88 @@
+package display
+
+import (
+	"bytes"
+	"testing"
+)
+
+type csvWriterTest struct {
+	record    []string
+	nulls     []bool
+	comma     rune
+	quoteMode string
+	expected  string
+}
+
+var csvWriterTestCases = map[string]csvWriterTest{
+	"simple": {
+		record:   []string{"a", "b", "c"},
+		expected: "a,b,c\n",
+	},
+	"null and empty string": {
+		record:   []string{"a", "", ""},
+		nulls:    []bool{false, true, false},
+		expected: "a,,\"\"\n",
+	},
+	"null string value": {
+		record:   []string{"a", "<null>"},
+		nulls:    []bool{false, true},
+		expected: "a,<null>\n",
+	},
+	"quotes and separators": {
+		record:   []string{`say "hi"`, "a,b", "line1\nline2", " leading"},
+		expected: "\"say \"\"hi\"\"\",\"a,b\",\"line1\nline2\",\" leading\"\n",
+	},
+	"custom separator": {
+		record:   []string{"a,b", "c|d"},
+		comma:    '|',
+		expected: "a,b|\"c|d\"\n",
+	},
+	"quote all": {
+		record:    []string{"a", "", "", "say \"hi\""},
+		nulls:     []bool{false, true, false, false},
+		quoteMode: "all",
+		expected:  "\"a\",,\"\",\"say \"\"hi\"\"\"\n",
+	},
+	"quote needed": {
+		record:    []string{"a", "", "", "a,b"},
+		nulls:     []bool{false, true, false, false},
+		quoteMode: "needed",
+		expected:  "a,,,\"a,b\"\n",
+	},
+}
+
+func TestCSVWriter(t *testing.T) {
+	for name, test := range csvWriterTestCases {
+		var buf bytes.Buffer
+		w := newCSVWriter(&buf)
+		if test.comma != 0 {
+			w.Comma = test.comma
+		}
+		if test.quoteMode != "" {
+			w.QuoteMode = test.quoteMode
+		}
+		if err := w.WriteWithNulls(test.record, test.nulls); err != nil {
+			t.Fatalf("test '%s' failed: %s", name, err.Error())
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("test '%s' failed: %s", name, err.Error())
+		}
+		if actual := buf.String(); actual != test.expected {
+			t.Errorf("test '%s' failed: expected %q, got %q", name, test.expected, actual)
+		}
+	}
+}
+
+func TestParseCSVSeparator(t *testing.T) {
+	tests := map[string]rune{"": ',', ";": ';', "tab": '\t', `\t`: '\t', "|": '|'}
+	for value, expected := range tests {
+		if actual, err := ParseCSVSeparator(value); err != nil || actual != expected {
+			t.Errorf("ParseCSVSeparator(%q): expected %q, got %q (%v)", value, expected, actual, err)
+		}
+	}
+	for _, value := range []string{";;", `"`, "\n"} {
+		if _, err := ParseCSVSeparator(value); err == nil {
+			t.Errorf("ParseCSVSeparator(%q): expected an error", value)
+		}
+	}
+}
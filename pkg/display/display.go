@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -39,6 +38,11 @@ func ShowOutput(ctx context.Context, result *queryresult.Result, opts ...Display
 
 	var timingResult *queryresult.TimingResult
 
+	// if a stable order was requested, sort the rows before display
+	if cmdconfig.Viper().GetBool(constants.ArgStableOrder) {
+		result = result.Sorted()
+	}
+
 	outputFormat := cmdconfig.Viper().GetString(constants.ArgOutput)
 	switch outputFormat {
 	case constants.OutputFormatJSON:
@@ -184,6 +188,8 @@ func getTerminalColumnsRequiredForString(str string) int {
 }
 
 type jsonOutput struct {
+	// the column schema - only populated for typed output
+	Columns  []jsonColumnSchema        `json:"columns,omitempty"`
 	Rows     []map[string]interface{}  `json:"rows"`
 	Metadata *queryresult.TimingResult `json:"metadata,omitempty"`
 }
@@ -199,11 +205,19 @@ func displayJSON(ctx context.Context, result *queryresult.Result) (int, *queryre
 	rowErrors := 0
 	jsonOutput := newJSONOutput()
 
+	// if typed output is requested, include the column schema and preserve native types
+	typed := cmdconfig.Viper().GetBool(constants.ArgTyped)
+	parseValue := ParseJSONOutputColumnValue
+	if typed {
+		jsonOutput.Columns = columnSchemas(result.Cols)
+		parseValue = ParseTypedJSONOutputColumnValue
+	}
+
 	// define function to add each row to the JSON output
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
 		record := map[string]interface{}{}
 		for idx, col := range result.Cols {
-			value, _ := ParseJSONOutputColumnValue(row[idx], col)
+			value, _ := parseValue(row[idx], col)
 			record[col.Name] = value
 		}
 		jsonOutput.Rows = append(jsonOutput.Rows, record)
@@ -233,18 +247,33 @@ func displayJSON(ctx context.Context, result *queryresult.Result) (int, *queryre
 
 func displayCSV(ctx context.Context, result *queryresult.Result) (int, *queryresult.TimingResult) {
 	rowErrors := 0
-	csvWriter := csv.NewWriter(os.Stdout)
-	csvWriter.Comma = []rune(cmdconfig.Viper().GetString(constants.ArgSeparator))[0]
+	csvWriter := newCSVWriter(os.Stdout)
+	// the separator is validated by the query command, but may also be set by the .separator metaquery
+	if comma, err := ParseCSVSeparator(cmdconfig.Viper().GetString(constants.ArgSeparator)); err == nil {
+		csvWriter.Comma = comma
+	} else {
+		csvWriter.Comma = []rune(cmdconfig.Viper().GetString(constants.ArgSeparator))[0]
+	}
+	if quoteMode := cmdconfig.Viper().GetString(constants.ArgQuoteMode); quoteMode != "" {
+		csvWriter.QuoteMode = quoteMode
+	}
 
 	if cmdconfig.Viper().GetBool(constants.ArgHeader) {
 		_ = csvWriter.Write(ColumnNames(result.Cols))
 	}
 
+	// by default null values are written as an empty (unquoted) field
+	nullString := getNullString("")
+
 	// print the data as it comes
 	// define function display each csv row
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
-		rowAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(""))
-		_ = csvWriter.Write(rowAsString)
+		rowAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(nullString))
+		nulls := make([]bool, len(row))
+		for i, val := range row {
+			nulls[i] = val == nil
+		}
+		_ = csvWriter.WriteWithNulls(rowAsString, nulls)
 	}
 
 	// call this function for each row
@@ -255,9 +284,8 @@ func displayCSV(ctx context.Context, result *queryresult.Result) (int, *queryres
 		return rowErrors, nil
 	}
 
-	csvWriter.Flush()
-	if csvWriter.Error() != nil {
-		error_helpers.ShowErrorWithMessage(ctx, csvWriter.Error(), "unable to print csv")
+	if err := csvWriter.Flush(); err != nil {
+		error_helpers.ShowErrorWithMessage(ctx, err, "unable to print csv")
 	}
 
 	// now we have iterated the rows, get the timing
@@ -266,6 +294,15 @@ func displayCSV(ctx context.Context, result *queryresult.Result) (int, *queryres
 	return rowErrors, timingResult
 }
 
+// getNullString returns the string used to display null values
+// if a null string has been configured (using --null-string or .nullvalue) this is used, otherwise defaultValue is returned
+func getNullString(defaultValue string) string {
+	if cmdconfig.Viper().IsSet(constants.ArgNullString) {
+		return cmdconfig.Viper().GetString(constants.ArgNullString)
+	}
+	return defaultValue
+}
+
 func displayLine(ctx context.Context, result *queryresult.Result) (int, *queryresult.TimingResult) {
 
 	maxColNameLength, rowErrors := 0, 0
@@ -276,10 +313,12 @@ func displayLine(ctx context.Context, result *queryresult.Result) (int, *queryre
 		}
 	}
 	itemIdx := 0
+	nullString := getNullString(constants.NullString)
+	timeFormat := timeFormatOption()
 
 	// define a function to display each row
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
-		recordAsString, _ := ColumnValuesAsString(row, result.Cols)
+		recordAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(nullString), timeFormat)
 		requiredTerminalColumnsForValuesOfRecord := 0
 		for _, colValue := range recordAsString {
 			colRequired := getTerminalColumnsRequiredForString(colValue)
@@ -345,36 +384,62 @@ func displayTable(ctx context.Context, result *queryresult.Result) (int, *queryr
 	t.Style().Format.Header = text.FormatDefault
 
 	var colConfigs []table.ColumnConfig
-	headers := make(table.Row, len(result.Cols))
+	headers := make([]string, len(result.Cols))
+	// the max width of each column, and whether values should be truncated to this width
+	colWidths := make([]int, len(result.Cols))
+	colTruncate := make([]bool, len(result.Cols))
 
 	for idx, column := range result.Cols {
 		headers[idx] = column.Name
+		colWidths[idx], colTruncate[idx] = columnWidth(column.Name)
 		colConfigs = append(colConfigs, table.ColumnConfig{
 			Name:     column.Name,
 			Number:   idx + 1,
-			WidthMax: constants.MaxColumnWidth,
+			WidthMax: colWidths[idx],
 		})
 	}
 
 	t.SetColumnConfigs(colConfigs)
 	if viper.GetBool(constants.ArgHeader) {
-		t.AppendHeader(headers)
+		headerRow := make(table.Row, len(headers))
+		for idx, h := range headers {
+			headerRow[idx] = h
+		}
+		t.AppendHeader(headerRow)
 	}
 
+	// if the column widths are chosen to fit the terminal, the rows must all be read before the widths are known
+	autoWidth := isAutoColumnWidth()
+	var autoRows [][]string
+
+	nullString := getNullString(constants.NullString)
+	timeFormat := timeFormatOption()
+
 	// define a function to execute for each row
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
-		rowAsString, _ := ColumnValuesAsString(row, result.Cols)
-		rowObj := table.Row{}
-		for _, col := range rowAsString {
+		rowAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(nullString), timeFormat)
+		for idx, col := range rowAsString {
 			// trim out non-displayable code-points in string
 			// exfept white-spaces
-			col = strings.Map(func(r rune) rune {
+			rowAsString[idx] = strings.Map(func(r rune) rune {
 				if unicode.IsSpace(r) || unicode.IsGraphic(r) {
 					// return if this is a white space character
 					return r
 				}
 				return -1
 			}, col)
+		}
+		// if the widths are chosen to fit the terminal, the values are truncated once the column widths are known
+		if autoWidth {
+			autoRows = append(autoRows, rowAsString)
+			return
+		}
+		rowObj := table.Row{}
+		for idx, col := range rowAsString {
+			// if a column width has been configured, truncate rather than wrap long values
+			if colTruncate[idx] {
+				col = truncateColumnValue(col, colWidths[idx])
+			}
 			rowObj = append(rowObj, col)
 		}
 		t.AppendRow(rowObj)
@@ -389,8 +454,29 @@ func displayTable(ctx context.Context, result *queryresult.Result) (int, *queryr
 		rowErrors++
 		fmt.Println()
 	}
+
+	// for auto widths, now all rows have been read, choose the column widths to fit the terminal
+	footnote := ""
+	if autoWidth {
+		widths := layoutTableColumns(headers, autoRows, viper.GetBool(constants.ArgHeader))
+		for idx, w := range widths {
+			colConfigs[idx].WidthMax = w
+			colConfigs[idx].Hidden = w == 0
+		}
+		t.SetColumnConfigs(colConfigs)
+		for _, row := range autoRows {
+			rowObj := make(table.Row, len(row))
+			for idx, col := range row {
+				rowObj[idx] = truncateColumnValue(col, widths[idx])
+			}
+			t.AppendRow(rowObj)
+		}
+		footnote = hiddenColumnsFootnote(headers, widths)
+	}
+
 	// write out the table to the buffer
 	t.Render()
+	outbuf.WriteString(footnote)
 
 	// page out the table
 	ShowPaged(ctx, outbuf.String())
@@ -513,6 +599,18 @@ func getVerboseTimingString(sb *strings.Builder, p *message.Printer, timingResul
 
 		sb.WriteString(fmt.Sprintf("  %d…%d) Zero rows fetched.\n", scanCount+1, scanCount+emptyScanCount))
 	}
+
+	// if more than one connection was scanned, show the breakdown by connection
+	if len(timingResult.Connections) > 1 {
+		sb.WriteString("\nConnections:\n")
+		for _, c := range timingResult.Connections {
+			cacheString := ""
+			if c.CachedRowsFetched > 0 {
+				cacheString = p.Sprintf(" (%d cached)", c.CachedRowsFetched)
+			}
+			sb.WriteString(p.Sprintf("  %s: Scans: %d. Fetched: %d%s. Hydrates: %d. Scan time: %s.\n", c.Connection, c.ScanCount, c.RowsFetched, cacheString, c.HydrateCalls, getDurationString(c.DurationMs, p)))
+		}
+	}
 	return nil
 }
 
@@ -18,7 +18,9 @@ func (i PluginInstallReports) Swap(lIdx, rIdx int)      { i[lIdx], i[rIdx] = i[r
 func (i PluginInstallReports) Less(lIdx, rIdx int) bool { return i[lIdx].Plugin < i[rIdx].Plugin }
 
 type PluginInstallReport struct {
-	Skipped        bool
+	Skipped bool
+	// Failed is set if the plugin was not installed because of an error (the error is the SkipReason)
+	Failed         bool
 	Plugin         string
 	SkipReason     string
 	DocURL         string
@@ -33,6 +35,13 @@ func (i *PluginInstallReport) skipString() string {
 	return fmt.Sprintf("Plugin:   %s\nReason:   %s", fmt.Sprintf("%s@%s", name, constraint), i.SkipReason)
 }
 
+func (i *PluginInstallReport) failString() string {
+	ref := ociinstaller.NewSteampipeImageRef(i.Plugin)
+	_, name, constraint := ref.GetOrgNameAndConstraint()
+
+	return fmt.Sprintf("Plugin:   %s\nError:    %s", fmt.Sprintf("%s@%s", name, constraint), i.SkipReason)
+}
+
 func (i *PluginInstallReport) installString() string {
 	thisReport := []string{}
 	if i.IsUpdateReport {
@@ -109,7 +118,12 @@ func PrintInstallReports(reports PluginInstallReports, isUpdateReport bool) {
 
 	if len(installedOrUpdated) < len(reports) {
 		installSkipReports := []string{}
+		installFailReports := []string{}
 		for _, report := range reports {
+			if report.Failed {
+				installFailReports = append(installFailReports, report.failString())
+				continue
+			}
 			showReport := true
 			if report.SkipReason == constants.InstallMessagePluginAlreadyInstalled || report.SkipReason == constants.InstallMessagePluginLatestAlreadyInstalled {
 				showReport = false
@@ -119,6 +133,19 @@ func PrintInstallReports(reports PluginInstallReports, isUpdateReport bool) {
 			}
 		}
 
+		if failCount := len(installFailReports); failCount > 0 {
+			action := "install"
+			if isUpdateReport {
+				action = "update"
+			}
+			fmt.Printf(
+				"\nFailed to %s the following %s:\n\n%s\n",
+				action,
+				utils.Pluralize("plugin", failCount),
+				strings.Join(installFailReports, "\n\n"),
+			)
+		}
+
 		skipCount := len(installSkipReports)
 		if (len(installSkipReports)) > 0 {
 			fmt.Printf(
@@ -0,0 +1,140 @@
+package display
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the minimum width a column is truncated to when fitting the table to the terminal width
+// (a column is never narrower than its header)
+const autoMinColumnWidth = 12
+
+// ValidateMaxColumnWidth validates the value of --max-col-width - either a positive integer or 'auto'
+func ValidateMaxColumnWidth(value string) error {
+	if strings.EqualFold(value, constants.MaxColumnWidthAuto) {
+		return nil
+	}
+	if w, err := strconv.Atoi(value); err != nil || w < 1 {
+		return fmt.Errorf("invalid --%s '%s' - must be a positive integer or '%s'", constants.ArgMaxColWidth, value, constants.MaxColumnWidthAuto)
+	}
+	return nil
+}
+
+// isAutoColumnWidth returns whether the column widths of table output are chosen to fit the terminal width
+func isAutoColumnWidth() bool {
+	return strings.EqualFold(cmdconfig.Viper().GetString(constants.ArgMaxColWidth), constants.MaxColumnWidthAuto)
+}
+
+// columnPriorityOrder returns the indexes of the columns in the order in which they are given space:
+// the priority columns (in the order given), then the remaining columns in result order
+func columnPriorityOrder(headers []string, priorityColumns []string) []int {
+	order := make([]int, 0, len(headers))
+	added := make(map[int]bool, len(headers))
+	for _, p := range priorityColumns {
+		for idx, h := range headers {
+			if !added[idx] && strings.EqualFold(h, strings.TrimSpace(p)) {
+				order = append(order, idx)
+				added[idx] = true
+			}
+		}
+	}
+	for idx := range headers {
+		if !added[idx] {
+			order = append(order, idx)
+		}
+	}
+	return order
+}
+
+// fitColumnsToWidth returns the width of each column so that the table fits the available width
+//
+// columns are added in the given order at their minimum width until no more columns fit - the columns which
+// do not fit are hidden and have a width of zero (the first column is always shown). The remaining space is then
+// given to the visible columns, in order, up to their natural width
+// if the available width is not known (zero), the natural widths are returned
+func fitColumnsToWidth(naturalWidths, minWidths, order []int, available int) []int {
+	widths := make([]int, len(naturalWidths))
+	if available <= 0 {
+		copy(widths, naturalWidths)
+		return widths
+	}
+
+	// account for the table borders, and the separator and padding of each column
+	used := 1
+	for _, idx := range order {
+		required := minWidths[idx] + 3
+		if used+required > available && used > 1 {
+			continue
+		}
+		widths[idx] = minWidths[idx]
+		used += required
+	}
+
+	remaining := available - used
+	for _, idx := range order {
+		if widths[idx] == 0 || remaining <= 0 {
+			continue
+		}
+		extra := naturalWidths[idx] - widths[idx]
+		if extra > remaining {
+			extra = remaining
+		}
+		widths[idx] += extra
+		remaining -= extra
+	}
+	return widths
+}
+
+// layoutTableColumns returns the width of each column of table output with --max-col-width auto
+// rows contain the (display) values of the columns
+func layoutTableColumns(headers []string, rows [][]string, showHeader bool) []int {
+	naturalWidths := make([]int, len(headers))
+	minWidths := make([]int, len(headers))
+	for idx, header := range headers {
+		headerWidth := 0
+		if showHeader {
+			headerWidth = getTerminalColumnsRequiredForString(header)
+		}
+		natural := headerWidth
+		for _, row := range rows {
+			if w := getTerminalColumnsRequiredForString(row[idx]); w > natural {
+				natural = w
+			}
+		}
+		// a width configured for the column (using the .width metaquery) limits its width
+		if w, truncate := columnWidth(header); truncate && natural > w {
+			natural = w
+		}
+		// truncated columns require space for at least one character and the truncation indicator
+		natural = max(natural, 2)
+		naturalWidths[idx] = natural
+		minWidths[idx] = min(natural, max(headerWidth, autoMinColumnWidth))
+	}
+
+	order := columnPriorityOrder(headers, cmdconfig.Viper().GetStringSlice(constants.ArgPriorityColumns))
+	return fitColumnsToWidth(naturalWidths, minWidths, order, GetMaxCols())
+}
+
+// hiddenColumnsFootnote returns the footnote shown below table output listing the columns which were hidden
+// to fit the terminal width (or an empty string if no columns were hidden)
+func hiddenColumnsFootnote(headers []string, widths []int) string {
+	var hidden []string
+	for idx, w := range widths {
+		if w == 0 {
+			hidden = append(hidden, headers[idx])
+		}
+	}
+	if len(hidden) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d %s hidden to fit the terminal width: %s (use --%s to choose the columns shown, or json output to see all columns)\n",
+		len(hidden),
+		utils.Pluralize("column", len(hidden)),
+		strings.Join(hidden, ", "),
+		constants.ArgPriorityColumns)
+}
@@ -0,0 +1,59 @@
+package display
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnPriorityOrder(t *testing.T) {
+	headers := []string{"arn", "name", "region", "tags"}
+	got := columnPriorityOrder(headers, []string{"Tags", "name", "missing"})
+	want := []int{3, 1, 0, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("columnPriorityOrder = %v, want %v", got, want)
+	}
+}
+
+func TestFitColumnsToWidth(t *testing.T) {
+	tests := map[string]struct {
+		natural   []int
+		min       []int
+		order     []int
+		available int
+		want      []int
+	}{
+		"all columns fit at their natural width": {
+			natural: []int{10, 20}, min: []int{10, 12}, order: []int{0, 1}, available: 80,
+			want: []int{10, 20},
+		},
+		"columns are truncated to fit": {
+			// 1 + (12+3) + (12+3) = 31 at min width, leaving 9 for the first column
+			natural: []int{40, 40}, min: []int{12, 12}, order: []int{0, 1}, available: 40,
+			want: []int{21, 12},
+		},
+		"columns which do not fit are hidden": {
+			natural: []int{40, 40, 40}, min: []int{12, 12, 12}, order: []int{0, 1, 2}, available: 40,
+			want: []int{21, 12, 0},
+		},
+		"priority columns are shown first": {
+			natural: []int{40, 40, 40}, min: []int{12, 12, 12}, order: []int{2, 0, 1}, available: 40,
+			want: []int{12, 0, 21},
+		},
+		"the first column is always shown": {
+			natural: []int{40}, min: []int{30}, order: []int{0}, available: 20,
+			want: []int{30},
+		},
+		"unknown width": {
+			natural: []int{40, 40}, min: []int{12, 12}, order: []int{0, 1}, available: 0,
+			want: []int{40, 40},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := fitColumnsToWidth(test.natural, test.min, test.order, test.available)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("fitColumnsToWidth = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package display
+
+import (
+	"log"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// timestampFormat returns the Go layout for the given timestamp format,
+// which may be either a named format (e.g. 'rfc3339') or a Go layout string
+// if no format is set, an empty layout is returned so the default format is used
+func timestampFormat(format string) string {
+	if format == "" {
+		return ""
+	}
+	layout, err := utils.TimestampLayout(format)
+	if err != nil {
+		// this should have been validated already
+		log.Printf("[WARN] %s", err.Error())
+		return ""
+	}
+	return layout
+}
+
+// timeFormatOption returns the ColumnValueOption to apply the configured timezone and timestamp format
+// NOTE: this is only used for table and line output - machine readable formats always use the raw timestamp
+func timeFormatOption() ColumnValueOption {
+	location, err := utils.ParseTimezone(cmdconfig.Viper().GetString(constants.ArgTimezone))
+	if err != nil {
+		// this should have been validated already
+		log.Printf("[WARN] invalid timezone: %s", err.Error())
+		location = nil
+	}
+	return WithTimeFormat(timestampFormat(cmdconfig.Viper().GetString(constants.ArgTimestampFormat)), location)
+}
@@ -0,0 +1,72 @@
+package display
+
+import (
+	"time"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// the JSON types reported in the column schema of typed json output
+const (
+	jsonTypeBoolean = "boolean"
+	jsonTypeNumber  = "number"
+	jsonTypeString  = "string"
+	jsonTypeJSON    = "json"
+)
+
+// jsonColumnSchema describes a column in typed json output
+type jsonColumnSchema struct {
+	Name string `json:"name"`
+	// the postgres data type of the column
+	DataType string `json:"data_type"`
+	// the JSON type used to represent values of the column
+	JSONType string `json:"json_type"`
+}
+
+func columnSchemas(cols []*queryresult.ColumnDef) []jsonColumnSchema {
+	res := make([]jsonColumnSchema, len(cols))
+	for i, c := range cols {
+		res[i] = jsonColumnSchema{
+			Name:     c.Name,
+			DataType: c.DataType,
+			JSONType: jsonTypeForDataType(c.DataType),
+		}
+	}
+	return res
+}
+
+// jsonTypeForDataType returns the JSON type used to represent a postgres data type in typed json output
+func jsonTypeForDataType(dataType string) string {
+	switch dataType {
+	case "BOOL":
+		return jsonTypeBoolean
+	case "INT2", "INT4", "INT8", "FLOAT4", "FLOAT8", "NUMERIC":
+		return jsonTypeNumber
+	case "JSON", "JSONB":
+		return jsonTypeJSON
+	default:
+		return jsonTypeString
+	}
+}
+
+// ParseTypedJSONOutputColumnValue returns the value to use for typed json output
+// numerics, booleans and json are returned natively, and timestamps are formatted as RFC3339
+// (preserving the timezone and fractional seconds) - all other types are converted to strings
+func ParseTypedJSONOutputColumnValue(val interface{}, col *queryresult.ColumnDef) (interface{}, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	switch jsonTypeForDataType(col.DataType) {
+	case jsonTypeBoolean, jsonTypeNumber, jsonTypeJSON:
+		return val, nil
+	}
+
+	if t, ok := val.(time.Time); ok {
+		if col.DataType == "DATE" {
+			return t.Format(time.DateOnly), nil
+		}
+		return t.Format(time.RFC3339Nano), nil
+	}
+	return ColumnValueAsString(val, col)
+}
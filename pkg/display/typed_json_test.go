@@ -0,0 +1,147 @@
+package display
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+func TestJsonTypeForDataType(t *testing.T) {
+	tests := map[string]string{
+		"BOOL":        jsonTypeBoolean,
+		"INT2":        jsonTypeNumber,
+		"INT8":        jsonTypeNumber,
+		"FLOAT8":      jsonTypeNumber,
+		"NUMERIC":     jsonTypeNumber,
+		"JSONB":       jsonTypeJSON,
+		"JSON":        jsonTypeJSON,
+		"TEXT":        jsonTypeString,
+		"TIMESTAMPTZ": jsonTypeString,
+		"INET":        jsonTypeString,
+		"_TEXT":       jsonTypeString,
+	}
+	for dataType, expected := range tests {
+		if actual := jsonTypeForDataType(dataType); actual != expected {
+			t.Errorf("jsonTypeForDataType(%s): expected %s, got %s", dataType, expected, actual)
+		}
+	}
+}
+
+func TestParseTypedJSONOutputColumnValue(t *testing.T) {
+	timestamp := time.Date(2024, 3, 7, 9, 5, 2, 123000000, time.FixedZone("", 2*60*60))
+	tests := map[string]struct {
+		value    interface{}
+		dataType string
+		expected interface{}
+	}{
+		"null":              {value: nil, dataType: "TEXT", expected: nil},
+		"bool":              {value: true, dataType: "BOOL", expected: true},
+		"int":               {value: int64(42), dataType: "INT8", expected: int64(42)},
+		"float":             {value: 1.5, dataType: "FLOAT8", expected: 1.5},
+		"json":              {value: map[string]interface{}{"a": 1}, dataType: "JSONB", expected: map[string]interface{}{"a": 1}},
+		"text":              {value: "abc", dataType: "TEXT", expected: "abc"},
+		"timestamptz":       {value: timestamp, dataType: "TIMESTAMPTZ", expected: "2024-03-07T09:05:02.123+02:00"},
+		"timestamp in utc":  {value: timestamp.UTC(), dataType: "TIMESTAMP", expected: "2024-03-07T07:05:02.123Z"},
+		"date":              {value: timestamp, dataType: "DATE", expected: "2024-03-07"},
+		"whole second time": {value: time.Date(2024, 3, 7, 9, 5, 2, 0, time.UTC), dataType: "TIMESTAMPTZ", expected: "2024-03-07T09:05:02Z"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseTypedJSONOutputColumnValue(test.value, &queryresult.ColumnDef{Name: "c", DataType: test.dataType})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDisplayTypedJSON(t *testing.T) {
+	v := cmdconfig.Viper()
+	defer func() {
+		v.Set(constants.ArgTyped, nil)
+		v.Set(constants.ArgTiming, nil)
+	}()
+	v.Set(constants.ArgTiming, constants.ArgOff)
+
+	cols := []*queryresult.ColumnDef{
+		{Name: "id", DataType: "INT8"},
+		{Name: "name", DataType: "TEXT"},
+		{Name: "active", DataType: "BOOL"},
+	}
+	row := []interface{}{int64(1), "one", true}
+
+	tests := map[string]struct {
+		typed           bool
+		expectedColumns []jsonColumnSchema
+	}{
+		"typed": {
+			typed: true,
+			expectedColumns: []jsonColumnSchema{
+				{Name: "id", DataType: "INT8", JSONType: jsonTypeNumber},
+				{Name: "name", DataType: "TEXT", JSONType: jsonTypeString},
+				{Name: "active", DataType: "BOOL", JSONType: jsonTypeBoolean},
+			},
+		},
+		"not typed": {
+			typed: false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v.Set(constants.ArgTyped, test.typed)
+
+			result := queryresult.NewResult(cols)
+			go func() {
+				result.StreamRow(row)
+				result.Close()
+			}()
+			output := captureStdout(t, func() { displayJSON(context.Background(), result) })
+
+			var res struct {
+				Columns []jsonColumnSchema       `json:"columns"`
+				Rows    []map[string]interface{} `json:"rows"`
+			}
+			if err := json.Unmarshal(output, &res); err != nil {
+				t.Fatalf("failed to parse output %s: %s", output, err.Error())
+			}
+			if !reflect.DeepEqual(res.Columns, test.expectedColumns) {
+				t.Errorf("expected columns %v, got %v", test.expectedColumns, res.Columns)
+			}
+			expectedRows := []map[string]interface{}{{"id": float64(1), "name": "one", "active": true}}
+			if !reflect.DeepEqual(res.Rows, expectedRows) {
+				t.Errorf("expected rows %v, got %v", expectedRows, res.Rows)
+			}
+		})
+	}
+}
+
+// captureStdout returns everything written to stdout by f
+func captureStdout(t *testing.T, f func()) []byte {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	outputChan := make(chan []byte)
+	go func() {
+		output, _ := io.ReadAll(r)
+		outputChan <- output
+	}()
+	f()
+	w.Close()
+	return <-outputChan
+}
@@ -25,7 +25,7 @@ func ShowVarsListJson(vars []*modconfig.Variable) {
 			Type:        v.TypeString,
 			Description: v.GetDescription(),
 			Default:     v.DefaultGo,
-			Value:       v.ValueGo,
+			Value:       v.DisplayValueGo(),
 			ModName:     v.ModName,
 		}
 		jsonStructs = append(jsonStructs, jv)
@@ -40,7 +40,7 @@ func ShowVarsListTable(vars []*modconfig.Variable) {
 	headers := []string{"mod_name", "name", "description", "value", "value_default", "type"}
 	var rows = make([][]string, len(vars))
 	for i, v := range vars {
-		rows[i] = []string{v.ModName, v.ShortName, v.GetDescription(), fmt.Sprintf("%v", v.ValueGo), fmt.Sprintf("%v", v.DefaultGo), v.TypeString}
+		rows[i] = []string{v.ModName, v.ShortName, v.GetDescription(), fmt.Sprintf("%v", v.DisplayValueGo()), fmt.Sprintf("%v", v.DefaultGo), v.TypeString}
 	}
 	ShowWrappedTable(headers, rows, &ShowWrappedTableOptions{AutoMerge: false})
 }
@@ -0,0 +1,332 @@
+// Package ephemeral runs a self-contained steampipe instance - the embedded database and plugin manager -
+// in its own install directory, for use by integration tests of mods and plugins and by programs which embed steampipe
+//
+// the instance does not use or change the default install directory (~/.steampipe),
+// and everything it creates is removed when it is closed
+package ephemeral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/otiai10/copy"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// the install dir and config are global, so only one instance may run in a process at a time
+var instanceLock sync.Mutex
+
+// Instance is a running ephemeral steampipe instance
+type Instance struct {
+	InstallDir string
+	Port       int
+
+	client           *db_local.LocalDbClient
+	closed           bool
+	removeInstallDir bool
+	prevInstallDir   string
+	prevConfig       *steampipeconfig.SteampipeConfig
+	// the viper values changed by the instance, keyed by viper key, which are restored when it is closed
+	prevViper map[string]prevViperValue
+}
+
+type prevViperValue struct {
+	value     any
+	isDefault bool
+}
+
+// Start installs (if needed) and starts the database and plugin manager in a new install directory,
+// and waits for all connections to be loaded
+// an error is returned if any connection fails to load
+func Start(ctx context.Context, opts ...Option) (_ *Instance, err error) {
+	config := &instanceConfig{configFiles: make(map[string]string)}
+	for _, o := range opts {
+		o(config)
+	}
+
+	if !instanceLock.TryLock() {
+		return nil, errors.New("an ephemeral steampipe instance is already running in this process")
+	}
+	i := &Instance{
+		prevInstallDir: filepaths.SteampipeDir,
+		prevConfig:     steampipeconfig.GlobalConfig,
+		prevViper:      make(map[string]prevViperValue),
+	}
+	defer func() {
+		if err != nil {
+			if closeErr := i.Close(ctx); closeErr != nil {
+				log.Printf("[WARN] failed to clean up ephemeral instance: %s", closeErr.Error())
+			}
+		}
+	}()
+
+	if err := i.initInstallDir(config); err != nil {
+		return nil, err
+	}
+	if err := i.initViper(config); err != nil {
+		return nil, err
+	}
+
+	// load the connection config from the new install dir
+	steampipeConfig, ew := steampipeconfig.LoadSteampipeConfig(ctx, "", "query")
+	if ew.GetError() != nil {
+		return nil, ew.GetError()
+	}
+	steampipeconfig.GlobalConfig = steampipeConfig
+	configMap := steampipeConfig.ConfigMap()
+	for key := range configMap {
+		i.recordViper(key, true)
+	}
+	cmdconfig.SetDefaultsFromConfig(configMap)
+
+	client, ew := db_local.GetLocalClient(ctx, constants.InvokerQuery, nil)
+	if ew.GetError() != nil {
+		return nil, ew.GetError()
+	}
+	i.client = client
+
+	if err := i.waitForConnections(ctx); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// Client returns the database client of the instance
+func (i *Instance) Client() db_common.Client {
+	return i.client
+}
+
+// Query executes the query and returns all of its rows
+func (i *Instance) Query(ctx context.Context, query string, args ...any) (*queryresult.SyncQueryResult, error) {
+	return i.client.ExecuteSync(ctx, query, args...)
+}
+
+// Close stops the database and plugin manager, removes the install directory (unless it was provided by WithInstallDir)
+// and restores the install dir and config of the process
+func (i *Instance) Close(ctx context.Context) error {
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+	defer instanceLock.Unlock()
+
+	var errs []error
+	if i.client != nil {
+		// closing the client shuts down the service (as it was started by the client)
+		if err := i.client.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		i.client = nil
+	}
+	// make sure the service is stopped, even if it was not started by the client
+	if i.InstallDir != "" {
+		if state, _ := db_local.GetState(); state != nil {
+			if _, err := db_local.StopServices(ctx, true, constants.InvokerQuery); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	filepaths.SteampipeDir = i.prevInstallDir
+	steampipeconfig.GlobalConfig = i.prevConfig
+	i.restoreViper()
+
+	if i.removeInstallDir {
+		if err := os.RemoveAll(i.InstallDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (i *Instance) initInstallDir(config *instanceConfig) error {
+	i.InstallDir = config.installDir
+	if i.InstallDir == "" {
+		installDir, err := os.MkdirTemp("", "steampipe-ephemeral-")
+		if err != nil {
+			return err
+		}
+		i.InstallDir = installDir
+		i.removeInstallDir = true
+	}
+	filepaths.SteampipeDir = i.InstallDir
+
+	if config.pluginsFrom != "" {
+		if err := linkPlugins(config.pluginsFrom, i.InstallDir); err != nil {
+			return sperr.WrapWithMessage(err, "failed to use plugins from %s", config.pluginsFrom)
+		}
+	}
+	if config.databaseFrom != "" {
+		if err := copyDatabase(config.databaseFrom, i.InstallDir); err != nil {
+			return sperr.WrapWithMessage(err, "failed to use database from %s", config.databaseFrom)
+		}
+	}
+
+	configDir := filepaths.EnsureConfigDir()
+	for fileName, content := range config.configFiles {
+		if err := os.WriteFile(filepath.Join(configDir, fileName), []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Instance) initViper(config *instanceConfig) error {
+	executable := config.executable
+	if executable == "" {
+		var err error
+		executable, err = os.Executable()
+		if err != nil {
+			return sperr.WrapWithMessage(err, "failed to determine the steampipe executable used to run the plugin manager - set its path with WithExecutable")
+		}
+	}
+
+	i.Port = config.port
+	if i.Port == 0 {
+		port, err := freePort()
+		if err != nil {
+			return err
+		}
+		i.Port = port
+	}
+
+	i.setViper(constants.ArgInstallDir, i.InstallDir)
+	i.setViper(constants.ArgDatabasePort, i.Port)
+	i.setViper(constants.ConfigKeySteampipeExecutable, executable)
+	// defaults for config which would otherwise be set by the command
+	i.setViperDefault(constants.ArgDatabaseStartTimeout, constants.DBStartTimeout.Seconds())
+	i.setViperDefault(constants.ArgServiceCacheEnabled, true)
+	i.setViperDefault(constants.ArgCacheMaxTtl, 300)
+	i.setViperDefault(constants.ArgIntrospection, constants.IntrospectionNone)
+	return nil
+}
+
+func (i *Instance) setViper(key string, value any) {
+	i.recordViper(key, false)
+	viper.Set(key, value)
+}
+
+func (i *Instance) setViperDefault(key string, value any) {
+	i.recordViper(key, true)
+	viper.SetDefault(key, value)
+}
+
+// recordViper records the current value of the viper key, the first time the instance changes it
+func (i *Instance) recordViper(key string, isDefault bool) {
+	if prev, ok := i.prevViper[key]; ok {
+		// if the key is both set and defaulted, restore the override, which takes precedence
+		prev.isDefault = prev.isDefault && isDefault
+		i.prevViper[key] = prev
+		return
+	}
+	i.prevViper[key] = prevViperValue{value: viper.Get(key), isDefault: isDefault}
+}
+
+// restoreViper restores the values of the viper keys changed by the instance
+func (i *Instance) restoreViper() {
+	for key, prev := range i.prevViper {
+		if prev.isDefault {
+			viper.SetDefault(key, prev.value)
+		} else {
+			viper.Set(key, prev.value)
+		}
+	}
+	i.prevViper = make(map[string]prevViperValue)
+}
+
+// waitForConnections waits until all connections have loaded, returning an error if any failed to load
+func (i *Instance) waitForConnections(ctx context.Context) error {
+	conn, err := i.client.AcquireManagementConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	connectionState, err := steampipeconfig.LoadConnectionState(ctx, conn.Conn(), steampipeconfig.WithWaitUntilLoading())
+	if err != nil {
+		return err
+	}
+	var failures []string
+	for name, state := range connectionState {
+		if state.State == constants.ConnectionStateError {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, state.Error()))
+		}
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return error_helpers.NewConnectionError(error_helpers.ErrorCodeConnectionLoadFailed, sperr.New("connections failed to load:\n\t%s", strings.Join(failures, "\n\t")))
+	}
+	return nil
+}
+
+// linkPlugins links the plugin directory of the install dir to the plugin directory of another install dir
+func linkPlugins(fromInstallDir, installDir string) error {
+	pluginDir := filepath.Join(fromInstallDir, "plugins")
+	if _, err := os.Stat(pluginDir); err != nil {
+		return err
+	}
+	return os.Symlink(pluginDir, filepath.Join(installDir, "plugins"))
+}
+
+// copyDatabase copies the database binaries installed in another install dir into the install dir
+// (the binaries are copied rather than linked, as the fdw is installed into the database installation)
+// the database version file is also copied, so the binaries are not reinstalled
+func copyDatabase(fromInstallDir, installDir string) error {
+	fromInstanceDir := filepath.Join(fromInstallDir, "db", constants.DatabaseVersion)
+	instanceDir := filepath.Join(installDir, "db", constants.DatabaseVersion)
+	if _, err := os.Stat(filepath.Join(fromInstanceDir, "postgres")); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(instanceDir, 0755); err != nil {
+		return err
+	}
+	if err := copy.Copy(filepath.Join(fromInstanceDir, "postgres"), filepath.Join(instanceDir, "postgres")); err != nil {
+		return err
+	}
+	return copyFile(filepath.Join(fromInstallDir, "db", "versions.json"), filepath.Join(installDir, "db", "versions.json"))
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// freePort returns a port which is not in use
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,67 @@
+package ephemeral
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestCopyDatabase(t *testing.T) {
+	fromInstallDir := t.TempDir()
+	installDir := t.TempDir()
+	binDir := filepath.Join(fromInstallDir, "db", constants.DatabaseVersion, "postgres", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "postgres"), []byte("binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(fromInstallDir, "db", "versions.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyDatabase(fromInstallDir, installDir); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	postgresDir := filepath.Join(installDir, "db", constants.DatabaseVersion, "postgres")
+	if info, err := os.Lstat(postgresDir); err != nil || info.Mode()&os.ModeSymlink != 0 {
+		t.Errorf("expected the database binaries to be copied, not linked")
+	}
+	if info, err := os.Stat(filepath.Join(postgresDir, "bin", "postgres")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("expected the database binaries to be copied and remain executable")
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "db", constants.DatabaseVersion, "data")); !os.IsNotExist(err) {
+		t.Errorf("expected the data directory not to be shared")
+	}
+	if versions, err := os.ReadFile(filepath.Join(installDir, "db", "versions.json")); err != nil || string(versions) != "{}" {
+		t.Errorf("expected the database version file to be copied")
+	}
+}
+
+func TestCopyDatabaseNotInstalled(t *testing.T) {
+	if err := copyDatabase(t.TempDir(), t.TempDir()); err == nil {
+		t.Errorf("expected an error if the database is not installed")
+	}
+}
+
+func TestRestoreViper(t *testing.T) {
+	defer viper.Set(constants.ArgDatabasePort, nil)
+	defer viper.Set(constants.ArgInstallDir, nil)
+	viper.Set(constants.ArgDatabasePort, 9193)
+
+	i := &Instance{prevViper: make(map[string]prevViperValue)}
+	i.setViper(constants.ArgDatabasePort, 9999)
+	i.setViper(constants.ArgDatabasePort, 9998)
+	i.setViper(constants.ArgInstallDir, "/tmp/ephemeral")
+	i.restoreViper()
+
+	if port := viper.GetInt(constants.ArgDatabasePort); port != 9193 {
+		t.Errorf("expected the database port to be restored to 9193, got %d", port)
+	}
+	if installDir := viper.GetString(constants.ArgInstallDir); installDir != "" {
+		t.Errorf("expected the install dir to be restored to unset, got '%s'", installDir)
+	}
+}
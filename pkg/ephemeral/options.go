@@ -0,0 +1,59 @@
+package ephemeral
+
+type instanceConfig struct {
+	installDir   string
+	configFiles  map[string]string
+	pluginsFrom  string
+	databaseFrom string
+	port         int
+	executable   string
+}
+
+type Option = func(config *instanceConfig)
+
+// WithInstallDir uses the given install dir rather than a temporary directory
+// (the directory is not removed when the instance is closed)
+func WithInstallDir(installDir string) Option {
+	return func(o *instanceConfig) {
+		o.installDir = installDir
+	}
+}
+
+// WithConfig adds a connection config file with the given name and content to the config directory of the instance
+func WithConfig(fileName, content string) Option {
+	return func(o *instanceConfig) {
+		o.configFiles[fileName] = content
+	}
+}
+
+// WithPluginsFrom uses the plugins installed in the given install dir, rather than installing plugins
+// NOTE: the plugin directory is shared, so plugins installed or removed using the instance also change the given install dir
+func WithPluginsFrom(installDir string) Option {
+	return func(o *instanceConfig) {
+		o.pluginsFrom = installDir
+	}
+}
+
+// WithDatabaseFrom copies the database binaries installed in the given install dir, rather than downloading them
+// (the instance has its own copy of the binaries and its own data directory, so the given install dir is not changed)
+func WithDatabaseFrom(installDir string) Option {
+	return func(o *instanceConfig) {
+		o.databaseFrom = installDir
+	}
+}
+
+// WithPort sets the database port of the instance - if not set, a free port is used
+func WithPort(port int) Option {
+	return func(o *instanceConfig) {
+		o.port = port
+	}
+}
+
+// WithExecutable sets the path of the steampipe executable, which is used to run the plugin manager
+// if not set, the executable of the running process is used - this must be set if the process is not steampipe
+// (e.g. a test binary or a program which embeds steampipe)
+func WithExecutable(executable string) Option {
+	return func(o *instanceConfig) {
+		o.executable = executable
+	}
+}
@@ -0,0 +1,30 @@
+package error_helpers
+
+import "strings"
+
+// the (lower case) error messages returned by cloud APIs and identity providers when the credentials
+// of a connection have expired, e.g. an STS session or OIDC token
+var credentialExpiryErrors = []string{
+	// AWS: ExpiredToken, ExpiredTokenException
+	"expiredtoken",
+	"security token included in the request is expired",
+	// Azure: the client assertion (federated token) or refresh token has expired
+	"aadsts700024",
+	"aadsts70043",
+	// GCP and generic OAuth/OIDC token errors
+	"token has expired",
+	"token is expired",
+	"token expired",
+	"credentials have expired",
+}
+
+// IsCredentialExpiryError returns whether the error returned by a plugin is caused by the expiry of the connection credentials
+func IsCredentialExpiryError(message string) bool {
+	message = strings.ToLower(message)
+	for _, expiryError := range credentialExpiryErrors {
+		if strings.Contains(message, expiryError) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package error_helpers
+
+import "testing"
+
+func TestIsCredentialExpiryError(t *testing.T) {
+	tests := map[string]struct {
+		message  string
+		expected bool
+	}{
+		"aws expired token":      {message: "operation error STS: GetCallerIdentity, https response error StatusCode: 403, api error ExpiredToken: The security token included in the request is expired", expected: true},
+		"aws expired exception":  {message: "ExpiredTokenException: The security token included in the request is expired", expected: true},
+		"azure client assertion": {message: "AADSTS700024: Client assertion is not within its valid time range.", expected: true},
+		"oauth token":            {message: "oauth2: token expired and refresh token is not set", expected: true},
+		"access denied":          {message: "AccessDenied: User is not authorized to perform: ec2:DescribeInstances", expected: false},
+		"invalid token":          {message: "InvalidClientTokenId: The security token included in the request is invalid", expected: false},
+		"empty":                  {message: "", expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsCredentialExpiryError(test.message); got != test.expected {
+				t.Errorf("IsCredentialExpiryError(%q) = %v, expected %v", test.message, got, test.expected)
+			}
+		})
+	}
+}
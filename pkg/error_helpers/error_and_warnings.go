@@ -13,7 +13,7 @@ type ErrorAndWarnings struct {
 
 func DiagsToErrorsAndWarnings(errPrefix string, diags hcl.Diagnostics) ErrorAndWarnings {
 	return NewErrorsAndWarning(
-		plugin.DiagsToError(errPrefix, diags),
+		HclDiagsToError(errPrefix, diags),
 		plugin.DiagsToWarnings(diags)...,
 	)
 }
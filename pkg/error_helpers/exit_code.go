@@ -0,0 +1,34 @@
+package error_helpers
+
+import "errors"
+
+// ExitCodeError is an error which determines the exit code of the command it causes to fail
+type ExitCodeError struct {
+	ExitCode int
+	err      error
+}
+
+// NewExitCodeError wraps the error with the given exit code - if err is nil, nil is returned
+func NewExitCodeError(exitCode int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitCodeError{ExitCode: exitCode, err: err}
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code of the first ExitCodeError in the error chain, or defaultExitCode if there is none
+func ExitCode(err error, defaultExitCode int) int {
+	var exitCodeErr *ExitCodeError
+	if errors.As(err, &exitCodeErr) {
+		return exitCodeErr.ExitCode
+	}
+	return defaultExitCode
+}
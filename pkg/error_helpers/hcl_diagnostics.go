@@ -0,0 +1,230 @@
+package error_helpers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// HclDiagnosticsError is an error raised by HCL parsing or decoding
+// it retains the diagnostics so they can be displayed with a source code frame, or as json
+type HclDiagnosticsError struct {
+	Prefix string
+	Diags  hcl.Diagnostics
+}
+
+// HclDiagsToError converts hcl diags into an HclDiagnosticsError - if there are no error diags, nil is returned
+func HclDiagsToError(prefix string, diags hcl.Diagnostics) error {
+	if !diags.HasErrors() {
+		return nil
+	}
+	return &HclDiagnosticsError{Prefix: prefix, Diags: diags}
+}
+
+func (e *HclDiagnosticsError) Error() string {
+	return plugin.DiagsToError(e.Prefix, e.Diags).Error()
+}
+
+// Kind implements TypedError - diagnostics are raised by invalid config
+func (e *HclDiagnosticsError) Kind() ErrorKind {
+	return ErrorKindConfig
+}
+
+// Code implements TypedError
+func (e *HclDiagnosticsError) Code() ErrorCode {
+	return ErrorCodeConfigInvalid
+}
+
+// number of lines of source to show either side of the diagnostic subject
+const codeFrameContextLines = 1
+
+// Render writes each error diagnostic with a source code frame, a caret marking the subject and a hint (if there is one)
+// source is a map of filename to file content - if a file is not in the map, it is read from disk
+func (e *HclDiagnosticsError) Render(w io.Writer, source map[string][]byte) {
+	if e.Prefix != "" {
+		fmt.Fprintf(w, "%s: %s\n", constants.ColoredErr, e.Prefix)
+	}
+	if source == nil {
+		source = make(map[string][]byte)
+	}
+	seen := make(map[string]struct{})
+	for _, diag := range e.Diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		// we may get the same diag multiple times
+		key := diagKey(diag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		fmt.Fprintf(w, "\n%s: %s\n", constants.ColoredErr, diag.Summary)
+		if diag.Subject != nil {
+			renderCodeFrame(w, diag.Subject, loadSource(diag.Subject.Filename, source))
+		}
+		if diag.Detail != "" {
+			fmt.Fprintf(w, "\n%s\n", diag.Detail)
+		}
+		if hint := DiagnosticHint(diag); hint != "" {
+			fmt.Fprintf(w, "\nHint: %s\n", hint)
+		}
+	}
+}
+
+func loadSource(filename string, source map[string][]byte) []byte {
+	if content, ok := source[filename]; ok {
+		return content
+	}
+	// if the file cannot be read, the frame is omitted
+	content, _ := os.ReadFile(filename)
+	source[filename] = content
+	return content
+}
+
+// renderCodeFrame writes the lines of source around the subject range, with a caret under the subject, e.g.
+//
+//	on aws.spc line 3, column 3:
+//	   2 | connection "aws" {
+//	>  3 |   regoins = ["*"]
+//	     |   ^^^^^^^
+//	   4 | }
+func renderCodeFrame(w io.Writer, subject *hcl.Range, src []byte) {
+	fmt.Fprintf(w, "\n  on %s line %d, column %d:\n", subject.Filename, subject.Start.Line, subject.Start.Column)
+	if len(src) == 0 {
+		return
+	}
+
+	firstLine := max(subject.Start.Line-codeFrameContextLines, 1)
+	lastLine := subject.End.Line + codeFrameContextLines
+	// only show the context after the start line if the subject is a single line
+	if subject.End.Line > subject.Start.Line {
+		lastLine = subject.Start.Line
+	}
+	gutterWidth := len(fmt.Sprintf("%d", lastLine))
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for line := 1; scanner.Scan() && line <= lastLine; line++ {
+		if line < firstLine {
+			continue
+		}
+		text := scanner.Text()
+		marker := " "
+		if line == subject.Start.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "  %s %*d | %s\n", marker, gutterWidth, line, text)
+		if line == subject.Start.Line {
+			fmt.Fprintf(w, "    %s | %s\n", strings.Repeat(" ", gutterWidth), caretLine(text, subject))
+		}
+	}
+}
+
+// caretLine returns a line of carets under the subject on its start line
+// (characters before the subject are replaced with spaces, preserving tabs so the carets align)
+func caretLine(text string, subject *hcl.Range) string {
+	runes := []rune(text)
+	start := min(max(subject.Start.Column-1, 0), len(runes))
+	end := len(runes)
+	if subject.End.Line == subject.Start.Line {
+		end = min(subject.End.Column-1, len(runes))
+	}
+
+	var sb strings.Builder
+	for _, r := range runes[:start] {
+		if r == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	sb.WriteString(strings.Repeat("^", max(end-start, 1)))
+	return sb.String()
+}
+
+// hcl diagnostic summaries, and hints on how to resolve them
+var diagnosticHints = map[string]string{
+	"Unsupported argument":                   "check the spelling of the argument name, and that it is supported by this block type",
+	"Unsupported block type":                 "check the spelling of the block type, and that it is nested in the correct block",
+	"Missing required argument":              "add the missing argument to the block",
+	"Argument or block definition required":  "each line must be an argument ('name = value') or a block ('type \"label\" { ... }') - check for a missing '=' or an unquoted value",
+	"Unclosed configuration block":           "add the missing closing brace '}' - check any nested blocks are also closed",
+	"Invalid multi-line string":              "use a heredoc ('<<-EOT ... EOT') for multi-line strings",
+	"Missing newline after block definition": "the opening brace '{' of a block must be followed by a newline",
+	"Missing block label":                    "add the block label(s), e.g. connection \"aws\" { ... }",
+	"Invalid expression":                     "check for unquoted strings, or a missing value after '='",
+	"Duplicate argument":                     "remove one of the duplicate arguments",
+}
+
+// DiagnosticHint returns a hint on how to resolve the diagnostic, if there is one
+func DiagnosticHint(diag *hcl.Diagnostic) string {
+	return diagnosticHints[diag.Summary]
+}
+
+// DiagnosticJSON is the json representation of a diagnostic, for editor and CI integration
+type DiagnosticJSON struct {
+	Severity string               `json:"severity"`
+	Summary  string               `json:"summary"`
+	Detail   string               `json:"detail,omitempty"`
+	Hint     string               `json:"hint,omitempty"`
+	Range    *DiagnosticRangeJSON `json:"range,omitempty"`
+}
+
+type DiagnosticRangeJSON struct {
+	Filename string            `json:"filename"`
+	Start    DiagnosticPosJSON `json:"start"`
+	End      DiagnosticPosJSON `json:"end"`
+}
+
+type DiagnosticPosJSON struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// DiagnosticsToJSON converts hcl diagnostics to their json representation
+func DiagnosticsToJSON(diags hcl.Diagnostics) []DiagnosticJSON {
+	res := []DiagnosticJSON{}
+	seen := make(map[string]struct{})
+	for _, diag := range diags {
+		key := diagKey(diag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		d := DiagnosticJSON{
+			Severity: "error",
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+			Hint:     DiagnosticHint(diag),
+		}
+		if diag.Severity == hcl.DiagWarning {
+			d.Severity = "warning"
+		}
+		if s := diag.Subject; s != nil {
+			d.Range = &DiagnosticRangeJSON{
+				Filename: s.Filename,
+				Start:    DiagnosticPosJSON{Line: s.Start.Line, Column: s.Start.Column, Byte: s.Start.Byte},
+				End:      DiagnosticPosJSON{Line: s.End.Line, Column: s.End.Column, Byte: s.End.Byte},
+			}
+		}
+		res = append(res, d)
+	}
+	return res
+}
+
+func diagKey(diag *hcl.Diagnostic) string {
+	key := fmt.Sprintf("%d|%s|%s", diag.Severity, diag.Summary, diag.Detail)
+	if diag.Subject != nil {
+		key += "|" + diag.Subject.String()
+	}
+	return key
+}
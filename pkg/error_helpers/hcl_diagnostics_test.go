@@ -0,0 +1,78 @@
+package error_helpers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestHclDiagnosticsErrorRender(t *testing.T) {
+	src := []byte("connection \"aws\" {\n  plugin  = \"aws\"\n  regoins = [\"*\"]\n}\n")
+	diags := hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Unsupported argument",
+		Detail:   `An argument named "regoins" is not expected here.`,
+		Subject: &hcl.Range{
+			Filename: "aws.spc",
+			Start:    hcl.Pos{Line: 3, Column: 3, Byte: 37},
+			End:      hcl.Pos{Line: 3, Column: 10, Byte: 44},
+		},
+	}}
+	err := HclDiagsToError("Failed to load config", diags).(*HclDiagnosticsError)
+
+	var buf bytes.Buffer
+	err.Render(&buf, map[string][]byte{"aws.spc": src})
+	expected := `Error: Failed to load config
+
+Error: Unsupported argument
+
+  on aws.spc line 3, column 3:
+    2 |   plugin  = "aws"
+  > 3 |   regoins = ["*"]
+      |   ^^^^^^^
+    4 | }
+
+An argument named "regoins" is not expected here.
+
+Hint: check the spelling of the argument name, and that it is supported by this block type
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestCaretLine(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		subject  hcl.Range
+		expected string
+	}{
+		"single line": {
+			text:     "  regoins = 1",
+			subject:  hcl.Range{Start: hcl.Pos{Line: 1, Column: 3}, End: hcl.Pos{Line: 1, Column: 10}},
+			expected: "  ^^^^^^^",
+		},
+		"tab indent": {
+			text:     "\tregoins = 1",
+			subject:  hcl.Range{Start: hcl.Pos{Line: 1, Column: 2}, End: hcl.Pos{Line: 1, Column: 9}},
+			expected: "\t^^^^^^^",
+		},
+		"empty range": {
+			text:     "connection \"aws\" {",
+			subject:  hcl.Range{Start: hcl.Pos{Line: 1, Column: 19}, End: hcl.Pos{Line: 1, Column: 19}},
+			expected: strings.Repeat(" ", 18) + "^",
+		},
+		"multi line": {
+			text:     "  sql = <<EOQ",
+			subject:  hcl.Range{Start: hcl.Pos{Line: 1, Column: 9}, End: hcl.Pos{Line: 4, Column: 4}},
+			expected: "        ^^^^^",
+		},
+	}
+	for name, test := range tests {
+		if res := caretLine(test.text, &test.subject); res != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", name, test.expected, res)
+		}
+	}
+}
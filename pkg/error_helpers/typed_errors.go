@@ -0,0 +1,138 @@
+package error_helpers
+
+import (
+	"errors"
+)
+
+// ErrorKind is the kind of failure a TypedError represents
+type ErrorKind string
+
+const (
+	ErrorKindConfig     ErrorKind = "config"
+	ErrorKindPlugin     ErrorKind = "plugin"
+	ErrorKindConnection ErrorKind = "connection"
+	ErrorKindQuery      ErrorKind = "query"
+)
+
+// ErrorCode identifies the cause of a TypedError
+// codes are part of the CLI output and are used by automation to classify failures, so must not be changed
+type ErrorCode string
+
+const (
+	ErrorCodeConfigInvalid    ErrorCode = "config_invalid"
+	ErrorCodeConfigLoadFailed ErrorCode = "config_load_failed"
+
+	ErrorCodePluginVersionMismatch  ErrorCode = "plugin_version_mismatch"
+	ErrorCodePluginRollbackNotFound ErrorCode = "plugin_rollback_not_found"
+
+	ErrorCodeDatabaseConnectionFailed ErrorCode = "database_connection_failed"
+	ErrorCodeConnectionLoadFailed     ErrorCode = "connection_load_failed"
+
+	ErrorCodeQueryFailed    ErrorCode = "query_failed"
+	ErrorCodeQueryTimeout   ErrorCode = "query_timeout"
+	ErrorCodeQueryCancelled ErrorCode = "query_cancelled"
+)
+
+// TypedError is an error with a kind and a code, which are included in the json representation of the error
+// (the text output of the error is unchanged - it is prefixed with 'Error:' as for any other error)
+type TypedError interface {
+	error
+	Kind() ErrorKind
+	Code() ErrorCode
+}
+
+type typedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *typedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *typedError) Unwrap() error {
+	return e.err
+}
+
+func (e *typedError) Code() ErrorCode {
+	return e.code
+}
+
+// ConfigError is raised when the steampipe config or workspace mod is invalid or cannot be loaded
+type ConfigError struct{ typedError }
+
+func (e *ConfigError) Kind() ErrorKind { return ErrorKindConfig }
+
+// NewConfigError wraps the error in a ConfigError with the given code - if err is nil, nil is returned
+func NewConfigError(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConfigError{typedError{code: code, err: err}}
+}
+
+// PluginError is raised when a plugin cannot be installed, loaded or used
+type PluginError struct{ typedError }
+
+func (e *PluginError) Kind() ErrorKind { return ErrorKindPlugin }
+
+// NewPluginError wraps the error in a PluginError with the given code - if err is nil, nil is returned
+func NewPluginError(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PluginError{typedError{code: code, err: err}}
+}
+
+// ConnectionError is raised when the steampipe database or a connection cannot be connected to or loaded
+type ConnectionError struct{ typedError }
+
+func (e *ConnectionError) Kind() ErrorKind { return ErrorKindConnection }
+
+// NewConnectionError wraps the error in a ConnectionError with the given code - if err is nil, nil is returned
+func NewConnectionError(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConnectionError{typedError{code: code, err: err}}
+}
+
+// QueryError is raised when a query fails to execute
+type QueryError struct{ typedError }
+
+func (e *QueryError) Kind() ErrorKind { return ErrorKindQuery }
+
+// NewQueryError wraps the error in a QueryError with the given code - if err is nil, nil is returned
+func NewQueryError(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &QueryError{typedError{code: code, err: err}}
+}
+
+// AsTypedError returns the first TypedError in the error chain
+func AsTypedError(err error) (TypedError, bool) {
+	var typedErr TypedError
+	if errors.As(err, &typedErr) {
+		return typedErr, true
+	}
+	return nil, false
+}
+
+// ErrorJSON is the json representation of an error
+// kind and code are only set for typed errors
+type ErrorJSON struct {
+	Kind    ErrorKind `json:"kind,omitempty"`
+	Code    ErrorCode `json:"code,omitempty"`
+	Message string    `json:"message"`
+}
+
+// ToErrorJSON returns the json representation of the error
+func ToErrorJSON(err error) ErrorJSON {
+	res := ErrorJSON{Message: TransformErrorToSteampipe(err).Error()}
+	if typedErr, ok := AsTypedError(err); ok {
+		res.Kind = typedErr.Kind()
+		res.Code = typedErr.Code()
+	}
+	return res
+}
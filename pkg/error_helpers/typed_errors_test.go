@@ -0,0 +1,41 @@
+package error_helpers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestToErrorJSON(t *testing.T) {
+	cause := errors.New("plugin aws is not installed")
+	testCases := map[string]struct {
+		err      error
+		expected ErrorJSON
+	}{
+		"untyped": {
+			err:      cause,
+			expected: ErrorJSON{Message: "plugin aws is not installed"},
+		},
+		"typed": {
+			err:      NewPluginError(ErrorCodePluginVersionMismatch, cause),
+			expected: ErrorJSON{Kind: ErrorKindPlugin, Code: ErrorCodePluginVersionMismatch, Message: "plugin aws is not installed"},
+		},
+		"wrapped": {
+			err:      NewExitCodeError(constants.ExitCodePluginLoadingError, fmt.Errorf("failed: %w", NewPluginError(ErrorCodePluginVersionMismatch, cause))),
+			expected: ErrorJSON{Kind: ErrorKindPlugin, Code: ErrorCodePluginVersionMismatch, Message: "failed: plugin aws is not installed"},
+		},
+	}
+	for name, test := range testCases {
+		if res := ToErrorJSON(test.err); res != test.expected {
+			t.Errorf("%s: expected %+v, got %+v", name, test.expected, res)
+		}
+	}
+}
+
+func TestTypedErrorNil(t *testing.T) {
+	if err := NewQueryError(ErrorCodeQueryFailed, nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
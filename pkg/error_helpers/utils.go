@@ -2,6 +2,7 @@ package error_helpers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -47,9 +48,39 @@ func ShowError(ctx context.Context, err error) {
 	}
 	err = HandleCancelError(err)
 	statushooks.Done(ctx)
+	// show hcl diagnostics with a source code frame (or as json if json output is selected)
+	var diagsErr *HclDiagnosticsError
+	if errors.As(err, &diagsErr) {
+		showHclDiagnosticsError(diagsErr)
+		return
+	}
+	// show typed errors as json if json output is selected, so automation can classify the failure
+	// (this is written to stdout, as are hcl diagnostics, as it replaces the json output of the command)
+	if typedErr, ok := AsTypedError(err); ok && viper.GetString(constants.ArgOutput) == constants.OutputFormatJSON {
+		jsonOutput, jsonErr := json.MarshalIndent(map[string]any{"error": ToErrorJSON(typedErr)}, "", "  ")
+		if jsonErr == nil {
+			fmt.Println(string(jsonOutput))
+			return
+		}
+	}
 	fmt.Fprintf(color.Error, "%s: %v\n", constants.ColoredErr, TransformErrorToSteampipe(err))
 }
 
+func showHclDiagnosticsError(err *HclDiagnosticsError) {
+	if viper.GetString(constants.ArgOutput) == constants.OutputFormatJSON {
+		res := map[string]any{
+			"error":       ToErrorJSON(err),
+			"diagnostics": DiagnosticsToJSON(err.Diags),
+		}
+		jsonOutput, jsonErr := json.MarshalIndent(res, "", "  ")
+		if jsonErr == nil {
+			fmt.Println(string(jsonOutput))
+			return
+		}
+	}
+	err.Render(color.Error, nil)
+}
+
 // ShowErrorWithMessage displays the given error nicely with the given message
 func ShowErrorWithMessage(ctx context.Context, err error, message string) {
 	if err == nil {
@@ -97,7 +128,7 @@ func HandleCancelError(err error) error {
 
 func HandleQueryTimeoutError(err error) error {
 	if errors.Is(err, context.DeadlineExceeded) {
-		err = fmt.Errorf("query timeout exceeded (%ds)", viper.GetInt(constants.ArgDatabaseQueryTimeout))
+		err = NewQueryError(ErrorCodeQueryTimeout, fmt.Errorf("query timeout exceeded (%ds)", viper.GetInt(constants.ArgDatabaseQueryTimeout)))
 	}
 	return err
 }
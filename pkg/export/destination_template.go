@@ -0,0 +1,78 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// variables which may be used in export destinations, e.g. --export "reports/{date}/{name}.json"
+const (
+	// the name of the execution being exported (e.g. the benchmark, query or dashboard name)
+	destinationVarName      = "{name}"
+	destinationVarBenchmark = "{benchmark}"
+	destinationVarQuery     = "{query}"
+	destinationVarDashboard = "{dashboard}"
+	// the name of the control - a separate file is written for each control of a check run
+	destinationVarControl = "{control}"
+	// the time of the export
+	destinationVarDate      = "{date}"
+	destinationVarTime      = "{time}"
+	destinationVarTimestamp = "{timestamp}"
+	destinationVarYear      = "{year}"
+	destinationVarMonth     = "{month}"
+	destinationVarDay       = "{day}"
+	destinationVarHour      = "{hour}"
+)
+
+// the variables which resolve to the execution name
+var destinationNameVars = []string{destinationVarName, destinationVarBenchmark, destinationVarQuery, destinationVarDashboard}
+
+// isTemplatedDestination returns whether the export destination contains any template variables
+func isTemplatedDestination(destination string) bool {
+	return strings.Contains(destination, "{") && strings.Contains(destination, "}")
+}
+
+// destinationDependsOnName returns whether the export destination contains a variable which resolves to the execution name
+// - if so, a separate file will be written for each execution
+func destinationDependsOnName(destination string) bool {
+	for _, v := range destinationNameVars {
+		if strings.Contains(destination, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// destinationDependsOnControl returns whether the export destination contains the control variable
+// - if so, a separate file will be written for each control
+func destinationDependsOnControl(destination string) bool {
+	return strings.Contains(destination, destinationVarControl)
+}
+
+// resolveDestinationControl replaces the control variable in the (otherwise resolved) export destination
+func resolveDestinationControl(destination, controlName string) string {
+	return strings.ReplaceAll(destination, destinationVarControl, controlName)
+}
+
+// resolveDestinationTemplate replaces the template variables in the export destination
+// with values for the given execution and time
+// the control variable is not replaced, as this is resolved for each control when the file is written
+func resolveDestinationTemplate(destination, executionName string, now time.Time) string {
+	if !isTemplatedDestination(destination) {
+		return destination
+	}
+	replacements := []string{
+		destinationVarDate, now.Format("2006-01-02"),
+		destinationVarTime, now.Format("150405"),
+		destinationVarTimestamp, fmt.Sprintf("%d%02d%02dT%02d%02d%02d", now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second()),
+		destinationVarYear, now.Format("2006"),
+		destinationVarMonth, now.Format("01"),
+		destinationVarDay, now.Format("02"),
+		destinationVarHour, now.Format("15"),
+	}
+	for _, v := range destinationNameVars {
+		replacements = append(replacements, v, executionName)
+	}
+	return strings.NewReplacer(replacements...).Replace(destination)
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"testing"
+	"time"
+)
+
+type destinationTemplateTest struct {
+	destination string
+	expected    string
+}
+
+var destinationTemplateTestTime = time.Date(2024, 3, 7, 9, 5, 2, 0, time.UTC)
+
+var destinationTemplateTests = map[string]destinationTemplateTest{
+	"no variables": {
+		destination: "output.json",
+		expected:    "output.json",
+	},
+	"date": {
+		destination: "reports/{date}/output.csv",
+		expected:    "reports/2024-03-07/output.csv",
+	},
+	"name and timestamp": {
+		destination: "{benchmark}.{timestamp}.json",
+		expected:    "mod.benchmark.cis.20240307T090502.json",
+	},
+	"date parts": {
+		destination: "s3://bucket/{year}/{month}/{day}/{hour}/{name}.csv",
+		expected:    "s3://bucket/2024/03/07/09/mod.benchmark.cis.csv",
+	},
+	"control is resolved per control": {
+		destination: "{benchmark}/{control}.json",
+		expected:    "mod.benchmark.cis/{control}.json",
+	},
+	"unknown variable": {
+		destination: "{unknown}/{time}.json",
+		expected:    "{unknown}/090502.json",
+	},
+}
+
+func TestResolveDestinationTemplate(t *testing.T) {
+	for name, test := range destinationTemplateTests {
+		actual := resolveDestinationTemplate(test.destination, "mod.benchmark.cis", destinationTemplateTestTime)
+		if actual != test.expected {
+			t.Errorf("test '%s' failed: expected '%s', got '%s'", name, test.expected, actual)
+		}
+	}
+}
@@ -7,6 +7,20 @@ type ExportSourceData interface {
 	IsExportSourceData()
 }
 
+// ControlPartitionedSourceData is implemented by export source data which can be split into the data for each control
+// it is used to write a file per control if the export destination contains the {control} variable
+type ControlPartitionedSourceData interface {
+	ExportSourceData
+	ControlPartitions() []ControlPartition
+}
+
+// ControlPartition is the export source data for a single control
+type ControlPartition struct {
+	// the full name of the control
+	Name string
+	Data ExportSourceData
+}
+
 type Exporter interface {
 	Export(ctx context.Context, input ExportSourceData, destPath string) error
 	FileExtension() string
@@ -14,6 +28,13 @@ type Exporter interface {
 	Alias() string
 }
 
+// DestinationExporter is implemented by exporters which send data to a destination specified in the export argument
+// (rather than writing a file), using the syntax <name>:<destination>, e.g. --export webhook:https://example.com/hook
+type DestinationExporter interface {
+	Exporter
+	IsDestinationExporter()
+}
+
 type ExporterBase struct{}
 
 func (*ExporterBase) Alias() string {
@@ -1,9 +1,11 @@
 package export
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -13,7 +15,18 @@ func GenerateDefaultExportFileName(executionName, fileExtension string) string {
 	return fmt.Sprintf("%s.%s%s", executionName, timeFormatted, fileExtension)
 }
 
-func Write(filePath string, exportData io.Reader) error {
+// Write writes the export data to the given file path
+// if the path is a remote destination (e.g. s3://bucket/report.json), the data is uploaded to the object store
+func Write(ctx context.Context, filePath string, exportData io.Reader) error {
+	if IsRemoteDestination(filePath) {
+		return writeRemote(ctx, filePath, exportData)
+	}
+	// ensure the parent directory exists - templated destinations may include directories, e.g. {date}/output.json
+	if dir := filepath.Dir(filePath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
 	// create the output file
 	destination, err := os.Create(filePath)
 	if err != nil {
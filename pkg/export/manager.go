@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/error_helpers"
@@ -41,6 +42,11 @@ func (m *Manager) Register(exporter Exporter) error {
 		m.registeredExporters[alias] = exporter
 	}
 
+	// destination exporters do not write files, so have no extension to register
+	if _, ok := exporter.(DestinationExporter); ok {
+		return nil
+	}
+
 	// now register extension
 	ext := exporter.FileExtension()
 	m.registerExporterByExtension(exporter, ext)
@@ -115,6 +121,9 @@ func (m *Manager) resolveTargetsFromArgs(exportArgs []string, executionName stri
 
 func (m *Manager) getExportTarget(export, executionName string) (*Target, error) {
 	if e, ok := m.registeredExporters[export]; ok {
+		if _, ok := e.(DestinationExporter); ok {
+			return nil, fmt.Errorf("export '%s' requires a destination, e.g. %s:<destination>", export, export)
+		}
 		t := &Target{
 			exporter: e,
 			filePath: GenerateDefaultExportFileName(executionName, e.FileExtension()),
@@ -122,13 +131,32 @@ func (m *Manager) getExportTarget(export, executionName string) (*Target, error)
 		return t, nil
 	}
 
+	// is this a destination exporter, i.e. <name>:<destination>
+	if name, destination, found := strings.Cut(export, ":"); found {
+		if e, ok := m.registeredExporters[name].(DestinationExporter); ok {
+			if destination == "" {
+				return nil, fmt.Errorf("export '%s' requires a destination, e.g. %s:<destination>", name, name)
+			}
+			t := &Target{
+				exporter:      e,
+				filePath:      destination,
+				isNamedTarget: true,
+			}
+			return t, nil
+		}
+	}
+
 	// now try by extension
 	ext := path.Ext(export)
 	if e, ok := m.registeredExtensions[ext]; ok {
 		t := &Target{
-			exporter:      e,
-			filePath:      export,
-			isNamedTarget: true,
+			exporter: e,
+			// resolve any template variables in the destination, e.g. {date} or {name}
+			filePath: resolveDestinationTemplate(export, executionName, time.Now()),
+			// if the destination depends on the execution name (or control), a separate file is written
+			// for each execution (or control), so this behaves like an unnamed target
+			isNamedTarget: !destinationDependsOnName(export) && !destinationDependsOnControl(export),
+			perControl:    destinationDependsOnControl(export),
 		}
 		return t, nil
 	}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/turbot/steampipe/pkg/network"
+)
+
+const envAzureStorageAccount = "AZURE_STORAGE_ACCOUNT"
+
+// azureBlobEndpoint is the endpoint of the blob service of a storage account (a var so tests may override it)
+var azureBlobEndpoint = "https://%s.blob.core.windows.net/"
+
+// newAzureCredential returns the credential used to authenticate with Azure storage (a var so tests may override it)
+// this is the Azure default credential chain (environment, workload identity, managed identity and Azure CLI)
+var newAzureCredential = func(clientOptions azcore.ClientOptions) (azcore.TokenCredential, error) {
+	return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: clientOptions})
+}
+
+// writeAzureBlob uploads the export data to an Azure blob
+// the storage account is read from the AZURE_STORAGE_ACCOUNT environment variable, and credentials
+// are resolved using the Azure default credential chain
+func writeAzureBlob(ctx context.Context, container, key string, data io.Reader) error {
+	account := os.Getenv(envAzureStorageAccount)
+	if account == "" {
+		return missingEnvError("Azure blob storage", envAzureStorageAccount)
+	}
+
+	// send all requests (including token requests) using the configured CA bundle, proxy and TLS settings
+	clientOptions := azcore.ClientOptions{Transport: network.HttpClient()}
+	credential, err := newAzureCredential(clientOptions)
+	if err != nil {
+		return err
+	}
+	client, err := azblob.NewClient(fmt.Sprintf(azureBlobEndpoint, account), credential, &azblob.ClientOptions{ClientOptions: clientOptions})
+	if err != nil {
+		return err
+	}
+
+	// UploadBuffer uploads the data with a single request, unless it is too large for a single blob upload
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	_, err = client.UploadBuffer(ctx, container, key, content, nil)
+	return err
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/network"
+)
+
+// testAzureCredential is a credential which issues a fixed token
+type testAzureCredential struct{}
+
+func (testAzureCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "test-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// newAzureTestServer returns a TLS blob server which records the blobs uploaded with a valid token
+// the server certificate is only trusted using the ca_cert_file network option
+func newAzureTestServer(t *testing.T, blobs map[string]string) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("x-ms-blob-type") != "BlockBlob" || r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		blobs[r.URL.Path] = string(content)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caCertFile, caCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(constants.ArgCaCertFile, caCertFile)
+	if _, err := network.Configure(); err != nil {
+		t.Fatal(err)
+	}
+
+	prevBlobEndpoint, prevNewAzureCredential := azureBlobEndpoint, newAzureCredential
+	azureBlobEndpoint = server.URL + "/%s/"
+	newAzureCredential = func(azcore.ClientOptions) (azcore.TokenCredential, error) {
+		return testAzureCredential{}, nil
+	}
+	t.Cleanup(func() {
+		azureBlobEndpoint, newAzureCredential = prevBlobEndpoint, prevNewAzureCredential
+		viper.Set(constants.ArgCaCertFile, nil)
+		_, _ = network.Configure()
+	})
+	return server
+}
+
+func TestWriteAzureBlob(t *testing.T) {
+	blobs := map[string]string{}
+	newAzureTestServer(t, blobs)
+	t.Setenv(envAzureStorageAccount, "account")
+
+	if err := writeAzureBlob(context.Background(), "container", "reports/report.json", strings.NewReader("{}")); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if got := blobs["/account/container/reports/report.json"]; got != "{}" {
+		t.Errorf("expected the blob to be uploaded, got '%s'", got)
+	}
+}
+
+func TestWriteAzureBlobUntrustedServer(t *testing.T) {
+	blobs := map[string]string{}
+	newAzureTestServer(t, blobs)
+	t.Setenv(envAzureStorageAccount, "account")
+	// without the ca_cert_file network option, the server is not trusted
+	viper.Set(constants.ArgCaCertFile, nil)
+	if _, err := network.Configure(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the upload is retried, so limit the time spent retrying
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := writeAzureBlob(ctx, "container", "report.json", strings.NewReader("{}"))
+	if err == nil || len(blobs) != 0 {
+		t.Errorf("expected the upload to an untrusted server to fail")
+	}
+}
+
+func TestWriteAzureBlobNoAccount(t *testing.T) {
+	t.Setenv(envAzureStorageAccount, "")
+
+	err := writeAzureBlob(context.Background(), "container", "report.json", strings.NewReader("{}"))
+	if err == nil || !strings.Contains(err.Error(), envAzureStorageAccount) {
+		t.Errorf("expected a missing storage account error, got %v", err)
+	}
+}
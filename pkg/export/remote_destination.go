@@ -0,0 +1,75 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/network"
+)
+
+// the url schemes supported for remote export destinations
+const (
+	destinationSchemeS3    = "s3"
+	destinationSchemeGCS   = "gs"
+	destinationSchemeAzure = "azblob"
+)
+
+// remoteWriter writes export data to an object in a cloud object store
+type remoteWriter func(ctx context.Context, bucket, key string, data io.Reader) error
+
+var remoteWriters = map[string]remoteWriter{
+	destinationSchemeS3:    writeS3,
+	destinationSchemeGCS:   writeGCS,
+	destinationSchemeAzure: writeAzureBlob,
+}
+
+// IsRemoteDestination returns whether the export destination is a cloud object store url,
+// e.g. s3://bucket/report.json, gs://bucket/report.json or azblob://container/report.json
+func IsRemoteDestination(destination string) bool {
+	scheme, _, found := strings.Cut(destination, "://")
+	if !found {
+		return false
+	}
+	_, ok := remoteWriters[scheme]
+	return ok
+}
+
+// JoinDestination joins a directory and file name to build an export destination
+// NOTE: path.Join cannot be used for remote destinations as it cleans the '//' from the url scheme
+func JoinDestination(dir, fileName string) string {
+	return strings.TrimSuffix(dir, "/") + "/" + fileName
+}
+
+// writeRemote writes the export data to the remote destination
+// credentials are resolved using the standard credential chain for each cloud
+func writeRemote(ctx context.Context, destination string, data io.Reader) error {
+	// fail fast rather than waiting for the upload to time out
+	if err := network.CheckOnline(fmt.Sprintf("exporting to %s", destination)); err != nil {
+		return err
+	}
+	u, err := url.Parse(destination)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "invalid export destination '%s'", destination)
+	}
+	writer, ok := remoteWriters[u.Scheme]
+	if !ok {
+		return sperr.New("unsupported export destination scheme '%s'", u.Scheme)
+	}
+	bucket := u.Host
+	key := strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || key == "" {
+		return sperr.New("invalid export destination '%s' - expected %s://<bucket>/<path>", destination, u.Scheme)
+	}
+	if err := writer(ctx, bucket, key, data); err != nil {
+		return sperr.WrapWithMessage(err, "failed to write export to %s", destination)
+	}
+	return nil
+}
+
+func missingEnvError(destinationType string, envVars ...string) error {
+	return fmt.Errorf("to export to %s, %s must be set", destinationType, strings.Join(envVars, " and "))
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestWriteRemoteOffline(t *testing.T) {
+	viper.Set(constants.ArgOffline, true)
+	defer viper.Set(constants.ArgOffline, nil)
+
+	// record any writes, so we can verify no upload is attempted
+	var written []string
+	prevRemoteWriters := remoteWriters
+	defer func() { remoteWriters = prevRemoteWriters }()
+	remoteWriters = map[string]remoteWriter{}
+	for scheme := range prevRemoteWriters {
+		remoteWriters[scheme] = func(_ context.Context, bucket, key string, _ io.Reader) error {
+			written = append(written, bucket+"/"+key)
+			return nil
+		}
+	}
+
+	tests := map[string]string{
+		"s3":    "s3://bucket/report.json",
+		"gcs":   "gs://bucket/report.json",
+		"azure": "azblob://container/report.json",
+	}
+	for name, destination := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Write(context.Background(), destination, strings.NewReader("{}"))
+			if err == nil || !strings.Contains(err.Error(), "offline mode") {
+				t.Errorf("expected an offline mode error, got %v", err)
+			}
+		})
+	}
+	if len(written) != 0 {
+		t.Errorf("expected no uploads in offline mode, got %v", written)
+	}
+}
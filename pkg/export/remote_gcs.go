@@ -0,0 +1,35 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"github.com/turbot/steampipe/pkg/network"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
+// writeGCS uploads the export data to a Google Cloud Storage object
+// credentials are resolved using Google application default credentials
+func writeGCS(ctx context.Context, bucket, key string, data io.Reader) error {
+	// send all requests (including token requests) using the configured CA bundle, proxy and TLS settings
+	authCtx := context.WithValue(ctx, oauth2.HTTPClient, network.HttpClient())
+	credentials, err := google.FindDefaultCredentials(authCtx, storage.ScopeReadWrite)
+	if err != nil {
+		return err
+	}
+	client, err := storage.NewClient(ctx, option.WithHTTPClient(oauth2.NewClient(authCtx, credentials.TokenSource)))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	w := client.Bucket(bucket).Object(key).NewWriter(ctx)
+	if _, err := io.Copy(w, data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/turbot/steampipe/pkg/network"
+)
+
+// writeS3 uploads the export data to an S3 object
+// credentials and region are resolved using the standard AWS credential chain
+// (environment variables, shared config/credentials files, instance/container roles)
+func writeS3(ctx context.Context, bucket, key string, data io.Reader) error {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		// send all requests (including credential requests) using the configured CA bundle, proxy and TLS settings
+		Config:            aws.Config{HTTPClient: network.HttpClient()},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return err
+	}
+	uploader := s3manager.NewUploader(sess)
+	_, err = uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Body:   data,
+	})
+	return err
+}
@@ -13,7 +13,7 @@ type SnapshotExporter struct {
 	ExporterBase
 }
 
-func (e *SnapshotExporter) Export(_ context.Context, input ExportSourceData, filePath string) error {
+func (e *SnapshotExporter) Export(ctx context.Context, input ExportSourceData, filePath string) error {
 	snapshot, ok := input.(*dashboardtypes.SteampipeSnapshot)
 	if !ok {
 		return fmt.Errorf("SnapshotExporter input must be *dashboardtypes.SteampipeSnapshot")
@@ -25,7 +25,7 @@ func (e *SnapshotExporter) Export(_ context.Context, input ExportSourceData, fil
 
 	res := strings.NewReader(fmt.Sprintf("%s\n", string(snapshotBytes)))
 
-	return Write(filePath, res)
+	return Write(ctx, filePath, res)
 }
 
 func (e *SnapshotExporter) FileExtension() string {
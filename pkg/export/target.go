@@ -4,20 +4,54 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+
+	"github.com/turbot/steampipe/pkg/utils"
 )
 
 type Target struct {
 	exporter      Exporter
 	filePath      string
 	isNamedTarget bool
+	// if set, the file path contains the {control} variable and a file is written for each control
+	perControl bool
 }
 
 func (t *Target) Export(ctx context.Context, input ExportSourceData) (string, error) {
+	if t.perControl {
+		return t.exportPerControl(ctx, input)
+	}
 	err := t.exporter.Export(ctx, input, t.filePath)
 	if err != nil {
 		return "", err
 	} else {
-		pwd, _ := os.Getwd()
-		return fmt.Sprintf("File exported to %s/%s", pwd, t.filePath), nil
+		if _, ok := t.exporter.(DestinationExporter); ok {
+			return fmt.Sprintf("Results sent to %s", t.filePath), nil
+		}
+		return fmt.Sprintf("File exported to %s", displayFilePath(t.filePath)), nil
+	}
+}
+
+// exportPerControl writes a file for each control in the input, resolving the {control} variable of the file path
+func (t *Target) exportPerControl(ctx context.Context, input ExportSourceData) (string, error) {
+	partitioned, ok := input.(ControlPartitionedSourceData)
+	if !ok {
+		return "", fmt.Errorf("export '%s' is invalid - %s may only be used when exporting check results", t.filePath, destinationVarControl)
+	}
+	partitions := partitioned.ControlPartitions()
+	for _, p := range partitions {
+		if err := t.exporter.Export(ctx, p.Data, resolveDestinationControl(t.filePath, p.Name)); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d %s exported to %s", len(partitions), utils.Pluralize("file", len(partitions)), displayFilePath(t.filePath)), nil
+}
+
+// displayFilePath returns the file path to display in the export message - relative paths are shown from the working directory
+func displayFilePath(filePath string) string {
+	if filepath.IsAbs(filePath) || IsRemoteDestination(filePath) {
+		return filePath
 	}
+	pwd, _ := os.Getwd()
+	return fmt.Sprintf("%s/%s", pwd, filePath)
 }
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type recordingExporter struct {
+	ExporterBase
+	paths []string
+}
+
+func (e *recordingExporter) Export(_ context.Context, _ ExportSourceData, destPath string) error {
+	e.paths = append(e.paths, destPath)
+	return nil
+}
+func (e *recordingExporter) FileExtension() string { return ".json" }
+func (e *recordingExporter) Name() string          { return "json" }
+
+type testSourceData struct{}
+
+func (testSourceData) IsExportSourceData() {}
+
+type testPartitionedSourceData struct {
+	testSourceData
+	controls []string
+}
+
+func (d testPartitionedSourceData) ControlPartitions() []ControlPartition {
+	var res []ControlPartition
+	for _, c := range d.controls {
+		res = append(res, ControlPartition{Name: c, Data: testSourceData{}})
+	}
+	return res
+}
+
+func TestTargetExportPerControl(t *testing.T) {
+	exporter := &recordingExporter{}
+	m := NewManager()
+	if err := m.Register(exporter); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := m.getExportTarget(filepath.Join("out", "{benchmark}", "{control}.json"), "mod.benchmark.cis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.isNamedTarget {
+		t.Errorf("expected a destination containing {control} to be an unnamed target")
+	}
+
+	source := testPartitionedSourceData{controls: []string{"mod.control.c1", "mod.control.c2"}}
+	if _, err := target.Export(context.Background(), source); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	expected := []string{
+		filepath.Join("out", "mod.benchmark.cis", "mod.control.c1.json"),
+		filepath.Join("out", "mod.benchmark.cis", "mod.control.c2.json"),
+	}
+	sort.Strings(exporter.paths)
+	if !reflect.DeepEqual(exporter.paths, expected) {
+		t.Errorf("expected files %v, got %v", expected, exporter.paths)
+	}
+
+	// data which cannot be split by control cannot be exported to a {control} destination
+	if _, err := target.Export(context.Background(), testSourceData{}); err == nil {
+		t.Errorf("expected an error exporting data which cannot be split by control")
+	}
+}
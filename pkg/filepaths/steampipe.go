@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
 
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/steampipe/pkg/constants"
@@ -19,10 +22,21 @@ const (
 	versionFileName              = "versions.json"
 	databaseRunningInfoFileName  = "steampipe.json"
 	pluginManagerStateFileName   = "plugin_manager.json"
+	serviceLockFileName          = "service.lock"
+	pluginDebugStateFileName     = "plugin_debug.json"
+	configKeyFileName            = "config.key"
+	cliVersionFileName           = "cli_version.json"
+	pluginSdkVersionsFileName    = "plugin_sdk_versions.json"
 	dashboardServerStateFileName = "dashboard_service.json"
 	stateFileName                = "update_check.json"
 	legacyStateFileName          = "update-check.json"
 	availableVersionsFileName    = "available_versions.json"
+	usageReportFileName          = "usage_report.jsonl"
+	refreshReportFileName        = "refresh_connections.json"
+	refreshHashFileName          = "refresh_connections_hash"
+	variableStoreFileName        = "variables.json"
+	cloudConnectionCacheFileName = "cloud_connections.json"
+	dashboardAssetsInstallFile   = "dashboard_assets.json"
 	legacyNotificationsFileName  = "notifications.json"
 	localPluginFolder            = "local"
 )
@@ -94,6 +108,12 @@ func EnsureBackupsDir() string {
 	return ensureSteampipeSubDir("backups")
 }
 
+// PluginRollbackDir returns the path of the directory containing the previously installed version of a plugin,
+// which is restored by 'steampipe plugin rollback'
+func PluginRollbackDir(pluginImageDisplayRef string) string {
+	return filepath.Join(EnsureBackupsDir(), "plugins", filepath.FromSlash(pluginImageDisplayRef))
+}
+
 // BackupsDir returns the path to the backups directory
 func BackupsDir() string {
 	return steampipeSubDir("backups")
@@ -113,6 +133,12 @@ func WorkspaceProfileDir(installDir string) (string, error) {
 
 }
 
+// EnsureModCacheDir returns the path to the mod git cache directory (creates if missing)
+// this contains a bare clone of each mod repo, shared by all workspaces
+func EnsureModCacheDir() string {
+	return ensureSteampipeSubDir(filepath.Join("mods", "cache"))
+}
+
 // EnsureDatabaseDir returns the path to the db directory (creates if missing)
 func EnsureDatabaseDir() string {
 	return ensureSteampipeSubDir("db")
@@ -127,6 +153,12 @@ func EnsureDashboardAssetsDir() string {
 	return ensureSteampipeSubDir(filepath.Join("dashboard", "assets"))
 }
 
+// DashboardAssetsInstallFilePath returns the path of the file recording the installed dashboard assets
+// (this is not in the assets directory as it contains the checksum of that directory)
+func DashboardAssetsInstallFilePath() string {
+	return filepath.Join(EnsureInternalDir(), dashboardAssetsInstallFile)
+}
+
 // LegacyDashboardAssetsDir returns the path to the legacy report assets folder
 func LegacyDashboardAssetsDir() string {
 	return steampipeSubDir("report")
@@ -190,6 +222,74 @@ func PluginManagerStateFilePath() string {
 	return filepath.Join(EnsureInternalDir(), pluginManagerStateFileName)
 }
 
+// ServiceLockFilePath returns the path of the lock file which is held while the service processes are
+// being started, installed or reaped, so these are not performed concurrently by different steampipe processes
+func ServiceLockFilePath() string {
+	return filepath.Join(EnsureInternalDir(), serviceLockFileName)
+}
+
+// PluginDebugStateFilePath returns the path of the file listing the plugin instances with trace logging enabled
+func PluginDebugStateFilePath() string {
+	return filepath.Join(EnsureInternalDir(), pluginDebugStateFileName)
+}
+
+// PluginDebugLogPrefix returns the log file prefix for the trace log of the given plugin instance
+// (plugin instances may be image refs, so any characters which are not valid in a filename are replaced)
+func PluginDebugLogPrefix(pluginInstance string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, pluginInstance)
+	return fmt.Sprintf("plugin-debug-%s", sanitized)
+}
+
+// PluginDebugLogPath returns the path of the current trace log file for the given plugin instance
+func PluginDebugLogPath(pluginInstance string) string {
+	return filepath.Join(EnsureLogDir(), fmt.Sprintf("%s-%s.log", PluginDebugLogPrefix(pluginInstance), time.Now().Format(time.DateOnly)))
+}
+
+// ConfigKeyFilePath returns the path of the key file used to encrypt/decrypt config values
+func ConfigKeyFilePath() string {
+	return filepath.Join(EnsureInternalDir(), configKeyFileName)
+}
+
+// CliVersionFilePath returns the path of the file recording the CLI version which last ran
+func CliVersionFilePath() string {
+	return filepath.Join(EnsureInternalDir(), cliVersionFileName)
+}
+
+// PluginSdkVersionsFilePath returns the path of the file caching the sdk version of each installed plugin binary
+func PluginSdkVersionsFilePath() string {
+	return filepath.Join(EnsureInternalDir(), pluginSdkVersionsFileName)
+}
+
+// UsageReportFilePath returns the path of the file which usage reports are exported to
+func UsageReportFilePath() string {
+	return filepath.Join(EnsureInternalDir(), usageReportFileName)
+}
+
+// RefreshConnectionsReportPath returns the path of the file containing the result of the last connection refresh
+func RefreshConnectionsReportPath() string {
+	return filepath.Join(EnsureInternalDir(), refreshReportFileName)
+}
+
+// RefreshConnectionsHashPath returns the path of the file containing the hash of the inputs of the last successful connection refresh
+func RefreshConnectionsHashPath() string {
+	return filepath.Join(EnsureInternalDir(), refreshHashFileName)
+}
+
+// VariableStoreFilePath returns the path of the file containing the variable values persisted with 'steampipe variable set'
+func VariableStoreFilePath() string {
+	return filepath.Join(EnsureInternalDir(), variableStoreFileName)
+}
+
+// CloudConnectionCacheFilePath returns the path of the file caching the connection strings of cloud workspace databases
+func CloudConnectionCacheFilePath() string {
+	return filepath.Join(EnsureInternalDir(), cloudConnectionCacheFileName)
+}
+
 func DashboardServiceStateFilePath() string {
 	return filepath.Join(EnsureInternalDir(), dashboardServerStateFileName)
 }
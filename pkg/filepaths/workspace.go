@@ -20,6 +20,8 @@ const (
 	WorkspaceIgnoreFile         = ".steampipeignore"
 	DefaultVarsFileName         = "steampipe.spvars"
 	WorkspaceLockFileName       = ".mod.cache.json"
+	WorkspaceUsageFileName      = "usage.json"
+	PluginManifestFileName      = "plugins.spc"
 )
 
 func WorkspaceModPath(workspacePath string) string {
@@ -38,6 +40,11 @@ func WorkspaceLockPath(workspacePath string) string {
 	return path.Join(workspacePath, WorkspaceLockFileName)
 }
 
+// WorkspaceUsagePath returns the path of the file recording the tables and columns used by the workspace resources
+func WorkspaceUsagePath(workspacePath string) string {
+	return path.Join(workspacePath, WorkspaceDataDir, WorkspaceUsageFileName)
+}
+
 func DefaultVarsFilePath(workspacePath string) string {
 	return path.Join(workspacePath, DefaultVarsFileName)
 }
@@ -0,0 +1,225 @@
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the minimum age of an invalid state file before it is removed
+// (a state file which is being written may be read before it is complete)
+const invalidStateFileMinAge = time.Minute
+
+// Policy defines how old files must be before they are garbage collected
+type Policy struct {
+	LogRetention     time.Duration
+	TempDirRetention time.Duration
+	// whether stale service state files are removed
+	// this is only done by an explicit gc (i.e. 'steampipe service gc'), not the automatic gc run by the task runner
+	StateFiles bool
+}
+
+// PolicyFromConfig builds the garbage collection policy from the 'general' options
+// (stale state files are not removed)
+func PolicyFromConfig() Policy {
+	return Policy{
+		LogRetention:     time.Duration(viper.GetInt(constants.ArgLogRetentionDays)) * 24 * time.Hour,
+		TempDirRetention: time.Duration(viper.GetInt(constants.ArgTempDirRetentionHours)) * time.Hour,
+	}
+}
+
+// Result lists the items removed by garbage collection
+type Result struct {
+	TempDirs   []string
+	LogFiles   []string
+	StateFiles []string
+	// errors encountered while removing items - these do not stop garbage collection
+	Errors []error
+}
+
+func (r *Result) addError(err error) {
+	log.Printf("[TRACE] garbage collection error: %s", err)
+	r.Errors = append(r.Errors, err)
+}
+
+// CleanupFiles removes abandoned installer temp dirs, old log files and, if the policy allows, stale service state files
+func CleanupFiles(ctx context.Context, policy Policy) *Result {
+	utils.LogTime("gc.CleanupFiles start")
+	defer utils.LogTime("gc.CleanupFiles end")
+
+	res := &Result{}
+
+	// the ociinstaller creates temp dirs in the plugin dir (for plugins), the database dir (for the db and fdw)
+	// and the dashboard assets dir - these are left behind if an install is interrupted
+	tempDirParents := map[string]bool{
+		filepaths.EnsurePluginDir():          true,
+		filepaths.GetDatabaseLocation():      false,
+		filepaths.EnsureDashboardAssetsDir(): false,
+	}
+	for parent, recursive := range tempDirParents {
+		removed, err := RemoveOldTempDirs(ctx, parent, recursive, policy.TempDirRetention)
+		if err != nil {
+			res.addError(err)
+		}
+		res.TempDirs = append(res.TempDirs, removed...)
+	}
+
+	removed, err := removeOldLogFiles(policy.LogRetention)
+	if err != nil {
+		res.addError(err)
+	}
+	res.LogFiles = removed
+
+	if policy.StateFiles {
+		removeStaleStateFiles(res)
+	}
+
+	return res
+}
+
+// removeStaleStateFiles removes the service state files which refer to a process which is no longer running
+// the service lock is held while doing so - if the service is being started by another process, nothing is removed
+func removeStaleStateFiles(res *Result) {
+	lock, err := utils.TryLockFile(filepaths.ServiceLockFilePath())
+	if err != nil {
+		res.addError(err)
+		return
+	}
+	if lock == nil {
+		log.Printf("[TRACE] the service is being started by another process - not removing stale state files")
+		return
+	}
+	defer lock.Unlock()
+
+	for _, stateFile := range []string{
+		filepaths.RunningInfoFilePath(),
+		filepaths.PluginManagerStateFilePath(),
+		filepaths.DashboardServiceStateFilePath(),
+	} {
+		stale, err := removeStaleStateFile(stateFile)
+		if err != nil {
+			res.addError(err)
+		}
+		if stale {
+			res.StateFiles = append(res.StateFiles, stateFile)
+		}
+	}
+}
+
+// RemoveOldTempDirs removes any temp dirs (created by the ociinstaller) under the parent dir which have
+// not been modified for longer than maxAge, returning the paths of the removed dirs
+func RemoveOldTempDirs(ctx context.Context, parent string, recursive bool, maxAge time.Duration) ([]string, error) {
+	if _, err := os.Stat(parent); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	flags := files.DirectoriesFlat
+	if recursive {
+		flags = files.DirectoriesRecursive
+	}
+	tmpDirs, err := files.ListFilesWithContext(ctx, parent, &files.ListOptions{
+		Include: []string{"tmp-*"},
+		Flags:   flags,
+	})
+	if err != nil {
+		log.Printf("[TRACE] Error while globbing for tmp dirs in %s: %s", parent, err)
+		return nil, err
+	}
+
+	var removed []string
+	for _, tmpDir := range tmpDirs {
+		stat, err := os.Stat(tmpDir)
+		if err != nil {
+			log.Printf("[TRACE] Error while stating tmp dir %s: %s", tmpDir, err)
+			continue
+		}
+		if time.Since(stat.ModTime()) > maxAge {
+			if err := os.RemoveAll(tmpDir); err != nil {
+				log.Printf("[TRACE] Error while removing old tmp dir %s: %s", tmpDir, err)
+				continue
+			}
+			removed = append(removed, tmpDir)
+		}
+	}
+	return removed, nil
+}
+
+// removeOldLogFiles removes log files which have not been modified for longer than maxAge
+func removeOldLogFiles(maxAge time.Duration) ([]string, error) {
+	logDir := filepaths.EnsureLogDir()
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		log.Println("[TRACE] error listing log directory", err)
+		return nil, err
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".log" {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			log.Printf("[TRACE] error reading file info of %s. continuing\n", entry.Name())
+			continue
+		}
+		if time.Since(fi.ModTime()) > maxAge {
+			logPath := filepath.Join(logDir, entry.Name())
+			if err := os.Remove(logPath); err != nil {
+				log.Printf("[TRACE] failed to delete log file %s\n", logPath)
+				continue
+			}
+			removed = append(removed, logPath)
+		}
+	}
+	return removed, nil
+}
+
+// removeStaleStateFile removes a service state file if the process it refers to is no longer running
+// (e.g. if the service was killed) - returns whether the file was removed
+// an invalid state file is only removed once it is older than invalidStateFileMinAge, as it may still be being written
+func removeStaleStateFile(path string) (bool, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	// all service state files store the pid of the service process
+	var state struct {
+		Pid int `json:"pid"`
+	}
+	if err := json.Unmarshal(content, &state); err == nil && state.Pid != 0 {
+		pidExists, err := utils.PidExists(state.Pid)
+		if err != nil {
+			return false, err
+		}
+		if pidExists {
+			return false, nil
+		}
+	} else if time.Since(stat.ModTime()) < invalidStateFileMinAge {
+		return false, nil
+	}
+
+	// the file is either invalid or refers to a process which is not running
+	log.Printf("[TRACE] removing stale state file %s", path)
+	if err := os.Remove(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestRemoveStaleStateFile(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-2 * invalidStateFileMinAge)
+	testCases := map[string]struct {
+		content  string
+		modTime  time.Time
+		expected bool
+	}{
+		"running process": {
+			content: fmt.Sprintf(`{"pid": %d}`, os.Getpid()),
+			modTime: old,
+		},
+		"process not running": {
+			// pids are limited to 2^22 on linux
+			content:  `{"pid": 99999999}`,
+			modTime:  old,
+			expected: true,
+		},
+		"invalid file being written": {
+			content: `{"pi`,
+			modTime: time.Now(),
+		},
+		"old invalid file": {
+			content:  `{"pi`,
+			modTime:  old,
+			expected: true,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".json")
+			if err := os.WriteFile(path, []byte(test.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, test.modTime, test.modTime); err != nil {
+				t.Fatal(err)
+			}
+			removed, err := removeStaleStateFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if removed != test.expected {
+				t.Errorf("expected removed=%v, got %v", test.expected, removed)
+			}
+			if _, err := os.Stat(path); os.IsNotExist(err) != test.expected {
+				t.Errorf("expected file to exist=%v", !test.expected)
+			}
+		})
+	}
+}
+
+func TestCleanupFilesOnlyRemovesStateFilesIfExplicit(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+
+	stateFile := filepaths.PluginManagerStateFilePath()
+	if err := os.WriteFile(stateFile, []byte(`{"pid": 99999999}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := Policy{LogRetention: time.Hour, TempDirRetention: time.Hour}
+	if res := CleanupFiles(context.Background(), policy); len(res.StateFiles) != 0 {
+		t.Errorf("expected the automatic gc not to remove state files, removed %v", res.StateFiles)
+	}
+
+	policy.StateFiles = true
+	if res := CleanupFiles(context.Background(), policy); len(res.StateFiles) != 1 {
+		t.Errorf("expected the explicit gc to remove the stale state file, removed %v", res.StateFiles)
+	}
+}
@@ -2,45 +2,82 @@ package initialisation
 
 import (
 	"context"
-	"strings"
+	"log"
 
 	"github.com/spf13/viper"
 	"github.com/turbot/steampipe/pkg/cloud"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/network"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 )
 
+// getCloudMetadata resolves the connection strings of the workspace database and sets them in viper
+// the workspace database may be a comma separated list of endpoints - the first endpoint which resolves is used as the
+// connection string and the remainder are set as failover connection strings
+// returns the cloud metadata of the endpoint used as the connection string (nil if this is not a cloud workspace)
 func getCloudMetadata(ctx context.Context) (*steampipeconfig.CloudMetadata, error) {
 	workspaceDatabase := viper.GetString(constants.ArgWorkspaceDatabase)
 	if workspaceDatabase == "local" {
 		// local database - nothing to do here
 		return nil, nil
 	}
-	connectionString := workspaceDatabase
 
+	endpoints := steampipeconfig.WorkspaceDatabaseEndpoints(workspaceDatabase)
 	var cloudMetadata *steampipeconfig.CloudMetadata
-
-	// so a backend was set - is it a connection string or a database name
-	workspaceDatabaseIsConnectionString := strings.HasPrefix(workspaceDatabase, "postgresql://") || strings.HasPrefix(workspaceDatabase, "postgres://")
-	if !workspaceDatabaseIsConnectionString {
-		// it must be a database name - verify the cloud token was provided
-		cloudToken := viper.GetString(constants.ArgPipesToken)
-		if cloudToken == "" {
-			return nil, error_helpers.MissingCloudTokenError
+	var connectionStrings []string
+	var resolveErrors []error
+	for _, endpoint := range endpoints {
+		endpointMetadata, connectionString, err := resolveWorkspaceDatabaseEndpoint(ctx, endpoint)
+		if err != nil {
+			// only fail if no endpoint can be resolved
+			if len(endpoints) > 1 {
+				log.Printf("[WARN] failed to resolve workspace database endpoint %s: %s", endpoint, err.Error())
+			}
+			resolveErrors = append(resolveErrors, err)
+			continue
 		}
-
-		// so we have a database and a token - build the connection string and set it in viper
-		var err error
-		if cloudMetadata, err = cloud.GetCloudMetadata(ctx, workspaceDatabase, cloudToken); err != nil {
-			return nil, err
+		if len(connectionStrings) == 0 {
+			cloudMetadata = endpointMetadata
+		}
+		connectionStrings = append(connectionStrings, connectionString)
+	}
+	if len(connectionStrings) == 0 {
+		if len(resolveErrors) == 1 {
+			return nil, resolveErrors[0]
 		}
-		// read connection string out of cloudMetadata
-		connectionString = cloudMetadata.ConnectionString
+		return nil, error_helpers.CombineErrorsWithPrefix("failed to resolve any workspace database endpoint", resolveErrors...)
 	}
 
-	// now set the connection string in viper
-	viper.Set(constants.ArgConnectionString, connectionString)
+	// now set the connection strings in viper
+	viper.Set(constants.ArgConnectionString, connectionStrings[0])
+	viper.Set(constants.ConfigKeyFailoverConnectionStrings, connectionStrings[1:])
 
 	return cloudMetadata, nil
 }
+
+// resolveWorkspaceDatabaseEndpoint returns the connection string for a workspace database endpoint
+// - a connection string is used directly
+// - a workspace identifier requires a Turbot Pipes lookup (which is cached)
+func resolveWorkspaceDatabaseEndpoint(ctx context.Context, endpoint string) (*steampipeconfig.CloudMetadata, string, error) {
+	if steampipeconfig.IsConnectionString(endpoint) {
+		return nil, endpoint, nil
+	}
+
+	// it must be a database name - this requires a Turbot Pipes lookup
+	if err := network.CheckOnline("connecting to a Turbot Pipes workspace"); err != nil {
+		return nil, "", err
+	}
+	// verify the cloud token was provided
+	cloudToken := viper.GetString(constants.ArgPipesToken)
+	if cloudToken == "" {
+		return nil, "", error_helpers.MissingCloudTokenError
+	}
+
+	// so we have a database and a token - build the connection string
+	cloudMetadata, err := cloud.GetCachedCloudMetadata(ctx, endpoint, cloudToken)
+	if err != nil {
+		return nil, "", err
+	}
+	return cloudMetadata, cloudMetadata.ConnectionString, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe-plugin-sdk/v5/telemetry"
+	"github.com/turbot/steampipe/pkg/cloud"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/db/db_client"
 	"github.com/turbot/steampipe/pkg/db/db_common"
@@ -113,6 +114,13 @@ func (i *InitData) Init(ctx context.Context, invoker constants.Invoker, opts ...
 	// set cloud metadata (may be nil)
 	i.Workspace.CloudMetadata = cloudMetadata
 
+	// the mod and its dependencies must support this version of the CLI
+	// (this is enforced even when connecting to a remote database)
+	if err := validateSteampipeVersionRecursively(i.Workspace.Mod); err != nil {
+		i.Result.Error = err
+		return
+	}
+
 	statushooks.SetStatus(ctx, "Checking for required plugins")
 	log.Printf("[INFO] Checking for required plugins")
 	pluginsInstalled, err := plugin.GetInstalledPlugins(ctx)
@@ -124,8 +132,8 @@ func (i *InitData) Init(ctx context.Context, invoker constants.Invoker, opts ...
 	// no need to validate local steampipe and plugin versions for when connecting to remote steampipe database
 	// ArgConnectionString is empty when connecting to local database
 	if connectionString := viper.GetString(constants.ArgConnectionString); connectionString == "" {
-		// validate steampipe version and required plugin version
-		validationWarnings := validateModRequirementsRecursively(i.Workspace.Mod, pluginsInstalled)
+		// validate required plugin versions
+		validationWarnings := validatePluginRequirementsRecursively(i.Workspace.Mod, pluginsInstalled)
 		i.Result.AddWarnings(validationWarnings...)
 	}
 
@@ -148,7 +156,7 @@ func (i *InitData) Init(ctx context.Context, invoker constants.Invoker, opts ...
 	log.Printf("[INFO] Connecting to steampipe database")
 	client, errorsAndWarnings := GetDbClient(getClientCtx, invoker, ensureSessionData, opts...)
 	if errorsAndWarnings.Error != nil {
-		i.Result.Error = errorsAndWarnings.Error
+		i.Result.Error = error_helpers.NewExitCodeError(constants.ExitCodeDatabaseConnectionFailed, error_helpers.NewConnectionError(error_helpers.ErrorCodeDatabaseConnectionFailed, errorsAndWarnings.Error))
 		return
 	}
 
@@ -164,11 +172,27 @@ func (i *InitData) Init(ctx context.Context, invoker constants.Invoker, opts ...
 	i.Client = client
 }
 
-func validateModRequirementsRecursively(mod *modconfig.Mod, pluginVersionMap map[string]*modconfig.PluginVersionString) []string {
+func validateSteampipeVersionRecursively(mod *modconfig.Mod) error {
+	if err := mod.ValidateSteampipeVersion(); err != nil {
+		return err
+	}
+	for childDependencyName, childMod := range mod.ResourceMaps.Mods {
+		// skip references to self (see validatePluginRequirementsRecursively)
+		if childDependencyName == "local" || mod.DependencyName == childMod.DependencyName {
+			continue
+		}
+		if err := validateSteampipeVersionRecursively(childMod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePluginRequirementsRecursively(mod *modconfig.Mod, pluginVersionMap map[string]*modconfig.PluginVersionString) []string {
 	var validationErrors []string
 
 	// validate this mod
-	for _, err := range mod.ValidateRequirements(pluginVersionMap) {
+	for _, err := range mod.ValidatePluginVersions(pluginVersionMap) {
 		validationErrors = append(validationErrors, err.Error())
 	}
 
@@ -181,7 +205,7 @@ func validateModRequirementsRecursively(mod *modconfig.Mod, pluginVersionMap map
 			// this is a reference to self - skip (otherwise we will end up with a recursion loop)
 			continue
 		}
-		childValidationErrors := validateModRequirementsRecursively(childMod, pluginVersionMap)
+		childValidationErrors := validatePluginRequirementsRecursively(childMod, pluginVersionMap)
 		validationErrors = append(validationErrors, childValidationErrors...)
 	}
 
@@ -192,7 +216,16 @@ func validateModRequirementsRecursively(mod *modconfig.Mod, pluginVersionMap map
 func GetDbClient(ctx context.Context, invoker constants.Invoker, onConnectionCallback db_client.DbConnectionCallback, opts ...db_client.ClientOption) (db_common.Client, error_helpers.ErrorAndWarnings) {
 	if connectionString := viper.GetString(constants.ArgConnectionString); connectionString != "" {
 		statushooks.SetStatus(ctx, "Connecting to remote Steampipe database")
+		if failoverConnectionStrings := viper.GetStringSlice(constants.ConfigKeyFailoverConnectionStrings); len(failoverConnectionStrings) > 0 {
+			// if the credentials of a (cached) cloud workspace connection string are no longer valid, evict it from the cache
+			opts = append(opts,
+				db_client.WithFailoverConnectionStrings(failoverConnectionStrings),
+				db_client.WithAuthenticationFailureHandler(cloud.EvictCachedConnectionString))
+		}
 		client, err := db_client.NewDbClient(ctx, connectionString, onConnectionCallback, opts...)
+		if db_common.IsAuthenticationError(err) {
+			cloud.EvictCachedConnectionString(connectionString)
+		}
 		return client, error_helpers.NewErrorsAndWarning(err)
 	}
 
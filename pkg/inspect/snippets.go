@@ -0,0 +1,147 @@
+// Package inspect contains the built-in query snippets used by 'steampipe inspect'
+//
+// the snippets only read catalog metadata and the connection state table, so they are cheap to run
+// and never call the APIs of the plugins
+package inspect
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// LargestTablesLimit is the number of tables shown by the largest-tables snippet
+const LargestTablesLimit = 25
+
+// Snippet is a canned report
+// the query of every snippet takes a single argument - the names of the connections (schemas) to report on,
+// or null to report on all of them
+type Snippet struct {
+	Name        string
+	Short       string
+	Description string
+	Query       string
+}
+
+var snippets = []*Snippet{
+	{
+		Name:  "counts",
+		Short: "Show the number of tables and columns of each connection",
+		Description: `Show the plugin, state and number of tables and columns of each connection.
+
+A connection which is ready but has no tables usually has a configuration problem.`,
+		Query: fmt.Sprintf(`select
+	c.name as connection,
+	c.plugin,
+	c.state,
+	count(distinct r.oid) as tables,
+	count(a.attnum) as columns
+from
+	%[1]s.%[2]s c
+	left join pg_namespace n on n.nspname = c.name
+	left join pg_class r on r.relnamespace = n.oid and r.relkind in ('r', 'f', 'v', 'm', 'p')
+	left join pg_attribute a on a.attrelid = r.oid and a.attnum > 0 and not a.attisdropped
+where
+	$1::text[] is null or c.name = any($1)
+group by
+	c.name, c.plugin, c.state
+order by
+	c.name`, constants.InternalSchema, constants.ConnectionTable),
+	},
+	{
+		Name:  "plugins",
+		Short: "Show the number of connections and tables of each plugin",
+		Description: `Show the number of connections of each plugin, how many of them are ready or in error,
+and the number of tables provided by the plugin.`,
+		Query: fmt.Sprintf(`select
+	c.plugin,
+	count(distinct c.name) as connections,
+	count(distinct c.name) filter (where c.state = '%[3]s') as ready,
+	count(distinct c.name) filter (where c.state = '%[4]s') as error,
+	count(distinct r.relname) as tables
+from
+	%[1]s.%[2]s c
+	left join pg_namespace n on n.nspname = c.name
+	left join pg_class r on r.relnamespace = n.oid and r.relkind in ('r', 'f', 'v', 'm', 'p')
+where
+	$1::text[] is null or c.name = any($1)
+group by
+	c.plugin
+order by
+	c.plugin`, constants.InternalSchema, constants.ConnectionTable, constants.ConnectionStateReady, constants.ConnectionStateError),
+	},
+	{
+		Name:        "errors",
+		Short:       "Show the connections which failed to load",
+		Description: `Show the connections which are in error, with the error and the config file which defines them.`,
+		Query: fmt.Sprintf(`select
+	name as connection,
+	plugin,
+	error,
+	file_name,
+	start_line_number as line
+from
+	%[1]s.%[2]s
+where
+	state = '%[3]s'
+	and ($1::text[] is null or name = any($1))
+order by
+	name`, constants.InternalSchema, constants.ConnectionTable, constants.ConnectionStateError),
+	},
+	{
+		Name:  "largest-tables",
+		Short: "Show the largest tables stored in the database",
+		Description: fmt.Sprintf(`Show the %d largest tables and materialized views stored in the database, with their size
+and estimated row count.
+
+Connection tables are foreign tables which store no data, so are not shown. Row counts are
+estimated from the statistics collected by the database when the tables are analyzed.`, LargestTablesLimit),
+		Query: fmt.Sprintf(`select
+	n.nspname as schema,
+	r.relname as "table",
+	case r.relkind when 'm' then 'materialized view' else 'table' end as type,
+	greatest(r.reltuples, 0)::bigint as estimated_rows,
+	pg_size_pretty(pg_total_relation_size(r.oid)) as size
+from
+	pg_class r
+	join pg_namespace n on n.oid = r.relnamespace
+where
+	r.relkind in ('r', 'm', 'p')
+	and n.nspname not in ('pg_catalog', 'information_schema')
+	and n.nspname not like 'pg_toast%%'
+	and ($1::text[] is null or n.nspname = any($1))
+order by
+	pg_total_relation_size(r.oid) desc,
+	n.nspname,
+	r.relname
+limit %d`, LargestTablesLimit),
+	},
+}
+
+// Snippets returns the built-in snippets, sorted by name
+func Snippets() []*Snippet {
+	res := make([]*Snippet, len(snippets))
+	copy(res, snippets)
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// GetSnippet returns the snippet with the given name
+func GetSnippet(name string) (*Snippet, bool) {
+	for _, s := range snippets {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// QueryArgs returns the arguments of the snippet query which reports on the given connections
+// (all connections if none are given)
+func QueryArgs(connections []string) []any {
+	if len(connections) == 0 {
+		return []any{nil}
+	}
+	return []any{connections}
+}
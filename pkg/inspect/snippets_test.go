@@ -0,0 +1,35 @@
+package inspect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnippets(t *testing.T) {
+	names := make(map[string]bool)
+	for _, s := range Snippets() {
+		if names[s.Name] {
+			t.Errorf("duplicate snippet name %s", s.Name)
+		}
+		names[s.Name] = true
+		if !strings.Contains(s.Query, "$1") {
+			t.Errorf("snippet %s does not filter by connection", s.Name)
+		}
+		if res, ok := GetSnippet(s.Name); !ok || res != s {
+			t.Errorf("GetSnippet(%s) did not return the snippet", s.Name)
+		}
+	}
+	if _, ok := GetSnippet("unknown"); ok {
+		t.Errorf("GetSnippet returned a snippet for an unknown name")
+	}
+}
+
+func TestQueryArgs(t *testing.T) {
+	if args := QueryArgs(nil); len(args) != 1 || args[0] != nil {
+		t.Errorf("expected a nil filter, got %v", args)
+	}
+	args := QueryArgs([]string{"aws", "gcp"})
+	if connections, ok := args[0].([]string); !ok || len(connections) != 2 {
+		t.Errorf("expected the connections as the filter, got %v", args)
+	}
+}
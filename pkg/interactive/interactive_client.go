@@ -10,6 +10,7 @@ import (
 	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alecthomas/chroma/formatters"
@@ -67,6 +68,10 @@ type InteractiveClient struct {
 	highlighter    *Highlighter
 	// hidePrompt is used to render a blank as the prompt prefix
 	hidePrompt bool
+	// the initial text of the next prompt - this is set by metaqueries which insert text into the prompt
+	nextPromptText string
+	// flag set while a query is waiting for connections to be loaded - refresh progress is displayed while this is set
+	waitingForConnections atomic.Bool
 
 	suggestions *autoCompleteSuggestions
 }
@@ -222,6 +227,7 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) {
 		callExecutor,
 		completer,
 		prompt.OptionTitle("steampipe interactive client "),
+		prompt.OptionInitialBufferText(c.nextPromptText),
 		prompt.OptionLivePrefix(func() (prefix string, useLive bool) {
 			prefix = "> "
 			useLive = true
@@ -313,6 +319,8 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) {
 			return cleanBufferForWSL(input)
 		}),
 	)
+	// the initial text only applies to this prompt
+	c.nextPromptText = ""
 	// set this to a default
 	c.autocompleteOnEmpty = false
 	c.interactivePrompt.RunCtx(ctx)
@@ -386,10 +394,20 @@ func (c *InteractiveClient) executor(ctx context.Context, line string) {
 func (c *InteractiveClient) executeQuery(ctx context.Context, queryCtx context.Context, resolvedQuery *modconfig.ResolvedQuery) {
 	// if there is a custom search path, wait until the first connection of each plugin has loaded
 	if customSearchPath := c.client().GetCustomSearchPath(); customSearchPath != nil {
-		if err := connection_sync.WaitForSearchPathSchemas(ctx, c.client(), customSearchPath); err != nil {
+		c.waitingForConnections.Store(true)
+		err := connection_sync.WaitForSearchPathSchemas(ctx, c.client(), customSearchPath)
+		c.waitingForConnections.Store(false)
+		if err != nil {
 			error_helpers.ShowError(ctx, err)
 			return
 		}
+		statushooks.SetStatus(ctx, "Executing query…")
+	}
+
+	// if a cache bypass was requested using '.cache bypass-once', it applies to this query only
+	if viper.GetBool(constants.ConfigKeyCacheBypassOnce) {
+		viper.Set(constants.ConfigKeyCacheBypassOnce, false)
+		queryCtx = db_common.WithCacheBypass(queryCtx)
 	}
 
 	t := time.Now()
@@ -533,6 +551,7 @@ func (c *InteractiveClient) executeMetaquery(ctx context.Context, query string)
 		SearchPath:            client.GetRequiredSessionSearchPath(),
 		Prompt:                c.interactivePrompt,
 		ClosePrompt:           func() { c.afterClose = AfterPromptCloseExit },
+		SetPromptText:         func(text string) { c.nextPromptText = text },
 		GetConnectionStateMap: c.getConnectionState,
 	})
 }
@@ -719,7 +738,24 @@ func (c *InteractiveClient) handlePostgresNotification(ctx context.Context, noti
 			return
 		}
 		c.handleErrorsAndWarningsNotification(ctx, errorNotification)
+	case steampipeconfig.PgNotificationRefreshProgress:
+		progressNotification := &steampipeconfig.RefreshProgressNotification{}
+		if err := json.Unmarshal([]byte(notification.Payload), progressNotification); err != nil {
+			log.Printf("[WARN] Error unmarshalling notification: %s", err)
+			return
+		}
+		c.handleRefreshProgressNotification(ctx, progressNotification)
+	}
+}
+
+// handleRefreshProgressNotification displays the progress of a connection refresh
+// - this is only displayed while a query is waiting for connections to be loaded
+func (c *InteractiveClient) handleRefreshProgressNotification(ctx context.Context, notification *steampipeconfig.RefreshProgressNotification) {
+	if !c.waitingForConnections.Load() {
+		return
 	}
+	msg := fmt.Sprintf("Waiting for connections: %d of %d loaded…", notification.Complete, notification.Total)
+	statushooks.SetProgress(ctx, msg, notification.Complete, notification.Total)
 }
 
 func (c *InteractiveClient) handleErrorsAndWarningsNotification(ctx context.Context, notification *steampipeconfig.ErrorsAndWarningsNotification) {
@@ -53,10 +53,12 @@ func (c *InteractiveClient) initialiseSchemaAndTableSuggestions(connectionStateM
 
 	// add connection state and rate limit
 	unqualifiedTablesToAdd[constants.ConnectionTable] = struct{}{}
+	unqualifiedTablesToAdd[constants.ConnectionStateHistoryTable] = struct{}{}
 	unqualifiedTablesToAdd[constants.PluginInstanceTable] = struct{}{}
 	unqualifiedTablesToAdd[constants.RateLimiterDefinitionTable] = struct{}{}
 	unqualifiedTablesToAdd[constants.PluginColumnTable] = struct{}{}
 	unqualifiedTablesToAdd[constants.ServerSettingsTable] = struct{}{}
+	unqualifiedTablesToAdd[constants.ScheduleTable] = struct{}{}
 
 	// get the first search path connection for each plugin
 	firstConnectionPerPlugin := connectionStateMap.GetFirstSearchPathConnectionForPlugins(c.client().GetRequiredSessionSearchPath())
@@ -103,11 +103,12 @@ func init() {
 			title:       constants.CmdCache,
 			handler:     cacheControl,
 			validator:   validatorFromArgsOf(constants.CmdCache),
-			description: "Enable, disable or clear the query cache",
+			description: "Enable, disable, clear or bypass the query cache",
 			args: []metaQueryArg{
 				{value: constants.ArgOn, description: "Turn on caching"},
 				{value: constants.ArgOff, description: "Turn off caching"},
 				{value: constants.ArgClear, description: "Clear the cache"},
+				{value: constants.ArgBypassOnce, description: "Bypass the cache for the next query only"},
 			},
 			completer: completerFromArgsOf(constants.CmdCache),
 		},
@@ -127,6 +128,13 @@ func init() {
 			description: "View connections, tables & column information",
 			completer:   inspectCompleter,
 		},
+		constants.CmdBrowse: {
+			title:       constants.CmdBrowse,
+			handler:     browse,
+			validator:   atMostNArgs(1),
+			description: "Browse connections, tables & columns - the selected table or column is inserted into the prompt",
+			completer:   inspectCompleter,
+		},
 		constants.CmdConnections: {
 			title:       constants.CmdConnections,
 			handler:     listConnections,
@@ -162,5 +170,23 @@ func init() {
 			},
 			completer: completerFromArgsOf(constants.CmdAutoComplete),
 		},
+		constants.CmdNullValue: {
+			title:       constants.CmdNullValue,
+			handler:     setNullValue,
+			validator:   atMostNArgs(1),
+			description: "Set or show the string used to display null values",
+		},
+		constants.CmdWidth: {
+			title:       constants.CmdWidth,
+			handler:     setWidth,
+			validator:   atMostNArgs(2),
+			description: "Set or show the max width of table output columns - usage: .width [column] <width>, .width auto (fit to the terminal) or .width reset",
+		},
+		constants.CmdCancel: {
+			title:       constants.CmdCancel,
+			handler:     cancelQuery,
+			validator:   atMostNArgs(1),
+			description: "List the queries running in other sessions, or cancel the query running in the session with the given backend pid",
+		},
 	}
 }
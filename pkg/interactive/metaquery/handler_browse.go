@@ -0,0 +1,65 @@
+package metaquery
+
+import (
+	"context"
+	"os"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"golang.org/x/term"
+)
+
+// .browse
+// open a navigable tree of connections, tables and columns - the selected table or column is inserted into the prompt
+func browse(_ context.Context, input *HandlerInput) error {
+	stdinFd := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdinFd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return sperr.New("%s requires a terminal", constants.CmdBrowse)
+	}
+	if input.Schema == nil {
+		return sperr.New("schema metadata is not loaded")
+	}
+
+	var startSchema string
+	if args := input.args(); len(args) > 0 {
+		startSchema = args[0]
+		if _, ok := input.Schema.Schemas[startSchema]; !ok {
+			return sperr.New("connection '%s' does not exist", startSchema)
+		}
+	}
+	browser := newSchemaBrowser(input.Schema, startSchema)
+
+	oldState, err := term.MakeRaw(stdinFd)
+	if err != nil {
+		return err
+	}
+	// use the alternate screen buffer and hide the cursor while browsing
+	os.Stdout.WriteString("\033[?1049h\033[?25l")
+	defer func() {
+		os.Stdout.WriteString("\033[?25h\033[?1049l")
+		//nolint:errcheck // nothing we can do if we fail to restore the terminal
+		term.Restore(stdinFd, oldState)
+	}()
+
+	buf := make([]byte, 8)
+	for {
+		width, height, err := term.GetSize(stdinFd)
+		if err != nil {
+			return err
+		}
+		browser.render(os.Stdout, width, height)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		done, selected := browser.handleKey(parseBrowserKey(buf[:n]))
+		if !done {
+			continue
+		}
+		if selected != nil && input.SetPromptText != nil {
+			input.SetPromptText(selected.insertText)
+		}
+		return nil
+	}
+}
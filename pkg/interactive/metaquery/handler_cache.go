@@ -20,6 +20,13 @@ func cacheControl(ctx context.Context, input *HandlerInput) error {
 		return showCache(ctx, input)
 	}
 
+	// bypass-once does not change the session cache settings - the client bypasses the cache when executing the next query
+	if strings.ToLower(input.args()[0]) == constants.ArgBypassOnce {
+		viper.Set(constants.ConfigKeyCacheBypassOnce, true)
+		fmt.Println("The cache will be bypassed for the next query.")
+		return nil
+	}
+
 	// just get the active session from the connection pool
 	// and set the cache parameters on it.
 	// NOTE: this works because the interactive client
@@ -0,0 +1,61 @@
+package metaquery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// the queries currently running in the given sessions of the database, longest running first
+const runningQueriesQuery = `select pid, (now() - query_start)::text, query
+from pg_stat_activity
+where state = 'active' and pid <> pg_backend_pid() and pid = any($1)
+order by query_start`
+
+// .cancel
+// with no args, list the queries running in the other sessions of this client
+// .cancel <backend_pid> - cancel the query running in the session with the given backend pid
+// (only the queries of this client may be cancelled)
+func cancelQuery(ctx context.Context, input *HandlerInput) error {
+	args := input.args()
+	if len(args) == 0 {
+		return showRunningQueries(ctx, input)
+	}
+
+	backendPid, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil || backendPid == 0 {
+		return sperr.New("invalid backend pid '%s' - usage: %s <backend_pid>", args[0], constants.CmdCancel)
+	}
+	if err := input.Client.CancelQuery(ctx, uint32(backendPid)); err != nil {
+		return err
+	}
+	fmt.Printf("Cancelled the query running in backend %d.\n", backendPid)
+	return nil
+}
+
+func showRunningQueries(ctx context.Context, input *HandlerInput) error {
+	rows := [][]string{{"PID", "RUNNING FOR", "QUERY"}}
+	err := input.Client.StreamQuery(ctx, runningQueriesQuery, func(_ []*queryresult.ColumnDef, row []interface{}) error {
+		rows = append(rows, []string{
+			fmt.Sprintf("%v", row[0]),
+			fmt.Sprintf("%v", row[1]),
+			// show the query on a single line
+			strings.Join(strings.Fields(fmt.Sprintf("%v", row[2])), " "),
+		})
+		return nil
+	}, input.Client.SessionBackendPids())
+	if err != nil {
+		return err
+	}
+	if len(rows) == 1 {
+		fmt.Println("No queries are running in other sessions.")
+		return nil
+	}
+	fmt.Printf("%s\nCancel a query with: %s\n", buildTable(rows, false), constants.Bold(fmt.Sprintf("%s <pid>", constants.CmdCancel)))
+	return nil
+}
@@ -15,8 +15,10 @@ type HandlerInput struct {
 	Client db_common.Client
 	Schema *db_common.SchemaMetadata
 
-	Prompt                *prompt.Prompt
-	ClosePrompt           func()
+	Prompt      *prompt.Prompt
+	ClosePrompt func()
+	// SetPromptText sets the initial text of the next prompt
+	SetPromptText         func(string)
 	Query                 string
 	GetConnectionStateMap ConnectionStateGetter
 	SearchPath            []string
@@ -0,0 +1,82 @@
+package metaquery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// .width
+// with no args, show the current column widths
+// .width reset                  - clear all configured widths and restore the default max width, so long values are wrapped rather than truncated
+// .width <width>                - set the max width for all columns
+// .width auto                   - fit the columns to the terminal width
+// .width <column> <width>       - set the max width for a single column
+func setWidth(_ context.Context, input *HandlerInput) error {
+	args := input.args()
+	switch len(args) {
+	case 0:
+		showWidths()
+		return nil
+	case 1:
+		if args[0] == constants.ArgWidthReset {
+			// unset the max width, so the value configured at startup (if any) or the flag default is used
+			cmdconfig.Viper().Set(constants.ArgMaxColWidth, nil)
+			cmdconfig.Viper().Set(constants.ConfigKeyColumnWidths, map[string]interface{}{})
+			return nil
+		}
+		if strings.EqualFold(args[0], constants.MaxColumnWidthAuto) {
+			cmdconfig.Viper().Set(constants.ArgMaxColWidth, constants.MaxColumnWidthAuto)
+			return nil
+		}
+		width, err := parseWidth(args[0])
+		if err != nil {
+			return err
+		}
+		cmdconfig.Viper().Set(constants.ArgMaxColWidth, width)
+		return nil
+	default:
+		width, err := parseWidth(args[1])
+		if err != nil {
+			return err
+		}
+		widths := cmdconfig.Viper().GetStringMap(constants.ConfigKeyColumnWidths)
+		if widths == nil {
+			widths = map[string]interface{}{}
+		}
+		// viper keys are case insensitive
+		widths[strings.ToLower(args[0])] = width
+		cmdconfig.Viper().Set(constants.ConfigKeyColumnWidths, widths)
+		return nil
+	}
+}
+
+func parseWidth(arg string) (int, error) {
+	width, err := strconv.Atoi(arg)
+	if err != nil || width < 1 {
+		return 0, fmt.Errorf("invalid width '%s' - width must be a positive integer", arg)
+	}
+	return width, nil
+}
+
+func showWidths() {
+	fmt.Printf("Max column width is %s.", constants.Bold(cmdconfig.Viper().GetString(constants.ArgMaxColWidth)))
+	widths := cmdconfig.Viper().GetStringMap(constants.ConfigKeyColumnWidths)
+	if len(widths) > 0 {
+		columns := make([]string, 0, len(widths))
+		for c := range widths {
+			columns = append(columns, c)
+		}
+		sort.Strings(columns)
+		for _, c := range columns {
+			fmt.Printf("\n  %s: %v", c, widths[c])
+		}
+	}
+	// add an empty line here so that the rendering buffer can start from the next line
+	fmt.Println()
+}
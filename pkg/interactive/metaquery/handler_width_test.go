@@ -0,0 +1,82 @@
+package metaquery
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/cmdconfig"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestSetWidth(t *testing.T) {
+	v := cmdconfig.Viper()
+	defer func() {
+		v.Set(constants.ArgMaxColWidth, nil)
+		v.Set(constants.ConfigKeyColumnWidths, nil)
+	}()
+
+	tests := map[string]struct {
+		query          string
+		err            bool
+		expectedWidth  string
+		expectedWidths map[string]interface{}
+	}{
+		"max width": {
+			query:         ".width 40",
+			expectedWidth: "40",
+		},
+		"auto": {
+			query:         ".width AUTO",
+			expectedWidth: constants.MaxColumnWidthAuto,
+		},
+		"column width": {
+			query:          ".width Name 20",
+			expectedWidth:  "80",
+			expectedWidths: map[string]interface{}{"name": 20},
+		},
+		// the max width is unset, so long values are wrapped at the default max width rather than truncated
+		"reset restores the default": {
+			query:          ".width reset",
+			expectedWidth:  "",
+			expectedWidths: map[string]interface{}{},
+		},
+		"invalid width": {
+			query: ".width 0",
+			err:   true,
+		},
+		"invalid column width": {
+			query: ".width name wide",
+			err:   true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v.Set(constants.ArgMaxColWidth, 80)
+			v.Set(constants.ConfigKeyColumnWidths, map[string]interface{}{})
+
+			err := setWidth(context.Background(), &HandlerInput{Query: test.query})
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error for '%s'", test.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if width := v.GetString(constants.ArgMaxColWidth); width != test.expectedWidth {
+				t.Errorf("expected max width %s, got %s", test.expectedWidth, width)
+			}
+			if isSet := v.IsSet(constants.ArgMaxColWidth); isSet != (test.expectedWidth != "") {
+				t.Errorf("expected max width set: %v, got %v", test.expectedWidth != "", isSet)
+			}
+			if test.expectedWidths == nil {
+				test.expectedWidths = map[string]interface{}{}
+			}
+			if widths := v.GetStringMap(constants.ConfigKeyColumnWidths); !reflect.DeepEqual(widths, test.expectedWidths) {
+				t.Errorf("expected column widths %v, got %v", test.expectedWidths, widths)
+			}
+		})
+	}
+}
@@ -62,6 +62,28 @@ func showTimingFlag() {
 	return
 }
 
+// .nullvalue
+// set the ArgNullString viper key with the value from arg[0], stripping any enclosing single quotes
+func setNullValue(_ context.Context, input *HandlerInput) error {
+	if len(input.args()) == 0 {
+		nullString := constants.NullString
+		if cmdconfig.Viper().IsSet(constants.ArgNullString) {
+			nullString = cmdconfig.Viper().GetString(constants.ArgNullString)
+		}
+		fmt.Printf("Null values are displayed as '%s'", constants.Bold(nullString))
+		// add an empty line here so that the rendering buffer can start from the next line
+		fmt.Println()
+		return nil
+	}
+
+	nullString := input.args()[0]
+	if len(nullString) >= 2 && strings.HasPrefix(nullString, "'") && strings.HasSuffix(nullString, "'") {
+		nullString = nullString[1 : len(nullString)-1]
+	}
+	cmdconfig.Viper().Set(constants.ArgNullString, nullString)
+	return nil
+}
+
 // .separator and .output
 // set the value of `viperKey` in `viper` with the value from `args[0]`
 func setViperConfigFromArg(viperKey string) handler {
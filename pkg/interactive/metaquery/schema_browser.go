@@ -0,0 +1,244 @@
+package metaquery
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"golang.org/x/exp/maps"
+)
+
+// identifiers which do not need to be quoted when inserted into the prompt
+var unquotedIdentifierRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+type browserKey int
+
+const (
+	browserKeyNone browserKey = iota
+	browserKeyUp
+	browserKeyDown
+	browserKeyLeft
+	browserKeyRight
+	browserKeyEnter
+	browserKeyQuit
+)
+
+// browserNode is a connection, table or column in the schema browser tree
+type browserNode struct {
+	label       string
+	description string
+	// the text inserted into the prompt when this node is selected (empty for connections)
+	insertText string
+	parent     *browserNode
+	children   []*browserNode
+	expanded   bool
+	depth      int
+}
+
+// schemaBrowser is a navigable tree of connections, tables and columns, built from the schema metadata
+type schemaBrowser struct {
+	roots []*browserNode
+	// the index of the selected node in the visible nodes
+	cursor int
+	// the index of the first visible node which is rendered
+	offset int
+}
+
+// newSchemaBrowser builds the browser tree - if startSchema is set, that connection is initially expanded and selected
+func newSchemaBrowser(schemaMetadata *db_common.SchemaMetadata, startSchema string) *schemaBrowser {
+	b := &schemaBrowser{}
+	for _, schemaName := range schemaMetadata.GetSchemas() {
+		// do not show the temporary schema
+		if schemaName == schemaMetadata.TemporarySchemaName {
+			continue
+		}
+		schemaNode := &browserNode{label: schemaName}
+		tables := schemaMetadata.Schemas[schemaName]
+		tableNames := maps.Keys(tables)
+		sort.Strings(tableNames)
+		for _, tableName := range tableNames {
+			table := tables[tableName]
+			tableNode := &browserNode{
+				label:       tableName,
+				description: table.Description,
+				insertText:  fmt.Sprintf("%s.%s", quoteIdentifierIfNeeded(schemaName), quoteIdentifierIfNeeded(tableName)),
+				parent:      schemaNode,
+				depth:       1,
+			}
+			columnNames := maps.Keys(table.Columns)
+			sort.Strings(columnNames)
+			for _, columnName := range columnNames {
+				column := table.Columns[columnName]
+				tableNode.children = append(tableNode.children, &browserNode{
+					label:       fmt.Sprintf("%s (%s)", columnName, column.Type),
+					description: column.Description,
+					insertText:  quoteIdentifierIfNeeded(columnName),
+					parent:      tableNode,
+					depth:       2,
+				})
+			}
+			schemaNode.children = append(schemaNode.children, tableNode)
+		}
+		b.roots = append(b.roots, schemaNode)
+
+		if schemaName == startSchema {
+			schemaNode.expanded = true
+			b.cursor = len(b.visibleNodes()) - len(schemaNode.children) - 1
+		}
+	}
+	return b
+}
+
+// visibleNodes returns the nodes which are visible, i.e. all roots and the children of expanded nodes
+func (b *schemaBrowser) visibleNodes() []*browserNode {
+	var res []*browserNode
+	var add func(nodes []*browserNode)
+	add = func(nodes []*browserNode) {
+		for _, n := range nodes {
+			res = append(res, n)
+			if n.expanded {
+				add(n.children)
+			}
+		}
+	}
+	add(b.roots)
+	return res
+}
+
+// handleKey updates the browser state for the given key
+// it returns whether browsing is complete, and the node which was selected (if any)
+func (b *schemaBrowser) handleKey(key browserKey) (done bool, selected *browserNode) {
+	nodes := b.visibleNodes()
+	if len(nodes) == 0 {
+		return key == browserKeyQuit || key == browserKeyEnter, nil
+	}
+	current := nodes[b.cursor]
+
+	switch key {
+	case browserKeyUp:
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case browserKeyDown:
+		if b.cursor < len(nodes)-1 {
+			b.cursor++
+		}
+	case browserKeyRight:
+		if len(current.children) > 0 {
+			current.expanded = true
+		}
+	case browserKeyLeft:
+		// collapse the current node, or if it is not expanded, move to its parent
+		if current.expanded {
+			current.expanded = false
+		} else if current.parent != nil {
+			current.parent.expanded = false
+			b.cursor = b.indexOf(current.parent)
+		}
+	case browserKeyEnter:
+		// connections are expanded/collapsed - tables and columns are selected
+		if current.insertText == "" {
+			current.expanded = !current.expanded
+			return false, nil
+		}
+		return true, current
+	case browserKeyQuit:
+		return true, nil
+	}
+	return false, nil
+}
+
+func (b *schemaBrowser) indexOf(node *browserNode) int {
+	for i, n := range b.visibleNodes() {
+		if n == node {
+			return i
+		}
+	}
+	return 0
+}
+
+// render writes the visible portion of the tree which contains the cursor
+func (b *schemaBrowser) render(w io.Writer, width, height int) {
+	nodes := b.visibleNodes()
+	// reserve lines for the header and footer
+	rows := height - 2
+	if rows < 1 {
+		rows = 1
+	}
+	// scroll so the cursor is visible
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	} else if b.cursor >= b.offset+rows {
+		b.offset = b.cursor - rows + 1
+	}
+
+	var sb strings.Builder
+	// clear the screen and move to the top left
+	sb.WriteString("\033[H\033[2J")
+	sb.WriteString(truncateToWidth("Schema browser - ↑/↓ move, →/← expand/collapse, enter inserts into the prompt, q quits", width))
+	sb.WriteString("\r\n")
+	for i := b.offset; i < len(nodes) && i < b.offset+rows; i++ {
+		n := nodes[i]
+		marker := " "
+		if len(n.children) > 0 {
+			marker = "+"
+			if n.expanded {
+				marker = "-"
+			}
+		}
+		line := fmt.Sprintf("%s%s %s", strings.Repeat("  ", n.depth), marker, n.label)
+		if n.description != "" {
+			line = fmt.Sprintf("%s - %s", line, n.description)
+		}
+		line = truncateToWidth(line, width)
+		if i == b.cursor {
+			// render the selected line in reverse video
+			line = fmt.Sprintf("\033[7m%s\033[0m", line)
+		}
+		sb.WriteString(line)
+		sb.WriteString("\r\n")
+	}
+	sb.WriteString(fmt.Sprintf("%d connections", len(b.roots)))
+	fmt.Fprint(w, sb.String())
+}
+
+// parseBrowserKey converts the bytes read from the terminal into a browserKey
+func parseBrowserKey(input []byte) browserKey {
+	switch string(input) {
+	case "\033[A", "k":
+		return browserKeyUp
+	case "\033[B", "j":
+		return browserKeyDown
+	case "\033[C", "l":
+		return browserKeyRight
+	case "\033[D", "h":
+		return browserKeyLeft
+	case "\r", "\n":
+		return browserKeyEnter
+	// escape, q or ctrl+c
+	case "\033", "q", "\003":
+		return browserKeyQuit
+	}
+	return browserKeyNone
+}
+
+func quoteIdentifierIfNeeded(name string) string {
+	if unquotedIdentifierRegex.MatchString(name) {
+		return name
+	}
+	return db_common.PgEscapeName(name)
+}
+
+func truncateToWidth(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
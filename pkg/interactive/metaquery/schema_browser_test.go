@@ -0,0 +1,45 @@
+package metaquery
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+func TestSchemaBrowser(t *testing.T) {
+	schemaMetadata := &db_common.SchemaMetadata{
+		Schemas: map[string]map[string]db_common.TableSchema{
+			"aws": {
+				"aws_account": {Name: "aws_account", Columns: map[string]db_common.ColumnSchema{"account_id": {Name: "account_id", Type: "text"}}},
+			},
+			"My Conn": {
+				"Table": {Name: "Table"},
+			},
+		},
+	}
+	b := newSchemaBrowser(schemaMetadata, "aws")
+
+	// "My Conn" sorts before "aws" - "aws" should be expanded and selected
+	if nodes := b.visibleNodes(); len(nodes) != 3 || nodes[b.cursor].label != "aws" {
+		t.Fatalf("expected aws to be expanded and selected, got %d nodes, cursor %d", len(nodes), b.cursor)
+	}
+	// move to the table, expand it and select the column
+	b.handleKey(browserKeyDown)
+	b.handleKey(browserKeyRight)
+	b.handleKey(browserKeyDown)
+	if done, selected := b.handleKey(browserKeyEnter); !done || selected == nil || selected.insertText != "account_id" {
+		t.Fatalf("expected account_id to be selected, got %v", selected)
+	}
+	// collapsing from a column moves to the table
+	b.handleKey(browserKeyLeft)
+	if done, selected := b.handleKey(browserKeyEnter); !done || selected.insertText != "aws.aws_account" {
+		t.Fatalf("expected aws.aws_account to be selected, got %v", selected)
+	}
+
+	// names which are not valid unquoted identifiers are quoted
+	b = newSchemaBrowser(schemaMetadata, "My Conn")
+	b.handleKey(browserKeyDown)
+	if _, selected := b.handleKey(browserKeyEnter); selected == nil || selected.insertText != `"My Conn"."Table"` {
+		t.Fatalf(`expected "My Conn"."Table" to be selected, got %v`, selected)
+	}
+}
@@ -0,0 +1,80 @@
+package introspection
+
+import (
+	"fmt"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// NOTE: unlike the connection state table, the history table is NOT dropped when the service starts
+// - history is pruned of entries older than the retention period instead
+
+func GetConnectionStateHistoryTableCreateSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (
+				name TEXT,
+				state TEXT,
+				error TEXT NULL,
+				transition_time TIMESTAMPTZ DEFAULT now()
+		);`, constants.InternalSchema, constants.ConnectionStateHistoryTable),
+	}
+}
+
+// GetConnectionStateHistoryTableGrantSql returns the sql to setup SELECT permission for the 'steampipe_users' role
+func GetConnectionStateHistoryTableGrantSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(
+			`GRANT SELECT ON TABLE %s.%s TO %s;`,
+			constants.InternalSchema,
+			constants.ConnectionStateHistoryTable,
+			constants.DatabaseUsersRole,
+		),
+	}
+}
+
+// GetConnectionStateHistoryPruneSql returns the sql to delete history older than the retention period
+func GetConnectionStateHistoryPruneSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(
+			`DELETE FROM %s.%s WHERE transition_time < now() - interval '%d days';`,
+			constants.InternalSchema,
+			constants.ConnectionStateHistoryTable,
+			constants.ConnectionStateHistoryRetentionDays,
+		),
+	}
+}
+
+// GetConnectionStateHistoryInsertSql returns the sql to record a transition of the connection to the given state
+func GetConnectionStateHistoryInsertSql(connectionName, state string, connectionError *string) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(
+			`INSERT INTO %s.%s (name, state, error) VALUES ($1, $2, $3);`,
+			constants.InternalSchema,
+			constants.ConnectionStateHistoryTable,
+		),
+		Args: []any{connectionName, state, connectionError},
+	}
+}
+
+// GetIncompleteConnectionStateHistoryErrorSql returns the sql to record the transition of all incomplete connections to 'error'
+// NOTE: this must be executed BEFORE GetIncompleteConnectionStateErrorSql, which updates the state of these connections
+func GetIncompleteConnectionStateHistoryErrorSql(err error) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`INSERT INTO %s.%s (name, state, error)
+SELECT name, '%s', $1
+FROM %s.%s
+WHERE
+	state <> 'ready'
+AND state <> 'disabled'
+AND state <> 'error'
+`,
+			constants.InternalSchema,
+			constants.ConnectionStateHistoryTable,
+			constants.ConnectionStateError,
+			constants.InternalSchema,
+			constants.ConnectionTable,
+		),
+		Args: []any{err.Error()},
+	}
+}
@@ -21,6 +21,10 @@ func GetConnectionStateTableCreateSql() []db_common.QueryWithArgs {
 	state TEXT,
 	type TEXT NULL,
 	connections TEXT[] NULL,
+	included_connections TEXT[] NULL,
+	excluded_connections JSONB NULL,
+	aggregator_views TEXT NOT NULL DEFAULT '',
+	column_types JSONB NULL,
 	import_schema TEXT,
 	error TEXT NULL,
 	plugin TEXT,
@@ -94,8 +98,12 @@ func GetUpsertConnectionStateSql(c *steampipeconfig.ConnectionState) []db_common
 		plugin_mod_time,
 	    file_name,
 	    start_line_number,
-	    end_line_number)
-VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),$12,$13,$14,$15) 
+	    end_line_number,
+	    included_connections,
+	    excluded_connections,
+	    column_types,
+	    aggregator_views)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),$12,$13,$14,$15,$16,$17,$18,$19) 
 ON CONFLICT (name) 
 DO 
    UPDATE SET 
@@ -113,7 +121,11 @@ DO
 			  plugin_mod_time = $12,
 			  file_name = $13,
 	    	  start_line_number = $14,
-	     	  end_line_number = $15
+	     	  end_line_number = $15,
+	     	  included_connections = $16,
+	     	  excluded_connections = $17,
+	     	  column_types = $18,
+	     	  aggregator_views = $19
 			  
 `
 	args := []any{
@@ -132,10 +144,26 @@ DO
 		c.FileName,
 		c.StartLineNumber,
 		c.EndLineNumber,
+		c.IncludedConnections,
+		c.ExcludedConnections,
+		c.ColumnTypes,
+		c.AggregatorViews,
 	}
 	return getConnectionStateQueries(queryFormat, args)
 }
 
+// GetSetAggregatorChildStatusSql returns the sql to set the child connections which are included in
+// and excluded from an aggregator connection, and the options its view schema was created with
+func GetSetAggregatorChildStatusSql(connectionName string, included []string, excluded map[string]string, aggregatorViews string) []db_common.QueryWithArgs {
+	queryFormat := `UPDATE %s.%s
+SET included_connections = $1,
+	excluded_connections = $2,
+	aggregator_views = $3
+WHERE NAME=$4`
+	args := []any{included, excluded, aggregatorViews, connectionName}
+	return getConnectionStateQueries(queryFormat, args)
+}
+
 func GetNewConnectionStateFromConnectionInsertSql(c *modconfig.Connection) []db_common.QueryWithArgs {
 	queryFormat := `INSERT INTO %s.%s (name, 
 		state,
@@ -152,8 +180,9 @@ func GetNewConnectionStateFromConnectionInsertSql(c *modconfig.Connection) []db_
 		plugin_mod_time,
 		file_name,
 	    start_line_number,
-	    end_line_number)
-VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),now(),$12,$13,$14) 
+	    end_line_number,
+	    column_types)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),now(),$12,$13,$14,$15) 
 `
 	schemaMode := ""
 	commentsSet := false
@@ -174,6 +203,7 @@ VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),now(),$12,$13,$14)
 		c.DeclRange.Filename,
 		c.DeclRange.Start.Line,
 		c.DeclRange.End.Line,
+		c.ColumnTypes,
 	}
 
 	return getConnectionStateQueries(queryFormat, args)
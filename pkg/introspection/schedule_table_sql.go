@@ -0,0 +1,102 @@
+package introspection
+
+import (
+	"fmt"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// NOTE: the schedule table is NOT dropped when the service starts, so paused jobs remain paused across restarts
+// - the jobs registered by the service are upserted, and any other jobs are deleted
+
+func GetScheduleTableCreateSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (
+				name TEXT PRIMARY KEY,
+				description TEXT,
+				interval_seconds BIGINT,
+				paused BOOL DEFAULT FALSE,
+				running BOOL DEFAULT FALSE,
+				last_run_time TIMESTAMPTZ NULL,
+				last_run_duration_ms BIGINT NULL,
+				last_error TEXT NULL,
+				next_run_time TIMESTAMPTZ NULL
+		);`, constants.InternalSchema, constants.ScheduleTable),
+	}
+}
+
+// GetScheduleTableGrantSql returns the sql to setup SELECT permission for the 'steampipe_users' role
+func GetScheduleTableGrantSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(
+			`GRANT SELECT ON TABLE %s.%s TO %s;`,
+			constants.InternalSchema,
+			constants.ScheduleTable,
+			constants.DatabaseUsersRole,
+		),
+	}
+}
+
+// GetScheduleUpsertSql returns the sql to register a scheduled job - the paused state of an existing job is retained
+func GetScheduleUpsertSql(name, description string, intervalSeconds int64, nextRunTime any) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`INSERT INTO %s.%s (name, description, interval_seconds, running, next_run_time)
+VALUES ($1, $2, $3, FALSE, $4)
+ON CONFLICT (name) DO UPDATE SET
+	description = EXCLUDED.description,
+	interval_seconds = EXCLUDED.interval_seconds,
+	running = FALSE,
+	next_run_time = EXCLUDED.next_run_time;`,
+			constants.InternalSchema,
+			constants.ScheduleTable,
+		),
+		Args: []any{name, description, intervalSeconds, nextRunTime},
+	}
+}
+
+// GetScheduleDeleteUnregisteredSql returns the sql to delete all jobs other than the given jobs
+func GetScheduleDeleteUnregisteredSql(names []string) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`DELETE FROM %s.%s WHERE NOT name = ANY($1);`,
+			constants.InternalSchema,
+			constants.ScheduleTable,
+		),
+		Args: []any{names},
+	}
+}
+
+// GetScheduleRunStartSql returns the sql to record that a run of the job has started
+func GetScheduleRunStartSql(name string) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`UPDATE %s.%s SET running = TRUE WHERE name = $1;`,
+			constants.InternalSchema,
+			constants.ScheduleTable,
+		),
+		Args: []any{name},
+	}
+}
+
+// GetScheduleRunCompleteSql returns the sql to record the result of a run of the job, and the time of the next run
+func GetScheduleRunCompleteSql(name string, runTime any, durationMs int64, runError *string, nextRunTime any) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`UPDATE %s.%s
+SET running = FALSE, last_run_time = $2, last_run_duration_ms = $3, last_error = $4, next_run_time = $5
+WHERE name = $1;`,
+			constants.InternalSchema,
+			constants.ScheduleTable,
+		),
+		Args: []any{name, runTime, durationMs, runError, nextRunTime},
+	}
+}
+
+// GetSchedulePausedSql returns the sql to pause or resume the job
+func GetSchedulePausedSql(name string, paused bool) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`UPDATE %s.%s SET paused = $2 WHERE name = $1;`,
+			constants.InternalSchema,
+			constants.ScheduleTable,
+		),
+		Args: []any{name, paused},
+	}
+}
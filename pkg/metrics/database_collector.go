@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the maximum time to spend querying the database for a single scrape
+const collectTimeout = 10 * time.Second
+
+var (
+	transactionsDesc = prometheus.NewDesc(
+		"steampipe_database_transactions_total",
+		"The number of transactions committed or rolled back in the database.",
+		nil, nil)
+	sessionsDesc = prometheus.NewDesc(
+		"steampipe_database_sessions",
+		"The number of client sessions of the database, by state.",
+		[]string{"state"}, nil)
+	maxConnectionsDesc = prometheus.NewDesc(
+		"steampipe_database_max_connections",
+		"The maximum number of connections to the database.",
+		nil, nil)
+	bufferCacheHitRatioDesc = prometheus.NewDesc(
+		"steampipe_database_buffer_cache_hit_ratio",
+		"The proportion of database block reads which were served from the Postgres buffer cache (this is not the query cache).",
+		nil, nil)
+	poolConnectionsDesc = prometheus.NewDesc(
+		"steampipe_refresh_pool_connections",
+		"The number of connections in the connection refresh pool, by state.",
+		[]string{"state"}, nil)
+	poolMaxConnectionsDesc = prometheus.NewDesc(
+		"steampipe_refresh_pool_max_connections",
+		"The maximum number of connections in the connection refresh pool.",
+		nil, nil)
+)
+
+// databasePool is the connection pool used to query the database metrics (a *pgxpool.Pool)
+type databasePool interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// poolStats is the stats of the connection pool which are reported (a *pgxpool.Stat)
+type poolStats interface {
+	AcquiredConns() int32
+	IdleConns() int32
+	MaxConns() int32
+}
+
+// databaseCollector queries the database for its metrics each time the metrics are scraped
+type databaseCollector struct {
+	pool  databasePool
+	stats func() poolStats
+}
+
+func newDatabaseCollector(pool *pgxpool.Pool) *databaseCollector {
+	return &databaseCollector{
+		pool:  pool,
+		stats: func() poolStats { return pool.Stat() },
+	}
+}
+
+func (c *databaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		transactionsDesc,
+		sessionsDesc,
+		maxConnectionsDesc,
+		bufferCacheHitRatioDesc,
+		poolConnectionsDesc,
+		poolMaxConnectionsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *databaseCollector) Collect(ch chan<- prometheus.Metric) {
+	utils.LogTime("metrics.databaseCollector.Collect start")
+	defer utils.LogTime("metrics.databaseCollector.Collect end")
+
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	stat := c.stats()
+	ch <- prometheus.MustNewConstMetric(poolConnectionsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()), "acquired")
+	ch <- prometheus.MustNewConstMetric(poolConnectionsDesc, prometheus.GaugeValue, float64(stat.IdleConns()), "idle")
+	ch <- prometheus.MustNewConstMetric(poolMaxConnectionsDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+
+	if err := c.collectDatabaseStats(ctx, ch); err != nil {
+		log.Printf("[WARN] failed to collect database metrics: %s", err.Error())
+		ch <- prometheus.NewInvalidMetric(transactionsDesc, err)
+	}
+}
+
+func (c *databaseCollector) collectDatabaseStats(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// the session used to retrieve the stats is excluded from the session counts
+	query := `
+SELECT
+  (SELECT coalesce(sum(xact_commit + xact_rollback), 0)::float8 FROM pg_stat_database WHERE datname = current_database()),
+  (SELECT coalesce(sum(blks_hit)::float8 / nullif(sum(blks_hit) + sum(blks_read), 0), 0) FROM pg_stat_database),
+  current_setting('max_connections')::int,
+  count(*) FILTER (WHERE state = 'active'),
+  count(*) FILTER (WHERE state = 'idle')
+FROM
+  pg_stat_activity
+WHERE
+  backend_type = $1
+  AND pid <> pg_backend_pid()`
+
+	var transactions, bufferCacheHitRatio float64
+	var maxConnections, active, idle int
+	err := c.pool.QueryRow(ctx, query, "client backend").Scan(&transactions, &bufferCacheHitRatio, &maxConnections, &active, &idle)
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(transactionsDesc, prometheus.CounterValue, transactions)
+	ch <- prometheus.MustNewConstMetric(bufferCacheHitRatioDesc, prometheus.GaugeValue, bufferCacheHitRatio)
+	ch <- prometheus.MustNewConstMetric(maxConnectionsDesc, prometheus.GaugeValue, float64(maxConnections))
+	ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(active), "active")
+	ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(idle), "idle")
+	return nil
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// testPool is a databasePool whose query returns the given values (or error)
+type testPool struct {
+	values []any
+	err    error
+}
+
+func (p *testPool) QueryRow(context.Context, string, ...any) pgx.Row {
+	return &testRow{values: p.values, err: p.err}
+}
+
+type testStats struct{}
+
+func (testStats) AcquiredConns() int32 { return 1 }
+func (testStats) IdleConns() int32     { return 2 }
+func (testStats) MaxConns() int32      { return 4 }
+
+func newTestCollector(pool *testPool) *databaseCollector {
+	return &databaseCollector{pool: pool, stats: func() poolStats { return testStats{} }}
+}
+
+type testRow struct {
+	values []any
+	err    error
+}
+
+func (r *testRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *float64:
+			*d = r.values[i].(float64)
+		case *int:
+			*d = r.values[i].(int)
+		}
+	}
+	return nil
+}
+
+// gatherMetrics returns the metrics of the collector, keyed by name (and state label, if any)
+func gatherMetrics(c prometheus.Collector) (map[string]float64, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	res := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.Metric {
+			key := family.GetName()
+			for _, label := range m.Label {
+				key += "/" + label.GetValue()
+			}
+			res[key] = metricValue(m)
+		}
+	}
+	return res, err
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	}
+	return 0
+}
+
+func TestDatabaseCollector(t *testing.T) {
+	pool := &testPool{values: []any{float64(42), 0.75, 100, 3, 2}}
+	got, err := gatherMetrics(newTestCollector(pool))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	want := map[string]float64{
+		"steampipe_database_transactions_total":       42,
+		"steampipe_database_buffer_cache_hit_ratio":   0.75,
+		"steampipe_database_max_connections":          100,
+		"steampipe_database_sessions/active":          3,
+		"steampipe_database_sessions/idle":            2,
+		"steampipe_refresh_pool_connections/acquired": 1,
+		"steampipe_refresh_pool_connections/idle":     2,
+		"steampipe_refresh_pool_max_connections":      4,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s: expected %v, got %v", name, value, got[name])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d metrics, got %v", len(want), got)
+	}
+}
+
+func TestDatabaseCollectorQueryError(t *testing.T) {
+	pool := &testPool{err: errors.New("connection refused")}
+	got, err := gatherMetrics(newTestCollector(pool))
+	// the failure is reported, but the pool metrics are still collected
+	if err == nil {
+		t.Error("expected the query error to be reported")
+	}
+	if _, ok := got["steampipe_refresh_pool_max_connections"]; !ok {
+		t.Errorf("expected the pool metrics to be collected, got %v", got)
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+)
+
+// the label values for the result of a refresh
+const (
+	refreshResultSuccess = "success"
+	refreshResultError   = "error"
+)
+
+var refreshDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "steampipe_refresh_connections_duration_seconds",
+	Help:    "The duration of connection refreshes, by result.",
+	Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600},
+}, []string{"result"})
+
+// newRegistry returns the registry of all metrics served by the metrics server
+func newRegistry(pool *pgxpool.Pool) (*prometheus.Registry, error) {
+	r := prometheus.NewRegistry()
+	for _, c := range []prometheus.Collector{
+		refreshDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		newDatabaseCollector(pool),
+	} {
+		if err := r.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// ObserveRefreshConnections records the duration and result of a connection refresh
+// (this is recorded regardless of whether the metrics are served)
+func ObserveRefreshConnections(duration time.Duration, err error) {
+	result := refreshResultSuccess
+	if err != nil {
+		result = refreshResultError
+	}
+	refreshDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// Serve starts a server which serves the metrics in Prometheus format at /metrics on the given port
+// the metrics are served on the same addresses as the database (see listenHosts), so they are only
+// accessible from the network if the database is
+// the pool is used to query the database metrics each time the metrics are scraped - its stats are also reported
+func Serve(listenAddresses []string, port int, pool *pgxpool.Pool) (*http.Server, error) {
+	registry, err := newRegistry(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	// listen before returning, so a port which is in use is reported to the caller
+	hosts := listenHosts(listenAddresses)
+	listeners := make([]net.Listener, 0, len(hosts))
+	for _, host := range hosts {
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, sperr.WrapWithMessage(err, "failed to start metrics server on port %d", port)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	mux := http.NewServeMux()
+	// continue on error, so a failure to query the database does not prevent the other metrics being served
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}))
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("[WARN] metrics server stopped: %s", err.Error())
+			}
+		}(listener)
+	}
+
+	log.Printf("[INFO] serving metrics on port %d (hosts: %v)", port, hosts)
+	return server, nil
+}
+
+// listenHosts returns the hosts the metrics server listens on, given the listen addresses of the database
+// (as given to the service, e.g. '*' for --database-listen=network and 'localhost' for local)
+// an empty host listens on all interfaces - if no addresses are given, only the loopback interface is used
+func listenHosts(listenAddresses []string) []string {
+	if slices.Contains(listenAddresses, "*") {
+		return []string{""}
+	}
+	var hosts []string
+	for _, address := range listenAddresses {
+		if address == "localhost" {
+			address = "127.0.0.1"
+		}
+		if address != "" && !slices.Contains(hosts, address) {
+			hosts = append(hosts, address)
+		}
+	}
+	if len(hosts) == 0 {
+		return []string{"127.0.0.1"}
+	}
+	return hosts
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestListenHosts(t *testing.T) {
+	tests := map[string]struct {
+		listenAddresses []string
+		want            []string
+	}{
+		"network":      {listenAddresses: []string{"127.0.0.1", "*"}, want: []string{""}},
+		"local":        {listenAddresses: []string{"127.0.0.1", "localhost"}, want: []string{"127.0.0.1"}},
+		"addresses":    {listenAddresses: []string{"127.0.0.1", "10.0.0.5"}, want: []string{"127.0.0.1", "10.0.0.5"}},
+		"no addresses": {want: []string{"127.0.0.1"}},
+	}
+	for name, test := range tests {
+		if got := listenHosts(test.listenAddresses); !slices.Equal(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", name, test.want, got)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	port := freePort(t)
+	// the pool connects lazily - the database metrics fail to be collected, but the other metrics are still served
+	pool, err := pgxpool.New(context.Background(), fmt.Sprintf("postgres://steampipe@127.0.0.1:%d/steampipe?connect_timeout=1", freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	server, err := Serve([]string{"127.0.0.1", "localhost"}, port, pool)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/metrics", port))
+	if err != nil {
+		t.Fatalf("failed to scrape metrics: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", resp.StatusCode, body)
+	}
+	for _, metric := range []string{"steampipe_refresh_pool_max_connections ", "go_goroutines"} {
+		if !strings.Contains(string(body), metric) {
+			t.Errorf("expected the metrics to contain %s", metric)
+		}
+	}
+
+	// a port which is in use is reported
+	if _, err := Serve(nil, port, pool); err == nil {
+		t.Error("expected an error serving on a port which is in use")
+	}
+}
+
+// freePort returns a port which is not in use on the loopback interface
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
@@ -3,18 +3,38 @@ package modinstaller
 import (
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/turbot/steampipe/pkg/network"
 )
 
 func getGitUrl(modName string) string {
 	return fmt.Sprintf("https://%s", modName)
 }
 
+// the hashes of the tags of each remote repo, recorded when the tags are listed
+// these are used to detect tags which have moved since they were cached (see ensureGitCacheReference)
+var (
+	remoteTagHashes    = make(map[string]map[plumbing.ReferenceName]plumbing.Hash)
+	remoteTagHashesMut sync.Mutex
+)
+
+func getRemoteTagHashes(repo string) (map[plumbing.ReferenceName]plumbing.Hash, bool) {
+	remoteTagHashesMut.Lock()
+	defer remoteTagHashesMut.Unlock()
+	hashes, ok := remoteTagHashes[repo]
+	return hashes, ok
+}
+
 func getTags(repo string) ([]string, error) {
+	if err := network.CheckOnline("resolving mod versions"); err != nil {
+		return nil, err
+	}
 	// Create the remote with repository URL
 	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: "origin",
@@ -22,18 +42,27 @@ func getTags(repo string) ([]string, error) {
 	})
 
 	// load remote references
-	refs, err := rem.List(&git.ListOptions{})
+	var refs []*plumbing.Reference
+	err := withGitOperationLimit(func() (err error) {
+		refs, err = rem.List(&git.ListOptions{})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// filters the references list and only keeps tags
 	var tags []string
+	hashes := make(map[plumbing.ReferenceName]plumbing.Hash)
 	for _, ref := range refs {
 		if ref.Name().IsTag() {
 			tags = append(tags, ref.Name().Short())
+			hashes[ref.Name()] = ref.Hash()
 		}
 	}
+	remoteTagHashesMut.Lock()
+	remoteTagHashes[repo] = hashes
+	remoteTagHashesMut.Unlock()
 
 	return tags, nil
 }
@@ -0,0 +1,175 @@
+package modinstaller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/network"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the maximum number of git network operations (clones, fetches and tag listings) performed concurrently
+const maxParallelGitOperations = 8
+
+// gitOperationLimiter limits the number of concurrent git network operations
+var gitOperationLimiter = make(chan struct{}, maxParallelGitOperations)
+
+// withGitOperationLimit runs f once there are fewer than maxParallelGitOperations git operations running
+func withGitOperationLimit(f func() error) error {
+	gitOperationLimiter <- struct{}{}
+	defer func() { <-gitOperationLimiter }()
+	return f()
+}
+
+// gitCacheLocks serialises operations on each cached repo
+var (
+	gitCacheLocks   = make(map[string]*sync.Mutex)
+	gitCacheLocksMu sync.Mutex
+)
+
+func gitCacheLock(cachePath string) *sync.Mutex {
+	gitCacheLocksMu.Lock()
+	defer gitCacheLocksMu.Unlock()
+	l, ok := gitCacheLocks[cachePath]
+	if !ok {
+		l = &sync.Mutex{}
+		gitCacheLocks[cachePath] = l
+	}
+	return l
+}
+
+// cloneFromGitCache clones the given reference of the mod repo into installPath
+// the clone is made from a bare clone of the repo in the mod cache directory - this is created if needed,
+// and only fetched from the remote if it does not contain the reference, or the reference has moved
+func cloneFromGitCache(modName string, reference plumbing.ReferenceName, installPath string) error {
+	cachePath := gitCachePath(modName)
+	if err := ensureGitCacheReference(cachePath, getGitUrl(modName), reference); err != nil {
+		return err
+	}
+
+	// NOTE: the cache is a local repo, so there is no need to limit this operation
+	_, err := git.PlainClone(installPath,
+		false,
+		&git.CloneOptions{
+			URL:           cachePath,
+			ReferenceName: reference,
+			Depth:         1,
+			SingleBranch:  true,
+		})
+	return err
+}
+
+// ensureGitCacheReference ensures the cached bare repo at cachePath contains the current version of the given reference
+// network access is only required if the repo is not cached, or does not contain the reference, or the reference
+// has moved on the remote (e.g. a tag was deleted and recreated)
+func ensureGitCacheReference(cachePath, remoteUrl string, reference plumbing.ReferenceName) error {
+	// serialise operations on the cached repo - between goroutines, and between steampipe processes
+	lock := gitCacheLock(cachePath)
+	lock.Lock()
+	defer lock.Unlock()
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+	fileLock, err := utils.LockFile(cachePath + ".lock")
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	repo, err := git.PlainOpen(cachePath)
+	if err != nil {
+		// the cache does not exist (or is corrupt) - clone the repo
+		log.Printf("[TRACE] no git cache for %s - cloning to %s", remoteUrl, cachePath)
+		return cloneGitCache(cachePath, remoteUrl)
+	}
+
+	// if the cache contains the current version of the reference there is nothing to do
+	if cached, err := repo.Reference(reference, true); err == nil {
+		expected, ok := remoteReferenceHash(remoteUrl, reference)
+		if !ok || expected == cached.Hash() {
+			log.Printf("[TRACE] git cache for %s contains %s", remoteUrl, reference)
+			return nil
+		}
+		log.Printf("[TRACE] %s has moved in %s (%s -> %s) - fetching", reference, remoteUrl, cached.Hash(), expected)
+	} else {
+		log.Printf("[TRACE] git cache for %s does not contain %s - fetching", remoteUrl, reference)
+	}
+
+	// fetch the latest branches and tags (moved tags are updated)
+	if err := network.CheckOnline(fmt.Sprintf("fetching '%s'", remoteUrl)); err != nil {
+		return err
+	}
+	err = withGitOperationLimit(func() error {
+		return repo.Fetch(&git.FetchOptions{
+			RemoteName: git.DefaultRemoteName,
+			RefSpecs: []config.RefSpec{
+				"+refs/heads/*:refs/heads/*",
+				"+refs/tags/*:refs/tags/*",
+			},
+			Force: true,
+		})
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+	if _, err := repo.Reference(reference, true); err != nil {
+		return sperr.New("'%s' does not contain %s", remoteUrl, reference.Short())
+	}
+	return nil
+}
+
+// cloneGitCache creates a bare clone of the remote repo at cachePath, replacing anything already there
+func cloneGitCache(cachePath, remoteUrl string) error {
+	if err := network.CheckOnline(fmt.Sprintf("cloning '%s'", remoteUrl)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(cachePath); err != nil {
+		return err
+	}
+	err := withGitOperationLimit(func() error {
+		_, err := git.PlainClone(cachePath, true, &git.CloneOptions{
+			URL:  remoteUrl,
+			Tags: git.AllTags,
+		})
+		return err
+	})
+	if err != nil {
+		// do not leave a partial clone in the cache
+		_ = os.RemoveAll(cachePath)
+	}
+	return err
+}
+
+// remoteReferenceHash returns the hash of the reference in the remote repo, as listed when resolving mod versions
+// if the remote tags have not been listed by this process they are listed now, unless running in offline mode
+// ok is false if the hash cannot be determined, in which case the cached reference is used
+func remoteReferenceHash(remoteUrl string, reference plumbing.ReferenceName) (hash plumbing.Hash, ok bool) {
+	hashes, listed := getRemoteTagHashes(remoteUrl)
+	if !listed {
+		if network.IsOffline() {
+			return hash, false
+		}
+		if _, err := getTags(remoteUrl); err != nil {
+			log.Printf("[WARN] failed to list the tags of %s: %s", remoteUrl, err.Error())
+			return hash, false
+		}
+		hashes, _ = getRemoteTagHashes(remoteUrl)
+	}
+	hash, ok = hashes[reference]
+	return hash, ok
+}
+
+// gitCachePath returns the path of the cached bare repo for the mod, e.g. <cache dir>/github.com/turbot/steampipe-mod-aws-compliance.git
+func gitCachePath(modName string) string {
+	return filepath.Join(filepaths.EnsureModCacheDir(), filepath.FromSlash(strings.TrimSuffix(modName, ".git"))+".git")
+}
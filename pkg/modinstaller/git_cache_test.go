@@ -0,0 +1,129 @@
+package modinstaller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// testRemote is a local repo used as the remote of the git cache
+type testRemote struct {
+	t    *testing.T
+	path string
+	repo *git.Repository
+}
+
+func newTestRemote(t *testing.T) *testRemote {
+	path := filepath.Join(t.TempDir(), "remote")
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRemote{t: t, path: path, repo: repo}
+}
+
+// commit commits a mod.sp with the given content and returns the commit hash
+func (r *testRemote) commit(content string) plumbing.Hash {
+	if err := os.WriteFile(filepath.Join(r.path, "mod.sp"), []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+	w, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	if _, err := w.Add("mod.sp"); err != nil {
+		r.t.Fatal(err)
+	}
+	hash, err := w.Commit(content, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return hash
+}
+
+// tag creates (or moves) the tag to the given commit
+func (r *testRemote) tag(name string, hash plumbing.Hash) {
+	_ = r.repo.DeleteTag(name)
+	if _, err := r.repo.CreateTag(name, hash, nil); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+func cloneTestReference(t *testing.T, cachePath, remoteUrl string, reference plumbing.ReferenceName) string {
+	if err := ensureGitCacheReference(cachePath, remoteUrl, reference); err != nil {
+		t.Fatalf("ensureGitCacheReference failed: %s", err)
+	}
+	installPath := filepath.Join(t.TempDir(), "install")
+	if _, err := git.PlainClone(installPath, false, &git.CloneOptions{URL: cachePath, ReferenceName: reference, Depth: 1, SingleBranch: true}); err != nil {
+		t.Fatalf("clone from cache failed: %s", err)
+	}
+	content, err := os.ReadFile(filepath.Join(installPath, "mod.sp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestEnsureGitCacheReference(t *testing.T) {
+	remote := newTestRemote(t)
+	remote.tag("v1.0.0", remote.commit("v1"))
+	cachePath := filepath.Join(t.TempDir(), "cache", "mod.git")
+
+	// the cache is created
+	if content := cloneTestReference(t, cachePath, remote.path, plumbing.NewTagReferenceName("v1.0.0")); content != "v1" {
+		t.Errorf("expected v1.0.0 to contain 'v1', got '%s'", content)
+	}
+
+	// a new tag is fetched
+	remote.tag("v1.1.0", remote.commit("v1.1"))
+	if content := cloneTestReference(t, cachePath, remote.path, plumbing.NewTagReferenceName("v1.1.0")); content != "v1.1" {
+		t.Errorf("expected v1.1.0 to contain 'v1.1', got '%s'", content)
+	}
+
+	// a tag which has moved on the remote is fetched again
+	remote.tag("v1.1.0", remote.commit("v1.1 fixed"))
+	if _, err := getTags(remote.path); err != nil {
+		t.Fatal(err)
+	}
+	if content := cloneTestReference(t, cachePath, remote.path, plumbing.NewTagReferenceName("v1.1.0")); content != "v1.1 fixed" {
+		t.Errorf("expected the moved v1.1.0 tag to contain 'v1.1 fixed', got '%s'", content)
+	}
+
+	// a reference which does not exist is an error
+	if err := ensureGitCacheReference(cachePath, remote.path, plumbing.NewTagReferenceName("v9.9.9")); err == nil {
+		t.Errorf("expected an error for a tag which does not exist")
+	}
+}
+
+func TestEnsureGitCacheReferenceOffline(t *testing.T) {
+	remote := newTestRemote(t)
+	remote.tag("v1.0.0", remote.commit("v1"))
+	cachePath := filepath.Join(t.TempDir(), "cache", "mod.git")
+	if err := ensureGitCacheReference(cachePath, remote.path, plumbing.NewTagReferenceName("v1.0.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	defer viper.Set(constants.ArgOffline, nil)
+	viper.Set(constants.ArgOffline, true)
+
+	// the cache satisfies the reference, so no network access is required
+	if err := ensureGitCacheReference(cachePath, remote.path, plumbing.NewTagReferenceName("v1.0.0")); err != nil {
+		t.Errorf("expected a cached reference to be used in offline mode, got %s", err)
+	}
+	// a reference which is not cached requires network access
+	remote.tag("v1.1.0", remote.commit("v1.1"))
+	if err := ensureGitCacheReference(cachePath, remote.path, plumbing.NewTagReferenceName("v1.1.0")); err == nil {
+		t.Errorf("expected an error fetching an uncached reference in offline mode")
+	}
+	// as does creating the cache
+	if err := ensureGitCacheReference(filepath.Join(t.TempDir(), "other.git"), remote.path, plumbing.NewTagReferenceName("v1.0.0")); err == nil {
+		t.Errorf("expected an error creating the cache in offline mode")
+	}
+}
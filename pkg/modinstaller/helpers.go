@@ -1,7 +1,10 @@
 package modinstaller
 
 import (
+	"sync"
+
 	"github.com/Masterminds/semver/v3"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/versionhelpers"
 )
 
@@ -14,3 +17,23 @@ func getVersionSatisfyingConstraint(constraint *versionhelpers.Constraints, avai
 	}
 	return nil
 }
+
+// forEachDependencyParallel calls f for each of the dependencies concurrently, returning any errors
+func forEachDependencyParallel(dependencies []*modconfig.ModVersionConstraint, f func(*modconfig.ModVersionConstraint) error) []error {
+	var wg sync.WaitGroup
+	var errorsMut sync.Mutex
+	var errors []error
+	for _, dependency := range dependencies {
+		wg.Add(1)
+		go func(dependency *modconfig.ModVersionConstraint) {
+			defer wg.Done()
+			if err := f(dependency); err != nil {
+				errorsMut.Lock()
+				errors = append(errors, err)
+				errorsMut.Unlock()
+			}
+		}(dependency)
+	}
+	wg.Wait()
+	return errors
+}
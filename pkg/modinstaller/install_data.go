@@ -2,12 +2,14 @@ package modinstaller
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/versionmap"
 	"github.com/turbot/steampipe/pkg/versionhelpers"
 	"github.com/xlab/treeprint"
+	"golang.org/x/sync/singleflight"
 )
 
 type InstallData struct {
@@ -27,6 +29,13 @@ type InstallData struct {
 	// list of dependencies which have been uninstalled
 	Uninstalled  versionmap.DependencyVersionMap
 	WorkspaceMod *modconfig.Mod
+	// version constraints of the workspace mod dependencies which have been updated
+	UpdatedConstraints []ConstraintUpdate
+
+	// dependencies are installed in parallel - protect access to NewLock and allAvailable
+	mut sync.Mutex
+	// ensures the available versions of each mod are only retrieved once
+	availableVersionsGroup singleflight.Group
 }
 
 func NewInstallData(workspaceLock *versionmap.WorkspaceLock, workspaceMod *modconfig.Mod) *InstallData {
@@ -68,6 +77,8 @@ func (d *InstallData) onModInstalled(dependency *ResolvedModRef, modDef *modconf
 	// get the constraint from the parent (it must be there)
 	modVersionConstraint := parent.Require.GetModDependency(dependency.Name).Constraint.Original
 
+	d.mut.Lock()
+	defer d.mut.Unlock()
 	// update lock
 	d.NewLock.InstallCache.Add(dependency.Name, modDef.ShortName, modDef.Version, modVersionConstraint, parentPath)
 }
@@ -76,26 +87,37 @@ func (d *InstallData) onModInstalled(dependency *ResolvedModRef, modDef *modconf
 func (d *InstallData) addExisting(dependencyName string, existingDep *modconfig.Mod, constraint *versionhelpers.Constraints, parent *modconfig.Mod) {
 	// update lock
 	parentPath := parent.GetInstallCacheKey()
+	d.mut.Lock()
+	defer d.mut.Unlock()
 	d.NewLock.InstallCache.Add(dependencyName, existingDep.ShortName, existingDep.Version, constraint.Original, parentPath)
 }
 
 // retrieve all available mod versions from our cache, or from Git if not yet cached
 func (d *InstallData) getAvailableModVersions(modName string, includePrerelease bool) ([]*semver.Version, error) {
 	// have we already loaded the versions for this mod
+	d.mut.Lock()
 	availableVersions, ok := d.allAvailable[modName]
+	d.mut.Unlock()
 	if ok {
 		return availableVersions, nil
 	}
 	// so we have not cached this yet - retrieve from Git
-	var err error
-	availableVersions, err = getTagVersionsFromGit(getGitUrl(modName), includePrerelease)
+	// (if the versions are already being retrieved for another dependency, wait for the result)
+	res, err, _ := d.availableVersionsGroup.Do(modName, func() (any, error) {
+		versions, err := getTagVersionsFromGit(getGitUrl(modName), includePrerelease)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve version data from Git URL '%s'", modName)
+		}
+		// update our cache
+		d.mut.Lock()
+		d.allAvailable[modName] = versions
+		d.mut.Unlock()
+		return versions, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("could not retrieve version data from Git URL '%s'", modName)
+		return nil, err
 	}
-	// update our cache
-	d.allAvailable[modName] = availableVersions
-
-	return availableVersions, nil
+	return res.([]*semver.Version), nil
 }
 
 // update the lock with the NewLock and dtermine if any mods have been uninstalled
@@ -13,6 +13,8 @@ type InstallOpts struct {
 	ModArgs      []string
 	DryRun       bool
 	Force        bool
+	// how to update the version constraints of the workspace mod dependencies (update only)
+	ConstraintPolicy ConstraintPolicy
 }
 
 func NewInstallOpts(workspaceMod *modconfig.Mod, modsToInstall ...string) *InstallOpts {
@@ -24,5 +26,10 @@ func NewInstallOpts(workspaceMod *modconfig.Mod, modsToInstall ...string) *Insta
 		ModArgs:      modsToInstall,
 		Command:      cmdName,
 	}
+	if viper.GetBool(constants.ArgLatest) {
+		opts.ConstraintPolicy = ConstraintPolicyLatest
+	} else if viper.GetBool(constants.ArgMinor) {
+		opts.ConstraintPolicy = ConstraintPolicyMinor
+	}
 	return opts
 }
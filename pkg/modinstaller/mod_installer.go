@@ -9,7 +9,6 @@ import (
 	"path/filepath"
 
 	"github.com/Masterminds/semver/v3"
-	git "github.com/go-git/go-git/v5"
 	"github.com/otiai10/copy"
 	"github.com/spf13/viper"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
@@ -21,6 +20,8 @@ import (
 	"github.com/turbot/steampipe/pkg/steampipeconfig/parse"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/versionmap"
 	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/turbot/steampipe/pkg/version"
+	"golang.org/x/sync/singleflight"
 )
 
 type ModInstaller struct {
@@ -52,6 +53,10 @@ type ModInstaller struct {
 	dryRun bool
 	// do we force install even if there are require errors
 	force bool
+	// how to update the version constraints of the workspace mod dependencies
+	constraintPolicy ConstraintPolicy
+	// ensures each dependency version is only installed once when dependencies are installed in parallel
+	installGroup singleflight.Group
 }
 
 func NewModInstaller(ctx context.Context, opts *InstallOpts) (*ModInstaller, error) {
@@ -65,6 +70,10 @@ func NewModInstaller(ctx context.Context, opts *InstallOpts) (*ModInstaller, err
 		dryRun:        opts.DryRun,
 		force:         opts.Force,
 	}
+	// constraints are only updated by the update command
+	if i.updating() {
+		i.constraintPolicy = opts.ConstraintPolicy
+	}
 
 	if opts.WorkspaceMod.Require != nil {
 		i.oldRequire = opts.WorkspaceMod.Require.Clone()
@@ -207,6 +216,11 @@ func (i *ModInstaller) InstallWorkspaceDependencies(ctx context.Context) (err er
 		workspaceMod.AddModDependencies(i.mods)
 	}
 
+	// if a constraint policy is set, update the dependency version constraints
+	if err := i.updateConstraints(); err != nil {
+		return err
+	}
+
 	if err := i.installMods(ctx, workspaceMod.Require.Mods, workspaceMod); err != nil {
 		return err
 	}
@@ -234,8 +248,34 @@ func (i *ModInstaller) InstallWorkspaceDependencies(ctx context.Context) (err er
 	return nil
 }
 
+// GetModList returns the installed mods as a tree, labelling each mod with its steampipe version requirement (if any)
 func (i *ModInstaller) GetModList() string {
-	return i.installData.Lock.GetModList(i.workspaceMod.GetInstallCacheKey())
+	rootName := i.workspaceMod.GetInstallCacheKey()
+	return i.installData.Lock.GetModList(rootName, func(name string) string {
+		mod := i.workspaceMod
+		if name != rootName {
+			var err error
+			mod, err = parse.LoadModfile(filepath.Join(i.modsPath, name))
+			if err != nil {
+				log.Printf("[WARN] failed to load mod definition for %s: %s", name, err.Error())
+				return ""
+			}
+		}
+		return steampipeRequirementLabel(mod)
+	})
+}
+
+// steampipeRequirementLabel returns a label describing the steampipe version requirement of the mod (if any),
+// and whether it is satisfied by this version of steampipe
+func steampipeRequirementLabel(mod *modconfig.Mod) string {
+	if mod == nil || mod.Require == nil || mod.Require.Steampipe == nil || mod.Require.SteampipeVersionConstraint() == nil {
+		return ""
+	}
+	requirement := mod.Require.Steampipe
+	if !requirement.Check(version.SteampipeVersion) {
+		return fmt.Sprintf("(requires steampipe %s - not supported by this version)", requirement.String())
+	}
+	return fmt.Sprintf("(requires steampipe %s)", requirement.String())
 }
 
 // commitShadow recursively copies over the contents of the shadow directory
@@ -301,22 +341,18 @@ func (i *ModInstaller) installMods(ctx context.Context, mods []*modconfig.ModVer
 		os.RemoveAll(i.shadowDirPath)
 	}()
 
-	var errors []error
-	for _, requiredModVersion := range mods {
+	errors := forEachDependencyParallel(mods, func(requiredModVersion *modconfig.ModVersionConstraint) error {
 		modToUse, err := i.getCurrentlyInstalledVersionToUse(ctx, requiredModVersion, parent, i.updating())
 		if err != nil {
-			errors = append(errors, err)
-			continue
+			return err
 		}
 
 		// if the mod is not installed or needs updating, OR if this is an update command,
 		// pass shouldUpdate=true into installModDependencesRecursively
 		// this ensures that we update any dependencies which have updates available
 		shouldUpdate := modToUse == nil || i.updating()
-		if err := i.installModDependencesRecursively(ctx, requiredModVersion, modToUse, parent, shouldUpdate); err != nil {
-			errors = append(errors, err)
-		}
-	}
+		return i.installModDependencesRecursively(ctx, requiredModVersion, modToUse, parent, shouldUpdate)
+	})
 
 	// update the lock to be the new lock, and record any uninstalled mods
 	i.installData.onInstallComplete()
@@ -374,18 +410,15 @@ func (i *ModInstaller) installModDependencesRecursively(ctx context.Context, req
 	}
 
 	// to get here we have the dependency mod - either we installed it or it was already installed
-	// recursively install its dependencies
-	for _, childDependency := range dependencyMod.Require.Mods {
+	// recursively install its dependencies (in parallel)
+	childErrors := forEachDependencyParallel(dependencyMod.Require.Mods, func(childDependency *modconfig.ModVersionConstraint) error {
 		childDependencyMod, err := i.getCurrentlyInstalledVersionToUse(ctx, childDependency, dependencyMod, shouldUpdate)
 		if err != nil {
-			errors = append(errors, err)
-			continue
-		}
-		if err := i.installModDependencesRecursively(ctx, childDependency, childDependencyMod, dependencyMod, shouldUpdate); err != nil {
-			errors = append(errors, err)
-			continue
+			return err
 		}
-	}
+		return i.installModDependencesRecursively(ctx, childDependency, childDependencyMod, dependencyMod, shouldUpdate)
+	})
+	errors = append(errors, childErrors...)
 
 	return error_helpers.CombineErrorsWithPrefix(fmt.Sprintf("%d child %s failed to install", len(errors), utils.Pluralize("dependency", len(errors))), errors...)
 }
@@ -518,11 +551,17 @@ func (i *ModInstaller) install(ctx context.Context, dependency *ResolvedModRef,
 	}()
 	// if the target path exists, use the exiting file
 	// if it does not exist (the usual case), install it
-	if _, err := os.Stat(destPath); os.IsNotExist(err) {
-		log.Println("[TRACE] installing", dependencyPath, "in", destPath)
-		if err := i.installFromGit(dependency, destPath); err != nil {
-			return nil, err
+	// (dependencies are installed in parallel, so the same mod version may be required by more than one parent
+	// concurrently - ensure it is only installed once)
+	_, err, _ = i.installGroup.Do(destPath, func() (any, error) {
+		if _, err := os.Stat(destPath); os.IsNotExist(err) {
+			log.Println("[TRACE] installing", dependencyPath, "in", destPath)
+			return nil, i.installFromGit(dependency, destPath)
 		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// now load the installed mod and return it
@@ -545,18 +584,10 @@ func (i *ModInstaller) install(ctx context.Context, dependency *ResolvedModRef,
 }
 
 func (i *ModInstaller) installFromGit(dependency *ResolvedModRef, installPath string) error {
-	// get the mod from git
-	gitUrl := getGitUrl(dependency.Name)
-	log.Println("[TRACE] >>> cloning", gitUrl, dependency.GitReference)
-	_, err := git.PlainClone(installPath,
-		false,
-		&git.CloneOptions{
-			URL:           gitUrl,
-			ReferenceName: dependency.GitReference,
-			Depth:         1,
-			SingleBranch:  true,
-		})
-	if err != nil {
+	// get the mod from git (via the mod git cache)
+	// NOTE: network access is only required if the cache does not contain the mod version
+	log.Println("[TRACE] >>> cloning", dependency.Name, dependency.GitReference)
+	if err := cloneFromGitCache(dependency.Name, dependency.GitReference, installPath); err != nil {
 		return sperr.WrapWithMessage(err, "failed to clone mod '%s' from git", dependency.Name)
 	}
 	// verify the cloned repo contains a valid modfile
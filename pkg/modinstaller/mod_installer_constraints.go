@@ -0,0 +1,138 @@
+package modinstaller
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ConstraintPolicy determines how the version constraints of the workspace mod dependencies are updated by mod update
+type ConstraintPolicy string
+
+const (
+	// ConstraintPolicyNone - do not change the constraints, just update to the latest versions which satisfy them
+	ConstraintPolicyNone ConstraintPolicy = ""
+	// ConstraintPolicyMinor - update the constraints to the latest version with the same major version
+	ConstraintPolicyMinor ConstraintPolicy = "minor"
+	// ConstraintPolicyLatest - update the constraints to the latest version
+	ConstraintPolicyLatest ConstraintPolicy = "latest"
+)
+
+// ConstraintUpdate is an update to the version constraint of a workspace mod dependency
+type ConstraintUpdate struct {
+	Name          string
+	OldConstraint string
+	NewConstraint string
+}
+
+// matches a single constraint with an optional operator, e.g. ^1.2, ~1.2.3, >=1.0, 1.2.3 or v1.2.3
+var singleConstraintRegex = regexp.MustCompile(`^\s*(\^|~|>=|=)?\s*v?\d+(\.\d+)?(\.\d+)?(-[0-9A-Za-z.-]+)?\s*$`)
+
+// updateConstraints updates the version constraints of the workspace mod dependencies (or just those passed as args)
+// to the target version determined by the constraint policy, replacing the constraints in the workspace mod require
+func (i *ModInstaller) updateConstraints() error {
+	if i.constraintPolicy == ConstraintPolicyNone || i.workspaceMod.Require == nil {
+		return nil
+	}
+
+	var errors []error
+	updatedConstraints := make(map[string]*modconfig.ModVersionConstraint)
+	for _, requiredModVersion := range i.workspaceMod.Require.Mods {
+		// if mod args were passed, only update those mods
+		if len(i.mods) > 0 && i.mods[requiredModVersion.Name] == nil {
+			continue
+		}
+		newConstraint, err := i.getUpdatedConstraint(requiredModVersion)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if newConstraint == nil {
+			continue
+		}
+		updatedConstraints[newConstraint.Name] = newConstraint
+		i.installData.UpdatedConstraints = append(i.installData.UpdatedConstraints, ConstraintUpdate{
+			Name:          requiredModVersion.Name,
+			OldConstraint: requiredModVersion.VersionString,
+			NewConstraint: newConstraint.VersionString,
+		})
+	}
+	if len(errors) > 0 {
+		return error_helpers.CombineErrorsWithPrefix(fmt.Sprintf("failed to update %d version constraints", len(errors)), errors...)
+	}
+
+	// replace the dependencies in the workspace mod (the original require is retained in oldRequire,
+	// so the changes will be written to the mod file)
+	i.workspaceMod.AddModDependencies(updatedConstraints)
+	return nil
+}
+
+// getUpdatedConstraint returns the new version constraint for the dependency, or nil if it is unchanged
+func (i *ModInstaller) getUpdatedConstraint(requiredModVersion *modconfig.ModVersionConstraint) (*modconfig.ModVersionConstraint, error) {
+	// local file dependencies and dependencies which are already unconstrained cannot be updated
+	if requiredModVersion.Constraint == nil || !requiredModVersion.HasVersion() {
+		return nil, nil
+	}
+
+	includePrerelease := requiredModVersion.Constraint.IsPrerelease()
+	availableVersions, err := i.installData.getAvailableModVersions(requiredModVersion.Name, includePrerelease)
+	if err != nil {
+		return nil, err
+	}
+
+	// the current version is the installed version if there is one, otherwise the version which would be installed
+	var currentVersion *semver.Version
+	if installed := i.installData.Lock.GetMod(requiredModVersion.Name, i.workspaceMod); installed != nil {
+		currentVersion = installed.Version
+	} else {
+		currentVersion = getVersionSatisfyingConstraint(requiredModVersion.Constraint, availableVersions)
+	}
+	if currentVersion == nil {
+		return nil, fmt.Errorf("no version of %s found satisfying version constraint: %s", requiredModVersion.Name, requiredModVersion.VersionString)
+	}
+
+	targetVersion := getTargetVersion(i.constraintPolicy, currentVersion, availableVersions)
+	if targetVersion == nil || targetVersion.LessThan(currentVersion) {
+		return nil, nil
+	}
+
+	newVersionString := updateVersionConstraint(requiredModVersion.VersionString, targetVersion)
+	if newVersionString == requiredModVersion.VersionString {
+		return nil, nil
+	}
+	log.Printf("[TRACE] updating version constraint for %s from %s to %s", requiredModVersion.Name, requiredModVersion.VersionString, newVersionString)
+
+	newConstraint, err := modconfig.NewModVersionConstraint(fmt.Sprintf("%s@%s", requiredModVersion.Name, newVersionString))
+	if err != nil {
+		return nil, err
+	}
+	newConstraint.Args = requiredModVersion.Args
+	return newConstraint, nil
+}
+
+// getTargetVersion returns the version the constraint should be updated to for the given policy
+// (availableVersions are sorted newest first)
+func getTargetVersion(policy ConstraintPolicy, currentVersion *semver.Version, availableVersions []*semver.Version) *semver.Version {
+	for _, v := range availableVersions {
+		if policy == ConstraintPolicyLatest || v.Major() == currentVersion.Major() {
+			return v
+		}
+	}
+	return nil
+}
+
+// updateVersionConstraint returns the version constraint updated to require the target version
+// the operator of a single constraint is retained (e.g. ^1.2 -> ^1.5.0, 1.2.0 -> 1.5.0)
+// - any other constraint is replaced with a caret constraint
+func updateVersionConstraint(versionString string, targetVersion *semver.Version) string {
+	match := singleConstraintRegex.FindStringSubmatch(versionString)
+	if match == nil {
+		return "^" + targetVersion.String()
+	}
+	return strings.TrimSpace(match[1]) + targetVersion.String()
+}
@@ -0,0 +1,54 @@
+package modinstaller
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestUpdateVersionConstraint(t *testing.T) {
+	target := semver.MustParse("1.5.2")
+	tests := map[string]string{
+		"^1.2":          "^1.5.2",
+		"~1.2.3":        "~1.5.2",
+		">=1.0":         ">=1.5.2",
+		"1.2.0":         "1.5.2",
+		"v1.2.0":        "1.5.2",
+		">=1.0, <1.4":   "^1.5.2",
+		"1.2.x":         "^1.5.2",
+		"^1.0.0-beta.1": "^1.5.2",
+	}
+	for versionString, expected := range tests {
+		if got := updateVersionConstraint(versionString, target); got != expected {
+			t.Errorf("updateVersionConstraint(%s): expected %s, got %s", versionString, expected, got)
+		}
+	}
+}
+
+func TestGetTargetVersion(t *testing.T) {
+	// available versions are sorted newest first
+	var available []*semver.Version
+	for _, v := range []string{"3.0.0", "2.4.1", "2.4.0", "1.9.0"} {
+		available = append(available, semver.MustParse(v))
+	}
+	tests := []struct {
+		policy   ConstraintPolicy
+		current  string
+		expected string
+	}{
+		{ConstraintPolicyLatest, "1.2.0", "3.0.0"},
+		{ConstraintPolicyMinor, "2.1.0", "2.4.1"},
+		{ConstraintPolicyMinor, "1.2.0", "1.9.0"},
+		{ConstraintPolicyMinor, "4.0.0", ""},
+	}
+	for _, test := range tests {
+		got := getTargetVersion(test.policy, semver.MustParse(test.current), available)
+		gotString := ""
+		if got != nil {
+			gotString = got.String()
+		}
+		if gotString != test.expected {
+			t.Errorf("getTargetVersion(%s, %s): expected %s, got %s", test.policy, test.current, test.expected, gotString)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package modinstaller
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 	"github.com/turbot/steampipe/pkg/constants"
@@ -15,6 +16,7 @@ const (
 	VerbUpgraded    = "Upgraded"
 	VerbDowngraded  = "Downgraded"
 	VerbPruned      = "Pruned"
+	VerbUpdated     = "Updated"
 )
 
 var dryRunVerbs = map[string]string{
@@ -23,6 +25,7 @@ var dryRunVerbs = map[string]string{
 	VerbUpgraded:    "Would upgrade",
 	VerbDowngraded:  "Would downgrade",
 	VerbPruned:      "Would prune",
+	VerbUpdated:     "Would update",
 }
 
 func getVerb(verb string) string {
@@ -58,13 +61,31 @@ func BuildInstallSummary(installData *InstallData) string {
 		downgradeString = fmt.Sprintf("\n%s %d %s:\n\n%s\n", verb, downgradeCount, utils.Pluralize("mod", downgradeCount), downgradeTreeString)
 	}
 
+	constraintCount := len(installData.UpdatedConstraints)
+	var constraintString string
+	if constraintCount > 0 {
+		verb := getVerb(VerbUpdated)
+		constraintString = fmt.Sprintf("\n%s %d version %s in mod.sp:\n\n%s", verb, constraintCount, utils.Pluralize("constraint", constraintCount), getConstraintUpdatesString(installData.UpdatedConstraints))
+	}
+
 	if installCount+uninstallCount+upgradeCount+downgradeCount == 0 {
+		if constraintCount > 0 {
+			return constraintString
+		}
 		if len(installData.Lock.InstallCache) == 0 {
 			return "No mods are installed"
 		}
 		return "All mods are up to date"
 	}
-	return fmt.Sprintf("%s%s%s%s", installString, upgradeString, downgradeString, uninstallString)
+	return fmt.Sprintf("%s%s%s%s%s", constraintString, installString, upgradeString, downgradeString, uninstallString)
+}
+
+func getConstraintUpdatesString(updates []ConstraintUpdate) string {
+	var sb strings.Builder
+	for _, u := range updates {
+		sb.WriteString(fmt.Sprintf("  %s: %s -> %s\n", u.Name, u.OldConstraint, u.NewConstraint))
+	}
+	return sb.String()
 }
 
 func getInstallationResultString(items versionmap.DependencyVersionMap, modDependencyPath string) (int, string) {
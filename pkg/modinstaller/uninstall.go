@@ -2,10 +2,16 @@ package modinstaller
 
 import (
 	"context"
+	"sort"
 
+	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/pkg/actionplan"
+	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/filepaths"
 	"github.com/turbot/steampipe/pkg/utils"
+	"golang.org/x/exp/maps"
 )
 
 func UninstallWorkspaceDependencies(ctx context.Context, opts *InstallOpts) (*InstallData, error) {
@@ -30,3 +36,39 @@ func UninstallWorkspaceDependencies(ctx context.Context, opts *InstallOpts) (*In
 	return installer.installData, nil
 
 }
+
+// PlanUninstallWorkspaceDependencies adds the actions UninstallWorkspaceDependencies would take to the plan,
+// by performing a dry run of the uninstall
+// NOTE: the workspace mod of the opts is modified by the dry run, so must be reloaded before uninstalling
+func PlanUninstallWorkspaceDependencies(ctx context.Context, opts *InstallOpts, plan *actionplan.Plan) error {
+	dryRunOpts := *opts
+	dryRunOpts.DryRun = true
+	installer, err := NewModInstaller(ctx, &dryRunOpts)
+	if err != nil {
+		return err
+	}
+	if err := installer.UninstallWorkspaceDependencies(ctx); err != nil {
+		return err
+	}
+
+	uninstalled := installer.installData.Uninstalled.FlatMap()
+	if len(uninstalled) == 0 {
+		return nil
+	}
+	plan.Add(actionplan.ActionUpdateFile, installer.workspaceMod.FilePath(), "remove the mod requirements")
+	plan.Add(actionplan.ActionUpdateFile, filepaths.WorkspaceLockPath(installer.workspacePath), "remove the locked mod versions")
+	if !viper.GetBool(constants.ArgPrune) {
+		return nil
+	}
+	// the lock is now the lock after the uninstall, so the unreferenced mods are those which will be pruned
+	pruned := maps.Keys(installer.installData.Lock.GetUnreferencedMods().FlatMap())
+	sort.Strings(pruned)
+	for _, dependencyPath := range pruned {
+		reason := "unused dependency"
+		if _, ok := uninstalled[dependencyPath]; ok {
+			reason = "uninstalled mod"
+		}
+		plan.Add(actionplan.ActionDeletePath, installer.getDependencyDestPath(dependencyPath), reason)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package modusage
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TableUsage is the columns of a table used by the workspace, and the resources which use them
+type TableUsage struct {
+	Table     string   `json:"table"`
+	Columns   []string `json:"columns"`
+	Resources []string `json:"resources"`
+}
+
+// MissingColumn is a column referenced by a resource query which no longer exists in the table
+// (if Column is empty, the table itself no longer exists)
+type MissingColumn struct {
+	Resource string `json:"resource"`
+	Table    string `json:"table"`
+	Column   string `json:"column,omitempty"`
+}
+
+// Report is the table usage of a workspace, and any references to tables or columns which no longer exist
+type Report struct {
+	Tables         []TableUsage    `json:"tables"`
+	MissingColumns []MissingColumn `json:"missing_columns"`
+	// resources which have recorded usage but no longer exist in the workspace
+	StaleResources []string `json:"stale_resources,omitempty"`
+}
+
+// Tables returns the names of all tables with recorded usage
+func (u *Usage) Tables() []string {
+	tables := make(map[string]struct{})
+	for _, r := range u.Resources {
+		for table := range r.Tables {
+			tables[table] = struct{}{}
+		}
+	}
+	return sortedKeys(tables)
+}
+
+// BuildReport correlates the recorded usage with the current query text of each resource and the current table schemas
+//
+// resourceSql is a map of resource name to the current SQL of the resource
+// schema is a map of table name to the set of columns the table currently has
+//
+// a column is reported as missing if it was fetched by the resource when usage was recorded,
+// the current resource SQL still references it, and the table no longer has the column
+// (checking the SQL avoids reporting columns which have since been removed from the query)
+func BuildReport(usage *Usage, resourceSql map[string]string, schema map[string]map[string]bool) *Report {
+	res := &Report{
+		Tables:         []TableUsage{},
+		MissingColumns: []MissingColumn{},
+	}
+
+	tableColumns := make(map[string]map[string]struct{})
+	tableResources := make(map[string]map[string]struct{})
+
+	for resourceName, resourceUsage := range usage.Resources {
+		sql, ok := resourceSql[resourceName]
+		if !ok {
+			res.StaleResources = append(res.StaleResources, resourceName)
+			continue
+		}
+		identifiers := SqlIdentifiers(sql)
+
+		for table, columns := range resourceUsage.Tables {
+			if tableColumns[table] == nil {
+				tableColumns[table] = make(map[string]struct{})
+				tableResources[table] = make(map[string]struct{})
+			}
+			tableResources[table][resourceName] = struct{}{}
+
+			currentColumns, tableExists := schema[table]
+			if !tableExists && identifiers[table] {
+				res.MissingColumns = append(res.MissingColumns, MissingColumn{Resource: resourceName, Table: table})
+			}
+			for _, column := range columns {
+				tableColumns[table][column] = struct{}{}
+				if tableExists && identifiers[column] && !currentColumns[column] {
+					res.MissingColumns = append(res.MissingColumns, MissingColumn{Resource: resourceName, Table: table, Column: column})
+				}
+			}
+		}
+	}
+
+	for table, columns := range tableColumns {
+		res.Tables = append(res.Tables, TableUsage{
+			Table:     table,
+			Columns:   sortedKeys(columns),
+			Resources: sortedKeys(tableResources[table]),
+		})
+	}
+
+	sort.Slice(res.Tables, func(i, j int) bool {
+		return res.Tables[i].Table < res.Tables[j].Table
+	})
+	sort.Slice(res.MissingColumns, func(i, j int) bool {
+		a, b := res.MissingColumns[i], res.MissingColumns[j]
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		return a.Column < b.Column
+	})
+	sort.Strings(res.StaleResources)
+	return res
+}
+
+var (
+	sqlCommentRegex       = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/`)
+	sqlStringRegex        = regexp.MustCompile(`(?s)'(?:[^']|'')*'`)
+	sqlQuotedIdentRegex   = regexp.MustCompile(`"((?:[^"]|"")+)"`)
+	sqlUnquotedIdentRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_$]*`)
+)
+
+// SqlIdentifiers returns the set of identifiers (and keywords) referenced in the SQL, ignoring comments and string literals
+// unquoted identifiers are folded to lower case, as postgres does
+func SqlIdentifiers(sql string) map[string]bool {
+	sql = sqlCommentRegex.ReplaceAllString(sql, " ")
+	sql = sqlStringRegex.ReplaceAllString(sql, " ")
+
+	res := make(map[string]bool)
+	for _, match := range sqlQuotedIdentRegex.FindAllStringSubmatch(sql, -1) {
+		res[strings.ReplaceAll(match[1], `""`, `"`)] = true
+	}
+	sql = sqlQuotedIdentRegex.ReplaceAllString(sql, " ")
+	for _, ident := range sqlUnquotedIdentRegex.FindAllString(sql, -1) {
+		res[strings.ToLower(ident)] = true
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package modusage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlIdentifiers(t *testing.T) {
+	sql := `
+-- check versioning_enabled
+select
+  arn as resource,
+  "Mixed_Case" as title,
+  'bucket_policy_is_public' as reason /* region */
+from
+  aws_s3_bucket`
+	res := SqlIdentifiers(sql)
+	for _, expected := range []string{"select", "arn", "resource", "Mixed_Case", "title", "reason", "aws_s3_bucket"} {
+		if !res[expected] {
+			t.Errorf("expected identifier %s", expected)
+		}
+	}
+	for _, unexpected := range []string{"versioning_enabled", "bucket_policy_is_public", "region", "mixed_case"} {
+		if res[unexpected] {
+			t.Errorf("unexpected identifier %s", unexpected)
+		}
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	usage := &Usage{Resources: map[string]*ResourceUsage{
+		"mod.control.bucket_versioning": {Tables: map[string][]string{
+			"aws_s3_bucket": {"arn", "region", "versioning_enabled"},
+		}},
+		"mod.control.bucket_policy": {Tables: map[string][]string{
+			"aws_s3_bucket": {"arn", "policy_std"},
+		}},
+		"mod.control.legacy": {Tables: map[string][]string{
+			"aws_legacy_table": {"id"},
+		}},
+		"mod.control.deleted": {Tables: map[string][]string{
+			"aws_s3_bucket": {"arn"},
+		}},
+	}}
+	resourceSql := map[string]string{
+		"mod.control.bucket_versioning": "select arn, region, versioning_enabled from aws_s3_bucket",
+		// policy_std has been removed from the query, so should not be reported
+		"mod.control.bucket_policy": "select arn from aws_s3_bucket",
+		"mod.control.legacy":        "select id from aws_legacy_table",
+	}
+	schema := map[string]map[string]bool{
+		"aws_s3_bucket": {"arn": true, "region": true, "versioning": true},
+	}
+
+	report := BuildReport(usage, resourceSql, schema)
+
+	expectedMissing := []MissingColumn{
+		{Resource: "mod.control.bucket_versioning", Table: "aws_s3_bucket", Column: "versioning_enabled"},
+		{Resource: "mod.control.legacy", Table: "aws_legacy_table"},
+	}
+	if !reflect.DeepEqual(report.MissingColumns, expectedMissing) {
+		t.Errorf("expected missing columns %v, got %v", expectedMissing, report.MissingColumns)
+	}
+	if !reflect.DeepEqual(report.StaleResources, []string{"mod.control.deleted"}) {
+		t.Errorf("unexpected stale resources %v", report.StaleResources)
+	}
+	expectedTables := []TableUsage{
+		{Table: "aws_legacy_table", Columns: []string{"id"}, Resources: []string{"mod.control.legacy"}},
+		{Table: "aws_s3_bucket", Columns: []string{"arn", "policy_std", "region", "versioning_enabled"}, Resources: []string{"mod.control.bucket_policy", "mod.control.bucket_versioning"}},
+	}
+	if !reflect.DeepEqual(report.Tables, expectedTables) {
+		t.Errorf("expected tables %v, got %v", expectedTables, report.Tables)
+	}
+}
@@ -0,0 +1,136 @@
+package modusage
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+const usageStructVersion = 20240601
+
+// ResourceUsage is the tables and columns fetched by a single query or control when it last ran
+type ResourceUsage struct {
+	LastRun time.Time `json:"last_run"`
+	// map of table name to the columns fetched from that table
+	Tables map[string][]string `json:"tables"`
+}
+
+// Usage is the recorded table and column usage of the resources of a workspace
+type Usage struct {
+	// map of resource name to usage
+	Resources     map[string]*ResourceUsage `json:"resources"`
+	StructVersion int64                     `json:"struct_version"`
+}
+
+func newUsage() *Usage {
+	return &Usage{Resources: make(map[string]*ResourceUsage)}
+}
+
+// Load loads the usage file for the workspace - if the file does not exist, empty usage is returned
+func Load(workspacePath string) (*Usage, error) {
+	path := filepaths.WorkspaceUsagePath(workspacePath)
+	if !filehelpers.FileExists(path) {
+		return newUsage(), nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	usage := newUsage()
+	if err := json.Unmarshal(content, usage); err != nil {
+		// treat an invalid file as empty - it will be overwritten
+		log.Printf("[WARN] failed to parse usage file %s: %s", path, err)
+		return newUsage(), nil
+	}
+	if usage.Resources == nil {
+		usage.Resources = make(map[string]*ResourceUsage)
+	}
+	return usage, nil
+}
+
+func (u *Usage) save(workspacePath string) error {
+	u.StructVersion = usageStructVersion
+	content, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepaths.WorkspaceUsagePath(workspacePath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// Recorder collects the scan metadata of executed resources - it is safe for concurrent use
+type Recorder struct {
+	usage *Usage
+	mut   sync.Mutex
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{usage: newUsage()}
+}
+
+// Record records the tables and columns fetched by the scans of a resource execution
+func (r *Recorder) Record(resourceName string, scans []*queryresult.ScanMetadataRow) {
+	if len(scans) == 0 {
+		return
+	}
+	tableColumns := make(map[string]map[string]struct{})
+	for _, scan := range scans {
+		if tableColumns[scan.Table] == nil {
+			tableColumns[scan.Table] = make(map[string]struct{})
+		}
+		for _, c := range scan.Columns {
+			tableColumns[scan.Table][c] = struct{}{}
+		}
+	}
+
+	resourceUsage := &ResourceUsage{
+		LastRun: time.Now(),
+		Tables:  make(map[string][]string, len(tableColumns)),
+	}
+	for table, columns := range tableColumns {
+		resourceUsage.Tables[table] = sortedKeys(columns)
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.usage.Resources[resourceName] = resourceUsage
+}
+
+// Save merges the recorded usage into the workspace usage file
+// resources which were recorded replace any existing usage, as the latest run reflects the current query
+func (r *Recorder) Save(workspacePath string) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if len(r.usage.Resources) == 0 {
+		return nil
+	}
+	usage, err := Load(workspacePath)
+	if err != nil {
+		return err
+	}
+	for name, resourceUsage := range r.usage.Resources {
+		usage.Resources[name] = resourceUsage
+	}
+	return usage.save(workspacePath)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,137 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/spf13/viper"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	transport    *http.Transport
+	transportMut sync.Mutex
+)
+
+// Configure builds the http transport used for all outbound requests (plugin and asset downloads,
+// Turbot Pipes API calls and update checks) from the network options
+//
+// this must be called once the config has been loaded - it returns any warnings about insecure settings
+func Configure() ([]string, error) {
+	t, warnings, err := newTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	transportMut.Lock()
+	defer transportMut.Unlock()
+	transport = t
+	return warnings, nil
+}
+
+// HttpClient returns a http client which uses the configured CA bundle, proxy and TLS settings
+func HttpClient() *http.Client {
+	return &http.Client{Transport: Transport()}
+}
+
+// Transport returns the configured http transport
+// (if Configure has not been called, a transport using the proxy env vars and system CA pool is returned)
+// in offline mode, the transport fails all requests
+func Transport() *http.Transport {
+	transportMut.Lock()
+	defer transportMut.Unlock()
+	if transport == nil {
+		transport = cleanhttp.DefaultPooledTransport()
+	}
+	return transport
+}
+
+func newTransport() (*http.Transport, []string, error) {
+	var warnings []string
+	t := cleanhttp.DefaultPooledTransport()
+	t.Proxy = proxyFunc()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caCertFile := viper.GetString(constants.ArgCaCertFile); caCertFile != "" {
+		rootCAs, err := loadCertPool(caCertFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if viper.GetBool(constants.ArgTlsSkipVerify) {
+		tlsConfig.InsecureSkipVerify = true
+		warnings = append(warnings, "TLS certificate verification is disabled ('tls_skip_verify' is set) - connections to remote servers are not secure")
+	}
+	t.TLSClientConfig = tlsConfig
+
+	if IsOffline() {
+		// do not use a proxy - no connections are made
+		t.Proxy = nil
+		t.DialContext = offlineDialContext
+	}
+
+	return t, warnings, nil
+}
+
+// proxyFunc returns the proxy function for the transport
+// proxies set in the network options take precedence over the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars
+func proxyFunc() func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if httpProxy := viper.GetString(constants.ArgHttpProxy); httpProxy != "" {
+		config.HTTPProxy = httpProxy
+	}
+	if httpsProxy := viper.GetString(constants.ArgHttpsProxy); httpsProxy != "" {
+		config.HTTPSProxy = httpsProxy
+	}
+	if noProxy := viper.GetString(constants.ArgNoProxy); noProxy != "" {
+		config.NoProxy = noProxy
+	}
+	log.Printf("[TRACE] network proxy config: http_proxy=%s, https_proxy=%s, no_proxy=%s", redactProxy(config.HTTPProxy), redactProxy(config.HTTPSProxy), config.NoProxy)
+
+	proxyForURL := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}
+}
+
+// loadCertPool returns the system CA pool with the certificates in the given PEM file appended
+func loadCertPool(caCertFile string) (*x509.CertPool, error) {
+	path, err := filehelpers.Tildefy(caCertFile)
+	if err != nil {
+		return nil, err
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to read CA certificate file '%s'", caCertFile)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		// the system pool is not available on all platforms - just use the given certificates
+		log.Printf("[INFO] failed to load system CA pool: %s", err)
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, sperr.New("CA certificate file '%s' does not contain any valid PEM certificates", caCertFile)
+	}
+	return pool, nil
+}
+
+// redactProxy removes any credentials from a proxy url so it may be logged
+func redactProxy(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return proxy
+	}
+	return u.Redacted()
+}
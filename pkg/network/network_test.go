@@ -0,0 +1,255 @@
+package network
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// setNetworkOptions sets the given network options, resetting them once the test completes
+func setNetworkOptions(t *testing.T, options map[string]any) {
+	for name, value := range options {
+		viper.Set(name, value)
+	}
+	t.Cleanup(func() {
+		for name := range options {
+			viper.Set(name, nil)
+		}
+	})
+}
+
+// setProxyEnv sets the proxy env vars, clearing the lower case variants which would otherwise be used if set
+func setProxyEnv(t *testing.T, env map[string]string) {
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy", "REQUEST_METHOD"} {
+		t.Setenv(name, env[name])
+	}
+}
+
+// writeCaCertFile writes the certificate of the test server to a PEM file
+func writeCaCertFile(t *testing.T, server *httptest.Server) (string, []byte) {
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, caCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return caCertFile, caCert
+}
+
+func TestProxyFunc(t *testing.T) {
+	tests := map[string]struct {
+		env      map[string]string
+		options  map[string]any
+		url      string
+		expected string
+	}{
+		"no proxy": {
+			url: "https://example.com",
+		},
+		"https proxy env var": {
+			env:      map[string]string{"HTTPS_PROXY": "http://env-proxy:8080"},
+			url:      "https://example.com",
+			expected: "http://env-proxy:8080",
+		},
+		"http proxy env var is not used for https": {
+			env: map[string]string{"HTTP_PROXY": "http://env-proxy:8080"},
+			url: "https://example.com",
+		},
+		"https proxy option overrides env var": {
+			env:      map[string]string{"HTTPS_PROXY": "http://env-proxy:8080"},
+			options:  map[string]any{constants.ArgHttpsProxy: "http://option-proxy:3128"},
+			url:      "https://example.com",
+			expected: "http://option-proxy:3128",
+		},
+		"http proxy option overrides env var": {
+			env:      map[string]string{"HTTP_PROXY": "http://env-proxy:8080"},
+			options:  map[string]any{constants.ArgHttpProxy: "http://option-proxy:3128"},
+			url:      "http://example.com",
+			expected: "http://option-proxy:3128",
+		},
+		"no proxy env var": {
+			env: map[string]string{"HTTPS_PROXY": "http://env-proxy:8080", "NO_PROXY": "example.com"},
+			url: "https://example.com",
+		},
+		"no proxy option": {
+			options: map[string]any{constants.ArgHttpsProxy: "http://option-proxy:3128", constants.ArgNoProxy: ".example.com"},
+			url:     "https://api.example.com",
+		},
+		"no proxy option overrides env var": {
+			env:      map[string]string{"HTTPS_PROXY": "http://env-proxy:8080", "NO_PROXY": "example.com"},
+			options:  map[string]any{constants.ArgNoProxy: "other.com"},
+			url:      "https://example.com",
+			expected: "http://env-proxy:8080",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setProxyEnv(t, test.env)
+			setNetworkOptions(t, test.options)
+
+			reqUrl, err := url.Parse(test.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proxy, err := proxyFunc()(&http.Request{URL: reqUrl})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			var got string
+			if proxy != nil {
+				got = proxy.String()
+			}
+			if got != test.expected {
+				t.Errorf("expected proxy '%s', got '%s'", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadCertPool(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	caCertFile, caCert := writeCaCertFile(t, server)
+
+	badCertFile := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(badCertFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		caCertFile  string
+		expectedErr string
+	}{
+		"CA bundle":   {caCertFile: caCertFile},
+		"invalid PEM": {caCertFile: badCertFile, expectedErr: "does not contain any valid PEM certificates"},
+		"missing file": {
+			caCertFile:  filepath.Join(t.TempDir(), "missing.pem"),
+			expectedErr: "failed to read CA certificate file",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pool, err := loadCertPool(test.caCertFile)
+			if test.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+					t.Errorf("expected error '%s', got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			// the certificates are appended to the system pool
+			expected, err := x509.SystemCertPool()
+			if err != nil {
+				expected = x509.NewCertPool()
+			}
+			expected.AppendCertsFromPEM(caCert)
+			if !pool.Equal(expected) {
+				t.Errorf("expected the CA bundle to be appended to the system pool")
+			}
+		})
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	caCertFile, _ := writeCaCertFile(t, server)
+
+	tests := map[string]struct {
+		options          map[string]any
+		expectedWarnings int
+		expectedErr      string
+		// whether a request to the test server succeeds using the transport
+		expectTrusted bool
+		expectOffline bool
+	}{
+		"default": {},
+		"CA bundle": {
+			options:       map[string]any{constants.ArgCaCertFile: caCertFile},
+			expectTrusted: true,
+		},
+		"tls_skip_verify": {
+			options:          map[string]any{constants.ArgTlsSkipVerify: true},
+			expectedWarnings: 1,
+			expectTrusted:    true,
+		},
+		"invalid CA bundle": {
+			options:     map[string]any{constants.ArgCaCertFile: filepath.Join(t.TempDir(), "missing.pem")},
+			expectedErr: "failed to read CA certificate file",
+		},
+		"offline": {
+			options:       map[string]any{constants.ArgOffline: true, constants.ArgCaCertFile: caCertFile},
+			expectOffline: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setProxyEnv(t, nil)
+			setNetworkOptions(t, test.options)
+
+			transport, warnings, err := newTransport()
+			if test.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+					t.Errorf("expected error '%s', got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if len(warnings) != test.expectedWarnings {
+				t.Errorf("expected %d warnings, got %v", test.expectedWarnings, warnings)
+			}
+			if test.expectedWarnings > 0 && !strings.Contains(warnings[0], "tls_skip_verify") {
+				t.Errorf("expected a tls_skip_verify warning, got '%s'", warnings[0])
+			}
+
+			client := &http.Client{Transport: transport}
+			resp, err := client.Get(server.URL)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			switch {
+			case test.expectOffline:
+				if err == nil || !strings.Contains(err.Error(), "offline mode") {
+					t.Errorf("expected the request to fail in offline mode, got %v", err)
+				}
+				if transport.Proxy != nil {
+					t.Errorf("expected no proxy in offline mode")
+				}
+			case test.expectTrusted:
+				if err != nil {
+					t.Errorf("expected the request to succeed, got %s", err.Error())
+				}
+			default:
+				if err == nil {
+					t.Errorf("expected the request to an untrusted server to fail")
+				}
+			}
+		})
+	}
+}
+
+func TestOfflineDialContext(t *testing.T) {
+	conn, err := offlineDialContext(context.Background(), "tcp", "example.com:443")
+	if conn != nil {
+		conn.Close()
+		t.Errorf("expected no connection in offline mode")
+	}
+	if err == nil || !strings.Contains(err.Error(), "example.com:443") || !strings.Contains(err.Error(), "offline mode") {
+		t.Errorf("expected an offline mode error naming the address, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package network
+
+import (
+	"context"
+	"net"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// IsOffline returns whether steampipe is running in offline mode (--offline or STEAMPIPE_OFFLINE)
+// in offline mode all outbound network requests (update checks, telemetry, Turbot Pipes and hub lookups) are disabled
+func IsOffline() bool {
+	return viper.GetBool(constants.ArgOffline)
+}
+
+// CheckOnline returns an error if steampipe is running in offline mode
+// operation describes the action which requires network access, e.g. "plugin install"
+func CheckOnline(operation string) error {
+	if !IsOffline() {
+		return nil
+	}
+	return sperr.New("%s requires network access but steampipe is running in offline mode - remove the --%s flag or unset %s", operation, constants.ArgOffline, constants.EnvOffline)
+}
+
+// offlineDialContext is used as the transport DialContext in offline mode
+// this ensures any outbound request which is not explicitly guarded by CheckOnline fails fast
+func offlineDialContext(_ context.Context, _, address string) (net.Conn, error) {
+	return nil, sperr.New("cannot connect to %s - steampipe is running in offline mode", address)
+}
@@ -0,0 +1,100 @@
+package ociinstaller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+// NewOciArchiveDownloader creates an ociDownloader which reads images from a local OCI archive rather than a registry
+// the archive may be an OCI image layout directory or a tarball of one (e.g. created with 'oras copy --to-oci-layout')
+// this allows installation on networks with no registry access
+func NewOciArchiveDownloader(archivePath string) *ociDownloader {
+	o := NewOciDownloader()
+	o.archivePath = archivePath
+	return o
+}
+
+// archiveSource opens the OCI archive and resolves the tag of the image with the given ref
+func (o *ociDownloader) archiveSource(ctx context.Context, ref, tag string) (oras.ReadOnlyTarget, string, error) {
+	store, err := openOciArchive(ctx, o.archivePath)
+	if err != nil {
+		return nil, "", sperr.WrapWithMessage(err, "failed to open OCI archive %s", o.archivePath)
+	}
+	// a digest ref can be resolved directly
+	if isDigestRef(ref) {
+		return store, tag, nil
+	}
+	archiveTag, err := resolveArchiveTag(ctx, store, ref, tag)
+	if err != nil {
+		return nil, "", err
+	}
+	return store, archiveTag, nil
+}
+
+func openOciArchive(ctx context.Context, archivePath string) (*oci.ReadOnlyStore, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return oci.NewFromFS(ctx, os.DirFS(archivePath))
+	}
+	return oci.NewFromTar(ctx, archivePath)
+}
+
+// resolveArchiveTag returns the tag of the image in the archive which has the given ref
+// images in an archive may be tagged with their full ref or just the tag - an image with any other tag is never used,
+// so an archive cannot install a different image (or version) from the one requested
+func resolveArchiveTag(ctx context.Context, store *oci.ReadOnlyStore, ref, tag string) (string, error) {
+	var tags []string
+	err := store.Tags(ctx, "", func(page []string) error {
+		for _, t := range page {
+			// exclude the signatures of the images
+			if !strings.HasSuffix(t, cosignSignatureTagSuffix) {
+				tags = append(tags, t)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range []string{ref, tag} {
+		for _, t := range tags {
+			if t == candidate {
+				return t, nil
+			}
+		}
+	}
+	if len(tags) == 0 {
+		return "", fmt.Errorf("OCI archive contains no tagged images")
+	}
+	return "", fmt.Errorf("OCI archive does not contain %s - it contains: %s", ref, strings.Join(tags, ", "))
+}
+
+// validateArchiveImage returns an error if the image read from an archive is not of the requested type,
+// i.e. its config is not a steampipe config, or it has no layer with any of the requested media types
+// (e.g. an FDW image in an archive used to install the database)
+func validateArchiveImage(ref string, manifest *ocispec.Manifest, mediaTypes []string) error {
+	if !slices.Contains(ConfigMediaTypes(), manifest.Config.MediaType) {
+		return fmt.Errorf("image %s in the OCI archive is not a steampipe image (config media type %s)", ref, manifest.Config.MediaType)
+	}
+	for _, layer := range manifest.Layers {
+		if slices.Contains(mediaTypes, layer.MediaType) {
+			return nil
+		}
+	}
+	layerMediaTypes := make([]string, len(manifest.Layers))
+	for i, layer := range manifest.Layers {
+		layerMediaTypes[i] = layer.MediaType
+	}
+	return fmt.Errorf("image %s in the OCI archive does not contain any of the expected layers - it contains: %s", ref, strings.Join(layerMediaTypes, ", "))
+}
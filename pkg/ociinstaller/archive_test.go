@@ -0,0 +1,93 @@
+package ociinstaller
+
+import (
+	"context"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+func TestResolveArchiveTag(t *testing.T) {
+	ctx := context.Background()
+
+	// create an OCI layout containing the given tags
+	createArchive := func(tags ...string) string {
+		dir := t.TempDir()
+		store, err := oci.New(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		desc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.turbot.steampipe.test", oras.PackManifestOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range tags {
+			if err := store.Tag(ctx, desc, tag); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	ref := "ghcr.io/turbot/steampipe/plugins/turbot/aws:0.118.0"
+	tests := map[string]struct {
+		tags    []string
+		want    string
+		wantErr bool
+	}{
+		"full ref": {tags: []string{ref, "ghcr.io/turbot/steampipe/plugins/turbot/aws:0.117.0"}, want: ref},
+		"tag":      {tags: []string{"0.117.0", "0.118.0"}, want: "0.118.0"},
+		// an image with another tag is never used, even if it is the only image in the archive
+		"single image":  {tags: []string{"latest"}, wantErr: true},
+		"missing image": {tags: []string{"0.116.0", "0.117.0"}, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := openOciArchive(ctx, createArchive(test.tags...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := resolveArchiveTag(ctx, store, ref, "0.118.0")
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateArchiveImage(t *testing.T) {
+	dbMediaTypes := append([]string{MediaTypeDbLinuxAmd64Layer}, SharedMediaTypes(ImageTypeDatabase)...)
+	tests := map[string]struct {
+		configMediaType string
+		layers          []string
+		wantErr         bool
+	}{
+		"db image":              {configMediaType: MediaTypeConfig, layers: []string{MediaTypeDbLinuxAmd64Layer, MediaTypeDbLicenseLayer}},
+		"fdw image":             {configMediaType: MediaTypeConfig, layers: []string{MediaTypeFdwLinuxAmd64Layer, MediaTypeFdwLicenseLayer}, wantErr: true},
+		"not a steampipe image": {configMediaType: "application/vnd.oci.image.config.v1+json", layers: []string{MediaTypeDbLinuxAmd64Layer}, wantErr: true},
+	}
+	for name, test := range tests {
+		manifest := &ocispec.Manifest{Config: ocispec.Descriptor{MediaType: test.configMediaType}}
+		for _, mediaType := range test.layers {
+			manifest.Layers = append(manifest.Layers, ocispec.Descriptor{MediaType: mediaType})
+		}
+		err := validateArchiveImage("ghcr.io/turbot/steampipe/db:14.2.0", manifest, dbMediaTypes)
+		if test.wantErr && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if !test.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err.Error())
+		}
+	}
+}
@@ -4,41 +4,53 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
 
 	"github.com/turbot/steampipe/pkg/constants"
-	"github.com/turbot/steampipe/pkg/filepaths"
 )
 
-// InstallAssets installs the Steampipe report server assets
-func InstallAssets(ctx context.Context, assetsLocation string) error {
-	tempDir := NewTempDir(assetsLocation)
+// InstallAssets installs the Steampipe report server assets, replacing any assets which are already installed
+// if a digest is given, the image with that manifest digest is installed, otherwise the image for this version
+// it returns the manifest digest of the installed image
+func InstallAssets(ctx context.Context, assetsLocation string, digest string) (string, error) {
+	// download to a sibling of the assets location, so the current assets may be replaced
+	tempDir := NewTempDir(filepath.Dir(assetsLocation))
 	defer func() {
 		if err := tempDir.Delete(); err != nil {
 			log.Printf("[TRACE] Failed to delete temp dir '%s' after installing assets: %s", tempDir, err)
 		}
 	}()
 
+	imageRef := constants.DashboardAssetsImageRef
+	if digest != "" {
+		imageRef = fmt.Sprintf("%s@%s", constants.DashboardAssetsImageRepo, digest)
+	}
+
 	// download the blobs
 	imageDownloader := NewOciDownloader()
-	image, err := imageDownloader.Download(ctx, NewSteampipeImageRef(constants.DashboardAssetsImageRef), ImageTypeAssets, tempDir.Path)
+	image, err := imageDownloader.Download(ctx, NewSteampipeImageRef(imageRef), ImageTypeAssets, tempDir.Path)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// install the files
 	if err = installAssetsFiles(image, tempDir.Path, assetsLocation); err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return string(image.OCIDescriptor.Digest), nil
 }
 
 func installAssetsFiles(image *SteampipeImage, tempdir string, dest string) error {
 	fileName := image.Assets.ReportUI
 	sourcePath := filepath.Join(tempdir, fileName)
-	if err := moveFolderWithinPartition(sourcePath, filepaths.EnsureDashboardAssetsDir()); err != nil {
-		return fmt.Errorf("could not install %s to %s", sourcePath, filepaths.EnsureDashboardAssetsDir())
+	// remove the existing assets so that no files from a previous version remain
+	if err := os.RemoveAll(dest); err != nil {
+		return fmt.Errorf("could not remove existing assets from %s", dest)
+	}
+	if err := moveFolderWithinPartition(sourcePath, dest); err != nil {
+		return fmt.Errorf("could not install %s to %s", sourcePath, dest)
 	}
 	return nil
 }
@@ -2,6 +2,9 @@ package ociinstaller
 
 type pluginInstallConfig struct {
 	skipConfigFile bool
+	archivePath    string
+	sideBySide     bool
+	backup         bool
 }
 
 type PluginInstallOption = func(config *pluginInstallConfig)
@@ -11,3 +14,27 @@ func WithSkipConfig(skipConfigFile bool) PluginInstallOption {
 		o.skipConfigFile = skipConfigFile
 	}
 }
+
+// WithArchive installs the plugin from a local OCI archive (an OCI image layout directory or tarball)
+// rather than the registry
+func WithArchive(archivePath string) PluginInstallOption {
+	return func(o *pluginInstallConfig) {
+		o.archivePath = archivePath
+	}
+}
+
+// WithSideBySide installs the plugin in a directory addressed by the image digest, rather than the directory
+// of the requested stream, so it is installed alongside any other installed versions of the plugin
+// (no config file is installed, as the connections of the plugin already exist)
+func WithSideBySide(sideBySide bool) PluginInstallOption {
+	return func(o *pluginInstallConfig) {
+		o.sideBySide = sideBySide
+	}
+}
+
+// WithBackup keeps the currently installed version of the plugin, so that it can be restored by RollbackPlugin
+func WithBackup(backup bool) PluginInstallOption {
+	return func(o *pluginInstallConfig) {
+		o.backup = backup
+	}
+}
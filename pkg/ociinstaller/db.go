@@ -12,6 +12,16 @@ import (
 
 // InstallDB :: Install Postgres files fom OCI image
 func InstallDB(ctx context.Context, dblocation string) (string, error) {
+	return installDB(ctx, dblocation, NewOciDownloader())
+}
+
+// InstallDBFromArchive installs the Postgres files from the image in a local OCI archive
+// (an OCI image layout directory or tarball), for installation without registry access
+func InstallDBFromArchive(ctx context.Context, dblocation string, archivePath string) (string, error) {
+	return installDB(ctx, dblocation, NewOciArchiveDownloader(archivePath))
+}
+
+func installDB(ctx context.Context, dblocation string, imageDownloader *ociDownloader) (string, error) {
 	tempDir := NewTempDir(dblocation)
 	defer func() {
 		if err := tempDir.Delete(); err != nil {
@@ -19,8 +29,6 @@ func InstallDB(ctx context.Context, dblocation string) (string, error) {
 		}
 	}()
 
-	imageDownloader := NewOciDownloader()
-
 	// Download the blobs
 	image, err := imageDownloader.Download(ctx, NewSteampipeImageRef(constants.PostgresImageRef), ImageTypeDatabase, tempDir.Path)
 	if err != nil {
@@ -47,6 +55,7 @@ func updateVersionFileDB(image *SteampipeImage) error {
 	v.EmbeddedDB.Version = image.Config.Database.Version
 	v.EmbeddedDB.Name = "embeddedDB"
 	v.EmbeddedDB.ImageDigest = string(image.OCIDescriptor.Digest)
+	v.EmbeddedDB.License = image.License()
 	v.EmbeddedDB.InstalledFrom = image.ImageRef.requestedRef
 	v.EmbeddedDB.LastCheckedDate = timeNow
 	v.EmbeddedDB.InstallDate = timeNow
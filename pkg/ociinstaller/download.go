@@ -0,0 +1,169 @@
+package ociinstaller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"oras.land/oras-go/v2"
+)
+
+const (
+	// the maximum number of blobs of an image which are downloaded concurrently
+	downloadConcurrency = 5
+	// the number of times the download of a blob is resumed after a failure before giving up
+	maxDownloadResumeAttempts = 5
+	// the interval between download progress updates
+	downloadProgressInterval = 250 * time.Millisecond
+)
+
+// the delay before the first attempt to resume a download - this is increased for each subsequent attempt
+var downloadResumeBackoff = time.Second
+
+// resumableSource wraps a source, resuming the download of a blob from the failure offset if reading it fails
+// (rather than restarting the whole copy) and reporting the download progress
+type resumableSource struct {
+	oras.ReadOnlyTarget
+	progress *downloadProgress
+}
+
+func newResumableSource(ctx context.Context, source oras.ReadOnlyTarget, ref string) *resumableSource {
+	return &resumableSource{
+		ReadOnlyTarget: source,
+		progress:       newDownloadProgress(ctx, ref),
+	}
+}
+
+// Fetch implements content.Fetcher
+func (s *resumableSource) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	reader, err := s.ReadOnlyTarget.Fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	s.progress.addTotal(target.Size)
+	return &resumableReader{
+		ctx:      ctx,
+		source:   s.ReadOnlyTarget,
+		target:   target,
+		reader:   reader,
+		progress: s.progress,
+	}, nil
+}
+
+// resumableReader reads a blob, re-fetching the remainder of the blob if a read fails
+type resumableReader struct {
+	ctx      context.Context
+	source   oras.ReadOnlyTarget
+	target   ocispec.Descriptor
+	reader   io.ReadCloser
+	offset   int64
+	attempts int
+	progress *downloadProgress
+}
+
+func (r *resumableReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+	r.progress.add(int64(n))
+	if err == nil || err == io.EOF || r.offset >= r.target.Size {
+		return n, err
+	}
+	if resumeErr := r.resume(err); resumeErr != nil {
+		return n, resumeErr
+	}
+	return n, nil
+}
+
+func (r *resumableReader) Close() error {
+	return r.reader.Close()
+}
+
+// resume re-fetches the blob, positioned at the current offset
+// if the blob cannot be re-fetched within the maximum number of attempts, the last error is returned
+func (r *resumableReader) resume(cause error) error {
+	for r.attempts < maxDownloadResumeAttempts {
+		r.attempts++
+		log.Printf("[TRACE] download of %s failed after %d of %d bytes: %s - resuming (attempt %d)", r.target.Digest, r.offset, r.target.Size, cause.Error(), r.attempts)
+
+		select {
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		case <-time.After(time.Duration(r.attempts) * downloadResumeBackoff):
+		}
+
+		reader, err := r.source.Fetch(r.ctx, r.target)
+		if err != nil {
+			cause = err
+			continue
+		}
+		if err := skipTo(reader, r.offset); err != nil {
+			reader.Close()
+			cause = err
+			continue
+		}
+		r.reader.Close()
+		r.reader = reader
+		return nil
+	}
+	return cause
+}
+
+// skipTo positions a newly fetched reader at the given offset
+// if the reader is seekable (i.e. the registry supports range requests), only the remainder of the blob is downloaded,
+// otherwise the content before the offset is downloaded again and discarded
+func skipTo(reader io.Reader, offset int64) error {
+	if offset == 0 {
+		return nil
+	}
+	if seeker, ok := reader.(io.Seeker); ok {
+		if _, err := seeker.Seek(offset, io.SeekStart); err == nil {
+			return nil
+		}
+	}
+	_, err := io.CopyN(io.Discard, reader, offset)
+	return err
+}
+
+// downloadProgress reports the number of bytes downloaded by the concurrent blob downloads of an image
+type downloadProgress struct {
+	ctx        context.Context
+	ref        string
+	total      int64
+	downloaded int64
+	lastUpdate time.Time
+	mut        sync.Mutex
+}
+
+func newDownloadProgress(ctx context.Context, ref string) *downloadProgress {
+	return &downloadProgress{ctx: ctx, ref: ref}
+}
+
+func (p *downloadProgress) addTotal(size int64) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.total += size
+}
+
+func (p *downloadProgress) add(bytes int64) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.downloaded += bytes
+	if time.Since(p.lastUpdate) < downloadProgressInterval {
+		return
+	}
+	p.lastUpdate = time.Now()
+	statushooks.SetStatus(p.ctx, fmt.Sprintf("Downloading %s (%s of %s)", p.ref, formatBytes(p.downloaded), formatBytes(p.total)))
+}
+
+func formatBytes(bytes int64) string {
+	const mb = 1024 * 1024
+	if bytes < mb {
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/mb)
+}
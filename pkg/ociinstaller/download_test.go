@@ -0,0 +1,73 @@
+package ociinstaller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// flakySource is a source whose first reader fails after reading failAfter bytes
+type flakySource struct {
+	oras.ReadOnlyTarget
+	failAfter int64
+	fetches   int
+}
+
+func (s *flakySource) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	s.fetches++
+	reader, err := s.ReadOnlyTarget.Fetch(ctx, target)
+	if err != nil || s.fetches > 1 {
+		return reader, err
+	}
+	return &flakyReader{ReadCloser: reader, remaining: s.failAfter}, nil
+}
+
+type flakyReader struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (r *flakyReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, errors.New("connection reset by peer")
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
+
+func TestResumableSource(t *testing.T) {
+	ctx := context.Background()
+	blob := bytes.Repeat([]byte("0123456789"), 150)
+	desc := content.NewDescriptorFromBytes("application/octet-stream", blob)
+	store := memory.New()
+	if err := store.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal(err)
+	}
+
+	downloadResumeBackoff = time.Millisecond
+	// the first read fails after 1000 bytes, so the download must be resumed
+	flaky := &flakySource{ReadOnlyTarget: store, failAfter: 1000}
+	source := newResumableSource(ctx, flaky, "test")
+	fetched, err := content.FetchAll(ctx, source, desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !bytes.Equal(fetched, blob) {
+		t.Error("fetched content does not match the blob")
+	}
+	if flaky.fetches != 2 {
+		t.Errorf("expected the blob to be fetched twice, got %d", flaky.fetches)
+	}
+}
@@ -15,6 +15,15 @@ import (
 
 // InstallFdw installs the Steampipe Postgres foreign data wrapper from an OCI image
 func InstallFdw(ctx context.Context, dbLocation string) (string, error) {
+	return installFdw(ctx, dbLocation, NewOciDownloader())
+}
+
+// InstallFdwFromArchive installs the Steampipe Postgres foreign data wrapper from the image in a local OCI archive
+func InstallFdwFromArchive(ctx context.Context, dbLocation string, archivePath string) (string, error) {
+	return installFdw(ctx, dbLocation, NewOciArchiveDownloader(archivePath))
+}
+
+func installFdw(ctx context.Context, dbLocation string, imageDownloader *ociDownloader) (string, error) {
 	tempDir := NewTempDir(dbLocation)
 	defer func() {
 		if err := tempDir.Delete(); err != nil {
@@ -22,8 +31,6 @@ func InstallFdw(ctx context.Context, dbLocation string) (string, error) {
 		}
 	}()
 
-	imageDownloader := NewOciDownloader()
-
 	// download the blobs.
 	image, err := imageDownloader.Download(ctx, NewSteampipeImageRef(constants.FdwImageRef), ImageTypeFdw, tempDir.Path)
 	if err != nil {
@@ -51,6 +58,7 @@ func updateVersionFileFdw(image *SteampipeImage) error {
 	v.FdwExtension.Version = image.Config.Fdw.Version
 	v.FdwExtension.Name = "fdwExtension"
 	v.FdwExtension.ImageDigest = string(image.OCIDescriptor.Digest)
+	v.FdwExtension.License = image.License()
 	v.FdwExtension.InstalledFrom = image.ImageRef.requestedRef
 	v.FdwExtension.LastCheckedDate = timeNow
 	v.FdwExtension.InstallDate = timeNow
@@ -76,6 +76,17 @@ func isDigestRef(ref string) bool {
 	return strings.Contains(ref, "@sha256:")
 }
 
+// DigestConstraint returns the constraint used for the install directory of a plugin installed side by side
+// (this is the same as the constraint of the display ref of the digest, e.g. aws@sha256-<hex>)
+func DigestConstraint(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+// IsDigestConstraint returns whether the constraint is the digest of a side by side install
+func IsDigestConstraint(constraint string) bool {
+	return strings.HasPrefix(constraint, "sha256-")
+}
+
 // sanitizes the ref to exclude any 'v' prefix
 // in the stream (if any)
 func sanitizeRefStream(ref string) string {
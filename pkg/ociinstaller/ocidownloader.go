@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"net/http"
 	"strings"
 
 	"github.com/containerd/containerd/remotes"
 	"github.com/containerd/containerd/remotes/docker"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
+	"github.com/turbot/steampipe/pkg/network"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/file"
@@ -23,6 +25,8 @@ import (
 type ociDownloader struct {
 	resolver remotes.Resolver
 	Images   []*SteampipeImage
+	// if set, images are read from this local OCI archive rather than the registry
+	archivePath string
 }
 
 // NewOciDownloader creates and returns a ociDownloader instance
@@ -31,7 +35,7 @@ func NewOciDownloader() *ociDownloader {
 	// warning and above.  Set to ErrrLevel to get rid of unwanted error message
 	logrus.SetLevel(logrus.ErrorLevel)
 	return &ociDownloader{
-		resolver: docker.NewResolver(docker.ResolverOptions{}),
+		resolver: docker.NewResolver(docker.ResolverOptions{Client: network.HttpClient()}),
 	}
 }
 
@@ -45,6 +49,10 @@ Returns
 func (o *ociDownloader) Pull(ctx context.Context, ref string, mediaTypes []string, destDir string) (*ocispec.Descriptor, *ocispec.Descriptor, []byte, []ocispec.Descriptor, error) {
 	split := strings.Split(ref, ":")
 	tag := split[len(split)-1]
+	// for a digest ref, copy the manifest with that digest (oras verifies the content against the digest)
+	if isDigestRef(ref) {
+		tag = ref[strings.LastIndex(ref, "@")+1:]
+	}
 	log.Println("[TRACE] ociDownloader.Pull:", "preparing to pull ref", ref, "tag", tag, "destDir", destDir)
 
 	// Create the target file store
@@ -55,37 +63,36 @@ func (o *ociDownloader) Pull(ctx context.Context, ref string, mediaTypes []strin
 	}
 	defer fileStore.Close()
 
-	// Connect to the remote repository
-	repo, err := remote.NewRepository(ref)
-	if err != nil {
-		return nil, nil, nil, nil, err
+	// get the repository to copy from - either the remote repository or a local archive
+	var source oras.ReadOnlyTarget
+	sourceTag := tag
+	if o.archivePath != "" {
+		source, sourceTag, err = o.archiveSource(ctx, ref, tag)
+	} else {
+		source, err = o.remoteRepository(ref)
 	}
-
-	// Get credentials from the docker credentials store
-	storeOpts := credentials.StoreOptions{}
-	credStore, err := credentials.NewStoreFromDocker(storeOpts)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
 
-	// Prepare the auth client for the registry and credential store
-	repo.Client = &auth.Client{
-		Client:     retry.DefaultClient,
-		Cache:      auth.DefaultCache,
-		Credential: credentials.Credential(credStore), // Use the credential store
-	}
-
-	// Copy from the remote repository to the file store
+	// Copy from the repository to the file store
 	log.Println("[TRACE] ociDownloader.Pull:", "pulling...")
 
+	// download the blobs of the image concurrently, resuming the download of any blob which fails part way
 	copyOpt := oras.DefaultCopyOptions
-	manifestDescriptor, err := oras.Copy(ctx, repo, tag, fileStore, tag, copyOpt)
+	copyOpt.Concurrency = downloadConcurrency
+	manifestDescriptor, err := oras.Copy(ctx, newResumableSource(ctx, source, ref), sourceTag, fileStore, tag, copyOpt)
 	if err != nil {
 		log.Println("[TRACE] ociDownloader.Pull:", "failed to pull", ref, err)
 		return nil, nil, nil, nil, err
 	}
 	log.Println("[TRACE] ociDownloader.Pull:", "manifest", manifestDescriptor.Digest, manifestDescriptor.MediaType)
 
+	// verify the signature of the image (as configured by the image options)
+	if err := verifyImageSignature(ctx, source, ref, manifestDescriptor); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// FIXME: this seems redundant as oras.Copy() already downloads all artifacts, but that's the only I found
 	// to access the manifest config. Also, it shouldn't be an issue as files are not re-downloaded.
 	manifestJson, err := content.FetchAll(ctx, fileStore, manifestDescriptor)
@@ -102,6 +109,12 @@ func (o *ociDownloader) Pull(ctx context.Context, ref string, mediaTypes []strin
 		log.Println("[TRACE] ociDownloader.Pull:", "failed to unmarshall manifest", manifestJson)
 		return nil, nil, nil, nil, err
 	}
+	// an archive may contain images of other types - verify this is the image type being installed
+	if o.archivePath != "" {
+		if err := validateArchiveImage(ref, &manifest, mediaTypes); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
 
 	// Fetch the config from the file store
 	configData, err := content.FetchAll(ctx, fileStore, manifest.Config)
@@ -111,5 +124,40 @@ func (o *ociDownloader) Pull(ctx context.Context, ref string, mediaTypes []strin
 	}
 	log.Println("[TRACE] ociDownloader.Pull:", "config", string(configData))
 
+	// include the manifest annotations (e.g. the image licenses) in the returned image descriptor
+	if len(manifest.Annotations) > 0 {
+		annotations := make(map[string]string, len(manifestDescriptor.Annotations)+len(manifest.Annotations))
+		for k, v := range manifestDescriptor.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range manifest.Annotations {
+			annotations[k] = v
+		}
+		manifestDescriptor.Annotations = annotations
+	}
+
 	return &manifestDescriptor, &manifest.Config, configData, manifest.Layers, err
 }
+
+// remoteRepository returns the remote repository of the ref, authenticated using the docker credentials store
+func (o *ociDownloader) remoteRepository(ref string) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get credentials from the docker credentials store
+	storeOpts := credentials.StoreOptions{}
+	credStore, err := credentials.NewStoreFromDocker(storeOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the auth client for the registry and credential store
+	repo.Client = &auth.Client{
+		Client:     &http.Client{Transport: retry.NewTransport(network.Transport())},
+		Cache:      auth.DefaultCache,
+		Credential: credentials.Credential(credStore), // Use the credential store
+	}
+	return repo, nil
+}
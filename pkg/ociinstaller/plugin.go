@@ -21,7 +21,7 @@ import (
 var versionFileUpdateLock = &sync.Mutex{}
 
 // InstallPlugin installs a plugin from an OCI Image
-func InstallPlugin(ctx context.Context, imageRef string, constraint string, sub chan struct{}, opts ...PluginInstallOption) (*SteampipeImage, error) {
+func InstallPlugin(ctx context.Context, imageRef string, constraint string, sub chan struct{}, opts ...PluginInstallOption) (_ *SteampipeImage, err error) {
 	config := &pluginInstallConfig{}
 	for _, opt := range opts {
 		opt(config)
@@ -37,6 +37,9 @@ func InstallPlugin(ctx context.Context, imageRef string, constraint string, sub
 
 	ref := NewSteampipeImageRef(imageRef)
 	imageDownloader := NewOciDownloader()
+	if config.archivePath != "" {
+		imageDownloader = NewOciArchiveDownloader(config.archivePath)
+	}
 
 	sub <- struct{}{}
 	image, err := imageDownloader.Download(ctx, ref, ImageTypePlugin, tempDir.Path)
@@ -44,10 +47,29 @@ func InstallPlugin(ctx context.Context, imageRef string, constraint string, sub
 		return nil, err
 	}
 
+	// for a side by side install, the plugin is installed in the directory of its digest
+	if config.sideBySide {
+		constraint = DigestConstraint(string(image.OCIDescriptor.Digest))
+		config.skipConfigFile = true
+	}
+
 	// update the image ref to include the constraint and use to get the plugin install path
 	constraintRef := image.ImageRef.DisplayImageRefConstraintOverride(constraint)
 	pluginPath := filepaths.EnsurePluginInstallDir(constraintRef)
 
+	// move the installed version of the plugin to its rollback directory - if the install fails, it is restored
+	if config.backup {
+		restoreBackup, backupErr := backupPlugin(constraintRef, pluginPath)
+		if backupErr != nil {
+			return nil, fmt.Errorf("plugin installation failed: could not back up the installed plugin: %s", backupErr)
+		}
+		defer func() {
+			if err != nil && restoreBackup != nil {
+				restoreBackup()
+			}
+		}()
+	}
+
 	sub <- struct{}{}
 	if err = installPluginBinary(image, tempDir.Path, pluginPath); err != nil {
 		return nil, fmt.Errorf("plugin installation failed: %s", err)
@@ -62,7 +84,7 @@ func InstallPlugin(ctx context.Context, imageRef string, constraint string, sub
 		}
 	}
 	sub <- struct{}{}
-	if err := updatePluginVersionFiles(ctx, image, constraint); err != nil {
+	if err = updatePluginVersionFiles(ctx, image, constraint); err != nil {
 		return nil, err
 	}
 	return image, nil
@@ -94,6 +116,7 @@ func updatePluginVersionFiles(ctx context.Context, image *SteampipeImage, constr
 	installedVersion.ImageDigest = string(image.OCIDescriptor.Digest)
 	installedVersion.BinaryDigest = image.Plugin.BinaryDigest
 	installedVersion.BinaryArchitecture = image.Plugin.BinaryArchitecture
+	installedVersion.License = image.License()
 	installedVersion.InstalledFrom = image.ImageRef.ActualImageRef()
 	installedVersion.LastCheckedDate = timeNow
 	installedVersion.InstallDate = timeNow
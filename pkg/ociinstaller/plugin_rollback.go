@@ -0,0 +1,120 @@
+package ociinstaller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+)
+
+// backupPlugin moves the plugin installed in pluginPath to the rollback directory of the plugin, replacing any previous backup
+// it returns a function which restores the backup (used if the install fails) - this is nil if no plugin is installed
+func backupPlugin(constraintRef, pluginPath string) (func(), error) {
+	if pluginBinary(pluginPath) == "" {
+		return nil, nil
+	}
+	rollbackDir := filepaths.PluginRollbackDir(constraintRef)
+	if err := os.RemoveAll(rollbackDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(rollbackDir), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(pluginPath, rollbackDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(pluginPath, 0755); err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] moved %s to %s", pluginPath, rollbackDir)
+
+	restore := func() {
+		log.Printf("[INFO] install of %s failed - restoring the previously installed version", constraintRef)
+		if err := os.RemoveAll(pluginPath); err != nil {
+			log.Printf("[WARN] failed to remove %s: %s", pluginPath, err.Error())
+			return
+		}
+		if err := os.Rename(rollbackDir, pluginPath); err != nil {
+			log.Printf("[WARN] failed to restore %s: %s", pluginPath, err.Error())
+		}
+	}
+	return restore, nil
+}
+
+// RollbackPlugin restores the version of a plugin which was installed before the plugin was last updated,
+// returning the restored version
+// the restored version and the replaced version are swapped, so a rollback may itself be rolled back
+func RollbackPlugin(ctx context.Context, imageRef string) (*versionfile.InstalledVersion, error) {
+	displayRef := NewSteampipeImageRef(imageRef).DisplayImageRef()
+	rollbackDir := filepaths.PluginRollbackDir(displayRef)
+	if pluginBinary(rollbackDir) == "" {
+		return nil, error_helpers.NewPluginError(error_helpers.ErrorCodePluginRollbackNotFound, fmt.Errorf("there is no previous version of %s to roll back to", displayRef))
+	}
+	restored, err := versionfile.LoadInstalledVersion(rollbackDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the version of the previous installation of %s: %s", displayRef, err.Error())
+	}
+
+	// swap the installed plugin with the backup
+	pluginPath := filepaths.PluginInstallDir(displayRef)
+	swapDir := rollbackDir + ".swap"
+	if err := os.RemoveAll(swapDir); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(pluginPath); err == nil {
+		if err := os.Rename(pluginPath, swapDir); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(pluginPath), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(rollbackDir, pluginPath); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(swapDir); err == nil {
+		if err := os.Rename(swapDir, rollbackDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// update the modification time of the restored binary so the connections using the plugin are refreshed
+	now := time.Now()
+	if err := os.Chtimes(pluginBinary(pluginPath), now, now); err != nil {
+		log.Printf("[WARN] failed to update the modification time of %s: %s", pluginPath, err.Error())
+	}
+
+	versionFileUpdateLock.Lock()
+	defer versionFileUpdateLock.Unlock()
+	v, err := versionfile.LoadPluginVersionFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	restored.Name = displayRef
+	v.Plugins[displayRef] = restored
+	if err := v.Save(); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// pluginBinary returns the path of the plugin binary in a plugin directory, or an empty string if there is none
+func pluginBinary(pluginDir string) string {
+	entries, err := os.ReadDir(pluginDir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == constants.PluginExtension {
+			return filepath.Join(pluginDir, entry.Name())
+		}
+	}
+	return ""
+}
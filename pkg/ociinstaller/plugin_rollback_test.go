@@ -0,0 +1,84 @@
+package ociinstaller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+)
+
+func writeTestPlugin(t *testing.T, pluginDir, name, version string) {
+	t.Helper()
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "steampipe-plugin-aws.plugin"), []byte(version), 0755); err != nil {
+		t.Fatal(err)
+	}
+	versionJSON, _ := json.Marshal(&versionfile.InstalledVersion{Name: name, Version: version})
+	if err := os.WriteFile(filepath.Join(pluginDir, "version.json"), versionJSON, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPluginRollback(t *testing.T) {
+	setRollbackTestDir(t)
+	const ref = "hub.steampipe.io/plugins/turbot/aws@latest"
+	pluginPath := filepaths.EnsurePluginInstallDir(ref)
+	writeTestPlugin(t, pluginPath, ref, "0.92.0")
+
+	// an update backs up the installed version
+	restore, err := backupPlugin(ref, pluginPath)
+	if err != nil || restore == nil {
+		t.Fatalf("expected the installed plugin to be backed up: %v", err)
+	}
+	if pluginBinary(pluginPath) != "" {
+		t.Fatalf("expected the plugin directory to be empty after the backup")
+	}
+	writeTestPlugin(t, pluginPath, ref, "0.93.0")
+
+	restored, err := RollbackPlugin(context.Background(), "aws")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if restored.Version != "0.92.0" {
+		t.Errorf("expected version 0.92.0 to be restored, got %s", restored.Version)
+	}
+	if content, _ := os.ReadFile(pluginBinary(pluginPath)); string(content) != "0.92.0" {
+		t.Errorf("expected the binary of version 0.92.0 to be installed, got %s", content)
+	}
+	v, err := versionfile.LoadPluginVersionFile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Plugins[ref] == nil || v.Plugins[ref].Version != "0.92.0" {
+		t.Errorf("expected the version file to contain the restored version")
+	}
+
+	// the rollback can itself be rolled back
+	restored, err = RollbackPlugin(context.Background(), "aws")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if restored.Version != "0.93.0" {
+		t.Errorf("expected version 0.93.0 to be restored, got %s", restored.Version)
+	}
+}
+
+func TestPluginRollbackNoBackup(t *testing.T) {
+	setRollbackTestDir(t)
+	if _, err := RollbackPlugin(context.Background(), "aws"); err == nil {
+		t.Errorf("expected an error when there is no previous version")
+	}
+}
+
+// setRollbackTestDir uses a temporary install dir, restoring it once the test completes
+func setRollbackTestDir(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	t.Cleanup(func() { filepaths.SteampipeDir = prevSteampipeDir })
+}
@@ -48,6 +48,14 @@ type AssetsImage struct {
 	ReportUI string
 }
 
+// License returns the licenses of the image, from the image annotations (if set)
+func (i *SteampipeImage) License() string {
+	if i.OCIDescriptor == nil {
+		return ""
+	}
+	return i.OCIDescriptor.Annotations[ocispec.AnnotationLicenses]
+}
+
 func (o *ociDownloader) newSteampipeImage() *SteampipeImage {
 	SteampipeImage := &SteampipeImage{
 		resolver: &o.resolver,
@@ -0,0 +1,209 @@
+package ociinstaller
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/viper"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// cosign stores the signature of an image as a manifest in the same repository, tagged 'sha256-<digest>.sig'
+// each layer of the signature manifest is a 'simple signing' payload identifying the image,
+// with the signature of the payload as a layer annotation
+const (
+	cosignSignatureAnnotation    = "dev.cosignproject.cosign/signature"
+	cosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureTagSuffix     = ".sig"
+)
+
+// noVerifyKeyWarning ensures the warning that signatures are not verified is shown at most once
+var noVerifyKeyWarning sync.Once
+
+// simpleSigningPayload is the payload signed by cosign - it identifies the signed image by its manifest digest
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// imageVerifyMode returns the configured image verify mode
+// no verify key is embedded, so if 'verify' is not set, images are only verified if a verify key is configured
+// (in 'warn' mode) - otherwise verification is off
+func imageVerifyMode() string {
+	if mode := viper.GetString(constants.ArgImageVerify); mode != "" {
+		return mode
+	}
+	if viper.GetString(constants.ArgImageVerifyKey) != "" {
+		return constants.ImageVerifyWarn
+	}
+	return constants.ImageVerifyOff
+}
+
+// verifyImageSignature verifies the cosign signature of the image with the given manifest, using the configured keys
+// NOTE: the content of the image is verified against its manifest digest by oras when it is copied
+//
+// if verification fails, an error is returned if the verify mode is 'strict' - if it is 'warn' a warning is shown
+// (as it is if 'verify' is set to 'warn' but no verify key is configured, since the image is then installed unverified)
+func verifyImageSignature(ctx context.Context, source oras.ReadOnlyTarget, ref string, manifest ocispec.Descriptor) error {
+	mode := imageVerifyMode()
+	if mode == constants.ImageVerifyOff {
+		return nil
+	}
+	keyFile := viper.GetString(constants.ArgImageVerifyKey)
+	if keyFile == "" {
+		if mode == constants.ImageVerifyStrict {
+			return sperr.New("cannot verify the signature of %s - 'verify' is '%s' but no 'verify_key' is configured", ref, mode)
+		}
+		log.Printf("[WARN] not verifying the signature of %s - no verify key is configured", ref)
+		// warn once, rather than for every image installed
+		noVerifyKeyWarning.Do(func() {
+			error_helpers.ShowWarning("image signatures are not being verified as no 'verify_key' is configured - set 'verify_key' in the image options to verify them, or set 'verify' to 'off' to hide this warning")
+		})
+		return nil
+	}
+	keys, err := loadVerificationKeys(keyFile)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to load the image verification keys")
+	}
+
+	err = verifySignature(ctx, source, manifest, keys)
+	if err == nil {
+		log.Printf("[INFO] verified the signature of %s", ref)
+		return nil
+	}
+	if mode == constants.ImageVerifyStrict {
+		return sperr.WrapWithMessage(err, "signature verification of %s failed", ref)
+	}
+	log.Printf("[WARN] signature verification of %s failed: %s", ref, err.Error())
+	error_helpers.ShowWarning(fmt.Sprintf("signature verification of %s failed: %s", ref, err.Error()))
+	return nil
+}
+
+// verifySignature verifies that the source contains a signature of the manifest, made by one of the given keys
+func verifySignature(ctx context.Context, source oras.ReadOnlyTarget, manifest ocispec.Descriptor, keys []crypto.PublicKey) error {
+	manifestDigest := string(manifest.Digest)
+	signatureDescriptor, err := source.Resolve(ctx, signatureTag(manifestDigest))
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return fmt.Errorf("the image is not signed")
+		}
+		return err
+	}
+	signatureManifestJson, err := content.FetchAll(ctx, source, signatureDescriptor)
+	if err != nil {
+		return err
+	}
+	var signatureManifest ocispec.Manifest
+	if err := json.Unmarshal(signatureManifestJson, &signatureManifest); err != nil {
+		return err
+	}
+
+	for _, layer := range signatureManifest.Layers {
+		encodedSignature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if layer.MediaType != cosignSimpleSigningMediaType || !ok {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			log.Printf("[TRACE] ignoring signature with invalid encoding: %s", err.Error())
+			continue
+		}
+		payload, err := content.FetchAll(ctx, source, layer)
+		if err != nil {
+			return err
+		}
+		if !verifyPayload(payload, signature, keys) {
+			continue
+		}
+		// the signed payload must identify this image
+		var signed simpleSigningPayload
+		if err := json.Unmarshal(payload, &signed); err != nil {
+			log.Printf("[TRACE] ignoring signature with invalid payload: %s", err.Error())
+			continue
+		}
+		if signed.Critical.Image.DockerManifestDigest == manifestDigest {
+			return nil
+		}
+	}
+	return fmt.Errorf("the image has no signature which can be verified with the configured keys")
+}
+
+// signatureTag returns the tag of the cosign signature of the manifest with the given digest
+func signatureTag(manifestDigest string) string {
+	return strings.Replace(manifestDigest, ":", "-", 1) + cosignSignatureTagSuffix
+}
+
+// verifyPayload returns whether the signature of the payload was made by one of the keys
+func verifyPayload(payload, signature []byte, keys []crypto.PublicKey) bool {
+	payloadHash := sha256.Sum256(payload)
+	for _, key := range keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, payloadHash[:], signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, payloadHash[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadVerificationKeys loads the PEM encoded public keys from the given file
+func loadVerificationKeys(keyFile string) ([]crypto.PublicKey, error) {
+	keyFile, err := filehelpers.Tildefy(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, sperr.WrapWithMessage(err, "invalid public key in %s", keyFile)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s contains no PEM encoded public keys", keyFile)
+	}
+	return keys, nil
+}
@@ -0,0 +1,140 @@
+package ociinstaller
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestVerifySignature(t *testing.T) {
+	ctx := context.Background()
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create a store containing an image, optionally signed with the given key for the given manifest digest
+	createStore := func(key *ecdsa.PrivateKey, signedDigest string) (oras.ReadOnlyTarget, ocispec.Descriptor) {
+		store := memory.New()
+		manifest, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.turbot.steampipe.test", oras.PackManifestOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key == nil {
+			return store, manifest
+		}
+		if signedDigest == "" {
+			signedDigest = string(manifest.Digest)
+		}
+		payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"test"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, signedDigest))
+		payloadHash := sha256.Sum256(payload)
+		signature, err := ecdsa.SignASN1(rand.Reader, key, payloadHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		layer := content.NewDescriptorFromBytes(cosignSimpleSigningMediaType, payload)
+		layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)}
+		if err := store.Push(ctx, layer, bytes.NewReader(payload)); err != nil {
+			t.Fatal(err)
+		}
+		signatureManifest, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.dev.cosign.artifact.sig.v1+json", oras.PackManifestOptions{Layers: []ocispec.Descriptor{layer}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Tag(ctx, signatureManifest, signatureTag(string(manifest.Digest))); err != nil {
+			t.Fatal(err)
+		}
+		return store, manifest
+	}
+
+	keys := []crypto.PublicKey{&signingKey.PublicKey}
+	tests := map[string]struct {
+		key          *ecdsa.PrivateKey
+		signedDigest string
+		wantErr      bool
+	}{
+		"signed":           {key: signingKey},
+		"unsigned":         {wantErr: true},
+		"wrong key":        {key: otherKey, wantErr: true},
+		"different digest": {key: signingKey, signedDigest: "sha256:0000000000000000000000000000000000000000000000000000000000000000", wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, manifest := createStore(test.key, test.signedDigest)
+			err := verifySignature(ctx, store, manifest, keys)
+			if test.wantErr && err == nil {
+				t.Error("expected verification to fail")
+			}
+			if !test.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestVerifyImageSignatureWithoutKey(t *testing.T) {
+	defer viper.Reset()
+	viper.Set(constants.ArgImageVerifyKey, "")
+	ctx := context.Background()
+	store := memory.New()
+	ref := "ghcr.io/turbot/steampipe/plugins/turbot/aws:0.118.0"
+
+	// by default, verification is off if there is no key
+	viper.Set(constants.ArgImageVerify, "")
+	if err := verifyImageSignature(ctx, store, ref, ocispec.Descriptor{}); err != nil {
+		t.Errorf("unexpected error by default: %s", err.Error())
+	}
+	// the image is installed unverified (with a warning) unless verification is strict
+	viper.Set(constants.ArgImageVerify, constants.ImageVerifyWarn)
+	if err := verifyImageSignature(ctx, store, ref, ocispec.Descriptor{}); err != nil {
+		t.Errorf("unexpected error in warn mode: %s", err.Error())
+	}
+	viper.Set(constants.ArgImageVerify, constants.ImageVerifyStrict)
+	if err := verifyImageSignature(ctx, store, ref, ocispec.Descriptor{}); err == nil {
+		t.Error("expected an error in strict mode")
+	}
+}
+
+func TestImageVerifyMode(t *testing.T) {
+	defer viper.Reset()
+	tests := map[string]struct {
+		verify    string
+		verifyKey string
+		expected  string
+	}{
+		"default":          {expected: constants.ImageVerifyOff},
+		"default with key": {verifyKey: "steampipe.pub", expected: constants.ImageVerifyWarn},
+		"strict":           {verify: constants.ImageVerifyStrict, expected: constants.ImageVerifyStrict},
+		"warn without key": {verify: constants.ImageVerifyWarn, expected: constants.ImageVerifyWarn},
+		"off with key":     {verify: constants.ImageVerifyOff, verifyKey: "steampipe.pub", expected: constants.ImageVerifyOff},
+		"strict with key":  {verify: constants.ImageVerifyStrict, verifyKey: "steampipe.pub", expected: constants.ImageVerifyStrict},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			viper.Set(constants.ArgImageVerify, test.verify)
+			viper.Set(constants.ArgImageVerifyKey, test.verifyKey)
+			if got := imageVerifyMode(); got != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
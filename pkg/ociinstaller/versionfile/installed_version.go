@@ -8,6 +8,7 @@ type InstalledVersion struct {
 	ImageDigest        string `json:"image_digest,omitempty"`
 	BinaryDigest       string `json:"binary_digest,omitempty"`
 	BinaryArchitecture string `json:"binary_arch,omitempty"`
+	License            string `json:"license,omitempty"`
 	InstalledFrom      string `json:"installed_from,omitempty"`
 	LastCheckedDate    string `json:"last_checked_date,omitempty"`
 	InstallDate        string `json:"install_date,omitempty"`
@@ -14,12 +14,18 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/statestore"
 )
 
 var (
 	ErrNoContent = errors.New("no content")
 )
 
+// pluginVersionStore is the store the plugin version data is saved in
+// this is always the file store, whatever the state_storage database option - plugins are installed by each node,
+// and the plugin commands save the versions of the plugins they install in the install dir
+var pluginVersionStore statestore.Store = statestore.NewFileStore()
+
 const (
 	PluginStructVersion = 20220411
 	// the name of the version files that are put in the plugin installation directories
@@ -71,15 +77,14 @@ func (p *PluginVersionFile) EnsurePluginVersionFile(installData *InstalledVersio
 	return os.WriteFile(versionFile, theBytes, 0644)
 }
 
-// Save writes the config file to disk
+// Save writes the plugin version data to the plugin version store
 func (p *PluginVersionFile) Save() error {
 	// set struct version
 	p.StructVersion = PluginStructVersion
-	versionFilePath := filepaths.PluginVersionFilePath()
-	return p.write(versionFilePath)
+	return p.write(context.Background(), pluginVersionStore)
 }
 
-func (p *PluginVersionFile) write(path string) error {
+func (p *PluginVersionFile) write(ctx context.Context, store statestore.Store) error {
 	versionFileJSON, err := json.MarshalIndent(p, "", "  ")
 	if err != nil {
 		log.Println("[ERROR]", "Error while writing version file", err)
@@ -89,7 +94,7 @@ func (p *PluginVersionFile) write(path string) error {
 		log.Println("[ERROR]", "Cannot write 0 bytes to file")
 		return sperr.WrapWithMessage(ErrNoContent, "cannot write versions file")
 	}
-	return os.WriteFile(path, versionFileJSON, 0644)
+	return store.Save(ctx, statestore.KeyPluginVersions, versionFileJSON)
 }
 
 func (p *PluginVersionFile) ensureVersionFilesInPluginDirectories() error {
@@ -142,23 +147,19 @@ func LoadPluginVersionFile(ctx context.Context) (*PluginVersionFile, error) {
 	pluginLoadLock.Lock()
 	defer pluginLoadLock.Unlock()
 
-	versionFilePath := filepaths.PluginVersionFilePath()
-	if filehelpers.FileExists(versionFilePath) {
-		pluginVersions, err := readGlobalPluginVersionsFile(versionFilePath)
-
-		// we could read and parse out the file - all is well
-		if err == nil {
-			return pluginVersions, nil
-		}
+	pluginVersions, err := readGlobalPluginVersions(ctx, pluginVersionStore)
+	// we could read and parse out the saved data - all is well
+	if err == nil {
+		return pluginVersions, nil
 	}
 
 	// we don't have a global plugin/versions.json or it is not parseable or is empty (always recompose)
 	// generate the version file from the individual version files by walking the plugin directories
 	// this will return an Empty Version file if there are no version files in the plugin directories
-	pluginVersions := recomposePluginVersionFile(ctx)
+	pluginVersions = recomposePluginVersionFile(ctx)
 
 	// save the recomposed file
-	err := pluginVersions.Save()
+	err = pluginVersions.Save()
 	if err != nil {
 		return nil, err
 	}
@@ -240,6 +241,11 @@ func recomposePluginVersionFile(ctx context.Context) *PluginVersionFile {
 	return pvf
 }
 
+// LoadInstalledVersion loads the version file in a plugin folder
+func LoadInstalledVersion(pluginFolder string) (*InstalledVersion, error) {
+	return readPluginVersionFile(filepath.Join(pluginFolder, pluginVersionFileName))
+}
+
 func readPluginVersionFile(versionFile string) (*InstalledVersion, error) {
 	data, err := os.ReadFile(versionFile)
 	if err != nil {
@@ -255,13 +261,13 @@ func readPluginVersionFile(versionFile string) (*InstalledVersion, error) {
 	return install, nil
 }
 
-func readGlobalPluginVersionsFile(path string) (*PluginVersionFile, error) {
-	file, err := os.ReadFile(path)
+func readGlobalPluginVersions(ctx context.Context, store statestore.Store) (*PluginVersionFile, error) {
+	file, err := store.Load(ctx, statestore.KeyPluginVersions)
 	if err != nil {
 		return nil, err
 	}
 	if len(file) == 0 {
-		// the file exists, but is empty - return an error
+		// nothing is saved, or the file exists but is empty - return an error
 		// start from scratch
 		return nil, sperr.New("plugin versions.json file is empty")
 	}
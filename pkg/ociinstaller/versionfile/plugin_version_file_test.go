@@ -1,16 +1,33 @@
 package versionfile
 
 import (
-	"os"
+	"context"
 	"testing"
 	"time"
 )
 
+// memoryStore is a state store which saves the state in memory
+type memoryStore map[string][]byte
+
+func (s memoryStore) Load(_ context.Context, key string) ([]byte, error) {
+	return s[key], nil
+}
+
+func (s memoryStore) Save(_ context.Context, key string, content []byte) error {
+	s[key] = content
+	return nil
+}
+
+func (s memoryStore) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
 func TestWrite(t *testing.T) {
 
 	var v PluginVersionFile
 
-	fileName := "test.json"
+	store := memoryStore{}
 	timeNow := time.Now()
 	timeNow2 := timeNow.Add(time.Minute * 10)
 	v.Plugins = make(map[string]*(InstalledVersion))
@@ -35,18 +52,19 @@ func TestWrite(t *testing.T) {
 		InstallDate:     timeNow2.Format(time.UnixDate),
 	}
 	v.Plugins[googlePlugin.Name] = &googlePlugin
-	if err := v.write(fileName); err != nil {
+	if err := v.write(context.Background(), store); err != nil {
 		t.Errorf("\nError writing file: %s", err.Error())
 	}
-	v2, err := readGlobalPluginVersionsFile(fileName)
+	v2, err := readGlobalPluginVersions(context.Background(), store)
 	if err != nil {
-		t.Errorf("\nError reading file: %s", err.Error())
+		t.Fatalf("\nError reading file: %s", err.Error())
 	}
 
 	if len(v2.Plugins) != 2 {
 		t.Errorf("\nexpected 2 plugins, found %d", len(v2.Plugins))
 	}
 
-	os.Remove(fileName)
-
+	if _, err := readGlobalPluginVersions(context.Background(), memoryStore{}); err == nil {
+		t.Errorf("\nexpected an error reading when nothing is saved")
+	}
 }
@@ -0,0 +1,77 @@
+package onboarding
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ConfigFileName is the name of the config file the generated connections are written to
+const ConfigFileName = "init" + constants.ConfigExtension
+
+// smokeTestTables is the table queried for each plugin to verify a new connection works
+var smokeTestTables = map[string]string{
+	"aws":   "aws_account",
+	"gcp":   "gcp_project",
+	"azure": "azure_subscription",
+}
+
+// GenerateConnectionConfig returns the HCL connection blocks for the given credentials
+func GenerateConnectionConfig(credentials []DetectedCredential) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for i, c := range credentials {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("connection", []string{c.ConnectionName})
+		blockBody := block.Body()
+		blockBody.SetAttributeValue("plugin", cty.StringVal(c.Plugin))
+
+		// write the attributes in a consistent order
+		var keys []string
+		for k := range c.Config {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			blockBody.SetAttributeValue(k, cty.StringVal(c.Config[k]))
+		}
+	}
+	return hclwrite.Format(f.Bytes())
+}
+
+// WriteConnectionConfig appends the connection blocks for the given credentials to the onboarding config file
+// returns the path of the config file
+func WriteConnectionConfig(credentials []DetectedCredential) (string, error) {
+	configPath := filepath.Join(filepaths.EnsureConfigDir(), ConfigFileName)
+
+	content := GenerateConnectionConfig(credentials)
+	existing, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if len(existing) > 0 {
+		content = append(append(existing, '\n'), content...)
+	}
+
+	if err := os.WriteFile(configPath, content, 0600); err != nil {
+		return "", err
+	}
+	return configPath, nil
+}
+
+// SmokeTestQuery returns a query which verifies the connection for the given credentials works
+func SmokeTestQuery(c DetectedCredential) string {
+	table, ok := smokeTestTables[c.Plugin]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("select count(*) from %s.%s", c.ConnectionName, table)
+}
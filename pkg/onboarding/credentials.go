@@ -0,0 +1,262 @@
+package onboarding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DetectedCredential is a set of cloud credentials found on this machine, which can be used by a plugin connection
+type DetectedCredential struct {
+	// the short name of the plugin which uses the credentials, e.g. aws
+	Plugin string
+	// the suggested connection name
+	ConnectionName string
+	// a description of where the credentials were found
+	Source string
+	// the connection config attributes (in addition to 'plugin')
+	Config map[string]string
+}
+
+// DetectCredentials looks for AWS profiles, gcloud configuration and Azure CLI subscriptions
+func DetectCredentials() []DetectedCredential {
+	var res []DetectedCredential
+	res = append(res, detectAwsCredentials()...)
+	res = append(res, detectGcpCredentials()...)
+	res = append(res, detectAzureCredentials()...)
+	return res
+}
+
+// SuggestedPlugins returns the distinct plugins used by the credentials
+func SuggestedPlugins(credentials []DetectedCredential) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, c := range credentials {
+		if !seen[c.Plugin] {
+			seen[c.Plugin] = true
+			res = append(res, c.Plugin)
+		}
+	}
+	return res
+}
+
+func detectAwsCredentials() []DetectedCredential {
+	credentialsPath := envOrHomePath("AWS_SHARED_CREDENTIALS_FILE", ".aws", "credentials")
+	configPath := envOrHomePath("AWS_CONFIG_FILE", ".aws", "config")
+	profiles := parseAwsProfiles(readFileIfExists(credentialsPath), readFileIfExists(configPath))
+
+	var res []DetectedCredential
+	for _, profile := range profiles {
+		connectionName := "aws"
+		if profile != "default" {
+			connectionName = ConnectionName("aws", profile)
+		}
+		res = append(res, DetectedCredential{
+			Plugin:         "aws",
+			ConnectionName: connectionName,
+			Source:         fmt.Sprintf("AWS profile '%s'", profile),
+			Config:         map[string]string{"profile": profile},
+		})
+	}
+	// if there are no profiles, but credentials are set in the environment, use those
+	if len(res) == 0 && os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		res = append(res, DetectedCredential{
+			Plugin:         "aws",
+			ConnectionName: "aws",
+			Source:         "AWS_ACCESS_KEY_ID environment variable",
+			Config:         map[string]string{},
+		})
+	}
+	return res
+}
+
+var awsProfileSectionRegex = regexp.MustCompile(`^\[\s*(?:profile\s+)?([^\]\s]+)\s*\]$`)
+
+// parseAwsProfiles returns the distinct profile names from the content of the AWS credentials and config files
+// (in the config file, sections other than 'default' are prefixed with 'profile ')
+func parseAwsProfiles(credentialsContent, configContent []byte) []string {
+	profileMap := map[string]bool{}
+	for _, content := range [][]byte{credentialsContent, configContent} {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			// ignore sections which are not profiles, e.g. [sso-session name] or [services name]
+			if strings.HasPrefix(line, "[sso-session ") || strings.HasPrefix(line, "[services ") {
+				continue
+			}
+			if match := awsProfileSectionRegex.FindStringSubmatch(line); match != nil {
+				profileMap[match[1]] = true
+			}
+		}
+	}
+
+	var profiles []string
+	for profile := range profileMap {
+		profiles = append(profiles, profile)
+	}
+	// order with default first
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i] == "default" || profiles[j] == "default" {
+			return profiles[i] == "default"
+		}
+		return profiles[i] < profiles[j]
+	})
+	return profiles
+}
+
+func detectGcpCredentials() []DetectedCredential {
+	project := os.Getenv("CLOUDSDK_CORE_PROJECT")
+	if project == "" {
+		gcloudDir := os.Getenv("CLOUDSDK_CONFIG")
+		if gcloudDir == "" {
+			gcloudDir = homePath(".config", "gcloud")
+		}
+		activeConfig := strings.TrimSpace(string(readFileIfExists(filepath.Join(gcloudDir, "active_config"))))
+		if activeConfig == "" {
+			activeConfig = "default"
+		}
+		project = parseGcloudProject(readFileIfExists(filepath.Join(gcloudDir, "configurations", "config_"+activeConfig)))
+	}
+	if project == "" {
+		return nil
+	}
+	return []DetectedCredential{{
+		Plugin:         "gcp",
+		ConnectionName: "gcp",
+		Source:         fmt.Sprintf("gcloud project '%s'", project),
+		Config:         map[string]string{"project": project},
+	}}
+}
+
+// parseGcloudProject returns the project set in the [core] section of a gcloud configuration file
+func parseGcloudProject(content []byte) string {
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != "core" {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "project" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func detectAzureCredentials() []DetectedCredential {
+	azureDir := os.Getenv("AZURE_CONFIG_DIR")
+	if azureDir == "" {
+		azureDir = homePath(".azure")
+	}
+	subscriptionID := os.Getenv("AZURE_SUBSCRIPTION_ID")
+	if subscriptionID == "" {
+		subscriptionID = parseAzureDefaultSubscription(readFileIfExists(filepath.Join(azureDir, "azureProfile.json")))
+	}
+	if subscriptionID == "" {
+		return nil
+	}
+	return []DetectedCredential{{
+		Plugin:         "azure",
+		ConnectionName: "azure",
+		Source:         fmt.Sprintf("Azure subscription '%s'", subscriptionID),
+		Config:         map[string]string{"subscription_id": subscriptionID},
+	}}
+}
+
+// parseAzureDefaultSubscription returns the id of the default subscription from the content of the Azure CLI profile
+func parseAzureDefaultSubscription(content []byte) string {
+	// the Azure CLI writes the profile with a UTF-8 byte order mark
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if len(content) == 0 {
+		return ""
+	}
+	var profile struct {
+		Subscriptions []struct {
+			ID        string `json:"id"`
+			IsDefault bool   `json:"isDefault"`
+		} `json:"subscriptions"`
+	}
+	if err := json.Unmarshal(content, &profile); err != nil {
+		log.Printf("[TRACE] failed to parse Azure profile: %s", err)
+		return ""
+	}
+	for _, s := range profile.Subscriptions {
+		if s.IsDefault {
+			return s.ID
+		}
+	}
+	return ""
+}
+
+var invalidConnectionNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// ConnectionName builds a valid connection name from the plugin name and a suffix (e.g. a profile name)
+func ConnectionName(plugin, suffix string) string {
+	suffix = strings.Trim(invalidConnectionNameChars.ReplaceAllString(strings.ToLower(suffix), "_"), "_")
+	if suffix == "" {
+		return plugin
+	}
+	return fmt.Sprintf("%s_%s", plugin, suffix)
+}
+
+// ValidateConnectionNames returns an error if the connection names of any of the credentials collide
+// (different profile names may sanitise to the same connection name, e.g. 'prod-admin' and 'prod_admin')
+func ValidateConnectionNames(credentials []DetectedCredential) error {
+	sources := make(map[string][]string)
+	var connectionNames []string
+	for _, c := range credentials {
+		if _, ok := sources[c.ConnectionName]; !ok {
+			connectionNames = append(connectionNames, c.ConnectionName)
+		}
+		sources[c.ConnectionName] = append(sources[c.ConnectionName], c.Source)
+	}
+
+	var collisions []string
+	for _, connectionName := range connectionNames {
+		if s := sources[connectionName]; len(s) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%s map to connection name '%s'", strings.Join(s, ", "), connectionName))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("connection names collide - rename the profiles so they are distinct:\n  %s", strings.Join(collisions, "\n  "))
+	}
+	return nil
+}
+
+func envOrHomePath(envVar string, elem ...string) string {
+	if path := os.Getenv(envVar); path != "" {
+		return path
+	}
+	return homePath(elem...)
+}
+
+func homePath(elem ...string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, elem...)...)
+}
+
+func readFileIfExists(path string) []byte {
+	if path == "" {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return content
+}
@@ -0,0 +1,107 @@
+package onboarding
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseAwsProfiles(t *testing.T) {
+	credentials := `
+[dev]
+aws_access_key_id = abc
+aws_secret_access_key = def
+
+[default]
+aws_access_key_id = ghi
+`
+	config := `
+[default]
+region = us-east-1
+
+[profile prod-admin]
+sso_session = corp
+
+[sso-session corp]
+sso_region = us-east-1
+
+[profile dev]
+region = eu-west-1
+`
+	expected := []string{"default", "dev", "prod-admin"}
+	if res := parseAwsProfiles([]byte(credentials), []byte(config)); !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected %v, got %v", expected, res)
+	}
+	if res := parseAwsProfiles(nil, nil); len(res) != 0 {
+		t.Errorf("expected no profiles, got %v", res)
+	}
+}
+
+func TestParseGcloudProject(t *testing.T) {
+	content := `
+[compute]
+project = not-this-one
+
+[core]
+account = user@example.com
+project = my-project
+`
+	if res := parseGcloudProject([]byte(content)); res != "my-project" {
+		t.Errorf("expected my-project, got %s", res)
+	}
+}
+
+func TestParseAzureDefaultSubscription(t *testing.T) {
+	content := "\xef\xbb\xbf" + `{"subscriptions": [{"id": "sub-1", "isDefault": false}, {"id": "sub-2", "isDefault": true}]}`
+	if res := parseAzureDefaultSubscription([]byte(content)); res != "sub-2" {
+		t.Errorf("expected sub-2, got %s", res)
+	}
+	if res := parseAzureDefaultSubscription([]byte("not json")); res != "" {
+		t.Errorf("expected no subscription, got %s", res)
+	}
+}
+
+func TestConnectionName(t *testing.T) {
+	tests := map[string]string{
+		"prod-admin":   "aws_prod_admin",
+		"Dev.Account":  "aws_dev_account",
+		"--":           "aws",
+		"team_a@corp ": "aws_team_a_corp",
+	}
+	for suffix, expected := range tests {
+		if res := ConnectionName("aws", suffix); res != expected {
+			t.Errorf("%s: expected %s, got %s", suffix, expected, res)
+		}
+	}
+}
+
+func TestValidateConnectionNames(t *testing.T) {
+	newAwsCredential := func(profile string) DetectedCredential {
+		return DetectedCredential{Plugin: "aws", ConnectionName: ConnectionName("aws", profile), Source: fmt.Sprintf("AWS profile '%s'", profile)}
+	}
+	tests := map[string]struct {
+		credentials []DetectedCredential
+		err         bool
+	}{
+		"distinct names": {
+			credentials: []DetectedCredential{newAwsCredential("dev"), newAwsCredential("prod-admin")},
+		},
+		"sanitised names collide": {
+			credentials: []DetectedCredential{newAwsCredential("dev"), newAwsCredential("prod-admin"), newAwsCredential("prod_admin")},
+			err:         true,
+		},
+		"case collides": {
+			credentials: []DetectedCredential{newAwsCredential("Dev"), newAwsCredential("dev")},
+			err:         true,
+		},
+		"no credentials": {},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateConnectionNames(test.credentials)
+			if test.err != (err != nil) {
+				t.Errorf("expected error: %v, got %v", test.err, err)
+			}
+		})
+	}
+}
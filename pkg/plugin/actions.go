@@ -10,6 +10,7 @@ import (
 
 	"github.com/turbot/go-kit/files"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/actionplan"
 	"github.com/turbot/steampipe/pkg/filepaths"
 	"github.com/turbot/steampipe/pkg/ociinstaller"
 	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
@@ -38,6 +39,10 @@ func Remove(ctx context.Context, image string, pluginConnections map[string][]*m
 	if err != nil {
 		return nil, err
 	}
+	// remove any previous version kept for rollback
+	if err := os.RemoveAll(filepaths.PluginRollbackDir(fullPluginName)); err != nil {
+		log.Printf("[WARN] failed to remove the rollback directory of %s: %s", fullPluginName, err.Error())
+	}
 
 	// update the version file
 	v, err := versionfile.LoadPluginVersionFile(ctx)
@@ -50,6 +55,27 @@ func Remove(ctx context.Context, image string, pluginConnections map[string][]*m
 	return &steampipeconfig.PluginRemoveReport{Connections: conns, Image: imageRef}, err
 }
 
+// PlanRemove adds the actions Remove would take for an installed plugin to the plan
+// the schemas of the connections using the plugin are dropped by the next connection refresh
+func PlanRemove(plan *actionplan.Plan, image string, pluginConnections map[string][]*modconfig.Connection) error {
+	imageRef := ociinstaller.NewSteampipeImageRef(image)
+	fullPluginName := imageRef.DisplayImageRef()
+
+	installedTo := filepath.Join(filepaths.EnsurePluginDir(), filepath.FromSlash(fullPluginName))
+	if _, err := os.Stat(installedTo); os.IsNotExist(err) {
+		return fmt.Errorf("plugin '%s' not found", image)
+	}
+	plan.Add(actionplan.ActionDeletePath, installedTo, fmt.Sprintf("installation of %s", fullPluginName))
+	if rollbackDir := filepaths.PluginRollbackDir(fullPluginName); files.DirectoryExists(rollbackDir) {
+		plan.Add(actionplan.ActionDeletePath, rollbackDir, "previous version kept for rollback")
+	}
+	plan.Add(actionplan.ActionUpdateFile, filepaths.PluginVersionFilePath(), fmt.Sprintf("remove %s", fullPluginName))
+	for _, c := range pluginConnections[fullPluginName] {
+		plan.Add(actionplan.ActionDropSchema, c.Name, "connection uses the plugin - dropped when connections are next refreshed")
+	}
+	return nil
+}
+
 // Exists looks up the version file and reports whether a plugin is already installed
 func Exists(ctx context.Context, plugin string) (bool, error) {
 	versionData, err := versionfile.LoadPluginVersionFile(ctx)
@@ -71,6 +97,12 @@ func Install(ctx context.Context, plugin ResolvedPluginVersion, sub chan struct{
 	return image, err
 }
 
+// Rollback restores the version of a plugin which was installed before the plugin was last updated
+func Rollback(ctx context.Context, image string) (*versionfile.InstalledVersion, error) {
+	statushooks.SetStatus(ctx, fmt.Sprintf("Rolling back plugin %s", image))
+	return ociinstaller.RollbackPlugin(ctx, image)
+}
+
 // PluginListItem is a struct representing an item in the list of plugins
 type PluginListItem struct {
 	Name        string
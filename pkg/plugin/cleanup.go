@@ -3,34 +3,14 @@ package plugin
 import (
 	"context"
 	"log"
-	"os"
-	"time"
 
-	"github.com/turbot/go-kit/files"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/gc"
 )
 
+// CleanupOldTmpDirs removes plugin install temp dirs which are older than the configured retention period
 func CleanupOldTmpDirs(ctx context.Context) {
-	const tmpDirAgeThreshold = 24 * time.Hour
-	tmpDirs, err := files.ListFilesWithContext(ctx, filepaths.EnsurePluginDir(), &files.ListOptions{
-		Include: []string{"tmp-*"},
-		Flags:   files.DirectoriesRecursive,
-	})
-	if err != nil {
-		log.Printf("[TRACE] Error while globbing for tmp dirs in plugin dir: %s", err)
-		return
-	}
-
-	for _, tmpDir := range tmpDirs {
-		stat, err := os.Stat(tmpDir)
-		if err != nil {
-			log.Printf("[TRACE] Error while stating tmp dir %s: %s", tmpDir, err)
-			continue
-		}
-		if time.Since(stat.ModTime()) > tmpDirAgeThreshold {
-			if err := os.RemoveAll(tmpDir); err != nil {
-				log.Printf("[TRACE] Error while removing old tmp dir %s: %s", tmpDir, err)
-			}
-		}
+	if _, err := gc.RemoveOldTempDirs(ctx, filepaths.EnsurePluginDir(), true, gc.PolicyFromConfig().TempDirRetention); err != nil {
+		log.Printf("[TRACE] Error while removing old tmp dirs in plugin dir: %s", err)
 	}
 }
@@ -0,0 +1,245 @@
+package plugin
+
+import (
+	"context"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/version"
+)
+
+const sdkModulePath = "github.com/turbot/steampipe-plugin-sdk"
+
+// MinimumSdkVersion is the oldest plugin sdk version supported by this CLI version
+var MinimumSdkVersion = semver.MustParse("4.0.0")
+
+// maximumSdkMajorVersion is the newest plugin sdk major version supported by this CLI version
+const maximumSdkMajorVersion = 5
+
+// sdkMinimumCliVersions is the first CLI version which supports plugins built with each sdk major version
+var sdkMinimumCliVersions = map[uint64]*semver.Version{
+	5: semver.MustParse("0.18.0"),
+}
+
+// PluginCompatibility is the result of checking whether an installed plugin is compatible with this CLI version
+type PluginCompatibility struct {
+	Plugin     string `json:"plugin"`
+	Version    string `json:"version"`
+	SdkVersion string `json:"sdk_version,omitempty"`
+	Compatible bool   `json:"compatible"`
+	// true if the plugin requires a newer CLI version, false if the plugin must be updated
+	TooNew bool `json:"too_new,omitempty"`
+	// the minimum CLI version which supports the plugin - empty if the plugin is too old, or this is not known
+	MinimumCliVersion string `json:"minimum_cli_version,omitempty"`
+	// the minimum sdk version the plugin must be built with - set if the plugin is too old
+	MinimumSdkVersion string `json:"minimum_sdk_version,omitempty"`
+	// the plugin version to update to - set if the plugin is too old and the latest version has been resolved
+	UpdateVersion string `json:"update_version,omitempty"`
+}
+
+// Fix returns a description of how to resolve the incompatibility
+func (c PluginCompatibility) Fix() string {
+	switch {
+	case c.Compatible:
+		return ""
+	case c.TooNew && c.MinimumCliVersion != "":
+		return fmt.Sprintf("upgrade Steampipe to v%s or later", c.MinimumCliVersion)
+	case c.TooNew:
+		return "upgrade Steampipe to the latest version"
+	case c.UpdateVersion != "":
+		return fmt.Sprintf("update the plugin to v%s using 'steampipe plugin update %s'", c.UpdateVersion, c.ShortName())
+	default:
+		return fmt.Sprintf("update the plugin to a version built with sdk v%s or later using 'steampipe plugin update %s'", c.MinimumSdkVersion, c.ShortName())
+	}
+}
+
+// ShortName returns the condensed plugin name, as used with the plugin commands
+func (c PluginCompatibility) ShortName() string {
+	return ociinstaller.NewSteampipeImageRef(c.Plugin).GetFriendlyName()
+}
+
+// UsesPreV5Sdk returns whether the plugin may be built with an sdk version earlier than v5
+// (these plugins do not support disabling the cache from the server)
+// if the sdk version cannot be determined, the plugin is assumed to use a pre-v5 sdk, so the cache is still forced off
+func (c PluginCompatibility) UsesPreV5Sdk() bool {
+	sdkVersion, err := semver.NewVersion(c.SdkVersion)
+	if err != nil {
+		return true
+	}
+	return sdkVersion.Major() < 5
+}
+
+func (c PluginCompatibility) String() string {
+	if c.Compatible {
+		return fmt.Sprintf("%s is compatible", c.ShortName())
+	}
+	if c.TooNew {
+		return fmt.Sprintf("%s uses sdk v%s, which requires a newer version of Steampipe - %s", c.ShortName(), c.SdkVersion, c.Fix())
+	}
+	return fmt.Sprintf("%s uses sdk v%s, which is no longer supported - %s", c.ShortName(), c.SdkVersion, c.Fix())
+}
+
+// GetPluginCompatibility determines the sdk version of each installed plugin by reading the go build info
+// embedded in the plugin binary, and returns whether each plugin is compatible with this CLI version
+// (plugins whose sdk version cannot be determined are assumed to be compatible)
+func GetPluginCompatibility(ctx context.Context) ([]PluginCompatibility, error) {
+	pluginVersions, err := versionfile.LoadPluginVersionFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// reading the build info of every plugin binary is slow - so cache the sdk version by binary digest
+	sdkVersions := loadSdkVersionCache()
+	cacheUpdated := false
+
+	var res []PluginCompatibility
+	for imageRef, installed := range pluginVersions.Plugins {
+		c := PluginCompatibility{
+			Plugin:     imageRef,
+			Version:    installed.Version,
+			Compatible: true,
+		}
+
+		cached, ok := sdkVersions[imageRef]
+		if !ok || cached.BinaryDigest == "" || cached.BinaryDigest != installed.BinaryDigest {
+			cached = sdkVersionCacheEntry{BinaryDigest: installed.BinaryDigest, SdkVersion: readPluginSdkVersion(imageRef)}
+			sdkVersions[imageRef] = cached
+			cacheUpdated = true
+		}
+
+		if sdkVersion, err := semver.NewVersion(cached.SdkVersion); err == nil {
+			c = checkSdkVersion(c, sdkVersion, version.SteampipeVersion)
+		}
+		res = append(res, c)
+	}
+
+	if cacheUpdated {
+		saveSdkVersionCache(sdkVersions)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Plugin < res[j].Plugin })
+	return res, nil
+}
+
+// GetIncompatiblePlugins returns the installed plugins which are not compatible with this CLI version
+func GetIncompatiblePlugins(ctx context.Context) ([]PluginCompatibility, error) {
+	compatibility, err := GetPluginCompatibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []PluginCompatibility
+	for _, c := range compatibility {
+		if !c.Compatible {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// checkSdkVersion checks whether a plugin built with the given sdk version can be used with the given CLI version
+func checkSdkVersion(c PluginCompatibility, sdkVersion, cliVersion *semver.Version) PluginCompatibility {
+	c.SdkVersion = sdkVersion.String()
+	c.Compatible = true
+
+	if sdkVersion.LessThan(MinimumSdkVersion) {
+		c.Compatible = false
+		c.MinimumSdkVersion = MinimumSdkVersion.String()
+		return c
+	}
+
+	minimumCliVersion, knownMajorVersion := sdkMinimumCliVersions[sdkVersion.Major()]
+	if sdkVersion.Major() > maximumSdkMajorVersion || (knownMajorVersion && cliVersion.LessThan(minimumCliVersion)) {
+		c.Compatible = false
+		c.TooNew = true
+		if knownMajorVersion {
+			c.MinimumCliVersion = minimumCliVersion.String()
+		}
+	}
+	return c
+}
+
+// readPluginSdkVersion returns the sdk version the plugin binary was built with, or an empty string if it cannot be determined
+func readPluginSdkVersion(imageRef string) string {
+	binaryPath, err := filepaths.GetPluginPath(imageRef, imageRef)
+	if err != nil {
+		log.Printf("[TRACE] could not find binary for plugin %s: %s", imageRef, err)
+		return ""
+	}
+	info, err := buildinfo.ReadFile(binaryPath)
+	if err != nil {
+		log.Printf("[TRACE] could not read build info for plugin %s: %s", imageRef, err)
+		return ""
+	}
+	sdkVersion, ok := SdkVersionFromBuildInfo(info)
+	if !ok {
+		return ""
+	}
+	return sdkVersion.String()
+}
+
+// SdkVersionFromBuildInfo returns the version of the plugin sdk module a binary was built with
+func SdkVersionFromBuildInfo(info *debug.BuildInfo) (*semver.Version, bool) {
+	for _, dep := range info.Deps {
+		// the module path has a major version suffix for v2 onwards, e.g. github.com/turbot/steampipe-plugin-sdk/v5
+		if dep.Path != sdkModulePath && !strings.HasPrefix(dep.Path, sdkModulePath+"/v") {
+			continue
+		}
+		module := dep
+		// respect any replace directive
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			module = dep.Replace
+		}
+		v, err := semver.NewVersion(module.Version)
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+type sdkVersionCacheEntry struct {
+	BinaryDigest string `json:"binary_digest"`
+	SdkVersion   string `json:"sdk_version"`
+}
+
+func loadSdkVersionCache() map[string]sdkVersionCacheEntry {
+	res := make(map[string]sdkVersionCacheEntry)
+	path := filepaths.PluginSdkVersionsFilePath()
+	if !filehelpers.FileExists(path) {
+		return res
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[WARN] failed to read plugin sdk version cache: %s", err)
+		return res
+	}
+	if err := json.Unmarshal(content, &res); err != nil {
+		// the cache will be rebuilt
+		log.Printf("[WARN] failed to parse plugin sdk version cache: %s", err)
+		return make(map[string]sdkVersionCacheEntry)
+	}
+	return res
+}
+
+func saveSdkVersionCache(cache map[string]sdkVersionCacheEntry) {
+	content, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		log.Printf("[WARN] failed to serialise plugin sdk version cache: %s", err)
+		return
+	}
+	if err := os.WriteFile(filepaths.PluginSdkVersionsFilePath(), content, 0644); err != nil {
+		log.Printf("[WARN] failed to write plugin sdk version cache: %s", err)
+	}
+}
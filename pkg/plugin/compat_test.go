@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestSdkVersionFromBuildInfo(t *testing.T) {
+	tests := map[string]struct {
+		deps     []*debug.Module
+		expected string
+	}{
+		"v5": {
+			deps:     []*debug.Module{{Path: "github.com/turbot/go-kit", Version: "v0.9.0"}, {Path: "github.com/turbot/steampipe-plugin-sdk/v5", Version: "v5.8.0"}},
+			expected: "5.8.0",
+		},
+		"v1": {
+			deps:     []*debug.Module{{Path: "github.com/turbot/steampipe-plugin-sdk", Version: "v1.8.3"}},
+			expected: "1.8.3",
+		},
+		"replaced": {
+			deps:     []*debug.Module{{Path: "github.com/turbot/steampipe-plugin-sdk/v5", Version: "v5.8.0", Replace: &debug.Module{Path: "github.com/fork/steampipe-plugin-sdk/v5", Version: "v5.9.1"}}},
+			expected: "5.9.1",
+		},
+		"similar module name": {
+			deps: []*debug.Module{{Path: "github.com/turbot/steampipe-plugin-sdk-extra", Version: "v5.8.0"}},
+		},
+		"no sdk": {
+			deps: []*debug.Module{{Path: "github.com/turbot/go-kit", Version: "v0.9.0"}},
+		},
+	}
+
+	for name, test := range tests {
+		v, ok := SdkVersionFromBuildInfo(&debug.BuildInfo{Deps: test.deps})
+		if test.expected == "" {
+			if ok {
+				t.Errorf("%s: expected no sdk version, got %s", name, v)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: expected sdk version %s, got none", name, test.expected)
+			continue
+		}
+		if v.String() != test.expected {
+			t.Errorf("%s: expected sdk version %s, got %s", name, test.expected, v)
+		}
+	}
+}
+
+func TestCheckSdkVersion(t *testing.T) {
+	tests := map[string]struct {
+		sdkVersion        string
+		cliVersion        string
+		compatible        bool
+		tooNew            bool
+		minimumCliVersion string
+		minimumSdkVersion string
+	}{
+		"v5":                 {sdkVersion: "5.8.0", cliVersion: "0.23.2", compatible: true},
+		"v4":                 {sdkVersion: "4.1.0", cliVersion: "0.23.2", compatible: true},
+		"v3":                 {sdkVersion: "3.3.2", cliVersion: "0.23.2", minimumSdkVersion: "4.0.0"},
+		"v6":                 {sdkVersion: "6.0.0", cliVersion: "0.23.2", tooNew: true},
+		"v5 with old cli":    {sdkVersion: "5.0.0", cliVersion: "0.17.4", tooNew: true, minimumCliVersion: "0.18.0"},
+		"v5 with min cli":    {sdkVersion: "5.0.0", cliVersion: "0.18.0", compatible: true},
+		"v5 with prerelease": {sdkVersion: "5.0.0", cliVersion: "0.24.0-rc.1", compatible: true},
+	}
+
+	for name, test := range tests {
+		c := checkSdkVersion(PluginCompatibility{Plugin: "hub.steampipe.io/plugins/turbot/aws@latest"}, semver.MustParse(test.sdkVersion), semver.MustParse(test.cliVersion))
+		if c.Compatible != test.compatible || c.TooNew != test.tooNew {
+			t.Errorf("%s: expected compatible=%v tooNew=%v, got compatible=%v tooNew=%v", name, test.compatible, test.tooNew, c.Compatible, c.TooNew)
+		}
+		if c.MinimumCliVersion != test.minimumCliVersion {
+			t.Errorf("%s: expected minimum cli version '%s', got '%s'", name, test.minimumCliVersion, c.MinimumCliVersion)
+		}
+		if c.MinimumSdkVersion != test.minimumSdkVersion {
+			t.Errorf("%s: expected minimum sdk version '%s', got '%s'", name, test.minimumSdkVersion, c.MinimumSdkVersion)
+		}
+		if !c.Compatible && c.Fix() == "" {
+			t.Errorf("%s: expected a fix for an incompatible plugin", name)
+		}
+	}
+}
+
+func TestUsesPreV5Sdk(t *testing.T) {
+	tests := map[string]bool{
+		"5.8.0":   false,
+		"6.0.0":   false,
+		"4.1.0":   true,
+		"3.3.2":   true,
+		"":        true,
+		"unknown": true,
+	}
+	for sdkVersion, expected := range tests {
+		if got := (PluginCompatibility{SdkVersion: sdkVersion}).UsesPreV5Sdk(); got != expected {
+			t.Errorf("UsesPreV5Sdk for sdk version '%s': expected %v, got %v", sdkVersion, expected, got)
+		}
+	}
+}
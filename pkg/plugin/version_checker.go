@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -45,12 +46,15 @@ func (vr *VersionCheckReport) ShortNameWithConstraint() string {
 type VersionChecker struct {
 	pluginsToCheck []*versionfile.InstalledVersion
 	signature      string
+	// the standard request payload fields which are not sent (see utils.RedactedRequestPayloadFields)
+	redacted []string
 }
 
 // GetUpdateReport looks up and reports the updated version of selective turbot plugins which are listed in versions.json
-func GetUpdateReport(ctx context.Context, installationID string, check []*versionfile.InstalledVersion) map[string]VersionCheckReport {
+func GetUpdateReport(ctx context.Context, installationID string, redacted []string, check []*versionfile.InstalledVersion) map[string]VersionCheckReport {
 	versionChecker := new(VersionChecker)
 	versionChecker.signature = installationID
+	versionChecker.redacted = redacted
 
 	for _, c := range check {
 		if strings.HasPrefix(c.Name, ociinstaller.DefaultImageRepoDisplayURL) {
@@ -62,9 +66,29 @@ func GetUpdateReport(ctx context.Context, installationID string, check []*versio
 }
 
 // GetAllUpdateReport looks up and reports the updated version of all turbot plugins which are listed in versions.json
-func GetAllUpdateReport(ctx context.Context, installationID string) map[string]VersionCheckReport {
+func GetAllUpdateReport(ctx context.Context, installationID string, redacted []string) map[string]VersionCheckReport {
+	return newAllPluginsVersionChecker(installationID, redacted).reportPluginUpdates(ctx)
+}
+
+// GetAllUpdateCheckRequest returns the url and payload of the request which GetAllUpdateReport sends
+// this does not send the request - it is used to show the usage data sent by steampipe
+// if no plugins would be checked, no request is sent and ok is false
+func GetAllUpdateCheckRequest(installationID string, redacted []string) (u url.URL, payload map[string]interface{}, ok bool) {
+	v := newAllPluginsVersionChecker(installationID, redacted)
+	if len(v.pluginsToCheck) == 0 {
+		return u, nil, false
+	}
+	var requestPayload []versionCheckCorePayload
+	for _, ref := range v.pluginsToCheck {
+		requestPayload = append(requestPayload, v.getPayloadFromInstalledData(ref))
+	}
+	return v.getVersionCheckURL(), v.buildRequestPayload(requestPayload), true
+}
+
+func newAllPluginsVersionChecker(installationID string, redacted []string) *VersionChecker {
 	versionChecker := new(VersionChecker)
 	versionChecker.signature = installationID
+	versionChecker.redacted = redacted
 	versionChecker.pluginsToCheck = []*versionfile.InstalledVersion{}
 
 	// retrieve the plugin version data from steampipe config
@@ -75,8 +99,7 @@ func GetAllUpdateReport(ctx context.Context, installationID string) map[string]V
 			versionChecker.pluginsToCheck = append(versionChecker.pluginsToCheck, p)
 		}
 	}
-
-	return versionChecker.reportPluginUpdates(ctx)
+	return versionChecker
 }
 
 func (v *VersionChecker) reportPluginUpdates(ctx context.Context) map[string]VersionCheckReport {
@@ -172,11 +195,12 @@ func (v *VersionChecker) getVersionCheckURL() url.URL {
 func (v *VersionChecker) requestServerForLatest(ctx context.Context, payload []versionCheckCorePayload) ([]versionCheckCorePayload, error) {
 	// Set a default timeout of 3 sec for the check request (in milliseconds)
 	sendRequestTo := v.getVersionCheckURL()
-	requestBody := utils.BuildRequestPayload(v.signature, map[string]interface{}{
-		"plugins": payload,
-	})
+	requestBody, err := json.Marshal(v.buildRequestPayload(payload))
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := utils.SendRequest(ctx, v.signature, "POST", sendRequestTo, requestBody)
+	resp, err := utils.SendRequest(ctx, v.signature, "POST", sendRequestTo, bytes.NewBuffer(requestBody))
 	if err != nil {
 		log.Printf("[TRACE] Could not send request")
 		return nil, err
@@ -205,8 +229,14 @@ func (v *VersionChecker) requestServerForLatest(ctx context.Context, payload []v
 	return responseData, nil
 }
 
-func GetLatestPluginVersionByConstraint(ctx context.Context, installationID string, org string, name string, constraint string) (*ResolvedPluginVersion, error) {
-	vc := VersionChecker{signature: installationID}
+func (v *VersionChecker) buildRequestPayload(payload []versionCheckCorePayload) map[string]interface{} {
+	return utils.BuildRequestPayloadMap(v.signature, map[string]interface{}{
+		"plugins": payload,
+	}, v.redacted)
+}
+
+func GetLatestPluginVersionByConstraint(ctx context.Context, installationID string, redacted []string, org string, name string, constraint string) (*ResolvedPluginVersion, error) {
+	vc := VersionChecker{signature: installationID, redacted: redacted}
 	payload := []versionCheckCorePayload{
 		{
 			Org:        org,
@@ -0,0 +1,99 @@
+package pluginmanager
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sort"
+	"syscall"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/utils"
+	"golang.org/x/exp/maps"
+)
+
+// DebugState contains the plugin instances which have trace logging enabled
+// this is written by `steampipe plugin debug` and read by the plugin manager
+type DebugState struct {
+	PluginInstances []string `json:"plugin_instances"`
+}
+
+// LoadDebugState loads the plugin debug state file - if there is no file an empty state is returned
+func LoadDebugState() (*DebugState, error) {
+	s := &DebugState{}
+	if !filehelpers.FileExists(filepaths.PluginDebugStateFilePath()) {
+		return s, nil
+	}
+	fileContent, err := os.ReadFile(filepaths.PluginDebugStateFilePath())
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(fileContent, s); err != nil {
+		log.Printf("[WARN] failed to unmarshall plugin debug state file at %s with error %s", filepaths.PluginDebugStateFilePath(), err.Error())
+		return &DebugState{}, nil
+	}
+	return s, nil
+}
+
+func (s *DebugState) Save() error {
+	// if no plugins are being debugged, just remove the file
+	if len(s.PluginInstances) == 0 {
+		if err := os.Remove(filepaths.PluginDebugStateFilePath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepaths.PluginDebugStateFilePath(), content, 0644)
+}
+
+// Lookup returns a lookup of the plugin instances with trace logging enabled
+func (s *DebugState) Lookup() map[string]struct{} {
+	return utils.SliceToLookup(s.PluginInstances)
+}
+
+// IsEnabled returns whether trace logging is enabled for the given plugin instance
+func (s *DebugState) IsEnabled(pluginInstance string) bool {
+	_, ok := s.Lookup()[pluginInstance]
+	return ok
+}
+
+// SetPluginDebug enables or disables trace logging for the given plugin instances
+// if the plugin manager is running, it is signalled to reload the debug state
+//   - any running instances of the plugins are stopped, and restarted (on next use) with the updated log level
+//     (the plugin log level cannot be changed at runtime)
+//
+// returns whether the plugin manager was signalled
+func SetPluginDebug(pluginInstances []string, enabled bool) (bool, error) {
+	debugState, err := LoadDebugState()
+	if err != nil {
+		return false, err
+	}
+	lookup := debugState.Lookup()
+	for _, p := range pluginInstances {
+		if enabled {
+			lookup[p] = struct{}{}
+		} else {
+			delete(lookup, p)
+		}
+	}
+	debugState.PluginInstances = maps.Keys(lookup)
+	sort.Strings(debugState.PluginInstances)
+	if err := debugState.Save(); err != nil {
+		return false, err
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		return false, err
+	}
+	if state == nil || !state.Running {
+		// the debug state will be picked up when the plugin manager next starts
+		return false, nil
+	}
+	return true, state.sendSignal(syscall.SIGUSR1)
+}
@@ -0,0 +1,105 @@
+package pluginmanager
+
+import (
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestDebugState(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+
+	// if there is no file, no plugins are being debugged
+	s, err := LoadDebugState()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(s.PluginInstances) != 0 {
+		t.Errorf("expected no plugin instances, got %v", s.PluginInstances)
+	}
+
+	s.PluginInstances = []string{"aws", "gcp"}
+	if err := s.Save(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	loaded, err := LoadDebugState()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !slices.Equal(loaded.PluginInstances, s.PluginInstances) {
+		t.Errorf("expected %v, got %v", s.PluginInstances, loaded.PluginInstances)
+	}
+	if !loaded.IsEnabled("aws") || loaded.IsEnabled("azure") {
+		t.Errorf("expected only the saved plugin instances to be enabled")
+	}
+
+	// saving an empty state removes the file
+	if err := (&DebugState{}).Save(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := os.Stat(filepaths.PluginDebugStateFilePath()); !os.IsNotExist(err) {
+		t.Errorf("expected the debug state file to be removed, got %v", err)
+	}
+
+	// an invalid file is treated as an empty state
+	if err := os.WriteFile(filepaths.PluginDebugStateFilePath(), []byte("invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := LoadDebugState(); err != nil || len(s.PluginInstances) != 0 {
+		t.Errorf("expected an empty state for an invalid file, got %v, %v", s, err)
+	}
+}
+
+func TestSetPluginDebug(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+
+	// the plugin manager is not running, so it is not signalled
+	signalled, err := SetPluginDebug([]string{"gcp", "aws"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if signalled {
+		t.Errorf("expected the plugin manager not to be signalled as it is not running")
+	}
+	s, err := LoadDebugState()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	// the plugin instances are sorted
+	if expected := []string{"aws", "gcp"}; !slices.Equal(s.PluginInstances, expected) {
+		t.Errorf("expected %v, got %v", expected, s.PluginInstances)
+	}
+
+	// the running plugin manager is faked by a state file with the pid of this process
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+	if err := (&State{Pid: os.Getpid()}).Save(); err != nil {
+		t.Fatalf("failed to save the plugin manager state: %s", err.Error())
+	}
+
+	signalled, err = SetPluginDebug([]string{"aws"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !signalled {
+		t.Errorf("expected the running plugin manager to be signalled")
+	}
+	select {
+	case <-signals:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the plugin manager to be sent SIGUSR1")
+	}
+	if s, err := LoadDebugState(); err != nil || !slices.Equal(s.PluginInstances, []string{"gcp"}) {
+		t.Errorf("expected only gcp to be enabled, got %v, %v", s, err)
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"io"
 	"log"
 	"os/exec"
+	"strconv"
 	"syscall"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/spf13/viper"
 	"github.com/turbot/steampipe-plugin-sdk/v5/logging"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/constants"
@@ -45,9 +47,19 @@ func StartNewInstance(steampipeExecutablePath string) (*State, error) {
 func start(steampipeExecutablePath string) (*State, error) {
 	// note: we assume the install dir has been assigned to file_paths.SteampipeDir
 	// - this is done both by the FDW and Steampipe
-	pluginManagerCmd := exec.Command(steampipeExecutablePath,
+	args := []string{
 		"plugin-manager",
-		"--"+constants.ArgInstallDir, filepaths.SteampipeDir)
+		"--" + constants.ArgInstallDir, filepaths.SteampipeDir,
+	}
+	// pass the refresh concurrency through, as it may have been set by a command line flag
+	if refreshConcurrency := viper.GetInt(constants.ArgRefreshConcurrency); refreshConcurrency > 0 {
+		args = append(args, "--"+constants.ArgRefreshConcurrency, strconv.Itoa(refreshConcurrency))
+	}
+	// likewise the metrics port, which may only be set by a command line flag
+	if metricsPort := viper.GetInt(constants.ArgMetricsPort); metricsPort > 0 {
+		args = append(args, "--"+constants.ArgMetricsPort, strconv.Itoa(metricsPort))
+	}
+	pluginManagerCmd := exec.Command(steampipeExecutablePath, args...)
 	// set attributes on the command to ensure the process is not shutdown when its parent terminates
 	pluginManagerCmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
@@ -64,6 +76,7 @@ func start(steampipeExecutablePath string) (*State, error) {
 		Cmd:              pluginManagerCmd,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
 		Logger:           logger,
+		GRPCDialOptions:  pluginshared.ClientDialOptions(),
 	})
 
 	if _, err := client.Start(); err != nil {
@@ -101,6 +114,22 @@ func Stop() error {
 	return stop(state)
 }
 
+// Reload signals the running plugin manager instance to reload the connection config
+// and refresh connections - this does not restart the plugin manager or the database service
+func Reload() error {
+	log.Println("[DEBUG] pluginmanager.Reload start")
+	defer log.Println("[DEBUG] pluginmanager.Reload end")
+	// try to load the plugin manager state
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+	if state == nil || !state.Running {
+		return sperr.New("plugin manager is not running")
+	}
+	return state.sendSignal(syscall.SIGHUP)
+}
+
 // stop the running plugin manager instance
 func stop(state *State) error {
 	log.Println("[DEBUG] pluginmanager.stop start")
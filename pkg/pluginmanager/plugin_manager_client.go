@@ -43,7 +43,8 @@ func (c *PluginManagerClient) attachToPluginManager() error {
 		Reattach:        c.pluginManagerState.reattachConfig(),
 		AllowedProtocols: []plugin.Protocol{
 			plugin.ProtocolNetRPC, plugin.ProtocolGRPC},
-		Logger: logger,
+		Logger:          logger,
+		GRPCDialOptions: pluginshared.ClientDialOptions(),
 	})
 
 	// connect via RPC
@@ -0,0 +1,59 @@
+package pluginmanager
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	psutils "github.com/shirou/gopsutil/process"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+// PluginProcess contains the details of a plugin process started by the plugin manager
+type PluginProcess struct {
+	Pid int32
+	// the plugin install path, relative to the plugin directory
+	Plugin string
+	// resident memory, in bytes
+	MemoryBytes uint64
+}
+
+// GetPluginProcesses returns the plugin processes which are running as children of the plugin manager
+func (s *State) GetPluginProcesses() ([]PluginProcess, error) {
+	if !s.Running {
+		return nil, nil
+	}
+	pluginManagerProcess, err := psutils.NewProcess(int32(s.Pid))
+	if err != nil {
+		return nil, err
+	}
+	children, err := pluginManagerProcess.Children()
+	if err != nil {
+		// Children returns an error if there are no child processes
+		log.Printf("[TRACE] failed to get plugin manager child processes: %s", err.Error())
+		return nil, nil
+	}
+
+	res := make([]PluginProcess, 0, len(children))
+	for _, child := range children {
+		p := PluginProcess{Pid: child.Pid}
+		if cmdLine, err := child.CmdlineSlice(); err == nil && len(cmdLine) > 0 {
+			p.Plugin = pluginNameFromPath(cmdLine[0])
+		}
+		if memoryInfo, err := child.MemoryInfo(); err == nil {
+			p.MemoryBytes = memoryInfo.RSS
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+// pluginNameFromPath returns the directory of the plugin binary, relative to the plugin directory
+// e.g. hub.steampipe.io/plugins/turbot/aws@latest
+func pluginNameFromPath(pluginPath string) string {
+	rel, err := filepath.Rel(filepaths.EnsurePluginDir(), filepath.Dir(pluginPath))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(pluginPath)
+	}
+	return filepath.ToSlash(rel)
+}
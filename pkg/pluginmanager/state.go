@@ -2,6 +2,7 @@ package pluginmanager
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"syscall"
@@ -122,6 +123,18 @@ func (s *State) kill() error {
 	return nil
 }
 
+// send the given signal to the plugin manager process
+func (s *State) sendSignal(signal syscall.Signal) error {
+	process, err := utils.FindProcess(s.Pid)
+	if err != nil {
+		return err
+	}
+	if process == nil {
+		return fmt.Errorf("could not find plugin manager process (%d)", s.Pid)
+	}
+	return process.SendSignal(signal)
+}
+
 func (s *State) delete() {
 	_ = os.Remove(filepaths.PluginManagerStateFilePath())
 }
@@ -0,0 +1,19 @@
+package shared
+
+import (
+	"github.com/hashicorp/go-plugin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// ClientDialOptions returns the dial options used by clients of the plugin manager
+// these create a span for each call and propagate its trace context to the plugin manager
+func ClientDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler())}
+}
+
+// NewGRPCServer creates the grpc server for the plugin manager
+// this creates a span for each call, as a child of the span of the client (if any)
+func NewGRPCServer(opts []grpc.ServerOption) *grpc.Server {
+	return plugin.DefaultGRPCServer(append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler())))
+}
@@ -21,34 +21,30 @@ func NewPluginMessageServer(pluginManager *PluginManager) (*PluginMessageServer,
 func (m *PluginMessageServer) AddConnection(pluginClient *sdkgrpc.PluginClient, pluginName string, connectionNames ...string) error {
 	log.Printf("[TRACE] PluginMessageServer AddConnection for connections %v", connectionNames)
 
+	// does this plugin support streaming cache
+	// (this is checked once for all connections, as they are all provided by the same plugin)
+	supportedOperations, err := pluginClient.GetSupportedOperations()
+	if err != nil {
+		return err
+	}
+	if !supportedOperations.MessageStream {
+		log.Printf("[WARN] plugin '%s' does not support message stream", pluginName)
+		return nil
+	}
+
 	for _, connection := range connectionNames {
-		cacheStream, err := m.openMessageStream(pluginClient, pluginName, connection)
+		cacheStream, err := m.openMessageStream(pluginClient, connection)
 		if err != nil {
 			return err
 		}
-
-		// if no cache stream was returned, this plugin cannot support cache streams
-		if cacheStream == nil {
-			return nil
-		}
 		go m.runMessageListener(cacheStream, connection)
 	}
 	return nil
 }
 
-func (m *PluginMessageServer) openMessageStream(pluginClient *sdkgrpc.PluginClient, pluginName, connection string) (sdkproto.WrapperPlugin_EstablishMessageStreamClient, error) {
+func (m *PluginMessageServer) openMessageStream(pluginClient *sdkgrpc.PluginClient, connection string) (sdkproto.WrapperPlugin_EstablishMessageStreamClient, error) {
 	log.Printf("[TRACE] openMessageStream for connection '%s'", connection)
 
-	// does this plugin support streaming cache
-	supportedOperations, err := pluginClient.GetSupportedOperations()
-	if err != nil {
-		return nil, err
-	}
-	if !supportedOperations.MessageStream {
-		log.Printf("[WARN] plugin '%s' does not support message stream", pluginName)
-		return nil, nil
-	}
-
 	log.Printf("[TRACE] calling EstablishMessageStream")
 
 	stream, err := pluginClient.EstablishMessageStream()
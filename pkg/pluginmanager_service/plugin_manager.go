@@ -24,13 +24,14 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/connection"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
 	"github.com/turbot/steampipe/pkg/pluginmanager_service/grpc"
 	pb "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/proto"
 	pluginshared "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/shared"
-	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/scheduler"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
 )
@@ -73,7 +74,25 @@ type PluginManager struct {
 	// map of plugin configs (keyed by plugin instance)
 	plugins connection.PluginMap
 
+	// connections whose schemas have been reported as updated, and which are waiting to be refreshed
+	// - updates are batched so connections which change together are refreshed together
+	pendingSchemaUpdates map[string]struct{}
+	schemaUpdateTimer    *time.Timer
+	schemaUpdateMut      sync.Mutex
+	// the time of the last credential refresh of each connection
+	credentialRefreshTimes map[string]time.Time
+	credentialRefreshMut   sync.Mutex
+	// only allow a single sync of discovered connections at a time
+	discoverySyncMut sync.Mutex
+
+	// lookup of plugin instances with trace logging enabled (by `steampipe plugin debug`)
+	debugPlugins map[string]struct{}
+
 	pool *pgxpool.Pool
+	// runs the periodic jobs of the service
+	scheduler *scheduler.Scheduler
+	// listener for postgres notifications sent by other steampipe processes
+	notificationListener *db_common.NotificationListener
 }
 
 func NewPluginManager(ctx context.Context, connectionConfig map[string]*sdkproto.ConnectionConfig, pluginConfigs connection.PluginMap, logger hclog.Logger) (*PluginManager, error) {
@@ -84,6 +103,9 @@ func NewPluginManager(ctx context.Context, connectionConfig map[string]*sdkproto
 		connectionConfigMap: connectionConfig,
 		userLimiters:        pluginConfigs.ToPluginLimiterMap(),
 		plugins:             pluginConfigs,
+
+		pendingSchemaUpdates:   make(map[string]struct{}),
+		credentialRefreshTimes: make(map[string]time.Time),
 	}
 
 	pluginManager.messageServer = &PluginMessageServer{pluginManager: pluginManager}
@@ -92,10 +114,11 @@ func NewPluginManager(ctx context.Context, connectionConfig map[string]*sdkproto
 	pluginManager.populatePluginConnectionConfigs()
 	// determine cache size for each plugin
 	pluginManager.setPluginCacheSizeMap()
+	// load the plugins which have trace logging enabled
+	pluginManager.loadPluginDebugState()
 
 	// create a connection pool to connection refresh
-	// in testing, a size of 20 seemed optimal
-	poolsize := 20
+	poolsize := connection.RefreshPoolSize()
 	pool, err := db_local.CreateConnectionPool(ctx, &db_local.CreateDbOptions{Username: constants.DatabaseSuperUser}, poolsize)
 	if err != nil {
 		return nil, err
@@ -109,6 +132,11 @@ func NewPluginManager(ctx context.Context, connectionConfig map[string]*sdkproto
 	if err := pluginManager.initialisePluginColumns(ctx); err != nil {
 		return nil, err
 	}
+
+	if err := pluginManager.initNotificationListener(ctx); err != nil {
+		return nil, err
+	}
+	pluginManager.startScheduler(ctx)
 	return pluginManager, nil
 }
 
@@ -123,7 +151,7 @@ func (m *PluginManager) Serve() {
 		HandshakeConfig: pluginshared.Handshake,
 		Plugins:         pluginMap,
 		//  enable gRPC serving for this plugin...
-		GRPCServer: plugin.DefaultGRPCServer,
+		GRPCServer: pluginshared.NewGRPCServer,
 	})
 }
 
@@ -208,23 +236,26 @@ func (m *PluginManager) Pool() *pgxpool.Pool {
 	return m.pool
 }
 
-func (m *PluginManager) RefreshConnections(*pb.RefreshConnectionsRequest) (*pb.RefreshConnectionsResponse, error) {
-	log.Printf("[INFO] PluginManager RefreshConnections")
+func (m *PluginManager) RefreshConnections(req *pb.RefreshConnectionsRequest) (*pb.RefreshConnectionsResponse, error) {
+	log.Printf("[INFO] PluginManager RefreshConnections, force update connections: %s", strings.Join(req.Connections, ","))
 
 	resp := &pb.RefreshConnectionsResponse{}
 
 	log.Printf("[INFO] calling RefreshConnections asyncronously")
 
-	go m.doRefresh()
+	go m.doRefresh(req.Connections...)
 	return resp, nil
 }
 
-func (m *PluginManager) doRefresh() {
-	refreshResult := connection.RefreshConnections(context.Background(), m)
+func (m *PluginManager) doRefresh(forceUpdateConnectionNames ...string) {
+	refreshResult := connection.RefreshConnections(context.Background(), m, forceUpdateConnectionNames...)
 	if refreshResult.Error != nil {
 		// NOTE: the RefreshConnectionState will already have sent a notification to the CLI
 		log.Printf("[WARN] RefreshConnections failed with error: %s", refreshResult.Error.Error())
+		return
 	}
+	// now the discovering connections are loaded, sync any discovered connections
+	m.syncConnectionDiscoveries(context.Background())
 }
 
 // OnConnectionConfigChanged is the callback function invoked by the connection watcher when the config changed
@@ -261,6 +292,16 @@ func (m *PluginManager) Shutdown(*pb.ShutdownRequest) (resp *pb.ShutdownResponse
 	m.shutdownMut.Lock()
 	m.startPluginWg.Wait()
 
+	// stop running scheduled jobs
+	if m.scheduler != nil {
+		m.scheduler.Stop()
+	}
+
+	// stop listening for notifications
+	if m.notificationListener != nil {
+		m.notificationListener.Stop(context.Background())
+	}
+
 	// close our pool
 	log.Printf("[INFO] PluginManager closing pool")
 	m.pool.Close()
@@ -291,7 +332,11 @@ func (m *PluginManager) killPlugin(p *runningPlugin) {
 		// shouldn't happen but has been observed in error situations
 		return
 	}
-	log.Printf("[INFO] PluginManager killing plugin %s (%v)", p.pluginInstance, p.reattach.Pid)
+	if p.reattach != nil {
+		log.Printf("[INFO] PluginManager killing plugin %s (%v)", p.pluginInstance, p.reattach.Pid)
+	} else {
+		log.Printf("[INFO] PluginManager killing plugin %s", p.pluginInstance)
+	}
 	p.client.Kill()
 }
 
@@ -497,14 +542,23 @@ func (m *PluginManager) startPluginProcess(pluginInstance string, connectionConf
 
 	cmd := exec.Command(pluginPath)
 	m.setPluginMaxMemory(pluginConfig, cmd)
+
+	// pass our logger to the plugin client to ensure plugin logs end up in logfile
+	logger := m.logger
+	m.mut.RLock()
+	debugEnabled := m.isDebugEnabled(pluginInstance)
+	m.mut.RUnlock()
+	if debugEnabled {
+		// if trace logging is enabled for this plugin, log to a dedicated logfile instead
+		logger = m.setPluginDebug(pluginInstance, cmd)
+	}
+
 	client := plugin.NewClient(&plugin.ClientConfig{
 		HandshakeConfig:  sdkshared.Handshake,
 		Plugins:          pluginMap,
 		Cmd:              cmd,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-
-		// pass our logger to the plugin client to ensure plugin logs end up in logfile
-		Logger: m.logger,
+		Logger:           logger,
 	})
 
 	if _, err := client.Start(); err != nil {
@@ -764,32 +818,6 @@ func (m *PluginManager) setRateLimiters(pluginInstance string, pluginClient *sdk
 	return err
 }
 
-// update the schema for the specified connection
-// called from the message server after receiving a PluginMessageType_SCHEMA_UPDATED message from plugin
-func (m *PluginManager) updateConnectionSchema(ctx context.Context, connectionName string) {
-	log.Printf("[INFO] updateConnectionSchema connection %s", connectionName)
-
-	refreshResult := connection.RefreshConnections(ctx, m, connectionName)
-	if refreshResult.Error != nil {
-		log.Printf("[TRACE] error refreshing connections: %s", refreshResult.Error)
-		return
-	}
-
-	// also send a postgres notification
-	notification := steampipeconfig.NewSchemaUpdateNotification()
-
-	conn, err := m.pool.Acquire(ctx)
-	if err != nil {
-		log.Printf("[WARN] failed to send schema update notification: %s", err)
-	}
-	defer conn.Release()
-
-	err = db_local.SendPostgresNotification(ctx, conn.Conn(), notification)
-	if err != nil {
-		log.Printf("[WARN] failed to send schema update notification: %s", err)
-	}
-}
-
 func (m *PluginManager) nonAggregatorConnectionCount() int {
 	res := 0
 	for _, connections := range m.pluginConnectionConfigMap {
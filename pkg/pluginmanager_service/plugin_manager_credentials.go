@@ -0,0 +1,144 @@
+package pluginmanager_service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	sdkproto "github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe/pkg/connection"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+)
+
+// the minimum time between credential refreshes of a connection
+// if the re-resolved credentials have also expired (e.g. the session could not be renewed), the connection fails again
+// on the next refresh - it must not be refreshed (and its plugin restarted) in a loop
+const credentialRefreshInterval = 5 * time.Minute
+
+// OnConnectionCredentialsExpired is called with the connections whose credentials have expired (e.g. an STS session
+// or OIDC token) - either by RefreshConnections, when the schema import of a connection fails, or when a client
+// notifies the service that a query failed (see handleCredentialsExpiredNotification)
+//
+// the connection config is re-resolved (re-running env and secret lookups) and only the sessions of the given
+// connections are restarted - other connections provided by the same plugin instance are unaffected
+func (m *PluginManager) OnConnectionCredentialsExpired(_ context.Context, connectionNames []string) {
+	connectionNames = m.startCredentialRefresh(connectionNames)
+	if len(connectionNames) == 0 {
+		return
+	}
+	// NOTE: this is called while the refresh holds the refresh lock, and the config reload refreshes connections,
+	// so refresh asynchronously (the refresh context is not used as the credential refresh outlives it)
+	go m.refreshConnectionCredentials(context.Background(), connectionNames)
+}
+
+// startCredentialRefresh returns the connections which have not had their credentials refreshed
+// within credentialRefreshInterval, and records the refresh time of each of them
+func (m *PluginManager) startCredentialRefresh(connectionNames []string) []string {
+	m.credentialRefreshMut.Lock()
+	defer m.credentialRefreshMut.Unlock()
+
+	now := time.Now()
+	var res []string
+	for _, connectionName := range connectionNames {
+		if lastRefresh, ok := m.credentialRefreshTimes[connectionName]; ok && now.Sub(lastRefresh) < credentialRefreshInterval {
+			log.Printf("[INFO] the credentials of connection %s were refreshed at %s - not refreshing again", connectionName, lastRefresh.Format(time.RFC3339))
+			continue
+		}
+		m.credentialRefreshTimes[connectionName] = now
+		res = append(res, connectionName)
+	}
+	return res
+}
+
+func (m *PluginManager) refreshConnectionCredentials(ctx context.Context, connectionNames []string) {
+	log.Printf("[INFO] refreshing the credentials of %s", strings.Join(connectionNames, ","))
+
+	// re-resolve the connection config - any changed config is sent to the plugins, and connections are refreshed
+	connection.ReloadConnectionConfig(ctx, m)
+
+	// the config of a connection may be unchanged even though its credentials have been renewed
+	// (e.g. a profile whose credentials file has been updated), so restart the sessions of the connections regardless
+	if err := m.restartConnectionSessions(connectionNames); err != nil {
+		log.Printf("[WARN] failed to restart the sessions of %s: %s", strings.Join(connectionNames, ","), err.Error())
+	}
+}
+
+// restartConnectionSessions sends the current config of the given connections to their running plugin instances as
+// changed config - the plugin clears the connection cache of each connection (which holds the clients created with the
+// expired credentials) and its query cache, so the next query of the connection creates new clients
+// the plugin instance is not restarted, so queries of its other connections are not interrupted
+func (m *PluginManager) restartConnectionSessions(connectionNames []string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	// build a map of UpdateConnectionConfig requests, keyed by plugin instance
+	requestMap := make(map[string]*sdkproto.UpdateConnectionConfigsRequest)
+	for _, connectionName := range connectionNames {
+		connectionConfig, ok := m.connectionConfigMap[connectionName]
+		if !ok {
+			continue
+		}
+		p, ok := m.runningPluginMap[connectionConfig.PluginInstance]
+		if !ok {
+			// the plugin is not running - the re-resolved config will be used when it starts
+			continue
+		}
+		// a plugin which is still starting is starting with the current config
+		// (the initialized channel is closed once the reattach config has been set)
+		select {
+		case <-p.initialized:
+		default:
+			log.Printf("[INFO] plugin %s is not initialized so the session of connection %s will not be restarted", connectionConfig.PluginInstance, connectionName)
+			continue
+		}
+		log.Printf("[INFO] restarting the session of connection %s to refresh its credentials", connectionName)
+		req, ok := requestMap[connectionConfig.PluginInstance]
+		if !ok {
+			req = &sdkproto.UpdateConnectionConfigsRequest{}
+			requestMap[connectionConfig.PluginInstance] = req
+		}
+		req.Changed = append(req.Changed, connectionConfig)
+	}
+	return m.sendUpdateConnectionConfigs(requestMap)
+}
+
+// credentialExpiredConnections returns the (sorted) connections named in an error caused by expired credentials
+// the plugin sdk prefixes the errors of a query with the name of the connection, e.g. 'aws_prod: ... ExpiredToken ...'
+func credentialExpiredConnections(message string, connectionNames []string) []string {
+	if !error_helpers.IsCredentialExpiryError(message) {
+		return nil
+	}
+	var res []string
+	for _, connectionName := range connectionNames {
+		if isConnectionNamedInError(message, connectionName) {
+			res = append(res, connectionName)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// isConnectionNamedInError returns whether the message contains the connection name followed by ': '
+// and not preceded by an identifier character (so 'aws' is not matched by 'prod_aws: ...')
+func isConnectionNamedInError(message, connectionName string) bool {
+	prefix := connectionName + ": "
+	for offset := 0; ; {
+		idx := strings.Index(message[offset:], prefix)
+		if idx == -1 {
+			return false
+		}
+		idx += offset
+		if previous, _ := utf8.DecodeLastRuneInString(message[:idx]); idx == 0 || !isIdentifierChar(previous) {
+			return true
+		}
+		offset = idx + 1
+	}
+}
+
+func isIdentifierChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
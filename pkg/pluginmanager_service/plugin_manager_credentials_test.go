@@ -0,0 +1,45 @@
+package pluginmanager_service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartCredentialRefresh(t *testing.T) {
+	m := &PluginManager{credentialRefreshTimes: map[string]time.Time{
+		"recent": time.Now().Add(-time.Minute),
+		"old":    time.Now().Add(-credentialRefreshInterval - time.Minute),
+	}}
+
+	// connections refreshed within the refresh interval are not refreshed again
+	if got := strings.Join(m.startCredentialRefresh([]string{"new", "recent", "old"}), ","); got != "new,old" {
+		t.Errorf("expected new,old to be refreshed, got %s", got)
+	}
+	// the refresh times are recorded
+	if got := m.startCredentialRefresh([]string{"new", "old"}); len(got) != 0 {
+		t.Errorf("expected no connections to be refreshed, got %v", got)
+	}
+}
+
+func TestCredentialExpiredConnections(t *testing.T) {
+	connectionNames := []string{"aws", "aws_prod", "prod_aws", "github"}
+	tests := map[string]struct {
+		message  string
+		expected string
+	}{
+		"query error":            {message: "aws_prod: operation error EC2: DescribeInstances, api error ExpiredToken: The security token included in the request is expired", expected: "aws_prod"},
+		"wrapped query error":    {message: "ERROR: rpc error: code = Unknown desc = aws: ExpiredTokenException (SQLSTATE HV000)", expected: "aws"},
+		"name is a suffix":       {message: "prod_aws: ExpiredToken", expected: "prod_aws"},
+		"multiple connections":   {message: "aws: ExpiredToken\naws_prod: ExpiredToken", expected: "aws,aws_prod"},
+		"not an expiry error":    {message: "github: 401 Bad credentials"},
+		"no connection in error": {message: "ExpiredToken: The security token included in the request is expired"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := strings.Join(credentialExpiredConnections(test.message, connectionNames), ","); got != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, got)
+			}
+		})
+	}
+}
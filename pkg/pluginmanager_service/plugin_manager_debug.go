@@ -0,0 +1,87 @@
+package pluginmanager_service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/go-kit/logging"
+	sdklogging "github.com/turbot/steampipe-plugin-sdk/v5/logging"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+)
+
+// ReloadPluginDebugState reloads the set of plugin instances with trace logging enabled
+// any running plugin whose debug state has changed is killed - it will be restarted with the
+// updated log level the next time it is used
+// called by the plugin manager signal handler when `steampipe plugin debug` updates the debug state
+func (m *PluginManager) ReloadPluginDebugState() error {
+	debugState, err := pluginmanager.LoadDebugState()
+	if err != nil {
+		return err
+	}
+	debugPlugins := debugState.Lookup()
+
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	for pluginInstance, p := range m.runningPluginMap {
+		_, wasEnabled := m.debugPlugins[pluginInstance]
+		_, isEnabled := debugPlugins[pluginInstance]
+		if wasEnabled == isEnabled {
+			continue
+		}
+		// a plugin which is still starting (or failed to start) cannot be killed - it will pick up
+		// the updated log level the next time it is restarted
+		// (the initialized channel is closed once the reattach config has been set)
+		select {
+		case <-p.initialized:
+		default:
+			log.Printf("[INFO] trace logging for plugin %s changed to %v - plugin is not initialized so will not be restarted", pluginInstance, isEnabled)
+			continue
+		}
+		log.Printf("[INFO] trace logging for plugin %s changed to %v - restarting plugin", pluginInstance, isEnabled)
+		m.killPlugin(p)
+	}
+	m.debugPlugins = debugPlugins
+	return nil
+}
+
+// loadPluginDebugState loads the initial plugin debug state (called at startup)
+func (m *PluginManager) loadPluginDebugState() {
+	debugState, err := pluginmanager.LoadDebugState()
+	if err != nil {
+		log.Printf("[WARN] failed to load plugin debug state: %s", err.Error())
+		m.debugPlugins = make(map[string]struct{})
+		return
+	}
+	m.debugPlugins = debugState.Lookup()
+}
+
+// isDebugEnabled returns whether trace logging is enabled for the plugin instance
+// NOTE: the caller must hold the mut lock
+func (m *PluginManager) isDebugEnabled(pluginInstance string) bool {
+	_, ok := m.debugPlugins[pluginInstance]
+	return ok
+}
+
+// setPluginDebug sets the plugin log level to trace and returns a logger which writes
+// the plugin logs to a dedicated trace log file for this plugin instance
+func (m *PluginManager) setPluginDebug(pluginInstance string, cmd *exec.Cmd) hclog.Logger {
+	log.Printf("[INFO] trace logging is enabled for plugin %s", pluginInstance)
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", sdklogging.EnvLogLevel, hclog.Trace.String()))
+
+	writer := sdklogging.NewUnescapeNewlineWriter(logging.NewRotatingLogWriter(filepaths.EnsureLogDir(), filepaths.PluginDebugLogPrefix(pluginInstance)))
+	return sdklogging.NewLogger(&hclog.LoggerOptions{
+		Output:     writer,
+		Level:      hclog.Trace,
+		TimeFn:     func() time.Time { return time.Now().UTC() },
+		TimeFormat: "2006-01-02 15:04:05.000 UTC",
+	})
+}
@@ -0,0 +1,65 @@
+package pluginmanager_service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/connection"
+	"github.com/turbot/steampipe/pkg/scheduler"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// the interval at which discovered connections are re-synced, so that they reflect changes in access
+const connectionDiscoverySyncInterval = time.Hour
+
+// connectionDiscoverySyncJob returns the scheduled job which periodically syncs the discovered connections
+// (discovered connections are also synced whenever connections are refreshed by a client)
+func (m *PluginManager) connectionDiscoverySyncJob() *scheduler.Job {
+	return &scheduler.Job{
+		Name:        scheduleJobConnectionDiscovery,
+		Description: "Sync the connections discovered by discovery blocks",
+		Interval:    connectionDiscoverySyncInterval,
+		Run: func(ctx context.Context) error {
+			if m.shuttingDown() {
+				return nil
+			}
+			m.syncConnectionDiscoveries(ctx)
+			return nil
+		},
+	}
+}
+
+// syncConnectionDiscoveries executes any configured connection discoveries and updates the generated config files
+// any changes are picked up by the connection watcher, which refreshes the connections
+func (m *PluginManager) syncConnectionDiscoveries(ctx context.Context) {
+	// the config may be replaced by a concurrent reload, so read it under the lock
+	config := steampipeconfig.GetGlobalConfig()
+	if config == nil || len(config.ConnectionDiscoveries) == 0 {
+		return
+	}
+	// if a sync is already in progress, there is nothing to do
+	if !m.discoverySyncMut.TryLock() {
+		return
+	}
+	defer m.discoverySyncMut.Unlock()
+
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to sync discovered connections: %s", err.Error())
+		return
+	}
+	defer conn.Release()
+
+	for _, res := range connection.SyncConnectionDiscoveries(ctx, conn.Conn(), config) {
+		if res.Error != nil {
+			log.Printf("[WARN] failed to sync discovered connections: %s", res.Error.Error())
+			continue
+		}
+		log.Printf("[INFO] discovery '%s' synced %d connections (added: %s, removed: %s)", res.Name, len(res.Connections), strings.Join(res.Added, ","), strings.Join(res.Removed, ","))
+		if len(res.Skipped) > 0 {
+			log.Printf("[WARN] discovery '%s' skipped connections which are already defined: %s", res.Name, strings.Join(res.Skipped, ","))
+		}
+	}
+}
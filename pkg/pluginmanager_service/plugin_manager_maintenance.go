@@ -0,0 +1,41 @@
+package pluginmanager_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/scheduler"
+)
+
+// maintenanceJob returns the scheduled job which periodically vacuums and analyzes the internal tables of the database
+// the interval is set by the maintenance_interval database option - if this is not positive, nil is returned
+func (m *PluginManager) maintenanceJob() *scheduler.Job {
+	interval := time.Duration(viper.GetInt(constants.ArgMaintenanceInterval)) * time.Minute
+	if interval <= 0 {
+		return nil
+	}
+	return &scheduler.Job{
+		Name:        scheduleJobMaintenance,
+		Description: "Vacuum and analyze the internal tables and materialized views of the database",
+		Interval:    interval,
+		Run: func(ctx context.Context) error {
+			if m.shuttingDown() {
+				return nil
+			}
+			conn, err := m.pool.Acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer conn.Release()
+
+			res, err := db_local.RunMaintenance(ctx, conn.Conn())
+			if err != nil {
+				return err
+			}
+			return res.Error()
+		},
+	}
+}
@@ -2,10 +2,18 @@ package pluginmanager_service
 
 import (
 	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/turbot/steampipe/pkg/connection"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
-	"log"
+	"golang.org/x/exp/maps"
 )
 
 func (m *PluginManager) SendPostgresSchemaNotification(ctx context.Context) error {
@@ -23,6 +31,10 @@ func (m *PluginManager) SendPostgresErrorsAndWarningsNotification(ctx context.Co
 	}
 
 }
+func (m *PluginManager) SendPostgresRefreshProgressNotification(ctx context.Context, notification *steampipeconfig.RefreshProgressNotification) error {
+	return m.sendPostgresNotification(ctx, notification)
+}
+
 func (m *PluginManager) sendPostgresNotification(ctx context.Context, notification any) error {
 	conn, err := m.pool.Acquire(ctx)
 	if err != nil {
@@ -32,3 +44,118 @@ func (m *PluginManager) sendPostgresNotification(ctx context.Context, notificati
 
 	return db_local.SendPostgresNotification(ctx, conn.Conn(), notification)
 }
+
+// initNotificationListener listens for postgres notifications sent by other steampipe processes
+// (e.g. a plugin install), so that we can react to them
+func (m *PluginManager) initNotificationListener(ctx context.Context) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// hijack from the pool as we will be keeping the connection open for the lifetime of the plugin manager
+	// the listener will manage the lifecycle of the connection
+	listener, err := db_common.NewNotificationListener(context.Background(), conn.Hijack())
+	if err != nil {
+		return err
+	}
+	listener.RegisterListener(m.handlePostgresNotification)
+	m.notificationListener = listener
+	return nil
+}
+
+func (m *PluginManager) handlePostgresNotification(notification *pgconn.Notification) {
+	if notification == nil {
+		return
+	}
+	n := &steampipeconfig.PostgresNotification{}
+	if err := json.Unmarshal([]byte(notification.Payload), n); err != nil {
+		log.Printf("[WARN] Error unmarshalling notification: %s", err)
+		return
+	}
+	// we receive the notifications we send ourselves - ignore everything except commands
+	// (plugin installs, schedule requests and expired credentials)
+	switch n.Type {
+	case steampipeconfig.PgNotificationPluginInstalled:
+		if m.verifyCommandNotification(notification) {
+			m.handlePluginInstalledNotification(notification)
+		}
+	case steampipeconfig.PgNotificationScheduleRunNow:
+		if m.verifyCommandNotification(notification) {
+			m.handleScheduleRunNowNotification(notification)
+		}
+	case steampipeconfig.PgNotificationCredentialsExpired:
+		// a credential refresh reloads the connection and clears its caches, so it is only run for admin clients
+		// (the credentials of a connection are refreshed at most once per credentialRefreshInterval)
+		if m.verifyCommandNotification(notification) {
+			m.handleCredentialsExpiredNotification(notification)
+		}
+	}
+}
+
+// verifyCommandNotification returns whether a notification requesting a command was sent by a member of the admin role
+// (any database user may send a notification, so commands are only run if they are recorded in the command request table)
+func (m *PluginManager) verifyCommandNotification(notification *pgconn.Notification) bool {
+	ctx := context.Background()
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to verify command notification: %s", err.Error())
+		return false
+	}
+	defer conn.Release()
+
+	verified, err := db_common.VerifyPostgresCommandNotification(ctx, conn.Conn(), notification.Payload)
+	if err != nil {
+		log.Printf("[WARN] failed to verify command notification: %s", err.Error())
+		return false
+	}
+	if !verified {
+		log.Printf("[WARN] ignoring command notification which was not sent by a member of the %s role", constants.DatabaseAdminRole)
+	}
+	return verified
+}
+
+func (m *PluginManager) handlePluginInstalledNotification(notification *pgconn.Notification) {
+	installedNotification := &steampipeconfig.PluginInstalledNotification{}
+	if err := json.Unmarshal([]byte(notification.Payload), installedNotification); err != nil {
+		log.Printf("[WARN] Error unmarshalling notification: %s", err)
+		return
+	}
+
+	// reload the connection config and refresh connections
+	// - connections which were missing their plugin will now resolve the plugin and be loaded
+	// NOTE: this is called from the listener goroutine, so reload asynchronously
+	log.Printf("[INFO] plugins installed: %s - reloading connection config", strings.Join(installedNotification.Plugins, ","))
+	go connection.ReloadConnectionConfig(context.Background(), m)
+}
+
+func (m *PluginManager) handleScheduleRunNowNotification(notification *pgconn.Notification) {
+	runNowNotification := &steampipeconfig.ScheduleRunNowNotification{}
+	if err := json.Unmarshal([]byte(notification.Payload), runNowNotification); err != nil {
+		log.Printf("[WARN] Error unmarshalling notification: %s", err)
+		return
+	}
+	// the job is run asynchronously
+	// NOTE: this is called from the listener goroutine, so the job is not bound to any context
+	log.Printf("[INFO] run of scheduled job %s requested", runNowNotification.Name)
+	if err := m.scheduler.RunNow(context.Background(), runNowNotification.Name); err != nil {
+		log.Printf("[WARN] failed to run scheduled job %s: %s", runNowNotification.Name, err.Error())
+	}
+}
+
+func (m *PluginManager) handleCredentialsExpiredNotification(notification *pgconn.Notification) {
+	expiredNotification := &steampipeconfig.CredentialsExpiredNotification{}
+	if err := json.Unmarshal([]byte(notification.Payload), expiredNotification); err != nil {
+		log.Printf("[WARN] Error unmarshalling notification: %s", err)
+		return
+	}
+
+	m.mut.RLock()
+	connectionNames := credentialExpiredConnections(expiredNotification.Error, maps.Keys(m.connectionConfigMap))
+	m.mut.RUnlock()
+	if len(connectionNames) == 0 {
+		log.Printf("[INFO] ignoring credentials expired notification which names no connection with expired credentials: %s", expiredNotification.Error)
+		return
+	}
+	log.Printf("[INFO] a query of %s failed because the credentials have expired", strings.Join(connectionNames, ","))
+	m.OnConnectionCredentialsExpired(context.Background(), connectionNames)
+}
@@ -0,0 +1,24 @@
+package pluginmanager_service
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe/pkg/scheduler"
+)
+
+// the names of the jobs scheduled by the plugin manager
+const (
+	scheduleJobConnectionDiscovery = "connection_discovery"
+	scheduleJobMaintenance         = "maintenance"
+)
+
+// startScheduler registers the periodic jobs of the service and starts the scheduler
+// the state of the jobs is recorded in the steampipe_schedule table
+func (m *PluginManager) startScheduler(ctx context.Context) {
+	m.scheduler = scheduler.NewScheduler(m.pool)
+	m.scheduler.Register(m.connectionDiscoverySyncJob())
+	if job := m.maintenanceJob(); job != nil {
+		m.scheduler.Register(job)
+	}
+	m.scheduler.Start(ctx)
+}
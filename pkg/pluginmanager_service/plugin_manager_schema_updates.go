@@ -0,0 +1,76 @@
+package pluginmanager_service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/connection"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the time to wait for further schema update messages before refreshing the updated connections
+// when the config of many connections of a plugin changes at once (e.g. generated multi-account config),
+// the plugin sends a message for each connection - these are refreshed together, rather than one refresh per connection
+const schemaUpdateBatchInterval = 500 * time.Millisecond
+
+// update the schema for the specified connection
+// called from the message server after receiving a PluginMessageType_SCHEMA_UPDATED message from plugin
+//
+// the connection is added to the pending schema updates, which are refreshed in a single batch
+// once no further updates have been received for schemaUpdateBatchInterval
+func (m *PluginManager) updateConnectionSchema(_ context.Context, connectionName string) {
+	log.Printf("[INFO] updateConnectionSchema connection %s", connectionName)
+
+	m.schemaUpdateMut.Lock()
+	defer m.schemaUpdateMut.Unlock()
+
+	m.pendingSchemaUpdates[connectionName] = struct{}{}
+	if m.schemaUpdateTimer != nil {
+		// a batch is already pending - extend it
+		m.schemaUpdateTimer.Reset(schemaUpdateBatchInterval)
+		return
+	}
+	// NOTE: the message stream context is not used as the refresh may outlive the stream
+	m.schemaUpdateTimer = time.AfterFunc(schemaUpdateBatchInterval, func() {
+		m.flushSchemaUpdates(context.Background())
+	})
+}
+
+// flushSchemaUpdates refreshes all connections with pending schema updates and sends a single schema update notification
+func (m *PluginManager) flushSchemaUpdates(ctx context.Context) {
+	m.schemaUpdateMut.Lock()
+	connectionNames := utils.SortedMapKeys(m.pendingSchemaUpdates)
+	m.pendingSchemaUpdates = make(map[string]struct{})
+	m.schemaUpdateTimer = nil
+	m.schemaUpdateMut.Unlock()
+
+	if len(connectionNames) == 0 {
+		return
+	}
+	log.Printf("[INFO] refreshing %d %s with updated schemas: %s", len(connectionNames), utils.Pluralize("connection", len(connectionNames)), strings.Join(connectionNames, ","))
+
+	refreshResult := connection.RefreshConnections(ctx, m, connectionNames...)
+	if refreshResult.Error != nil {
+		log.Printf("[TRACE] error refreshing connections: %s", refreshResult.Error)
+		return
+	}
+
+	// also send a postgres notification
+	notification := steampipeconfig.NewSchemaUpdateNotification()
+
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to send schema update notification: %s", err)
+		return
+	}
+	defer conn.Release()
+
+	err = db_local.SendPostgresNotification(ctx, conn.Conn(), notification)
+	if err != nil {
+		log.Printf("[WARN] failed to send schema update notification: %s", err)
+	}
+}
@@ -54,7 +54,7 @@ func NewInitData(ctx context.Context, args []string) *InitData {
 	// load workspace variables syncronously
 	w, inputVariables, errAndWarnings := workspace.LoadWorkspaceVars(ctx)
 	if errAndWarnings.GetError() != nil {
-		i.Result.Error = fmt.Errorf("failed to load workspace: %s", error_helpers.HandleCancelError(errAndWarnings.GetError()).Error())
+		i.Result.Error = error_helpers.NewExitCodeError(constants.ExitCodeConfigLoadFailed, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigLoadFailed, fmt.Errorf("failed to load workspace: %s", error_helpers.HandleCancelError(errAndWarnings.GetError()).Error())))
 		return i
 	}
 
@@ -119,7 +119,7 @@ func (i *InitData) init(ctx context.Context, inputVariables *modconfig.ModVariab
 	errAndWarnings := i.Workspace.LoadWorkspaceMod(ctx, inputVariables)
 	i.Result.AddWarnings(errAndWarnings.Warnings...)
 	if errAndWarnings.GetError() != nil {
-		i.Result.Error = fmt.Errorf("failed to load workspace mod: %s", error_helpers.HandleCancelError(errAndWarnings.GetError()).Error())
+		i.Result.Error = error_helpers.NewExitCodeError(constants.ExitCodeConfigLoadFailed, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigLoadFailed, fmt.Errorf("failed to load workspace mod: %s", error_helpers.HandleCancelError(errAndWarnings.GetError()).Error())))
 		return
 	}
 
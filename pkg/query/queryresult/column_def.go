@@ -6,7 +6,10 @@ import "reflect"
 type ColumnDef struct {
 	Name     string `json:"name"`
 	DataType string `json:"data_type"`
-	isScalar *bool
+	// is this column part of the primary key of its source table
+	// (only identified when a stable row order is requested)
+	PrimaryKey bool `json:"-"`
+	isScalar   *bool
 }
 
 // IsScalar checks if the given value is a scalar value
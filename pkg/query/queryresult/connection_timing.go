@@ -0,0 +1,45 @@
+package queryresult
+
+import (
+	"sort"
+)
+
+// ConnectionTiming is the timing data for all the scans of a single connection
+type ConnectionTiming struct {
+	Connection        string `json:"connection"`
+	ScanCount         int64  `json:"scan_count"`
+	RowsFetched       int64  `json:"rows_fetched"`
+	CachedRowsFetched int64  `json:"cached_rows_fetched"`
+	CacheHits         int64  `json:"cache_hits"`
+	HydrateCalls      int64  `json:"hydrate_calls"`
+	// the total duration of the scans - as scans may run in parallel, this may exceed the query duration
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// NewConnectionTimings aggregates the given scans by connection
+// the result is sorted by connection name
+func NewConnectionTimings(scans []*ScanMetadataRow) []*ConnectionTiming {
+	if len(scans) == 0 {
+		return nil
+	}
+	timingMap := make(map[string]*ConnectionTiming)
+	var res []*ConnectionTiming
+	for _, scan := range scans {
+		t, ok := timingMap[scan.Connection]
+		if !ok {
+			t = &ConnectionTiming{Connection: scan.Connection}
+			timingMap[scan.Connection] = t
+			res = append(res, t)
+		}
+		t.ScanCount++
+		t.RowsFetched += scan.RowsFetched
+		if scan.CacheHit {
+			t.CacheHits++
+			t.CachedRowsFetched += scan.RowsFetched
+		}
+		t.HydrateCalls += scan.HydrateCalls
+		t.DurationMs += scan.DurationMs
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Connection < res[j].Connection })
+	return res
+}
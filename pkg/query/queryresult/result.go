@@ -9,6 +9,8 @@ type TimingResult struct {
 	CachedRowsFetched   int64              `json:"cached_rows_fetched"`
 	HydrateCalls        int64              `json:"hydrate_calls"`
 	ConnectionCount     int64              `json:"connection_count"`
+	// the timing of the scans, broken down by connection
+	Connections []*ConnectionTiming `json:"connections,omitempty"`
 }
 
 func (r *TimingResult) Initialise(summary *QueryRowSummary, scans []*ScanMetadataRow) {
@@ -19,6 +21,7 @@ func (r *TimingResult) Initialise(summary *QueryRowSummary, scans []*ScanMetadat
 	r.HydrateCalls = summary.HydrateCalls
 	// populate scans - note this may not be all scans
 	r.Scans = scans
+	r.Connections = NewConnectionTimings(scans)
 }
 
 type RowResult struct {
@@ -60,3 +63,7 @@ type SyncQueryResult struct {
 	Cols         []*ColumnDef
 	TimingResult *TimingResult
 }
+
+// RowCallback is called for each row of a streamed query result
+// cols are the column definitions of the result, which are the same for every row
+type RowCallback func(cols []*ColumnDef, row []interface{}) error
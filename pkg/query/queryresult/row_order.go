@@ -0,0 +1,227 @@
+package queryresult
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sorted returns a result which streams the rows of this result in a stable order
+// (ordered by the primary key columns where known, then by all other columns, in column order)
+//
+// this is used to make output deterministic (e.g. for diffing exports), as the row order of queries
+// which use parallel scans is not deterministic
+// NOTE: all rows are buffered before any are streamed; any row errors are streamed after the rows
+func (r *Result) Sorted() *Result {
+	res := &Result{
+		RowChan:      new(chan *RowResult),
+		Cols:         r.Cols,
+		TimingResult: r.TimingResult,
+	}
+	*res.RowChan = make(chan *RowResult)
+
+	go func() {
+		var rows [][]interface{}
+		var errors []error
+		for row := range *r.RowChan {
+			if row.Error != nil {
+				errors = append(errors, row.Error)
+				continue
+			}
+			rows = append(rows, row.Data)
+		}
+		// the primary key columns are identified once the rows have been read, so determine the order now
+		columnOrder := SortColumnOrder(r.Cols)
+		sort.SliceStable(rows, func(i, j int) bool {
+			return CompareRows(rows[i], rows[j], columnOrder) < 0
+		})
+		for _, row := range rows {
+			res.StreamRow(row)
+		}
+		for _, err := range errors {
+			res.StreamError(err)
+		}
+		res.Close()
+	}()
+	return res
+}
+
+// SortColumnOrder returns the indexes of the columns in the order they are compared when sorting rows:
+// the primary key columns first, followed by all other columns, in column order
+func SortColumnOrder(cols []*ColumnDef) []int {
+	res := make([]int, 0, len(cols))
+	for i, c := range cols {
+		if c.PrimaryKey {
+			res = append(res, i)
+		}
+	}
+	for i, c := range cols {
+		if !c.PrimaryKey {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// CompareRows compares 2 rows by the columns with the given indexes, in order, returning -1, 0 or 1
+func CompareRows(a, b []interface{}, columnOrder []int) int {
+	for _, i := range columnOrder {
+		if i >= len(a) || i >= len(b) {
+			continue
+		}
+		if c := CompareValues(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(int64(len(a)), int64(len(b)))
+}
+
+// the kinds of value, in the order they are sorted
+// values of different kinds are ordered by kind, so the ordering is total and transitive
+// even when a column contains values of mixed types
+const (
+	valueKindNull = iota
+	valueKindBool
+	valueKindNumber
+	valueKindTime
+	valueKindString
+	valueKindOther
+)
+
+// CompareValues compares 2 column values, returning -1, 0 or 1
+// values are ordered by kind (null, bool, number, time, string, other), then naturally within their kind
+// values which are otherwise equal (e.g. an int64 and a float64 of the same value) are ordered by type
+// and string representation, so the order never depends on the order the rows were read
+func CompareValues(a, b interface{}) int {
+	aKind, bKind := valueKind(a), valueKind(b)
+	if c := compareInts(int64(aKind), int64(bKind)); c != 0 {
+		return c
+	}
+
+	switch aKind {
+	case valueKindNull:
+		return 0
+	case valueKindBool:
+		if c := compareBools(a.(bool), b.(bool)); c != 0 {
+			return c
+		}
+	case valueKindNumber:
+		aNum, _ := toFloat(a)
+		bNum, _ := toFloat(b)
+		if c := compareFloats(aNum, bNum); c != 0 {
+			return c
+		}
+	case valueKindTime:
+		if c := a.(time.Time).Compare(b.(time.Time)); c != 0 {
+			return c
+		}
+	case valueKindString:
+		return strings.Compare(a.(string), b.(string))
+	}
+
+	if c := strings.Compare(reflect.TypeOf(a).String(), reflect.TypeOf(b).String()); c != 0 {
+		return c
+	}
+	return strings.Compare(valueString(a), valueString(b))
+}
+
+func valueKind(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return valueKindNull
+	case bool:
+		return valueKindBool
+	case time.Time:
+		return valueKindTime
+	case string:
+		return valueKindString
+	}
+	if _, ok := toFloat(v); ok {
+		return valueKindNumber
+	}
+	return valueKindOther
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareFloats compares 2 floats - NaN is ordered before all other values
+func compareFloats(a, b float64) int {
+	aNaN, bNaN := math.IsNaN(a), math.IsNaN(b)
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return -1
+	case bNaN:
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+// valueString returns a string representation of the value - json is used for non scalar values
+// as this is deterministic for maps (keys are sorted)
+func valueString(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,108 @@
+package queryresult
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCompareValues(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		a, b     interface{}
+		expected int
+	}{
+		"both nil":           {a: nil, b: nil, expected: 0},
+		"nil first":          {a: nil, b: 1, expected: -1},
+		"nil last":           {a: "a", b: nil, expected: 1},
+		"ints":               {a: int64(2), b: int64(10), expected: -1},
+		"mixed numerics":     {a: int32(3), b: float64(2.5), expected: 1},
+		"equal numerics":     {a: int64(3), b: int64(3), expected: 0},
+		"strings":            {a: "b", b: "a", expected: 1},
+		"bools":              {a: false, b: true, expected: -1},
+		"times":              {a: now, b: now.Add(time.Second), expected: -1},
+		"maps":               {a: map[string]interface{}{"a": 1}, b: map[string]interface{}{"a": 2}, expected: -1},
+		"equal maps":         {a: map[string]interface{}{"a": 1, "b": 2}, b: map[string]interface{}{"b": 2, "a": 1}, expected: 0},
+		"mismatched types":   {a: "1", b: true, expected: 1},
+		"numeric and string": {a: 1, b: "1", expected: -1},
+		"numeric types":      {a: float64(3), b: int64(3), expected: -1},
+		"nan first":          {a: math.NaN(), b: float64(-1), expected: -1},
+		"time and string":    {a: now, b: "a", expected: -1},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := CompareValues(test.a, test.b); got != test.expected {
+				t.Errorf("CompareValues(%v, %v) = %d, expected %d", test.a, test.b, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestSorted(t *testing.T) {
+	res := NewResult([]*ColumnDef{{Name: "a"}, {Name: "b"}})
+	go func() {
+		res.StreamRow([]interface{}{"y", int64(1)})
+		res.StreamRow([]interface{}{"x", int64(2)})
+		res.StreamRow([]interface{}{"x", int64(1)})
+		res.StreamRow([]interface{}{nil, int64(3)})
+		res.Close()
+	}()
+
+	expected := [][]interface{}{
+		{nil, int64(3)},
+		{"x", int64(1)},
+		{"x", int64(2)},
+		{"y", int64(1)},
+	}
+	var got [][]interface{}
+	for row := range *res.Sorted().RowChan {
+		got = append(got, row.Data)
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if CompareRows(got[i], expected[i], []int{0, 1}) != 0 {
+			t.Errorf("row %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+}
+
+// the comparison must be a total order for the sort to be deterministic - in particular it must be transitive
+// for values of mixed types
+func TestCompareValuesTransitive(t *testing.T) {
+	values := []interface{}{nil, true, false, int64(10), int32(9), float64(9), math.NaN(), "10", "9", "a",
+		time.Unix(0, 0), map[string]interface{}{"a": 1}, []interface{}{1, 2}}
+	for _, a := range values {
+		if CompareValues(a, a) != 0 {
+			t.Errorf("CompareValues(%v, %v) != 0", a, a)
+		}
+		for _, b := range values {
+			if CompareValues(a, b) != -CompareValues(b, a) {
+				t.Errorf("CompareValues(%v, %v) is not antisymmetric", a, b)
+			}
+			for _, c := range values {
+				if CompareValues(a, b) < 0 && CompareValues(b, c) < 0 && CompareValues(a, c) >= 0 {
+					t.Errorf("CompareValues is not transitive for %v < %v < %v", a, b, c)
+				}
+			}
+		}
+	}
+}
+
+func TestSortedPrimaryKeyFirst(t *testing.T) {
+	res := NewResult([]*ColumnDef{{Name: "name"}, {Name: "id", PrimaryKey: true}})
+	go func() {
+		res.StreamRow([]interface{}{"a", int64(2)})
+		res.StreamRow([]interface{}{"b", int64(1)})
+		res.Close()
+	}()
+
+	var got []interface{}
+	for row := range *res.Sorted().RowChan {
+		got = append(got, row.Data[1])
+	}
+	if len(got) != 2 || got[0] != int64(1) || got[1] != int64(2) {
+		t.Errorf("expected rows to be ordered by the primary key column, got ids %v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type cycloneDXDocument struct {
+	BomFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BomRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Licenses   []cycloneDXLicense  `json:"licenses,omitempty"`
+	Purl       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXLicense struct {
+	Expression string `json:"expression"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// ToCycloneDX returns the Sbom as a CycloneDX 1.5 JSON document
+func (s *Sbom) ToCycloneDX() ([]byte, error) {
+	cli := newCycloneDXComponent(s.CLI)
+	doc := cycloneDXDocument{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: fmt.Sprintf("urn:uuid:%s", uuid.New().String()),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: s.Created.Format(time.RFC3339),
+			Tools: cycloneDXTools{
+				Components: []cycloneDXComponent{{Type: "application", Name: s.CLI.Name, Version: s.CLI.Version}},
+			},
+			Component: cli,
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	var refs []string
+	for _, c := range s.Components {
+		component := newCycloneDXComponent(c)
+		doc.Components = append(doc.Components, component)
+		refs = append(refs, component.BomRef)
+	}
+	doc.Dependencies = []cycloneDXDependency{{Ref: cli.BomRef, DependsOn: refs}}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func newCycloneDXComponent(c *Component) cycloneDXComponent {
+	res := cycloneDXComponent{
+		Type:    "application",
+		BomRef:  c.Purl,
+		Name:    c.Name,
+		Version: c.Version,
+		Purl:    c.Purl,
+		Properties: []cycloneDXProperty{
+			{Name: "steampipe:kind", Value: string(c.Kind)},
+		},
+	}
+	if res.BomRef == "" {
+		res.BomRef = fmt.Sprintf("%s:%s@%s", c.Kind, c.Name, c.Version)
+	}
+	if c.Kind == ComponentKindMod || c.Kind == ComponentKindFdw {
+		res.Type = "library"
+	}
+	if c.Source != "" {
+		res.Properties = append(res.Properties, cycloneDXProperty{Name: "steampipe:source", Value: c.Source})
+	}
+	if algorithm, value, ok := splitDigest(c.Digest); ok {
+		// CycloneDX hash algorithms are of the form SHA-256
+		alg := strings.ToUpper(algorithm)
+		if strings.HasPrefix(alg, "SHA") && !strings.Contains(alg, "-") {
+			alg = "SHA-" + strings.TrimPrefix(alg, "SHA")
+		}
+		res.Hashes = []cycloneDXHash{{Alg: alg, Content: value}}
+	}
+	if c.License != "" {
+		res.Licenses = []cycloneDXLicense{{Expression: c.License}}
+	}
+	return res
+}
@@ -0,0 +1,194 @@
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/versionmap"
+	"github.com/turbot/steampipe/pkg/version"
+)
+
+const (
+	FormatSPDX      = "spdx"
+	FormatCycloneDX = "cyclonedx"
+
+	// the license of the steampipe CLI
+	cliLicense = "AGPL-3.0-only"
+)
+
+type ComponentKind string
+
+const (
+	ComponentKindCLI      ComponentKind = "cli"
+	ComponentKindDatabase ComponentKind = "database"
+	ComponentKindFdw      ComponentKind = "fdw"
+	ComponentKindPlugin   ComponentKind = "plugin"
+	ComponentKindMod      ComponentKind = "mod"
+)
+
+// Component is an installed component of steampipe
+type Component struct {
+	Kind    ComponentKind
+	Name    string
+	Version string
+	// the digest of the OCI image the component was installed from, e.g. sha256:abc...
+	Digest string
+	// the SPDX license expression for the component (if known)
+	License string
+	// the image ref or git url the component was installed from (if known)
+	Source string
+	// the package url of the component
+	Purl string
+}
+
+// Sbom is a software bill of materials for the steampipe installation
+type Sbom struct {
+	Created time.Time
+	// the steampipe CLI - all other components are contained in this
+	CLI        *Component
+	Components []*Component
+}
+
+// Build builds an Sbom from the CLI version, the database and plugin version files
+// and the mod lock file of the workspace at workspacePath
+func Build(ctx context.Context, workspacePath string) (*Sbom, error) {
+	cliVersion := version.SteampipeVersion.String()
+	res := &Sbom{
+		Created: time.Now().UTC(),
+		CLI: &Component{
+			Kind:    ComponentKindCLI,
+			Name:    "steampipe",
+			Version: cliVersion,
+			License: cliLicense,
+			Source:  "https://github.com/turbot/steampipe",
+			Purl:    fmt.Sprintf("pkg:github/turbot/steampipe@v%s", cliVersion),
+		},
+	}
+
+	dbVersions, err := versionfile.LoadDatabaseVersionFile()
+	if err != nil {
+		return nil, err
+	}
+	for kind, v := range map[ComponentKind]versionfile.InstalledVersion{
+		ComponentKindDatabase: dbVersions.EmbeddedDB,
+		ComponentKindFdw:      dbVersions.FdwExtension,
+	} {
+		if v.Version == "" {
+			// not installed
+			continue
+		}
+		res.Components = append(res.Components, newImageComponent(kind, v))
+	}
+
+	pluginVersions, err := versionfile.LoadPluginVersionFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range pluginVersions.Plugins {
+		res.Components = append(res.Components, newImageComponent(ComponentKindPlugin, *v))
+	}
+
+	mods, err := getModComponents(ctx, workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	res.Components = append(res.Components, mods...)
+
+	// sort by kind, then name, so the output is stable
+	kindOrder := map[ComponentKind]int{ComponentKindDatabase: 0, ComponentKindFdw: 1, ComponentKindPlugin: 2, ComponentKindMod: 3}
+	sort.Slice(res.Components, func(i, j int) bool {
+		ci, cj := res.Components[i], res.Components[j]
+		if ci.Kind != cj.Kind {
+			return kindOrder[ci.Kind] < kindOrder[cj.Kind]
+		}
+		return ci.Name < cj.Name
+	})
+	return res, nil
+}
+
+func newImageComponent(kind ComponentKind, v versionfile.InstalledVersion) *Component {
+	name := v.Name
+	switch kind {
+	case ComponentKindDatabase:
+		name = "steampipe-postgres"
+	case ComponentKindFdw:
+		name = "steampipe-postgres-fdw"
+	}
+	return &Component{
+		Kind:    kind,
+		Name:    name,
+		Version: v.Version,
+		Digest:  v.ImageDigest,
+		License: v.License,
+		Source:  v.InstalledFrom,
+		Purl:    ociPurl(v.InstalledFrom, v.ImageDigest),
+	}
+}
+
+func getModComponents(ctx context.Context, workspacePath string) ([]*Component, error) {
+	lock, err := versionmap.LoadWorkspaceLock(ctx, workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	// a mod version may be a dependency of multiple parents - only include it once
+	var res []*Component
+	added := make(map[string]struct{})
+	for _, deps := range lock.InstallCache {
+		for _, dep := range deps {
+			if dep.Version == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s@%s", dep.Name, dep.Version.String())
+			if _, ok := added[key]; ok {
+				continue
+			}
+			added[key] = struct{}{}
+			res = append(res, &Component{
+				Kind:    ComponentKindMod,
+				Name:    dep.Name,
+				Version: dep.Version.String(),
+				Source:  fmt.Sprintf("https://%s", dep.Name),
+				Purl:    modPurl(dep.Name, dep.Version.String()),
+			})
+		}
+	}
+	return res, nil
+}
+
+// ociPurl returns the package url for an OCI image, e.g.
+// pkg:oci/aws@sha256%3Aabc?repository_url=us-docker.pkg.dev/steampipe/plugins/turbot/aws
+func ociPurl(imageRef, digest string) string {
+	if imageRef == "" || digest == "" {
+		return ""
+	}
+	repository := imageRef
+	// remove the tag (but not a registry port)
+	if idx := strings.LastIndex(repository, ":"); idx > strings.LastIndex(repository, "/") {
+		repository = repository[:idx]
+	}
+	name := repository[strings.LastIndex(repository, "/")+1:]
+	return fmt.Sprintf("pkg:oci/%s@%s?repository_url=%s", name, url.QueryEscape(digest), repository)
+}
+
+// modPurl returns the package url for a mod, e.g. pkg:github/turbot/steampipe-mod-aws-compliance@v1.0.0
+// (mods which are not hosted on GitHub have a generic package url)
+func modPurl(name, version string) string {
+	if path, ok := strings.CutPrefix(name, "github.com/"); ok {
+		return fmt.Sprintf("pkg:github/%s@v%s", path, version)
+	}
+	return fmt.Sprintf("pkg:generic/%s@v%s", url.QueryEscape(name), version)
+}
+
+// splitDigest splits a digest into its algorithm and hex value, e.g. sha256:abc -> sha256, abc
+func splitDigest(digest string) (string, string, bool) {
+	algorithm, value, found := strings.Cut(digest, ":")
+	if !found || value == "" {
+		return "", "", false
+	}
+	return algorithm, value, true
+}
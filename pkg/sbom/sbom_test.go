@@ -0,0 +1,40 @@
+package sbom
+
+import "testing"
+
+func TestOciPurl(t *testing.T) {
+	tests := []struct {
+		imageRef string
+		digest   string
+		expected string
+	}{
+		{
+			"us-docker.pkg.dev/steampipe/plugins/turbot/aws:latest",
+			"sha256:abc",
+			"pkg:oci/aws@sha256%3Aabc?repository_url=us-docker.pkg.dev/steampipe/plugins/turbot/aws",
+		},
+		{
+			"localhost:5000/plugins/acme/test",
+			"sha256:abc",
+			"pkg:oci/test@sha256%3Aabc?repository_url=localhost:5000/plugins/acme/test",
+		},
+		{"us-docker.pkg.dev/steampipe/plugins/turbot/aws:latest", "", ""},
+	}
+	for _, test := range tests {
+		if got := ociPurl(test.imageRef, test.digest); got != test.expected {
+			t.Errorf("ociPurl(%s, %s): expected %s, got %s", test.imageRef, test.digest, test.expected, got)
+		}
+	}
+}
+
+func TestModPurl(t *testing.T) {
+	tests := map[string]string{
+		"github.com/turbot/steampipe-mod-aws-compliance": "pkg:github/turbot/steampipe-mod-aws-compliance@v1.2.0",
+		"gitlab.com/acme/mod":                            "pkg:generic/gitlab.com%2Facme%2Fmod@v1.2.0",
+	}
+	for name, expected := range tests {
+		if got := modPurl(name, "1.2.0"); got != expected {
+			t.Errorf("modPurl(%s): expected %s, got %s", name, expected, got)
+		}
+	}
+}
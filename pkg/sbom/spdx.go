@@ -0,0 +1,124 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const spdxNoAssertion = "NOASSERTION"
+
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SpdxID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SpdxID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// ToSPDX returns the Sbom as an SPDX 2.3 JSON document
+func (s *Sbom) ToSPDX() ([]byte, error) {
+	doc := spdxDocument{
+		SpdxVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SpdxID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("steampipe-%s", s.CLI.Version),
+		DocumentNamespace: fmt.Sprintf("https://steampipe.io/spdx/steampipe-%s-%s", s.CLI.Version, uuid.New().String()),
+		CreationInfo: spdxCreationInfo{
+			Created:  s.Created.Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: steampipe-%s", s.CLI.Version)},
+		},
+	}
+
+	cliID := "SPDXRef-steampipe"
+	doc.Packages = append(doc.Packages, newSpdxPackage(s.CLI, cliID))
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		SpdxElementID:      doc.SpdxID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSpdxElement: cliID,
+	})
+	for idx, c := range s.Components {
+		id := fmt.Sprintf("SPDXRef-%s-%d", c.Kind, idx)
+		doc.Packages = append(doc.Packages, newSpdxPackage(c, id))
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SpdxElementID:      cliID,
+			RelationshipType:   "CONTAINS",
+			RelatedSpdxElement: id,
+		})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func newSpdxPackage(c *Component, id string) spdxPackage {
+	p := spdxPackage{
+		Name:                  c.Name,
+		SpdxID:                id,
+		VersionInfo:           c.Version,
+		DownloadLocation:      spdxNoAssertion,
+		LicenseConcluded:      spdxNoAssertion,
+		LicenseDeclared:       spdxNoAssertion,
+		CopyrightText:         spdxNoAssertion,
+		PrimaryPackagePurpose: "APPLICATION",
+		Comment:               fmt.Sprintf("steampipe %s", c.Kind),
+	}
+	if c.Kind == ComponentKindMod || c.Kind == ComponentKindFdw {
+		p.PrimaryPackagePurpose = "LIBRARY"
+	}
+	if c.Source != "" {
+		p.DownloadLocation = c.Source
+	}
+	if c.License != "" {
+		p.LicenseDeclared = c.License
+	}
+	if algorithm, value, ok := splitDigest(c.Digest); ok {
+		p.Checksums = []spdxChecksum{{Algorithm: strings.ToUpper(algorithm), ChecksumValue: value}}
+	}
+	if c.Purl != "" {
+		p.ExternalRefs = []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.Purl}}
+	}
+	return p
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/introspection"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+// ScheduleState is the state of a scheduled job, as recorded in the schedule table
+type ScheduleState struct {
+	Name              string     `json:"name" db:"name"`
+	Description       string     `json:"description" db:"description"`
+	IntervalSeconds   int64      `json:"interval_seconds" db:"interval_seconds"`
+	Paused            bool       `json:"paused" db:"paused"`
+	Running           bool       `json:"running" db:"running"`
+	LastRunTime       *time.Time `json:"last_run_time,omitempty" db:"last_run_time"`
+	LastRunDurationMs *int64     `json:"last_run_duration_ms,omitempty" db:"last_run_duration_ms"`
+	LastError         *string    `json:"last_error,omitempty" db:"last_error"`
+	NextRunTime       *time.Time `json:"next_run_time,omitempty" db:"next_run_time"`
+}
+
+// LoadScheduleState loads the state of all scheduled jobs, ordered by name
+func LoadScheduleState(ctx context.Context, conn *pgx.Conn) ([]*ScheduleState, error) {
+	query := fmt.Sprintf(`SELECT name, description, interval_seconds, paused, running, last_run_time, last_run_duration_ms, last_error, next_run_time
+FROM %s.%s ORDER BY name`, constants.InternalSchema, constants.ScheduleTable)
+	rows, err := conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ScheduleState])
+}
+
+// SetPaused pauses or resumes the scheduled job - a paused job is not run until it is resumed (unless it is run now)
+func SetPaused(ctx context.Context, conn *pgx.Conn, name string, paused bool) error {
+	results, err := db_local.ExecuteSqlWithArgsInTransaction(ctx, conn, introspection.GetSchedulePausedSql(name, paused))
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 || results[0].RowsAffected() == 0 {
+		return fmt.Errorf("there is no scheduled job named '%s'", name)
+	}
+	return nil
+}
+
+// RequestRunNow requests that the service runs the scheduled job immediately
+// the job is run asynchronously by the service - the result is recorded in the schedule table
+func RequestRunNow(ctx context.Context, conn *pgx.Conn, name string) error {
+	// verify the job exists
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.%s WHERE name = $1)`, constants.InternalSchema, constants.ScheduleTable)
+	if err := conn.QueryRow(ctx, query, name).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("there is no scheduled job named '%s'", name)
+	}
+	return db_common.SendPostgresCommandNotification(ctx, conn, steampipeconfig.NewScheduleRunNowNotification(name))
+}
@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/introspection"
+	"golang.org/x/exp/maps"
+)
+
+// the interval at which the schedule table is polled for jobs which are due (a var so tests may override it)
+var pollInterval = 10 * time.Second
+
+// Job is a unit of work which the service runs periodically
+type Job struct {
+	Name        string
+	Description string
+	Interval    time.Duration
+	Run         func(context.Context) error
+}
+
+// Scheduler runs the registered jobs at their interval
+// the state of each job is recorded in the schedule table, which is also used to pause jobs
+// - jobs may be inspected, paused or run immediately using the 'steampipe schedule' commands
+type Scheduler struct {
+	pool *pgxpool.Pool
+	jobs map[string]*Job
+	// the jobs which are currently running
+	running map[string]struct{}
+	// the next run time of each job - this is used if the schedule table cannot be read
+	nextRunTimes map[string]time.Time
+	mut          sync.Mutex
+
+	cancel context.CancelFunc
+}
+
+func NewScheduler(pool *pgxpool.Pool) *Scheduler {
+	return &Scheduler{
+		pool:         pool,
+		jobs:         make(map[string]*Job),
+		running:      make(map[string]struct{}),
+		nextRunTimes: make(map[string]time.Time),
+	}
+}
+
+// Register adds a job to the scheduler - this must be called before Start
+func (s *Scheduler) Register(job *Job) {
+	s.jobs[job.Name] = job
+}
+
+// Start registers the jobs in the schedule table and starts a goroutine which runs jobs as they become due
+// the first run of each job is one interval after the scheduler starts
+// if the schedule table cannot be created, the jobs are still run, but cannot be inspected or paused
+func (s *Scheduler) Start(ctx context.Context) {
+	names := maps.Keys(s.jobs)
+	sort.Strings(names)
+
+	s.mut.Lock()
+	for _, name := range names {
+		s.nextRunTimes[name] = time.Now().Add(s.jobs[name].Interval)
+	}
+	s.mut.Unlock()
+
+	queries := []db_common.QueryWithArgs{
+		introspection.GetScheduleTableCreateSql(),
+		introspection.GetScheduleTableGrantSql(),
+		introspection.GetScheduleDeleteUnregisteredSql(names),
+	}
+	for _, name := range names {
+		job := s.jobs[name]
+		queries = append(queries, introspection.GetScheduleUpsertSql(job.Name, job.Description, int64(job.Interval.Seconds()), s.nextRunTimes[name]))
+	}
+	if err := s.executeSql(ctx, queries...); err != nil {
+		log.Printf("[WARN] failed to create the schedule table - scheduled jobs will run, but cannot be inspected or paused: %s", err.Error())
+	}
+
+	ctx, s.cancel = context.WithCancel(context.Background())
+	go s.run(ctx)
+}
+
+// Stop stops the scheduler - jobs which are running are cancelled
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// RunNow runs the job immediately (with the given context), whether or not it is paused
+// if the job is already running, this is a no-op
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	job, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("there is no scheduled job named '%s'", name)
+	}
+	if s.cancel == nil {
+		return fmt.Errorf("the scheduler is not started")
+	}
+	go s.runJob(ctx, job)
+	return nil
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runDueJobs(ctx)
+		}
+	}
+}
+
+// runDueJobs runs all jobs which are not paused and whose next run time has passed
+func (s *Scheduler) runDueJobs(ctx context.Context) {
+	states, err := s.loadScheduleState(ctx)
+	if err != nil {
+		log.Printf("[WARN] scheduler failed to load the schedule state - using the next run times of this process: %s", err.Error())
+		states = s.localScheduleState()
+	}
+
+	for _, state := range states {
+		job, ok := s.jobs[state.Name]
+		if !ok || state.Paused || state.NextRunTime == nil || state.NextRunTime.After(time.Now()) {
+			continue
+		}
+		go s.runJob(ctx, job)
+	}
+}
+
+// runJob runs the job and records the result in the schedule table
+func (s *Scheduler) runJob(ctx context.Context, job *Job) {
+	s.mut.Lock()
+	if _, running := s.running[job.Name]; running {
+		s.mut.Unlock()
+		log.Printf("[TRACE] scheduled job %s is already running", job.Name)
+		return
+	}
+	s.running[job.Name] = struct{}{}
+	s.mut.Unlock()
+	defer func() {
+		s.mut.Lock()
+		delete(s.running, job.Name)
+		s.mut.Unlock()
+	}()
+
+	log.Printf("[INFO] running scheduled job %s", job.Name)
+	if err := s.executeSql(ctx, introspection.GetScheduleRunStartSql(job.Name)); err != nil {
+		log.Printf("[WARN] failed to record the start of scheduled job %s: %s", job.Name, err.Error())
+	}
+
+	startTime := time.Now()
+	var runError *string
+	if err := job.Run(ctx); err != nil {
+		log.Printf("[WARN] scheduled job %s failed: %s", job.Name, err.Error())
+		errString := err.Error()
+		runError = &errString
+	}
+	duration := time.Since(startTime)
+	log.Printf("[INFO] scheduled job %s completed in %s", job.Name, duration)
+
+	nextRunTime := time.Now().Add(job.Interval)
+	s.mut.Lock()
+	s.nextRunTimes[job.Name] = nextRunTime
+	s.mut.Unlock()
+	if err := s.executeSql(ctx, introspection.GetScheduleRunCompleteSql(job.Name, startTime, duration.Milliseconds(), runError, nextRunTime)); err != nil {
+		log.Printf("[WARN] failed to record the result of scheduled job %s: %s", job.Name, err.Error())
+	}
+}
+
+func (s *Scheduler) loadScheduleState(ctx context.Context) ([]*ScheduleState, error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	return LoadScheduleState(ctx, conn.Conn())
+}
+
+// localScheduleState returns the state of the jobs from the next run times recorded by this process
+// (jobs cannot be paused without the schedule table, so none are paused)
+func (s *Scheduler) localScheduleState() []*ScheduleState {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	var res []*ScheduleState
+	for name, nextRunTime := range s.nextRunTimes {
+		nextRunTime := nextRunTime
+		res = append(res, &ScheduleState{Name: name, NextRunTime: &nextRunTime})
+	}
+	return res
+}
+
+func (s *Scheduler) executeSql(ctx context.Context, queries ...db_common.QueryWithArgs) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = db_local.ExecuteSqlWithArgsInTransaction(ctx, conn.Conn(), queries...)
+	return err
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestScheduler returns a scheduler whose database is unavailable, so the schedule table cannot be used
+func newTestScheduler(t *testing.T) *Scheduler {
+	prevPollInterval := pollInterval
+	pollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { pollInterval = prevPollInterval })
+
+	pool, err := pgxpool.New(context.Background(), "postgres://steampipe@127.0.0.1:1/steampipe?connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return NewScheduler(pool)
+}
+
+func TestStartWithoutScheduleTable(t *testing.T) {
+	s := newTestScheduler(t)
+	runs := make(chan struct{}, 10)
+	s.Register(&Job{Name: "job", Interval: time.Millisecond, Run: func(context.Context) error {
+		runs <- struct{}{}
+		return nil
+	}})
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	// the job is run using the next run times of the scheduler, as the schedule table cannot be read
+	select {
+	case <-runs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the job to run when the schedule table is unavailable")
+	}
+}
+
+func TestRunNow(t *testing.T) {
+	s := newTestScheduler(t)
+	result := make(chan error, 1)
+	s.Register(&Job{Name: "job", Interval: time.Hour, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		result <- ctx.Err()
+		return ctx.Err()
+	}})
+
+	if err := s.RunNow(context.Background(), "job"); err == nil {
+		t.Errorf("expected an error running a job before the scheduler is started")
+	}
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	if err := s.RunNow(context.Background(), "unknown"); err == nil {
+		t.Errorf("expected an error running a job which does not exist")
+	}
+
+	// the job is run with the context of the caller
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.RunNow(ctx, "job"); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the job context to be cancelled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the job to be cancelled with the context of the caller")
+	}
+}
+
+func TestRunJobSkipsRunningJob(t *testing.T) {
+	s := newTestScheduler(t)
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	job := &Job{Name: "job", Interval: time.Hour, Run: func(context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	s.Register(job)
+
+	done := make(chan struct{})
+	go func() {
+		s.runJob(context.Background(), job)
+		close(done)
+	}()
+	<-started
+
+	// a second run while the job is running is a no-op
+	s.runJob(context.Background(), job)
+	close(release)
+	<-done
+	if len(started) != 0 {
+		t.Errorf("expected the job to run once, got %d runs", len(started)+1)
+	}
+}
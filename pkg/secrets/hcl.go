@@ -0,0 +1,189 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DefaultSensitiveAttributes are the substrings of attribute names which are encrypted by default
+var DefaultSensitiveAttributes = []string{
+	"password",
+	"passphrase",
+	"secret",
+	"token",
+	"private_key",
+	"access_key",
+	"api_key",
+	"credentials",
+}
+
+// IsSensitiveAttribute returns whether the attribute name contains any of the given sensitive names
+func IsSensitiveAttribute(name string, sensitiveAttributes []string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveAttributes {
+		if strings.Contains(name, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// replacement is a replacement of a byte range of the source
+type replacement struct {
+	start, end int
+	value      []byte
+}
+
+// EncryptHcl encrypts the values of all sensitive attributes in the HCL source
+// only literal string values are encrypted (i.e. not templates, heredocs or references),
+// and values which are already encrypted are left unchanged
+// the rest of the source, including formatting and comments, is preserved
+// returns the updated source and the number of values encrypted
+func EncryptHcl(filename string, src []byte, key *age.X25519Identity, sensitiveAttributes []string) ([]byte, int, error) {
+	tokens, diags := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, 0, diags
+	}
+
+	var replacements []replacement
+	for i := 0; i+3 < len(tokens); i++ {
+		if tokens[i].Type != hclsyntax.TokenIdent ||
+			tokens[i+1].Type != hclsyntax.TokenEqual ||
+			tokens[i+2].Type != hclsyntax.TokenOQuote {
+			continue
+		}
+		// a literal string may be lexed as multiple literal tokens (e.g. if it contains escape sequences)
+		// - find the closing quote, skipping any values which are not plain literals
+		end := i + 3
+		for end < len(tokens) && tokens[end].Type == hclsyntax.TokenQuotedLit {
+			end++
+		}
+		if end == i+3 || end == len(tokens) || tokens[end].Type != hclsyntax.TokenCQuote {
+			continue
+		}
+		if !IsSensitiveAttribute(string(tokens[i].Bytes), sensitiveAttributes) {
+			continue
+		}
+		start, stop := tokens[i+3].Range.Start.Byte, tokens[end-1].Range.End.Byte
+		literal := src[start:stop]
+		if IsEncrypted(string(literal)) {
+			continue
+		}
+		value, err := unescapeQuotedLiteral(literal)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %s", tokens[i+3].Range.String(), err.Error())
+		}
+		encrypted, err := EncryptValue(key, value)
+		if err != nil {
+			return nil, 0, err
+		}
+		replacements = append(replacements, replacement{
+			start: start,
+			end:   stop,
+			value: []byte(encrypted),
+		})
+		i = end
+	}
+	return applyReplacements(src, replacements), len(replacements), nil
+}
+
+// DecryptHcl decrypts all encrypted string literals in the HCL source
+// getKey is only called if the source contains encrypted values
+// returns the updated source and the number of values decrypted
+func DecryptHcl(filename string, src []byte, getKey func() (*age.X25519Identity, error)) ([]byte, int, error) {
+	// avoid lexing files with no encrypted values
+	if !bytes.Contains(src, []byte(EncryptedValuePrefix)) {
+		return src, 0, nil
+	}
+	tokens, diags := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, 0, diags
+	}
+
+	var key *age.X25519Identity
+	var replacements []replacement
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenQuotedLit || !IsEncrypted(string(token.Bytes)) {
+			continue
+		}
+		if key == nil {
+			var err error
+			if key, err = getKey(); err != nil {
+				return nil, 0, err
+			}
+		}
+		value, err := DecryptValue(key, string(token.Bytes))
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %s", token.Range.String(), err.Error())
+		}
+		replacements = append(replacements, replacement{
+			start: token.Range.Start.Byte,
+			end:   token.Range.End.Byte,
+			value: escapeQuotedLiteral(value),
+		})
+	}
+	return applyReplacements(src, replacements), len(replacements), nil
+}
+
+// DecryptFileData decrypts the encrypted values in all the given files
+// this is called when loading config, so encrypted values are transparently decrypted
+func DecryptFileData(fileData map[string][]byte) (map[string][]byte, error) {
+	res := make(map[string][]byte, len(fileData))
+	for filename, data := range fileData {
+		decrypted, _, err := DecryptHcl(filename, data, LoadKey)
+		if err != nil {
+			return nil, err
+		}
+		res[filename] = decrypted
+	}
+	return res, nil
+}
+
+func applyReplacements(src []byte, replacements []replacement) []byte {
+	if len(replacements) == 0 {
+		return src
+	}
+	var res bytes.Buffer
+	pos := 0
+	for _, r := range replacements {
+		res.Write(src[pos:r.start])
+		res.Write(r.value)
+		pos = r.end
+	}
+	res.Write(src[pos:])
+	return res.Bytes()
+}
+
+// unescapeQuotedLiteral returns the string value of a quoted literal
+func unescapeQuotedLiteral(literal []byte) (string, error) {
+	quoted := append(append([]byte{'"'}, literal...), '"')
+	expr, diags := hclsyntax.ParseExpression(quoted, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	if !val.Type().Equals(cty.String) || val.IsNull() {
+		return "", fmt.Errorf("value is not a string literal")
+	}
+	return val.AsString(), nil
+}
+
+// escapeQuotedLiteral returns the escaped quoted literal for the string value
+func escapeQuotedLiteral(value string) []byte {
+	for _, t := range hclwrite.TokensForValue(cty.StringVal(value)) {
+		if t.Type == hclsyntax.TokenQuotedLit {
+			return t.Bytes
+		}
+	}
+	return nil
+}
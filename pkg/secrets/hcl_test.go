@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func newTestKey(t *testing.T) *age.X25519Identity {
+	key, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestEncryptDecryptHcl(t *testing.T) {
+	key := newTestKey(t)
+	getKey := func() (*age.X25519Identity, error) { return key, nil }
+
+	src := `connection "aws" {
+  plugin     = "aws"
+  # the secret key
+  secret_key = "abc\"def\n$${x}"
+  access_key = "AKIA"
+  region     = "us-east-1"
+  token      = "${var.token}"
+}
+`
+	encrypted, count, err := EncryptHcl("test.spc", []byte(src), key, DefaultSensitiveAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 values to be encrypted, got %d\n%s", count, encrypted)
+	}
+	if strings.Contains(string(encrypted), "AKIA") || !strings.Contains(string(encrypted), `region     = "us-east-1"`) || !strings.Contains(string(encrypted), "# the secret key") {
+		t.Fatalf("unexpected encrypted source:\n%s", encrypted)
+	}
+
+	// encrypting again should be a no-op
+	if _, count, err := EncryptHcl("test.spc", encrypted, key, DefaultSensitiveAttributes); err != nil || count != 0 {
+		t.Fatalf("expected re-encrypt to do nothing, got count %d err %v", count, err)
+	}
+
+	decrypted, count, err := DecryptHcl("test.spc", encrypted, getKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 values to be decrypted, got %d", count)
+	}
+	if string(decrypted) != src {
+		t.Fatalf("unexpected decrypted source:\n%s", decrypted)
+	}
+}
+
+func TestDecryptHclWrongKey(t *testing.T) {
+	key := newTestKey(t)
+	otherKey := newTestKey(t)
+	encrypted, _, err := EncryptHcl("test.spc", []byte(`password = "p"`), key, DefaultSensitiveAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := DecryptHcl("test.spc", encrypted, func() (*age.X25519Identity, error) { return otherKey, nil }); err == nil {
+		t.Fatal("expected error decrypting with the wrong key")
+	}
+}
@@ -0,0 +1,279 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+// the key used to encrypt/decrypt config values is an age X25519 identity
+// it is stored in the OS keyring (the macOS keychain, Windows credential manager or the Secret Service on linux)
+// if one is available, otherwise in the key file in the internal directory, encrypted with a passphrase
+// (the key file is an armored age file, encrypted using an age scrypt recipient)
+
+// keyringService is the OS keyring service the key is stored under
+// the keyring user is the install dir, so each install dir has its own key
+const keyringService = "steampipe-config-key"
+
+// scryptLogN is the log2 of the scrypt work factor used to encrypt the key file with the passphrase
+// (a var so tests may use a lower work factor)
+var scryptLogN = 18
+
+// the key is cached once loaded, so the passphrase is only prompted for once per process
+var (
+	loadedKey    *age.X25519Identity
+	loadedKeyMut sync.Mutex
+)
+
+// LoadKey returns the key used to encrypt/decrypt config values
+// the key is read from the STEAMPIPE_CONFIG_KEY env var if set, otherwise from the OS keyring, otherwise from the key file
+// in the internal directory, decrypting it with the passphrase from the STEAMPIPE_CONFIG_PASSPHRASE env var (or prompting for it)
+func LoadKey() (*age.X25519Identity, error) {
+	if encodedKey, ok := os.LookupEnv(constants.EnvConfigKey); ok {
+		key, err := age.ParseX25519Identity(strings.TrimSpace(encodedKey))
+		if err != nil {
+			return nil, sperr.WrapWithMessage(err, "invalid %s", constants.EnvConfigKey)
+		}
+		return key, nil
+	}
+
+	loadedKeyMut.Lock()
+	defer loadedKeyMut.Unlock()
+	if loadedKey != nil {
+		return loadedKey, nil
+	}
+
+	key, err := readKey(true)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, sperr.New("no config encryption key found - set %s or run 'steampipe config encrypt' to create a key", constants.EnvConfigKey)
+	}
+	loadedKey = key
+	return key, nil
+}
+
+// LoadKeyIfAvailable returns the config encryption key if one exists and can be loaded without prompting for the
+// passphrase of the key file, otherwise nil - a key is never created
+// this is used to encrypt cached values, which must not create a key or prompt the user
+func LoadKeyIfAvailable() (*age.X25519Identity, error) {
+	if _, envSet := os.LookupEnv(constants.EnvConfigKey); envSet {
+		return LoadKey()
+	}
+
+	loadedKeyMut.Lock()
+	defer loadedKeyMut.Unlock()
+	if loadedKey != nil {
+		return loadedKey, nil
+	}
+
+	key, err := readKey(false)
+	if err != nil || key == nil {
+		return nil, err
+	}
+	loadedKey = key
+	return key, nil
+}
+
+// EnsureKey returns the config encryption key, creating a new key if no key exists
+// a new key is stored in the OS keyring if available, otherwise in a passphrase protected key file
+// returns whether a key was created
+func EnsureKey() (*age.X25519Identity, bool, error) {
+	if _, envSet := os.LookupEnv(constants.EnvConfigKey); envSet {
+		key, err := LoadKey()
+		return key, false, err
+	}
+
+	loadedKeyMut.Lock()
+	defer loadedKeyMut.Unlock()
+	if loadedKey != nil {
+		return loadedKey, false, nil
+	}
+
+	key, err := readKey(true)
+	if err != nil {
+		return nil, false, err
+	}
+	if key != nil {
+		loadedKey = key
+		return key, false, nil
+	}
+
+	key, err = age.GenerateX25519Identity()
+	if err != nil {
+		return nil, false, err
+	}
+	err = keyring.Set(keyringService, filepaths.SteampipeDir, key.String())
+	if err == nil {
+		loadedKey = key
+		return key, true, nil
+	}
+	log.Printf("[INFO] the OS keyring is not available (%s) - storing the config encryption key in %s", err.Error(), filepaths.ConfigKeyFilePath())
+
+	passphrase, err := getPassphrase(true)
+	if err != nil {
+		return nil, false, err
+	}
+	content, err := protectKey(key, passphrase)
+	if err != nil {
+		return nil, false, err
+	}
+	// only the current user may read the key
+	if err := os.WriteFile(filepaths.ConfigKeyFilePath(), content, 0600); err != nil {
+		return nil, false, sperr.WrapWithMessage(err, "failed to write config encryption key")
+	}
+	loadedKey = key
+	return key, true, nil
+}
+
+// KeyLocation returns a description of where the config encryption key is stored
+func KeyLocation() string {
+	if _, err := keyring.Get(keyringService, filepaths.SteampipeDir); err == nil {
+		return "the OS keyring"
+	}
+	return filepaths.ConfigKeyFilePath()
+}
+
+// readKey reads the key from the OS keyring or the key file, returning nil if there is no key
+// if interactive is false, the passphrase of the key file is not prompted for - if it is not set in the
+// STEAMPIPE_CONFIG_PASSPHRASE env var, the key file is not read and nil is returned
+func readKey(interactive bool) (*age.X25519Identity, error) {
+	encodedKey, err := keyring.Get(keyringService, filepaths.SteampipeDir)
+	switch {
+	case err == nil:
+		key, err := age.ParseX25519Identity(encodedKey)
+		if err != nil {
+			return nil, sperr.WrapWithMessage(err, "invalid config encryption key in the OS keyring")
+		}
+		return key, nil
+	case !errors.Is(err, keyring.ErrNotFound):
+		log.Printf("[TRACE] failed to read the config encryption key from the OS keyring: %s", err.Error())
+	}
+
+	keyPath := filepaths.ConfigKeyFilePath()
+	if !filehelpers.FileExists(keyPath) {
+		return nil, nil
+	}
+	if _, ok := os.LookupEnv(constants.EnvConfigPassphrase); !ok && !interactive {
+		log.Printf("[TRACE] not reading the config encryption key file as %s is not set", constants.EnvConfigPassphrase)
+		return nil, nil
+	}
+	content, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to read config encryption key")
+	}
+	passphrase, err := getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := unprotectKey(content, passphrase)
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to decrypt the config encryption key file %s", keyPath)
+	}
+	return key, nil
+}
+
+// protectKey returns the key file content for the key - an armored age file encrypted with the passphrase
+func protectKey(key *age.X25519Identity, passphrase string) ([]byte, error) {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	recipient.SetWorkFactor(scryptLogN)
+
+	var buf bytes.Buffer
+	armorWriter := armor.NewWriter(&buf)
+	w, err := age.Encrypt(armorWriter, recipient)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, key.String()); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := armorWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unprotectKey decrypts the key from key file content returned by protectKey
+func unprotectKey(content []byte, passphrase string) (*age.X25519Identity, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.Decrypt(armor.NewReader(bytes.NewReader(content)), identity)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, fmt.Errorf("incorrect passphrase")
+		}
+		return nil, fmt.Errorf("invalid key file: %s", err.Error())
+	}
+	encodedKey, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file: %s", err.Error())
+	}
+	return age.ParseX25519Identity(string(encodedKey))
+}
+
+// getPassphrase returns the passphrase protecting the key file
+// this is read from the STEAMPIPE_CONFIG_PASSPHRASE env var if set, otherwise it is prompted for if stdin is a terminal
+// (when creating a key file, the passphrase must be entered twice)
+func getPassphrase(confirm bool) (string, error) {
+	if passphrase, ok := os.LookupEnv(constants.EnvConfigPassphrase); ok {
+		if passphrase == "" {
+			return "", sperr.New("%s is empty", constants.EnvConfigPassphrase)
+		}
+		return passphrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", sperr.New("the config encryption key file is protected by a passphrase - set %s to provide it", constants.EnvConfigPassphrase)
+	}
+
+	passphrase, err := readPassphrase("Config encryption key passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", sperr.New("the passphrase is empty")
+	}
+	if confirm {
+		confirmed, err := readPassphrase("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if confirmed != passphrase {
+			return "", sperr.New("the passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to read passphrase")
+	}
+	return string(passphrase), nil
+}
@@ -0,0 +1,202 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/zalando/go-keyring"
+)
+
+// setupKeyTest uses a temporary install dir and a low scrypt work factor, restoring them once the test completes
+// if keyringErr is set, the OS keyring is unavailable, returning this error
+func setupKeyTest(t *testing.T, keyringErr error) {
+	prevSteampipeDir, prevLogN := filepaths.SteampipeDir, scryptLogN
+	filepaths.SteampipeDir = t.TempDir()
+	scryptLogN = 10
+	if keyringErr != nil {
+		keyring.MockInitWithError(keyringErr)
+	} else {
+		keyring.MockInit()
+	}
+	t.Setenv(constants.EnvConfigPassphrase, "correct horse battery staple")
+	t.Cleanup(func() {
+		filepaths.SteampipeDir, scryptLogN = prevSteampipeDir, prevLogN
+		loadedKey = nil
+	})
+	loadedKey = nil
+}
+
+func TestProtectKey(t *testing.T) {
+	setupKeyTest(t, nil)
+	key := newTestKey(t)
+
+	content, err := protectKey(key, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), key.String()) {
+		t.Errorf("the key file contains the plaintext key")
+	}
+	got, err := unprotectKey(content, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != key.String() {
+		t.Errorf("expected the decrypted key to match the original key")
+	}
+	if _, err := unprotectKey(content, "wrong passphrase"); err == nil {
+		t.Errorf("expected an error decrypting the key with the wrong passphrase")
+	}
+}
+
+func TestEnsureKeyKeyring(t *testing.T) {
+	setupKeyTest(t, nil)
+
+	key, created, err := EnsureKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Errorf("expected a key to be created")
+	}
+	if stored, err := keyring.Get(keyringService, filepaths.SteampipeDir); err != nil || stored != key.String() {
+		t.Errorf("expected the key to be stored in the OS keyring")
+	}
+	if _, err := os.Stat(filepaths.ConfigKeyFilePath()); err == nil {
+		t.Errorf("expected no key file to be written if the OS keyring is available")
+	}
+	if location := KeyLocation(); location != "the OS keyring" {
+		t.Errorf("expected the key location to be the OS keyring, got %s", location)
+	}
+
+	// the key is loaded from the keyring, without the passphrase
+	loadedKey = nil
+	t.Setenv(constants.EnvConfigPassphrase, "")
+	loaded, err := LoadKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != key.String() {
+		t.Errorf("expected the loaded key to match the created key")
+	}
+}
+
+func TestEnsureKeyFile(t *testing.T) {
+	setupKeyTest(t, errors.New("no keyring"))
+
+	key, created, err := EnsureKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Errorf("expected a key to be created")
+	}
+	content, err := os.ReadFile(filepaths.ConfigKeyFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "-----BEGIN AGE ENCRYPTED FILE-----") {
+		t.Errorf("expected the key file to be an age encrypted file")
+	}
+	if location := KeyLocation(); location != filepaths.ConfigKeyFilePath() {
+		t.Errorf("expected the key location to be the key file, got %s", location)
+	}
+
+	// the key file is decrypted with the passphrase
+	loadedKey = nil
+	loaded, err := LoadKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != key.String() {
+		t.Errorf("expected the loaded key to match the created key")
+	}
+
+	// the existing key is not replaced
+	loadedKey = nil
+	ensured, created, err := EnsureKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || ensured.String() != key.String() {
+		t.Errorf("expected the existing key to be kept")
+	}
+
+	// the wrong passphrase is an error
+	loadedKey = nil
+	t.Setenv(constants.EnvConfigPassphrase, "wrong")
+	if _, err := LoadKey(); err == nil {
+		t.Errorf("expected an error loading the key with the wrong passphrase")
+	}
+}
+
+func TestLoadKeyEnv(t *testing.T) {
+	setupKeyTest(t, nil)
+	key := newTestKey(t)
+
+	t.Setenv(constants.EnvConfigKey, key.String())
+	loaded, err := LoadKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != key.String() {
+		t.Errorf("expected the key to be read from %s", constants.EnvConfigKey)
+	}
+
+	t.Setenv(constants.EnvConfigKey, "invalid")
+	if _, err := LoadKey(); err == nil {
+		t.Errorf("expected an error for an invalid %s", constants.EnvConfigKey)
+	}
+}
+
+func TestLoadKeyIfAvailable(t *testing.T) {
+	tests := map[string]struct {
+		keyringErr error
+		// create a key before loading it
+		createKey bool
+		// unset the passphrase env var before loading the key
+		unsetPassphrase bool
+		expectKey       bool
+	}{
+		"no key":                              {},
+		"no key or keyring":                   {keyringErr: errors.New("no keyring")},
+		"keyring key":                         {createKey: true, unsetPassphrase: true, expectKey: true},
+		"key file with passphrase env var":    {keyringErr: errors.New("no keyring"), createKey: true, expectKey: true},
+		"key file without passphrase env var": {keyringErr: errors.New("no keyring"), createKey: true, unsetPassphrase: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setupKeyTest(t, test.keyringErr)
+			if test.createKey {
+				if _, _, err := EnsureKey(); err != nil {
+					t.Fatal(err)
+				}
+				loadedKey = nil
+			}
+			if test.unsetPassphrase {
+				os.Unsetenv(constants.EnvConfigPassphrase)
+			}
+
+			key, err := LoadKeyIfAvailable()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if (key != nil) != test.expectKey {
+				t.Errorf("expected key: %v, got %v", test.expectKey, key)
+			}
+			// a key is never created
+			if !test.createKey {
+				if _, err := keyring.Get(keyringService, filepaths.SteampipeDir); err == nil {
+					t.Errorf("expected no key to be stored in the OS keyring")
+				}
+				if _, err := os.Stat(filepaths.ConfigKeyFilePath()); err == nil {
+					t.Errorf("expected no key file to be written")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// EncryptedValuePrefix is the prefix of encrypted config values
+const EncryptedValuePrefix = "enc:age:"
+
+// IsEncrypted returns whether the value is an encrypted config value
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedValuePrefix)
+}
+
+// EncryptValue encrypts the value to the key using age, returning the prefixed, base64 encoded age ciphertext
+func EncryptValue(key *age.X25519Identity, value string) (string, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, key.Recipient())
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return EncryptedValuePrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecryptValue decrypts a value returned by EncryptValue
+func DecryptValue(key *age.X25519Identity, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedValuePrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %s", err.Error())
+	}
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), key)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return "", fmt.Errorf("failed to decrypt value - was it encrypted with a different key?")
+		}
+		return "", fmt.Errorf("invalid encrypted value: %s", err.Error())
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %s", err.Error())
+	}
+	return string(plaintext), nil
+}
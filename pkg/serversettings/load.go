@@ -30,6 +30,7 @@ func Load(ctx context.Context, pool *pgxpool.Pool) (serverSettings *db_common.Se
 	}
 	defer rows.Close()
 
-	serverSettings, e = pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[db_common.ServerSettings])
+	// use the lax version so settings tables created by older services (which may not have all columns) can be loaded
+	serverSettings, e = pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByNameLax[db_common.ServerSettings])
 	return
 }
@@ -16,8 +16,9 @@ steampipe_version,
 fdw_version,
 cache_max_ttl,
 cache_max_size_mb,
-cache_enabled)
-	VALUES($1,$2,$3,$4,$5,$6)`, constants.InternalSchema, constants.ServerSettingsTable),
+cache_enabled,
+max_query_duration)
+	VALUES($1,$2,$3,$4,$5,$6,$7)`, constants.InternalSchema, constants.ServerSettingsTable),
 		Args: []any{
 			settings.StartTime,
 			settings.SteampipeVersion,
@@ -25,6 +26,7 @@ cache_enabled)
 			settings.CacheMaxTtl,
 			settings.CacheMaxSizeMb,
 			settings.CacheEnabled,
+			settings.MaxQueryDuration,
 		},
 	}
 }
@@ -37,7 +39,8 @@ steampipe_version TEXT NOT NULL,
 fdw_version TEXT NOT NULL,
 cache_max_ttl INTEGER NOT NULL,
 cache_max_size_mb INTEGER NOT NULL,
-cache_enabled BOOLEAN NOT NULL
+cache_enabled BOOLEAN NOT NULL,
+max_query_duration INTEGER NOT NULL
 		);`, constants.InternalSchema, constants.ServerSettingsTable),
 	}
 }
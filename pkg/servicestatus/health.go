@@ -0,0 +1,161 @@
+package servicestatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardserver"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+)
+
+// the overall status of the service
+const (
+	// the database, plugin manager and all connections are running without error
+	HealthStatusHealthy = "healthy"
+	// the service is running, but some part of it is not - e.g. the plugin manager is not running,
+	// a connection is in error or the status could not be retrieved
+	HealthStatusDegraded     = "degraded"
+	HealthStatusStopped      = "stopped"
+	HealthStatusNotInstalled = "not_installed"
+)
+
+// the connection states which are always included in the health, so monitoring agents may rely on their presence
+var healthConnectionStates = []string{
+	constants.ConnectionStatePending,
+	constants.ConnectionStatePendingIncomplete,
+	constants.ConnectionStateReady,
+	constants.ConnectionStateUpdating,
+	constants.ConnectionStateDeleting,
+	constants.ConnectionStateDisabled,
+	constants.ConnectionStateError,
+}
+
+// Health is the structured status of the service, for consumption by monitoring agents
+type Health struct {
+	Status        string              `json:"status"`
+	Time          time.Time           `json:"time"`
+	Database      DatabaseHealth      `json:"database"`
+	PluginManager PluginManagerHealth `json:"plugin_manager"`
+	Dashboard     DashboardHealth     `json:"dashboard"`
+	// count of connections in each state
+	Connections map[string]int `json:"connections"`
+	// have all connections finished loading (i.e. none are pending or updating)
+	ConnectionsLoaded bool                      `json:"connections_loaded"`
+	Pool              *db_local.PoolUtilization `json:"pool,omitempty"`
+	// errors retrieving any part of the status
+	Errors []string `json:"errors"`
+}
+
+type DatabaseHealth struct {
+	Running             bool       `json:"running"`
+	Pid                 int        `json:"pid,omitempty"`
+	Port                int        `json:"port,omitempty"`
+	Listen              []string   `json:"listen,omitempty"`
+	Database            string     `json:"database,omitempty"`
+	StartTime           *time.Time `json:"start_time,omitempty"`
+	UptimeSeconds       int64      `json:"uptime_seconds,omitempty"`
+	BufferCacheHitRatio float64    `json:"buffer_cache_hit_ratio,omitempty"`
+	Version             string     `json:"version,omitempty"`
+	FdwVersion          string     `json:"fdw_version,omitempty"`
+}
+
+type PluginManagerHealth struct {
+	Running bool           `json:"running"`
+	Pid     int            `json:"pid,omitempty"`
+	Plugins []PluginHealth `json:"plugins"`
+}
+
+type PluginHealth struct {
+	Pid         int32  `json:"pid"`
+	Plugin      string `json:"plugin"`
+	MemoryBytes uint64 `json:"memory_bytes"`
+}
+
+type DashboardHealth struct {
+	Running bool `json:"running"`
+	Pid     int  `json:"pid,omitempty"`
+	Port    int  `json:"port,omitempty"`
+}
+
+// GetHealth returns the structured status of the service
+// errors retrieving any part of the status are included in the health (and degrade its status), rather than returned
+func GetHealth(ctx context.Context) *Health {
+	if !db_local.IsDBInstalled() || !db_local.IsFDWInstalled() {
+		return newHealth(time.Now(), HealthStatusNotInstalled)
+	}
+	return getSnapshot(ctx).health()
+}
+
+func newHealth(t time.Time, status string) *Health {
+	h := &Health{
+		Status:        status,
+		Time:          t,
+		PluginManager: PluginManagerHealth{Plugins: []PluginHealth{}},
+		Connections:   make(map[string]int, len(healthConnectionStates)),
+		Errors:        []string{},
+	}
+	for _, state := range healthConnectionStates {
+		h.Connections[state] = 0
+	}
+	return h
+}
+
+func (s *snapshot) health() *Health {
+	h := newHealth(s.time, "")
+	h.Errors = append(h.Errors, s.errors...)
+
+	if s.dbState != nil {
+		h.Database = DatabaseHealth{
+			Running:  true,
+			Pid:      s.dbState.Pid,
+			Port:     s.dbState.Port,
+			Listen:   s.dbState.ResolvedListenAddresses,
+			Database: s.dbState.Database,
+		}
+		if versions, err := versionfile.LoadDatabaseVersionFile(); err != nil {
+			h.Errors = append(h.Errors, "failed to load database version file: "+err.Error())
+		} else {
+			h.Database.Version = versions.EmbeddedDB.Version
+			h.Database.FdwVersion = versions.FdwExtension.Version
+		}
+	}
+	if s.stats != nil {
+		startTime := s.stats.StartTime
+		h.Database.StartTime = &startTime
+		h.Database.UptimeSeconds = int64(s.time.Sub(startTime).Seconds())
+		h.Database.BufferCacheHitRatio = s.stats.BufferCacheHitRatio
+		for state, count := range s.stats.ConnectionSummary {
+			h.Connections[state] = count
+		}
+		h.ConnectionsLoaded = s.stats.ConnectionsLoaded
+		pool := s.stats.Pool
+		h.Pool = &pool
+	}
+	if s.pmState != nil && s.pmState.Running {
+		h.PluginManager.Running = true
+		h.PluginManager.Pid = s.pmState.Pid
+		for _, p := range s.plugins {
+			h.PluginManager.Plugins = append(h.PluginManager.Plugins, PluginHealth{Pid: p.Pid, Plugin: p.Plugin, MemoryBytes: p.MemoryBytes})
+		}
+	}
+	if s.dashboardState != nil && s.dashboardState.State == dashboardserver.ServiceStateRunning {
+		h.Dashboard = DashboardHealth{Running: true, Pid: s.dashboardState.Pid, Port: s.dashboardState.Port}
+	}
+
+	h.Status = getHealthStatus(h)
+	return h
+}
+
+// getHealthStatus returns the overall status of the service
+// (the dashboard is optional, so does not affect the status)
+func getHealthStatus(h *Health) string {
+	if !h.Database.Running && !h.PluginManager.Running {
+		return HealthStatusStopped
+	}
+	if !h.Database.Running || !h.PluginManager.Running || len(h.Errors) > 0 || h.Connections[constants.ConnectionStateError] > 0 {
+		return HealthStatusDegraded
+	}
+	return HealthStatusHealthy
+}
@@ -0,0 +1,84 @@
+package servicestatus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+)
+
+func TestSnapshotHealth(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+	now := time.Now()
+	runningStats := func(summary steampipeconfig.ConnectionStateSummary) *db_local.ServiceStats {
+		return &db_local.ServiceStats{
+			StartTime:         now.Add(-time.Hour),
+			ConnectionSummary: summary,
+			ConnectionsLoaded: true,
+			Pool:              db_local.PoolUtilization{Sessions: 5, MaxConnections: 100, Utilization: 0.05},
+		}
+	}
+	tests := map[string]struct {
+		snapshot *snapshot
+		want     string
+	}{
+		"stopped": {
+			snapshot: &snapshot{time: now},
+			want:     HealthStatusStopped,
+		},
+		"healthy": {
+			snapshot: &snapshot{
+				time:    now,
+				dbState: &db_local.RunningDBInstanceInfo{Pid: 1, Port: 9193},
+				pmState: &pluginmanager.State{Pid: 2, Running: true},
+				stats:   runningStats(steampipeconfig.ConnectionStateSummary{constants.ConnectionStateReady: 3}),
+			},
+			want: HealthStatusHealthy,
+		},
+		"connection in error": {
+			snapshot: &snapshot{
+				time:    now,
+				dbState: &db_local.RunningDBInstanceInfo{Pid: 1, Port: 9193},
+				pmState: &pluginmanager.State{Pid: 2, Running: true},
+				stats:   runningStats(steampipeconfig.ConnectionStateSummary{constants.ConnectionStateReady: 2, constants.ConnectionStateError: 1}),
+			},
+			want: HealthStatusDegraded,
+		},
+		"plugin manager not running": {
+			snapshot: &snapshot{
+				time:    now,
+				dbState: &db_local.RunningDBInstanceInfo{Pid: 1, Port: 9193},
+				stats:   runningStats(nil),
+			},
+			want: HealthStatusDegraded,
+		},
+		"stats failed": {
+			snapshot: &snapshot{
+				time:    now,
+				dbState: &db_local.RunningDBInstanceInfo{Pid: 1, Port: 9193},
+				pmState: &pluginmanager.State{Pid: 2, Running: true},
+				errors:  []string{"failed to get service stats"},
+			},
+			want: HealthStatusDegraded,
+		},
+	}
+	for name, test := range tests {
+		h := test.snapshot.health()
+		if h.Status != test.want {
+			t.Errorf("%s: expected status %s, got %s", name, test.want, h.Status)
+		}
+		// every connection state is reported, even if no connection is in that state
+		if _, ok := h.Connections[constants.ConnectionStateUpdating]; !ok {
+			t.Errorf("%s: expected the updating connection count to be reported", name)
+		}
+		if test.snapshot.stats != nil && h.Database.UptimeSeconds != 3600 {
+			t.Errorf("%s: expected uptime of 3600 seconds, got %d", name, h.Database.UptimeSeconds)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package servicestatus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardserver"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/pluginmanager"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+const (
+	// how often the status is refreshed
+	WatchInterval = 2 * time.Second
+	// the maximum length of a query shown in the active queries table
+	maxQueryLength = 80
+
+	clearScreen = "\033[H\033[2J"
+)
+
+// snapshot is the status of the service at a point in time
+type snapshot struct {
+	time           time.Time
+	dbState        *db_local.RunningDBInstanceInfo
+	pmState        *pluginmanager.State
+	dashboardState *dashboardserver.DashboardServiceState
+	plugins        []pluginmanager.PluginProcess
+	stats          *db_local.ServiceStats
+	// errors retrieving any part of the status - these are displayed, rather than stopping the watch
+	errors []string
+}
+
+// Watch displays the service status, active queries, plugin memory usage, postgres buffer cache hit ratio and connection refresh progress,
+// refreshing every interval until the context is cancelled
+func Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s := getSnapshot(ctx)
+		var sb strings.Builder
+		sb.WriteString(clearScreen)
+		s.render(&sb, interval)
+		fmt.Fprint(os.Stdout, sb.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func getSnapshot(ctx context.Context) *snapshot {
+	s := &snapshot{time: time.Now()}
+
+	var err error
+	if s.dbState, err = db_local.GetState(); err != nil {
+		s.addError("failed to get database state: %s", err.Error())
+	}
+	if s.pmState, err = pluginmanager.LoadState(); err != nil {
+		s.addError("failed to get plugin manager state: %s", err.Error())
+	}
+	if s.dashboardState, err = dashboardserver.GetDashboardServiceState(); err != nil {
+		s.addError("failed to get dashboard state: %s", err.Error())
+	}
+
+	if s.pmState != nil {
+		if s.plugins, err = s.pmState.GetPluginProcesses(); err != nil {
+			s.addError("failed to get plugin processes: %s", err.Error())
+		}
+	}
+	if s.dbState != nil {
+		if s.stats, err = db_local.GetServiceStats(ctx, s.dbState.Database); err != nil && ctx.Err() == nil {
+			s.addError("failed to get service stats: %s", err.Error())
+		}
+	}
+	return s
+}
+
+func (s *snapshot) addError(format string, args ...any) {
+	s.errors = append(s.errors, fmt.Sprintf(format, args...))
+}
+
+func (s *snapshot) render(w io.Writer, interval time.Duration) {
+	fmt.Fprintf(w, "%s  %s\n", constants.Bold("Steampipe service"), s.time.Format(time.TimeOnly))
+	fmt.Fprintf(w, "Refreshing every %s. Press Ctrl+C to exit.\n\n", interval)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if s.dbState != nil && s.stats != nil {
+		fmt.Fprintf(tw, "Database:\trunning (pid %d, port %d, up %s)\n", s.dbState.Pid, s.dbState.Port, s.time.Sub(s.stats.StartTime).Round(time.Second))
+	} else if s.dbState != nil {
+		fmt.Fprintf(tw, "Database:\trunning (pid %d, port %d)\n", s.dbState.Pid, s.dbState.Port)
+	} else {
+		fmt.Fprintf(tw, "Database:\tnot running\n")
+	}
+	if s.pmState != nil && s.pmState.Running {
+		fmt.Fprintf(tw, "Plugin manager:\trunning (pid %d)\n", s.pmState.Pid)
+	} else {
+		fmt.Fprintf(tw, "Plugin manager:\tnot running\n")
+	}
+	if s.dashboardState != nil && s.dashboardState.State == dashboardserver.ServiceStateRunning {
+		fmt.Fprintf(tw, "Dashboard:\trunning (pid %d, port %d)\n", s.dashboardState.Pid, s.dashboardState.Port)
+	} else {
+		fmt.Fprintf(tw, "Dashboard:\tnot running\n")
+	}
+	if s.stats != nil {
+		fmt.Fprintf(tw, "Connections:\t%s\n", connectionSummaryString(s.stats))
+		fmt.Fprintf(tw, "Buffer cache hit ratio:\t%.1f%%\n", s.stats.BufferCacheHitRatio*100)
+		fmt.Fprintf(tw, "Sessions:\t%d of %d (%d active, %d idle)\n", s.stats.Pool.Sessions, s.stats.Pool.MaxConnections, s.stats.Pool.Active, s.stats.Pool.Idle)
+	}
+	tw.Flush()
+
+	s.renderPlugins(w)
+	s.renderActiveQueries(w)
+
+	for _, e := range s.errors {
+		fmt.Fprintf(w, "\n%s: %s", constants.ColoredWarn, e)
+	}
+	if len(s.errors) > 0 {
+		fmt.Fprintln(w)
+	}
+}
+
+func (s *snapshot) renderPlugins(w io.Writer) {
+	fmt.Fprintf(w, "\n%s\n", constants.Bold("Plugins"))
+	if len(s.plugins) == 0 {
+		fmt.Fprintln(w, "No plugins running")
+		return
+	}
+	// show the plugins using the most memory first
+	sort.Slice(s.plugins, func(i, j int) bool {
+		return s.plugins[i].MemoryBytes > s.plugins[j].MemoryBytes
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tMEMORY\tPLUGIN")
+	var total uint64
+	for _, p := range s.plugins {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", p.Pid, formatBytes(p.MemoryBytes), p.Plugin)
+		total += p.MemoryBytes
+	}
+	fmt.Fprintf(tw, "\t%s\t(total)\n", formatBytes(total))
+	tw.Flush()
+}
+
+func (s *snapshot) renderActiveQueries(w io.Writer) {
+	fmt.Fprintf(w, "\n%s\n", constants.Bold("Active queries"))
+	if s.stats == nil || len(s.stats.ActiveQueries) == 0 {
+		fmt.Fprintln(w, "No active queries")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tDURATION\tSTATE\tAPPLICATION\tQUERY")
+	for _, q := range s.stats.ActiveQueries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", q.Pid, q.Duration.Round(100*time.Millisecond), q.State, q.ApplicationName, formatQuery(q.Query))
+	}
+	tw.Flush()
+}
+
+func connectionSummaryString(stats *db_local.ServiceStats) string {
+	var counts []string
+	for _, state := range utils.SortedMapKeys(stats.ConnectionSummary) {
+		counts = append(counts, fmt.Sprintf("%d %s", stats.ConnectionSummary[state], state))
+	}
+	str := strings.Join(counts, ", ")
+	if str == "" {
+		str = "none"
+	}
+	if !stats.ConnectionsLoaded && stats.LoadingMessage != "" {
+		str += fmt.Sprintf(" - %s", stats.LoadingMessage)
+	}
+	return str
+}
+
+// formatQuery collapses the query onto a single line and truncates it to maxQueryLength
+func formatQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxQueryLength {
+		query = query[:maxQueryLength-3] + "..."
+	}
+	return query
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,66 @@
+package statestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// Executor executes statements against the database - it is implemented by both *pgx.Conn and *pgxpool.Pool
+type Executor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// DatabaseStore saves the state in the state table of the internal schema, so it is shared by every node
+// of a deployment which uses the same database
+// node local state (e.g. the plugin versions) is saved in the file store
+type DatabaseStore struct {
+	db    Executor
+	files *FileStore
+}
+
+func NewDatabaseStore(db Executor) *DatabaseStore {
+	return &DatabaseStore{db: db, files: NewFileStore()}
+}
+
+func (s *DatabaseStore) Load(ctx context.Context, key string) ([]byte, error) {
+	if isNodeLocal(key) {
+		return s.files.Load(ctx, key)
+	}
+	query := fmt.Sprintf(`SELECT content FROM %s.%s WHERE key = $1`, constants.InternalSchema, constants.StateTable)
+	var content string
+	err := s.db.QueryRow(ctx, query, key).Scan(&content)
+	if err != nil {
+		// the table will not exist if the service has not been started by this version
+		if errors.Is(err, pgx.ErrNoRows) || db_common.IsRelationNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+func (s *DatabaseStore) Save(ctx context.Context, key string, content []byte) error {
+	if isNodeLocal(key) {
+		return s.files.Save(ctx, key, content)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s.%s (key, content, updated_at) VALUES ($1, $2, now())
+ON CONFLICT (key) DO UPDATE SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at`, constants.InternalSchema, constants.StateTable)
+	_, err := s.db.Exec(ctx, query, key, string(content))
+	return err
+}
+
+func (s *DatabaseStore) Delete(ctx context.Context, key string) error {
+	if isNodeLocal(key) {
+		return s.files.Delete(ctx, key)
+	}
+	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE key = $1`, constants.InternalSchema, constants.StateTable)
+	_, err := s.db.Exec(ctx, query, key)
+	return err
+}
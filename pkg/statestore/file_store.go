@@ -0,0 +1,65 @@
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+// FileStore saves the state in files in the internal directory of the install dir
+type FileStore struct{}
+
+func NewFileStore() *FileStore {
+	return &FileStore{}
+}
+
+func (s *FileStore) Load(_ context.Context, key string) ([]byte, error) {
+	path, err := filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return content, nil
+}
+
+func (s *FileStore) Save(_ context.Context, key string, content []byte) error {
+	path, err := filePath(key)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	path, err := filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// filePath returns the path of the file the state for the key is saved in
+func filePath(key string) (string, error) {
+	switch key {
+	case KeyConnectionState:
+		return filepaths.ConnectionStatePath(), nil
+	case KeyRefreshConnectionsReport:
+		return filepaths.RefreshConnectionsReportPath(), nil
+	case KeyRefreshConnectionsHash:
+		return filepaths.RefreshConnectionsHashPath(), nil
+	case KeyPluginVersions:
+		return filepaths.PluginVersionFilePath(), nil
+	}
+	return "", fmt.Errorf("unknown state key: '%s'", key)
+}
@@ -0,0 +1,63 @@
+package statestore
+
+import (
+	"context"
+	"log"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// the keys of the state saved by the service
+const (
+	// KeyConnectionState is the state of every connection, saved when a connection refresh completes
+	KeyConnectionState = "connection_state"
+	// KeyRefreshConnectionsReport is the result of the last connection refresh
+	KeyRefreshConnectionsReport = "refresh_connections_report"
+	// KeyRefreshConnectionsHash is the hash of the inputs of the last successful connection refresh
+	KeyRefreshConnectionsHash = "refresh_connections_hash"
+	// KeyPluginVersions is the versions of the installed plugins, saved when plugins are installed or updated
+	// (this is node local state - see nodeLocalKeys)
+	KeyPluginVersions = "plugin_versions"
+)
+
+// the keys of the state which is specific to a node, so is always saved in the file store
+// plugins are installed by each node, so the versions of the installed plugins must not be shared
+var nodeLocalKeys = map[string]struct{}{
+	KeyPluginVersions: {},
+}
+
+func isNodeLocal(key string) bool {
+	_, ok := nodeLocalKeys[key]
+	return ok
+}
+
+// Store saves the state of the service between refreshes and restarts
+//
+// by default the state is saved in files in the install dir - a deployment with multiple nodes sharing
+// a database should use the database store, so the nodes do not each keep (and act on) their own copy
+type Store interface {
+	// Load returns the content saved for the key - if nothing is saved, nil is returned
+	Load(ctx context.Context, key string) ([]byte, error)
+	Save(ctx context.Context, key string, content []byte) error
+	// Delete deletes the content saved for the key - it is not an error if nothing is saved
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the store configured by the state_storage database option
+// the database store uses the given executor, which must be able to write to the internal schema
+// (if no executor is given, the file store is returned)
+func New(db Executor) Store {
+	storage := viper.GetString(constants.ArgStateStorage)
+	switch storage {
+	case constants.StateStorageDatabase:
+		if db != nil {
+			return NewDatabaseStore(db)
+		}
+		log.Printf("[WARN] no database connection is available for the state store - using the file store")
+	case constants.StateStorageFile, "":
+	default:
+		log.Printf("[WARN] invalid value of 'state_storage' (%s) - using the file store", storage)
+	}
+	return NewFileStore()
+}
@@ -0,0 +1,121 @@
+package statestore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestFileStore(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+	ctx := context.Background()
+	store := NewFileStore()
+
+	// nothing is saved
+	if content, err := store.Load(ctx, KeyRefreshConnectionsHash); err != nil || content != nil {
+		t.Fatalf("expected nothing to be loaded, got %q, %v", content, err)
+	}
+	if err := store.Delete(ctx, KeyRefreshConnectionsHash); err != nil {
+		t.Fatalf("expected deleting unsaved state to succeed: %v", err)
+	}
+
+	if err := store.Save(ctx, KeyRefreshConnectionsHash, []byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := store.Load(ctx, KeyRefreshConnectionsHash); err != nil || string(content) != "abc" {
+		t.Fatalf("expected the saved state to be loaded, got %q, %v", content, err)
+	}
+
+	if err := store.Delete(ctx, KeyRefreshConnectionsHash); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := store.Load(ctx, KeyRefreshConnectionsHash); err != nil || content != nil {
+		t.Fatalf("expected the deleted state not to be loaded, got %q, %v", content, err)
+	}
+
+	// the plugin versions are saved in the plugin version file
+	if err := store.Save(ctx, KeyPluginVersions, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(filepaths.PluginVersionFilePath()); err != nil || string(content) != "{}" {
+		t.Errorf("expected the plugin versions to be saved in the plugin version file, got %q, %v", content, err)
+	}
+
+	if err := store.Save(ctx, "unknown", []byte("abc")); err == nil {
+		t.Errorf("expected an error saving an unknown key")
+	}
+}
+
+// testExecutor is an Executor which is never called (its QueryRow returns a nil row)
+type testExecutor struct{}
+
+func (testExecutor) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (testExecutor) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	defer viper.Reset()
+	tests := map[string]struct {
+		storage string
+		db      Executor
+		want    Store
+	}{
+		"default":                {storage: "", want: &FileStore{}},
+		"file":                   {storage: constants.StateStorageFile, want: &FileStore{}},
+		"invalid":                {storage: "s3", want: &FileStore{}},
+		"database, no executor":  {storage: constants.StateStorageDatabase, want: &FileStore{}},
+		"database with executor": {storage: constants.StateStorageDatabase, db: testExecutor{}, want: &DatabaseStore{}},
+	}
+	for name, test := range tests {
+		viper.Set(constants.ArgStateStorage, test.storage)
+		got := New(test.db)
+		switch test.want.(type) {
+		case *FileStore:
+			if _, ok := got.(*FileStore); !ok {
+				t.Errorf("%s: expected a file store, got %T", name, got)
+			}
+		case *DatabaseStore:
+			if _, ok := got.(*DatabaseStore); !ok {
+				t.Errorf("%s: expected a database store, got %T", name, got)
+			}
+		}
+	}
+}
+
+func TestDatabaseStoreNodeLocalState(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+	ctx := context.Background()
+	// the executor returns a nil row - loading from the database would panic
+	store := NewDatabaseStore(testExecutor{})
+
+	// the plugin versions are node local, so are saved in the plugin version file rather than the database
+	if err := store.Save(ctx, KeyPluginVersions, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(filepaths.PluginVersionFilePath()); err != nil || string(content) != "{}" {
+		t.Errorf("expected the plugin versions to be saved in the plugin version file, got %q, %v", content, err)
+	}
+	if content, err := store.Load(ctx, KeyPluginVersions); err != nil || string(content) != "{}" {
+		t.Errorf("expected the plugin versions to be loaded from the plugin version file, got %q, %v", content, err)
+	}
+	if err := store.Delete(ctx, KeyPluginVersions); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepaths.PluginVersionFilePath()); !os.IsNotExist(err) {
+		t.Errorf("expected the plugin version file to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package statushooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// the minimum interval between progress lines for the same status
+const plainProgressInterval = 5 * time.Second
+
+// PlainStatusHook is a struct which implements StatusHooks, and writes status messages as plain text lines
+// this is used in place of the spinner when the output is not a terminal, e.g. in CI logs
+//
+// a line is written whenever the status changes - progress updates are written at most every plainProgressInterval
+type PlainStatusHook struct {
+	writer  io.Writer
+	visible bool
+	// the current status and progress percentage (-1 if there is no progress)
+	status  string
+	percent int
+	// the last status and progress percentage written, and when
+	written        string
+	writtenPercent int
+	writtenTime    time.Time
+	mut            sync.Mutex
+}
+
+func NewPlainStatusHook() *PlainStatusHook {
+	return &PlainStatusHook{
+		// write to stderr so the progress lines do not interleave with command output
+		writer:         os.Stderr,
+		percent:        -1,
+		writtenPercent: -1,
+	}
+}
+
+// SetStatus implements StatusHooks
+func (s *PlainStatusHook) SetStatus(msg string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.status = msg
+	s.percent = -1
+	if s.status != s.written {
+		s.write()
+	}
+}
+
+// SetProgress sets the status, with the percentage of the current step which is complete
+func (s *PlainStatusHook) SetProgress(msg string, complete, total int) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.status = msg
+	s.percent = 0
+	if total > 0 {
+		s.percent = complete * 100 / total
+	}
+	// write the first and final progress of a step, and otherwise periodically
+	if s.status == s.written {
+		return
+	}
+	if s.writtenPercent < 0 || s.percent == 100 || time.Since(s.writtenTime) >= plainProgressInterval {
+		s.write()
+	}
+}
+
+func (s *PlainStatusHook) Show() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.visible = true
+	if s.status != s.written {
+		s.write()
+	}
+}
+
+// Hide implements StatusHooks
+func (s *PlainStatusHook) Hide() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	// write the final status, if it has not already been written
+	if s.status != s.written {
+		s.write()
+	}
+	s.visible = false
+	s.written = ""
+	s.writtenPercent = -1
+}
+
+func (s *PlainStatusHook) Message(msgs ...string) {
+	for _, msg := range msgs {
+		fmt.Println(msg)
+	}
+}
+
+func (s *PlainStatusHook) Warn(msg string) {
+	fmt.Fprintf(color.Output, "%s: %v\n", constants.ColoredWarn, msg)
+}
+
+func (s *PlainStatusHook) write() {
+	if !s.visible || s.status == "" {
+		return
+	}
+	line := s.status
+	if s.percent >= 0 {
+		line = fmt.Sprintf("[%3d%%] %s", s.percent, line)
+	}
+	fmt.Fprintf(s.writer, "%s %s\n", time.Now().Format(time.TimeOnly), line)
+	s.written = s.status
+	s.writtenPercent = s.percent
+	s.writtenTime = time.Now()
+}
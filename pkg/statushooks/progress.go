@@ -0,0 +1,55 @@
+package statushooks
+
+import (
+	"context"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// progressHooks is implemented by status hooks which display the progress of the current step
+type progressHooks interface {
+	SetProgress(msg string, complete, total int)
+}
+
+// SetProgress sets the status message, along with the progress of the current step
+// (for status hooks which do not display progress, this is the same as SetStatus)
+func SetProgress(ctx context.Context, msg string, complete, total int) {
+	hooks := StatusHooksFromContext(ctx)
+	if p, ok := hooks.(progressHooks); ok {
+		p.SetProgress(msg, complete, total)
+		return
+	}
+	hooks.SetStatus(msg)
+}
+
+// ProgressEnabled returns whether progress should be displayed, based on the progress arg
+func ProgressEnabled() bool {
+	switch viper.GetString(constants.ArgProgress) {
+	case "false", constants.ProgressNone:
+		return false
+	}
+	return true
+}
+
+// NewStatusHooksForProgress returns the status hooks to use for the progress arg:
+// - tty: a status spinner
+// - plain: plain text progress lines
+// - none: no status output
+// otherwise, a status spinner if the output is a terminal, and no status output if not
+func NewStatusHooksForProgress() StatusHooks {
+	switch viper.GetString(constants.ArgProgress) {
+	case constants.ProgressTTY:
+		return NewStatusSpinnerHook()
+	case constants.ProgressPlain:
+		return NewPlainStatusHook()
+	case constants.ProgressNone:
+		return NullHooks
+	}
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		return NewStatusSpinnerHook()
+	}
+	return NullHooks
+}
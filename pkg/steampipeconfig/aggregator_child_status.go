@@ -0,0 +1,47 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"sort"
+
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// AggregatorChildStatus returns the child connections of the aggregator connection which are included in the aggregator
+// (i.e. are ready) and the child connections which are excluded, with the reason they are excluded
+// - children which are disabled, in error or not yet loaded are excluded
+// - connections explicitly named by the aggregator which use a different plugin instance are excluded
+func AggregatorChildStatus(aggregator *modconfig.Connection, connectionMap map[string]*modconfig.Connection, stateMap ConnectionStateMap) ([]string, map[string]string) {
+	included := []string{}
+	excluded := make(map[string]string)
+
+	for childName := range aggregator.Connections {
+		state, ok := stateMap[childName]
+		switch {
+		case !ok:
+			excluded[childName] = "connection state not found"
+		case state.Disabled():
+			excluded[childName] = "connection is disabled"
+		case state.State == constants.ConnectionStateError:
+			excluded[childName] = fmt.Sprintf("connection is in error: %s", typehelpers.SafeString(state.ConnectionError))
+		case state.State != constants.ConnectionStateReady:
+			excluded[childName] = fmt.Sprintf("connection is %s", state.State)
+		default:
+			included = append(included, childName)
+		}
+	}
+
+	for _, childName := range aggregator.ConnectionNames {
+		if _, resolved := aggregator.Connections[childName]; resolved {
+			continue
+		}
+		if child, ok := connectionMap[childName]; ok && typehelpers.SafeString(child.PluginInstance) != typehelpers.SafeString(aggregator.PluginInstance) {
+			excluded[childName] = fmt.Sprintf("connection uses plugin instance '%s'", typehelpers.SafeString(child.PluginInstance))
+		}
+	}
+
+	sort.Strings(included)
+	return included, excluded
+}
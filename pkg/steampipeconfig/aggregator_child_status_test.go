@@ -0,0 +1,58 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestAggregatorChildStatus(t *testing.T) {
+	awsInstance := "aws"
+	otherInstance := "aws_other"
+	connectionError := "invalid credentials"
+
+	connectionMap := map[string]*modconfig.Connection{
+		"aws_dev":      {Name: "aws_dev", PluginInstance: &awsInstance},
+		"aws_prod":     {Name: "aws_prod", PluginInstance: &awsInstance},
+		"aws_test":     {Name: "aws_test", PluginInstance: &awsInstance},
+		"aws_sandbox":  {Name: "aws_sandbox", PluginInstance: &awsInstance},
+		"aws_new":      {Name: "aws_new", PluginInstance: &awsInstance},
+		"aws_external": {Name: "aws_external", PluginInstance: &otherInstance},
+	}
+	aggregator := &modconfig.Connection{
+		Name:            "aws_all",
+		PluginInstance:  &awsInstance,
+		ConnectionNames: []string{"aws_*", "aws_external"},
+		Connections: map[string]*modconfig.Connection{
+			"aws_dev":     connectionMap["aws_dev"],
+			"aws_prod":    connectionMap["aws_prod"],
+			"aws_test":    connectionMap["aws_test"],
+			"aws_sandbox": connectionMap["aws_sandbox"],
+			"aws_new":     connectionMap["aws_new"],
+		},
+	}
+	stateMap := ConnectionStateMap{
+		"aws_dev":     {ConnectionName: "aws_dev", State: constants.ConnectionStateReady},
+		"aws_prod":    {ConnectionName: "aws_prod", State: constants.ConnectionStateReady},
+		"aws_test":    {ConnectionName: "aws_test", State: constants.ConnectionStateError, ConnectionError: &connectionError},
+		"aws_sandbox": {ConnectionName: "aws_sandbox", State: constants.ConnectionStateDisabled},
+	}
+
+	included, excluded := AggregatorChildStatus(aggregator, connectionMap, stateMap)
+
+	expectedIncluded := []string{"aws_dev", "aws_prod"}
+	if !reflect.DeepEqual(included, expectedIncluded) {
+		t.Errorf("expected included %v, got %v", expectedIncluded, included)
+	}
+	expectedExcluded := map[string]string{
+		"aws_test":     "connection is in error: invalid credentials",
+		"aws_sandbox":  "connection is disabled",
+		"aws_new":      "connection state not found",
+		"aws_external": "connection uses plugin instance 'aws_other'",
+	}
+	if !reflect.DeepEqual(excluded, expectedExcluded) {
+		t.Errorf("expected excluded %v, got %v", expectedExcluded, excluded)
+	}
+}
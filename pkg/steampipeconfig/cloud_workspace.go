@@ -1,9 +1,52 @@
 package steampipeconfig
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 // IsCloudWorkspaceIdentifier returns whether name is a cloud workspace identifier
 // of the form: {identity_handle}/{workspace_handle},
 func IsCloudWorkspaceIdentifier(name string) bool {
 	return len(strings.Split(name, "/")) == 2
 }
+
+// IsConnectionString returns whether the workspace database is a postgres connection string
+func IsConnectionString(workspaceDatabase string) bool {
+	return strings.HasPrefix(workspaceDatabase, "postgresql://") || strings.HasPrefix(workspaceDatabase, "postgres://")
+}
+
+var workspaceHandleRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+/[a-zA-Z0-9_-]+$`)
+
+// WorkspaceDatabaseEndpoints splits the workspace database into its endpoints
+// the workspace database may be a comma separated list of connection strings and/or {identity_handle}/{workspace_handle}
+// identifiers - the first is the primary endpoint and the remainder are used for failover, in order
+//
+// NOTE: a multi-host connection string (e.g. postgres://host1:9193,host2:9193/db) is kept as a single endpoint,
+// as the fragments following a comma are only treated as a new endpoint if they are a connection string or a workspace identifier
+func WorkspaceDatabaseEndpoints(workspaceDatabase string) []string {
+	var res []string
+	for _, fragment := range strings.Split(workspaceDatabase, ",") {
+		trimmed := strings.TrimSpace(fragment)
+		if trimmed == "" {
+			continue
+		}
+		if len(res) == 0 || IsConnectionString(trimmed) || workspaceHandleRegex.MatchString(trimmed) {
+			res = append(res, trimmed)
+			continue
+		}
+		// this fragment is part of the previous endpoint
+		res[len(res)-1] += "," + trimmed
+	}
+	return res
+}
+
+// IsCloudWorkspaceDatabase returns whether any endpoint of the workspace database is a cloud workspace identifier
+func IsCloudWorkspaceDatabase(workspaceDatabase string) bool {
+	for _, endpoint := range WorkspaceDatabaseEndpoints(workspaceDatabase) {
+		if !IsConnectionString(endpoint) && IsCloudWorkspaceIdentifier(endpoint) {
+			return true
+		}
+	}
+	return false
+}
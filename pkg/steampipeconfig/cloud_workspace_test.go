@@ -0,0 +1,46 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkspaceDatabaseEndpoints(t *testing.T) {
+	tests := map[string]struct {
+		workspaceDatabase string
+		want              []string
+	}{
+		"single workspace": {
+			workspaceDatabase: "acme/prod",
+			want:              []string{"acme/prod"},
+		},
+		"workspaces": {
+			workspaceDatabase: "acme/prod, acme/prod-dr",
+			want:              []string{"acme/prod", "acme/prod-dr"},
+		},
+		"connection strings": {
+			workspaceDatabase: "postgres://u:p@host1:9193/db,postgresql://u:p@host2:9193/db",
+			want:              []string{"postgres://u:p@host1:9193/db", "postgresql://u:p@host2:9193/db"},
+		},
+		"workspace and connection string": {
+			workspaceDatabase: "acme/prod,postgres://u:p@host2:9193/db",
+			want:              []string{"acme/prod", "postgres://u:p@host2:9193/db"},
+		},
+		"multi-host connection string": {
+			workspaceDatabase: "postgres://u:p@host1:9193,host2:9193/db",
+			want:              []string{"postgres://u:p@host1:9193,host2:9193/db"},
+		},
+		"empty endpoints": {
+			workspaceDatabase: "acme/prod,,acme/prod-dr,",
+			want:              []string{"acme/prod", "acme/prod-dr"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := WorkspaceDatabaseEndpoints(test.workspaceDatabase)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("WorkspaceDatabaseEndpoints(%q) = %v, want %v", test.workspaceDatabase, got, test.want)
+			}
+		})
+	}
+}
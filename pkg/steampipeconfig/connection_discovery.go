@@ -0,0 +1,235 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/maps"
+)
+
+// characters which are not valid in a connection name (and are replaced with underscores)
+var invalidConnectionNameCharsRegex = regexp.MustCompile(`[^a-z0-9_]`)
+
+// DiscoveredConnection is a connection created by a connection discovery
+type DiscoveredConnection struct {
+	Name string
+	// the config attributes of the connection, in the order they are defined in the config template
+	Config []*DiscoveredConnectionAttribute
+}
+
+// DiscoveredConnectionAttribute is a config attribute of a discovered connection
+type DiscoveredConnectionAttribute struct {
+	Name  string
+	Value cty.Value
+}
+
+func (c *SteampipeConfig) addConnectionDiscovery(discovery *modconfig.ConnectionDiscovery) error {
+	if c.ConnectionDiscoveries == nil {
+		c.ConnectionDiscoveries = make(map[string]*modconfig.ConnectionDiscovery)
+	}
+	if existing, ok := c.ConnectionDiscoveries[discovery.Name]; ok {
+		return sperr.New("duplicate discovery name: '%s'\n\t(%s:%d)\n\t(%s:%d)",
+			discovery.Name,
+			existing.DeclRange.Filename, existing.DeclRange.Start.Line,
+			discovery.DeclRange.Filename, discovery.DeclRange.Start.Line)
+	}
+	c.ConnectionDiscoveries[discovery.Name] = discovery
+	return nil
+}
+
+// DiscoveredConnectionsFilePath returns the path of the generated config file containing the connections of a discovery
+func DiscoveredConnectionsFilePath(discoveryName string) string {
+	return filepath.Join(filepaths.EnsureConfigDir(), fmt.Sprintf("discovered_%s%s", discoveryName, constants.ConfigExtension))
+}
+
+// DiscoveredConnectionNames returns the names of the connections which are currently defined
+// in the generated config file of the given discovery
+func (c *SteampipeConfig) DiscoveredConnectionNames(discoveryName string) []string {
+	filePath := DiscoveredConnectionsFilePath(discoveryName)
+	var res []string
+	for name, connection := range c.Connections {
+		if connection.DeclRange.Filename == filePath {
+			res = append(res, name)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+// NewDiscoveredConnections renders the name and config templates of the discovery for each discovered item
+// items are the rows returned by the discovery query, keyed by column name
+//
+// the config template is parsed as HCL, and template placeholders are only rendered within its string values
+// - the rendered values are written as escaped HCL values, so a discovered value cannot alter the structure
+// of the generated config (e.g. by adding attributes)
+func NewDiscoveredConnections(discovery *modconfig.ConnectionDiscovery, items []map[string]string) ([]*DiscoveredConnection, error) {
+	configTemplate, err := parseDiscoveryConfigTemplate(discovery)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*DiscoveredConnection
+	names := make(map[string]struct{})
+	configs := make(map[string]struct{})
+	for _, item := range items {
+		// make the name of the discovering connection available to the templates
+		data := map[string]string{"connection": discovery.Connection}
+		for k, v := range item {
+			data[k] = v
+		}
+
+		name, err := renderDiscoveryTemplate(discovery.GetNameTemplate(), data)
+		if err != nil {
+			return nil, sperr.WrapWithMessage(err, "discovery '%s': failed to render connection name", discovery.Name)
+		}
+		connectionName := toConnectionName(name)
+		if ok, message := db_common.IsSchemaNameValid(connectionName); !ok {
+			return nil, sperr.New("discovery '%s': invalid connection name '%s': %s", discovery.Name, connectionName, message)
+		}
+		if _, duplicate := names[connectionName]; duplicate {
+			return nil, sperr.New("discovery '%s': connection name template produces duplicate name '%s'", discovery.Name, connectionName)
+		}
+		names[connectionName] = struct{}{}
+
+		connection := &DiscoveredConnection{Name: connectionName}
+		for _, attr := range configTemplate {
+			value, err := renderDiscoveryConfigValue(attr.Value, data)
+			if err != nil {
+				return nil, sperr.WrapWithMessage(err, "discovery '%s': failed to render config attribute '%s'", discovery.Name, attr.Name)
+			}
+			connection.Config = append(connection.Config, &DiscoveredConnectionAttribute{Name: attr.Name, Value: value})
+		}
+		configs[connection.configKey()] = struct{}{}
+		res = append(res, connection)
+	}
+
+	// if the config of the discovered connections is the same, they would all use the same credentials
+	// - the config template must reference the discovered columns
+	if len(res) > 1 && len(configs) == 1 {
+		return nil, sperr.New("discovery '%s': the config template produces identical config for all discovered connections - it must reference the columns returned by the discovery query, e.g. {{ .id }}", discovery.Name)
+	}
+
+	slices.SortFunc(res, func(a, b *DiscoveredConnection) int { return strings.Compare(a.Name, b.Name) })
+	return res, nil
+}
+
+// parseDiscoveryConfigTemplate parses the config template of the discovery into its attributes, in the order they are defined
+// the template may only contain attributes with literal values
+func parseDiscoveryConfigTemplate(discovery *modconfig.ConnectionDiscovery) ([]*DiscoveredConnectionAttribute, error) {
+	if strings.TrimSpace(discovery.ConfigTemplate) == "" {
+		return nil, sperr.New("discovery '%s': config must be set - otherwise all discovered connections would use the same credentials as connection '%s'", discovery.Name, discovery.Connection)
+	}
+	file, diags := hclsyntax.ParseConfig([]byte(discovery.ConfigTemplate), discovery.DeclRange.Filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, sperr.New("discovery '%s': invalid config template: %s", discovery.Name, diags.Error())
+	}
+	body := file.Body.(*hclsyntax.Body)
+	if len(body.Blocks) > 0 {
+		return nil, sperr.New("discovery '%s': invalid config template: only attributes are supported", discovery.Name)
+	}
+
+	attrs := maps.Values(body.Attributes)
+	slices.SortFunc(attrs, func(a, b *hclsyntax.Attribute) int { return a.SrcRange.Start.Byte - b.SrcRange.Start.Byte })
+
+	var res []*DiscoveredConnectionAttribute
+	for _, attr := range attrs {
+		// the plugin of the discovered connections is always that of the discovering connection
+		if attr.Name == "plugin" {
+			return nil, sperr.New("discovery '%s': invalid config template: 'plugin' may not be set", discovery.Name)
+		}
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, sperr.New("discovery '%s': invalid config template: attribute '%s' must be a literal value: %s", discovery.Name, attr.Name, diags.Error())
+		}
+		res = append(res, &DiscoveredConnectionAttribute{Name: attr.Name, Value: value})
+	}
+	return res, nil
+}
+
+// renderDiscoveryConfigValue renders the template placeholders in all string values within the given value
+func renderDiscoveryConfigValue(value cty.Value, data map[string]string) (cty.Value, error) {
+	return cty.Transform(value, func(_ cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.Type().Equals(cty.String) || v.IsNull() || !v.IsKnown() {
+			return v, nil
+		}
+		rendered, err := renderDiscoveryTemplate(v.AsString(), data)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(rendered), nil
+	})
+}
+
+func renderDiscoveryTemplate(text string, data map[string]string) (string, error) {
+	tmpl, err := template.New("discovery").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// configKey returns a string representation of the config of the connection, used to compare configs
+func (c *DiscoveredConnection) configKey() string {
+	var sb strings.Builder
+	for _, attr := range c.Config {
+		sb.WriteString(attr.Name)
+		sb.WriteString("=")
+		sb.Write(hclwrite.TokensForValue(attr.Value).Bytes())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// BuildDiscoveredConnectionsConfig returns the content of the generated config file for the discovered connections
+// the discovered connections use the same plugin as the discovering connection
+func BuildDiscoveredConnectionsConfig(discovery *modconfig.ConnectionDiscovery, source *modconfig.Connection, connections []*DiscoveredConnection) string {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for _, c := range connections {
+		body.AppendNewline()
+		blockBody := body.AppendNewBlock("connection", []string{c.Name}).Body()
+		// if the discovering connection uses a plugin instance, reference that - otherwise use the plugin name
+		if pluginInstance := typehelpers.SafeString(source.PluginInstance); pluginInstance != "" && pluginInstance != source.Plugin {
+			blockBody.SetAttributeTraversal("plugin", hcl.Traversal{hcl.TraverseRoot{Name: "plugin"}, hcl.TraverseAttr{Name: pluginInstance}})
+		} else {
+			blockBody.SetAttributeValue("plugin", cty.StringVal(source.PluginAlias))
+		}
+		for _, attr := range c.Config {
+			blockBody.SetAttributeValue(attr.Name, attr.Value)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# This file is generated by the '%s' discovery, using connection '%s'.\n", discovery.Name, discovery.Connection))
+	sb.WriteString("# Do not edit - any changes will be overwritten when the discovered connections are next synced.\n")
+	sb.Write(hclwrite.Format(f.Bytes()))
+	return sb.String()
+}
+
+// toConnectionName converts a rendered name into a valid connection name
+func toConnectionName(name string) string {
+	name = invalidConnectionNameCharsRegex.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "_")
+	// names must not start with a digit
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
@@ -0,0 +1,112 @@
+package steampipeconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestNewDiscoveredConnections(t *testing.T) {
+	nameTemplate := "aws_{{ .name }}"
+	discovery := &modconfig.ConnectionDiscovery{
+		Name:           "aws_accounts",
+		Connection:     "aws_org",
+		NameTemplate:   &nameTemplate,
+		ConfigTemplate: "profile = \"{{ .id }}\"\nregions = [\"*\"]",
+	}
+	items := []map[string]string{
+		{"id": "222222222222", "name": "Prod-Account"},
+		{"id": "111111111111", "name": "dev account"},
+	}
+
+	connections, err := NewDiscoveredConnections(discovery, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(connections) != 2 || connections[0].Name != "aws_dev_account" || connections[1].Name != "aws_prod_account" {
+		t.Fatalf("unexpected connections: %v", connections)
+	}
+	config := connections[1].Config
+	if len(config) != 2 || config[0].Name != "profile" || config[0].Value.AsString() != "222222222222" || config[1].Name != "regions" {
+		t.Errorf("unexpected config: %v", config)
+	}
+
+	content := BuildDiscoveredConnectionsConfig(discovery, &modconfig.Connection{PluginAlias: "aws"}, connections)
+	if !strings.Contains(content, "connection \"aws_dev_account\" {\n  plugin  = \"aws\"\n  profile = \"111111111111\"\n") {
+		t.Errorf("unexpected config file content:\n%s", content)
+	}
+}
+
+func TestNewDiscoveredConnectionsErrors(t *testing.T) {
+	// the default name template uses the discovering connection name and the id column
+	discovery := &modconfig.ConnectionDiscovery{Name: "aws_accounts", Connection: "aws_org", ConfigTemplate: `role_arn = "{{ .id }}"`}
+
+	connections, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": "123"}})
+	if err != nil || len(connections) != 1 || connections[0].Name != "aws_org_123" {
+		t.Errorf("unexpected result for default template: %v, %v", connections, err)
+	}
+
+	// the config template produces the same config for all connections, so they would share credentials
+	discovery.ConfigTemplate = `regions = ["*"]`
+	if _, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": "1"}, {"id": "2"}}); err == nil {
+		t.Errorf("expected an error for identical config")
+	}
+
+	// the config template must be set
+	discovery.ConfigTemplate = ""
+	if _, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": "1"}}); err == nil {
+		t.Errorf("expected an error for a missing config template")
+	}
+
+	// the config template may only contain attributes
+	discovery.ConfigTemplate = "options \"connection\" {\n}"
+	if _, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": "1"}}); err == nil {
+		t.Errorf("expected an error for a config template containing a block")
+	}
+	discovery.ConfigTemplate = `role_arn = "{{ .id }}"`
+
+	// the template references a column which was not returned
+	missingColumn := "aws_{{ .alias }}"
+	discovery.NameTemplate = &missingColumn
+	if _, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": "123"}}); err == nil {
+		t.Errorf("expected an error for a template referencing a missing column")
+	}
+
+	// the template produces duplicate names
+	constantName := "aws_account"
+	discovery.NameTemplate = &constantName
+	if _, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": "1"}, {"id": "2"}}); err == nil {
+		t.Errorf("expected an error for duplicate connection names")
+	}
+}
+
+func TestBuildDiscoveredConnectionsConfigEscapesValues(t *testing.T) {
+	discovery := &modconfig.ConnectionDiscovery{
+		Name:           "aws_accounts",
+		Connection:     "aws_org",
+		ConfigTemplate: `profile = "{{ .id }}"`,
+	}
+	// a discovered value which attempts to add an attribute to the generated config
+	value := "1\"\n  role_arn = \"${file(\"/etc/passwd\")}"
+	connections, err := NewDiscoveredConnections(discovery, []map[string]string{{"id": value}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	content := BuildDiscoveredConnectionsConfig(discovery, &modconfig.Connection{PluginAlias: "aws"}, connections)
+	file, diags := hclsyntax.ParseConfig([]byte(content), "discovered.spc", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("generated config is invalid: %s\n%s", diags.Error(), content)
+	}
+	blocks := file.Body.(*hclsyntax.Body).Blocks
+	if len(blocks) != 1 || len(blocks[0].Body.Attributes) != 2 {
+		t.Fatalf("unexpected generated config:\n%s", content)
+	}
+	profile, diags := blocks[0].Body.Attributes["profile"].Expr.Value(nil)
+	if diags.HasErrors() || profile.AsString() != value {
+		t.Errorf("profile was not preserved:\n%s", content)
+	}
+}
@@ -0,0 +1,38 @@
+package steampipeconfig
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+)
+
+// ConnectionNamesMatching returns the sorted names of the connections which match any of the given
+// connection names or glob patterns, e.g. "aws_*"
+// an error is returned if a pattern is invalid or does not match any connection
+func (c *SteampipeConfig) ConnectionNamesMatching(patterns []string) ([]string, error) {
+	matched := make(map[string]struct{})
+	for _, pattern := range patterns {
+		found := false
+		for connectionName := range c.Connections {
+			isMatch, err := filepath.Match(pattern, connectionName)
+			if err != nil {
+				return nil, sperr.WrapWithMessage(err, "invalid connection pattern '%s'", pattern)
+			}
+			if isMatch {
+				matched[connectionName] = struct{}{}
+				found = true
+			}
+		}
+		if !found {
+			return nil, sperr.New("no connections match '%s'", pattern)
+		}
+	}
+
+	res := make([]string, 0, len(matched))
+	for connectionName := range matched {
+		res = append(res, connectionName)
+	}
+	sort.Strings(res)
+	return res, nil
+}
@@ -0,0 +1,46 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestConnectionNamesMatching(t *testing.T) {
+	config := &SteampipeConfig{Connections: map[string]*modconfig.Connection{
+		"aws_dev":  {},
+		"aws_prod": {},
+		"gcp":      {},
+	}}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "exact name", patterns: []string{"gcp"}, expected: []string{"gcp"}},
+		{name: "glob", patterns: []string{"aws_*"}, expected: []string{"aws_dev", "aws_prod"}},
+		{name: "overlapping patterns", patterns: []string{"aws_*", "aws_dev", "gcp"}, expected: []string{"aws_dev", "aws_prod", "gcp"}},
+		{name: "no match", patterns: []string{"azure*"}, wantErr: true},
+		{name: "invalid pattern", patterns: []string{"aws_["}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := config.ConnectionNamesMatching(test.patterns)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", res)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(res, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
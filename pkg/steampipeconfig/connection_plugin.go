@@ -101,9 +101,10 @@ func CreateConnectionPlugins(pluginManager pluginshared.PluginManager, connectio
 	}
 	log.Printf("[TRACE] CreateConnectionPlugin creating %d %s", len(connectionNamesToCreate), utils.Pluralize("connection", len(connectionNamesToCreate)))
 
+	globalConfig := GetGlobalConfig()
 	var connectionsToCreate = make([]*modconfig.Connection, len(connectionNamesToCreate))
 	for i, name := range connectionNamesToCreate {
-		connectionsToCreate[i] = GlobalConfig.Connections[name]
+		connectionsToCreate[i] = globalConfig.Connections[name]
 	}
 	// build result map, keyed by connection name
 	requestedConnectionPluginMap = make(map[string]*ConnectionPlugin, len(connectionsToCreate))
@@ -178,13 +179,14 @@ func handleGetFailures(getResponse *proto.GetResponse, res *RefreshConnectionRes
 	// handle PluginSdkCompatibilityError separately
 	var pluginsWithCompatibilityError = make(map[string]struct{})
 	var compatibilityErrorConnectionCount int
+	globalConfig := GetGlobalConfig()
 
 	for failedPluginInstance, failure := range getResponse.FailureMap {
 		// if this is a compatibility error, handle separately
 		if failure == error_helpers.PluginSdkCompatibilityError {
-			failedPluginShortName := GlobalConfig.PluginsInstances[failedPluginInstance].FriendlyName()
+			failedPluginShortName := globalConfig.PluginsInstances[failedPluginInstance].FriendlyName()
 			pluginsWithCompatibilityError[failedPluginShortName] = struct{}{}
-			for _, c := range GlobalConfig.Connections {
+			for _, c := range globalConfig.Connections {
 				if typehelpers.SafeString(c.PluginInstance) == failedPluginInstance {
 					compatibilityErrorConnectionCount++
 				}
@@ -319,12 +321,13 @@ func createConnectionPlugin(connection *modconfig.Connection, reattach *proto.Re
 
 	log.Printf("[TRACE] multiple connections ARE supported - adding all connections to ConnectionPlugin: %v", reattach.Connections)
 	// now identify all connections serviced by this plugin
+	globalConfig := GetGlobalConfig()
 	for _, c := range reattach.Connections {
 		log.Printf("[TRACE] adding connection %s", c)
 
 		// NOTE: use GlobalConfig to access connection config
 		// we assume this has been populated either by the hub (if this is being invoked from the fdw) or the CLI
-		config, ok := GlobalConfig.Connections[c]
+		config, ok := globalConfig.Connections[c]
 		if !ok {
 			log.Printf("[WARN] no connection config loaded for '%s', skipping", c)
 			continue
@@ -1,6 +1,7 @@
 package steampipeconfig
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"golang.org/x/exp/maps"
 )
 
 // ConnectionState is a struct containing all details for a connection
@@ -44,6 +46,14 @@ type ConnectionState struct {
 	FileName        string   `json:"file_name" db:"file_name"`
 	StartLineNumber int      `json:"start_line_number" db:"start_line_number"`
 	EndLineNumber   int      `json:"end_line_number" db:"end_line_number"`
+	// the child connections which are included in the aggregator, i.e. are ready (for aggregators)
+	IncludedConnections []string `json:"included_connections,omitempty" db:"included_connections"`
+	// the child connections which are excluded from the aggregator, with the reason for exclusion (for aggregators)
+	ExcludedConnections map[string]string `json:"excluded_connections,omitempty" db:"excluded_connections"`
+	// the options the view schema of the connection was created with - empty if it has no view schema (for aggregators)
+	AggregatorViews string `json:"aggregator_views,omitempty" db:"aggregator_views"`
+	// the column type overrides applied to the foreign tables of the connection
+	ColumnTypes map[string]string `json:"column_types,omitempty" db:"column_types"`
 }
 
 func NewConnectionState(connection *modconfig.Connection, creationTime time.Time) *ConnectionState {
@@ -56,6 +66,7 @@ func NewConnectionState(connection *modconfig.Connection, creationTime time.Time
 		Type:           &connection.Type,
 		ImportSchema:   connection.ImportSchema,
 		Connections:    connection.ConnectionNames,
+		ColumnTypes:    connection.ColumnTypes,
 	}
 	state.setFilename(connection)
 	if connection.Error != nil {
@@ -83,6 +94,10 @@ func (d *ConnectionState) Equals(other *ConnectionState) bool {
 	if d.Error() != other.Error() {
 		return false
 	}
+	// the column types are applied when the schema is imported, so a change requires the schema to be updated
+	if !maps.Equal(d.ColumnTypes, other.ColumnTypes) {
+		return false
+	}
 
 	names := d.Connections
 	sort.Strings(names)
@@ -107,7 +122,8 @@ func (d *ConnectionState) pluginModTimeChanged(other *ConnectionState) bool {
 
 func (d *ConnectionState) CanCloneSchema() bool {
 	return d.SchemaMode != plugin.SchemaModeDynamic &&
-		d.GetType() != modconfig.ConnectionTypeAggregator
+		d.GetType() != modconfig.ConnectionTypeAggregator &&
+		len(d.ColumnTypes) == 0
 }
 
 func (d *ConnectionState) Error() string {
@@ -129,6 +145,21 @@ func (d *ConnectionState) Disabled() bool {
 	return d.State == constants.ConnectionStateDisabled
 }
 
+// DisabledError returns an error explaining that the schema for the connection has not been imported
+// because the connection is disabled, and how to enable it
+//
+// NOTE: this is only returned by the steampipe client, when a query fails as the schema does not exist (see
+// startQueryWithRetries) - postgres does not fire event triggers for queries, and a stub schema cannot contain
+// every table of the plugin, so other postgres clients receive the bare error (the connection is listed with
+// state 'disabled' in the steampipe_connection table)
+func (d *ConnectionState) DisabledError() error {
+	location := "the connection config"
+	if d.FileName != "" {
+		location = fmt.Sprintf("%s:%d", d.FileName, d.StartLineNumber)
+	}
+	return fmt.Errorf("connection '%s' is disabled so its schema has not been imported - to enable it, set import_schema = \"%s\" in the connection block (%s)", d.ConnectionName, modconfig.ImportSchemaEnabled, location)
+}
+
 func (d *ConnectionState) GetType() string {
 	return typehelpers.SafeString(d.Type)
 }
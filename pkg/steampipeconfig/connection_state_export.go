@@ -0,0 +1,130 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// connectionStates is the list of all connection states - used to output a value for every state
+var connectionStates = []string{
+	constants.ConnectionStatePending,
+	constants.ConnectionStatePendingIncomplete,
+	constants.ConnectionStateReady,
+	constants.ConnectionStateUpdating,
+	constants.ConnectionStateDeleting,
+	constants.ConnectionStateDisabled,
+	constants.ConnectionStateError,
+}
+
+// ConnectionStateExport is a snapshot of the state of all connections, for consumption by external monitoring
+type ConnectionStateExport struct {
+	ServiceRunning bool                        `json:"service_running"`
+	Timestamp      time.Time                   `json:"timestamp"`
+	Summary        ConnectionStateSummary      `json:"summary"`
+	Connections    []ConnectionStateExportItem `json:"connections"`
+}
+
+// ConnectionStateExportItem is the exported state of a single connection
+type ConnectionStateExportItem struct {
+	Name           string    `json:"name"`
+	Plugin         string    `json:"plugin"`
+	PluginInstance string    `json:"plugin_instance,omitempty"`
+	Type           string    `json:"type,omitempty"`
+	State          string    `json:"state"`
+	Error          string    `json:"error,omitempty"`
+	LastUpdated    time.Time `json:"last_updated"`
+	// for aggregators, the child connections which are excluded from the aggregator, with the reason for exclusion
+	ExcludedConnections map[string]string `json:"excluded_connections,omitempty"`
+}
+
+// NewConnectionStateExport builds the export from the connection state map (which may be nil if the service is not running)
+func NewConnectionStateExport(connectionStateMap ConnectionStateMap, serviceRunning bool) *ConnectionStateExport {
+	res := &ConnectionStateExport{
+		ServiceRunning: serviceRunning,
+		Timestamp:      time.Now(),
+		Summary:        connectionStateMap.GetSummary(),
+		Connections:    []ConnectionStateExportItem{},
+	}
+	for _, c := range connectionStateMap {
+		res.Connections = append(res.Connections, ConnectionStateExportItem{
+			Name:           c.ConnectionName,
+			Plugin:         c.Plugin,
+			PluginInstance: typehelpers.SafeString(c.PluginInstance),
+			Type:           c.GetType(),
+			State:          c.State,
+			Error:          c.Error(),
+			LastUpdated:    c.ConnectionModTime,
+
+			ExcludedConnections: c.ExcludedConnections,
+		})
+	}
+	sort.Slice(res.Connections, func(i, j int) bool {
+		return res.Connections[i].Name < res.Connections[j].Name
+	})
+	return res
+}
+
+// Prometheus returns the export in the Prometheus text exposition format
+func (e *ConnectionStateExport) Prometheus() string {
+	var b strings.Builder
+
+	writeMetricHeader(&b, "steampipe_service_up", "Whether the Steampipe service is running.")
+	fmt.Fprintf(&b, "steampipe_service_up %d\n", boolToInt(e.ServiceRunning))
+
+	writeMetricHeader(&b, "steampipe_connections", "Number of connections in each state.")
+	for _, state := range connectionStates {
+		fmt.Fprintf(&b, "steampipe_connections{state=%s} %d\n", prometheusLabelValue(state), e.Summary[state])
+	}
+
+	writeMetricHeader(&b, "steampipe_connection_state", "Current state of each connection (1 for the current state, 0 otherwise).")
+	for _, c := range e.Connections {
+		for _, state := range connectionStates {
+			fmt.Fprintf(&b, "steampipe_connection_state{connection=%s,plugin=%s,state=%s} %d\n",
+				prometheusLabelValue(c.Name), prometheusLabelValue(c.Plugin), prometheusLabelValue(state), boolToInt(c.State == state))
+		}
+	}
+
+	writeMetricHeader(&b, "steampipe_connection_error", "Set for each connection in error - the error label contains the last error.")
+	for _, c := range e.Connections {
+		if c.State == constants.ConnectionStateError {
+			fmt.Fprintf(&b, "steampipe_connection_error{connection=%s,plugin=%s,error=%s} 1\n",
+				prometheusLabelValue(c.Name), prometheusLabelValue(c.Plugin), prometheusLabelValue(c.Error))
+		}
+	}
+
+	writeMetricHeader(&b, "steampipe_connection_last_updated_timestamp_seconds", "Time the connection was last updated, in seconds since the epoch.")
+	for _, c := range e.Connections {
+		if c.LastUpdated.IsZero() {
+			continue
+		}
+		fmt.Fprintf(&b, "steampipe_connection_last_updated_timestamp_seconds{connection=%s,plugin=%s} %d\n",
+			prometheusLabelValue(c.Name), prometheusLabelValue(c.Plugin), c.LastUpdated.Unix())
+	}
+
+	return b.String()
+}
+
+func writeMetricHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s gauge\n", name)
+}
+
+// prometheusLabelValue quotes a label value, escaping backslashes, double quotes and newlines
+func prometheusLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
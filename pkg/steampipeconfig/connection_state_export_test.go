@@ -0,0 +1,68 @@
+package steampipeconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestConnectionStateExportPrometheus(t *testing.T) {
+	connectionError := "failed to start plugin: \"aws\"\ncheck credentials"
+	modTime := time.Unix(1700000000, 0)
+	connectionStateMap := ConnectionStateMap{
+		"aws": {
+			ConnectionName:    "aws",
+			Plugin:            "hub.steampipe.io/plugins/turbot/aws@latest",
+			State:             constants.ConnectionStateError,
+			ConnectionError:   &connectionError,
+			ConnectionModTime: modTime,
+		},
+		"gcp": {
+			ConnectionName: "gcp",
+			Plugin:         "hub.steampipe.io/plugins/turbot/gcp@latest",
+			State:          constants.ConnectionStateReady,
+		},
+	}
+
+	output := NewConnectionStateExport(connectionStateMap, true).Prometheus()
+
+	expectedLines := []string{
+		`steampipe_service_up 1`,
+		`steampipe_connections{state="ready"} 1`,
+		`steampipe_connections{state="error"} 1`,
+		`steampipe_connections{state="pending"} 0`,
+		`steampipe_connection_state{connection="aws",plugin="hub.steampipe.io/plugins/turbot/aws@latest",state="error"} 1`,
+		`steampipe_connection_state{connection="aws",plugin="hub.steampipe.io/plugins/turbot/aws@latest",state="ready"} 0`,
+		`steampipe_connection_state{connection="gcp",plugin="hub.steampipe.io/plugins/turbot/gcp@latest",state="ready"} 1`,
+		`steampipe_connection_error{connection="aws",plugin="hub.steampipe.io/plugins/turbot/aws@latest",error="failed to start plugin: \"aws\"\ncheck credentials"} 1`,
+		`steampipe_connection_last_updated_timestamp_seconds{connection="aws",plugin="hub.steampipe.io/plugins/turbot/aws@latest"} 1700000000`,
+	}
+	lines := strings.Split(output, "\n")
+	for _, expected := range expectedLines {
+		found := false
+		for _, line := range lines {
+			if line == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected line not found: %s\noutput:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, `steampipe_connection_error{connection="gcp"`) {
+		t.Errorf("unexpected error metric for connection which is not in error:\n%s", output)
+	}
+}
+
+func TestConnectionStateExportServiceNotRunning(t *testing.T) {
+	export := NewConnectionStateExport(nil, false)
+	if len(export.Connections) != 0 {
+		t.Errorf("expected no connections, got %d", len(export.Connections))
+	}
+	if !strings.Contains(export.Prometheus(), "steampipe_service_up 0\n") {
+		t.Errorf("expected service down metric:\n%s", export.Prometheus())
+	}
+}
@@ -1,15 +1,15 @@
 package steampipeconfig
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"log"
-	"os"
 	"time"
 
 	sdkplugin "github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe/pkg/constants"
-	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/statestore"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
 	"golang.org/x/exp/maps"
@@ -138,14 +138,13 @@ func (m ConnectionStateMap) ConnectionsInState(states ...string) bool {
 	return false
 }
 
-func (m ConnectionStateMap) Save() error {
-	connFilePath := filepaths.ConnectionStatePath()
+func (m ConnectionStateMap) Save(ctx context.Context, store statestore.Store) error {
 	connFileJSON, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
 		log.Println("[ERROR]", "Error while writing state file", err)
 		return err
 	}
-	return os.WriteFile(connFilePath, connFileJSON, 0644)
+	return store.Save(ctx, statestore.KeyConnectionState, connFileJSON)
 }
 
 func (m ConnectionStateMap) Equals(other ConnectionStateMap) bool {
@@ -240,7 +239,7 @@ func (m ConnectionStateMap) SetConnectionsToPendingOrIncomplete() {
 // this is required as these fields were added to the table after release
 func (m ConnectionStateMap) PopulateFilename() {
 	// get the connection from config
-	connections := GlobalConfig.Connections
+	connections := GetGlobalConfig().Connections
 	for name, state := range m {
 		// do we have config for this connection (
 		if connection := connections[name]; connection != nil {
@@ -1,6 +1,7 @@
 package steampipeconfig
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/otiai10/copy"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/statestore"
 	"github.com/turbot/steampipe/pkg/utils"
 )
 
@@ -492,7 +494,7 @@ func setupTestConfig(test getConnectionsToUpdateTest) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	test.current.Save()
+	test.current.Save(context.Background(), statestore.NewFileStore())
 }
 
 func resetConfig(test getConnectionsToUpdateTest) {
@@ -88,7 +88,9 @@ func populateConnectionUpdates(ctx context.Context, pool *pgxpool.Pool, pluginMa
 	// build connection data for all required connections
 	// NOTE: this will NOT populate SchemaMode for the connections, as we need to load the schema for that
 	// this will be updated below on the call to updateRequiredStateWithSchemaProperties
-	requiredConnectionStateMap, missingPlugins, connectionStateResult := GetRequiredConnectionStateMap(GlobalConfig.Connections, currentConnectionStateMap)
+	// use the same config throughout, in case it is reloaded while the updates are being determined
+	globalConfig := GetGlobalConfig()
+	requiredConnectionStateMap, missingPlugins, connectionStateResult := GetRequiredConnectionStateMap(globalConfig.Connections, currentConnectionStateMap)
 	if connectionStateResult.Error != nil {
 		log.Printf("[WARN] failed to build required connection state: %s", err.Error())
 		return nil, NewErrorRefreshConnectionResult(connectionStateResult.Error)
@@ -154,7 +156,7 @@ func populateConnectionUpdates(ctx context.Context, pool *pgxpool.Pool, pluginMa
 			// we need to refetch the rate limiters for this plugin
 			if res.pluginBinaryChanged {
 				// store map item of plugin name to connection name (so we only have one entry per plugin)
-				pluginLogName := GlobalConfig.Connections[requiredConnectionState.ConnectionName].Plugin
+				pluginLogName := globalConfig.Connections[requiredConnectionState.ConnectionName].Plugin
 				updates.PluginsWithUpdatedBinary[pluginLogName] = requiredConnectionState.ConnectionName
 			}
 		}
@@ -354,8 +356,9 @@ func (u *ConnectionUpdates) getConnectionsToCreate(alreadyCreatedConnectionPlugi
 	connections := append(maps.Keys(u.Update), maps.Keys(u.MissingComments)...)
 	// put connections into a map to avoid dupes
 	var connectionMap = make(map[string]*modconfig.Connection, len(connections))
+	globalConfig := GetGlobalConfig()
 	for _, connectionName := range connections {
-		connection := GlobalConfig.Connections[connectionName]
+		connection := globalConfig.Connections[connectionName]
 		connectionMap[connectionName] = connection
 		// if this connection is an aggregator, add all its children
 		for _, child := range connection.Connections {
@@ -25,6 +25,8 @@ func (u *ConnectionUpdates) validatePluginsAndConnections() {
 			u.InvalidConnections[connectionName] = validationFailure
 		} else if validationFailure := validateConnectionName(connectionName, connectionPlugin); validationFailure != nil {
 			u.InvalidConnections[connectionName] = validationFailure
+		} else if validationFailure := validatePluginVersion(connectionName, connectionPlugin); validationFailure != nil {
+			u.InvalidConnections[connectionName] = validationFailure
 		} else {
 			validatedPlugins[connectionName] = connectionPlugin
 		}
@@ -83,6 +85,25 @@ func validateConnectionName(connectionName string, p *ConnectionPlugin) *Validat
 	return nil
 }
 
+// validatePluginVersion verifies the installed version of the plugin satisfies the plugin version pin of the connection
+func validatePluginVersion(connectionName string, p *ConnectionPlugin) *ValidationFailure {
+	globalConfig := GetGlobalConfig()
+	connection, ok := globalConfig.Connections[connectionName]
+	if !ok {
+		return nil
+	}
+	if err := CheckPluginVersionPin(connection, globalConfig.PluginVersions[connection.Plugin]); err != nil {
+		return &ValidationFailure{
+			Plugin:         p.PluginName,
+			ConnectionName: connectionName,
+			Message:        err.Error(),
+			// drop this connection if it exists - its schema may not match the pinned version
+			ShouldDropIfExists: true,
+		}
+	}
+	return nil
+}
+
 func validateProtocolVersion(connectionName string, p *ConnectionPlugin) *ValidationFailure {
 	pluginProtocolVersion := p.ConnectionMap[connectionName].Schema.GetProtocolVersion()
 	// if this is 0, the plugin does not define a protocol version
@@ -97,7 +118,7 @@ func validateProtocolVersion(connectionName string, p *ConnectionPlugin) *Valida
 		return &ValidationFailure{
 			Plugin:         p.PluginName,
 			ConnectionName: connectionName,
-			Message:        "Incompatible steampipe-plugin-sdk version. Please upgrade Steampipe to use this plugin.",
+			Message:        "Incompatible steampipe-plugin-sdk version. Please upgrade Steampipe to use this plugin (run 'steampipe plugin check-compat' for details).",
 			// drop this connection if it exists
 			ShouldDropIfExists: true,
 		}
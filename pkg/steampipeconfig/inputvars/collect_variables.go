@@ -1,6 +1,7 @@
 package inputvars
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
@@ -25,7 +26,11 @@ import (
 // This method returns diagnostics relating to the collection of the values,
 // but the values themselves may produce additional diagnostics when finally
 // parsed.
-func CollectVariableValues(workspacePath string, variableFileArgs []string, variablesArgs []string, workspaceMod *modconfig.Mod) (map[string]UnparsedVariableValue, error) {
+//
+// cloudValues are the values set in a Turbot Pipes workspace (if any) - these have the lowest precedence,
+// so may be overridden by any locally specified value
+// any warnings (e.g. a stored value which could not be decrypted) are returned, to be reported to the user
+func CollectVariableValues(workspacePath string, variableFileArgs []string, variablesArgs []string, workspaceMod *modconfig.Mod, cloudValues map[string]any) (map[string]UnparsedVariableValue, []string, error) {
 	workspaceModName := workspaceMod.ShortName
 	var modNames = make(map[string]struct{})
 	for _, m := range workspaceMod.ResourceMaps.Mods {
@@ -34,8 +39,26 @@ func CollectVariableValues(workspacePath string, variableFileArgs []string, vari
 
 	ret := map[string]UnparsedVariableValue{}
 
-	// First we'll deal with environment variables
-	// since they have the lowest precedence.
+	// First add any values from the cloud workspace, which are used as defaults
+	// NOTE: these may be secret so never log the value
+	for name, value := range cloudValues {
+		ret[name] = unparsedVariableValueCloud{
+			value: value,
+			name:  name,
+		}
+		log.Printf("[INFO] adding value for variable '%s' from cloud workspace", name)
+	}
+
+	// Next add any values persisted for the workspace using 'steampipe variable set'
+	// these have a lower precedence than all other local values
+	// NOTE: these may be secret so never log the value
+	warnings, err := addStoredVariableValues(workspacePath, ret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Next we'll deal with environment variables
+	// since they have the lowest precedence of the local values.
 	// (apart from values in the mod Require proeprty, which are handled separately later)
 	{
 		env := os.Environ()
@@ -72,7 +95,7 @@ func CollectVariableValues(workspacePath string, variableFileArgs []string, vari
 		log.Printf("[INFO] adding values from %s", defaultVarsPath)
 		diags := addVarsFromFile(defaultVarsPath, ValueFromAutoFile, ret)
 		if diags.HasErrors() {
-			return nil, error_helpers.DiagsToError(fmt.Sprintf("failed to load variables from '%s'", defaultVarsPath), diags)
+			return nil, nil, error_helpers.DiagsToError(fmt.Sprintf("failed to load variables from '%s'", defaultVarsPath), diags)
 		}
 
 	}
@@ -87,7 +110,7 @@ func CollectVariableValues(workspacePath string, variableFileArgs []string, vari
 			log.Printf("[INFO] adding values from %s", name)
 			diags := addVarsFromFile(name, ValueFromAutoFile, ret)
 			if diags.HasErrors() {
-				return nil, error_helpers.DiagsToError(fmt.Sprintf("failed to load variables from '%s'", name), diags)
+				return nil, nil, error_helpers.DiagsToError(fmt.Sprintf("failed to load variables from '%s'", name), diags)
 			}
 
 		}
@@ -99,7 +122,7 @@ func CollectVariableValues(workspacePath string, variableFileArgs []string, vari
 		log.Printf("[INFO] adding values from %s", fileArg)
 		diags := addVarsFromFile(fileArg, ValueFromNamedFile, ret)
 		if diags.HasErrors() {
-			return nil, error_helpers.DiagsToError(fmt.Sprintf("failed to load variables from '%s'", fileArg), diags)
+			return nil, nil, error_helpers.DiagsToError(fmt.Sprintf("failed to load variables from '%s'", fileArg), diags)
 		}
 	}
 
@@ -130,7 +153,7 @@ func CollectVariableValues(workspacePath string, variableFileArgs []string, vari
 	}
 
 	if diags.HasErrors() {
-		return nil, error_helpers.DiagsToError("failed to evaluate var args:", diags)
+		return nil, nil, error_helpers.DiagsToError("failed to evaluate var args:", diags)
 	}
 
 	// check viper for any interactively added variables
@@ -152,7 +175,7 @@ func CollectVariableValues(workspacePath string, variableFileArgs []string, vari
 	// - remove any variables which are not in the root mod or first level dependencies
 	ret = transformVarNames(ret, workspaceModName, modNames)
 
-	return ret, nil
+	return ret, warnings, nil
 }
 
 // map any variable names of form <modname>.<variablename> to <modname>.var.<varname>
@@ -332,6 +355,60 @@ func (v unparsedVariableValueString) ParseVariableValue(mode var_config.Variable
 	}, diags
 }
 
+// unparsedVariableValueCloud is a variable value set in a Turbot Pipes workspace
+// the value is the decoded json value returned by the API
+type unparsedVariableValueCloud struct {
+	value any
+	name  string
+}
+
+func (v unparsedVariableValueCloud) ParseVariableValue(mode var_config.VariableParsingMode) (*InputValue, tfdiags.Diagnostics) {
+	// string values are used raw for variables which are parsed literally,
+	// otherwise convert the value to json, which is a valid HCL expression
+	str, isString := v.value.(string)
+	if !isString || mode != var_config.VariableParseLiteral {
+		jsonBytes, err := json.Marshal(v.value)
+		if err != nil {
+			var diags tfdiags.Diagnostics
+			return nil, diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid cloud workspace variable value",
+				fmt.Sprintf("The value of variable %q set in the cloud workspace could not be converted: %s", v.name, err),
+			))
+		}
+		str = string(jsonBytes)
+	}
+	return unparsedVariableValueString{
+		str:        str,
+		name:       v.name,
+		sourceType: ValueFromCloudWorkspace,
+	}.ParseVariableValue(mode)
+}
+
+// addStoredVariableValues adds the values persisted in the variable store for the workspace
+// returns a warning for each stored value which could not be decrypted (these are skipped)
+func addStoredVariableValues(workspacePath string, ret map[string]UnparsedVariableValue) ([]string, error) {
+	store, err := LoadVariableStore()
+	if err != nil {
+		return nil, err
+	}
+	storedValues, decryptErrors := store.WorkspaceValues(workspacePath)
+	var warnings []string
+	for _, err := range decryptErrors {
+		log.Printf("[WARN] skipping stored variable value: %s", err.Error())
+		warnings = append(warnings, fmt.Sprintf("%s - the stored value is ignored", err.Error()))
+	}
+	for name, value := range storedValues {
+		ret[name] = unparsedVariableValueString{
+			str:        value,
+			name:       name,
+			sourceType: ValueFromStore,
+		}
+		log.Printf("[INFO] adding value for variable '%s' from variable store", name)
+	}
+	return warnings, nil
+}
+
 // isAutoVarFile determines if the file ends with .auto.spvars or .auto.spvars.json
 func isAutoVarFile(path string) bool {
 	for _, ext := range constants.AutoVariablesExtensions {
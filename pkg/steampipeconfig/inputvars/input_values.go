@@ -59,6 +59,13 @@ const (
 
 	// ValueFromModFile indicates that the value was provided in the 'Require' section of a mod file
 	ValueFromModFile ValueSourceType = 'M'
+
+	// ValueFromCloudWorkspace indicates that the value was set in the Turbot Pipes workspace
+	// given by the 'variables-workspace' argument
+	ValueFromCloudWorkspace ValueSourceType = 'W'
+
+	// ValueFromStore indicates that the value was persisted for the workspace using 'steampipe variable set'
+	ValueFromStore ValueSourceType = 'S'
 )
 
 func (v *InputValue) GoString() string {
@@ -83,6 +90,10 @@ func (v *InputValue) SourceTypeString() string {
 		return "env var"
 	case ValueFromInput:
 		return "user input"
+	case ValueFromCloudWorkspace:
+		return modconfig.VariableSourceCloudWorkspace
+	case ValueFromStore:
+		return "variable store"
 	default:
 		return "unknown"
 	}
@@ -255,6 +266,12 @@ func CheckInputVariables(vcs map[string]*modconfig.Variable, vs InputValues) tfd
 					"Invalid value for input variable",
 					fmt.Sprintf("The argument --var=\"%s=...\" does not contain a valid value for variable %q: %s.", name, name, err),
 				))
+			case ValueFromStore:
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid value for input variable",
+					fmt.Sprintf("The value stored using 'steampipe variable set %s' is not valid for variable %q: %s.", name, name, err),
+				))
 			case ValueFromInput:
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
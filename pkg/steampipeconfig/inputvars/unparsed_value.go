@@ -87,11 +87,12 @@ func ParseVariableValues(inputValuesUnparsed map[string]UnparsedVariableValue, v
 				}
 				seenUndeclaredInFile++
 
-			case ValueFromEnvVar:
+			case ValueFromEnvVar, ValueFromCloudWorkspace, ValueFromStore:
 				// We allow and ignore undeclared names for environment
 				// variables, because users will often set these globally
 				// when they are used across many (but not necessarily all)
 				// configurations.
+				// Likewise the cloud workspace and the variable store may have values for variables of a different mod version.
 			case ValueFromCLIArg:
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
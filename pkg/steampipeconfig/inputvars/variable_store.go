@@ -0,0 +1,146 @@
+package inputvars
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/secrets"
+	"golang.org/x/exp/maps"
+)
+
+// VariableStore contains the variable values persisted using 'steampipe variable set', keyed by workspace path
+// the stored values for a workspace are applied automatically when its variables are loaded
+type VariableStore struct {
+	Workspaces map[string]map[string]StoredVariableValue `json:"workspaces"`
+}
+
+// StoredVariableValue is a persisted variable value
+// the value is parsed in the same way as a value passed with --var
+type StoredVariableValue struct {
+	Value string `json:"value"`
+	// secret values are encrypted with the config encryption key
+	Secret bool `json:"secret,omitempty"`
+}
+
+// LoadVariableStore loads the variable store - if the file does not exist, an empty store is returned
+func LoadVariableStore() (*VariableStore, error) {
+	s := &VariableStore{Workspaces: make(map[string]map[string]StoredVariableValue)}
+	path := filepaths.VariableStoreFilePath()
+	if !filehelpers.FileExists(path) {
+		return s, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to read variable store")
+	}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to parse variable store %s", path)
+	}
+	if s.Workspaces == nil {
+		s.Workspaces = make(map[string]map[string]StoredVariableValue)
+	}
+	return s, nil
+}
+
+// Save writes the variable store - only the current user may read the file
+func (s *VariableStore) Save() error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepaths.VariableStoreFilePath(), content, 0600)
+}
+
+// Set stores the value of the variable for the workspace, encrypting it if it is a secret
+func (s *VariableStore) Set(workspacePath, name, value string, secret bool) error {
+	if secret {
+		key, _, err := secrets.EnsureKey()
+		if err != nil {
+			return err
+		}
+		value, err = secrets.EncryptValue(key, value)
+		if err != nil {
+			return sperr.WrapWithMessage(err, "failed to encrypt the value of variable '%s'", name)
+		}
+	}
+	workspaceKey := variableStoreKey(workspacePath)
+	if s.Workspaces[workspaceKey] == nil {
+		s.Workspaces[workspaceKey] = make(map[string]StoredVariableValue)
+	}
+	s.Workspaces[workspaceKey][name] = StoredVariableValue{Value: value, Secret: secret}
+	return nil
+}
+
+// Get returns the (decrypted) stored value of the variable for the workspace, and whether a value is stored
+func (s *VariableStore) Get(workspacePath, name string) (string, bool, error) {
+	stored, ok := s.Workspaces[variableStoreKey(workspacePath)][name]
+	if !ok {
+		return "", false, nil
+	}
+	value, err := stored.decryptedValue(name)
+	return value, true, err
+}
+
+// Unset removes the stored value of the variable for the workspace, returning whether a value was stored
+func (s *VariableStore) Unset(workspacePath, name string) bool {
+	workspaceKey := variableStoreKey(workspacePath)
+	if _, ok := s.Workspaces[workspaceKey][name]; !ok {
+		return false
+	}
+	delete(s.Workspaces[workspaceKey], name)
+	if len(s.Workspaces[workspaceKey]) == 0 {
+		delete(s.Workspaces, workspaceKey)
+	}
+	return true
+}
+
+// Names returns the sorted names of the variables stored for the workspace
+func (s *VariableStore) Names(workspacePath string) []string {
+	names := maps.Keys(s.Workspaces[variableStoreKey(workspacePath)])
+	sort.Strings(names)
+	return names
+}
+
+// WorkspaceValues returns the (decrypted) stored values of all variables for the workspace
+// a secret value which cannot be decrypted (e.g. as the key has changed) is skipped, and an error returned for it
+func (s *VariableStore) WorkspaceValues(workspacePath string) (map[string]string, []error) {
+	res := make(map[string]string)
+	var errors []error
+	for name, stored := range s.Workspaces[variableStoreKey(workspacePath)] {
+		value, err := stored.decryptedValue(name)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		res[name] = value
+	}
+	return res, errors
+}
+
+func (v StoredVariableValue) decryptedValue(name string) (string, error) {
+	if !v.Secret {
+		return v.Value, nil
+	}
+	key, err := secrets.LoadKey()
+	if err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to decrypt the stored value of variable '%s'", name)
+	}
+	value, err := secrets.DecryptValue(key, v.Value)
+	if err != nil {
+		return "", sperr.WrapWithMessage(err, "failed to decrypt the stored value of variable '%s'", name)
+	}
+	return value, nil
+}
+
+// variableStoreKey returns the key of the workspace in the store - the absolute, cleaned workspace path
+func variableStoreKey(workspacePath string) string {
+	if absPath, err := filepath.Abs(workspacePath); err == nil {
+		return absPath
+	}
+	return filepath.Clean(workspacePath)
+}
@@ -0,0 +1,103 @@
+package inputvars
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestVariableStore(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+	t.Setenv("STEAMPIPE_CONFIG_KEY", "AGE-SECRET-KEY-13S32S0TUPYWQLVNQH8MLL3Z9DY9TPEC6NR5D5MGSL2FFQ7GCHQPQEXESFV")
+
+	store, err := LoadVariableStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("/work/a", "org_id", "o-123", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("/work/a", "token", "s3cret", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("/work/b", "org_id", "o-456", false); err != nil {
+		t.Fatal(err)
+	}
+	if stored := store.Workspaces["/work/a"]["token"].Value; strings.Contains(stored, "s3cret") {
+		t.Errorf("secret value was stored in plaintext: %s", stored)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// reload and check the values for each workspace
+	store, err = LoadVariableStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, errs := store.WorkspaceValues("/work/a")
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(values) != 2 || values["org_id"] != "o-123" || values["token"] != "s3cret" {
+		t.Errorf("unexpected values for workspace a: %v", values)
+	}
+	if value, ok, _ := store.Get("/work/b/", "org_id"); !ok || value != "o-456" {
+		t.Errorf("expected o-456 for workspace b, got %q (stored: %v)", value, ok)
+	}
+
+	if !store.Unset("/work/b", "org_id") {
+		t.Error("expected org_id to be unset for workspace b")
+	}
+	if _, ok := store.Workspaces["/work/b"]; ok {
+		t.Error("expected workspace b to be removed once it has no values")
+	}
+}
+
+func TestVariableStoreUndecryptableValue(t *testing.T) {
+	prevSteampipeDir := filepaths.SteampipeDir
+	filepaths.SteampipeDir = t.TempDir()
+	defer func() { filepaths.SteampipeDir = prevSteampipeDir }()
+	t.Setenv("STEAMPIPE_CONFIG_KEY", "AGE-SECRET-KEY-13S32S0TUPYWQLVNQH8MLL3Z9DY9TPEC6NR5D5MGSL2FFQ7GCHQPQEXESFV")
+
+	store, err := LoadVariableStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("/work/a", "org_id", "o-123", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("/work/a", "token", "s3cret", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// the key has changed, so the secret value cannot be decrypted
+	t.Setenv("STEAMPIPE_CONFIG_KEY", "AGE-SECRET-KEY-1RXD6GTEZPUW2GGHXGYEKMX9GA4233AWZ0AFH07S0P9KLWZZXZVAS5E7E8P")
+	values, errs := store.WorkspaceValues("/work/a")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "'token'") {
+		t.Errorf("expected an error for the undecryptable value of 'token', got %v", errs)
+	}
+	// the other values are still returned
+	if len(values) != 1 || values["org_id"] != "o-123" {
+		t.Errorf("expected only the value of org_id, got %v", values)
+	}
+
+	// the undecryptable value is skipped, and reported as a warning
+	ret := map[string]UnparsedVariableValue{}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := addStoredVariableValues("/work/a", ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ret["token"]; ok || len(ret) != 1 {
+		t.Errorf("expected the undecryptable value to be skipped, got %v", ret)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning for the undecryptable value, got %v", warnings)
+	}
+}
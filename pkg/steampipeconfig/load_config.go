@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gertd/go-pluralize"
@@ -17,10 +18,10 @@ import (
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/constants"
-	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
 	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/secrets"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/parse"
@@ -28,9 +29,29 @@ import (
 )
 
 var GlobalConfig *SteampipeConfig
+
+// globalConfigMut guards GlobalConfig when it is replaced while in use by other goroutines (i.e. in the plugin manager)
+var globalConfigMut sync.RWMutex
 var defaultConfigFileName = "default.spc"
 var defaultConfigSampleFileName = "default.spc.sample"
 
+// GetGlobalConfig returns GlobalConfig, reading it under the lock
+// this must be used by code which may run concurrently with a config reload
+func GetGlobalConfig() *SteampipeConfig {
+	globalConfigMut.RLock()
+	defer globalConfigMut.RUnlock()
+	return GlobalConfig
+}
+
+// SetGlobalConfig replaces GlobalConfig under the lock, returning the previous config
+func SetGlobalConfig(config *SteampipeConfig) *SteampipeConfig {
+	globalConfigMut.Lock()
+	defer globalConfigMut.Unlock()
+	prevConfig := GlobalConfig
+	GlobalConfig = config
+	return prevConfig
+}
+
 // LoadSteampipeConfig loads the HCL connection config and workspace options
 func LoadSteampipeConfig(ctx context.Context, modLocation string, commandName string) (*SteampipeConfig, error_helpers.ErrorAndWarnings) {
 	utils.LogTime("steampipeconfig.LoadSteampipeConfig start")
@@ -176,6 +197,12 @@ func loadSteampipeConfig(ctx context.Context, modLocation string, commandName st
 		errorsAndWarnings.AddWarning(ew.Warnings...)
 	}
 
+	// map the connection names to schema names (this also validates the connection names)
+	// NOTE: this is done once all config is loaded, as it depends on the database options
+	if err := steampipeConfig.resolveSchemaNames(); err != nil {
+		return nil, error_helpers.NewErrorsAndWarning(err)
+	}
+
 	// now set default options on all connections without options set
 	// this is needed as the connection config is also loaded by the FDW which has no access to viper
 	steampipeConfig.setDefaultConnectionOptions()
@@ -243,6 +270,12 @@ func loadConfig(ctx context.Context, configFolder string, steampipeConfig *Steam
 		return error_helpers.DiagsToErrorsAndWarnings("Failed to load all config files", diags)
 	}
 
+	// decrypt any encrypted config values
+	fileData, err = secrets.DecryptFileData(fileData)
+	if err != nil {
+		return error_helpers.NewErrorsAndWarning(sperr.WrapWithMessage(err, "failed to decrypt config"))
+	}
+
 	body, diags := parse.ParseHclFiles(fileData)
 	if diags.HasErrors() {
 		return error_helpers.DiagsToErrorsAndWarnings("Failed to load all config files", diags)
@@ -285,11 +318,18 @@ func loadConfig(ctx context.Context, configFolder string, steampipeConfig *Steam
 				err := getDuplicateConnectionError(existingConnection, connection)
 				return error_helpers.NewErrorsAndWarning(err)
 			}
-			if ok, errorMessage := db_common.IsSchemaNameValid(connection.Name); !ok {
-				return error_helpers.NewErrorsAndWarning(sperr.New("invalid connection name: '%s' in '%s'. %s ", connection.Name, block.TypeRange.Filename, errorMessage))
-			}
 			steampipeConfig.Connections[connection.Name] = connection
 
+		case modconfig.BlockTypeDiscovery:
+			discovery, moreDiags := parse.DecodeConnectionDiscovery(block)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+			if err := steampipeConfig.addConnectionDiscovery(discovery); err != nil {
+				return error_helpers.NewErrorsAndWarning(err)
+			}
+
 		case modconfig.BlockTypeOptions:
 			// check this options type is permitted based on the options passed in
 			if err := optionsBlockPermitted(block, optionBlockMap, opts); err != nil {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"regexp"
 	"time"
 
@@ -13,7 +12,7 @@ import (
 	"github.com/sethvargo/go-retry"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/db/db_common"
-	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/statestore"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/utils"
 )
@@ -187,7 +186,7 @@ func GetLoadingConnectionStatusMessage(connectionStateMap ConnectionStateMap, re
 	return loadedMessage
 }
 
-func SaveConnectionStateFile(res *RefreshConnectionResult, connectionUpdates *ConnectionUpdates) {
+func SaveConnectionStateFile(ctx context.Context, store statestore.Store, res *RefreshConnectionResult, connectionUpdates *ConnectionUpdates) {
 	// now serialise the connection state
 	connectionState := make(ConnectionStateMap, len(connectionUpdates.FinalConnectionState))
 	for k, v := range connectionUpdates.FinalConnectionState {
@@ -199,13 +198,15 @@ func SaveConnectionStateFile(res *RefreshConnectionResult, connectionUpdates *Co
 	}
 
 	// update connection state and write the missing and failed plugin connections
-	if err := connectionState.Save(); err != nil {
+	if err := connectionState.Save(ctx, store); err != nil {
 		res.Error = err
 	}
 }
 
-func DeleteConnectionStateFile() {
-	os.Remove(filepaths.ConnectionStatePath())
+func DeleteConnectionStateFile(ctx context.Context, store statestore.Store) {
+	if err := store.Delete(ctx, statestore.KeyConnectionState); err != nil {
+		log.Printf("[WARN] failed to delete connection state file: %s", err.Error())
+	}
 }
 
 func isColumnNotFoundError(err error) (string, bool) {
@@ -32,10 +32,12 @@ func LoadVariableDefinitions(ctx context.Context, variablePath string, parseCtx
 	return variableMap, nil
 }
 
-func GetVariableValues(parseCtx *parse.ModParseContext, variableMap *modconfig.ModVariableMap, validate bool) (*modconfig.ModVariableMap, error_helpers.ErrorAndWarnings) {
+// GetVariableValues resolves the values of all variables in the variable map
+// cloudValues are default values loaded from a Turbot Pipes workspace, which may be overridden locally
+func GetVariableValues(parseCtx *parse.ModParseContext, variableMap *modconfig.ModVariableMap, cloudValues map[string]any, validate bool) (*modconfig.ModVariableMap, error_helpers.ErrorAndWarnings) {
 	log.Printf("[INFO] GetVariableValues")
 	// now resolve all input variables
-	inputValues, errorsAndWarnings := getInputVariables(parseCtx, variableMap, validate)
+	inputValues, errorsAndWarnings := getInputVariables(parseCtx, variableMap, cloudValues, validate)
 	if errorsAndWarnings.Error == nil {
 		// now update the variables map with the input values
 		inputValues.SetVariableValues(variableMap)
@@ -44,7 +46,7 @@ func GetVariableValues(parseCtx *parse.ModParseContext, variableMap *modconfig.M
 	return variableMap, errorsAndWarnings
 }
 
-func getInputVariables(parseCtx *parse.ModParseContext, variableMap *modconfig.ModVariableMap, validate bool) (inputvars.InputValues, error_helpers.ErrorAndWarnings) {
+func getInputVariables(parseCtx *parse.ModParseContext, variableMap *modconfig.ModVariableMap, cloudValues map[string]any, validate bool) (inputvars.InputValues, error_helpers.ErrorAndWarnings) {
 	variableFileArgs := viper.GetStringSlice(constants.ArgVarFile)
 	variableArgs := viper.GetStringSlice(constants.ArgVariable)
 
@@ -54,7 +56,7 @@ func getInputVariables(parseCtx *parse.ModParseContext, variableMap *modconfig.M
 
 	log.Printf("[INFO] getInputVariables, variableFileArgs: %s, variableArgs: %s", variableFileArgs, variableArgs)
 
-	var inputValuesUnparsed, err = inputvars.CollectVariableValues(path, variableFileArgs, variableArgs, parseCtx.CurrentMod)
+	inputValuesUnparsed, warnings, err := inputvars.CollectVariableValues(path, variableFileArgs, variableArgs, parseCtx.CurrentMod, cloudValues)
 	if err != nil {
 		log.Printf("[WARN] CollectVariableValues failed: %s", err.Error())
 
@@ -67,7 +69,7 @@ func getInputVariables(parseCtx *parse.ModParseContext, variableMap *modconfig.M
 		if err := identifyAllMissingVariables(parseCtx, variableMap, inputValuesUnparsed); err != nil {
 			log.Printf("[INFO] identifyAllMissingVariables returned a validation error: %s", err.Error())
 
-			return nil, error_helpers.NewErrorsAndWarning(err)
+			return nil, error_helpers.NewErrorsAndWarning(err, warnings...)
 		}
 	}
 
@@ -84,7 +86,9 @@ func getInputVariables(parseCtx *parse.ModParseContext, variableMap *modconfig.M
 		diags = append(diags, moreDiags...)
 	}
 
-	return parsedValues, newVariableValidationResult(diags)
+	res := newVariableValidationResult(diags)
+	res.AddWarning(warnings...)
+	return parsedValues, res
 }
 
 func newVariableValidationResult(diags tfdiags.Diagnostics) error_helpers.ErrorAndWarnings {
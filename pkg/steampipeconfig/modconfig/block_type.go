@@ -38,6 +38,7 @@ const (
 	BlockTypeConnection       = "connection"
 	BlockTypeOptions          = "options"
 	BlockTypeWorkspaceProfile = "workspace"
+	BlockTypeDiscovery        = "discovery"
 
 	ResourceTypeSnapshot = "snapshot"
 	AttributeArgs        = "args"
@@ -7,10 +7,13 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/go-kit/helpers"
+	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
 	"github.com/turbot/steampipe/pkg/utils"
 	"golang.org/x/exp/maps"
@@ -41,6 +44,8 @@ type Connection struct {
 	Plugin string `json:"plugin"`
 	// the label of the plugin config we are using
 	PluginInstance *string `json:"plugin_instance"`
+	// a version constraint the installed plugin must satisfy, e.g. "0.92.0" or "^0.92"
+	PluginVersion string `json:"plugin_version,omitempty"`
 	// Path to the installed plugin (if it exists)
 	PluginPath *string
 	// connection type - supported values: "aggregator"
@@ -56,6 +61,19 @@ type Connection struct {
 	// a list of the names resolved child connections
 	// (only valid for "aggregator" type)
 	ResolvedConnectionNames []string `json:"resolved_connections,omitempty"`
+	// should the aggregator views include a _ctx_connection column containing the name of the child connection
+	// (only valid for "aggregator" type)
+	ConnectionColumn bool `json:"connection_column,omitempty"`
+	// columns used to de-duplicate rows returned by multiple child connections, e.g. for global resources
+	// (only valid for "aggregator" type)
+	DedupKeys []string `json:"dedup_keys,omitempty"`
+	// columns which are hidden from non-admin database users
+	MaskColumns []string `json:"mask_columns,omitempty"`
+	// how the masked columns are masked - one of hide, null, redact or hash (defaults to hide)
+	MaskingPolicy string `json:"masking_policy,omitempty"`
+	// map of column to the type of the column in the foreign tables of the connection, overriding the type
+	// declared by the plugin - the column is either a column name (matching all tables) or 'table.column'
+	ColumnTypes map[string]string `json:"column_types,omitempty"`
 	// unparsed HCL of plugin specific connection config
 	Config string `json:"config,omitempty"`
 
@@ -142,8 +160,64 @@ func (c *Connection) Equals(other *Connection) bool {
 		strings.Join(c.ConnectionNames, ",") == strings.Join(other.ConnectionNames, ",") &&
 		connectionOptionsEqual &&
 		c.Config == other.Config &&
-		c.ImportSchema == other.ImportSchema
+		c.ImportSchema == other.ImportSchema &&
+		c.ConnectionColumn == other.ConnectionColumn &&
+		strings.Join(c.DedupKeys, ",") == strings.Join(other.DedupKeys, ",") &&
+		strings.Join(c.MaskColumns, ",") == strings.Join(other.MaskColumns, ",") &&
+		c.MaskingPolicy == other.MaskingPolicy &&
+		maps.Equal(c.ColumnTypes, other.ColumnTypes)
+
+}
+
+// PluginVersionConstraint returns the version constraint the installed plugin of the connection must satisfy
+// this is the plugin_version property if set - otherwise, if the plugin property specifies a version,
+// e.g. "aws@0.92.0", that version (returns an empty string if the version is not pinned)
+func (c *Connection) PluginVersionConstraint() string {
+	if c.PluginVersion != "" {
+		return c.PluginVersion
+	}
+	if c.Plugin == "" || strings.HasPrefix(c.Plugin, "local/") {
+		return ""
+	}
+	_, _, constraint := ociinstaller.NewSteampipeImageRef(c.Plugin).GetOrgNameAndConstraint()
+	if constraint == ociinstaller.DefaultImageTag {
+		return ""
+	}
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return ""
+	}
+	return constraint
+}
 
+// ConfigOnlyChanged returns whether the plugin specific config is the ONLY difference between this connection and other
+// such a change can be applied to the running plugin without rebuilding the connection schema
+func (c *Connection) ConfigOnlyChanged(other *Connection) bool {
+	if c.Config == other.Config || c.Error != nil || other.Error != nil {
+		return false
+	}
+	if typehelpers.SafeString(c.PluginInstance) != typehelpers.SafeString(other.PluginInstance) {
+		return false
+	}
+	// compare the connections with the config excluded
+	otherWithConfig := *other
+	otherWithConfig.Config = c.Config
+	return c.Equals(&otherWithConfig)
+}
+
+// RequiresAggregatorViews returns whether a view schema should be created for this connection
+// (i.e. this is an aggregator with either connection_column or dedup_keys set)
+func (c *Connection) RequiresAggregatorViews() bool {
+	return c.Type == ConnectionTypeAggregator && (c.ConnectionColumn || len(c.DedupKeys) > 0)
+}
+
+// AggregatorViewsOptions returns the options the view schema of this connection is created with
+// (empty if the connection does not require aggregator views)
+// this is recorded in the connection state, so the views are only recreated if the options change
+func (c *Connection) AggregatorViewsOptions() string {
+	if !c.RequiresAggregatorViews() {
+		return ""
+	}
+	return fmt.Sprintf("connection_column=%t;dedup_keys=%s", c.ConnectionColumn, strings.Join(c.DedupKeys, ","))
 }
 
 // SetOptions sets the options on the connection
@@ -175,6 +249,19 @@ func (c *Connection) Validate(map[string]*Connection) (warnings []string, errors
 	if !helpers.StringSliceContains(validConnectionTypes, c.Type) {
 		return nil, []string{fmt.Sprintf("connection '%s' has invalid connection type '%s'", c.Name, c.Type)}
 	}
+	if c.PluginVersion != "" {
+		if _, err := semver.NewConstraint(c.PluginVersion); err != nil {
+			return nil, []string{fmt.Sprintf("connection '%s' has invalid plugin_version '%s': %s", c.Name, c.PluginVersion, err.Error())}
+		}
+	}
+
+	if c.MaskingPolicy != "" && !helpers.StringSliceContains(constants.MaskingPolicies, c.MaskingPolicy) {
+		return nil, []string{fmt.Sprintf("connection '%s' has invalid masking_policy '%s', must be one of ['%s']", c.Name, c.MaskingPolicy, strings.Join(constants.MaskingPolicies, "','"))}
+	}
+
+	if errors := c.validateColumnTypes(); len(errors) > 0 {
+		return nil, errors
+	}
 
 	if c.Type == ConnectionTypeAggregator {
 		return c.ValidateAggregatorConnection()
@@ -186,6 +273,12 @@ func (c *Connection) Validate(map[string]*Connection) (warnings []string, errors
 	if len(c.ConnectionNames) != 0 {
 		validationErrors = append(validationErrors, fmt.Sprintf("connection '%s' has %d children, but is not of type 'aggregator'", c.Name, len(c.ConnectionNames)))
 	}
+	if c.ConnectionColumn {
+		validationErrors = append(validationErrors, fmt.Sprintf("connection '%s' sets connection_column, but is not of type 'aggregator'", c.Name))
+	}
+	if len(c.DedupKeys) != 0 {
+		validationErrors = append(validationErrors, fmt.Sprintf("connection '%s' sets dedup_keys, but is not of type 'aggregator'", c.Name))
+	}
 	validImportSchemaValues := utils.SliceToLookup(ValidImportSchemaValues)
 	if _, isValid := validImportSchemaValues[c.ImportSchema]; !isValid {
 		validationErrors = append(validationErrors, fmt.Sprintf("invalid value '%s'for import_schema, must be one of ['%s']", c.ImportSchema, strings.Join(ValidImportSchemaValues, "','")))
@@ -278,6 +371,49 @@ func (c *Connection) PopulateChildren(connectionMap map[string]*Connection) []st
 	return failures
 }
 
+// validateColumnTypes verifies the columns of the column_types option are either a column name or 'table.column',
+// and the types are supported
+func (c *Connection) validateColumnTypes() []string {
+	var errors []string
+	for _, column := range utils.SortedMapKeys(c.ColumnTypes) {
+		columnType := c.ColumnTypes[column]
+		table, columnName, hasTable := strings.Cut(column, ".")
+		if column == "" || (hasTable && (table == "" || columnName == "" || strings.Contains(columnName, "."))) {
+			errors = append(errors, fmt.Sprintf("connection '%s' has invalid column_types column '%s', must be a column name or 'table.column'", c.Name, column))
+			continue
+		}
+		if !helpers.StringSliceContains(constants.ColumnTypeOverrideTypes, columnType) {
+			errors = append(errors, fmt.Sprintf("connection '%s' has unsupported column_types type '%s' for column '%s', must be one of ['%s']", c.Name, columnType, column, strings.Join(constants.ColumnTypeOverrideTypes, "','")))
+		}
+	}
+	return errors
+}
+
+// GetMaskColumns returns the columns to hide from non-admin users
+// for aggregator connections this includes the masked columns of all child connections,
+// so data hidden in a child connection is not exposed via the aggregator
+func (c *Connection) GetMaskColumns() []string {
+	res := append([]string{}, c.MaskColumns...)
+	for _, child := range c.Connections {
+		res = append(res, child.MaskColumns...)
+	}
+	return helpers.StringSliceDistinct(res)
+}
+
+// GetMaskingPolicy returns the masking policy of the connection, defaulting to hide
+func (c *Connection) GetMaskingPolicy() string {
+	if c.MaskingPolicy == "" {
+		return constants.MaskingPolicyHide
+	}
+	return c.MaskingPolicy
+}
+
+// RequiresMaskedViews returns whether a masked view schema should be created for this connection
+// (i.e. columns are masked, using a policy which redacts the values rather than just hiding them)
+func (c *Connection) RequiresMaskedViews() bool {
+	return len(c.GetMaskColumns()) > 0 && c.GetMaskingPolicy() != constants.MaskingPolicyHide
+}
+
 // GetResolveConnectionNames return the names of all child connections
 // (will only be non-empty for aggregator connections)
 func (c *Connection) GetResolveConnectionNames() []string {
@@ -0,0 +1,66 @@
+package modconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+)
+
+// the default name template for discovered connections
+const defaultDiscoveryNameTemplate = "{{ .connection }}_{{ .id }}"
+
+// the default discovery queries for plugins which support discovery, keyed by plugin org/name
+// each query returns a row for each connection to create, and must return an 'id' column
+var defaultDiscoveryQueries = map[string]string{
+	"turbot/aws": "select id, name from aws_organizations_account where status = 'ACTIVE'",
+}
+
+// ConnectionDiscovery is a config block which defines connections which are discovered using the credentials
+// of an existing connection, e.g. the accounts of an AWS organization
+// the discovered connections are written to a generated config file, which is kept in sync as access changes
+type ConnectionDiscovery struct {
+	Name string
+	// the connection whose credentials are used to discover the connections
+	Connection string `hcl:"connection"`
+	// the query which returns a row for each connection to create - this must return an 'id' column
+	// (if not set, the default query for the plugin of the connection is used)
+	Query *string `hcl:"query,optional"`
+	// the template used to name the discovered connections - the columns returned by the query may be referenced,
+	// as may the name of the discovering connection, e.g. "{{ .connection }}_{{ .id }}"
+	NameTemplate *string `hcl:"connection_name,optional"`
+	// the template used for the (plugin specific) config of the discovered connections
+	// this is HCL - the columns returned by the query may be referenced within its string values, e.g. role_arn = "{{ .arn }}"
+	// (this is required, as the discovered connections must not share the credentials of the discovering connection)
+	ConfigTemplate string `hcl:"config"`
+
+	DeclRange hcl.Range
+}
+
+func NewConnectionDiscovery(block *hcl.Block) *ConnectionDiscovery {
+	return &ConnectionDiscovery{
+		Name:      block.Labels[0],
+		DeclRange: block.TypeRange,
+	}
+}
+
+// GetQuery returns the discovery query - either the configured query or the default query for the given plugin
+func (d *ConnectionDiscovery) GetQuery(plugin string) (string, error) {
+	if d.Query != nil {
+		return *d.Query, nil
+	}
+	org, name, _ := ociinstaller.NewSteampipeImageRef(plugin).GetOrgNameAndConstraint()
+	if query, ok := defaultDiscoveryQueries[fmt.Sprintf("%s/%s", org, name)]; ok {
+		return query, nil
+	}
+	return "", sperr.New("discovery '%s': plugin '%s/%s' does not support discovery - a query must be specified", d.Name, org, name)
+}
+
+// GetNameTemplate returns the name template - either the configured template or the default
+func (d *ConnectionDiscovery) GetNameTemplate() string {
+	if d.NameTemplate != nil {
+		return *d.NameTemplate
+	}
+	return defaultDiscoveryNameTemplate
+}
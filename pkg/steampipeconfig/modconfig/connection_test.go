@@ -36,3 +36,49 @@ func TestConnectionEquals(t *testing.T) {
 		}
 	}
 }
+
+var conn1_config_changed *Connection = &Connection{
+	Name:   "connection",
+	Config: "hcl_helpers_rotated",
+}
+
+var conn1_type_changed *Connection = &Connection{
+	Name:   "connection",
+	Type:   ConnectionTypeAggregator,
+	Config: "hcl_helpers_rotated",
+}
+
+var configOnlyChangedCases = map[string]connectionEquality{
+	"unchanged":              {connection1: conn1, connection2: conn1_duplicate, expectation: false},
+	"config_changed":         {connection1: conn1, connection2: conn1_config_changed, expectation: true},
+	"config_and_type_change": {connection1: conn1, connection2: conn1_type_changed, expectation: false},
+	"different_connection":   {connection1: conn1, connection2: other_conn, expectation: false},
+}
+
+func TestConnectionConfigOnlyChanged(t *testing.T) {
+	for caseName, caseData := range configOnlyChangedCases {
+		configOnlyChanged := caseData.connection1.ConfigOnlyChanged(caseData.connection2)
+		if caseData.expectation != configOnlyChanged {
+			t.Errorf(`Test: '%s' FAILED: expected: %v, actual: %v`, caseName, caseData.expectation, configOnlyChanged)
+		}
+	}
+}
+
+func TestConnectionValidateColumnTypes(t *testing.T) {
+	tests := map[string]struct {
+		columnTypes map[string]string
+		errors      int
+	}{
+		"none":             {columnTypes: nil},
+		"column":           {columnTypes: map[string]string{"tags": "text"}},
+		"table column":     {columnTypes: map[string]string{"aws_s3_bucket.creation_date": "date"}},
+		"unsupported type": {columnTypes: map[string]string{"tags": "xml"}, errors: 1},
+		"invalid column":   {columnTypes: map[string]string{".tags": "text", "a.b.c": "text"}, errors: 2},
+	}
+	for name, test := range tests {
+		c := &Connection{Name: "aws", Type: ConnectionTypePlugin, ImportSchema: ImportSchemaEnabled, ColumnTypes: test.columnTypes}
+		if _, errors := c.Validate(nil); len(errors) != test.errors {
+			t.Errorf("%s: expected %d errors, got %v", name, test.errors, errors)
+		}
+	}
+}
@@ -270,9 +270,18 @@ func (m *Mod) Save() error {
 	if require := m.Require; require != nil && !require.Empty() {
 		requiresBody := modBody.AppendNewBlock("require", nil).Body()
 
-		if require.Steampipe != nil && require.Steampipe.MinVersionString != "" {
-			steampipeRequiresBody := requiresBody.AppendNewBlock("steampipe", nil).Body()
-			steampipeRequiresBody.SetAttributeValue("min_version", cty.StringVal(require.Steampipe.MinVersionString))
+		if steampipeRequire := require.Steampipe; steampipeRequire != nil {
+			if steampipeRequire.ConstraintString != "" {
+				requiresBody.SetAttributeValue("steampipe", cty.StringVal(steampipeRequire.ConstraintString))
+			} else if steampipeRequire.MinVersionString != "" || steampipeRequire.MaxVersionString != "" {
+				steampipeRequiresBody := requiresBody.AppendNewBlock("steampipe", nil).Body()
+				if steampipeRequire.MinVersionString != "" {
+					steampipeRequiresBody.SetAttributeValue("min_version", cty.StringVal(steampipeRequire.MinVersionString))
+				}
+				if steampipeRequire.MaxVersionString != "" {
+					steampipeRequiresBody.SetAttributeValue("max_version", cty.StringVal(steampipeRequire.MaxVersionString))
+				}
+			}
 		}
 		if len(require.Plugins) > 0 {
 			pluginValues := make([]cty.Value, len(require.Plugins))
@@ -314,22 +323,24 @@ func (m *Mod) SetFilePath(modFilePath string) {
 // ValidateRequirements validates that the current steampipe CLI and the installed plugins is compatible with the mod
 func (m *Mod) ValidateRequirements(pluginVersionMap map[string]*PluginVersionString) []error {
 	validationErrors := []error{}
-	if err := m.validateSteampipeVersion(); err != nil {
+	if err := m.ValidateSteampipeVersion(); err != nil {
 		validationErrors = append(validationErrors, err)
 	}
-	pluginErr := m.validatePluginVersions(pluginVersionMap)
+	pluginErr := m.ValidatePluginVersions(pluginVersionMap)
 	validationErrors = append(validationErrors, pluginErr...)
 	return validationErrors
 }
 
-func (m *Mod) validateSteampipeVersion() error {
+// ValidateSteampipeVersion validates that the current steampipe CLI satisfies the steampipe version requirement of the mod
+func (m *Mod) ValidateSteampipeVersion() error {
 	if m.Require == nil {
 		return nil
 	}
 	return m.Require.validateSteampipeVersion(m.Name())
 }
 
-func (m *Mod) validatePluginVersions(availablePlugins map[string]*PluginVersionString) []error {
+// ValidatePluginVersions validates that the installed plugins satisfy the plugin requirements of the mod
+func (m *Mod) ValidatePluginVersions(availablePlugins map[string]*PluginVersionString) []error {
 	if m.Require == nil {
 		return nil
 	}
@@ -1,8 +1,8 @@
 package modconfig
 
 import (
-	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/hcl/v2"
@@ -14,10 +14,11 @@ import (
 
 // Require is a struct representing mod dependencies
 type Require struct {
-	Plugins                          []*PluginVersion        `hcl:"plugin,block"`
-	DeprecatedSteampipeVersionString string                  `hcl:"steampipe,optional"`
-	Steampipe                        *SteampipeRequire       `hcl:"steampipe,block"`
-	Mods                             []*ModVersionConstraint `hcl:"mod,block"`
+	Plugins []*PluginVersion `hcl:"plugin,block"`
+	// the 'steampipe' property - either a version constraint, e.g. ">=0.21", or a (deprecated) min version
+	SteampipeVersionString string                  `hcl:"steampipe,optional"`
+	Steampipe              *SteampipeRequire       `hcl:"steampipe,block"`
+	Mods                   []*ModVersionConstraint `hcl:"mod,block"`
 	// map keyed by name [and alias]
 	modMap map[string]*ModVersionConstraint
 	// range of the definition of the require block
@@ -103,20 +104,23 @@ func (r *Require) initialise(modBlock *hcl.Block) hcl.Diagnostics {
 
 func (r *Require) handleDeprecations() hcl.Diagnostics {
 	var diags hcl.Diagnostics
-	// the 'steampipe' property is deprecated and replace with a steampipe block
-	if r.DeprecatedSteampipeVersionString != "" {
+	if r.SteampipeVersionString != "" {
 		// if there is both a steampipe block and property, fail
 		if r.Steampipe != nil {
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
-				Summary:  "Both 'steampipe' block and deprecated 'steampipe' property are set",
+				Summary:  "Both 'steampipe' block and 'steampipe' property are set",
 				Subject:  &r.DeclRange,
 			})
+		} else if _, err := semver.NewVersion(strings.TrimPrefix(r.SteampipeVersionString, "v")); err != nil {
+			// the property is a version constraint
+			r.Steampipe = &SteampipeRequire{ConstraintString: r.SteampipeVersionString}
 		} else {
-			r.Steampipe = &SteampipeRequire{MinVersionString: r.DeprecatedSteampipeVersionString}
+			// the 'steampipe' property is deprecated as a min version - it is replaced with a steampipe block
+			r.Steampipe = &SteampipeRequire{MinVersionString: r.SteampipeVersionString}
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagWarning,
-				Summary:  "Property 'steampipe' is deprecated for mod require block - use a steampipe block instead",
+				Summary:  "Setting property 'steampipe' to a version is deprecated for mod require block - use a version constraint, e.g. \">=0.21\", or a steampipe block instead",
 				Subject:  &r.DeclRange,
 			},
 			)
@@ -126,12 +130,10 @@ func (r *Require) handleDeprecations() hcl.Diagnostics {
 }
 
 func (r *Require) validateSteampipeVersion(modName string) error {
-	if steampipeVersionConstraint := r.SteampipeVersionConstraint(); steampipeVersionConstraint != nil {
-		if !steampipeVersionConstraint.Check(version.SteampipeVersion) {
-			return fmt.Errorf("steampipe version %s does not satisfy %s which requires version %s", version.SteampipeVersion.String(), modName, r.Steampipe.MinVersionString)
-		}
+	if r.SteampipeVersionConstraint() == nil {
+		return nil
 	}
-	return nil
+	return r.Steampipe.Validate(modName, version.SteampipeVersion)
 }
 
 // validatePluginVersions validates that for every plugin requirement there's at least one plugin installed
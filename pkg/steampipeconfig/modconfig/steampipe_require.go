@@ -7,10 +7,17 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 )
 
+// a version greater than any steampipe version - used to determine whether upgrading would satisfy a requirement
+var maxSteampipeVersion = semver.MustParse("999999.0.0")
+
 type SteampipeRequire struct {
 	MinVersionString string `hcl:"min_version,optional"`
+	MaxVersionString string `hcl:"max_version,optional"`
+	// a version constraint, e.g. ">=0.21" - this is set by the 'steampipe' require property
+	ConstraintString string
 	Constraint       *semver.Constraints
 	DeclRange        hcl.Range
 }
@@ -25,22 +32,94 @@ func (r *SteampipeRequire) initialise(requireBlock *hcl.Block) hcl.Diagnostics {
 	// set DeclRange
 	r.DeclRange = hclhelpers.BlockRange(steampipeBlock)
 
-	if r.MinVersionString == "" {
-		return nil
-	}
-
-	// convert min version into constraint (including prereleases)
-	minVersion, err := semver.NewVersion(strings.TrimPrefix(r.MinVersionString, "v"))
-	if err == nil {
-		r.Constraint, err = semver.NewConstraint(fmt.Sprintf(">=%s-0", minVersion))
-	}
-	if err != nil {
+	if err := r.setConstraint(); err != nil {
 		return hcl.Diagnostics{
 			&hcl.Diagnostic{
 				Severity: hcl.DiagError,
-				Summary:  fmt.Sprintf("invalid required steampipe version %s", r.MinVersionString),
+				Summary:  fmt.Sprintf("invalid required steampipe version %s", r.String()),
+				Detail:   err.Error(),
 				Subject:  &r.DeclRange,
 			}}
 	}
 	return nil
 }
+
+// setConstraint sets the Constraint from the constraint, min version and max version
+func (r *SteampipeRequire) setConstraint() error {
+	constraintString, err := r.buildConstraintString()
+	if err != nil || constraintString == "" {
+		return err
+	}
+	r.Constraint, err = semver.NewConstraint(constraintString)
+	return err
+}
+
+// buildConstraintString combines the constraint, min version and max version into a single constraint
+func (r *SteampipeRequire) buildConstraintString() (string, error) {
+	var constraints []string
+	if r.ConstraintString != "" {
+		constraints = append(constraints, r.ConstraintString)
+	}
+	if r.MinVersionString != "" {
+		minVersion, err := semver.NewVersion(strings.TrimPrefix(r.MinVersionString, "v"))
+		if err != nil {
+			return "", err
+		}
+		// include prereleases of the min version
+		constraints = append(constraints, fmt.Sprintf(">=%s-0", minVersion))
+	}
+	if r.MaxVersionString != "" {
+		maxVersion := strings.TrimPrefix(r.MaxVersionString, "v")
+		if _, err := semver.NewVersion(maxVersion); err != nil {
+			return "", err
+		}
+		// NOTE: do not normalise the max version - a partial version, e.g. 0.22, includes all 0.22.x patch versions
+		constraints = append(constraints, fmt.Sprintf("<=%s", maxVersion))
+	}
+	return strings.Join(constraints, ", "), nil
+}
+
+// String returns the requirement in a form suitable for display, e.g. ">=0.21, <=0.22"
+func (r *SteampipeRequire) String() string {
+	var requirements []string
+	if r.ConstraintString != "" {
+		requirements = append(requirements, r.ConstraintString)
+	}
+	if r.MinVersionString != "" {
+		requirements = append(requirements, fmt.Sprintf(">=%s", strings.TrimPrefix(r.MinVersionString, "v")))
+	}
+	if r.MaxVersionString != "" {
+		requirements = append(requirements, fmt.Sprintf("<=%s", strings.TrimPrefix(r.MaxVersionString, "v")))
+	}
+	return strings.Join(requirements, ", ")
+}
+
+// Check returns whether the given steampipe version satisfies the requirement
+func (r *SteampipeRequire) Check(steampipeVersion *semver.Version) bool {
+	if r.Constraint == nil {
+		return true
+	}
+	if r.Constraint.Check(steampipeVersion) {
+		return true
+	}
+	// a prerelease version is only matched by a constraint with a prerelease
+	// - for a user specified constraint, check the prerelease as if it were the release version
+	if steampipeVersion.Prerelease() != "" {
+		releaseVersion, _ := steampipeVersion.SetPrerelease("")
+		return r.Constraint.Check(&releaseVersion)
+	}
+	return false
+}
+
+// Validate returns an error if the given steampipe version does not satisfy the requirement
+// the error includes a hint of how to resolve the problem
+func (r *SteampipeRequire) Validate(modName string, steampipeVersion *semver.Version) error {
+	if r.Check(steampipeVersion) {
+		return nil
+	}
+	hint := "install a supported version from https://steampipe.io/downloads"
+	if r.Constraint.Check(maxSteampipeVersion) {
+		hint = "upgrade steampipe - see https://steampipe.io/downloads"
+	}
+	return sperr.New("mod %s requires steampipe %s but this is version %s - %s", modName, r.String(), steampipeVersion.String(), hint)
+}
@@ -0,0 +1,46 @@
+package modconfig
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestSteampipeRequireCheck(t *testing.T) {
+	tests := map[string]struct {
+		require  *SteampipeRequire
+		version  string
+		expected bool
+	}{
+		"constraint satisfied":           {&SteampipeRequire{ConstraintString: ">=0.21"}, "0.21.2", true},
+		"constraint not satisfied":       {&SteampipeRequire{ConstraintString: ">=0.21"}, "0.20.9", false},
+		"constraint prerelease":          {&SteampipeRequire{ConstraintString: ">=0.21"}, "0.21.0-rc.1", true},
+		"min version prerelease":         {&SteampipeRequire{MinVersionString: "v0.21.0"}, "0.21.0-rc.1", true},
+		"max version includes patches":   {&SteampipeRequire{MaxVersionString: "0.22"}, "0.22.5", true},
+		"max version not satisfied":      {&SteampipeRequire{MaxVersionString: "0.22"}, "0.23.0", false},
+		"min and max satisfied":          {&SteampipeRequire{MinVersionString: "0.21.0", MaxVersionString: "0.22"}, "0.21.1", true},
+		"min and max not satisfied":      {&SteampipeRequire{MinVersionString: "0.21.0", MaxVersionString: "0.22"}, "0.20.0", false},
+		"constraint range not satisfied": {&SteampipeRequire{ConstraintString: ">=0.21, <0.22"}, "0.22.0", false},
+	}
+	for name, test := range tests {
+		if err := test.require.setConstraint(); err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err.Error())
+			continue
+		}
+		if got := test.require.Check(semver.MustParse(test.version)); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, got)
+		}
+	}
+}
+
+func TestSteampipeRequireInvalid(t *testing.T) {
+	for _, require := range []*SteampipeRequire{
+		{ConstraintString: ">=foo"},
+		{MinVersionString: "foo"},
+		{MaxVersionString: "foo"},
+	} {
+		if err := require.setConstraint(); err == nil {
+			t.Errorf("expected an error for requirement %s", require.String())
+		}
+	}
+}
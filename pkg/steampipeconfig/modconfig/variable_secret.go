@@ -0,0 +1,34 @@
+package modconfig
+
+import "strings"
+
+// RedactedVariableValue is the value displayed in place of a redacted variable value
+const RedactedVariableValue = "<redacted>"
+
+// VariableSourceCloudWorkspace is the value source type of a variable whose value was set in a Turbot Pipes workspace
+const VariableSourceCloudWorkspace = "cloud workspace"
+
+// variables whose name contains any of these terms are assumed to be secrets and have their values redacted
+var secretVariableTerms = []string{"password", "passwd", "secret", "token", "key", "credential", "private", "auth", "cert"}
+
+// IsSecretVariableName returns whether the variable name suggests the value is a secret
+// variables do not (yet) support a 'sensitive' property, so this is a name based heuristic
+func IsSecretVariableName(name string) bool {
+	// only consider the variable name, not the mod it belongs to
+	name = strings.ToLower(name[strings.LastIndex(name, ".")+1:])
+	for _, term := range secretVariableTerms {
+		if strings.Contains(name, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayValueGo returns the value of the variable to display
+// values loaded from a cloud workspace are redacted if the variable name suggests they are secret
+func (v *Variable) DisplayValueGo() any {
+	if v.ValueSourceType == VariableSourceCloudWorkspace && IsSecretVariableName(v.ShortName) {
+		return RedactedVariableValue
+	}
+	return v.ValueGo
+}
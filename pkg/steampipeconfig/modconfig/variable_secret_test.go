@@ -0,0 +1,39 @@
+package modconfig
+
+import "testing"
+
+func TestIsSecretVariableName(t *testing.T) {
+	tests := map[string]bool{
+		"region":                 false,
+		"aws_compliance.regions": false,
+		"api_token":              true,
+		"DB_PASSWORD":            true,
+		"mymod.client_secret":    true,
+		"secrets_mod.region":     false,
+	}
+	for name, expected := range tests {
+		if actual := IsSecretVariableName(name); actual != expected {
+			t.Errorf("IsSecretVariableName(%s): expected %v, got %v", name, expected, actual)
+		}
+	}
+}
+
+func TestVariableDisplayValueGo(t *testing.T) {
+	tests := map[string]struct {
+		name       string
+		sourceType string
+		expected   any
+	}{
+		"cloud workspace secret":  {name: "api_token", sourceType: VariableSourceCloudWorkspace, expected: RedactedVariableValue},
+		"cloud workspace value":   {name: "region", sourceType: VariableSourceCloudWorkspace, expected: "value"},
+		"local secret":            {name: "api_token", sourceType: "CLI arg", expected: "value"},
+		"default value of secret": {name: "api_token", sourceType: "", expected: "value"},
+	}
+	for testName, test := range tests {
+		v := &Variable{ValueGo: "value", ValueSourceType: test.sourceType}
+		v.ShortName = test.name
+		if actual := v.DisplayValueGo(); actual != test.expected {
+			t.Errorf("%s: expected %v, got %v", testName, test.expected, actual)
+		}
+	}
+}
@@ -2,11 +2,13 @@ package modconfig
 
 import (
 	"fmt"
+	"log"
 	"reflect"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/cobra"
+	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
@@ -19,31 +21,35 @@ type WorkspaceProfile struct {
 	CloudHost *string `hcl:"cloud_host,optional" cty:"cloud_host"`
 	PipesHost *string `hcl:"pipes_host,optional" cty:"pipes_host"`
 	// deprecated
-	CloudToken        *string           `hcl:"cloud_token,optional" cty:"cloud_token"`
-	PipesToken        *string           `hcl:"pipes_token,optional" cty:"pipes_token"`
-	InstallDir        *string           `hcl:"install_dir,optional" cty:"install_dir"`
-	ModLocation       *string           `hcl:"mod_location,optional" cty:"mod_location"`
-	QueryTimeout      *int              `hcl:"query_timeout,optional" cty:"query_timeout"`
-	SnapshotLocation  *string           `hcl:"snapshot_location,optional" cty:"snapshot_location"`
-	WorkspaceDatabase *string           `hcl:"workspace_database,optional" cty:"workspace_database"`
-	SearchPath        *string           `hcl:"search_path" cty:"search_path"`
-	SearchPathPrefix  *string           `hcl:"search_path_prefix" cty:"search_path_prefix"`
-	Watch             *bool             `hcl:"watch" cty:"watch"`
-	MaxParallel       *int              `hcl:"max_parallel" cty:"max-parallel"`
-	Introspection     *string           `hcl:"introspection" cty:"introspection"`
-	Input             *bool             `hcl:"input" cty:"input"`
-	Progress          *bool             `hcl:"progress" cty:"progress"`
-	Theme             *string           `hcl:"theme" cty:"theme"`
-	Cache             *bool             `hcl:"cache" cty:"cache"`
-	CacheTTL          *int              `hcl:"cache_ttl" cty:"cache_ttl"`
-	Base              *WorkspaceProfile `hcl:"base"`
+	CloudToken        *string `hcl:"cloud_token,optional" cty:"cloud_token"`
+	PipesToken        *string `hcl:"pipes_token,optional" cty:"pipes_token"`
+	InstallDir        *string `hcl:"install_dir,optional" cty:"install_dir"`
+	ModLocation       *string `hcl:"mod_location,optional" cty:"mod_location"`
+	QueryTimeout      *int    `hcl:"query_timeout,optional" cty:"query_timeout"`
+	SnapshotLocation  *string `hcl:"snapshot_location,optional" cty:"snapshot_location"`
+	WorkspaceDatabase *string `hcl:"workspace_database,optional" cty:"workspace_database"`
+	// the Turbot Pipes workspace to load default variable values from
+	VariablesWorkspace *string           `hcl:"variables_workspace,optional" cty:"variables_workspace"`
+	SearchPath         *string           `hcl:"search_path" cty:"search_path"`
+	SearchPathPrefix   *string           `hcl:"search_path_prefix" cty:"search_path_prefix"`
+	Watch              *bool             `hcl:"watch" cty:"watch"`
+	MaxParallel        *int              `hcl:"max_parallel" cty:"max-parallel"`
+	Introspection      *string           `hcl:"introspection" cty:"introspection"`
+	Input              *bool             `hcl:"input" cty:"input"`
+	Progress           *bool             `hcl:"progress" cty:"progress"`
+	Theme              *string           `hcl:"theme" cty:"theme"`
+	Cache              *bool             `hcl:"cache" cty:"cache"`
+	CacheTTL           *int              `hcl:"cache_ttl" cty:"cache_ttl"`
+	Base               *WorkspaceProfile `hcl:"base"`
 
 	// options
 	QueryOptions     *options.Query                     `cty:"query-options"`
 	CheckOptions     *options.Check                     `cty:"check-options"`
 	DashboardOptions *options.WorkspaceProfileDashboard `cty:"dashboard-options"`
-	DeclRange        hcl.Range
-	block            *hcl.Block
+	// the default flag values set by the command blocks of the profile, keyed by command name then flag name
+	CommandDefaults map[string]map[string]any
+	DeclRange       hcl.Range
+	block           *hcl.Block
 }
 
 func NewWorkspaceProfile(block *hcl.Block) *WorkspaceProfile {
@@ -84,6 +90,22 @@ func (p *WorkspaceProfile) SetOptions(opts options.Options, block *hcl.Block) hc
 	return diags
 }
 
+// SetCommandDefaults sets the default flag values of a command, defined by a command block of the profile
+func (p *WorkspaceProfile) SetCommandDefaults(cmdName string, defaults map[string]any, block *hcl.Block) hcl.Diagnostics {
+	if _, ok := p.CommandDefaults[cmdName]; ok {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("duplicate %s block", cmdName),
+			Subject:  hclhelpers.BlockRangePointer(block),
+		}}
+	}
+	if p.CommandDefaults == nil {
+		p.CommandDefaults = make(map[string]map[string]any)
+	}
+	p.CommandDefaults[cmdName] = defaults
+	return nil
+}
+
 func duplicateOptionsBlockDiag(block *hcl.Block) *hcl.Diagnostic {
 	return &hcl.Diagnostic{
 		Severity: hcl.DiagError,
@@ -138,9 +160,28 @@ func (p *WorkspaceProfile) validate() hcl.Diagnostics {
 			Subject:  hclhelpers.BlockRangePointer(p.block),
 		})
 	}
+	diags = append(diags, p.validateQueryOptions()...)
 	return diags
 }
 
+// validateQueryOptions checks the query options output is a valid query output format
+// this means an invalid default is reported when the config is loaded, rather than when a query is run
+func (p *WorkspaceProfile) validateQueryOptions() hcl.Diagnostics {
+	if p.QueryOptions == nil || p.QueryOptions.Output == nil {
+		return nil
+	}
+	// match case-sensitively, as the query command does
+	output := *p.QueryOptions.Output
+	if helpers.StringSliceContains(constants.QueryOutputFormats, output) {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("invalid query output format '%s' in workspace '%s', must be one of [%s]", output, p.ProfileName, strings.Join(constants.QueryOutputFormats, ", ")),
+		Subject:  hclhelpers.BlockRangePointer(p.block),
+	}}
+}
+
 func (p *WorkspaceProfile) setBaseProperties() {
 	if p.Base == nil {
 		return
@@ -164,6 +205,9 @@ func (p *WorkspaceProfile) setBaseProperties() {
 	if p.WorkspaceDatabase == nil {
 		p.WorkspaceDatabase = p.Base.WorkspaceDatabase
 	}
+	if p.VariablesWorkspace == nil {
+		p.VariablesWorkspace = p.Base.VariablesWorkspace
+	}
 	if p.QueryTimeout == nil {
 		p.QueryTimeout = p.Base.QueryTimeout
 	}
@@ -221,6 +265,22 @@ func (p *WorkspaceProfile) setBaseProperties() {
 	} else {
 		p.DashboardOptions.SetBaseProperties(p.Base.DashboardOptions)
 	}
+	// command defaults which are not set in my command block are inherited from the base command block
+	for cmdName, baseDefaults := range p.Base.CommandDefaults {
+		if p.CommandDefaults == nil {
+			p.CommandDefaults = make(map[string]map[string]any)
+		}
+		defaults, ok := p.CommandDefaults[cmdName]
+		if !ok {
+			defaults = make(map[string]any)
+			p.CommandDefaults[cmdName] = defaults
+		}
+		for flagName, value := range baseDefaults {
+			if _, ok := defaults[flagName]; !ok {
+				defaults[flagName] = value
+			}
+		}
+	}
 }
 
 // ConfigMap creates a config map containing all options to pass to viper
@@ -236,6 +296,7 @@ func (p *WorkspaceProfile) ConfigMap(cmd *cobra.Command) map[string]interface{}
 	res.SetStringItem(p.ModLocation, constants.ArgModLocation)
 	res.SetStringItem(p.SnapshotLocation, constants.ArgSnapshotLocation)
 	res.SetStringItem(p.WorkspaceDatabase, constants.ArgWorkspaceDatabase)
+	res.SetStringItem(p.VariablesWorkspace, constants.ArgVariablesWorkspace)
 	res.SetIntItem(p.QueryTimeout, constants.ArgDatabaseQueryTimeout)
 	res.SetBoolItem(p.Watch, constants.ArgWatch)
 	res.SetIntItem(p.MaxParallel, constants.ArgMaxParallel)
@@ -257,10 +318,38 @@ func (p *WorkspaceProfile) ConfigMap(cmd *cobra.Command) map[string]interface{}
 	if cmd.Name() == constants.CmdNameDashboard && p.DashboardOptions != nil {
 		res.PopulateConfigMapForOptions(p.DashboardOptions)
 	}
+	// command blocks take precedence over options blocks
+	p.populateConfigMapForCommand(res, cmd)
 
 	return res
 }
 
+// populateConfigMapForCommand adds the defaults set by the command block for the top level command of cmd
+// (so a 'plugin' block sets the defaults of all plugin subcommands)
+// defaults for flags which the command does not have (i.e. flags of other subcommands) are ignored
+// - attributes which are not flags of any subcommand are reported when the profile is parsed
+func (p *WorkspaceProfile) populateConfigMapForCommand(res ConfigMap, cmd *cobra.Command) {
+	defaults, ok := p.CommandDefaults[topLevelCommandName(cmd)]
+	if !ok {
+		return
+	}
+	for flagName, value := range defaults {
+		if cmd.Flags().Lookup(flagName) == nil {
+			log.Printf("[TRACE] ignoring default for '%s' from workspace '%s' - command '%s' has no such flag", flagName, p.ProfileName, cmd.CommandPath())
+			continue
+		}
+		res[flagName] = value
+	}
+}
+
+// topLevelCommandName returns the name of the child of the root command which cmd is (or is a descendant of)
+func topLevelCommandName(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
+
 // searchPathFromString checks that `str` is `nil` and returns a string slice with `str`
 // separated with `separator`
 // If `str` is `nil`, this returns a `nil`
@@ -0,0 +1,57 @@
+package modconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/cobra"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
+)
+
+func TestWorkspaceProfileConfigMapCommandDefaults(t *testing.T) {
+	root := &cobra.Command{Use: "steampipe"}
+	plugin := &cobra.Command{Use: "plugin"}
+	install := &cobra.Command{Use: "install"}
+	install.Flags().Int("concurrency", 4, "")
+	plugin.AddCommand(install)
+	root.AddCommand(plugin)
+
+	profile := &WorkspaceProfile{
+		ProfileName: "dev",
+		CommandDefaults: map[string]map[string]any{
+			"plugin": {"concurrency": 8, "prune": false},
+			"query":  {"output": "csv"},
+		},
+	}
+	res := profile.ConfigMap(install)
+	// the plugin block applies to plugin subcommands, ignoring flags the subcommand does not have
+	if !reflect.DeepEqual(res, map[string]any{"concurrency": 8}) {
+		t.Errorf("unexpected config map %v", res)
+	}
+}
+
+func TestWorkspaceProfileValidateQueryOptions(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: "json"},
+		{output: "sps"},
+		// the query command matches output formats case-sensitively, so config validation must too
+		{output: "JSON", wantErr: true},
+		{output: "yaml", wantErr: true},
+	}
+	for _, test := range tests {
+		output := test.output
+		profile := &WorkspaceProfile{
+			ProfileName:  "dev",
+			QueryOptions: &options.Query{Output: &output},
+			block:        &hcl.Block{},
+		}
+		diags := profile.validateQueryOptions()
+		if diags.HasErrors() != test.wantErr {
+			t.Errorf("output '%s': expected error %v, got %v", test.output, test.wantErr, diags)
+		}
+	}
+}
@@ -15,6 +15,7 @@ type Check struct {
 	Output    *string `hcl:"output" cty:"check_output"`
 	Separator *string `hcl:"separator" cty:"check_separator"`
 	Header    *bool   `hcl:"header" cty:"check_header"`
+	QuoteMode *string `hcl:"quote_mode" cty:"check_quote_mode"`
 	Timing    *string `hcl:"timing" cty:"check_timing"`
 }
 
@@ -35,6 +36,9 @@ func (t *Check) SetBaseProperties(otherOptions Options) {
 		if t.Header == nil && o.Header != nil {
 			t.Header = o.Header
 		}
+		if t.QuoteMode == nil && o.QuoteMode != nil {
+			t.QuoteMode = o.QuoteMode
+		}
 	}
 }
 
@@ -51,6 +55,9 @@ func (t *Check) ConfigMap() map[string]interface{} {
 	if t.Header != nil {
 		res[constants.ArgHeader] = t.Header
 	}
+	if t.QuoteMode != nil {
+		res[constants.ArgQuoteMode] = t.QuoteMode
+	}
 	if t.Timing != nil {
 		res[constants.ArgTiming] = t.Timing
 	}
@@ -71,6 +78,9 @@ func (t *Check) Merge(otherOptions Options) {
 		if o.Header != nil {
 			t.Header = o.Header
 		}
+		if o.QuoteMode != nil {
+			t.QuoteMode = o.QuoteMode
+		}
 		if o.Timing != nil {
 			t.Timing = o.Timing
 		}
@@ -97,6 +107,11 @@ func (t *Check) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  Header: %v", *t.Header))
 	}
+	if t.QuoteMode == nil {
+		str = append(str, "  QuoteMode: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  QuoteMode: %s", *t.QuoteMode))
+	}
 	if t.Timing == nil {
 		str = append(str, "  Timing: nil")
 	} else {
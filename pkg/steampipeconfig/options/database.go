@@ -16,6 +16,18 @@ type Database struct {
 	SearchPath       *string `hcl:"search_path"`
 	SearchPathPrefix *string `hcl:"search_path_prefix"`
 	StartTimeout     *int    `hcl:"start_timeout"`
+	// the number of connections which are updated in parallel during a connection refresh
+	// (this is also used as the size of the refresh connection pool)
+	RefreshConcurrency *int `hcl:"refresh_concurrency"`
+	// the maximum duration (in seconds) of a statement executed by a non-superuser session
+	MaxQueryDuration *int `hcl:"max_query_duration"`
+	// how connection names are mapped to schema names
+	SchemaNameCase         *string `hcl:"schema_name_case"`
+	SchemaNameInvalidChars *string `hcl:"schema_name_invalid_chars"`
+	// the interval (in minutes) at which the service vacuums and analyzes its internal tables - 0 disables maintenance
+	MaintenanceInterval *int `hcl:"maintenance_interval"`
+	// where the connection state files are saved: file (the install dir) or database (the internal schema)
+	StateStorage *string `hcl:"state_storage"`
 }
 
 // ConfigMap creates a config map that can be merged with viper
@@ -51,6 +63,24 @@ func (d *Database) ConfigMap() map[string]interface{} {
 	if d.CacheMaxSizeMb != nil {
 		res[constants.ArgMaxCacheSizeMb] = d.CacheMaxSizeMb
 	}
+	if d.RefreshConcurrency != nil {
+		res[constants.ArgRefreshConcurrency] = d.RefreshConcurrency
+	}
+	if d.MaxQueryDuration != nil {
+		res[constants.ArgMaxQueryDuration] = d.MaxQueryDuration
+	}
+	if d.SchemaNameCase != nil {
+		res[constants.ArgSchemaNameCase] = d.SchemaNameCase
+	}
+	if d.SchemaNameInvalidChars != nil {
+		res[constants.ArgSchemaNameInvalidChars] = d.SchemaNameInvalidChars
+	}
+	if d.MaintenanceInterval != nil {
+		res[constants.ArgMaintenanceInterval] = d.MaintenanceInterval
+	}
+	if d.StateStorage != nil {
+		res[constants.ArgStateStorage] = d.StateStorage
+	}
 	return res
 }
 
@@ -83,6 +113,24 @@ func (d *Database) Merge(otherOptions Options) {
 		if o.CacheMaxTtl != nil {
 			d.CacheMaxTtl = o.CacheMaxTtl
 		}
+		if o.RefreshConcurrency != nil {
+			d.RefreshConcurrency = o.RefreshConcurrency
+		}
+		if o.MaxQueryDuration != nil {
+			d.MaxQueryDuration = o.MaxQueryDuration
+		}
+		if o.SchemaNameCase != nil {
+			d.SchemaNameCase = o.SchemaNameCase
+		}
+		if o.SchemaNameInvalidChars != nil {
+			d.SchemaNameInvalidChars = o.SchemaNameInvalidChars
+		}
+		if o.MaintenanceInterval != nil {
+			d.MaintenanceInterval = o.MaintenanceInterval
+		}
+		if o.StateStorage != nil {
+			d.StateStorage = o.StateStorage
+		}
 	}
 }
 
@@ -131,5 +179,35 @@ func (d *Database) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  CacheMaxTtl: %d", *d.CacheMaxTtl))
 	}
+	if d.RefreshConcurrency == nil {
+		str = append(str, "  RefreshConcurrency: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  RefreshConcurrency: %d", *d.RefreshConcurrency))
+	}
+	if d.MaxQueryDuration == nil {
+		str = append(str, "  MaxQueryDuration: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  MaxQueryDuration: %d", *d.MaxQueryDuration))
+	}
+	if d.SchemaNameCase == nil {
+		str = append(str, "  SchemaNameCase: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  SchemaNameCase: %s", *d.SchemaNameCase))
+	}
+	if d.SchemaNameInvalidChars == nil {
+		str = append(str, "  SchemaNameInvalidChars: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  SchemaNameInvalidChars: %s", *d.SchemaNameInvalidChars))
+	}
+	if d.MaintenanceInterval == nil {
+		str = append(str, "  MaintenanceInterval: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  MaintenanceInterval: %d", *d.MaintenanceInterval))
+	}
+	if d.StateStorage == nil {
+		str = append(str, "  StateStorage: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  StateStorage: %s", *d.StateStorage))
+	}
 	return strings.Join(str, "\n")
 }
@@ -8,10 +8,15 @@ import (
 )
 
 type General struct {
-	UpdateCheck *string `hcl:"update_check"`
-	Telemetry   *string `hcl:"telemetry"`
-	LogLevel    *string `hcl:"log_level"`
-	MemoryMaxMb *int    `hcl:"memory_max_mb"`
+	UpdateCheck           *string `hcl:"update_check"`
+	Telemetry             *string `hcl:"telemetry"`
+	TelemetryRedact       *string `hcl:"telemetry_redact"`
+	TelemetryExport       *bool   `hcl:"telemetry_export"`
+	LogLevel              *string `hcl:"log_level"`
+	MemoryMaxMb           *int    `hcl:"memory_max_mb"`
+	LogRetentionDays      *int    `hcl:"log_retention_days"`
+	TempDirRetentionHours *int    `hcl:"temp_dir_retention_hours"`
+	StrictConfig          *bool   `hcl:"strict_config"`
 }
 
 // ConfigMap creates a config map that can be merged with viper
@@ -24,12 +29,27 @@ func (g *General) ConfigMap() map[string]interface{} {
 	if g.Telemetry != nil {
 		res[constants.ArgTelemetry] = g.Telemetry
 	}
+	if g.TelemetryRedact != nil {
+		res[constants.ArgTelemetryRedact] = g.TelemetryRedact
+	}
+	if g.TelemetryExport != nil {
+		res[constants.ArgTelemetryExport] = g.TelemetryExport
+	}
 	if g.LogLevel != nil {
 		res[constants.ArgLogLevel] = g.LogLevel
 	}
 	if g.MemoryMaxMb != nil {
 		res[constants.ArgMemoryMaxMb] = g.MemoryMaxMb
 	}
+	if g.LogRetentionDays != nil {
+		res[constants.ArgLogRetentionDays] = g.LogRetentionDays
+	}
+	if g.TempDirRetentionHours != nil {
+		res[constants.ArgTempDirRetentionHours] = g.TempDirRetentionHours
+	}
+	if g.StrictConfig != nil {
+		res[constants.ArgStrictConfig] = g.StrictConfig
+	}
 
 	return res
 }
@@ -42,6 +62,21 @@ func (g *General) Merge(otherOptions Options) {
 		if o.UpdateCheck != nil {
 			g.UpdateCheck = o.UpdateCheck
 		}
+		if o.TelemetryRedact != nil {
+			g.TelemetryRedact = o.TelemetryRedact
+		}
+		if o.TelemetryExport != nil {
+			g.TelemetryExport = o.TelemetryExport
+		}
+		if o.LogRetentionDays != nil {
+			g.LogRetentionDays = o.LogRetentionDays
+		}
+		if o.TempDirRetentionHours != nil {
+			g.TempDirRetentionHours = o.TempDirRetentionHours
+		}
+		if o.StrictConfig != nil {
+			g.StrictConfig = o.StrictConfig
+		}
 	}
 }
 
@@ -60,6 +95,16 @@ func (g *General) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  Telemetry: %s", *g.Telemetry))
 	}
+	if g.TelemetryRedact == nil {
+		str = append(str, "  TelemetryRedact: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  TelemetryRedact: %s", *g.TelemetryRedact))
+	}
+	if g.TelemetryExport == nil {
+		str = append(str, "  TelemetryExport: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  TelemetryExport: %t", *g.TelemetryExport))
+	}
 	if g.LogLevel == nil {
 		str = append(str, "  LogLevel: nil")
 	} else {
@@ -71,5 +116,20 @@ func (g *General) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  MemoryMaxMb: %d", *g.MemoryMaxMb))
 	}
+	if g.LogRetentionDays == nil {
+		str = append(str, "  LogRetentionDays: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  LogRetentionDays: %d", *g.LogRetentionDays))
+	}
+	if g.TempDirRetentionHours == nil {
+		str = append(str, "  TempDirRetentionHours: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  TempDirRetentionHours: %d", *g.TempDirRetentionHours))
+	}
+	if g.StrictConfig == nil {
+		str = append(str, "  StrictConfig: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  StrictConfig: %t", *g.StrictConfig))
+	}
 	return strings.Join(str, "\n")
 }
@@ -0,0 +1,60 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// Image is the verification settings for the plugin and database images which are installed
+type Image struct {
+	Verify    *string `hcl:"verify"`
+	VerifyKey *string `hcl:"verify_key"`
+}
+
+// ConfigMap creates a config map that can be merged with viper
+func (i *Image) ConfigMap() map[string]interface{} {
+	// only add keys which are non-null
+	res := map[string]interface{}{}
+	if i.Verify != nil {
+		res[constants.ArgImageVerify] = i.Verify
+	}
+	if i.VerifyKey != nil {
+		res[constants.ArgImageVerifyKey] = i.VerifyKey
+	}
+
+	return res
+}
+
+// Merge merges other options over the top of this options object
+// i.e. if a property is set in otherOptions, it takes precedence
+func (i *Image) Merge(otherOptions Options) {
+	switch o := otherOptions.(type) {
+	case *Image:
+		if o.Verify != nil {
+			i.Verify = o.Verify
+		}
+		if o.VerifyKey != nil {
+			i.VerifyKey = o.VerifyKey
+		}
+	}
+}
+
+func (i *Image) String() string {
+	if i == nil {
+		return ""
+	}
+	var str []string
+	if i.Verify == nil {
+		str = append(str, "  Verify: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  Verify: %s", *i.Verify))
+	}
+	if i.VerifyKey == nil {
+		str = append(str, "  VerifyKey: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  VerifyKey: %s", *i.VerifyKey))
+	}
+	return strings.Join(str, "\n")
+}
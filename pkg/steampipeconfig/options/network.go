@@ -0,0 +1,97 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// Network is the CA bundle, proxy and TLS settings used for all outbound requests
+type Network struct {
+	CaCertFile    *string `hcl:"ca_cert_file"`
+	HttpProxy     *string `hcl:"http_proxy"`
+	HttpsProxy    *string `hcl:"https_proxy"`
+	NoProxy       *string `hcl:"no_proxy"`
+	TlsSkipVerify *bool   `hcl:"tls_skip_verify"`
+}
+
+// ConfigMap creates a config map that can be merged with viper
+func (n *Network) ConfigMap() map[string]interface{} {
+	// only add keys which are non-null
+	res := map[string]interface{}{}
+	if n.CaCertFile != nil {
+		res[constants.ArgCaCertFile] = n.CaCertFile
+	}
+	if n.HttpProxy != nil {
+		res[constants.ArgHttpProxy] = n.HttpProxy
+	}
+	if n.HttpsProxy != nil {
+		res[constants.ArgHttpsProxy] = n.HttpsProxy
+	}
+	if n.NoProxy != nil {
+		res[constants.ArgNoProxy] = n.NoProxy
+	}
+	if n.TlsSkipVerify != nil {
+		res[constants.ArgTlsSkipVerify] = n.TlsSkipVerify
+	}
+
+	return res
+}
+
+// Merge merges other options over the top of this options object
+// i.e. if a property is set in otherOptions, it takes precedence
+func (n *Network) Merge(otherOptions Options) {
+	switch o := otherOptions.(type) {
+	case *Network:
+		if o.CaCertFile != nil {
+			n.CaCertFile = o.CaCertFile
+		}
+		if o.HttpProxy != nil {
+			n.HttpProxy = o.HttpProxy
+		}
+		if o.HttpsProxy != nil {
+			n.HttpsProxy = o.HttpsProxy
+		}
+		if o.NoProxy != nil {
+			n.NoProxy = o.NoProxy
+		}
+		if o.TlsSkipVerify != nil {
+			n.TlsSkipVerify = o.TlsSkipVerify
+		}
+	}
+}
+
+func (n *Network) String() string {
+	if n == nil {
+		return ""
+	}
+	var str []string
+	if n.CaCertFile == nil {
+		str = append(str, "  CaCertFile: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  CaCertFile: %s", *n.CaCertFile))
+	}
+	// NOTE: proxy urls may contain credentials so just show whether they are set
+	if n.HttpProxy == nil {
+		str = append(str, "  HttpProxy: nil")
+	} else {
+		str = append(str, "  HttpProxy: <set>")
+	}
+	if n.HttpsProxy == nil {
+		str = append(str, "  HttpsProxy: nil")
+	} else {
+		str = append(str, "  HttpsProxy: <set>")
+	}
+	if n.NoProxy == nil {
+		str = append(str, "  NoProxy: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  NoProxy: %s", *n.NoProxy))
+	}
+	if n.TlsSkipVerify == nil {
+		str = append(str, "  TlsSkipVerify: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  TlsSkipVerify: %t", *n.TlsSkipVerify))
+	}
+	return strings.Join(str, "\n")
+}
@@ -10,6 +10,8 @@ const (
 	GeneralBlock    = "general"
 	TerminalBlock   = "terminal"
 	PluginBlock     = "plugin"
+	NetworkBlock    = "network"
+	ImageBlock      = "image"
 )
 
 type Options interface {
@@ -8,15 +8,20 @@ import (
 
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
 )
 
 type Query struct {
 	Output       *string `hcl:"output" cty:"query_output"`
 	Separator    *string `hcl:"separator" cty:"query_separator"`
 	Header       *bool   `hcl:"header" cty:"query_header"`
+	QuoteMode    *string `hcl:"quote_mode" cty:"query_quote_mode"`
 	Multi        *bool   `hcl:"multi" cty:"query_multi"`
 	Timing       *string `cty:"query_timing"` // parsed manually
 	AutoComplete *bool   `hcl:"autocomplete" cty:"query_autocomplete"`
+	// the timezone and format used to display timestamps in table and line output
+	Timezone        *string `hcl:"timezone" cty:"query_timezone"`
+	TimestampFormat *string `hcl:"timestamp_format" cty:"query_timestamp_format"`
 }
 
 func (t *Query) SetBaseProperties(otherOptions Options) {
@@ -33,6 +38,9 @@ func (t *Query) SetBaseProperties(otherOptions Options) {
 		if t.Header == nil && o.Header != nil {
 			t.Header = o.Header
 		}
+		if t.QuoteMode == nil && o.QuoteMode != nil {
+			t.QuoteMode = o.QuoteMode
+		}
 		if t.Multi == nil && o.Multi != nil {
 			t.Multi = o.Multi
 		}
@@ -42,6 +50,12 @@ func (t *Query) SetBaseProperties(otherOptions Options) {
 		if t.AutoComplete == nil && o.AutoComplete != nil {
 			t.AutoComplete = o.AutoComplete
 		}
+		if t.Timezone == nil && o.Timezone != nil {
+			t.Timezone = o.Timezone
+		}
+		if t.TimestampFormat == nil && o.TimestampFormat != nil {
+			t.TimestampFormat = o.TimestampFormat
+		}
 	}
 }
 
@@ -58,6 +72,9 @@ func (t *Query) ConfigMap() map[string]interface{} {
 	if t.Header != nil {
 		res[constants.ArgHeader] = t.Header
 	}
+	if t.QuoteMode != nil {
+		res[constants.ArgQuoteMode] = t.QuoteMode
+	}
 	if t.Multi != nil {
 		res[constants.ArgMultiLine] = t.Multi
 	}
@@ -67,6 +84,12 @@ func (t *Query) ConfigMap() map[string]interface{} {
 	if t.AutoComplete != nil {
 		res[constants.ArgAutoComplete] = t.AutoComplete
 	}
+	if t.Timezone != nil {
+		res[constants.ArgTimezone] = t.Timezone
+	}
+	if t.TimestampFormat != nil {
+		res[constants.ArgTimestampFormat] = t.TimestampFormat
+	}
 	return res
 }
 
@@ -87,6 +110,9 @@ func (t *Query) Merge(otherOptions Options) {
 		if o.Header != nil {
 			t.Header = o.Header
 		}
+		if o.QuoteMode != nil {
+			t.QuoteMode = o.QuoteMode
+		}
 		if o.Multi != nil {
 			t.Multi = o.Multi
 		}
@@ -96,6 +122,12 @@ func (t *Query) Merge(otherOptions Options) {
 		if o.AutoComplete != nil {
 			t.AutoComplete = o.AutoComplete
 		}
+		if o.Timezone != nil {
+			t.Timezone = o.Timezone
+		}
+		if o.TimestampFormat != nil {
+			t.TimestampFormat = o.TimestampFormat
+		}
 	}
 }
 
@@ -119,6 +151,11 @@ func (t *Query) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  Header: %v", *t.Header))
 	}
+	if t.QuoteMode == nil {
+		str = append(str, "  QuoteMode: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  QuoteMode: %s", *t.QuoteMode))
+	}
 	if t.Multi == nil {
 		str = append(str, "  Multi: nil")
 	} else {
@@ -134,6 +171,16 @@ func (t *Query) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  AutoComplete: %v", *t.AutoComplete))
 	}
+	if t.Timezone == nil {
+		str = append(str, "  Timezone: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  Timezone: %s", *t.Timezone))
+	}
+	if t.TimestampFormat == nil {
+		str = append(str, "  TimestampFormat: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  TimestampFormat: %s", *t.TimestampFormat))
+	}
 	return strings.Join(str, "\n")
 }
 
@@ -152,3 +199,25 @@ func (t *Query) SetTiming(flag string, r hcl.Range) hcl.Diagnostics {
 
 	return nil
 }
+
+// Validate implements CanValidate
+func (t *Query) Validate() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if t.TimestampFormat != nil {
+		if _, err := utils.TimestampLayout(*t.TimestampFormat); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid timestamp_format value: %s", err.Error()),
+			})
+		}
+	}
+	if t.Timezone != nil {
+		if _, err := utils.ParseTimezone(*t.Timezone); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid timezone value '%s' - must be 'local', 'utc' or an IANA timezone name, e.g. 'Europe/London'", *t.Timezone),
+			})
+		}
+	}
+	return diags
+}
@@ -5,3 +5,8 @@ import "github.com/hashicorp/hcl/v2"
 type CanSetTiming interface {
 	SetTiming(flag string, r hcl.Range) hcl.Diagnostics
 }
+
+// CanValidate is implemented by options which validate their values after decoding
+type CanValidate interface {
+	Validate() hcl.Diagnostics
+}
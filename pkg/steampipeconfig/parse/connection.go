@@ -53,6 +53,54 @@ func DecodeConnection(block *hcl.Block) (*modconfig.Connection, hcl.Diagnostics)
 		}
 		connection.ConnectionNames = connections
 	}
+	if connectionContent.Attributes["connection_column"] != nil {
+		var connectionColumn bool
+		diags = gohcl.DecodeExpression(connectionContent.Attributes["connection_column"].Expr, nil, &connectionColumn)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		connection.ConnectionColumn = connectionColumn
+	}
+	if connectionContent.Attributes["dedup_keys"] != nil {
+		var dedupKeys []string
+		diags = gohcl.DecodeExpression(connectionContent.Attributes["dedup_keys"].Expr, nil, &dedupKeys)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		connection.DedupKeys = dedupKeys
+	}
+	if connectionContent.Attributes["mask_columns"] != nil {
+		var maskColumns []string
+		diags = gohcl.DecodeExpression(connectionContent.Attributes["mask_columns"].Expr, nil, &maskColumns)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		connection.MaskColumns = maskColumns
+	}
+	if connectionContent.Attributes["masking_policy"] != nil {
+		var maskingPolicy string
+		diags = gohcl.DecodeExpression(connectionContent.Attributes["masking_policy"].Expr, nil, &maskingPolicy)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		connection.MaskingPolicy = maskingPolicy
+	}
+	if connectionContent.Attributes["column_types"] != nil {
+		var columnTypes map[string]string
+		diags = gohcl.DecodeExpression(connectionContent.Attributes["column_types"].Expr, nil, &columnTypes)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		connection.ColumnTypes = columnTypes
+	}
+	if connectionContent.Attributes["plugin_version"] != nil {
+		var pluginVersion string
+		diags = gohcl.DecodeExpression(connectionContent.Attributes["plugin_version"].Expr, nil, &pluginVersion)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		connection.PluginVersion = pluginVersion
+	}
 
 	// check for nested options
 	for _, connectionBlock := range connectionContent.Blocks {
@@ -0,0 +1,16 @@
+package parse
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func DecodeConnectionDiscovery(block *hcl.Block) (*modconfig.ConnectionDiscovery, hcl.Diagnostics) {
+	discovery := modconfig.NewConnectionDiscovery(block)
+	diags := gohcl.DecodeBody(block.Body, nil, discovery)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return discovery, diags
+}
@@ -42,6 +42,18 @@ func DecodeOptions(block *hcl.Block, overrides ...BlockMappingOverride) (options
 		return nil, diags
 	}
 
+	if validator, ok := destination.(options.CanValidate); ok {
+		for _, diag := range validator.Validate() {
+			if diag.Subject == nil {
+				diag.Subject = hclhelpers.BlockRangePointer(block)
+			}
+			diags = append(diags, diag)
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
 	return destination, nil
 }
 
@@ -86,6 +98,8 @@ func defaultOptionsBlockMapping() OptionsBlockMapping {
 		options.CheckBlock:     &options.Check{},
 		options.DashboardBlock: &options.GlobalDashboard{},
 		options.PluginBlock:    &options.Plugin{},
+		options.NetworkBlock:   &options.Network{},
+		options.ImageBlock:     &options.Image{},
 	}
 	return mapping
 }
@@ -0,0 +1,54 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
+)
+
+func TestDecodeQueryOptionsTimestampFormat(t *testing.T) {
+	tests := map[string]struct {
+		config   string
+		expected string
+		err      bool
+	}{
+		"named format": {
+			config:   `options "query" { timestamp_format = "rfc3339ms" }`,
+			expected: "rfc3339ms",
+		},
+		"go layout": {
+			config:   `options "query" { timestamp_format = "02 Jan 06 15:04" }`,
+			expected: "02 Jan 06 15:04",
+		},
+		"invalid layout": {
+			config: `options "query" { timestamp_format = "dd/mm/yyyy" }`,
+			err:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(test.config), "test.spc", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			block := file.Body.(*hclsyntax.Body).Blocks[0].AsHCLBlock()
+
+			opts, diags := DecodeOptions(block)
+			if test.err {
+				if !diags.HasErrors() {
+					t.Errorf("expected an error decoding '%s'", test.config)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected error: %s", diags.Error())
+			}
+			queryOptions := opts.(*options.Query)
+			if queryOptions.TimestampFormat == nil || *queryOptions.TimestampFormat != test.expected {
+				t.Errorf("expected timestamp format '%s', got %v", test.expected, queryOptions.TimestampFormat)
+			}
+		})
+	}
+}
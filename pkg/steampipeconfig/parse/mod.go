@@ -29,7 +29,7 @@ func LoadModfile(modPath string) (*modconfig.Mod, error) {
 
 	mod, res := ParseModDefinition(modFilePath, evalCtx)
 	if res.Diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load mod", res.Diags)
+		return nil, error_helpers.HclDiagsToError("Failed to load mod", res.Diags)
 	}
 
 	return mod, nil
@@ -100,13 +100,13 @@ func ParseModDefinition(modFilePath string, evalCtx *hcl.EvalContext) (*modconfi
 func ParseMod(ctx context.Context, fileData map[string][]byte, pseudoResources []modconfig.MappableResource, parseCtx *ModParseContext) (*modconfig.Mod, error_helpers.ErrorAndWarnings) {
 	body, diags := ParseHclFiles(fileData)
 	if diags.HasErrors() {
-		return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to load all mod source files", diags))
+		return nil, error_helpers.NewErrorsAndWarning(error_helpers.HclDiagsToError("Failed to load all mod source files", diags))
 	}
 
 	content, moreDiags := body.Content(WorkspaceBlockSchema)
 	if moreDiags.HasErrors() {
 		diags = append(diags, moreDiags...)
-		return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to load mod", diags))
+		return nil, error_helpers.NewErrorsAndWarning(error_helpers.HclDiagsToError("Failed to load mod", diags))
 	}
 
 	mod := parseCtx.CurrentMod
@@ -123,7 +123,7 @@ func ParseMod(ctx context.Context, fileData map[string][]byte, pseudoResources [
 	if parseCtx.Variables != nil {
 		for _, v := range parseCtx.Variables.RootVariables {
 			if diags = mod.AddResource(v); diags.HasErrors() {
-				return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to add resource to mod", diags))
+				return nil, error_helpers.NewErrorsAndWarning(error_helpers.HclDiagsToError("Failed to add resource to mod", diags))
 			}
 		}
 	}
@@ -144,7 +144,7 @@ func ParseMod(ctx context.Context, fileData map[string][]byte, pseudoResources [
 	// - this it to ensure all pseudo resources get added and build the eval context with the variables we just added
 	// - it also adds the top level resources of the any dependency mods
 	if diags = parseCtx.AddModResources(mod); diags.HasErrors() {
-		return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to add mod to run context", diags))
+		return nil, error_helpers.NewErrorsAndWarning(error_helpers.HclDiagsToError("Failed to add mod to run context", diags))
 	}
 
 	// we may need to decode more than once as we gather dependencies as we go
@@ -153,7 +153,7 @@ func ParseMod(ctx context.Context, fileData map[string][]byte, pseudoResources [
 	for attempts := 0; ; attempts++ {
 		diags = decode(parseCtx)
 		if diags.HasErrors() {
-			return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to decode all mod hcl files", diags))
+			return nil, error_helpers.NewErrorsAndWarning(error_helpers.HclDiagsToError("Failed to decode all mod hcl files", diags))
 		}
 		// now retrieve the warning strings
 		res.AddWarning(plugin.DiagsToWarnings(diags)...)
@@ -13,7 +13,6 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/json"
-	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
@@ -171,13 +170,13 @@ func ParseModResourceNames(fileData map[string][]byte) (*modconfig.WorkspaceReso
 	var resources = modconfig.NewWorkspaceResources()
 	body, diags := ParseHclFiles(fileData)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load all mod source files", diags)
+		return nil, error_helpers.HclDiagsToError("Failed to load all mod source files", diags)
 	}
 
 	content, moreDiags := body.Content(WorkspaceBlockSchema)
 	if moreDiags.HasErrors() {
 		diags = append(diags, moreDiags...)
-		return nil, plugin.DiagsToError("Failed to load mod", diags)
+		return nil, error_helpers.HclDiagsToError("Failed to load mod", diags)
 	}
 
 	for _, block := range content.Blocks {
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 )
 
@@ -27,6 +28,10 @@ var ConfigBlockSchema = &hcl.BodySchema{
 			Type:       modconfig.BlockTypeWorkspaceProfile,
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       modconfig.BlockTypeDiscovery,
+			LabelNames: []string{"name"},
+		},
 	},
 }
 var PluginBlockSchema = &hcl.BodySchema{
@@ -41,12 +46,22 @@ var PluginBlockSchema = &hcl.BodySchema{
 
 var WorkspaceProfileBlockSchema = &hcl.BodySchema{
 
-	Blocks: []hcl.BlockHeaderSchema{
+	Blocks: append([]hcl.BlockHeaderSchema{
 		{
 			Type:       "options",
 			LabelNames: []string{"type"},
 		},
-	},
+	}, workspaceProfileCommandBlockSchemas()...),
+}
+
+// workspaceProfileCommandBlockSchemas returns the schemas of the command blocks of a workspace profile
+// e.g. check { output = "brief" }
+func workspaceProfileCommandBlockSchemas() []hcl.BlockHeaderSchema {
+	var res []hcl.BlockHeaderSchema
+	for _, cmdName := range constants.WorkspaceProfileCommands {
+		res = append(res, hcl.BlockHeaderSchema{Type: cmdName})
+	}
+	return res
 }
 
 var ConnectionBlockSchema = &hcl.BodySchema{
@@ -64,6 +79,24 @@ var ConnectionBlockSchema = &hcl.BodySchema{
 		{
 			Name: "import_schema",
 		},
+		{
+			Name: "connection_column",
+		},
+		{
+			Name: "dedup_keys",
+		},
+		{
+			Name: "mask_columns",
+		},
+		{
+			Name: "masking_policy",
+		},
+		{
+			Name: "column_types",
+		},
+		{
+			Name: "plugin_version",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -4,19 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/secrets"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
+	"github.com/zclconf/go-cty/cty"
 )
 
-func LoadWorkspaceProfiles(ctx context.Context, workspaceProfilePath string) (profileMap map[string]*modconfig.WorkspaceProfile, err error) {
+// LoadWorkspaceProfiles loads the workspace profiles defined in the given folder
+// the returned warnings describe config which has been ignored (e.g. options blocks with unsupported attributes)
+func LoadWorkspaceProfiles(ctx context.Context, workspaceProfilePath string) (profileMap map[string]*modconfig.WorkspaceProfile, warnings []string, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -36,26 +46,32 @@ func LoadWorkspaceProfiles(ctx context.Context, workspaceProfilePath string) (pr
 		Include: filehelpers.InclusionsFromExtensions([]string{constants.ConfigExtension}),
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(configPaths) == 0 {
-		return profileMap, nil
+		return profileMap, nil, nil
 	}
 
 	fileData, diags := LoadFileData(configPaths...)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load workspace profiles", diags)
+		return nil, nil, error_helpers.HclDiagsToError("Failed to load workspace profiles", diags)
+	}
+
+	// decrypt any encrypted config values
+	fileData, err = secrets.DecryptFileData(fileData)
+	if err != nil {
+		return nil, nil, sperr.WrapWithMessage(err, "failed to decrypt workspace profiles")
 	}
 
 	body, diags := ParseHclFiles(fileData)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load workspace profiles", diags)
+		return nil, nil, error_helpers.HclDiagsToError("Failed to load workspace profiles", diags)
 	}
 
 	// do a partial decode
 	content, diags := body.Content(ConfigBlockSchema)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load workspace profiles", diags)
+		return nil, nil, error_helpers.HclDiagsToError("Failed to load workspace profiles", diags)
 	}
 
 	parseCtx := NewWorkspaceProfileParseContext(workspaceProfilePath)
@@ -65,15 +81,18 @@ func LoadWorkspaceProfiles(ctx context.Context, workspaceProfilePath string) (pr
 	return parseWorkspaceProfiles(parseCtx)
 
 }
-func parseWorkspaceProfiles(parseCtx *WorkspaceProfileParseContext) (map[string]*modconfig.WorkspaceProfile, error) {
+func parseWorkspaceProfiles(parseCtx *WorkspaceProfileParseContext) (map[string]*modconfig.WorkspaceProfile, []string, error) {
+	var warnings []string
 	// we may need to decode more than once as we gather dependencies as we go
 	// continue decoding as long as the number of unresolved blocks decreases
 	prevUnresolvedBlocks := 0
 	for attempts := 0; ; attempts++ {
 		_, diags := decodeWorkspaceProfiles(parseCtx)
 		if diags.HasErrors() {
-			return nil, plugin.DiagsToError("Failed to decode all workspace profile files", diags)
+			return nil, nil, error_helpers.HclDiagsToError("Failed to decode all workspace profile files", diags)
 		}
+		// each profile is only successfully decoded once, so warnings are not duplicated between attempts
+		warnings = append(warnings, plugin.DiagsToWarnings(diags)...)
 
 		// if there are no unresolved blocks, we are done
 		unresolvedBlocks := len(parseCtx.UnresolvedBlocks)
@@ -84,13 +103,13 @@ func parseWorkspaceProfiles(parseCtx *WorkspaceProfileParseContext) (map[string]
 		// if the number of unresolved blocks has NOT reduced, fail
 		if prevUnresolvedBlocks != 0 && unresolvedBlocks >= prevUnresolvedBlocks {
 			str := parseCtx.FormatDependencies()
-			return nil, fmt.Errorf("failed to resolve workspace profile dependencies after %d attempts\nDependencies:\n%s", attempts+1, str)
+			return nil, nil, fmt.Errorf("failed to resolve workspace profile dependencies after %d attempts\nDependencies:\n%s", attempts+1, str)
 		}
 		// update prevUnresolvedBlocks
 		prevUnresolvedBlocks = unresolvedBlocks
 	}
 
-	return parseCtx.workspaceProfiles, nil
+	return parseCtx.workspaceProfiles, warnings, nil
 
 }
 
@@ -132,6 +151,83 @@ func decodeWorkspaceProfileOption(block *hcl.Block) (options.Options, hcl.Diagno
 	return DecodeOptions(block, WithOverride(constants.CmdNameDashboard, &options.WorkspaceProfileDashboard{}))
 }
 
+// decodeWorkspaceProfileCommandDefaults decodes a command block of a workspace profile, e.g. check { output = "brief" }
+// the attributes are the default values of the flags of the command, with underscores in place of hyphens
+// attributes which are not flags of the command (or any of its subcommands), or which have invalid values, are
+// not included in the defaults, and are returned as error diags
+func decodeWorkspaceProfileCommandDefaults(block *hcl.Block, parseCtx *WorkspaceProfileParseContext) (map[string]any, hcl.Diagnostics) {
+	attrs, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	flagNames, validateFlags := commandFlagNames(block.Type)
+	defaults := make(map[string]any, len(attrs))
+	for name, attr := range attrs {
+		flagName := strings.ReplaceAll(name, "_", "-")
+		if _, ok := flagNames[flagName]; validateFlags && !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Unsupported attribute '%s' in %s block", name, block.Type),
+				Detail:   fmt.Sprintf("command '%s' has no flag --%s", block.Type, flagName),
+				Subject:  attr.NameRange.Ptr(),
+			})
+			continue
+		}
+		val, moreDiags := attr.Expr.Value(parseCtx.EvalCtx)
+		if moreDiags.HasErrors() {
+			diags = append(diags, moreDiags...)
+			continue
+		}
+		// support boolean timing values, as for options blocks
+		if name == constants.ArgTiming && val.Type() == cty.Bool && val.IsKnown() && !val.IsNull() {
+			if val.True() {
+				val = cty.StringVal(constants.ArgOn)
+			} else {
+				val = cty.StringVal(constants.ArgOff)
+			}
+		}
+		goVal, err := hclhelpers.CtyToGo(val)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("invalid value for '%s' in %s block: %s", name, block.Type, err.Error()),
+				Subject:  attr.Range.Ptr(),
+			})
+			continue
+		}
+		defaults[flagName] = goVal
+	}
+	return defaults, diags
+}
+
+// commandFlagNames returns the names of the flags of the top level command with the given name and all its subcommands
+// returns false if the command is not known (i.e. config is not being loaded for a command), so the flags cannot be validated
+func commandFlagNames(cmdName string) (map[string]struct{}, bool) {
+	activeCmd, ok := viper.Get(constants.ConfigKeyActiveCommand).(*cobra.Command)
+	if !ok {
+		return nil, false
+	}
+	for _, cmd := range activeCmd.Root().Commands() {
+		if cmd.Name() == cmdName {
+			flagNames := make(map[string]struct{})
+			addCommandFlagNames(cmd, flagNames)
+			return flagNames, true
+		}
+	}
+	return nil, false
+}
+
+func addCommandFlagNames(cmd *cobra.Command, flagNames map[string]struct{}) {
+	addFlagName := func(flag *pflag.Flag) {
+		flagNames[flag.Name] = struct{}{}
+	}
+	cmd.Flags().VisitAll(addFlagName)
+	cmd.InheritedFlags().VisitAll(addFlagName)
+	for _, child := range cmd.Commands() {
+		addCommandFlagNames(child, flagNames)
+	}
+}
+
 func decodeWorkspaceProfile(block *hcl.Block, parseCtx *WorkspaceProfileParseContext) (*modconfig.WorkspaceProfile, *DecodeResult) {
 	res := newDecodeResult()
 	// get shell resource
@@ -148,6 +244,15 @@ func decodeWorkspaceProfile(block *hcl.Block, parseCtx *WorkspaceProfileParseCon
 	if len(diags) > 0 {
 		res.handleDecodeDiags(diags)
 	}
+	// the base profile is decoded from its cty value, which does not include the command defaults
+	// - use the decoded base profile instead
+	if resource.Base != nil {
+		if base, ok := parseCtx.workspaceProfiles[resource.Base.ProfileName]; ok {
+			resource.Base = base
+		}
+	}
+	// diags for options blocks which cannot be applied
+	var optionsDiags hcl.Diagnostics
 	// use a map keyed by a string for fast lookup
 	// we use an empty struct as the value type, so that
 	// we don't use up unnecessary memory
@@ -158,7 +263,7 @@ func decodeWorkspaceProfile(block *hcl.Block, parseCtx *WorkspaceProfileParseCon
 			optionsBlockType := block.Labels[0]
 			if _, found := foundOptions[optionsBlockType]; found {
 				// fail
-				diags = append(diags, &hcl.Diagnostic{
+				optionsDiags = append(optionsDiags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Subject:  hclhelpers.BlockRangePointer(block),
 					Summary:  fmt.Sprintf("Duplicate options type '%s'", optionsBlockType),
@@ -166,23 +271,39 @@ func decodeWorkspaceProfile(block *hcl.Block, parseCtx *WorkspaceProfileParseCon
 			}
 			opts, moreDiags := decodeWorkspaceProfileOption(block)
 			if moreDiags.HasErrors() {
-				diags = append(diags, moreDiags...)
+				optionsDiags = append(optionsDiags, moreDiags...)
 				break
 			}
 			moreDiags = resource.SetOptions(opts, block)
 			if moreDiags.HasErrors() {
-				diags = append(diags, moreDiags...)
+				optionsDiags = append(optionsDiags, moreDiags...)
 			}
 			foundOptions[optionsBlockType] = struct{}{}
 		default:
-			// this should never happen
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  fmt.Sprintf("invalid block type '%s' - only 'options' blocks are supported for workspace profiles", block.Type),
-				Subject:  hclhelpers.BlockRangePointer(block),
-			})
+			if !helpers.StringSliceContains(constants.WorkspaceProfileCommands, block.Type) {
+				// this should never happen
+				optionsDiags = append(optionsDiags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("invalid block type '%s' - only 'options' and command blocks are supported for workspace profiles", block.Type),
+					Subject:  hclhelpers.BlockRangePointer(block),
+				})
+				break
+			}
+			// attributes which fail to decode are ignored - the rest of the command block is applied
+			defaults, moreDiags := decodeWorkspaceProfileCommandDefaults(block, parseCtx)
+			optionsDiags = append(optionsDiags, moreDiags...)
+			if defaults == nil {
+				break
+			}
+			optionsDiags = append(optionsDiags, resource.SetCommandDefaults(block.Type, defaults, block)...)
 		}
 	}
+	// options blocks which fail to decode (e.g. because they have unsupported attributes) are ignored
+	// raise the failures as warnings so they are reported (and are errors in strict config mode)
+	// NOTE: only do this if there are no unresolved dependencies, as the profile will be decoded again
+	if len(res.Depends) == 0 {
+		res.addDiags(ignoredConfigDiags(optionsDiags))
+	}
 
 	handleWorkspaceProfileDecodeResult(resource, res, block, parseCtx)
 	return resource, res
@@ -206,3 +327,19 @@ func handleWorkspaceProfileDecodeResult(resource *modconfig.WorkspaceProfile, re
 		res.addDiags(moreDiags)
 	}
 }
+
+// ignoredConfigDiags converts the diags for config which is being ignored into warnings
+func ignoredConfigDiags(diags hcl.Diagnostics) hcl.Diagnostics {
+	var res hcl.Diagnostics
+	for _, diag := range diags {
+		warning := *diag
+		warning.Severity = hcl.DiagWarning
+		if warning.Detail == "" {
+			warning.Detail = "this setting is ignored"
+		} else {
+			warning.Detail += " - this setting is ignored"
+		}
+		res = append(res, &warning)
+	}
+	return res
+}
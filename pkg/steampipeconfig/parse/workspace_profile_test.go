@@ -0,0 +1,140 @@
+package parse
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestLoadWorkspaceProfilesIgnoredOptions(t *testing.T) {
+	dir := t.TempDir()
+	config := `
+workspace "dev" {
+  query_timeout = 300
+  options "query" {
+    multi  = true
+    chache = true
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "workspaces.spc"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, warnings, err := LoadWorkspaceProfiles(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	profile, ok := profiles["dev"]
+	if !ok {
+		t.Fatal("workspace profile 'dev' was not loaded")
+	}
+	if profile.QueryTimeout == nil || *profile.QueryTimeout != 300 {
+		t.Errorf("expected query_timeout to be 300")
+	}
+	if profile.QueryOptions != nil {
+		t.Errorf("expected the invalid query options block to be ignored")
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "chache") {
+		t.Errorf("expected a single warning for the unsupported 'chache' attribute, got %v", warnings)
+	}
+}
+
+func TestLoadWorkspaceProfilesCommandBlocks(t *testing.T) {
+	dir := t.TempDir()
+	config := `
+workspace "default" {
+  check {
+    output = "brief"
+    max_parallel = 5
+  }
+}
+
+workspace "dev" {
+  base = workspace.default
+  check {
+    output = "csv"
+  }
+  query {
+    timing = true
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "workspaces.spc"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, warnings, err := LoadWorkspaceProfiles(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	profile, ok := profiles["dev"]
+	if !ok {
+		t.Fatal("workspace profile 'dev' was not loaded")
+	}
+	expected := map[string]map[string]any{
+		"check": {"output": "csv", "max-parallel": 5},
+		"query": {"timing": "on"},
+	}
+	if !reflect.DeepEqual(profile.CommandDefaults, expected) {
+		t.Errorf("expected command defaults %v, got %v", expected, profile.CommandDefaults)
+	}
+}
+
+func TestLoadWorkspaceProfilesUnknownCommandAttributes(t *testing.T) {
+	// the command blocks are validated against the flags of the commands, and their subcommands
+	rootCmd := &cobra.Command{Use: "steampipe"}
+	rootCmd.PersistentFlags().String(constants.ArgInstallDir, "", "")
+	checkCmd := &cobra.Command{Use: constants.CmdNameCheck}
+	checkCmd.Flags().String(constants.ArgOutput, "", "")
+	pluginCmd := &cobra.Command{Use: constants.CmdNamePlugin}
+	pluginInstallCmd := &cobra.Command{Use: "install"}
+	pluginInstallCmd.Flags().Bool(constants.ArgProgress, true, "")
+	pluginCmd.AddCommand(pluginInstallCmd)
+	rootCmd.AddCommand(checkCmd, pluginCmd)
+	viper.Set(constants.ConfigKeyActiveCommand, checkCmd)
+	defer viper.Set(constants.ConfigKeyActiveCommand, nil)
+
+	dir := t.TempDir()
+	config := `
+workspace "dev" {
+  check {
+    output      = "brief"
+    outptu      = "csv"
+    install_dir = "/tmp/steampipe"
+  }
+  plugin {
+    progress = false
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "workspaces.spc"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, warnings, err := LoadWorkspaceProfiles(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "outptu") {
+		t.Errorf("expected a single warning for the unknown 'outptu' attribute, got %v", warnings)
+	}
+	// the unknown attribute is ignored - the rest of the block is applied
+	expected := map[string]map[string]any{
+		"check":  {"output": "brief", "install-dir": "/tmp/steampipe"},
+		"plugin": {"progress": false},
+	}
+	if !reflect.DeepEqual(profiles["dev"].CommandDefaults, expected) {
+		t.Errorf("expected command defaults %v, got %v", expected, profiles["dev"].CommandDefaults)
+	}
+}
@@ -0,0 +1,116 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+)
+
+// PluginManifest is the list of plugins required by a workspace, defined in the plugins.spc file of the workspace:
+//
+//	plugin "aws" {
+//	  version = "^0.118"
+//	}
+//
+// it is used by 'steampipe plugin sync' to install, update and remove plugins to match the manifest
+type PluginManifest struct {
+	Plugins []*PluginManifestEntry `hcl:"plugin,block"`
+}
+
+// PluginManifestEntry is a plugin required by the manifest
+type PluginManifestEntry struct {
+	// the plugin name, e.g. aws, theapsgroup/gitlab
+	Name string `hcl:"name,label"`
+	// the version constraint the installed plugin must satisfy - if not set, any version is accepted
+	Version string `hcl:"version,optional"`
+}
+
+// LoadPluginManifest loads and validates the plugin manifest file at the given path
+func LoadPluginManifest(manifestPath string) (*PluginManifest, error) {
+	if _, err := os.Stat(manifestPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigLoadFailed, sperr.New("plugin manifest %s does not exist", manifestPath))
+		}
+		return nil, err
+	}
+	file, diags := hclparse.NewParser().ParseHCLFile(manifestPath)
+	if diags.HasErrors() {
+		return nil, error_helpers.HclDiagsToError("Failed to load plugin manifest", diags)
+	}
+	manifest := &PluginManifest{}
+	if diags := gohcl.DecodeBody(file.Body, nil, manifest); diags.HasErrors() {
+		return nil, error_helpers.HclDiagsToError("Failed to load plugin manifest", diags)
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, error_helpers.NewConfigError(error_helpers.ErrorCodeConfigInvalid, sperr.WrapWithMessage(err, "invalid plugin manifest %s", manifestPath))
+	}
+	return manifest, nil
+}
+
+func (m *PluginManifest) validate() error {
+	seen := make(map[string]string)
+	for _, p := range m.Plugins {
+		imageRef := p.ImageRef()
+		if previous, ok := seen[imageRef]; ok {
+			return fmt.Errorf("plugin '%s' is defined more than once (as '%s' and '%s')", imageRef, previous, p.Name)
+		}
+		seen[imageRef] = p.Name
+		if p.Version == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(p.Version); err != nil {
+			return fmt.Errorf("plugin '%s' has an invalid version '%s'", p.Name, p.Version)
+		}
+	}
+	return nil
+}
+
+// ImageRef returns the display image ref of the plugin (the key of the plugin in the plugin versions file)
+func (p *PluginManifestEntry) ImageRef() string {
+	return ociinstaller.NewSteampipeImageRef(p.Name).DisplayImageRef()
+}
+
+// VersionConstraints returns a map of plugin image ref to the version constraint of the plugin
+// (plugins without a version constraint are not included)
+func (m *PluginManifest) VersionConstraints() map[string]string {
+	res := make(map[string]string)
+	for _, p := range m.Plugins {
+		if p.Version != "" {
+			res[p.ImageRef()] = p.Version
+		}
+	}
+	return res
+}
+
+// SyncPlan returns the plugins which must be installed or updated to match the manifest
+// (plugins which are not installed, or whose installed version does not satisfy the manifest version),
+// and the image refs of the installed plugins which are not in the manifest
+// locally built plugins are never removed
+func (m *PluginManifest) SyncPlan(installed map[string]*versionfile.InstalledVersion) (install []string, remove []string) {
+	required := make(map[string]struct{})
+	for _, p := range m.Plugins {
+		imageRef := p.ImageRef()
+		required[imageRef] = struct{}{}
+		installedVersion, ok := installed[imageRef]
+		if !ok || CheckInstalledPluginVersion(imageRef, p.Version, installedVersion) != nil {
+			install = append(install, p.Name)
+		}
+	}
+	for imageRef, installedVersion := range installed {
+		if _, ok := required[imageRef]; ok || installedVersion.Version == "local" {
+			continue
+		}
+		remove = append(remove, imageRef)
+	}
+	sort.Strings(install)
+	sort.Strings(remove)
+	return install, remove
+}
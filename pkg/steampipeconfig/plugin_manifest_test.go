@@ -0,0 +1,72 @@
+package steampipeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+)
+
+func TestLoadPluginManifest(t *testing.T) {
+	testCases := map[string]struct {
+		content   string
+		expectErr bool
+	}{
+		"valid": {
+			content: `
+plugin "aws" {
+  version = "^0.118"
+}
+plugin "theapsgroup/gitlab" {}
+`,
+		},
+		"invalid version": {
+			content:   `plugin "aws" { version = "not a version" }`,
+			expectErr: true,
+		},
+		"duplicate": {
+			content:   `plugin "aws" {}` + "\n" + `plugin "turbot/aws" {}`,
+			expectErr: true,
+		},
+		"unknown attribute": {
+			content:   `plugin "aws" { min_version = "0.118.0" }`,
+			expectErr: true,
+		},
+	}
+	for name, test := range testCases {
+		manifestPath := filepath.Join(t.TempDir(), "plugins.spc")
+		if err := os.WriteFile(manifestPath, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadPluginManifest(manifestPath)
+		if test.expectErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", name, test.expectErr, err)
+		}
+	}
+}
+
+func TestPluginManifestSyncPlan(t *testing.T) {
+	manifest := &PluginManifest{Plugins: []*PluginManifestEntry{
+		{Name: "aws", Version: "^0.118"},
+		{Name: "azure"},
+		{Name: "gcp", Version: ">=0.40"},
+		{Name: "github"},
+	}}
+	installed := map[string]*versionfile.InstalledVersion{
+		"hub.steampipe.io/plugins/turbot/aws@latest":    {Version: "0.117.0"},
+		"hub.steampipe.io/plugins/turbot/azure@latest":  {Version: "0.50.0"},
+		"hub.steampipe.io/plugins/turbot/gcp@latest":    {Version: "0.41.0"},
+		"hub.steampipe.io/plugins/turbot/net@latest":    {Version: "0.11.0"},
+		"hub.steampipe.io/plugins/turbot/custom@latest": {Version: "local"},
+	}
+
+	install, remove := manifest.SyncPlan(installed)
+	if expected := []string{"aws", "github"}; !reflect.DeepEqual(install, expected) {
+		t.Errorf("expected install %v, got %v", expected, install)
+	}
+	if expected := []string{"hub.steampipe.io/plugins/turbot/net@latest"}; !reflect.DeepEqual(remove, expected) {
+		t.Errorf("expected remove %v, got %v", expected, remove)
+	}
+}
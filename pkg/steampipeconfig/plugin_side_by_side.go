@@ -0,0 +1,85 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/turbot/steampipe/pkg/ociinstaller"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// resolvePluginVersionImageRef returns the image ref of the installed plugin which a connection should use
+//
+// if the connection sets plugin_version and the plugin installed for the requested stream does not satisfy it,
+// the latest side by side install (see 'plugin install --side-by-side') of the plugin which satisfies it is used
+// - this allows connections to use different versions of a plugin
+func resolvePluginVersionImageRef(connection *modconfig.Connection, imageRef string, pluginVersions map[string]*versionfile.InstalledVersion) string {
+	if connection.PluginVersion == "" {
+		return imageRef
+	}
+	res := findPluginVersionImageRef(imageRef, connection.PluginVersion, pluginVersions)
+	if res == "" {
+		// this will be reported when the connection is validated
+		return imageRef
+	}
+	if res != imageRef {
+		log.Printf("[INFO] connection '%s' uses plugin %s (version %s), which satisfies plugin_version '%s'", connection.Name, res, pluginVersions[res].Version, connection.PluginVersion)
+	}
+	return res
+}
+
+// findPluginVersionImageRef returns the image ref of the installed plugin which satisfies the version constraint
+// - the install of the stream given by imageRef if it satisfies the constraint, otherwise the latest side by side
+// install of the plugin which does (returns an empty string if there is none)
+func findPluginVersionImageRef(imageRef, versionConstraint string, pluginVersions map[string]*versionfile.InstalledVersion) string {
+	if pluginVersions[imageRef] != nil && CheckInstalledPluginVersion(imageRef, versionConstraint, pluginVersions[imageRef]) == nil {
+		return imageRef
+	}
+	constraint, err := semver.NewConstraint(versionConstraint)
+	if err != nil {
+		return ""
+	}
+
+	org, name, _ := ociinstaller.NewSteampipeImageRef(imageRef).GetOrgNameAndConstraint()
+	var res string
+	var resVersion *semver.Version
+	for installedRef, installed := range pluginVersions {
+		installedOrg, installedName, installedConstraint := ociinstaller.NewSteampipeImageRef(installedRef).GetOrgNameAndConstraint()
+		if installedOrg != org || installedName != name || !ociinstaller.IsDigestConstraint(installedConstraint) {
+			continue
+		}
+		version, err := semver.NewVersion(installed.Version)
+		if err != nil || !constraint.Check(version) {
+			continue
+		}
+		if resVersion == nil || version.GreaterThan(resVersion) {
+			res, resVersion = installedRef, version
+		}
+	}
+	return res
+}
+
+// resolvePluginVersionInstance returns the plugin instance which a connection referencing a plugin block should use
+//
+// if the connection sets plugin_version and only a side by side install of the plugin satisfies it,
+// this is a copy of the plugin block which uses the side by side install (named <instance>@<digest>)
+func (c *SteampipeConfig) resolvePluginVersionInstance(connection *modconfig.Connection, p *modconfig.Plugin) (*modconfig.Plugin, error) {
+	imageRef := resolvePluginVersionImageRef(connection, p.Plugin, c.PluginVersions)
+	if imageRef == p.Plugin {
+		return p, nil
+	}
+	_, _, digest := ociinstaller.NewSteampipeImageRef(imageRef).GetOrgNameAndConstraint()
+	instance := fmt.Sprintf("%s@%s", p.Instance, digest)
+	if existing, ok := c.PluginsInstances[instance]; ok {
+		return existing, nil
+	}
+	versionInstance := *p
+	versionInstance.Instance = instance
+	versionInstance.Plugin = imageRef
+	if err := c.addPlugin(&versionInstance); err != nil {
+		return nil, err
+	}
+	return &versionInstance, nil
+}
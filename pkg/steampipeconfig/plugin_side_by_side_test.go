@@ -0,0 +1,67 @@
+package steampipeconfig
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestResolvePluginVersionImageRef(t *testing.T) {
+	const latestRef = "hub.steampipe.io/plugins/turbot/aws@latest"
+	pluginVersions := map[string]*versionfile.InstalledVersion{
+		latestRef: {Version: "0.93.0"},
+		"hub.steampipe.io/plugins/turbot/aws@sha256-aaa": {Version: "0.92.0"},
+		"hub.steampipe.io/plugins/turbot/aws@sha256-bbb": {Version: "0.92.5"},
+		"hub.steampipe.io/plugins/turbot/gcp@sha256-ccc": {Version: "0.91.0"},
+	}
+	tests := map[string]struct {
+		pluginVersion string
+		want          string
+	}{
+		"no plugin_version":                {want: latestRef},
+		"stream install satisfies":         {pluginVersion: "0.93.0", want: latestRef},
+		"side by side install satisfies":   {pluginVersion: "0.92.0", want: "hub.steampipe.io/plugins/turbot/aws@sha256-aaa"},
+		"latest side by side install used": {pluginVersion: "^0.92", want: "hub.steampipe.io/plugins/turbot/aws@sha256-bbb"},
+		"other plugin not used":            {pluginVersion: "0.91.0", want: latestRef},
+		"no install satisfies":             {pluginVersion: "0.90.0", want: latestRef},
+		"invalid plugin_version":           {pluginVersion: "not a version", want: latestRef},
+	}
+	for name, test := range tests {
+		connection := &modconfig.Connection{Name: "aws", PluginVersion: test.pluginVersion}
+		if got := resolvePluginVersionImageRef(connection, latestRef, pluginVersions); got != test.want {
+			t.Errorf("%s: expected %s, got %s", name, test.want, got)
+		}
+	}
+}
+
+func TestResolvePluginVersionInstance(t *testing.T) {
+	const latestRef = "hub.steampipe.io/plugins/turbot/aws@latest"
+	const sideBySideRef = "hub.steampipe.io/plugins/turbot/aws@sha256-aaa"
+	config := NewSteampipeConfig("")
+	config.PluginVersions = map[string]*versionfile.InstalledVersion{
+		latestRef:     {Version: "0.93.0"},
+		sideBySideRef: {Version: "0.92.0"},
+	}
+	block := &modconfig.Plugin{Instance: "aws_limited", Alias: "aws", Plugin: latestRef}
+	if err := config.addPlugin(block); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	p, err := config.resolvePluginVersionInstance(&modconfig.Connection{Name: "aws1", PluginVersion: "0.93.0"}, block)
+	if err != nil || p != block {
+		t.Errorf("expected the plugin block to be used when the stream install satisfies plugin_version, got %v (%v)", p, err)
+	}
+
+	p, err = config.resolvePluginVersionInstance(&modconfig.Connection{Name: "aws2", PluginVersion: "0.92.0"}, block)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if p.Plugin != sideBySideRef || p.Instance != "aws_limited@sha256-aaa" || p.Version != "0.92.0" {
+		t.Errorf("expected an instance of the plugin block using the side by side install, got %+v", p)
+	}
+	// connections pinning the same version share the instance
+	if p2, _ := config.resolvePluginVersionInstance(&modconfig.Connection{Name: "aws3", PluginVersion: "0.92.0"}, block); p2 != p {
+		t.Error("expected connections pinning the same version to share the plugin instance")
+	}
+}
@@ -0,0 +1,70 @@
+package steampipeconfig
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// PluginVersionPins returns a map of plugin image ref (of the stream requested by the connections, e.g. aws@latest)
+// to the distinct version constraints set by the plugin_version property of the connections which use the plugin
+// connections may pin different versions of the same plugin - each pinned version is installed side by side
+// with the install of the stream (see resolvePluginVersionImageRef)
+func (c *SteampipeConfig) PluginVersionPins() map[string][]string {
+	pins := make(map[string][]string)
+	for _, connection := range c.Connections {
+		if connection.PluginVersion == "" || connection.PluginAlias == "" {
+			continue
+		}
+		imageRef := modconfig.ResolvePluginImageRef(connection.PluginAlias)
+		// locally built plugins cannot be installed at a version
+		if strings.HasPrefix(imageRef, "local/") {
+			continue
+		}
+		if !slices.Contains(pins[imageRef], connection.PluginVersion) {
+			pins[imageRef] = append(pins[imageRef], connection.PluginVersion)
+		}
+	}
+	for _, constraints := range pins {
+		sort.Strings(constraints)
+	}
+	return pins
+}
+
+// PluginVersionInstalled returns whether an installed version of the plugin - either the install of the stream
+// or a side by side install - satisfies the version constraint
+func (c *SteampipeConfig) PluginVersionInstalled(imageRef, constraint string) bool {
+	return findPluginVersionImageRef(imageRef, constraint, c.PluginVersions) != ""
+}
+
+// CheckPluginVersionPin returns an error if the installed version of the plugin of a connection
+// does not satisfy the version constraint of the connection
+func CheckPluginVersionPin(connection *modconfig.Connection, installed *versionfile.InstalledVersion) error {
+	return CheckInstalledPluginVersion(connection.Plugin, connection.PluginVersionConstraint(), installed)
+}
+
+// CheckInstalledPluginVersion returns an error if the installed version of a plugin does not satisfy the given constraint
+// (local plugins and plugins which are not installed are not checked)
+func CheckInstalledPluginVersion(plugin, constraintString string, installed *versionfile.InstalledVersion) error {
+	if constraintString == "" || installed == nil || installed.Version == "local" {
+		return nil
+	}
+	constraint, err := semver.NewConstraint(constraintString)
+	if err != nil {
+		return error_helpers.NewConfigError(error_helpers.ErrorCodeConfigInvalid, sperr.WrapWithMessage(err, "invalid plugin version '%s'", constraintString))
+	}
+	installedVersion, err := semver.NewVersion(installed.Version)
+	if err != nil {
+		return error_helpers.NewPluginError(error_helpers.ErrorCodePluginVersionMismatch, sperr.New("the installed version of plugin %s (%s) cannot be checked against the plugin version '%s'", plugin, installed.Version, constraintString))
+	}
+	if !constraint.Check(installedVersion) {
+		return error_helpers.NewPluginError(error_helpers.ErrorCodePluginVersionMismatch, sperr.New("the installed version of plugin %s (%s) does not satisfy the plugin version '%s' - run 'steampipe plugin install' to install a matching version", plugin, installed.Version, constraintString))
+	}
+	return nil
+}
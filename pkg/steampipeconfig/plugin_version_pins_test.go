@@ -0,0 +1,73 @@
+package steampipeconfig
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestCheckPluginVersionPin(t *testing.T) {
+	tests := map[string]struct {
+		connection *modconfig.Connection
+		installed  string
+		wantErr    bool
+	}{
+		"not pinned":             {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@latest"}, installed: "0.93.0"},
+		"plugin_version matches": {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@latest", PluginVersion: "0.92.0"}, installed: "0.92.0"},
+		"plugin_version differs": {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@latest", PluginVersion: "0.92.0"}, installed: "0.93.0", wantErr: true},
+		"constraint matches":     {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@latest", PluginVersion: "^0.92"}, installed: "0.92.4"},
+		"plugin ref matches":     {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@0.92.0"}, installed: "0.92.0"},
+		"plugin ref differs":     {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@0.92.0"}, installed: "0.91.0", wantErr: true},
+		"local plugin":           {connection: &modconfig.Connection{Plugin: "hub.steampipe.io/plugins/turbot/aws@latest", PluginVersion: "0.92.0"}, installed: "local"},
+	}
+	for name, test := range tests {
+		err := CheckPluginVersionPin(test.connection, &versionfile.InstalledVersion{Version: test.installed})
+		if test.wantErr && err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if !test.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err.Error())
+		}
+	}
+}
+
+func TestPluginVersionPins(t *testing.T) {
+	config := NewSteampipeConfig("")
+	config.Connections["aws1"] = &modconfig.Connection{Name: "aws1", PluginAlias: "aws", PluginVersion: "0.93.0"}
+	config.Connections["aws2"] = &modconfig.Connection{Name: "aws2", PluginAlias: "aws", PluginVersion: "0.92.0"}
+	config.Connections["aws3"] = &modconfig.Connection{Name: "aws3", PluginAlias: "aws", PluginVersion: "0.92.0"}
+	// a connection using a side by side install is keyed by the stream it requests
+	config.Connections["aws4"] = &modconfig.Connection{Name: "aws4", PluginAlias: "aws", Plugin: "hub.steampipe.io/plugins/turbot/aws@sha256-aaa", PluginVersion: "0.91.0"}
+	config.Connections["gcp"] = &modconfig.Connection{Name: "gcp", PluginAlias: "gcp"}
+	config.Connections["local"] = &modconfig.Connection{Name: "local", PluginAlias: "local/aws", PluginVersion: "0.92.0"}
+
+	pins := config.PluginVersionPins()
+	want := []string{"0.91.0", "0.92.0", "0.93.0"}
+	if len(pins) != 1 || !slices.Equal(pins["hub.steampipe.io/plugins/turbot/aws@latest"], want) {
+		t.Errorf("expected aws@latest to be pinned to %v, got %v", want, pins)
+	}
+}
+
+func TestPluginVersionInstalled(t *testing.T) {
+	const latestRef = "hub.steampipe.io/plugins/turbot/aws@latest"
+	config := NewSteampipeConfig("")
+	config.PluginVersions = map[string]*versionfile.InstalledVersion{
+		latestRef: {Version: "0.93.0"},
+		"hub.steampipe.io/plugins/turbot/aws@sha256-aaa": {Version: "0.92.0"},
+	}
+	tests := map[string]struct {
+		constraint string
+		want       bool
+	}{
+		"stream install satisfies":       {constraint: "0.93.0", want: true},
+		"side by side install satisfies": {constraint: "0.92.0", want: true},
+		"no install satisfies":           {constraint: "0.91.0", want: false},
+	}
+	for name, test := range tests {
+		if got := config.PluginVersionInstalled(latestRef, test.constraint); got != test.want {
+			t.Errorf("%s: expected %v, got %v", name, test.want, got)
+		}
+	}
+}
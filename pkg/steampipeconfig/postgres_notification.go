@@ -11,6 +11,10 @@ type PostgresNotificationType int
 const (
 	PgNotificationSchemaUpdate PostgresNotificationType = iota + 1
 	PgNotificationConnectionError
+	PgNotificationPluginInstalled
+	PgNotificationRefreshProgress
+	PgNotificationScheduleRunNow
+	PgNotificationCredentialsExpired
 )
 
 type PostgresNotification struct {
@@ -24,6 +28,40 @@ type ErrorsAndWarningsNotification struct {
 	Warnings []string
 }
 
+// PluginInstalledNotification is sent when plugins have been installed or updated by another process
+// - the plugin manager responds by refreshing connections, so connections which were missing their plugin are loaded
+type PluginInstalledNotification struct {
+	PostgresNotification
+	Plugins []string
+}
+
+// RefreshProgressNotification is sent as connections are updated by a connection refresh
+// - attached clients use this to display the refresh progress
+type RefreshProgressNotification struct {
+	PostgresNotification
+	// the number of connection updates which are complete, and the total number of updates
+	Complete int
+	Total    int
+	// the most recently updated connection, and its state
+	Connection string
+	State      string
+}
+
+// ScheduleRunNowNotification is sent to request that a scheduled job is run immediately
+// - the plugin manager responds by running the job, whether or not it is paused
+type ScheduleRunNowNotification struct {
+	PostgresNotification
+	Name string
+}
+
+// CredentialsExpiredNotification is sent by a client when a query fails because the credentials of a connection
+// have expired (e.g. an STS session used by a static schema plugin, which is only detected at query time)
+// - the plugin manager responds by refreshing the credentials of the connection named in the error
+type CredentialsExpiredNotification struct {
+	PostgresNotification
+	Error string
+}
+
 func NewSchemaUpdateNotification() *PostgresNotification {
 	return &PostgresNotification{
 		StructVersion: PostgresNotificationStructVersion,
@@ -45,3 +83,46 @@ func NewErrorsAndWarningsNotification(errorAndWarnings error_helpers.ErrorAndWar
 	res.Warnings = append(res.Warnings, errorAndWarnings.Warnings...)
 	return res
 }
+
+func NewPluginInstalledNotification(plugins []string) *PluginInstalledNotification {
+	return &PluginInstalledNotification{
+		PostgresNotification: PostgresNotification{
+			StructVersion: PostgresNotificationStructVersion,
+			Type:          PgNotificationPluginInstalled,
+		},
+		Plugins: plugins,
+	}
+}
+
+func NewRefreshProgressNotification(complete, total int, connection, state string) *RefreshProgressNotification {
+	return &RefreshProgressNotification{
+		PostgresNotification: PostgresNotification{
+			StructVersion: PostgresNotificationStructVersion,
+			Type:          PgNotificationRefreshProgress,
+		},
+		Complete:   complete,
+		Total:      total,
+		Connection: connection,
+		State:      state,
+	}
+}
+
+func NewScheduleRunNowNotification(name string) *ScheduleRunNowNotification {
+	return &ScheduleRunNowNotification{
+		PostgresNotification: PostgresNotification{
+			StructVersion: PostgresNotificationStructVersion,
+			Type:          PgNotificationScheduleRunNow,
+		},
+		Name: name,
+	}
+}
+
+func NewCredentialsExpiredNotification(err string) *CredentialsExpiredNotification {
+	return &CredentialsExpiredNotification{
+		PostgresNotification: PostgresNotification{
+			StructVersion: PostgresNotificationStructVersion,
+			Type:          PgNotificationCredentialsExpired,
+		},
+		Error: err,
+	}
+}
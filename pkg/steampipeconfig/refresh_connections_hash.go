@@ -0,0 +1,132 @@
+package steampipeconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	filehelpers "github.com/turbot/go-kit/files"
+	sdkplugin "github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/statestore"
+	"github.com/turbot/steampipe/pkg/version"
+	"golang.org/x/exp/maps"
+)
+
+// RefreshConnectionsHash returns a hash of the inputs of a connection refresh:
+// the connection config files, the installed plugins, the search path and the CLI version
+//
+// if this matches the hash saved by the last successful refresh, a refresh would not change the connection schemas
+func RefreshConnectionsHash(ctx context.Context) (string, error) {
+	h := sha256.New()
+
+	configPaths, err := filehelpers.ListFilesWithContext(ctx, filepaths.EnsureConfigDir(), &filehelpers.ListOptions{
+		Flags:   filehelpers.FilesFlat,
+		Include: filehelpers.InclusionsFromExtensions(constants.ConnectionConfigExtensions),
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(configPaths)
+	for _, configPath := range configPaths {
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "config %s %x\n", configPath, sha256.Sum256(content))
+	}
+
+	pluginVersions, err := versionfile.LoadPluginVersionFile(ctx)
+	if err != nil {
+		return "", err
+	}
+	if ew := pluginVersions.AddLocalPlugins(ctx); ew.GetError() != nil {
+		return "", ew.GetError()
+	}
+	hashPluginVersions(h, pluginVersions.Plugins)
+
+	fmt.Fprintf(h, "search_path %s\n", strings.Join(viper.GetStringSlice(constants.ConfigKeyServerSearchPath), ","))
+	fmt.Fprintf(h, "search_path_prefix %s\n", strings.Join(viper.GetStringSlice(constants.ConfigKeyServerSearchPathPrefix), ","))
+	fmt.Fprintf(h, "version %s\n", version.SteampipeVersion.String())
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashPluginVersions(h hash.Hash, pluginVersions map[string]*versionfile.InstalledVersion) {
+	imageRefs := maps.Keys(pluginVersions)
+	sort.Strings(imageRefs)
+	for _, imageRef := range imageRefs {
+		installed := pluginVersions[imageRef]
+		fmt.Fprintf(h, "plugin %s %s %s %s\n", imageRef, installed.Version, installed.ImageDigest, installed.BinaryDigest)
+		// locally built plugins have no digest - use the modification time and size of the binary instead
+		if installed.Version != "local" {
+			continue
+		}
+		if pluginPath, err := filepaths.GetPluginPath(imageRef, imageRef); err == nil {
+			if info, err := os.Stat(pluginPath); err == nil {
+				fmt.Fprintf(h, "binary %d %d\n", info.ModTime().UnixNano(), info.Size())
+			}
+		}
+	}
+}
+
+// SaveRefreshConnectionsHash saves the hash of the inputs of a successful connection refresh
+func SaveRefreshConnectionsHash(ctx context.Context, store statestore.Store, refreshHash string) error {
+	return store.Save(ctx, statestore.KeyRefreshConnectionsHash, []byte(refreshHash))
+}
+
+// DeleteRefreshConnectionsHash deletes the saved refresh hash, so the next service start will refresh connections
+func DeleteRefreshConnectionsHash(ctx context.Context, store statestore.Store) {
+	if err := store.Delete(ctx, statestore.KeyRefreshConnectionsHash); err != nil {
+		log.Printf("[WARN] failed to delete refresh connections hash: %s", err.Error())
+	}
+}
+
+// RefreshConnectionsRequired returns whether connections must be refreshed when the service starts
+//
+// the refresh is only skipped if the last refresh succeeded for every connection in the config,
+// and nothing which affects the connection schemas has changed since
+// (connections with dynamic schemas and connection discoveries may change without any config change, so are always refreshed)
+func RefreshConnectionsRequired(ctx context.Context, store statestore.Store, connectionState ConnectionStateMap) bool {
+	globalConfig := GetGlobalConfig()
+	if len(globalConfig.ConnectionDiscoveries) > 0 || len(connectionState) != len(globalConfig.Connections) {
+		return true
+	}
+	for name := range globalConfig.Connections {
+		state, ok := connectionState[name]
+		if !ok || state.SchemaMode == sdkplugin.SchemaModeDynamic {
+			return true
+		}
+		if state.State != constants.ConnectionStateReady && state.State != constants.ConnectionStateDisabled {
+			return true
+		}
+	}
+
+	savedHash, err := store.Load(ctx, statestore.KeyRefreshConnectionsHash)
+	if err != nil {
+		log.Printf("[WARN] failed to load refresh connections hash: %s", err.Error())
+		return true
+	}
+	if savedHash == nil {
+		return true
+	}
+	refreshHash, err := RefreshConnectionsHash(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to build refresh connections hash: %s", err.Error())
+		return true
+	}
+	if refreshHash != string(savedHash) {
+		return true
+	}
+	log.Printf("[INFO] nothing has changed since the last successful connection refresh - refresh is not required")
+	return false
+}
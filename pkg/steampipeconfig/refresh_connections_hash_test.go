@@ -0,0 +1,42 @@
+package steampipeconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+)
+
+func pluginVersionsHash(pluginVersions map[string]*versionfile.InstalledVersion) string {
+	h := sha256.New()
+	hashPluginVersions(h, pluginVersions)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestHashPluginVersions(t *testing.T) {
+	installed := func(version, digest string) map[string]*versionfile.InstalledVersion {
+		return map[string]*versionfile.InstalledVersion{
+			"hub.steampipe.io/plugins/turbot/aws@latest": {Version: version, ImageDigest: digest},
+			"hub.steampipe.io/plugins/turbot/gcp@latest": {Version: "0.40.0", ImageDigest: "sha256:gcp"},
+		}
+	}
+	base := pluginVersionsHash(installed("0.93.0", "sha256:aws"))
+
+	for i := 0; i < 10; i++ {
+		if got := pluginVersionsHash(installed("0.93.0", "sha256:aws")); got != base {
+			t.Fatalf("expected the hash of the same plugins to be stable")
+		}
+	}
+	if pluginVersionsHash(installed("0.93.0", "sha256:other")) == base {
+		t.Errorf("expected a change of image digest to change the hash")
+	}
+	if pluginVersionsHash(installed("0.94.0", "sha256:aws")) == base {
+		t.Errorf("expected a change of version to change the hash")
+	}
+	uninstalled := installed("0.93.0", "sha256:aws")
+	delete(uninstalled, "hub.steampipe.io/plugins/turbot/gcp@latest")
+	if pluginVersionsHash(uninstalled) == base {
+		t.Errorf("expected uninstalling a plugin to change the hash")
+	}
+}
@@ -0,0 +1,101 @@
+package steampipeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/statestore"
+	"golang.org/x/exp/maps"
+)
+
+// RefreshConnectionsReport is a serialisable summary of a RefreshConnections operation
+// it is saved by the plugin manager when a refresh completes, so the CLI is able to report the result
+type RefreshConnectionsReport struct {
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	DurationMs int64     `json:"duration_ms"`
+	// the connections whose schemas were successfully updated
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+	// map of connection name to error
+	Failed   map[string]string `json:"failed"`
+	Warnings []string          `json:"warnings"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// NewRefreshConnectionsReport builds a report from the result of a refresh
+// connectionUpdates will be nil if the refresh failed before the updates were determined
+func NewRefreshConnectionsReport(res *RefreshConnectionResult, connectionUpdates *ConnectionUpdates, startTime time.Time) *RefreshConnectionsReport {
+	endTime := time.Now()
+	r := &RefreshConnectionsReport{
+		StartTime:  startTime,
+		EndTime:    endTime,
+		DurationMs: endTime.Sub(startTime).Milliseconds(),
+		Updated:    []string{},
+		Deleted:    []string{},
+		Failed:     map[string]string{},
+		Warnings:   []string{},
+	}
+	if res != nil {
+		r.Warnings = append(r.Warnings, res.Warnings...)
+		if res.Error != nil {
+			r.Error = res.Error.Error()
+		}
+		for c, failure := range res.FailedConnections {
+			r.Failed[c] = failure
+		}
+	}
+	if connectionUpdates == nil {
+		return r
+	}
+
+	for c, state := range connectionUpdates.FinalConnectionState {
+		if _, alreadyFailed := r.Failed[c]; !alreadyFailed && state.State == constants.ConnectionStateError {
+			r.Failed[c] = state.Error()
+		}
+	}
+	for c := range connectionUpdates.Update {
+		if _, failed := r.Failed[c]; !failed {
+			r.Updated = append(r.Updated, c)
+		}
+	}
+	r.Deleted = maps.Keys(connectionUpdates.Delete)
+	slices.Sort(r.Updated)
+	slices.Sort(r.Deleted)
+	return r
+}
+
+// Includes returns whether all the given connections were either updated or failed by this refresh
+func (r *RefreshConnectionsReport) Includes(connectionNames []string) bool {
+	for _, c := range connectionNames {
+		if _, failed := r.Failed[c]; !failed && !slices.Contains(r.Updated, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RefreshConnectionsReport) Save(ctx context.Context, store statestore.Store) error {
+	reportJSON, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Save(ctx, statestore.KeyRefreshConnectionsReport, reportJSON)
+}
+
+// LoadRefreshConnectionsReport loads the report of the last connection refresh
+// if no refresh has been reported, nil is returned
+func LoadRefreshConnectionsReport(ctx context.Context, store statestore.Store) (*RefreshConnectionsReport, error) {
+	reportJSON, err := store.Load(ctx, statestore.KeyRefreshConnectionsReport)
+	if err != nil || reportJSON == nil {
+		return nil, err
+	}
+	var r RefreshConnectionsReport
+	if err := json.Unmarshal(reportJSON, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,53 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestNewRefreshConnectionsReport(t *testing.T) {
+	failedState := &ConnectionState{}
+	failedState.SetError("plugin crashed")
+
+	res := &RefreshConnectionResult{}
+	res.AddWarning("1 plugin required by 1 connection is missing")
+	res.AddFailedConnection("aws_dev", "invalid credentials")
+
+	connectionUpdates := &ConnectionUpdates{
+		Update: ConnectionStateMap{
+			"aws_dev":  {},
+			"aws_prod": {},
+			"gcp":      {},
+		},
+		Delete: map[string]struct{}{"azure": {}},
+		FinalConnectionState: ConnectionStateMap{
+			"aws_dev":  {State: constants.ConnectionStateReady},
+			"aws_prod": {State: constants.ConnectionStateReady},
+			"gcp":      failedState,
+		},
+	}
+
+	report := NewRefreshConnectionsReport(res, connectionUpdates, time.Now())
+
+	if expected := []string{"aws_prod"}; !reflect.DeepEqual(report.Updated, expected) {
+		t.Errorf("Updated: expected %v, got %v", expected, report.Updated)
+	}
+	if expected := []string{"azure"}; !reflect.DeepEqual(report.Deleted, expected) {
+		t.Errorf("Deleted: expected %v, got %v", expected, report.Deleted)
+	}
+	if expected := map[string]string{"aws_dev": "invalid credentials", "gcp": "plugin crashed"}; !reflect.DeepEqual(report.Failed, expected) {
+		t.Errorf("Failed: expected %v, got %v", expected, report.Failed)
+	}
+	if len(report.Warnings) != 1 {
+		t.Errorf("expected 1 warning, got %v", report.Warnings)
+	}
+	if !report.Includes([]string{"aws_dev", "gcp"}) {
+		t.Errorf("expected report to include failed connections")
+	}
+	if report.Includes([]string{"aws_prod", "azure"}) {
+		t.Errorf("expected report not to include deleted connections")
+	}
+}
@@ -0,0 +1,151 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// resolveSchemaNames renames each connection to the name of its schema, as determined by the
+// 'schema_name_case' and 'schema_name_invalid_chars' database options
+// the child connections of aggregators and the connections used by discoveries are updated to match
+//
+// an error is returned if a connection name is invalid, or if the names of multiple connections map to the same schema
+func (c *SteampipeConfig) resolveSchemaNames() error {
+	nameCase, invalidChars := c.schemaNameSettings()
+	if !helpers.StringSliceContains(constants.SchemaNameCaseModes, nameCase) {
+		return sperr.New("invalid value of 'schema_name_case' (%s), must be one of: %s", nameCase, strings.Join(constants.SchemaNameCaseModes, ", "))
+	}
+	if !helpers.StringSliceContains(constants.SchemaNameInvalidCharsModes, invalidChars) {
+		return sperr.New("invalid value of 'schema_name_invalid_chars' (%s), must be one of: %s", invalidChars, strings.Join(constants.SchemaNameInvalidCharsModes, ", "))
+	}
+
+	// map of connection name to schema name
+	schemaNames := make(map[string]string, len(c.Connections))
+	// map of schema name to the connections which map to it
+	schemaConnections := make(map[string][]*modconfig.Connection, len(c.Connections))
+	for connectionName, connection := range c.Connections {
+		schemaName, err := db_common.SchemaNameForConnection(connectionName, nameCase, invalidChars)
+		if err != nil {
+			return sperr.New("invalid connection name: '%s' in '%s'. %s", connectionName, connection.DeclRange.Filename, err.Error())
+		}
+		schemaNames[connectionName] = schemaName
+		schemaConnections[schemaName] = append(schemaConnections[schemaName], connection)
+	}
+	if err := schemaNameConflictsError(schemaConnections); err != nil {
+		return err
+	}
+
+	connections := make(map[string]*modconfig.Connection, len(c.Connections))
+	for connectionName, connection := range c.Connections {
+		schemaName := schemaNames[connectionName]
+		if schemaName != connectionName {
+			log.Printf("[INFO] connection '%s' uses schema '%s'", connectionName, schemaName)
+			connection.Name = schemaName
+		}
+		if connection.Type == modconfig.ConnectionTypeAggregator {
+			for i, pattern := range connection.ConnectionNames {
+				connection.ConnectionNames[i] = schemaNamePattern(pattern, schemaNames, nameCase, invalidChars)
+			}
+		}
+		connections[schemaName] = connection
+	}
+	c.Connections = connections
+
+	for _, discovery := range c.ConnectionDiscoveries {
+		if schemaName, ok := schemaNames[discovery.Connection]; ok {
+			discovery.Connection = schemaName
+		}
+	}
+	return nil
+}
+
+// schemaNameSettings returns the configured schema name settings
+// NOTE: this is also called when the FDW loads the connection config, so the settings are not read from viper
+// (environment variables take precedence over the database options, as they do for other options)
+func (c *SteampipeConfig) schemaNameSettings() (nameCase, invalidChars string) {
+	nameCase = constants.SchemaNameCasePreserve
+	invalidChars = constants.SchemaNameInvalidCharsError
+	if c.DatabaseOptions != nil {
+		nameCase = typehelpers.SafeString(c.DatabaseOptions.SchemaNameCase)
+		if nameCase == "" {
+			nameCase = constants.SchemaNameCasePreserve
+		}
+		invalidChars = typehelpers.SafeString(c.DatabaseOptions.SchemaNameInvalidChars)
+		if invalidChars == "" {
+			invalidChars = constants.SchemaNameInvalidCharsError
+		}
+	}
+	if value, ok := os.LookupEnv(constants.EnvSchemaNameCase); ok {
+		nameCase = value
+	}
+	if value, ok := os.LookupEnv(constants.EnvSchemaNameInvalidChars); ok {
+		invalidChars = value
+	}
+	return nameCase, invalidChars
+}
+
+// schemaNamePattern returns the aggregator child connection pattern which matches the schema names
+// of the connections matched by the given connection name pattern
+func schemaNamePattern(pattern string, schemaNames map[string]string, nameCase, invalidChars string) string {
+	// if the pattern is a connection name, use its schema name
+	if schemaName, ok := schemaNames[pattern]; ok {
+		return schemaName
+	}
+	if nameCase == constants.SchemaNameCaseLower {
+		pattern = strings.ToLower(pattern)
+	}
+	if invalidChars != constants.SchemaNameInvalidCharsTransliterate {
+		return pattern
+	}
+	// transliterate the parts of the pattern between the wildcards
+	var sb strings.Builder
+	var segment strings.Builder
+	writeSegment := func() {
+		if segment.Len() > 0 {
+			// do not apply the leading digit/length rules to the segment - only transliterate the characters
+			sb.WriteString(strings.TrimPrefix(db_common.TransliterateSchemaName("_"+segment.String()), "_"))
+			segment.Reset()
+		}
+	}
+	for _, r := range pattern {
+		if r == '*' || r == '?' {
+			writeSegment()
+			sb.WriteRune(r)
+			continue
+		}
+		segment.WriteRune(r)
+	}
+	writeSegment()
+	return sb.String()
+}
+
+// schemaNameConflictsError returns an error listing the connections whose names map to the same schema
+func schemaNameConflictsError(schemaConnections map[string][]*modconfig.Connection) error {
+	var conflicts []string
+	for schemaName, connections := range schemaConnections {
+		if len(connections) < 2 {
+			continue
+		}
+		sort.Slice(connections, func(i, j int) bool { return connections[i].Name < connections[j].Name })
+		conflict := fmt.Sprintf("schema '%s' is the schema of multiple connections:", schemaName)
+		for _, connection := range connections {
+			conflict += fmt.Sprintf("\n\t'%s' (%s:%d)", connection.Name, connection.DeclRange.Filename, connection.DeclRange.Start.Line)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+	return sperr.New("connection names conflict - rename the connections or change the 'schema_name_case' and 'schema_name_invalid_chars' options\n%s", strings.Join(conflicts, "\n"))
+}
@@ -0,0 +1,44 @@
+package steampipeconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
+)
+
+func TestResolveSchemaNames(t *testing.T) {
+	newConfig := func(connectionNames ...string) *SteampipeConfig {
+		config := NewSteampipeConfig("")
+		nameCase := constants.SchemaNameCaseLower
+		invalidChars := constants.SchemaNameInvalidCharsTransliterate
+		config.DatabaseOptions = &options.Database{SchemaNameCase: &nameCase, SchemaNameInvalidChars: &invalidChars}
+		for _, name := range connectionNames {
+			config.Connections[name] = &modconfig.Connection{Name: name}
+		}
+		return config
+	}
+
+	config := newConfig("AWS-Prod", "aws-dev")
+	config.Connections["all"] = &modconfig.Connection{Name: "all", Type: modconfig.ConnectionTypeAggregator, ConnectionNames: []string{"AWS-Prod", "AWS-*"}}
+	if err := config.resolveSchemaNames(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	for _, expected := range []string{"aws_prod", "aws_dev", "all"} {
+		if connection, ok := config.Connections[expected]; !ok || connection.Name != expected {
+			t.Errorf("expected connection %s", expected)
+		}
+	}
+	if patterns := strings.Join(config.Connections["all"].ConnectionNames, ","); patterns != "aws_prod,aws_*" {
+		t.Errorf("expected aggregator patterns 'aws_prod,aws_*', got '%s'", patterns)
+	}
+
+	// names which map to the same schema are reported
+	config = newConfig("aws-prod", "AWS_Prod")
+	err := config.resolveSchemaNames()
+	if err == nil || !strings.Contains(err.Error(), "'aws_prod'") {
+		t.Errorf("expected a conflict error for schema 'aws_prod', got %v", err)
+	}
+}
@@ -36,8 +36,12 @@ type SteampipeConfig struct {
 	TerminalOptions          *options.Terminal
 	GeneralOptions           *options.General
 	PluginOptions            *options.Plugin
+	NetworkOptions           *options.Network
+	ImageOptions             *options.Image
 	// map of installed plugin versions, keyed by plugin image ref
 	PluginVersions map[string]*versionfile.InstalledVersion
+	// map of connection discovery configs, keyed by name
+	ConnectionDiscoveries map[string]*modconfig.ConnectionDiscovery
 }
 
 func NewSteampipeConfig(commandName string) *SteampipeConfig {
@@ -91,6 +95,12 @@ func (c *SteampipeConfig) ConfigMap() map[string]interface{} {
 	if c.PluginOptions != nil {
 		res.PopulateConfigMapForOptions(c.PluginOptions)
 	}
+	if c.NetworkOptions != nil {
+		res.PopulateConfigMapForOptions(c.NetworkOptions)
+	}
+	if c.ImageOptions != nil {
+		res.PopulateConfigMapForOptions(c.ImageOptions)
+	}
 
 	return res
 }
@@ -123,6 +133,18 @@ func (c *SteampipeConfig) SetOptions(opts options.Options) (errorsAndWarnings er
 		} else {
 			c.PluginOptions.Merge(o)
 		}
+	case *options.Network:
+		if c.NetworkOptions == nil {
+			c.NetworkOptions = o
+		} else {
+			c.NetworkOptions.Merge(o)
+		}
+	case *options.Image:
+		if c.ImageOptions == nil {
+			c.ImageOptions = o
+		} else {
+			c.ImageOptions.Merge(o)
+		}
 	}
 	return errorsAndWarnings
 }
@@ -244,6 +266,18 @@ GeneralOptions:
 PluginOptions:
 %s`, c.PluginOptions.String())
 	}
+	if c.NetworkOptions != nil {
+		str += fmt.Sprintf(`
+
+NetworkOptions:
+%s`, c.NetworkOptions.String())
+	}
+	if c.ImageOptions != nil {
+		str += fmt.Sprintf(`
+
+ImageOptions:
+%s`, c.ImageOptions.String())
+	}
 
 	return str
 }
@@ -391,11 +425,14 @@ func (c *SteampipeConfig) resolvePluginInstanceForConnection(connection *modconf
 				connection.DeclRange.Start.Line,
 			)
 		}
-		return p, nil
+		// if the connection selects a plugin version, this may resolve to a side by side install of the plugin
+		return c.resolvePluginVersionInstance(connection, p)
 	}
 
 	// resolve the image ref (this handles the special case of locally developed plugins in the plugins/local folder)
 	imageRef := modconfig.ResolvePluginImageRef(connection.PluginAlias)
+	// if the connection selects a plugin version, this may resolve to a side by side install of the plugin
+	imageRef = resolvePluginVersionImageRef(connection, imageRef, c.PluginVersions)
 
 	// verify the plugin is installed - if not return nil
 	if _, ok := c.PluginVersions[imageRef]; !ok {
@@ -1,6 +1,7 @@
 package versionmap
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/Masterminds/semver/v3"
@@ -37,22 +38,38 @@ func (m DependencyVersionMap) FlatMap() ResolvedVersionMap {
 }
 
 func (m DependencyVersionMap) GetDependencyTree(rootName string) treeprint.Tree {
-	tree := treeprint.NewWithRoot(rootName)
-	m.buildTree(rootName, tree)
+	return m.GetLabelledDependencyTree(rootName, nil)
+}
+
+// GetLabelledDependencyTree returns the dependency tree, appending the label returned by labelFunc (if any) to each mod
+// labelFunc is passed the root name or the dependency path of the mod
+func (m DependencyVersionMap) GetLabelledDependencyTree(rootName string, labelFunc func(string) string) treeprint.Tree {
+	tree := treeprint.NewWithRoot(labelTreeNode(rootName, labelFunc))
+	m.buildTree(rootName, tree, labelFunc)
 	return tree
 }
 
-func (m DependencyVersionMap) buildTree(name string, tree treeprint.Tree) {
+func (m DependencyVersionMap) buildTree(name string, tree treeprint.Tree, labelFunc func(string) string) {
 	deps := m[name]
 	depNames := maps.Keys(deps)
 	sort.Strings(depNames)
 	for _, name := range depNames {
 		version := deps[name]
 		fullName := modconfig.BuildModDependencyPath(name, version.Version)
-		child := tree.AddBranch(fullName)
+		child := tree.AddBranch(labelTreeNode(fullName, labelFunc))
 		// if there are children add them
-		m.buildTree(fullName, child)
+		m.buildTree(fullName, child, labelFunc)
+	}
+}
+
+func labelTreeNode(name string, labelFunc func(string) string) string {
+	if labelFunc == nil {
+		return name
+	}
+	if label := labelFunc(name); label != "" {
+		return fmt.Sprintf("%s %s", name, label)
 	}
+	return name
 }
 
 // GetMissingFromOther returns a map of dependencies which exit in this map but not 'other'
@@ -1,7 +1,9 @@
 package versionmap
 
-func (l *WorkspaceLock) GetModList(rootName string) string {
+// GetModList returns the installed mods as a tree
+// if labelFunc is non-nil, the label it returns is appended to each mod (see DependencyVersionMap.GetLabelledDependencyTree)
+func (l *WorkspaceLock) GetModList(rootName string, labelFunc func(string) string) string {
 
-	tree := l.InstallCache.GetDependencyTree(rootName)
+	tree := l.InstallCache.GetLabelledDependencyTree(rootName, labelFunc)
 	return tree.String()
 }
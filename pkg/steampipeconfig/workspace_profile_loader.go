@@ -23,6 +23,8 @@ type WorkspaceProfileLoader struct {
 	workspaceProfilePath string
 	DefaultProfile       *modconfig.WorkspaceProfile
 	ConfiguredProfile    *modconfig.WorkspaceProfile
+	// warnings for workspace profile config which has been ignored
+	Warnings []string
 }
 
 func ensureDefaultWorkspaceFile(configFolder string) error {
@@ -49,11 +51,12 @@ func NewWorkspaceProfileLoader(ctx context.Context, workspaceProfilePath string)
 			)
 	}
 	loader := &WorkspaceProfileLoader{workspaceProfilePath: workspaceProfilePath}
-	workspaceProfiles, err := loader.load(ctx)
+	workspaceProfiles, warnings, err := loader.load(ctx)
 	if err != nil {
 		return nil, err
 	}
 	loader.workspaceProfiles = workspaceProfiles
+	loader.Warnings = warnings
 
 	defaultProfile, err := loader.get("default")
 	if err != nil {
@@ -93,7 +96,7 @@ func (l *WorkspaceProfileLoader) get(name string) (*modconfig.WorkspaceProfile,
 	return nil, fmt.Errorf("workspace profile %s does not exist", name)
 }
 
-func (l *WorkspaceProfileLoader) load(ctx context.Context) (map[string]*modconfig.WorkspaceProfile, error) {
+func (l *WorkspaceProfileLoader) load(ctx context.Context) (map[string]*modconfig.WorkspaceProfile, []string, error) {
 	// get all the config files in the directory
 	return parse.LoadWorkspaceProfiles(ctx, l.workspaceProfilePath)
 }
@@ -9,6 +9,10 @@ type HookFn func(context.Context)
 type taskRunConfig struct {
 	preHooks       []HookFn
 	runUpdateCheck bool
+	// if set, this usage report is exported locally
+	usageReport *UsageReport
+	// the standard request payload fields which are not sent by the update checks
+	telemetryRedact []string
 }
 
 func newRunConfig() *taskRunConfig {
@@ -23,6 +27,20 @@ func WithUpdateCheck(run bool) TaskRunOption {
 	}
 }
 
+// WithUsageReportExport exports the given usage report to the local usage report file when the tasks run
+func WithUsageReportExport(report *UsageReport) TaskRunOption {
+	return func(o *taskRunConfig) {
+		o.usageReport = report
+	}
+}
+
+// WithTelemetryRedact sets the standard request payload fields which are not sent by the update checks
+func WithTelemetryRedact(fields []string) TaskRunOption {
+	return func(o *taskRunConfig) {
+		o.telemetryRedact = fields
+	}
+}
+
 func WithPreHook(f HookFn) TaskRunOption {
 	return func(o *taskRunConfig) {
 		o.preHooks = append(o.preHooks, f)
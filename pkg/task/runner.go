@@ -10,9 +10,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/turbot/go-kit/files"
-	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/gc"
 	"github.com/turbot/steampipe/pkg/installationstate"
 	"github.com/turbot/steampipe/pkg/plugin"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -97,17 +97,27 @@ func (r *Runner) run(ctx context.Context) {
 	if r.options.runUpdateCheck {
 		// check whether an updated version is available
 		r.runJobAsync(ctx, func(c context.Context) {
-			availableCliVersion, _ = fetchAvailableCLIVersion(ctx, r.currentState.InstallationID)
+			availableCliVersion, _ = fetchAvailableCLIVersion(ctx, r.currentState.InstallationID, r.options.telemetryRedact)
 		}, &waitGroup)
 
 		// check whether an updated version is available
 		r.runJobAsync(ctx, func(c context.Context) {
-			availablePluginVersions = plugin.GetAllUpdateReport(c, r.currentState.InstallationID)
+			availablePluginVersions = plugin.GetAllUpdateReport(c, r.currentState.InstallationID, r.options.telemetryRedact)
 		}, &waitGroup)
 	}
 
-	// remove log files older than 7 days
-	r.runJobAsync(ctx, func(_ context.Context) { db_local.TrimLogs() }, &waitGroup)
+	// record the usage report locally - this is done regardless of whether the update check is enabled
+	if r.options.usageReport != nil {
+		r.runJobAsync(ctx, func(c context.Context) {
+			if err := ExportUsageReport(r.options.usageReport, filepaths.UsageReportFilePath()); err != nil {
+				log.Printf("[WARN] failed to export usage report: %s", err.Error())
+			}
+		}, &waitGroup)
+	}
+
+	// remove old log files and abandoned install temp dirs
+	// (stale state files are only removed by an explicit 'steampipe service gc')
+	r.runJobAsync(ctx, func(c context.Context) { gc.CleanupFiles(c, gc.PolicyFromConfig()) }, &waitGroup)
 
 	// wait for all jobs to complete
 	waitGroup.Wait()
@@ -163,7 +173,9 @@ func showNotificationsForCommand(cmd *cobra.Command, cmdArgs []string) bool {
 		isServiceStopCmd(cmd) ||
 		IsBatchQueryCmd(cmd, cmdArgs) ||
 		isCompletionCmd(cmd) ||
-		isPluginListCmd(cmd))
+		isPluginListCmd(cmd) ||
+		isBenchCmd(cmd) ||
+		isConnectionStateCmd(cmd))
 }
 
 func isServiceStopCmd(cmd *cobra.Command) bool {
@@ -185,6 +197,19 @@ func isPluginListCmd(cmd *cobra.Command) bool {
 	return cmd.Name() == "list" && cmd.Parent() != nil && cmd.Parent().Name() == "plugin"
 }
 
+func IsPluginCheckCompatCmd(cmd *cobra.Command) bool {
+	return cmd.Name() == "check-compat" && cmd.Parent() != nil && cmd.Parent().Name() == "plugin"
+}
+
+// the bench command outputs json, so notifications are not shown
+func isBenchCmd(cmd *cobra.Command) bool {
+	return cmd.Name() == "bench"
+}
+
+func isConnectionStateCmd(cmd *cobra.Command) bool {
+	return cmd.Name() == "state" && cmd.Parent() != nil && cmd.Parent().Name() == "connection"
+}
+
 func IsCheckCmd(cmd *cobra.Command) bool {
 	return cmd.Name() == "check"
 }
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/installationstate"
+	"github.com/turbot/steampipe/pkg/network"
+	"github.com/turbot/steampipe/pkg/plugin"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// UsageReportRequest is a request which is sent to the Steampipe hub when the update check runs
+type UsageReportRequest struct {
+	Url     string                 `json:"url"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// UsageReport describes exactly the usage data which steampipe sends externally
+// it is displayed by 'steampipe telemetry show' and may be recorded locally
+// by 'steampipe telemetry export' or the telemetry_export option
+type UsageReport struct {
+	Timestamp time.Time `json:"timestamp"`
+	// whether the update check (which sends the requests) is enabled
+	UpdateCheck bool `json:"update_check"`
+	Offline     bool `json:"offline"`
+	// the telemetry level passed to the dashboard UI
+	Telemetry string               `json:"telemetry"`
+	Redacted  []string             `json:"redacted"`
+	Requests  []UsageReportRequest `json:"requests"`
+}
+
+// BuildUsageReport builds the usage report for this installation
+// NOTE: this reads viper config, so must not be called concurrently with other viper access
+func BuildUsageReport() (*UsageReport, error) {
+	state, err := installationstate.Load()
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to load installation state")
+	}
+
+	report := &UsageReport{
+		Timestamp:   time.Now(),
+		UpdateCheck: viper.GetBool(constants.ArgUpdateCheck),
+		Offline:     network.IsOffline(),
+		Telemetry:   viper.GetString(constants.ArgTelemetry),
+		Redacted:    utils.RedactedRequestPayloadFields(),
+	}
+	if report.Redacted == nil {
+		report.Redacted = []string{}
+	}
+
+	cliUrl, cliPayload := getCLIVersionCheckRequest(state.InstallationID, report.Redacted)
+	report.Requests = append(report.Requests, UsageReportRequest{Url: cliUrl.String(), Payload: cliPayload})
+	if pluginUrl, pluginPayload, ok := plugin.GetAllUpdateCheckRequest(state.InstallationID, report.Redacted); ok {
+		report.Requests = append(report.Requests, UsageReportRequest{Url: pluginUrl.String(), Payload: pluginPayload})
+	}
+	return report, nil
+}
+
+// ExportUsageReport appends the usage report to the given file, as a single line of JSON
+func ExportUsageReport(report *UsageReport, path string) error {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to open usage report file '%s'", path)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(jsonBytes, '\n'))
+	return err
+}
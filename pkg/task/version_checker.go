@@ -28,12 +28,14 @@ type CLIVersionCheckResponse struct {
 type versionChecker struct {
 	checkResult *CLIVersionCheckResponse // a channel to store the HTTP response
 	signature   string                   // flags whether update check should be done
+	redacted    []string                 // the standard request payload fields which are not sent
 }
 
 // get the latest available version of the CLI
-func fetchAvailableCLIVersion(ctx context.Context, installationId string) (*CLIVersionCheckResponse, error) {
+func fetchAvailableCLIVersion(ctx context.Context, installationId string, redacted []string) (*CLIVersionCheckResponse, error) {
 	v := new(versionChecker)
 	v.signature = installationId
+	v.redacted = redacted
 	err := v.doCheckRequest(ctx)
 	if err != nil {
 		return nil, err
@@ -41,9 +43,15 @@ func fetchAvailableCLIVersion(ctx context.Context, installationId string) (*CLIV
 	return v.checkResult, nil
 }
 
+// getCLIVersionCheckRequest returns the url and payload of the request sent to check for a newer CLI version
+func getCLIVersionCheckRequest(installationId string, redacted []string) (url.URL, map[string]interface{}) {
+	c := &versionChecker{signature: installationId, redacted: redacted}
+	return c.versionCheckURL(), utils.BuildRequestPayloadMap(c.signature, map[string]interface{}{}, c.redacted)
+}
+
 // contact the Turbot Artifacts Server and retrieve the latest released version
 func (c *versionChecker) doCheckRequest(ctx context.Context) error {
-	payload := utils.BuildRequestPayload(c.signature, map[string]interface{}{})
+	payload := utils.BuildRequestPayload(c.signature, map[string]interface{}{}, c.redacted)
 	sendRequestTo := c.versionCheckURL()
 	timeout := 5 * time.Second
 
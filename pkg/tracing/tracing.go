@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/telemetry"
+	"github.com/turbot/steampipe/pkg/constants"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Enabled returns whether spans should be exported
+// this is the case if the telemetry level is 'info' and an OTLP endpoint is configured
+// (using the standard OTEL_EXPORTER_OTLP_ENDPOINT env var)
+func Enabled() bool {
+	if viper.GetString(constants.ArgTelemetry) != constants.TelemetryInfo {
+		return false
+	}
+	_, endpointSet := os.LookupEnv(telemetry.EnvOtelEndpoint)
+	return endpointSet
+}
+
+// SetEnv sets the otel level env var to enable tracing, if tracing is enabled and the level is not already set
+// the env var is read by telemetry.Init, and is inherited by any other process started by this process
+// (postgres/plugin-manager/plugins), so they also export their spans
+func SetEnv() error {
+	if !Enabled() {
+		return nil
+	}
+	if _, ok := os.LookupEnv(telemetry.EnvOtelLevel); ok {
+		return nil
+	}
+	return os.Setenv(telemetry.EnvOtelLevel, telemetry.OtelTrace)
+}
+
+// StartSpan starts a span with the given name and attributes, as a child of the span in the context (if any)
+// if tracing has not been initialised the span is a no-op
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(constants.AppName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error (if any) on the span and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StatementAttributes returns the span attributes describing a SQL statement
+// the statement text may contain literal values (credentials, PII) so it is never exported -
+// instead the operation (the first keyword) and a fingerprint of the statement are recorded,
+// which allows executions of the same statement to be correlated
+func StatementAttributes(statement string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "postgresql"),
+	}
+	if fields := strings.Fields(statement); len(fields) > 0 {
+		attrs = append(attrs, attribute.String("db.operation", strings.ToUpper(fields[0])))
+	}
+	hash := sha256.Sum256([]byte(statement))
+	return append(attrs, attribute.String("db.statement.fingerprint", hex.EncodeToString(hash[:8])))
+}
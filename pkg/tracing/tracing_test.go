@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/telemetry"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestEnabled(t *testing.T) {
+	defer viper.Set(constants.ArgTelemetry, nil)
+
+	tests := []struct {
+		name        string
+		telemetry   string
+		endpointSet bool
+		expected    bool
+	}{
+		{name: "info with endpoint", telemetry: constants.TelemetryInfo, endpointSet: true, expected: true},
+		{name: "info without endpoint", telemetry: constants.TelemetryInfo, endpointSet: false, expected: false},
+		{name: "none with endpoint", telemetry: constants.TelemetryNone, endpointSet: true, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set(constants.ArgTelemetry, tt.telemetry)
+			unsetEnv(t, telemetry.EnvOtelEndpoint)
+			if tt.endpointSet {
+				t.Setenv(telemetry.EnvOtelEndpoint, "localhost:4317")
+			}
+			if got := Enabled(); got != tt.expected {
+				t.Errorf("Enabled() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	defer viper.Set(constants.ArgTelemetry, nil)
+	viper.Set(constants.ArgTelemetry, constants.TelemetryInfo)
+	t.Setenv(telemetry.EnvOtelEndpoint, "localhost:4317")
+
+	// the level is set if it is not already set
+	unsetEnv(t, telemetry.EnvOtelLevel)
+	if err := SetEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(telemetry.EnvOtelLevel); got != telemetry.OtelTrace {
+		t.Errorf("expected otel level '%s', got '%s'", telemetry.OtelTrace, got)
+	}
+
+	// an existing level is not overridden
+	t.Setenv(telemetry.EnvOtelLevel, telemetry.OtelNone)
+	if err := SetEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(telemetry.EnvOtelLevel); got != telemetry.OtelNone {
+		t.Errorf("expected otel level '%s', got '%s'", telemetry.OtelNone, got)
+	}
+}
+
+func TestStatementAttributes(t *testing.T) {
+	query := "select * from aws_iam_user where name = 'secret'"
+	attrs := StatementAttributes(query)
+
+	values := map[string]string{}
+	for _, attr := range attrs {
+		values[string(attr.Key)] = attr.Value.AsString()
+		// the statement text must never be exported
+		if strings.Contains(attr.Value.AsString(), "secret") {
+			t.Errorf("attribute '%s' contains the statement text", attr.Key)
+		}
+	}
+	if values["db.operation"] != "SELECT" {
+		t.Errorf("expected operation 'SELECT', got '%s'", values["db.operation"])
+	}
+	fingerprint := values["db.statement.fingerprint"]
+	if fingerprint == "" {
+		t.Fatal("expected a statement fingerprint")
+	}
+	if again := StatementAttributes(query); again[len(again)-1].Value.AsString() != fingerprint {
+		t.Error("expected the fingerprint of the same statement to be stable")
+	}
+	if other := StatementAttributes("select 1"); other[len(other)-1].Value.AsString() == fingerprint {
+		t.Error("expected different statements to have different fingerprints")
+	}
+}
+
+// unsetEnv unsets the env var for the duration of the test
+func unsetEnv(t *testing.T, key string) {
+	// Setenv registers the restore of the original value
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
@@ -0,0 +1,155 @@
+package upgradeadvisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/color"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/plugin"
+	"github.com/turbot/steampipe/pkg/version"
+)
+
+// Advice is the result of running the upgrade advisor after the CLI version has changed
+type Advice struct {
+	// the version which previously ran - empty if this is not known (the previous version predates the advisor)
+	PreviousVersion     string
+	CurrentVersion      string
+	Downgrade           bool
+	Migrations          []string
+	IncompatiblePlugins []plugin.PluginCompatibility
+	DeprecatedOptions   []string
+	Actions             []string
+}
+
+// Empty returns whether the advisor has nothing to report
+func (a *Advice) Empty() bool {
+	return len(a.Migrations) == 0 &&
+		len(a.IncompatiblePlugins) == 0 &&
+		len(a.DeprecatedOptions) == 0 &&
+		len(a.Actions) == 0
+}
+
+// Run checks whether the CLI version has changed since the last run and if so, returns advice about
+// migrations which will be required, installed plugins which are incompatible, deprecated options in use
+// and any recommended actions.
+// If the version has not changed (or this is a new installation), nil is returned.
+func Run(ctx context.Context) (*Advice, error) {
+	currentVersion := version.SteampipeVersion.String()
+
+	versionFile, err := loadCliVersionFile()
+	if err != nil {
+		return nil, err
+	}
+	if versionFile != nil && versionFile.Version == currentVersion {
+		return nil, nil
+	}
+
+	// if there is no version file, and the database is not installed, this is a new installation
+	newInstallation := versionFile == nil && !filehelpers.FileExists(filepaths.DatabaseVersionFilePath())
+
+	// record the current version, so the advisor only runs once per version change
+	if err := (&cliVersionFile{Version: currentVersion}).save(); err != nil {
+		return nil, err
+	}
+	if newInstallation {
+		return nil, nil
+	}
+
+	advice := &Advice{CurrentVersion: currentVersion}
+	if versionFile != nil {
+		advice.PreviousVersion = versionFile.Version
+		if previous, err := semver.NewVersion(versionFile.Version); err == nil {
+			advice.Downgrade = previous.GreaterThan(version.SteampipeVersion)
+		}
+	}
+	log.Printf("[INFO] Steampipe version changed from '%s' to '%s' - running upgrade advisor", advice.PreviousVersion, currentVersion)
+
+	advice.Migrations, advice.Actions = getMigrations()
+
+	advice.IncompatiblePlugins, err = plugin.GetIncompatiblePlugins(ctx)
+	if err != nil {
+		// do not fail - just log
+		log.Printf("[WARN] upgrade advisor failed to check plugin compatibility: %s", err)
+	}
+	advice.Actions = append(advice.Actions, pluginUpdateAction(advice.IncompatiblePlugins)...)
+
+	advice.DeprecatedOptions = getDeprecatedOptions(ctx)
+	if len(advice.DeprecatedOptions) > 0 {
+		advice.Actions = append(advice.Actions, "Update config to remove the use of deprecated options - see https://steampipe.io/docs/reference/config-files/overview")
+	}
+
+	return advice, nil
+}
+
+// getMigrations returns the database migrations which will be performed when the service next starts
+func getMigrations() (migrations []string, actions []string) {
+	dbVersions, err := versionfile.LoadDatabaseVersionFile()
+	if err != nil {
+		log.Printf("[WARN] upgrade advisor failed to load database version file: %s", err)
+		return nil, nil
+	}
+
+	if installed := dbVersions.EmbeddedDB.Version; installed != "" && installed != constants.DatabaseVersion {
+		migrations = append(migrations, fmt.Sprintf("The embedded database will be updated from %s to %s (existing data will be migrated)", installed, constants.DatabaseVersion))
+	}
+	if installed := dbVersions.FdwExtension.Version; installed != "" && installed != constants.FdwVersion {
+		migrations = append(migrations, fmt.Sprintf("The Postgres FDW will be updated from %s to %s", installed, constants.FdwVersion))
+	}
+	if len(migrations) > 0 {
+		actions = append(actions, fmt.Sprintf("Database updates are applied the next time the database starts - if the service is running, run %s", constants.Bold("steampipe service restart")))
+	}
+	return migrations, actions
+}
+
+// Display writes the advice to the given writer
+func (a *Advice) Display(w io.Writer) {
+	if a.Downgrade {
+		fmt.Fprintf(w, "\n%s Steampipe has been downgraded from v%s to v%s.\n", color.YellowString("Upgrade advisor:"), a.PreviousVersion, a.CurrentVersion)
+	} else if a.PreviousVersion != "" {
+		fmt.Fprintf(w, "\n%s Steampipe has been upgraded from v%s to v%s.\n", color.YellowString("Upgrade advisor:"), a.PreviousVersion, a.CurrentVersion)
+	} else {
+		fmt.Fprintf(w, "\n%s Steampipe has been upgraded to v%s.\n", color.YellowString("Upgrade advisor:"), a.CurrentVersion)
+	}
+	displaySection(w, "Required migrations", a.Migrations)
+	var plugins []string
+	for _, p := range a.IncompatiblePlugins {
+		plugins = append(plugins, p.String())
+	}
+	displaySection(w, "Incompatible plugins", plugins)
+	displaySection(w, "Deprecated options in use", a.DeprecatedOptions)
+	displaySection(w, "Recommended actions", a.Actions)
+	fmt.Fprintln(w)
+}
+
+func displaySection(w io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(w, "  - %s\n", item)
+	}
+}
+
+// DisplayAdvice runs the upgrade advisor and displays any advice to stderr, returning whether advice was displayed
+// errors are logged rather than returned, as the advisor must never prevent a command from running
+func DisplayAdvice(ctx context.Context) bool {
+	advice, err := Run(ctx)
+	if err != nil {
+		log.Printf("[WARN] upgrade advisor failed: %s", err)
+		return false
+	}
+	// only display if there is something to report
+	if advice == nil || advice.Empty() {
+		return false
+	}
+	advice.Display(color.Error)
+	return true
+}
@@ -0,0 +1,33 @@
+package upgradeadvisor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetDeprecatedConfig(t *testing.T) {
+	content := `
+options "general" {
+  update_check = true
+}
+
+options "terminal" {
+  timing = true
+}
+
+workspace "default" {
+  cloud_token = "abc"
+  install_dir = "~/.steampipe"
+}
+`
+	res := getDeprecatedConfig("default.spc", []byte(content))
+	if len(res) != 2 {
+		t.Fatalf("expected 2 deprecations, got %d: %v", len(res), res)
+	}
+	if !strings.HasPrefix(res[0], `default.spc:6: options "terminal"`) {
+		t.Errorf("unexpected deprecation: %s", res[0])
+	}
+	if !strings.HasPrefix(res[1], "default.spc:11: workspace attribute 'cloud_token'") {
+		t.Errorf("unexpected deprecation: %s", res[1])
+	}
+}
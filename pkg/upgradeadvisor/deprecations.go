@@ -0,0 +1,99 @@
+package upgradeadvisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
+)
+
+// deprecatedOptionsBlocks are the options blocks which are ignored if set in the global config
+var deprecatedOptionsBlocks = map[string]string{
+	options.ConnectionBlock: "set 'cache' and 'cache_max_ttl' in the 'database' options, or use an 'options' block in the connection",
+	options.TerminalBlock:   "set terminal options in a workspace profile",
+}
+
+// deprecatedWorkspaceAttributes are the deprecated workspace profile attributes, and their replacements
+var deprecatedWorkspaceAttributes = map[string]string{
+	"cloud_host":  "pipes_host",
+	"cloud_token": "pipes_token",
+}
+
+// deprecatedEnvVars are the deprecated environment variables, and their replacements
+var deprecatedEnvVars = map[string]string{
+	constants.EnvCloudHost:  constants.EnvPipesHost,
+	constants.EnvCloudToken: constants.EnvPipesToken,
+}
+
+// getDeprecatedOptions returns descriptions of any deprecated options set in the config files or environment
+func getDeprecatedOptions(ctx context.Context) []string {
+	var res []string
+
+	configFiles, err := filehelpers.ListFilesWithContext(ctx, filepaths.EnsureConfigDir(), &filehelpers.ListOptions{
+		Flags:   filehelpers.FilesFlat,
+		Include: filehelpers.InclusionsFromExtensions([]string{constants.ConfigExtension}),
+	})
+	if err != nil {
+		log.Printf("[WARN] upgrade advisor failed to list config files: %s", err)
+	}
+	for _, path := range configFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("[WARN] upgrade advisor failed to read config file %s: %s", path, err)
+			continue
+		}
+		res = append(res, getDeprecatedConfig(filepath.Base(path), content)...)
+	}
+
+	for envVar, replacement := range deprecatedEnvVars {
+		if _, ok := os.LookupEnv(envVar); ok {
+			res = append(res, fmt.Sprintf("env var %s is deprecated - use %s", envVar, replacement))
+		}
+	}
+
+	sort.Strings(res)
+	return res
+}
+
+// getDeprecatedConfig returns descriptions of any deprecated options blocks or attributes in the config file content
+func getDeprecatedConfig(filename string, content []byte) []string {
+	file, diags := hclsyntax.ParseConfig(content, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		// config errors will be reported when the config is loaded
+		return nil
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var res []string
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case modconfig.BlockTypeOptions:
+			if len(block.Labels) == 0 {
+				continue
+			}
+			if replacement, ok := deprecatedOptionsBlocks[block.Labels[0]]; ok {
+				res = append(res, fmt.Sprintf("%s:%d: options \"%s\" is no longer supported in global config and is ignored - %s", filename, block.DefRange().Start.Line, block.Labels[0], replacement))
+			}
+		case modconfig.BlockTypeWorkspaceProfile:
+			for name, attr := range block.Body.Attributes {
+				if replacement, ok := deprecatedWorkspaceAttributes[name]; ok {
+					res = append(res, fmt.Sprintf("%s:%d: workspace attribute '%s' is deprecated - use '%s'", filename, attr.SrcRange.Start.Line, name, replacement))
+				}
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package upgradeadvisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/plugin"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// pluginUpdateCommand is the command to update all plugins
+const pluginUpdateCommand = "steampipe plugin update --all"
+
+// pluginUpdateAction returns the recommended action for the given incompatible plugins
+func pluginUpdateAction(plugins []plugin.PluginCompatibility) []string {
+	var actions []string
+	var needsUpdate, needsNewerCli bool
+	for _, p := range plugins {
+		if p.TooNew {
+			needsNewerCli = true
+		} else {
+			needsUpdate = true
+		}
+	}
+	if needsUpdate {
+		actions = append(actions, fmt.Sprintf("Update plugins using unsupported sdk versions: %s", constants.Bold(pluginUpdateCommand)))
+	}
+	if needsNewerCli {
+		actions = append(actions, "Upgrade Steampipe to use plugins built with a newer sdk version")
+	}
+	return actions
+}
+
+// DisplayIncompatiblePluginsWarning displays a warning listing any installed plugins which are incompatible
+// with this CLI version, and how to fix each
+func DisplayIncompatiblePluginsWarning(ctx context.Context) {
+	incompatiblePlugins, err := plugin.GetIncompatiblePlugins(ctx)
+	if err != nil {
+		log.Printf("[WARN] failed to check plugin compatibility: %s", err)
+		return
+	}
+	if len(incompatiblePlugins) == 0 {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d installed %s %s incompatible with this version of Steampipe:",
+		len(incompatiblePlugins),
+		utils.Pluralize("plugin", len(incompatiblePlugins)),
+		utils.Pluralize("is", len(incompatiblePlugins)))
+	for _, p := range incompatiblePlugins {
+		fmt.Fprintf(&b, "\n  - %s", p.String())
+	}
+	error_helpers.ShowWarning(b.String())
+}
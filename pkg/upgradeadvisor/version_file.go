@@ -0,0 +1,49 @@
+package upgradeadvisor
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+const cliVersionStructVersion = 20240601
+
+// cliVersionFile records the version of the CLI which last ran, so upgrades (and downgrades) can be detected
+type cliVersionFile struct {
+	Version       string `json:"version"`
+	LastRun       string `json:"last_run"`
+	StructVersion int64  `json:"struct_version"`
+}
+
+// loadCliVersionFile loads the CLI version file - if the file does not exist, nil is returned
+func loadCliVersionFile() (*cliVersionFile, error) {
+	path := filepaths.CliVersionFilePath()
+	if !filehelpers.FileExists(path) {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f cliVersionFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		// treat an invalid file as missing - it will be overwritten
+		log.Printf("[WARN] failed to parse CLI version file %s: %s", path, err)
+		return nil, nil
+	}
+	return &f, nil
+}
+
+func (f *cliVersionFile) save() error {
+	f.StructVersion = cliVersionStructVersion
+	f.LastRun = time.Now().Format(time.RFC3339)
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepaths.CliVersionFilePath(), content, 0644)
+}
@@ -0,0 +1,50 @@
+//go:build darwin || linux
+// +build darwin linux
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// FileLock is an exclusive advisory lock on a file, used to serialise operations across steampipe processes
+// the lock is released when Unlock is called, or when the process exits
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile acquires an exclusive lock on the file at the given path (creating it if necessary),
+// blocking until the lock is available
+func LockFile(path string) (*FileLock, error) {
+	return lockFile(path, syscall.LOCK_EX)
+}
+
+// TryLockFile attempts to acquire an exclusive lock on the file at the given path (creating it if necessary)
+// if the lock is held by another process, it returns a nil lock and no error
+func TryLockFile(path string) (*FileLock, error) {
+	lock, err := lockFile(path, syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return nil, nil
+	}
+	return lock, err
+}
+
+func lockFile(path string, how int) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), how); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FileLock{file: file}, nil
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,31 @@
+//go:build darwin || linux
+// +build darwin linux
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTryLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	lock, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	// the lock is held, so it cannot be acquired again
+	if other, err := TryLockFile(path); err != nil || other != nil {
+		t.Fatalf("expected the lock to be held, got %v, %v", other, err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	other, err := TryLockFile(path)
+	if err != nil || other == nil {
+		t.Fatalf("expected the lock to be acquired, got %v, %v", other, err)
+	}
+	other.Unlock()
+}
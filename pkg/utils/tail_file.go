@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// tailPollInterval is the interval at which the tailed file is polled (a var so tests may use a shorter interval)
+var tailPollInterval = 500 * time.Millisecond
+
+// TailFile writes any content appended to a file to w, until the context is cancelled
+//
+// getPath is re-evaluated on every poll, so log files which are rotated (e.g. daily) are followed
+// - content which exists in the first file when tailing starts is skipped
+// - the file does not need to exist when tailing starts
+func TailFile(ctx context.Context, getPath func() string, w io.Writer) error {
+	var currentPath string
+	var f *os.File
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+	firstFile := true
+
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+	for {
+		path := getPath()
+		if path != currentPath || f == nil {
+			if f != nil {
+				// drain the previous file before switching
+				_, _ = io.Copy(w, f)
+				f.Close()
+				f = nil
+			}
+			if newFile, err := os.Open(path); err == nil {
+				f = newFile
+				currentPath = path
+				if firstFile {
+					if _, err := f.Seek(0, io.SeekEnd); err != nil {
+						return err
+					}
+				}
+				firstFile = false
+			} else if !os.IsNotExist(err) {
+				return err
+			} else {
+				// the file does not exist yet - any file created later should be read from the start
+				firstFile = false
+			}
+		}
+
+		if f != nil {
+			// if the file has been truncated, read from the start
+			if info, err := f.Stat(); err == nil {
+				if pos, err := f.Seek(0, io.SeekCurrent); err == nil && info.Size() < pos {
+					_, _ = f.Seek(0, io.SeekStart)
+				}
+			}
+			if _, err := io.Copy(w, f); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a buffer which may be written by TailFile while the test reads it
+type syncBuffer struct {
+	mut sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.String()
+}
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTailFile(t *testing.T) {
+	prevPollInterval := tailPollInterval
+	tailPollInterval = 10 * time.Millisecond
+	defer func() { tailPollInterval = prevPollInterval }()
+
+	tests := map[string]struct {
+		// the content of the file when tailing starts - if not set, the file does not exist
+		initial *string
+		// each step is run once the output of the previous step has been tailed
+		steps []func(t *testing.T, dir string, setPath func(string))
+		// the expected output after each step
+		expected []string
+	}{
+		"existing content is skipped": {
+			initial: ptr("before\n"),
+			steps: []func(t *testing.T, dir string, setPath func(string)){
+				func(t *testing.T, dir string, _ func(string)) {
+					appendToFile(t, filepath.Join(dir, "1.log"), "after\n")
+				},
+			},
+			expected: []string{"after\n"},
+		},
+		"file created after tailing starts is read from the start": {
+			steps: []func(t *testing.T, dir string, setPath func(string)){
+				func(t *testing.T, dir string, _ func(string)) {
+					appendToFile(t, filepath.Join(dir, "1.log"), "first\n")
+				},
+				func(t *testing.T, dir string, _ func(string)) {
+					appendToFile(t, filepath.Join(dir, "1.log"), "second\n")
+				},
+			},
+			expected: []string{"first\n", "first\nsecond\n"},
+		},
+		"rotated file is followed": {
+			initial: ptr(""),
+			steps: []func(t *testing.T, dir string, setPath func(string)){
+				func(t *testing.T, dir string, _ func(string)) { appendToFile(t, filepath.Join(dir, "1.log"), "old\n") },
+				func(t *testing.T, dir string, setPath func(string)) {
+					appendToFile(t, filepath.Join(dir, "2.log"), "new\n")
+					setPath(filepath.Join(dir, "2.log"))
+				},
+			},
+			expected: []string{"old\n", "old\nnew\n"},
+		},
+		"truncated file is read from the start": {
+			initial: ptr("before\n"),
+			steps: []func(t *testing.T, dir string, setPath func(string)){
+				func(t *testing.T, dir string, _ func(string)) {
+					appendToFile(t, filepath.Join(dir, "1.log"), "after\n")
+				},
+				func(t *testing.T, dir string, _ func(string)) {
+					if err := os.WriteFile(filepath.Join(dir, "1.log"), []byte("new\n"), 0644); err != nil {
+						t.Fatal(err)
+					}
+				},
+			},
+			expected: []string{"after\n", "after\nnew\n"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if test.initial != nil {
+				if err := os.WriteFile(filepath.Join(dir, "1.log"), []byte(*test.initial), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var pathMut sync.Mutex
+			path := filepath.Join(dir, "1.log")
+			getPath := func() string {
+				pathMut.Lock()
+				defer pathMut.Unlock()
+				return path
+			}
+			setPath := func(p string) {
+				pathMut.Lock()
+				defer pathMut.Unlock()
+				path = p
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			output := &syncBuffer{}
+			done := make(chan error)
+			go func() { done <- TailFile(ctx, getPath, output) }()
+			// let the first poll open the file, so the initial content is skipped
+			time.Sleep(5 * tailPollInterval)
+
+			for i, step := range test.steps {
+				step(t, dir, setPath)
+				deadline := time.Now().Add(5 * time.Second)
+				for output.String() != test.expected[i] && time.Now().Before(deadline) {
+					time.Sleep(tailPollInterval)
+				}
+				if got := output.String(); got != test.expected[i] {
+					t.Fatalf("step %d: expected output %q, got %q", i, test.expected[i], got)
+				}
+			}
+
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
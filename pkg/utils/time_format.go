@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// TimestampFormatLookup is the map of named timestamp formats to their Go time layout
+var TimestampFormatLookup = map[string]string{
+	"rfc3339":   time.RFC3339,
+	"rfc3339ms": "2006-01-02T15:04:05.000Z07:00",
+	"rfc1123":   time.RFC1123,
+	"datetime":  time.DateTime,
+	"kitchen":   time.Kitchen,
+}
+
+// a time which differs from the Go reference time in every layout element
+var timestampLayoutTestTime = time.Date(2023, 11, 28, 9, 37, 48, 123456789, time.FixedZone("TST", 3600))
+
+// TimestampLayout returns the Go time layout for the given timestamp format,
+// which may be either a named format (e.g. 'rfc3339') or a Go time layout
+// an error is returned if the format is neither, i.e. it does not contain any layout elements
+func TimestampLayout(format string) (string, error) {
+	if layout, ok := TimestampFormatLookup[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+	// a layout with no elements formats every time as the layout itself
+	if format == "" || timestampLayoutTestTime.Format(format) == format {
+		return "", fmt.Errorf("invalid timestamp format '%s' - must be one of %s or a Go time layout, e.g. '2006-01-02 15:04'", format, strings.Join(SortedMapKeys(TimestampFormatLookup), ", "))
+	}
+	return format, nil
+}
+
+// ParseTimezone returns the location for the given timezone
+// this may be 'local', 'utc' or an IANA timezone name, e.g. 'Europe/London'
+func ParseTimezone(timezone string) (*time.Location, error) {
+	switch strings.ToLower(timezone) {
+	case "":
+		return nil, nil
+	case constants.TimezoneLocal:
+		return time.Local, nil
+	case constants.TimezoneUTC:
+		return time.UTC, nil
+	}
+	return time.LoadLocation(timezone)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampLayout(t *testing.T) {
+	tests := map[string]struct {
+		format   string
+		expected string
+		err      bool
+	}{
+		"named format":                       {format: "rfc3339", expected: time.RFC3339},
+		"named format is not case sensitive": {format: "DateTime", expected: time.DateTime},
+		"go layout":                          {format: "2006-01-02 15:04", expected: "2006-01-02 15:04"},
+		"single element":                     {format: "Jan", expected: "Jan"},
+		"time only":                          {format: "3:04PM", expected: "3:04PM"},
+		"no layout elements":                 {format: "yyyy-mm-dd", err: true},
+		"unknown name":                       {format: "isoformat", err: true},
+		"empty":                              {format: "", err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			layout, err := TimestampLayout(test.format)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error for format '%s', got layout '%s'", test.format, layout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if layout != test.expected {
+				t.Errorf("expected layout '%s', got '%s'", test.expected, layout)
+			}
+		})
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	tests := map[string]struct {
+		timezone string
+		expected string
+		err      bool
+	}{
+		"not set":      {timezone: ""},
+		"local":        {timezone: "LOCAL", expected: time.Local.String()},
+		"utc":          {timezone: "utc", expected: "UTC"},
+		"iana name":    {timezone: "Europe/London", expected: "Europe/London"},
+		"unknown name": {timezone: "Mars/Olympus_Mons", err: true},
+		"invalid name": {timezone: "not a timezone", err: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			location, err := ParseTimezone(test.timezone)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error for timezone '%s'", test.timezone)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if test.expected == "" {
+				if location != nil {
+					t.Errorf("expected no location, got %s", location)
+				}
+				return
+			}
+			if location.String() != test.expected {
+				t.Errorf("expected location '%s', got '%s'", test.expected, location)
+			}
+		})
+	}
+}
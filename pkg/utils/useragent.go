@@ -9,8 +9,11 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
+	"strings"
 
-	"github.com/hashicorp/go-cleanhttp"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/network"
 	"github.com/turbot/steampipe/pkg/version"
 )
 
@@ -18,8 +21,21 @@ func getUserAgent() string {
 	return fmt.Sprintf("Turbot Steampipe/%s (+https://steampipe.io)", version.SteampipeVersion.String())
 }
 
+// RedactableRequestPayloadFields are the fields of the standard request payload
+// which may be removed using the telemetry_redact option
+var RedactableRequestPayloadFields = []string{"signature", "os_platform", "arch"}
+
 // BuildRequestPayload merges the provided payload with the standard payload that needs to be sent
-func BuildRequestPayload(signature string, payload map[string]interface{}) *bytes.Buffer {
+func BuildRequestPayload(signature string, payload map[string]interface{}, redacted []string) *bytes.Buffer {
+	jsonStr, _ := json.Marshal(BuildRequestPayloadMap(signature, payload, redacted))
+	return bytes.NewBuffer(jsonStr)
+}
+
+// BuildRequestPayloadMap merges the provided payload with the standard payload that needs to be sent
+// and removes the redacted standard fields (see RedactedRequestPayloadFields)
+// NOTE: this may be called from the task runner goroutines, so the redacted fields are passed in
+// rather than read from viper
+func BuildRequestPayloadMap(signature string, payload map[string]interface{}, redacted []string) map[string]interface{} {
 	requestPayload := map[string]interface{}{
 		"version":     version.SteampipeVersion.String(),
 		"os_platform": runtime.GOOS,
@@ -43,8 +59,22 @@ func BuildRequestPayload(signature string, payload map[string]interface{}) *byte
 		requestPayload[k] = v
 	}
 
-	jsonStr, _ := json.Marshal(requestPayload)
-	return bytes.NewBuffer(jsonStr)
+	for _, field := range redacted {
+		delete(requestPayload, field)
+	}
+	return requestPayload
+}
+
+// RedactedRequestPayloadFields returns the fields set in the telemetry_redact option
+// NOTE: this reads viper config, so must not be called concurrently with other viper access
+func RedactedRequestPayloadFields() []string {
+	var res []string
+	for _, field := range strings.Split(viper.GetString(constants.ArgTelemetryRedact), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			res = append(res, field)
+		}
+	}
+	return res
 }
 
 // SendRequest makes a http call to the given URL
@@ -57,7 +87,7 @@ func SendRequest(ctx context.Context, signature string, method string, sendReque
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", getUserAgent())
 
-	client := cleanhttp.DefaultClient()
+	client := network.HttpClient()
 
 	return client.Do(req)
 }
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestBuildRequestPayloadMapRedaction(t *testing.T) {
+	defer viper.Set(constants.ArgTelemetryRedact, "")
+	viper.Set(constants.ArgTelemetryRedact, " signature, arch ")
+
+	payload := BuildRequestPayloadMap("installation-id", map[string]interface{}{"plugins": []string{"aws"}}, RedactedRequestPayloadFields())
+
+	for _, field := range []string{"signature", "arch"} {
+		if _, ok := payload[field]; ok {
+			t.Errorf("expected field '%s' to be redacted", field)
+		}
+	}
+	for _, field := range []string{"version", "os_platform", "plugins"} {
+		if _, ok := payload[field]; !ok {
+			t.Errorf("expected field '%s' to be present", field)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/go-kit/filewatcher"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/cloud"
 	"github.com/turbot/steampipe/pkg/cmdconfig"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
@@ -44,6 +45,9 @@ type Workspace struct {
 	// the input variables used in the parse
 	VariableValues map[string]string
 	CloudMetadata  *steampipeconfig.CloudMetadata
+	// default variable values loaded from the variables-workspace (if set)
+	// NOTE: these may include secrets
+	cloudVariableValues map[string]any
 
 	// source snapshot paths
 	// if this is set, no other mod resources are loaded and
@@ -404,8 +408,46 @@ func (w *Workspace) getInputVariables(ctx context.Context, validateMissing bool)
 
 	log.Printf("[INFO] loaded variable definitions: %s", variableMap)
 
+	// load any default values from the cloud workspace
+	cloudValues, err := w.getCloudVariableValues(ctx, variableMap)
+	if err != nil {
+		return nil, error_helpers.NewErrorsAndWarning(err)
+	}
+
 	// get the values
-	return steampipeconfig.GetVariableValues(variablesParseCtx, variableMap, validateMissing)
+	return steampipeconfig.GetVariableValues(variablesParseCtx, variableMap, cloudValues, validateMissing)
+}
+
+// getCloudVariableValues loads the variable values set in the Turbot Pipes workspace given by the variables-workspace arg
+// the values are only loaded once, as variables may be resolved multiple times (e.g. after prompting for missing variables)
+func (w *Workspace) getCloudVariableValues(ctx context.Context, variableMap *modconfig.ModVariableMap) (map[string]any, error) {
+	variablesWorkspace := viper.GetString(constants.ArgVariablesWorkspace)
+	if variablesWorkspace == "" {
+		return nil, nil
+	}
+	if w.cloudVariableValues != nil {
+		return w.cloudVariableValues, nil
+	}
+
+	// values for the workspace mod are keyed by variable name,
+	// values for dependency mods are keyed by '<mod>.<variable name>'
+	modAliases := map[string]string{variableMap.Mod.ShortName: ""}
+	for _, depVariableMap := range variableMap.DependencyVariables {
+		depModName := depVariableMap.Mod.ShortName
+		if _, ok := modAliases[depModName]; !ok {
+			modAliases[depModName] = depModName + "."
+		}
+	}
+
+	statushooks.SetStatus(ctx, fmt.Sprintf("Loading variables from %s", variablesWorkspace))
+	values, err := cloud.GetWorkspaceVariableValues(ctx, variablesWorkspace, viper.GetString(constants.ArgPipesToken), modAliases)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] loaded values for %d %s from cloud workspace %s", len(values), utils.Pluralize("variable", len(values)), variablesWorkspace)
+
+	w.cloudVariableValues = values
+	return values, nil
 }
 
 // build options used to load workspace
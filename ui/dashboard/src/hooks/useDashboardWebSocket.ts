@@ -7,6 +7,7 @@ import {
   IActions,
   ReceivedSocketMessagePayload,
 } from "../types";
+import { getBasePath } from "../utils/url";
 import { useCallback, useEffect, useRef } from "react";
 
 export const SocketActions: IActions = {
@@ -42,7 +43,9 @@ const useDashboardWebSocket = (
     }
     // Otherwise, it's a production build, so use the URL details
     const url = new URL(window.location.toString());
-    return `${url.protocol === "https:" ? "wss" : "ws"}://${url.host}/ws`;
+    return `${url.protocol === "https:" ? "wss" : "ws"}://${
+      url.host
+    }${getBasePath()}/ws`;
   }, [socketUrlFactory]);
 
   const { lastJsonMessage, readyState, sendJsonMessage } = useWebSocket(
@@ -5,6 +5,7 @@ import { AnalyticsProvider } from "./hooks/useAnalytics";
 import { BreakpointProvider } from "./hooks/useBreakpoint";
 import { BrowserRouter as Router } from "react-router-dom";
 import { createRoot } from "react-dom/client";
+import { getBasePath } from "./utils/url";
 import { ThemeProvider } from "./hooks/useTheme";
 import "./styles/index.css";
 
@@ -13,7 +14,7 @@ const container = document.getElementById("root");
 const root = createRoot(container);
 
 root.render(
-  <Router>
+  <Router basename={getBasePath()}>
     <ThemeProvider>
       <ErrorBoundary>
         <BreakpointProvider>
@@ -4,4 +4,11 @@ const isRelativeUrl = (url) => {
   );
 };
 
-export { isRelativeUrl };
+// The dashboard server injects the base path it is served under (if any),
+// e.g. when running behind a reverse proxy
+const getBasePath = (): string => {
+  const meta = document.querySelector('meta[name="steampipe-base-path"]');
+  return meta?.getAttribute("content") || "";
+};
+
+export { getBasePath, isRelativeUrl };